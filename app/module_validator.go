@@ -17,14 +17,32 @@ type widgetError struct {
 	validationErrors []cfg.Validatable
 }
 
+type widgetWarning struct {
+	name        string
+	unknownKeys []cfg.UnknownKey
+}
+
 // NewModuleValidator creates and returns an instance of ModuleValidator
 func NewModuleValidator() *ModuleValidator {
 	return &ModuleValidator{}
 }
 
 // Validate rolls through all the enabled widgets and looks for configuration errors.
-// If it finds any it stringifies them, writes them to the console, and kills the app gracefully
+// If it finds any it stringifies them, writes them to the console, and kills the app gracefully.
+// Settings the widgets don't know are written to the console as warnings, and don't stop it
 func (val *ModuleValidator) Validate(widgets []wtf.Wtfable) {
+	validationWarnings := warn(widgets)
+
+	if len(validationWarnings) > 0 {
+		fmt.Println()
+		for _, warning := range validationWarnings {
+			for _, message := range warning.warningMessages() {
+				fmt.Println(message)
+			}
+		}
+		fmt.Println()
+	}
+
 	validationErrors := validate(widgets)
 
 	if len(validationErrors) > 0 {
@@ -58,6 +76,18 @@ func validate(widgets []wtf.Wtfable) (widgetErrors []widgetError) {
 	return widgetErrors
 }
 
+func warn(widgets []wtf.Wtfable) (widgetWarnings []widgetWarning) {
+	for _, widget := range widgets {
+		unknownKeys := widget.CommonSettings().UnknownKeys()
+
+		if len(unknownKeys) > 0 {
+			widgetWarnings = append(widgetWarnings, widgetWarning{name: widget.Name(), unknownKeys: unknownKeys})
+		}
+	}
+
+	return widgetWarnings
+}
+
 func (err widgetError) errorMessages() (messages []string) {
 	widgetMessage := fmt.Sprintf(
 		"%s in %s configuration",
@@ -79,3 +109,18 @@ func (err widgetError) errorMessages() (messages []string) {
 
 	return messages
 }
+
+func (warning widgetWarning) warningMessages() (messages []string) {
+	widgetMessage := fmt.Sprintf(
+		"%s in %s configuration",
+		aurora.Magenta("Warnings"),
+		aurora.Yellow(warning.name),
+	)
+	messages = append(messages, widgetMessage)
+
+	for _, unknownKey := range warning.unknownKeys {
+		messages = append(messages, fmt.Sprintf(" - %s", unknownKey.String()))
+	}
+
+	return messages
+}
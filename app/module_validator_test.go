@@ -100,3 +100,36 @@ func Test_validate(t *testing.T) {
 		})
 	}
 }
+
+func Test_warn(t *testing.T) {
+	config, err := config.ParseYaml(`
+wtf:
+  mods:
+    jira:
+      enabled: true
+      domain: https://jira.example.com
+      jqll: assignee = currentUser()
+      position:
+        top: 0
+        left: 0
+        height: 1
+        width: 1`)
+	assert.NoError(t, err)
+
+	widget := MakeWidget(nil, nil, "jira", config, make(chan bool))
+	assert.NotNil(t, widget)
+
+	warnings := warn([]wtf.Wtfable{widget})
+	assert.Len(t, warnings, 1)
+
+	assert.Equal(
+		t,
+		[]string{
+			fmt.Sprintf("%s in %s configuration", aurora.Magenta("Warnings"), aurora.Yellow("jira")),
+			fmt.Sprintf(" - Unknown setting %s, did you mean %s?", aurora.Yellow("jqll"), aurora.Green("jql")),
+		},
+		warnings[0].warningMessages(),
+	)
+
+	assert.Empty(t, validate([]wtf.Wtfable{widget}))
+}
@@ -281,7 +281,7 @@ func MakeWidget(
 		widget = pihole.NewWidget(tviewApp, redrawChan, pages, settings)
 	case "ping":
 		settings := ping.NewSettingsFromYAML(moduleName, moduleConfig, config)
-		widget = ping.NewWidget(tviewApp, redrawChan, settings)
+		widget = ping.NewWidget(tviewApp, redrawChan, pages, settings)
 	case "power":
 		settings := power.NewSettingsFromYAML(moduleName, moduleConfig, config)
 		widget = power.NewWidget(tviewApp, redrawChan, settings)
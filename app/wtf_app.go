@@ -217,7 +217,11 @@ func (wtfApp *WtfApp) watchForConfigChanges() {
 				config := cfg.LoadWtfConfigFile(wtfApp.configFilePath)
 				newApp := NewWtfApp(wtfApp.TViewApp, config, wtfApp.configFilePath)
 				openURLUtil := utils.ToStrs(config.UList("wtf.openUrlUtil", []interface{}{}))
-				utils.Init(config.UString("wtf.openFileUtil", "open"), openURLUtil)
+				utils.Init(
+					config.UString("wtf.openFileUtil", "open"),
+					openURLUtil,
+					config.UString("wtf.clipboardMode", utils.ClipboardAuto),
+				)
 
 				newApp.Start()
 			case err := <-watch.Error:
@@ -45,14 +45,28 @@ type Common struct {
 	Enabled         bool          `help:"Whether or not this module is executed and if its data displayed onscreen." values:"true, false" optional:"true" default:"false"`
 	Focusable       bool          `help:"Whether or  not this module is focusable." values:"true, false" optional:"true" default:"false"`
 	LanguageTag     string        `help:"The BCP 47 langauge tag to localize text to." values:"Any supported BCP 47 language tag." optional:"true" default:"en-CA"`
-	RefreshInterval time.Duration `help:"How often this module will update its data." values:"A positive integer followed by a time unit (ns, us, ms, s, m, h, or nothing which defaults to s)" optional:"true"`
+	RefreshInterval time.Duration `help:"How often this module will update its data." values:"A number of seconds, or a duration such as 90s, 5m or 1h30m, of at least a second. 0, or a negative value, never refreshes." optional:"true" default:"300s"`
 	Title           string        `help:"The title string to show when displaying this module" optional:"true"`
 
+	ShowRefreshFooter bool `help:"Whether or not to show when the module last refreshed and when it refreshes next, under its content. Only some modules support it." values:"true, false" optional:"true" default:"false"`
+
 	focusChar int `help:"Define one of the number keys as a short cut key to access the widget." optional:"true"`
+
+	// validations are the settings that couldn't be read: the ones that refer to an
+	// environment variable or a file that couldn't be expanded, and an invalid refreshInterval
+	validations *Validations
+
+	// unknownKeys are the settings the module doesn't read, found by CheckKeys
+	unknownKeys []UnknownKey
 }
 
 // NewCommonSettingsFromModule returns a common settings configuration tailed to the given module
 func NewCommonSettingsFromModule(name, defaultTitle string, defaultFocusable bool, moduleConfig *config.Config, globalConfig *config.Config) *Common {
+	// Fill in the defaults of the module's type, then resolve the settings that refer to
+	// secrets kept elsewhere, so that modules read the resolved values of both
+	applyModuleDefaults(name, moduleConfig, globalConfig)
+	validations := expandValues(moduleConfig)
+
 	baseColors := NewDefaultColorTheme()
 
 	colorsConfig, err := globalConfig.Get("wtf.colors")
@@ -103,10 +117,14 @@ func NewCommonSettingsFromModule(name, defaultTitle string, defaultFocusable boo
 		Enabled:         moduleConfig.UBool("enabled", false),
 		Focusable:       moduleConfig.UBool("focusable", defaultFocusable),
 		LanguageTag:     globalConfig.UString("wtf.language", defaultLanguageTag),
-		RefreshInterval: ParseTimeString(moduleConfig, "refreshInterval", "300s"),
+		RefreshInterval: readRefreshInterval(name, moduleConfig, validations),
 		Title:           moduleConfig.UString("title", defaultTitle),
 
+		ShowRefreshFooter: moduleConfig.UBool("showRefreshFooter", false),
+
 		focusChar: moduleConfig.UInt("focusChar", -1),
+
+		validations: validations,
 	}
 
 	sigilsPath := "wtf.sigils"
@@ -218,5 +236,11 @@ func (common *Common) Validations() []Validatable {
 		validatables = append(validatables, validation)
 	}
 
+	if common.validations != nil {
+		for _, validation := range common.validations.validations {
+			validatables = append(validatables, validation)
+		}
+	}
+
 	return validatables
 }
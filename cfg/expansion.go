@@ -0,0 +1,141 @@
+package cfg
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/logrusorgru/aurora/v4"
+	"github.com/olebedev/config"
+)
+
+const (
+	filePrefix = "file:"
+
+	// escapePrefix keeps what follows it as it is: "$${NAME}" is the literal "${NAME}", and
+	// "$file:name" the literal "file:name"
+	escapePrefix = "$"
+)
+
+// envVarPattern matches a reference to an environment variable, "${JIRA_API_KEY}", or an
+// escaped "$${", which is matched first so that what follows it isn't expanded
+var envVarPattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expansionValidation records why a setting that refers to a secret couldn't be expanded
+type expansionValidation struct {
+	err error
+	key string
+}
+
+func (expVal *expansionValidation) Error() error {
+	return expVal.err
+}
+
+func (expVal *expansionValidation) HasError() bool {
+	return expVal.err != nil
+}
+
+func (expVal *expansionValidation) IntValue() int {
+	return 0
+}
+
+// String returns the Stringer representation of the expansionValidation
+func (expVal *expansionValidation) String() string {
+	return fmt.Sprintf("Could not expand the value of %s:", aurora.Yellow(expVal.key))
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// expandValues replaces, in place, the string settings of a module that refer to a value
+// kept outside of the config file, so that modules read the value itself: "${NAME}" is
+// replaced by the NAME environment variable, and "file:/path" by the contents of the file,
+// without surrounding whitespace. Settings that can't be expanded are left as they are, and
+// returned as validations, so that the app reports them and exits. A "$" escapes either:
+// "$${NAME}" is the literal "${NAME}", and "$file:name" the literal "file:name"
+//
+// Example:
+//
+//	apiKey: ${JIRA_API_KEY}
+//	clientSecret: file:~/.secrets/azure
+//	args: ["-c", "echo $${HOME}"]
+func expandValues(moduleConfig *config.Config) *Validations {
+	validations := NewValidations()
+	if moduleConfig == nil {
+		return validations
+	}
+
+	moduleConfig.Root = expandValue(moduleConfig.Root, "", validations)
+
+	return validations
+}
+
+// expandValue expands a value and, for maps and lists, the values in it. key is the path
+// of the value, for the error messages
+func expandValue(value interface{}, key string, validations *Validations) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for name, child := range typed {
+			typed[name] = expandValue(child, joinKey(key, name), validations)
+		}
+	case []interface{}:
+		for idx, child := range typed {
+			typed[idx] = expandValue(child, joinKey(key, strconv.Itoa(idx)), validations)
+		}
+	case string:
+		expanded, err := expandString(typed)
+		if err != nil {
+			validations.append(key, &expansionValidation{err: err, key: key})
+			return typed
+		}
+		return expanded
+	}
+
+	return value
+}
+
+// expandString expands a single string setting
+func expandString(value string) (string, error) {
+	if escaped, ok := strings.CutPrefix(value, escapePrefix+filePrefix); ok {
+		value = filePrefix + escaped
+	} else if path, ok := strings.CutPrefix(value, filePrefix); ok {
+		path, err := expandHomeDir(strings.TrimSpace(path))
+		if err != nil {
+			return "", err
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimSpace(string(contents)), nil
+	}
+
+	var err error
+	expanded := envVarPattern.ReplaceAllStringFunc(value, func(match string) string {
+		if match == escapePrefix+"${" {
+			return "${"
+		}
+
+		name := envVarPattern.FindStringSubmatch(match)[1]
+
+		envValue, ok := os.LookupEnv(name)
+		if !ok && err == nil {
+			err = fmt.Errorf("environment variable %s is not set", name)
+		}
+
+		return envValue
+	})
+
+	return expanded, err
+}
+
+func joinKey(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
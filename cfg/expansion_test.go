@@ -0,0 +1,83 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/olebedev/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_expandValues(t *testing.T) {
+	t.Setenv("WTF_TEST_TOKEN", "s3cret")
+	t.Setenv("WTF_TEST_EMPTY", "")
+
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	assert.NoError(t, os.WriteFile(secretFile, []byte("  from-file\n"), 0o600))
+
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		err      string
+	}{
+		{name: "literal", value: "plain text", expected: "plain text"},
+		{name: "env", value: "${WTF_TEST_TOKEN}", expected: "s3cret"},
+		{name: "env in text", value: "Bearer ${WTF_TEST_TOKEN}", expected: "Bearer s3cret"},
+		{name: "empty env", value: "${WTF_TEST_EMPTY}", expected: ""},
+		{name: "file", value: "file:" + secretFile, expected: "from-file"},
+		{name: "escaped env", value: "echo $${WTF_TEST_TOKEN} is ${WTF_TEST_TOKEN}", expected: "echo ${WTF_TEST_TOKEN} is s3cret"},
+		{name: "escaped missing env", value: "$${WTF_TEST_MISSING}", expected: "${WTF_TEST_MISSING}"},
+		{name: "escaped file", value: "$file:" + secretFile, expected: "file:" + secretFile},
+		{name: "escaped file with env", value: "$file:${WTF_TEST_TOKEN}", expected: "file:s3cret"},
+		{name: "dollar", value: "costs $5 or $$", expected: "costs $5 or $$"},
+		{name: "missing env", value: "${WTF_TEST_MISSING}", expected: "${WTF_TEST_MISSING}", err: "environment variable WTF_TEST_MISSING is not set"},
+		{name: "missing file", value: "file:/nonexistent/secret", expected: "file:/nonexistent/secret", err: "open /nonexistent/secret: no such file or directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moduleConfig := &config.Config{Root: map[string]interface{}{
+				"apiKey": tt.value,
+				"nested": map[string]interface{}{"list": []interface{}{1, tt.value}},
+			}}
+
+			validations := expandValues(moduleConfig)
+
+			assert.Equal(t, tt.expected, moduleConfig.UString("apiKey"))
+			assert.Equal(t, tt.expected, moduleConfig.UString("nested.list.1"))
+			assert.Equal(t, 1, moduleConfig.UInt("nested.list.0"))
+
+			if tt.err == "" {
+				assert.Empty(t, validations.validations)
+				return
+			}
+
+			assert.Len(t, validations.validations, 2)
+			assert.EqualError(t, validations.validations["apiKey"].Error(), tt.err)
+			assert.Contains(t, validations.validations["nested.list.1"].String(), "nested.list.1")
+		})
+	}
+}
+
+func Test_NewCommonSettingsFromModule_Expansion(t *testing.T) {
+	t.Setenv("WTF_TEST_TITLE", "Expanded")
+
+	moduleConfig, _ := config.ParseYaml("title: ${WTF_TEST_TITLE}\napiKey: ${WTF_TEST_MISSING}")
+	common := NewCommonSettingsFromModule("test", "Test Config", true, moduleConfig, globalSettings)
+
+	assert.Equal(t, "Expanded", common.Title)
+
+	// The position isn't configured either, so only look at the expansions
+	expansions := []Validatable{}
+	for _, validation := range common.Validations() {
+		if _, ok := validation.(*expansionValidation); ok {
+			expansions = append(expansions, validation)
+		}
+	}
+
+	assert.Len(t, expansions, 1)
+	assert.Contains(t, expansions[0].String(), "apiKey")
+	assert.EqualError(t, expansions[0].Error(), "environment variable WTF_TEST_MISSING is not set")
+}
@@ -0,0 +1,80 @@
+package cfg
+
+import (
+	"github.com/olebedev/config"
+)
+
+// moduleDefaultsPath is where the global config keeps the settings shared by every module of
+// a type, by type
+const moduleDefaultsPath = "wtf.moduleDefaults"
+
+/* -------------------- Unexported Functions -------------------- */
+
+// applyModuleDefaults merges, in place, the defaults the global config has for the module's
+// type into its settings, so that modules read them as their own. The module's own settings
+// win: maps are merged key by key, and any other value, lists included, replaces the
+// default. The type and whether the module is enabled are read before, from the module only
+//
+// Example:
+//
+//	wtf:
+//	  moduleDefaults:
+//	    ping:
+//	      count: 3
+//	      colors:
+//	        up: green
+func applyModuleDefaults(name string, moduleConfig *config.Config, globalConfig *config.Config) {
+	if moduleConfig == nil || globalConfig == nil {
+		return
+	}
+
+	defaults, err := globalConfig.Map(moduleDefaultsPath + "." + moduleConfig.UString("type", name))
+	if err != nil {
+		return
+	}
+
+	values, ok := moduleConfig.Root.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	mergeDefaults(values, defaults)
+}
+
+// mergeDefaults adds the defaults values doesn't have, merging the maps both have
+func mergeDefaults(values, defaults map[string]interface{}) {
+	for key, def := range defaults {
+		value, ok := values[key]
+		if !ok {
+			values[key] = copyValue(def)
+			continue
+		}
+
+		valueMap, isMap := value.(map[string]interface{})
+		defMap, defIsMap := def.(map[string]interface{})
+		if isMap && defIsMap {
+			mergeDefaults(valueMap, defMap)
+		}
+	}
+}
+
+// copyValue copies the maps and lists in a default, so that the modules that share it don't
+// share them, as settings are modified in place
+func copyValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(typed))
+		for key, child := range typed {
+			copied[key] = copyValue(child)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(typed))
+		for idx, child := range typed {
+			copied[idx] = copyValue(child)
+		}
+		return copied
+	default:
+		return value
+	}
+}
@@ -0,0 +1,112 @@
+package cfg
+
+import (
+	"testing"
+
+	"github.com/olebedev/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const moduleDefaultsYaml = `
+wtf:
+  moduleDefaults:
+    ping:
+      count: 3
+      timeout: 2s
+      colors:
+        up: green
+        down: red
+      hosts:
+        - hostname: router
+`
+
+func Test_applyModuleDefaults(t *testing.T) {
+	globalConfig, err := config.ParseYaml(moduleDefaultsYaml)
+	require.NoError(t, err)
+
+	moduleConfig, err := config.ParseYaml(`
+type: ping
+count: 5
+colors:
+  down: orange
+hosts:
+  - hostname: example.com
+  - hostname: example.org
+`)
+	require.NoError(t, err)
+
+	applyModuleDefaults("office", moduleConfig, globalConfig)
+
+	// The module's own values win
+	assert.Equal(t, 5, moduleConfig.UInt("count"))
+	assert.Equal(t, "2s", moduleConfig.UString("timeout"))
+
+	// Maps are merged
+	assert.Equal(t, "green", moduleConfig.UString("colors.up"))
+	assert.Equal(t, "orange", moduleConfig.UString("colors.down"))
+
+	// Lists are replaced
+	assert.Len(t, moduleConfig.UList("hosts"), 2)
+	assert.Equal(t, "example.com", moduleConfig.UString("hosts.0.hostname"))
+}
+
+func Test_applyModuleDefaults_ByName(t *testing.T) {
+	globalConfig, err := config.ParseYaml(moduleDefaultsYaml)
+	require.NoError(t, err)
+
+	// Without a type, the module's name is its type
+	moduleConfig, err := config.ParseYaml("enabled: true")
+	require.NoError(t, err)
+	applyModuleDefaults("ping", moduleConfig, globalConfig)
+	assert.Equal(t, 3, moduleConfig.UInt("count"))
+	assert.Equal(t, "router", moduleConfig.UString("hosts.0.hostname"))
+
+	// Other types get nothing
+	moduleConfig, err = config.ParseYaml("type: jira")
+	require.NoError(t, err)
+	applyModuleDefaults("ping", moduleConfig, globalConfig)
+	assert.Equal(t, map[string]interface{}{"type": "jira"}, moduleConfig.Root)
+}
+
+func Test_applyModuleDefaults_Copies(t *testing.T) {
+	globalConfig, err := config.ParseYaml(moduleDefaultsYaml)
+	require.NoError(t, err)
+
+	first := &config.Config{Root: map[string]interface{}{"type": "ping"}}
+	second := &config.Config{Root: map[string]interface{}{"type": "ping"}}
+	applyModuleDefaults("first", first, globalConfig)
+	applyModuleDefaults("second", second, globalConfig)
+
+	// Changing the settings of one module doesn't change the others, nor the defaults
+	require.NoError(t, first.Set("colors.up", "blue"))
+	require.NoError(t, first.Set("hosts.0.hostname", "gateway"))
+
+	assert.Equal(t, "green", second.UString("colors.up"))
+	assert.Equal(t, "router", second.UString("hosts.0.hostname"))
+	assert.Equal(t, "green", globalConfig.UString("wtf.moduleDefaults.ping.colors.up"))
+}
+
+func Test_NewCommonSettingsFromModule_ModuleDefaults(t *testing.T) {
+	t.Setenv("WTF_TEST_TITLE", "From defaults")
+
+	globalConfig, err := config.ParseYaml(`
+wtf:
+  moduleDefaults:
+    clocks:
+      title: ${WTF_TEST_TITLE}
+      border: false
+      refreshInterval: 1m
+`)
+	require.NoError(t, err)
+
+	moduleConfig, err := config.ParseYaml("border: true")
+	require.NoError(t, err)
+
+	common := NewCommonSettingsFromModule("clocks", "Clocks", false, moduleConfig, globalConfig)
+
+	// Defaults are expanded like the module's own values
+	assert.Equal(t, "From defaults", common.Title)
+	assert.True(t, common.Bordered)
+	assert.Equal(t, 60, int(common.RefreshInterval.Seconds()))
+}
@@ -0,0 +1,127 @@
+package cfg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/logrusorgru/aurora/v4"
+	"github.com/olebedev/config"
+)
+
+const (
+	defaultRefreshInterval = "300s"
+	refreshIntervalKey     = "refreshInterval"
+
+	// minRefreshInterval is the shortest refresh interval a module can have, other than the
+	// ones that never refresh
+	minRefreshInterval = time.Second
+)
+
+// refreshIntervalValidation records why a module's refresh interval is invalid
+type refreshIntervalValidation struct {
+	err   error
+	value string
+}
+
+func (refVal *refreshIntervalValidation) Error() error {
+	return refVal.err
+}
+
+func (refVal *refreshIntervalValidation) HasError() bool {
+	return refVal.err != nil
+}
+
+func (refVal *refreshIntervalValidation) IntValue() int {
+	return 0
+}
+
+// String returns the Stringer representation of the refreshIntervalValidation
+func (refVal *refreshIntervalValidation) String() string {
+	return fmt.Sprintf("Invalid value for %s:\t%s", aurora.Yellow(refreshIntervalKey), refVal.value)
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+// ParseRefreshInterval reads how often a module refreshes from its refreshInterval setting:
+// either a number of seconds, or a duration such as "90s", "5m" or "1h30m". 0 means the
+// module never refreshes on its own, and so do negative intervals, as they always have.
+// Intervals under a second and values that aren't durations are rejected with an error that
+// names the module, and defaultValue is returned in their place
+//
+// Example:
+//
+//	refreshInterval: 5m
+func ParseRefreshInterval(name string, moduleConfig *config.Config, defaultValue string) (time.Duration, error) {
+	defaultInterval, err := time.ParseDuration(defaultValue)
+	if err != nil {
+		return 0, err
+	}
+
+	if moduleConfig == nil {
+		return defaultInterval, nil
+	}
+
+	if _, err := moduleConfig.Get(refreshIntervalKey); err != nil {
+		return defaultInterval, nil
+	}
+
+	interval, err := parseRefreshInterval(moduleConfig)
+	if err != nil {
+		return defaultInterval, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return interval, nil
+}
+
+// SetDefaultRefreshInterval replaces the refresh interval of modules that refresh more or
+// less often than most, unless their refreshInterval setting is set
+func (common *Common) SetDefaultRefreshInterval(interval time.Duration) {
+	if common.Config != nil {
+		if _, err := common.Config.Get(refreshIntervalKey); err == nil {
+			return
+		}
+	}
+
+	common.RefreshInterval = interval
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// parseRefreshInterval parses the refreshInterval setting of a module that sets it
+func parseRefreshInterval(moduleConfig *config.Config) (time.Duration, error) {
+	var interval time.Duration
+
+	if seconds, err := moduleConfig.Int(refreshIntervalKey); err == nil {
+		interval = time.Duration(seconds) * time.Second
+	} else {
+		str := moduleConfig.UString(refreshIntervalKey)
+
+		interval, err = time.ParseDuration(str)
+		if err != nil {
+			return 0, fmt.Errorf("refreshInterval must be a number of seconds or a duration such as 5m, got %q", str)
+		}
+	}
+
+	switch {
+	case interval <= 0:
+		return 0, nil
+	case interval < minRefreshInterval:
+		return 0, fmt.Errorf("refreshInterval must be at least %v, got %v", minRefreshInterval, interval)
+	}
+
+	return interval, nil
+}
+
+// readRefreshInterval returns the refresh interval of a module, and appends a validation
+// to validations when it's invalid
+func readRefreshInterval(name string, moduleConfig *config.Config, validations *Validations) time.Duration {
+	interval, err := ParseRefreshInterval(name, moduleConfig, defaultRefreshInterval)
+
+	// A value that couldn't be expanded is already reported as such
+	if _, unexpanded := validations.validations[refreshIntervalKey]; err != nil && !unexpanded {
+		value := moduleConfig.UString(refreshIntervalKey)
+		validations.append(refreshIntervalKey, &refreshIntervalValidation{err: err, value: value})
+	}
+
+	return interval
+}
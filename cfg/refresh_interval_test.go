@@ -0,0 +1,187 @@
+package cfg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/olebedev/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseRefreshInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		yaml        string
+		expected    time.Duration
+		expectedErr string
+	}{
+		{name: "not set", yaml: "", expected: 5 * time.Minute},
+		{name: "seconds", yaml: "refreshInterval: 15", expected: 15 * time.Second},
+		{name: "seconds as a string", yaml: `refreshInterval: "15"`, expected: 15 * time.Second},
+		{name: "zero", yaml: "refreshInterval: 0", expected: 0},
+		{name: "one second", yaml: "refreshInterval: 1s", expected: time.Second},
+		{name: "duration in seconds", yaml: "refreshInterval: 90s", expected: 90 * time.Second},
+		{name: "duration in minutes", yaml: "refreshInterval: 5m", expected: 5 * time.Minute},
+		{name: "mixed duration", yaml: "refreshInterval: 1h30m", expected: 90 * time.Minute},
+		{name: "fractional duration", yaml: "refreshInterval: 1.5s", expected: 1500 * time.Millisecond},
+		{name: "negative seconds never refreshes", yaml: "refreshInterval: -1", expected: 0},
+		{name: "negative duration never refreshes", yaml: "refreshInterval: -5m", expected: 0},
+		{name: "negative sub-second duration never refreshes", yaml: "refreshInterval: -500ms", expected: 0},
+		{
+			name:        "milliseconds",
+			yaml:        "refreshInterval: 500ms",
+			expected:    5 * time.Minute,
+			expectedErr: "clock: refreshInterval must be at least 1s, got 500ms",
+		},
+		{
+			name:        "microseconds",
+			yaml:        "refreshInterval: 5us",
+			expected:    5 * time.Minute,
+			expectedErr: "clock: refreshInterval must be at least 1s, got 5µs",
+		},
+		{
+			name:        "fractional seconds",
+			yaml:        "refreshInterval: 1.5",
+			expected:    5 * time.Minute,
+			expectedErr: `clock: refreshInterval must be a number of seconds or a duration such as 5m, got "1.5"`,
+		},
+		{
+			name:        "no unit on a duration",
+			yaml:        "refreshInterval: 1h30",
+			expected:    5 * time.Minute,
+			expectedErr: `clock: refreshInterval must be a number of seconds or a duration such as 5m, got "1h30"`,
+		},
+		{
+			name:        "not a duration",
+			yaml:        "refreshInterval: often",
+			expected:    5 * time.Minute,
+			expectedErr: `clock: refreshInterval must be a number of seconds or a duration such as 5m, got "often"`,
+		},
+		{
+			name:        "empty",
+			yaml:        `refreshInterval: ""`,
+			expected:    5 * time.Minute,
+			expectedErr: `clock: refreshInterval must be a number of seconds or a duration such as 5m, got ""`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moduleConfig, err := config.ParseYaml(tt.yaml)
+			assert.NoError(t, err)
+
+			actual, err := ParseRefreshInterval("clock", moduleConfig, "5m")
+
+			assert.Equal(t, tt.expected, actual)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func Test_ParseRefreshInterval_BadDefault(t *testing.T) {
+	_, err := ParseRefreshInterval("clock", nil, "often")
+
+	assert.Error(t, err)
+}
+
+func Test_NewCommonSettingsFromModule_RefreshInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		expected time.Duration
+		invalid  string
+	}{
+		{name: "default", yaml: "enabled: true", expected: 300 * time.Second},
+		{name: "valid", yaml: "refreshInterval: 5m", expected: 5 * time.Minute},
+		{name: "invalid", yaml: "refreshInterval: 100ms", expected: 300 * time.Second, invalid: "100ms"},
+		{name: "never", yaml: "refreshInterval: -30", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moduleConfig, err := config.ParseYaml(tt.yaml)
+			assert.NoError(t, err)
+
+			common := NewCommonSettingsFromModule("clock", "Clock", false, moduleConfig, globalSettings)
+
+			assert.Equal(t, tt.expected, common.RefreshInterval)
+
+			intervals := refreshIntervalValidations(common)
+			if tt.invalid == "" {
+				assert.Empty(t, intervals)
+				return
+			}
+
+			assert.Len(t, intervals, 1)
+			assert.True(t, intervals[0].HasError())
+			assert.Contains(t, intervals[0].String(), "refreshInterval")
+			assert.Contains(t, intervals[0].String(), tt.invalid)
+			assert.Contains(t, intervals[0].Error().Error(), "clock")
+		})
+	}
+}
+
+func Test_NewCommonSettingsFromModule_RefreshIntervalExpansion(t *testing.T) {
+	t.Setenv("WTF_TEST_REFRESH", "90s")
+
+	moduleConfig := &config.Config{Root: map[string]interface{}{
+		"refreshInterval": "${WTF_TEST_REFRESH}",
+	}}
+
+	common := NewCommonSettingsFromModule("clock", "Clock", false, moduleConfig, globalSettings)
+
+	assert.Equal(t, 90*time.Second, common.RefreshInterval)
+	assert.Empty(t, refreshIntervalValidations(common))
+
+	// A value that can't be expanded is only reported once, as such
+	moduleConfig = &config.Config{Root: map[string]interface{}{
+		"refreshInterval": "${WTF_TEST_REFRESH_MISSING}",
+	}}
+
+	common = NewCommonSettingsFromModule("clock", "Clock", false, moduleConfig, globalSettings)
+
+	assert.Equal(t, 300*time.Second, common.RefreshInterval)
+	assert.Empty(t, refreshIntervalValidations(common))
+	assert.Len(t, common.validations.validations, 1)
+}
+
+func Test_SetDefaultRefreshInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		expected time.Duration
+	}{
+		{name: "not set", yaml: "enabled: true", expected: time.Minute},
+		{name: "set", yaml: "refreshInterval: 5m", expected: 5 * time.Minute},
+		{name: "set to zero", yaml: "refreshInterval: 0", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moduleConfig, err := config.ParseYaml(tt.yaml)
+			assert.NoError(t, err)
+
+			common := NewCommonSettingsFromModule("clock", "Clock", false, moduleConfig, globalSettings)
+			common.SetDefaultRefreshInterval(time.Minute)
+
+			assert.Equal(t, tt.expected, common.RefreshInterval)
+		})
+	}
+}
+
+// refreshIntervalValidations returns the validations of the refresh interval in common
+func refreshIntervalValidations(common *Common) []Validatable {
+	intervals := []Validatable{}
+
+	for _, validation := range common.Validations() {
+		if _, ok := validation.(*refreshIntervalValidation); ok {
+			intervals = append(intervals, validation)
+		}
+	}
+
+	return intervals
+}
@@ -0,0 +1,227 @@
+package cfg
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/logrusorgru/aurora/v4"
+)
+
+// Schema lists the settings a module reads, so that the settings it doesn't read, which are
+// usually typos such as queryfile for queryFile, can be reported instead of being ignored
+type Schema struct {
+	// keys maps each setting to the schema of the settings nested under it, or to nil for
+	// settings that hold a value
+	keys map[string]*Schema
+}
+
+// UnknownKey is a setting in a module's configuration that the module doesn't read
+type UnknownKey struct {
+	// Key is the path of the setting, such as hosts.0.hostnme
+	Key string
+	// Suggestion is the path of the setting closest to Key, or "" when none is close enough
+	Suggestion string
+}
+
+// NewSchema returns the schema of a module: the settings every module reads, and a setting
+// for each field of the given structs that has a help tag. Settings are named after their
+// field, or after the field's key tag when it is read from a different key
+//
+// Example:
+//
+//	schema := NewSchema(Settings{}).Keys("apikey")
+//	schema.Block("colors").Keys("even", "odd")
+func NewSchema(settings ...interface{}) *Schema {
+	schema := newSchema(settings...)
+
+	schema.Keys("border", "enabled", "focusChar", "focusable", "refreshInterval", "showRefreshFooter", "title", "type")
+	schema.Block("position").Keys("top", "left", "width", "height")
+
+	colors := schema.Block("colors").Keys("background", "checked", "label", "subheading", "text", "title")
+	colors.Block("border").Keys("focusable", "focused", "normal")
+	colors.Block("rows").Keys("even", "odd")
+
+	return schema
+}
+
+// CheckKeys compares the module's settings to its schema, for UnknownKeys. Modules call it
+// once they've read their settings, with a schema of every setting they read
+func (common *Common) CheckKeys(schema *Schema) {
+	if common.Config == nil {
+		return
+	}
+
+	common.unknownKeys = schema.unknownKeys(common.Config.Root, "")
+}
+
+// UnknownKeys returns the settings in the module's configuration that the module doesn't
+// read, as found by CheckKeys. The app warns about them on startup
+func (common *Common) UnknownKeys() []UnknownKey {
+	return common.unknownKeys
+}
+
+// SettingKey returns the name of the setting a struct field is read from: its key tag, or
+// else its name with the first letter lowercased
+func SettingKey(field reflect.StructField) string {
+	if key := field.Tag.Get("key"); key != "" {
+		return key
+	}
+
+	r, n := utf8.DecodeRuneInString(field.Name)
+	return string(unicode.ToLower(r)) + field.Name[n:]
+}
+
+// Keys adds settings that hold a value, for the settings that aren't fields with a help tag
+func (schema *Schema) Keys(keys ...string) *Schema {
+	for _, key := range keys {
+		if _, ok := schema.keys[key]; !ok {
+			schema.keys[key] = nil
+		}
+	}
+
+	return schema
+}
+
+// Block returns the schema of the settings nested under key, with a setting for each field
+// of the given structs that has a help tag. The settings of a block apply to a map, and to
+// each map in a list, such as the hosts of a ping module
+func (schema *Schema) Block(key string, settings ...interface{}) *Schema {
+	block := schema.keys[key]
+	if block == nil {
+		block = newSchema()
+		schema.keys[key] = block
+	}
+
+	for _, item := range settings {
+		block.addFields(reflect.TypeOf(item))
+	}
+
+	return block
+}
+
+// String returns the Stringer representation of the UnknownKey
+func (unknown UnknownKey) String() string {
+	if unknown.Suggestion == "" {
+		return fmt.Sprintf("Unknown setting %s", aurora.Yellow(unknown.Key))
+	}
+
+	return fmt.Sprintf("Unknown setting %s, did you mean %s?", aurora.Yellow(unknown.Key), aurora.Green(unknown.Suggestion))
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+func newSchema(settings ...interface{}) *Schema {
+	schema := &Schema{keys: make(map[string]*Schema)}
+
+	for _, item := range settings {
+		schema.addFields(reflect.TypeOf(item))
+	}
+
+	return schema
+}
+
+// addFields adds a setting for each field of t that has a help tag
+func (schema *Schema) addFields(t reflect.Type) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("help") != "" {
+			schema.Keys(SettingKey(field))
+		}
+	}
+}
+
+// unknownKeys returns the settings in value, found under path, that aren't in the schema,
+// sorted by path
+func (schema *Schema) unknownKeys(value interface{}, path string) []UnknownKey {
+	unknown := []UnknownKey{}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		names := make([]string, 0, len(typed))
+		for name := range typed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			block, known := schema.keys[name]
+
+			switch {
+			case !known:
+				unknown = append(unknown, UnknownKey{
+					Key:        joinKey(path, name),
+					Suggestion: schema.suggest(path, name),
+				})
+			case block != nil:
+				unknown = append(unknown, block.unknownKeys(typed[name], joinKey(path, name))...)
+			}
+		}
+	case []interface{}:
+		for idx, item := range typed {
+			unknown = append(unknown, schema.unknownKeys(item, joinKey(path, strconv.Itoa(idx)))...)
+		}
+	}
+
+	return unknown
+}
+
+// suggest returns the path of the setting closest to name, or "" when none is close enough
+// to be what was meant
+func (schema *Schema) suggest(path, name string) string {
+	best := ""
+	bestDistance := max(len(name)/3, 2) + 1
+
+	for key := range schema.keys {
+		distance := editDistance(strings.ToLower(name), strings.ToLower(key))
+		if distance < bestDistance || (distance == bestDistance && key < best) {
+			best, bestDistance = key, distance
+		}
+	}
+
+	if best == "" {
+		return ""
+	}
+
+	return joinKey(path, best)
+}
+
+// editDistance returns the Levenshtein distance between a and b: how many runes have to be
+// inserted, deleted or replaced to turn one into the other
+func editDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+
+	prev := make([]int, len(target)+1)
+	curr := make([]int, len(target)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(target)]
+}
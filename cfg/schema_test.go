@@ -0,0 +1,189 @@
+package cfg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/olebedev/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSchemaSettings struct {
+	Common *Common
+
+	apiKey    string `help:"API key"`
+	queryFile string `help:"Path to the query file" key:"queryFile"`
+	URL       string `help:"URL to fetch" key:"url"`
+	internal  string
+}
+
+type testSchemaHost struct {
+	Hostname string `help:"Host to check"`
+	Port     int    `help:"Port to connect to" optional:"true"`
+}
+
+func testSchema() *Schema {
+	schema := NewSchema(testSchemaSettings{}).Keys("apikey")
+	schema.Block("colors").Keys("even", "odd")
+	schema.Block("hosts", testSchemaHost{})
+	schema.Block("groups").Keys("name").Block("hosts", testSchemaHost{})
+
+	return schema
+}
+
+func Test_CheckKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		expected []UnknownKey
+	}{
+		{
+			name: "known settings",
+			yaml: `
+apiKey: abc
+apikey: abc
+queryFile: query.yaml
+url: https://example.com
+enabled: true
+refreshInterval: 5m
+position:
+  top: 0
+  left: 0
+  height: 1
+  width: 1
+colors:
+  even: white
+  rows:
+    odd: gray
+`,
+			expected: []UnknownKey{},
+		},
+		{
+			name:     "differs in case",
+			yaml:     "queryfile: query.yaml",
+			expected: []UnknownKey{{Key: "queryfile", Suggestion: "queryFile"}},
+		},
+		{
+			name:     "typo",
+			yaml:     "refreshIntreval: 5m",
+			expected: []UnknownKey{{Key: "refreshIntreval", Suggestion: "refreshInterval"}},
+		},
+		{
+			name:     "nothing close",
+			yaml:     "frobnicate: true",
+			expected: []UnknownKey{{Key: "frobnicate"}},
+		},
+		{
+			name:     "field without help",
+			yaml:     "internal: true",
+			expected: []UnknownKey{{Key: "internal"}},
+		},
+		{
+			name:     "field name instead of key",
+			yaml:     "uRL: https://example.com",
+			expected: []UnknownKey{{Key: "uRL", Suggestion: "url"}},
+		},
+		{
+			name: "nested block",
+			yaml: `
+position:
+  top: 0
+  lfet: 0
+colors:
+  border:
+    focussed: red
+`,
+			expected: []UnknownKey{
+				{Key: "colors.border.focussed", Suggestion: "colors.border.focused"},
+				{Key: "position.lfet", Suggestion: "position.left"},
+			},
+		},
+		{
+			name: "list of blocks",
+			yaml: `
+hosts:
+  - hostname: a
+  - hostnme: b
+    prot: 22
+groups:
+  - name: Network
+    hosts:
+      - hostname: c
+        extra: true
+`,
+			expected: []UnknownKey{
+				{Key: "groups.0.hosts.0.extra"},
+				{Key: "hosts.1.hostnme", Suggestion: "hosts.1.hostname"},
+				{Key: "hosts.1.prot", Suggestion: "hosts.1.port"},
+			},
+		},
+		{
+			name:     "value where a block is expected",
+			yaml:     "position: top\nhosts: [a, b]",
+			expected: []UnknownKey{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moduleConfig, err := config.ParseYaml(tt.yaml)
+			assert.NoError(t, err)
+
+			common := &Common{Config: moduleConfig}
+			common.CheckKeys(testSchema())
+
+			assert.Equal(t, tt.expected, common.UnknownKeys())
+		})
+	}
+}
+
+func Test_CheckKeys_NoConfig(t *testing.T) {
+	common := &Common{}
+	common.CheckKeys(testSchema())
+
+	assert.Empty(t, common.UnknownKeys())
+}
+
+func Test_SettingKey(t *testing.T) {
+	settingsType := reflect.TypeOf(testSchemaSettings{})
+
+	tests := []struct {
+		field    string
+		expected string
+	}{
+		{field: "apiKey", expected: "apiKey"},
+		{field: "queryFile", expected: "queryFile"},
+		{field: "URL", expected: "url"},
+		{field: "Common", expected: "common"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field, _ := settingsType.FieldByName(tt.field)
+			assert.Equal(t, tt.expected, SettingKey(field))
+		})
+	}
+}
+
+func Test_editDistance(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "", b: "", expected: 0},
+		{a: "", b: "abc", expected: 3},
+		{a: "abc", b: "", expected: 3},
+		{a: "title", b: "title", expected: 0},
+		{a: "titel", b: "title", expected: 2},
+		{a: "kitten", b: "sitting", expected: 3},
+		{a: "hostnme", b: "hostname", expected: 1},
+		{a: "größe", b: "grösse", expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, editDistance(tt.a, tt.b))
+		})
+	}
+}
@@ -44,7 +44,8 @@ func main() {
 
 	openFileUtil := config.UString("wtf.openFileUtil", "open")
 	openURLUtil := utils.ToStrs(config.UList("wtf.openUrlUtil", []interface{}{}))
-	utils.Init(openFileUtil, openURLUtil)
+	clipboardMode := config.UString("wtf.clipboardMode", utils.ClipboardAuto)
+	utils.Init(openFileUtil, openURLUtil, clipboardMode)
 
 	/* Initialize the App Manager */
 	appMan := app.NewAppManager()
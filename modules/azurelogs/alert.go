@@ -0,0 +1,316 @@
+package azurelogs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultAlertPrefix = "⚠"
+
+	aggregateFirst = "first"
+	aggregateMax   = "max"
+	aggregateMin   = "min"
+	aggregateSum   = "sum"
+
+	severityWarn     = "warn"
+	severityCritical = "critical"
+)
+
+// defaultSeverityColors are used when the alert block does not define a color for a severity
+var defaultSeverityColors = map[string]string{
+	severityWarn:     "yellow",
+	severityCritical: "red",
+}
+
+// severityRanks orders the severities, from least to most severe. Other severity names rank
+// below them
+var severityRanks = map[string]int{
+	severityWarn:     1,
+	severityCritical: 2,
+}
+
+// AlertConfig describes the thresholds evaluated against a column of the query result
+type AlertConfig struct {
+	Column         string            `yaml:"column"`         // Column the thresholds are evaluated against
+	Aggregate      string            `yaml:"aggregate"`      // How rows are reduced: first (default), max, min, sum
+	Operator       string            `yaml:"operator"`       // Shorthand for a single critical threshold
+	Value          string            `yaml:"value"`          // Shorthand for a single critical threshold
+	Prefix         string            `yaml:"prefix"`         // Prefix added to the title when triggered
+	SeverityColors map[string]string `yaml:"severityColors"` // Color per severity name
+	Thresholds     []AlertThreshold  `yaml:"thresholds"`     // Thresholds, the most severe match is reported
+}
+
+// AlertThreshold is a single comparison that raises an alert of the given severity
+type AlertThreshold struct {
+	Severity string `yaml:"severity"`
+	Operator string `yaml:"operator"`
+	Value    string `yaml:"value"`
+}
+
+// AlertResult describes a triggered alert
+type AlertResult struct {
+	Column    string
+	Severity  string
+	Color     string
+	Prefix    string
+	Value     string
+	Threshold AlertThreshold
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+// IsConfigured returns true if the alert block defines anything to evaluate
+func (ac *AlertConfig) IsConfigured() bool {
+	return ac != nil && (ac.Column != "" || ac.Operator != "" || len(ac.Thresholds) > 0)
+}
+
+// Validate checks that the alert block is well formed
+func (ac *AlertConfig) Validate() error {
+	if !ac.IsConfigured() {
+		return nil
+	}
+
+	if ac.Column == "" {
+		return fmt.Errorf("alert column is required")
+	}
+
+	switch ac.aggregate() {
+	case aggregateFirst, aggregateMax, aggregateMin, aggregateSum:
+	default:
+		return fmt.Errorf("invalid alert aggregate %q, expected one of first, max, min, sum", ac.Aggregate)
+	}
+
+	thresholds := ac.thresholds()
+	if len(thresholds) == 0 {
+		return fmt.Errorf("alert on column %s defines no thresholds", ac.Column)
+	}
+
+	for i, threshold := range thresholds {
+		if !isValidOperator(threshold.Operator) {
+			return fmt.Errorf("alert threshold %d has invalid operator %q", i+1, threshold.Operator)
+		}
+
+		if threshold.Value == "" {
+			return fmt.Errorf("alert threshold %d has no value", i+1)
+		}
+
+		if ac.aggregate() != aggregateFirst {
+			if _, err := strconv.ParseFloat(threshold.Value, 64); err != nil {
+				return fmt.Errorf("alert threshold %d value %q must be numeric when using aggregate %s", i+1, threshold.Value, ac.aggregate())
+			}
+		}
+	}
+
+	return nil
+}
+
+// Evaluate reduces the alert column of the table and returns the most severe triggered
+// threshold, or nil if none of them match
+func (ac *AlertConfig) Evaluate(tr *TableResp) (*AlertResult, error) {
+	if !ac.IsConfigured() || tr == nil {
+		return nil, nil
+	}
+
+	colIdx := columnIndex(tr.Header, ac.Column)
+	if colIdx < 0 {
+		return nil, fmt.Errorf("alert column %s not found in query columns", ac.Column)
+	}
+
+	if len(tr.Rows) == 0 {
+		return nil, nil
+	}
+
+	value, err := ac.reduce(tr.Rows, colIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The most severe threshold that matches wins, whatever the order they are listed in. Of
+	// those as severe, the last one listed does
+	var result *AlertResult
+	for _, threshold := range ac.thresholds() {
+		if !compareValues(value, threshold.Operator, threshold.Value) {
+			continue
+		}
+		if result != nil && severityRanks[threshold.Severity] < severityRanks[result.Severity] {
+			continue
+		}
+
+		result = &AlertResult{
+			Column:    ac.Column,
+			Severity:  threshold.Severity,
+			Color:     ac.colorFor(threshold.Severity),
+			Prefix:    ac.prefix(),
+			Value:     value,
+			Threshold: threshold,
+		}
+	}
+
+	return result, nil
+}
+
+// Matches returns true if the given cell value satisfies the triggered threshold
+func (ar *AlertResult) Matches(cell string) bool {
+	if ar == nil {
+		return false
+	}
+
+	return compareValues(strings.TrimSpace(cell), ar.Threshold.Operator, ar.Threshold.Value)
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+func (ac *AlertConfig) aggregate() string {
+	if ac.Aggregate == "" {
+		return aggregateFirst
+	}
+
+	return strings.ToLower(ac.Aggregate)
+}
+
+func (ac *AlertConfig) colorFor(severity string) string {
+	if color, ok := ac.SeverityColors[severity]; ok && color != "" {
+		return color
+	}
+
+	if color, ok := defaultSeverityColors[severity]; ok {
+		return color
+	}
+
+	return defaultSeverityColors[severityCritical]
+}
+
+func (ac *AlertConfig) prefix() string {
+	if ac.Prefix == "" {
+		return defaultAlertPrefix
+	}
+
+	return ac.Prefix
+}
+
+// reduce collapses the alert column into a single value using the configured aggregate
+func (ac *AlertConfig) reduce(rows []TableRow, colIdx int) (string, error) {
+	if ac.aggregate() == aggregateFirst {
+		if colIdx >= len(rows[0]) {
+			return "", nil
+		}
+		return strings.TrimSpace(rows[0][colIdx]), nil
+	}
+
+	var total float64
+	found := false
+
+	for _, row := range rows {
+		if colIdx >= len(row) {
+			continue
+		}
+
+		num, err := strconv.ParseFloat(strings.TrimSpace(row[colIdx]), 64)
+		if err != nil {
+			return "", fmt.Errorf("alert column %s contains non-numeric value %q", ac.Column, row[colIdx])
+		}
+
+		switch {
+		case !found:
+			total = num
+		case ac.aggregate() == aggregateMax && num > total:
+			total = num
+		case ac.aggregate() == aggregateMin && num < total:
+			total = num
+		case ac.aggregate() == aggregateSum:
+			total += num
+		}
+		found = true
+	}
+
+	return strconv.FormatFloat(total, 'f', -1, 64), nil
+}
+
+// thresholds returns the configured thresholds, including the single-threshold shorthand
+func (ac *AlertConfig) thresholds() []AlertThreshold {
+	thresholds := []AlertThreshold{}
+
+	if ac.Operator != "" || ac.Value != "" {
+		thresholds = append(thresholds, AlertThreshold{
+			Severity: severityCritical,
+			Operator: ac.Operator,
+			Value:    ac.Value,
+		})
+	}
+
+	for _, threshold := range ac.Thresholds {
+		if threshold.Severity == "" {
+			threshold.Severity = severityCritical
+		}
+		thresholds = append(thresholds, threshold)
+	}
+
+	return thresholds
+}
+
+// columnIndex returns the index of the named column, or -1 if it is not present
+func columnIndex(headers []string, name string) int {
+	for i, header := range headers {
+		if strings.EqualFold(header, name) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func isValidOperator(op string) bool {
+	switch op {
+	case ">", ">=", "<", "<=", "==", "!=", "contains":
+		return true
+	}
+
+	return false
+}
+
+// compareValues compares numerically when both sides are numbers, and as strings otherwise
+func compareValues(actual, op, expected string) bool {
+	if op == "contains" {
+		return strings.Contains(strings.ToLower(actual), strings.ToLower(expected))
+	}
+
+	a, errA := strconv.ParseFloat(actual, 64)
+	e, errE := strconv.ParseFloat(expected, 64)
+	if errA == nil && errE == nil {
+		switch op {
+		case ">":
+			return a > e
+		case ">=":
+			return a >= e
+		case "<":
+			return a < e
+		case "<=":
+			return a <= e
+		case "==":
+			return a == e
+		case "!=":
+			return a != e
+		}
+		return false
+	}
+
+	cmp := strings.Compare(actual, expected)
+	switch op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	}
+
+	return false
+}
@@ -0,0 +1,259 @@
+package azurelogs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertConfig_Evaluate_Numeric(t *testing.T) {
+	alert := &AlertConfig{
+		Column: "ErrorCount",
+		Thresholds: []AlertThreshold{
+			{Severity: "warn", Operator: ">", Value: "10"},
+			{Severity: "critical", Operator: ">=", Value: "100"},
+		},
+	}
+	require.NoError(t, alert.Validate())
+
+	tests := []struct {
+		name             string
+		value            string
+		expectedSeverity string
+		expectedColor    string
+	}{
+		{name: "below thresholds", value: "5"},
+		{name: "warn", value: "42", expectedSeverity: "warn", expectedColor: "yellow"},
+		{name: "critical", value: "100", expectedSeverity: "critical", expectedColor: "red"},
+		{name: "numeric not lexical", value: "9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &TableResp{
+				Header: []string{"Computer", "ErrorCount"},
+				Rows:   []TableRow{{"web-01", tt.value}},
+			}
+
+			result, err := alert.Evaluate(tr)
+			require.NoError(t, err)
+
+			if tt.expectedSeverity == "" {
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expectedSeverity, result.Severity)
+			assert.Equal(t, tt.expectedColor, result.Color)
+			assert.Equal(t, defaultAlertPrefix, result.Prefix)
+		})
+	}
+}
+
+func TestAlertConfig_Evaluate_MostSevere(t *testing.T) {
+	tr := &TableResp{
+		Header: []string{"Computer", "ErrorCount"},
+		Rows:   []TableRow{{"web-01", "150"}},
+	}
+
+	tests := []struct {
+		name  string
+		alert *AlertConfig
+	}{
+		{
+			name: "critical listed first",
+			alert: &AlertConfig{
+				Column: "ErrorCount",
+				Thresholds: []AlertThreshold{
+					{Severity: "critical", Operator: ">=", Value: "100"},
+					{Severity: "warn", Operator: ">", Value: "10"},
+				},
+			},
+		},
+		{
+			name: "shorthand critical with a warn threshold",
+			alert: &AlertConfig{
+				Column:     "ErrorCount",
+				Operator:   ">=",
+				Value:      "100",
+				Thresholds: []AlertThreshold{{Severity: "warn", Operator: ">", Value: "10"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.alert.Validate())
+
+			result, err := tt.alert.Evaluate(tr)
+			require.NoError(t, err)
+			require.NotNil(t, result)
+
+			assert.Equal(t, "critical", result.Severity)
+			assert.Equal(t, "red", result.Color)
+			assert.Equal(t, "100", result.Threshold.Value)
+		})
+	}
+}
+
+func TestAlertConfig_Evaluate_String(t *testing.T) {
+	alert := &AlertConfig{
+		Column:         "Level",
+		Operator:       "==",
+		Value:          "Error",
+		Prefix:         "!!",
+		SeverityColors: map[string]string{"critical": "orange"},
+	}
+	require.NoError(t, alert.Validate())
+
+	tr := &TableResp{
+		Header: []string{"Level", "Message"},
+		Rows:   []TableRow{{"Error", "disk full"}, {"Info", "ok"}},
+	}
+
+	result, err := alert.Evaluate(tr)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "orange", result.Color)
+	assert.Equal(t, "!!", result.Prefix)
+	assert.True(t, result.Matches("Error"))
+	assert.False(t, result.Matches("Info"))
+}
+
+func TestAlertConfig_Evaluate_Aggregate(t *testing.T) {
+	tr := &TableResp{
+		Header: []string{"Bucket", "Count"},
+		Rows:   []TableRow{{"a", "3"}, {"b", "12"}, {"c", "7"}},
+	}
+
+	tests := []struct {
+		aggregate string
+		expected  string
+	}{
+		{aggregate: "max", expected: "12"},
+		{aggregate: "min", expected: "3"},
+		{aggregate: "sum", expected: "22"},
+		{aggregate: "", expected: "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.aggregate, func(t *testing.T) {
+			alert := &AlertConfig{Column: "Count", Aggregate: tt.aggregate, Operator: ">", Value: "0"}
+
+			result, err := alert.Evaluate(tr)
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expected, result.Value)
+		})
+	}
+}
+
+func TestAlertConfig_Evaluate_Errors(t *testing.T) {
+	tr := &TableResp{
+		Header: []string{"Level"},
+		Rows:   []TableRow{{"Error"}},
+	}
+
+	_, err := (&AlertConfig{Column: "Missing", Operator: ">", Value: "1"}).Evaluate(tr)
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = (&AlertConfig{Column: "Level", Aggregate: "max", Operator: ">", Value: "1"}).Evaluate(tr)
+	assert.ErrorContains(t, err, "non-numeric")
+
+	var alert *AlertConfig
+	result, err := alert.Evaluate(tr)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestAlertConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		alert         *AlertConfig
+		expectedError string
+	}{
+		{name: "nil", alert: nil},
+		{name: "missing column", alert: &AlertConfig{Operator: ">", Value: "1"}, expectedError: "column is required"},
+		{name: "no thresholds", alert: &AlertConfig{Column: "A"}, expectedError: "no thresholds"},
+		{name: "bad operator", alert: &AlertConfig{Column: "A", Operator: "=~", Value: "1"}, expectedError: "invalid operator"},
+		{name: "missing value", alert: &AlertConfig{Column: "A", Operator: ">"}, expectedError: "has no value"},
+		{name: "bad aggregate", alert: &AlertConfig{Column: "A", Aggregate: "avg", Operator: ">", Value: "1"}, expectedError: "invalid alert aggregate"},
+		{name: "non-numeric aggregate value", alert: &AlertConfig{Column: "A", Aggregate: "max", Operator: ">", Value: "x"}, expectedError: "must be numeric"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.alert.Validate()
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestReadQueryFileContent_Alert(t *testing.T) {
+	yamlContent := `azure_subscription_id: "sub"
+azure_workspace_id: "ws"
+columns: ["Count"]
+query: "AzureActivity | count"
+alert:
+  column: Count
+  thresholds:
+    - severity: warn
+      operator: ">"
+      value: 10
+    - severity: critical
+      operator: ">"
+      value: 100`
+
+	tmpFile := writeTempQueryFile(t, yamlContent)
+
+	qf, err := readQueryFileContent(tmpFile)
+	require.NoError(t, err)
+	require.NotNil(t, qf.Alert)
+	assert.Len(t, qf.Alert.Thresholds, 2)
+	assert.Equal(t, "10", qf.Alert.Thresholds[0].Value)
+
+	tmpFile = writeTempQueryFile(t, yamlContent+"\n  aggregate: median")
+
+	_, err = readQueryFileContent(tmpFile)
+	assert.ErrorContains(t, err, "invalid alert")
+}
+
+func TestWidget_RenderTable_Alert(t *testing.T) {
+	widget := createTestWidget()
+	widget.tableData = &TableResp{
+		Header: []string{"Computer", "Errors"},
+		Rows:   []TableRow{{"web-01", "150"}, {"web-02", "1"}},
+	}
+	widget.alert = &AlertResult{
+		Column:    "Errors",
+		Color:     "red",
+		Prefix:    defaultAlertPrefix,
+		Threshold: AlertThreshold{Operator: ">", Value: "100"},
+	}
+
+	title, content, _ := widget.renderTable("Test Title")
+
+	assert.Equal(t, "[red]⚠ Test Title[white]", title)
+	assert.Contains(t, content, "[red]150     [white]")
+	assert.NotContains(t, content, "[red]1       [white]")
+}
+
+func writeTempQueryFile(t *testing.T, content string) string {
+	t.Helper()
+
+	tmpFile, err := os.CreateTemp(t.TempDir(), "query-*.yaml")
+	require.NoError(t, err)
+
+	_, err = tmpFile.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, tmpFile.Close())
+
+	return tmpFile.Name()
+}
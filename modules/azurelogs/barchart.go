@@ -0,0 +1,129 @@
+package azurelogs
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/wtfutil/wtf/utils"
+)
+
+const (
+	renderBarChart = "barchart"
+	renderTable    = "table"
+
+	barFull        = "█"
+	minBarWidth    = 1
+	negativeColor  = "red"
+	positiveColor  = "green"
+	barChartMargin = 2 // spaces between label, bar and value
+)
+
+// barPartials are the eighth-width block characters used for the fractional end of a bar
+var barPartials = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// barChartValues validates that the table has the label/number shape required for a bar
+// chart and returns the parsed values
+func barChartValues(tr *TableResp) ([]float64, error) {
+	if tr == nil || len(tr.Header) != 2 {
+		return nil, fmt.Errorf("bar chart requires exactly two columns")
+	}
+
+	values := make([]float64, len(tr.Rows))
+	for i, row := range tr.Rows {
+		if len(row) < 2 {
+			return nil, fmt.Errorf("bar chart row %d has %d columns, expected 2", i+1, len(row))
+		}
+
+		num, err := strconv.ParseFloat(strings.TrimSpace(row[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("bar chart column %s is not numeric: %q", tr.Header[1], row[1])
+		}
+		values[i] = num
+	}
+
+	return values, nil
+}
+
+// formatBarChart renders a two-column table as horizontal bars scaled to the largest
+// absolute value, fitting everything into the available width
+func formatBarChart(tr *TableResp, availableWidth int) (string, error) {
+	values, err := barChartValues(tr)
+	if err != nil {
+		return "", err
+	}
+
+	rowCount := len(tr.Rows)
+	if rowCount > maxDisplayRows {
+		rowCount = maxDisplayRows
+	}
+
+	labelWidth := cellWidth(tr.Header[0])
+	valueWidth := cellWidth(tr.Header[1])
+	maxValue := 0.0
+	for i := 0; i < rowCount; i++ {
+		labelWidth = max(labelWidth, cellWidth(strings.TrimSpace(tr.Rows[i][0])))
+		valueWidth = max(valueWidth, cellWidth(strings.TrimSpace(tr.Rows[i][1])))
+		maxValue = math.Max(maxValue, math.Abs(values[i]))
+	}
+	labelWidth = min(labelWidth, maxColumnWidth)
+
+	barWidth := availableWidth - labelWidth - valueWidth - 2*barChartMargin
+	if barWidth < minBarWidth {
+		barWidth = minBarWidth
+	}
+
+	var sb strings.Builder
+	for i := 0; i < rowCount; i++ {
+		label := utils.TruncateTagged(utils.EscapeTview(strings.TrimSpace(tr.Rows[i][0])), labelWidth, truncateMarker)
+
+		bar := scaleBar(values[i], maxValue, barWidth)
+		color := positiveColor
+		if values[i] < 0 {
+			color = negativeColor
+		}
+
+		_, _ = fmt.Fprintf(
+			&sb,
+			"%s%s[%s]%s[white]%s%s\n",
+			padTagged(label, labelWidth),
+			strings.Repeat(" ", barChartMargin),
+			color, bar,
+			strings.Repeat(" ", barWidth-barCells(values[i], maxValue, barWidth)+barChartMargin),
+			tview.Escape(strings.TrimSpace(tr.Rows[i][1])),
+		)
+	}
+
+	if len(tr.Rows) > maxDisplayRows {
+		_, _ = fmt.Fprintf(&sb, "\n[gray]... (%d more rows truncated for display)[white]\n", len(tr.Rows)-maxDisplayRows)
+	}
+
+	return sb.String(), nil
+}
+
+// scaleBar returns the bar for a value, in eighths of a cell, relative to maxValue
+func scaleBar(value, maxValue float64, width int) string {
+	if maxValue == 0 || value == 0 {
+		return ""
+	}
+
+	eighths := int(math.Round(math.Abs(value) / maxValue * float64(width*8)))
+	return strings.Repeat(barFull, eighths/8) + barPartials[eighths%8]
+}
+
+// barCells returns the number of terminal cells the bar for a value occupies
+func barCells(value, maxValue float64, width int) int {
+	if maxValue == 0 || value == 0 {
+		return 0
+	}
+
+	eighths := int(math.Round(math.Abs(value) / maxValue * float64(width*8)))
+	cells := eighths / 8
+	if eighths%8 > 0 {
+		cells++
+	}
+
+	return cells
+}
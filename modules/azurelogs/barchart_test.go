@@ -0,0 +1,139 @@
+package azurelogs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wtfutil/wtf/utils"
+)
+
+func TestFormatBarChart_Scaling(t *testing.T) {
+	tr := &TableResp{
+		Header: []string{"Hour", "Count"},
+		Rows: []TableRow{
+			{"10:00", "50"},
+			{"11:00", "100"},
+			{"12:00", "25"},
+		},
+	}
+
+	// label(5) + margin(2) + bar + margin(2) + value(5) = 34, so bars are 20 cells wide
+	chart, err := formatBarChart(tr, 34)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimRight(chart, "\n"), "\n")
+	require.Len(t, lines, 3)
+
+	assert.Equal(t, 10, strings.Count(lines[0], barFull))
+	assert.Equal(t, 20, strings.Count(lines[1], barFull))
+	assert.Equal(t, 5, strings.Count(lines[2], barFull))
+
+	for i, line := range lines {
+		assert.True(t, strings.HasSuffix(line, tr.Rows[i][1]), "line should end with its value: %q", line)
+	}
+}
+
+func TestFormatBarChart_ZeroValues(t *testing.T) {
+	tr := &TableResp{
+		Header: []string{"Hour", "Count"},
+		Rows: []TableRow{
+			{"10:00", "0"},
+			{"11:00", "0"},
+		},
+	}
+
+	chart, err := formatBarChart(tr, 40)
+	require.NoError(t, err)
+
+	assert.NotContains(t, chart, barFull)
+	assert.Contains(t, chart, "10:00")
+	assert.Contains(t, chart, "0\n")
+}
+
+func TestFormatBarChart_NegativeValues(t *testing.T) {
+	tr := &TableResp{
+		Header: []string{"Region", "Delta"},
+		Rows: []TableRow{
+			{"east", "-40"},
+			{"west", "20"},
+		},
+	}
+
+	chart, err := formatBarChart(tr, 36)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimRight(chart, "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	assert.Contains(t, lines[0], "["+negativeColor+"]")
+	assert.Contains(t, lines[1], "["+positiveColor+"]")
+	assert.Greater(t, strings.Count(lines[0], barFull), strings.Count(lines[1], barFull))
+	assert.True(t, strings.HasSuffix(lines[0], "-40"))
+}
+
+func TestFormatBarChart_RespectsWidth(t *testing.T) {
+	tr := &TableResp{
+		Header: []string{"Hour", "Count"},
+		Rows:   []TableRow{{"10:00", "1000"}},
+	}
+
+	chart, err := formatBarChart(tr, 5)
+	require.NoError(t, err)
+
+	// Even when there's no room, the bar shrinks to the minimum rather than disappearing
+	assert.Equal(t, minBarWidth, strings.Count(chart, barFull))
+}
+
+func TestFormatBarChart_LabelWidths(t *testing.T) {
+	tr := &TableResp{
+		Header: []string{"City", "Count"},
+		Rows: []TableRow{
+			{"東京", "10"},
+			{"[ERROR]", "10"},
+			{strings.Repeat("日", 20), "10"},
+		},
+	}
+
+	chart, err := formatBarChart(tr, 80)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimRight(chart, "\n"), "\n")
+	require.Len(t, lines, 3)
+
+	// Every bar starts in the same column, wide runes and escaped brackets included
+	for _, line := range lines {
+		label, _, found := strings.Cut(line, "[green]")
+		require.True(t, found, "line should have a bar: %q", line)
+		assert.Equal(t, maxColumnWidth+barChartMargin, utils.DisplayWidth(label), "label: %q", label)
+	}
+
+	assert.True(t, strings.HasPrefix(lines[1], "[ERROR[]"))
+	assert.True(t, strings.HasPrefix(lines[2], strings.Repeat("日", 13)+truncateMarker+" "))
+}
+
+func TestFormatBarChart_InvalidShape(t *testing.T) {
+	_, err := formatBarChart(&TableResp{Header: []string{"A", "B", "C"}}, 80)
+	assert.ErrorContains(t, err, "exactly two columns")
+
+	_, err = formatBarChart(&TableResp{
+		Header: []string{"Hour", "Level"},
+		Rows:   []TableRow{{"10:00", "Error"}},
+	}, 80)
+	assert.ErrorContains(t, err, "not numeric")
+}
+
+func TestWidget_RenderTable_BarChartFallback(t *testing.T) {
+	widget := createTestWidget()
+	widget.renderMode = renderBarChart
+	widget.tableData = &TableResp{
+		Header: []string{"Level", "Message"},
+		Rows:   []TableRow{{"Error", "disk full"}},
+	}
+
+	_, content, _ := widget.renderTable("Test Title")
+
+	assert.Contains(t, content, "Cannot render bar chart")
+	assert.Contains(t, content, "[lightblue]Level")
+}
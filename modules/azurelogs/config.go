@@ -10,11 +10,17 @@ import (
 
 // QueryFile represents the structure of a query configuration file
 type QueryFile struct {
-	Title          string   `yaml:"title"`                 // Display title for the query
-	SubscriptionID string   `yaml:"azure_subscription_id"` // Azure subscription ID
-	WorkspaceID    string   `yaml:"azure_workspace_id"`    // Log Analytics workspace ID
-	Columns        []string `yaml:"columns"`               // Expected column names
-	Query          string   `yaml:"query"`                 // KQL query string
+	Title          string            `yaml:"title"`                 // Display title for the query
+	SubscriptionID string            `yaml:"azure_subscription_id"` // Azure subscription ID
+	WorkspaceID    string            `yaml:"azure_workspace_id"`    // Log Analytics workspace ID
+	ResourceID     string            `yaml:"azure_resource_id"`     // Azure resource ID, used instead of a workspace
+	Columns        []string          `yaml:"columns"`               // Expected column names
+	Query          string            `yaml:"query"`                 // KQL query string, expanded as a Go template
+	Params         map[string]string `yaml:"params"`                // Values available to the query template
+	Alert          *AlertConfig      `yaml:"alert"`                 // Optional threshold alert
+	Render         string            `yaml:"render"`                // How results are drawn: table (default) or barchart
+	MinSeverity    string            `yaml:"minSeverity"`           // Rows below this severity are left out, e.g. Warning
+	SeverityColumn string            `yaml:"severityColumn"`        // Column holding the severity, Level or SeverityLevel by default
 }
 
 // readQueryFile reads and parses a query configuration file
@@ -53,5 +59,21 @@ func readQueryFileContent(filePath string) (QueryFile, error) {
 		return configFile, fmt.Errorf("failed to parse YAML in config file %s: %w", filePath, err)
 	}
 
+	switch configFile.Render {
+	case "", renderTable, renderBarChart:
+	default:
+		return configFile, fmt.Errorf("invalid render mode %q in config file %s, expected table or barchart", configFile.Render, filePath)
+	}
+
+	err = validateMinSeverity(configFile.MinSeverity)
+	if err != nil {
+		return configFile, fmt.Errorf("%w in config file %s", err, filePath)
+	}
+
+	err = configFile.Alert.Validate()
+	if err != nil {
+		return configFile, fmt.Errorf("invalid alert in config file %s: %w", filePath, err)
+	}
+
 	return configFile, nil
 }
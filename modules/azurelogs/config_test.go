@@ -218,3 +218,17 @@ query: "TestQuery | limit 1"`
 	assert.Equal(t, []string{"TestColumn1", "TestColumn2"}, queryFile.Columns) // yaml:"columns"
 	assert.Equal(t, "TestQuery | limit 1", queryFile.Query)                    // yaml:"query"
 }
+
+func TestReadQueryFileContent_ResourceID(t *testing.T) {
+	yamlContent := `azure_subscription_id: "sub"
+azure_resource_id: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Insights/components/app"
+columns: ["Count"]
+query: "requests | count"`
+
+	queryFile, err := readQueryFileContent(writeTempQueryFile(t, yamlContent))
+	require.NoError(t, err)
+
+	assert.Equal(t, "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Insights/components/app", queryFile.ResourceID)
+	assert.Empty(t, queryFile.WorkspaceID)
+	assert.NoError(t, validateQueryTarget(queryFile))
+}
@@ -0,0 +1,103 @@
+package azurelogs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tsvEscaper escapes the characters that would break a tab-separated line
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// serializeTSV joins the row cells with tabs, escaping tabs, newlines and backslashes
+// inside the cells so that the row stays on a single line
+func serializeTSV(row TableRow) string {
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		cells[i] = tsvEscaper.Replace(cell)
+	}
+
+	return strings.Join(cells, "\t")
+}
+
+// resolveColumn finds a column by name (case-insensitive) or by its 1-based position
+func resolveColumn(headers []string, text string) (int, error) {
+	text = strings.TrimSpace(text)
+
+	if idx := columnIndex(headers, text); idx >= 0 {
+		return idx, nil
+	}
+
+	if num, err := strconv.Atoi(text); err == nil && num >= 1 && num <= len(headers) {
+		return num - 1, nil
+	}
+
+	return -1, fmt.Errorf("unknown column %q", text)
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// copySelectedRow copies the full, untruncated, selected row as tab-separated values
+func (widget *Widget) copySelectedRow() {
+	widget.mu.Lock()
+	row, selected := widget.selectedRow(), widget.selected
+	widget.mu.Unlock()
+
+	if row == nil {
+		widget.setToast("[red]No row selected[white]")
+		return
+	}
+
+	widget.copyText(serializeTSV(row), fmt.Sprintf("row %d", selected+1))
+}
+
+// copySelectedCell prompts for a column, then copies the untruncated cell of the selected row
+func (widget *Widget) copySelectedCell() {
+	widget.mu.Lock()
+	row := widget.selectedRow()
+	var headers []string
+	if widget.tableData != nil {
+		headers = widget.tableData.Header
+	}
+	widget.mu.Unlock()
+
+	if row == nil {
+		widget.setToast("[red]No row selected[white]")
+		return
+	}
+
+	// The headers of the row selected, should a fetch end while the prompt is open
+	widget.showPrompt("Column: ", "", func(text string) {
+		colIdx, err := resolveColumn(headers, text)
+		if err != nil {
+			widget.setToast(fmt.Sprintf("[red]%v[white]", err))
+			return
+		}
+
+		cell := ""
+		if colIdx < len(row) {
+			cell = row[colIdx]
+		}
+
+		widget.copyText(cell, headers[colIdx])
+	}, nil)
+}
+
+// copyText writes text to the clipboard and reports the outcome in the footer
+func (widget *Widget) copyText(text, description string) {
+	err := widget.clipboard.Write(text)
+	if err != nil {
+		widget.setToast(fmt.Sprintf("[red]Copy failed: %v[white]", err))
+		return
+	}
+
+	widget.setToast(fmt.Sprintf("[green]Copied %s to the clipboard[white]", description))
+}
+
+// setToast shows a short message in the footer until the next successful fetch
+func (widget *Widget) setToast(message string) {
+	widget.update(func() bool {
+		widget.toast = message
+		return true
+	})
+}
@@ -0,0 +1,72 @@
+package azurelogs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wtfutil/wtf/utils"
+)
+
+func TestSerializeTSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		row      TableRow
+		expected string
+	}{
+		{name: "plain cells", row: TableRow{"a", "b", "c"}, expected: "a\tb\tc"},
+		{name: "empty cells", row: TableRow{"", "b", ""}, expected: "\tb\t"},
+		{name: "tab inside a cell", row: TableRow{"a\tb", "c"}, expected: `a\tb` + "\tc"},
+		{name: "newlines inside a cell", row: TableRow{"line1\nline2\r\n", "x"}, expected: `line1\nline2\r\n` + "\tx"},
+		{name: "backslashes are escaped first", row: TableRow{`C:\temp\new`}, expected: `C:\\temp\\new`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, serializeTSV(tt.row))
+		})
+	}
+}
+
+func TestResolveColumn(t *testing.T) {
+	headers := []string{"TimeGenerated", "Level", "Message"}
+
+	idx, err := resolveColumn(headers, "message")
+	require.NoError(t, err)
+	assert.Equal(t, 2, idx)
+
+	idx, err = resolveColumn(headers, " 2 ")
+	require.NoError(t, err)
+	assert.Equal(t, 1, idx)
+
+	_, err = resolveColumn(headers, "4")
+	assert.Error(t, err)
+}
+
+func TestWidget_CopySelectedRow(t *testing.T) {
+	longMessage := "a message that is much longer than the maximum column width of the table"
+
+	widget := createTestWidget()
+	drainRedraws(t, widget)
+	widget.tableData = &TableResp{
+		Header: []string{"Level", "Message"},
+		Rows:   []TableRow{{"Info", "ok"}, {"Error", longMessage}},
+	}
+
+	clipboard := &utils.FakeClipboard{}
+	widget.clipboard = clipboard
+
+	widget.copySelectedRow()
+	assert.Contains(t, widget.toast, "No row selected")
+
+	widget.selected = 1
+	widget.copySelectedRow()
+	assert.Equal(t, []string{"Error\t" + longMessage}, clipboard.Copies())
+	assert.Contains(t, widget.toast, "Copied row 2")
+
+	clipboard.Err = errors.New("no clipboard utility found")
+	widget.copySelectedRow()
+	assert.Contains(t, widget.toast, "[red]Copy failed: no clipboard utility found")
+}
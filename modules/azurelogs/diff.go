@@ -0,0 +1,249 @@
+package azurelogs
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	deltaUp   = "▲"
+	deltaDown = "▼"
+)
+
+// rowMark is how a row differs from the previous refresh
+type rowMark struct {
+	added bool
+	// deltas are how much the numeric cells that changed went up or down, by column name
+	deltas map[string]float64
+}
+
+// tableDiff is how a table differs from the one of the previous refresh: which of its rows
+// are new or changed, and which rows of the previous table are gone
+type tableDiff struct {
+	marks   []rowMark
+	removed []TableRow
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// diffTables compares the current table to the previous one. Rows are matched by the values
+// of their key columns, the first column when none are given, and rows with the same key
+// are matched in order. There's no diff when there's no previous table, or when its columns
+// aren't the same
+func diffTables(previous, current *TableResp, keyColumns []string) *tableDiff {
+	if previous == nil || current == nil || !slices.Equal(previous.Header, current.Header) {
+		return nil
+	}
+
+	keys := keyIndices(current.Header, keyColumns)
+
+	previousRows := map[string]TableRow{}
+	for _, key := range occurrenceKeys(previous.Rows, keys) {
+		previousRows[key.key] = key.row
+	}
+
+	diff := &tableDiff{marks: make([]rowMark, len(current.Rows))}
+	matched := map[string]bool{}
+
+	for i, key := range occurrenceKeys(current.Rows, keys) {
+		previousRow, ok := previousRows[key.key]
+		if !ok {
+			diff.marks[i].added = true
+			continue
+		}
+
+		matched[key.key] = true
+		diff.marks[i].deltas = numericDeltas(current.Header, previousRow, key.row)
+	}
+
+	for _, key := range occurrenceKeys(previous.Rows, keys) {
+		if !matched[key.key] {
+			diff.removed = append(diff.removed, key.row)
+		}
+	}
+
+	return diff
+}
+
+// keyIndices returns the indices of the key columns in the header, or the first column when
+// none of them are in it
+func keyIndices(header []string, keyColumns []string) []int {
+	indices := []int{}
+	for _, name := range keyColumns {
+		if idx := columnIndex(header, name); idx >= 0 {
+			indices = append(indices, idx)
+		}
+	}
+
+	if len(indices) == 0 && len(header) > 0 {
+		indices = append(indices, 0)
+	}
+
+	return indices
+}
+
+// keyedRow is a row with the key it's matched by: the values of its key columns, and how
+// many rows before it have the same values
+type keyedRow struct {
+	key string
+	row TableRow
+}
+
+// occurrenceKeys keys the rows by the values of their key columns, numbering the rows that
+// have the same values so that each key is unique
+func occurrenceKeys(rows []TableRow, keys []int) []keyedRow {
+	seen := map[string]int{}
+	keyed := make([]keyedRow, len(rows))
+
+	for i, row := range rows {
+		values := make([]string, len(keys))
+		for j, idx := range keys {
+			if idx < len(row) {
+				values[j] = row[idx]
+			}
+		}
+
+		value := strings.Join(values, "\x00")
+		keyed[i] = keyedRow{key: fmt.Sprintf("%s\x00%d", value, seen[value]), row: row}
+		seen[value]++
+	}
+
+	return keyed
+}
+
+// numericDeltas returns how much the numeric cells that changed between two rows went up or
+// down, by column name
+func numericDeltas(header []string, previous, current TableRow) map[string]float64 {
+	var deltas map[string]float64
+
+	for col, name := range header {
+		if col >= len(previous) || col >= len(current) || previous[col] == current[col] {
+			continue
+		}
+
+		before, err := strconv.ParseFloat(strings.TrimSpace(previous[col]), 64)
+		if err != nil {
+			continue
+		}
+		after, err := strconv.ParseFloat(strings.TrimSpace(current[col]), 64)
+		if err != nil || after == before {
+			continue
+		}
+
+		if deltas == nil {
+			deltas = map[string]float64{}
+		}
+		deltas[name] = after - before
+	}
+
+	return deltas
+}
+
+// allMarks returns the marks of every row of the current table, or nil when there's no diff
+func (diff *tableDiff) allMarks() []rowMark {
+	if diff == nil {
+		return nil
+	}
+
+	return diff.marks
+}
+
+// marksFor returns the marks of the rows at the given indices of the current table
+func (diff *tableDiff) marksFor(indices []int) []rowMark {
+	if diff == nil {
+		return nil
+	}
+
+	marks := make([]rowMark, len(indices))
+	for i, idx := range indices {
+		if idx < len(diff.marks) {
+			marks[i] = diff.marks[idx]
+		}
+	}
+
+	return marks
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// formatDelta returns how much a cell went up or down, such as ▲12, with its digits grouped
+// the way numberFormat says
+func (widget *Widget) formatDelta(delta float64) string {
+	arrow := deltaUp
+	if delta < 0 {
+		arrow = deltaDown
+	}
+
+	text := strconv.FormatFloat(math.Abs(delta), 'f', -1, 64)
+	if widget.settings.NumberFormat == numberFormatThousands {
+		text = groupThousands(text)
+	}
+
+	return arrow + text
+}
+
+// withDeltas returns the rows with how much their changed cells went up or down after them,
+// such as "42 ▲12"
+func (widget *Widget) withDeltas(rows []TableRow, headers []string, marks []rowMark) []TableRow {
+	if len(marks) == 0 {
+		return rows
+	}
+
+	withDeltas := make([]TableRow, len(rows))
+	for r, row := range rows {
+		withDeltas[r] = row
+		if r >= len(marks) || len(marks[r].deltas) == 0 {
+			continue
+		}
+
+		withDeltas[r] = make(TableRow, len(row))
+		for col, cell := range row {
+			if col >= len(headers) {
+				break
+			}
+			if delta, ok := marks[r].deltas[headers[col]]; ok {
+				cell = fmt.Sprintf("%s %s", cell, widget.formatDelta(delta))
+			}
+			withDeltas[r][col] = cell
+		}
+	}
+
+	return withDeltas
+}
+
+// removedRows renders the rows that the last refresh removed, struck through, under the
+// table, in the same columns
+func (widget *Widget) removedRows(visible []int, colWidths []int) string {
+	if widget.diff == nil || !widget.settings.ShowRemovedRows || len(widget.diff.removed) == 0 {
+		return ""
+	}
+
+	removed := filterRows(widget.diff.removed, widget.filter)
+	if len(removed) == 0 {
+		return ""
+	}
+
+	table := projectTable(&TableResp{Header: widget.tableData.Header, Rows: removed}, visible)
+
+	text := widget.textTable(nil, table.Header, colWidths).
+		SetRowNumberWidth(0).
+		SetSelectedRow(-1, "").
+		SetCellFormatter(func(_, _ int, _, text string) string {
+			return fmt.Sprintf("[gray::s]%s[white::-]", text)
+		})
+	for _, row := range widget.displayRows(table.Rows, widget.numericHeaders(table.Header)) {
+		text.AddRow(row...)
+	}
+
+	gutter := strings.Repeat(" ", widget.gutterWidth())
+
+	sb := strings.Builder{}
+	for _, line := range strings.Split(strings.TrimSuffix(text.RenderRows(), "\n"), "\n") {
+		sb.WriteString(gutter + line + "\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,177 @@
+package azurelogs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func errorCounts(rows ...TableRow) *TableResp {
+	return &TableResp{
+		Header: []string{"ErrorType", "Service", "Count"},
+		Types:  []string{"string", "string", "long"},
+		Rows:   rows,
+	}
+}
+
+func TestDiffTables(t *testing.T) {
+	previous := errorCounts(
+		TableRow{"Timeout", "api", "120"},
+		TableRow{"NullReference", "api", "40"},
+		TableRow{"Deadlock", "db", "7"},
+	)
+	current := errorCounts(
+		TableRow{"Timeout", "api", "150"},
+		TableRow{"OutOfMemory", "worker", "3"},
+		TableRow{"NullReference", "api", "25"},
+	)
+
+	diff := diffTables(previous, current, nil)
+	require.NotNil(t, diff)
+
+	assert.Equal(t, []rowMark{
+		{deltas: map[string]float64{"Count": 30}},
+		{added: true},
+		{deltas: map[string]float64{"Count": -15}},
+	}, diff.marks)
+	assert.Equal(t, []TableRow{{"Deadlock", "db", "7"}}, diff.removed)
+}
+
+func TestDiffTables_KeyColumns(t *testing.T) {
+	previous := errorCounts(
+		TableRow{"Timeout", "api", "120"},
+		TableRow{"Timeout", "db", "4"},
+	)
+	current := errorCounts(
+		TableRow{"Timeout", "db", "9"},
+		TableRow{"Timeout", "worker", "1"},
+	)
+
+	diff := diffTables(previous, current, []string{"errortype", "Service", "Unknown"})
+	require.NotNil(t, diff)
+
+	assert.Equal(t, []rowMark{
+		{deltas: map[string]float64{"Count": 5}},
+		{added: true},
+	}, diff.marks)
+	assert.Equal(t, []TableRow{{"Timeout", "api", "120"}}, diff.removed)
+}
+
+func TestDiffTables_DuplicateKeys(t *testing.T) {
+	previous := errorCounts(
+		TableRow{"Timeout", "api", "10"},
+		TableRow{"Timeout", "api", "20"},
+		TableRow{"Timeout", "api", "30"},
+	)
+	current := errorCounts(
+		TableRow{"Timeout", "api", "10"},
+		TableRow{"Timeout", "api", "25"},
+	)
+
+	// Rows with the same key are matched in order, so the third one is gone
+	diff := diffTables(previous, current, []string{"ErrorType"})
+	require.NotNil(t, diff)
+
+	assert.Equal(t, []rowMark{
+		{},
+		{deltas: map[string]float64{"Count": 5}},
+	}, diff.marks)
+	assert.Equal(t, []TableRow{{"Timeout", "api", "30"}}, diff.removed)
+
+	// And the second duplicate is new
+	diff = diffTables(current, previous, []string{"ErrorType"})
+	require.NotNil(t, diff)
+
+	assert.Equal(t, []rowMark{
+		{},
+		{deltas: map[string]float64{"Count": -5}},
+		{added: true},
+	}, diff.marks)
+	assert.Empty(t, diff.removed)
+}
+
+func TestDiffTables_TextChanges(t *testing.T) {
+	diff := diffTables(
+		errorCounts(TableRow{"Timeout", "api", "n/a"}),
+		errorCounts(TableRow{"Timeout", "db", "12"}),
+		nil,
+	)
+	require.NotNil(t, diff)
+
+	// Only numbers have deltas
+	assert.Equal(t, []rowMark{{}}, diff.marks)
+}
+
+func TestDiffTables_None(t *testing.T) {
+	current := errorCounts(TableRow{"Timeout", "api", "1"})
+
+	assert.Nil(t, diffTables(nil, current, nil))
+	assert.Nil(t, diffTables(&TableResp{Header: []string{"Level"}}, current, nil))
+}
+
+func TestWidget_RenderTable_Diff(t *testing.T) {
+	widget := createTestWidget()
+	widget.settings.ShowDiff = true
+	widget.settings.ShowRemovedRows = true
+	widget.settings.NumberFormat = numberFormatThousands
+
+	previous := errorCounts(
+		TableRow{"Timeout", "api", "1200"},
+		TableRow{"Deadlock", "db", "7"},
+	)
+	widget.tableData = errorCounts(
+		TableRow{"Timeout", "api", "2500"},
+		TableRow{"OutOfMemory", "worker", "3"},
+	)
+	widget.diff = diffTables(previous, widget.tableData, nil)
+
+	_, content, _ := widget.renderTable("Test Title")
+	lines := strings.Split(content, "\n")
+
+	assert.Equal(t, "Timeout     ¦api      ¦[yellow]2,500 ▲1,300[white]", lines[2])
+	assert.Equal(t, "[green]OutOfMemory[white] ¦[green]worker  [white] ¦[green]           3[white]", lines[3])
+	assert.Equal(t, "[gray::s]Deadlock   [white::-] ¦[gray::s]db      [white::-] ¦[gray::s]           7[white::-]", lines[4])
+
+	// Without the removed rows
+	widget.settings.ShowRemovedRows = false
+	_, content, _ = widget.renderTable("Test Title")
+	assert.NotContains(t, content, "Deadlock")
+
+	// Filtered rows keep their marks
+	widget.filter = "worker"
+	_, content, _ = widget.renderTable("Test Title")
+	lines = strings.Split(content, "\n")
+	assert.Equal(t, "[green]OutOfMemory[white] ¦[green][::r]worker[::-]  [white] ¦[green]           3[white]", lines[3])
+}
+
+func TestWidget_Refresh_Diff(t *testing.T) {
+	widget := createWorkspaceWidget(t, func(_ *Session, _ string) (string, error) { return "", nil })
+	widget.settings.ShowWorkspaceName = false
+	widget.settings.ShowDiff = true
+
+	results := []*TableResp{
+		errorCounts(TableRow{"Timeout", "api", "10"}),
+		errorCounts(TableRow{"Timeout", "api", "12"}, TableRow{"Deadlock", "db", "1"}),
+		errorCounts(TableRow{"Timeout", "api", "12"}),
+	}
+	widget.runQuery = func(_ *Session, _ ProgressFunc) (*TableResp, error) {
+		result := results[0]
+		results = results[1:]
+		return result, nil
+	}
+
+	// Nothing to compare the first result to
+	refreshAndWait(t, widget)
+	assert.Nil(t, widget.diff)
+
+	refreshAndWait(t, widget)
+	require.NotNil(t, widget.diff)
+	assert.Equal(t, []rowMark{{deltas: map[string]float64{"Count": 2}}, {added: true}}, widget.diff.marks)
+
+	// Removed rows are only shown for one refresh
+	refreshAndWait(t, widget)
+	assert.Equal(t, []TableRow{{"Deadlock", "db", "1"}}, widget.diff.removed)
+	assert.Equal(t, []rowMark{{}}, widget.diff.marks)
+}
@@ -0,0 +1,57 @@
+package azurelogs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+)
+
+// displayed strips the tview tags from rendered content, leaving the text as it appears on
+// screen, by running it through a TextView
+func displayed(content string) string {
+	view := tview.NewTextView()
+	view.SetDynamicColors(true)
+	view.SetText(content)
+
+	return view.GetText(true)
+}
+
+func TestWidget_FormatTableRows_EscapesTags(t *testing.T) {
+	tests := []struct {
+		name string
+		cell string
+	}{
+		{name: "color tag", cell: "[red]boom"},
+		{name: "empty brackets", cell: "list []"},
+		{name: "nested brackets", cell: "[[ERROR]] x"},
+		{name: "json array", cell: `["a","b"]`},
+		{name: "region-like tag", cell: `["1"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			widget := createTestWidget()
+
+			var sb strings.Builder
+			widget.formatTableRows(&sb, []TableRow{{tt.cell, "next"}}, nil, []string{"Message", "Other"}, []int{12, 8})
+
+			text := displayed(sb.String())
+			assert.Contains(t, text, tt.cell)
+
+			// The padding is computed on the raw text, so the next column stays aligned
+			assert.Equal(t, 12+len(" ¦"), strings.Index(text, "next"))
+		})
+	}
+}
+
+func TestWidget_FormatTableHeaders_EscapesTags(t *testing.T) {
+	widget := createTestWidget()
+
+	var sb strings.Builder
+	widget.formatTableHeaders(&sb, []string{"[yellow]", "B"}, []int{10, 8})
+
+	assert.Contains(t, sb.String(), "[lightblue]")
+	assert.Contains(t, displayed(sb.String()), "[yellow]")
+}
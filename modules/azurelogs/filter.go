@@ -0,0 +1,130 @@
+package azurelogs
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rivo/tview"
+
+	"golang.org/x/text/cases"
+)
+
+const (
+	filterHighlight   = "[::r]"
+	filterUnhighlight = "[::-]"
+)
+
+// filterRows returns the rows that contain the filter text in any column, ignoring case
+func filterRows(rows []TableRow, filter string) []TableRow {
+	if filter == "" {
+		return rows
+	}
+
+	matches := []TableRow{}
+	for _, idx := range filterRowIndices(rows, filter) {
+		matches = append(matches, rows[idx])
+	}
+
+	return matches
+}
+
+// filterRowIndices returns the indices of the rows that contain the filter text in any
+// column, ignoring case
+func filterRowIndices(rows []TableRow, filter string) []int {
+	matches := []int{}
+	if filter == "" {
+		for idx := range rows {
+			matches = append(matches, idx)
+		}
+		return matches
+	}
+
+	// Full unicode case folding, so that e.g. "STRASSE" matches "straße"
+	folder := cases.Fold()
+	needle := folder.String(filter)
+
+	for idx, row := range rows {
+		for _, cell := range row {
+			if strings.Contains(folder.String(cell), needle) {
+				matches = append(matches, idx)
+				break
+			}
+		}
+	}
+
+	return matches
+}
+
+// filterIndicator describes how many of the cached rows match the active filter
+func filterIndicator(filter string, matched, total int) string {
+	return fmt.Sprintf("[yellow]Filter: %s (%d/%d rows match)[white]\n", tview.Escape(filter), matched, total)
+}
+
+// highlightMatch wraps every case-insensitive occurrence of filter in text with the highlight
+// color. Padding must be applied to text beforehand, as the tags don't take up any width
+func highlightMatch(text, filter string) string {
+	if filter == "" {
+		return text
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(text); {
+		end := matchEnd(text, i, filter)
+		if end < 0 {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			sb.WriteString(text[i : i+size])
+			i += size
+			continue
+		}
+
+		sb.WriteString(filterHighlight + text[i:end] + filterUnhighlight)
+		i = end
+	}
+
+	return sb.String()
+}
+
+// matchEnd returns the end of the shortest prefix of text[start:] equal to filter under
+// case folding, or -1 if there is none
+func matchEnd(text string, start int, filter string) int {
+	for end := start + 1; end <= len(text); end++ {
+		if !strings.EqualFold(text[start:end], filter) {
+			continue
+		}
+		return end
+	}
+
+	return -1
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// clearFilter removes the active row filter
+func (widget *Widget) clearFilter() {
+	widget.update(func() bool {
+		if widget.filter == "" {
+			return false
+		}
+
+		widget.filter = ""
+		return true
+	})
+}
+
+// setFilter applies a row filter, which persists across refreshes until cleared
+func (widget *Widget) setFilter(filter string) {
+	widget.update(func() bool {
+		widget.filter = strings.TrimSpace(filter)
+		return true
+	})
+}
+
+// showFilterPrompt opens an input field to type the row filter into. Escape clears the filter
+func (widget *Widget) showFilterPrompt() {
+	widget.mu.Lock()
+	filter := widget.filter
+	widget.mu.Unlock()
+
+	widget.showPrompt("Filter: ", filter, widget.setFilter, func() { widget.setFilter("") })
+}
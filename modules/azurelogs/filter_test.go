@@ -0,0 +1,69 @@
+package azurelogs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterRows(t *testing.T) {
+	rows := []TableRow{
+		{"web-01", "Error", "Disk FULL on /var"},
+		{"web-02", "Info", "Straße closed"},
+		{"db-01", "Warning", "ΣΊΣΥΦΟΣ restarted"},
+	}
+
+	tests := []struct {
+		name     string
+		filter   string
+		expected int
+	}{
+		{name: "empty filter keeps everything", filter: "", expected: 3},
+		{name: "case insensitive", filter: "disk full", expected: 1},
+		{name: "matches any column", filter: "web", expected: 2},
+		{name: "unicode folding", filter: "STRASSE", expected: 1},
+		{name: "greek final sigma", filter: "σίσυφος", expected: 1},
+		{name: "no match", filter: "timeout", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, filterRows(rows, tt.filter), tt.expected)
+		})
+	}
+}
+
+func TestFilterIndicator(t *testing.T) {
+	assert.Equal(t, "[yellow]Filter: err (12/143 rows match)[white]\n", filterIndicator("err", 12, 143))
+}
+
+func TestHighlightMatch(t *testing.T) {
+	assert.Equal(t, "a [::r]Err[::-]or and [::r]err[::-] ", highlightMatch("a Error and err ", "err"))
+	assert.Equal(t, "no match", highlightMatch("no match", "xyz"))
+	assert.Equal(t, "unchanged", highlightMatch("unchanged", ""))
+}
+
+func TestWidget_RenderTable_Filter(t *testing.T) {
+	widget := createTestWidget()
+	widget.filter = "error"
+
+	// More rows than can be displayed, to make sure filtering uses the full table
+	rows := make([]TableRow, maxDisplayRows+10)
+	for i := range rows {
+		rows[i] = TableRow{"Info", "ok"}
+	}
+	rows[len(rows)-1] = TableRow{"Error", "disk full"}
+	widget.tableData = &TableResp{Header: []string{"Level", "Message"}, Rows: rows}
+
+	_, content, _ := widget.renderTable("Test Title")
+
+	assert.Contains(t, content, "(1/60 rows match)")
+	assert.Contains(t, content, "[::r]Error[::-]")
+	assert.NotContains(t, content, "more rows truncated")
+	assert.Equal(t, 0, strings.Count(content, "Info"))
+
+	widget.filter = "timeout"
+	_, content, _ = widget.renderTable("Test Title")
+	assert.Contains(t, content, "No rows match the filter")
+}
@@ -0,0 +1,25 @@
+package azurelogs
+
+import "github.com/gdamore/tcell/v2"
+
+func (widget *Widget) initializeKeyboardControls() {
+	widget.InitializeHelpTextKeyboardControl(widget.ShowHelp)
+	widget.InitializeRefreshKeyboardControl(widget.Refresh)
+
+	widget.SetKeyboardChar("j", widget.next, "Select next row")
+	widget.SetKeyboardChar("k", widget.prev, "Select previous row")
+	widget.SetKeyboardChar("h", widget.moveColumnLeft, "Move selected column left")
+	widget.SetKeyboardChar("l", widget.moveColumnRight, "Move selected column right")
+	widget.SetKeyboardChar("x", widget.hideColumn, "Hide selected column")
+	widget.SetKeyboardChar("X", widget.unhideColumns, "Unhide all columns")
+	widget.SetKeyboardChar("f", widget.showFilterPrompt, "Filter rows")
+	widget.SetKeyboardChar("y", widget.copySelectedRow, "Copy selected row to the clipboard")
+	widget.SetKeyboardChar("c", widget.copySelectedCell, "Copy a cell of the selected row to the clipboard")
+	widget.SetKeyboardChar("o", widget.openInPortal, "Open the query in the Azure Portal")
+
+	widget.SetKeyboardKey(tcell.KeyDown, widget.next, "Select next row")
+	widget.SetKeyboardKey(tcell.KeyUp, widget.prev, "Select previous row")
+	widget.SetKeyboardKey(tcell.KeyLeft, widget.prevColumn, "Select previous column")
+	widget.SetKeyboardKey(tcell.KeyRight, widget.nextColumn, "Select next column")
+	widget.SetKeyboardKey(tcell.KeyEsc, widget.clearFilter, "Clear filter")
+}
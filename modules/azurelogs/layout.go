@@ -0,0 +1,241 @@
+package azurelogs
+
+import "fmt"
+
+// columnLayout remembers, by column name, the order and visibility of the table columns so
+// that they can be reapplied to every refreshed table
+type columnLayout struct {
+	order  []string
+	hidden map[string]bool
+}
+
+func newColumnLayout() *columnLayout {
+	return &columnLayout{
+		order:  []string{},
+		hidden: map[string]bool{},
+	}
+}
+
+/* -------------------- Layout Functions -------------------- */
+
+// apply returns the indices of the visible headers, in display order
+func (layout *columnLayout) apply(headers []string) []int {
+	layout.merge(headers)
+
+	positions := make(map[string]int, len(headers))
+	for i, header := range headers {
+		positions[header] = i
+	}
+
+	indices := []int{}
+	for _, name := range layout.order {
+		idx, ok := positions[name]
+		if !ok || layout.hidden[name] {
+			continue
+		}
+		indices = append(indices, idx)
+	}
+
+	return indices
+}
+
+// hiddenCount returns how many of the given headers are hidden
+func (layout *columnLayout) hiddenCount(headers []string) int {
+	count := 0
+	for _, header := range headers {
+		if layout.hidden[header] {
+			count++
+		}
+	}
+
+	return count
+}
+
+// hide hides the named column
+func (layout *columnLayout) hide(name string) {
+	layout.hidden[name] = true
+}
+
+// merge adds headers the layout doesn't know about yet (new or renamed columns), placing
+// each one right after the header that precedes it in the table. Columns that disappeared
+// are kept, so their position is restored if they come back
+func (layout *columnLayout) merge(headers []string) {
+	prev := -1
+	for _, header := range headers {
+		if pos := layout.position(header); pos >= 0 {
+			prev = pos
+			continue
+		}
+
+		prev++
+		layout.order = append(layout.order[:prev], append([]string{header}, layout.order[prev:]...)...)
+	}
+}
+
+// move shifts the named column by delta positions among the visible columns of headers
+func (layout *columnLayout) move(headers []string, name string, delta int) {
+	visible := layout.apply(headers)
+
+	names := make([]string, len(visible))
+	current := -1
+	for i, idx := range visible {
+		names[i] = headers[idx]
+		if headers[idx] == name {
+			current = i
+		}
+	}
+
+	target := current + delta
+	if current < 0 || target < 0 || target >= len(names) {
+		return
+	}
+
+	// Take the column out, then put it back before (moving left) or after (moving right)
+	// the column it is moving past
+	from := layout.position(name)
+	layout.order = append(layout.order[:from], layout.order[from+1:]...)
+
+	to := layout.position(names[target])
+	if delta > 0 {
+		to++
+	}
+	layout.order = append(layout.order[:to], append([]string{name}, layout.order[to:]...)...)
+}
+
+// position returns the index of the named column in the layout order, or -1
+func (layout *columnLayout) position(name string) int {
+	for i, existing := range layout.order {
+		if existing == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// unhideAll makes every column visible again
+func (layout *columnLayout) unhideAll() {
+	layout.hidden = map[string]bool{}
+}
+
+// projectTable returns a copy of the table containing only the given columns, in order
+func projectTable(tr *TableResp, indices []int) *TableResp {
+	projected := &TableResp{
+		Header: make([]string, len(indices)),
+		Rows:   make([]TableRow, len(tr.Rows)),
+	}
+
+	for i, idx := range indices {
+		projected.Header[i] = tr.Header[idx]
+		if idx < len(tr.Types) {
+			projected.Types = append(projected.Types, tr.Types[idx])
+		}
+	}
+
+	for r, row := range tr.Rows {
+		projectedRow := make(TableRow, len(indices))
+		for i, idx := range indices {
+			if idx < len(row) {
+				projectedRow[i] = row[idx]
+			}
+		}
+		projected.Rows[r] = projectedRow
+	}
+
+	return projected
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// hiddenMarker describes how many columns are hidden, for the header line. Callers must
+// hold mu
+func (widget *Widget) hiddenMarker() string {
+	if widget.tableData == nil {
+		return ""
+	}
+
+	count := widget.layout.hiddenCount(widget.tableData.Header)
+	if count == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" [dim](%d hidden)[white]", count)
+}
+
+// selectedColumnName returns the name of the selected visible column, or "" if none is.
+// Callers must hold mu
+func (widget *Widget) selectedColumnName() string {
+	if widget.tableData == nil {
+		return ""
+	}
+
+	visible := widget.layout.apply(widget.tableData.Header)
+	if widget.selectedCol < 0 || widget.selectedCol >= len(visible) {
+		return ""
+	}
+
+	return widget.tableData.Header[visible[widget.selectedCol]]
+}
+
+func (widget *Widget) nextColumn() {
+	widget.update(func() bool { return widget.selectColumn(widget.selectedCol + 1) })
+}
+
+func (widget *Widget) prevColumn() {
+	widget.update(func() bool { return widget.selectColumn(widget.selectedCol - 1) })
+}
+
+// selectColumn selects the visible column at idx, clamped to the available columns. It
+// returns false when there is no table to select a column of. Callers must hold mu
+func (widget *Widget) selectColumn(idx int) bool {
+	if widget.tableData == nil {
+		return false
+	}
+
+	count := len(widget.layout.apply(widget.tableData.Header))
+	widget.selectedCol = max(0, min(idx, count-1))
+
+	return true
+}
+
+// moveColumn moves the selected column by delta positions and keeps it selected
+func (widget *Widget) moveColumn(delta int) {
+	widget.update(func() bool {
+		name := widget.selectedColumnName()
+		if name == "" {
+			return false
+		}
+
+		widget.layout.move(widget.tableData.Header, name, delta)
+		return widget.selectColumn(widget.selectedCol + delta)
+	})
+}
+
+func (widget *Widget) moveColumnLeft() {
+	widget.moveColumn(-1)
+}
+
+func (widget *Widget) moveColumnRight() {
+	widget.moveColumn(1)
+}
+
+// hideColumn hides the selected column
+func (widget *Widget) hideColumn() {
+	widget.update(func() bool {
+		name := widget.selectedColumnName()
+		if name == "" {
+			return false
+		}
+
+		widget.layout.hide(name)
+		return widget.selectColumn(widget.selectedCol)
+	})
+}
+
+// unhideColumns makes every hidden column visible again
+func (widget *Widget) unhideColumns() {
+	widget.update(func() bool {
+		widget.layout.unhideAll()
+		return true
+	})
+}
@@ -0,0 +1,92 @@
+package azurelogs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func visibleHeaders(layout *columnLayout, headers []string) []string {
+	names := []string{}
+	for _, idx := range layout.apply(headers) {
+		names = append(names, headers[idx])
+	}
+	return names
+}
+
+func TestColumnLayout_MoveAndHide(t *testing.T) {
+	headers := []string{"TimeGenerated", "Level", "Computer", "Message"}
+	layout := newColumnLayout()
+
+	assert.Equal(t, headers, visibleHeaders(layout, headers))
+
+	layout.move(headers, "Message", -1)
+	assert.Equal(t, []string{"TimeGenerated", "Level", "Message", "Computer"}, visibleHeaders(layout, headers))
+
+	layout.hide("Level")
+	assert.Equal(t, []string{"TimeGenerated", "Message", "Computer"}, visibleHeaders(layout, headers))
+	assert.Equal(t, 1, layout.hiddenCount(headers))
+
+	// Moving skips over hidden columns
+	layout.move(headers, "Message", -1)
+	assert.Equal(t, []string{"Message", "TimeGenerated", "Computer"}, visibleHeaders(layout, headers))
+
+	// Moving past the edge does nothing
+	layout.move(headers, "Message", -1)
+	assert.Equal(t, []string{"Message", "TimeGenerated", "Computer"}, visibleHeaders(layout, headers))
+
+	layout.unhideAll()
+	assert.Equal(t, 0, layout.hiddenCount(headers))
+	assert.Len(t, visibleHeaders(layout, headers), 4)
+}
+
+func TestColumnLayout_RefreshedTableWithRenamedColumn(t *testing.T) {
+	layout := newColumnLayout()
+
+	original := []string{"TimeGenerated", "Level", "Message", "Computer"}
+	layout.move(original, "Computer", -3)
+	layout.hide("Level")
+	assert.Equal(t, []string{"Computer", "TimeGenerated", "Message"}, visibleHeaders(layout, original))
+
+	// After a refresh the query renamed Message to Msg: the rest of the layout is reapplied
+	// by name and the renamed column keeps its place after the column that precedes it
+	refreshed := []string{"TimeGenerated", "Level", "Msg", "Computer"}
+	assert.Equal(t, []string{"Computer", "TimeGenerated", "Msg"}, visibleHeaders(layout, refreshed))
+	assert.Equal(t, 1, layout.hiddenCount(refreshed))
+
+	// The original column name comes back in its remembered place
+	assert.Equal(t, []string{"Computer", "TimeGenerated", "Message"}, visibleHeaders(layout, original))
+}
+
+func TestProjectTable(t *testing.T) {
+	tr := &TableResp{
+		Header: []string{"A", "B", "C"},
+		Rows:   []TableRow{{"1", "2", "3"}, {"4"}},
+	}
+
+	projected := projectTable(tr, []int{2, 0})
+
+	assert.Equal(t, []string{"C", "A"}, projected.Header)
+	assert.Equal(t, []TableRow{{"3", "1"}, {"", "4"}}, projected.Rows)
+}
+
+func TestWidget_RenderTable_HiddenColumns(t *testing.T) {
+	widget := createTestWidget()
+	drainRedraws(t, widget)
+	widget.tableData = &TableResp{
+		Header: []string{"Level", "Computer", "Message"},
+		Rows:   []TableRow{{"Error", "web-01", "disk full"}},
+	}
+
+	widget.selectColumn(1)
+	widget.hideColumn()
+	widget.selectColumn(0)
+	widget.hideColumn()
+
+	_, content, _ := widget.renderTable("Test Title")
+
+	assert.Contains(t, content, "(2 hidden)")
+	assert.Contains(t, content, "disk full")
+	assert.NotContains(t, content, "web-01")
+	assert.NotContains(t, content, "Error")
+}
@@ -0,0 +1,97 @@
+package azurelogs
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/monitor/azquery"
+)
+
+const (
+	numberFormatPlain     = "plain"
+	numberFormatThousands = "thousands"
+)
+
+// numericColumnTypes are the column types whose cells are right-aligned
+var numericColumnTypes = map[string]bool{
+	string(azquery.LogsColumnTypeDecimal): true,
+	string(azquery.LogsColumnTypeInt):     true,
+	string(azquery.LogsColumnTypeLong):    true,
+	string(azquery.LogsColumnTypeReal):    true,
+}
+
+// plainNumberPattern matches the numbers thousands separators are added to: an optional sign,
+// digits, and an optional fraction
+var plainNumberPattern = regexp.MustCompile(`^([-+]?)(\d+)(\.\d+)?$`)
+
+/* -------------------- Unexported Functions -------------------- */
+
+// groupThousands separates the thousands of a number with commas, keeping its sign and its
+// fraction as they are. The digits are grouped as text, so large longs keep every digit.
+// Anything that isn't a plain number is returned as it is
+//
+// Example:
+//
+//	x := groupThousands("-1234567.25")
+//	> "-1,234,567.25"
+func groupThousands(text string) string {
+	match := plainNumberPattern.FindStringSubmatch(text)
+	if match == nil {
+		return text
+	}
+
+	sign, digits, fraction := match[1], match[2], match[3]
+
+	sb := strings.Builder{}
+	sb.WriteString(sign)
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(digit)
+	}
+	sb.WriteString(fraction)
+
+	return sb.String()
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// numericHeaders returns which of the headers are of numeric columns, by the type the
+// response gave the column of that name
+func (widget *Widget) numericHeaders(headers []string) []bool {
+	numeric := make([]bool, len(headers))
+	if widget.tableData == nil {
+		return numeric
+	}
+
+	for i, header := range headers {
+		idx := columnIndex(widget.tableData.Header, header)
+		numeric[i] = idx >= 0 && idx < len(widget.tableData.Types) && numericColumnTypes[widget.tableData.Types[idx]]
+	}
+
+	return numeric
+}
+
+// displayRows returns the rows with the numbers of their numeric columns formatted the way
+// numberFormat says. The rows themselves are left as they are, so that filters, alerts and
+// copies still see the values the query returned
+func (widget *Widget) displayRows(rows []TableRow, numeric []bool) []TableRow {
+	if widget.settings.NumberFormat != numberFormatThousands {
+		return rows
+	}
+
+	formatted := make([]TableRow, len(rows))
+	for r, row := range rows {
+		formattedRow := make(TableRow, len(row))
+		for col, cell := range row {
+			if col < len(numeric) && numeric[col] {
+				cell = groupThousands(cell)
+			}
+			formattedRow[col] = cell
+		}
+		formatted[r] = formattedRow
+	}
+
+	return formatted
+}
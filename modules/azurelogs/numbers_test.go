@@ -0,0 +1,120 @@
+package azurelogs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/monitor/azquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupThousands(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{text: "0", expected: "0"},
+		{text: "999", expected: "999"},
+		{text: "1000", expected: "1,000"},
+		{text: "1234567", expected: "1,234,567"},
+		{text: "9223372036854775807", expected: "9,223,372,036,854,775,807"},
+		{text: "-1234", expected: "-1,234"},
+		{text: "-999", expected: "-999"},
+		{text: "+12345", expected: "+12,345"},
+		{text: "-1234567.125", expected: "-1,234,567.125"},
+		{text: "0.5", expected: "0.5"},
+		{text: "", expected: ""},
+		{text: "1e+06", expected: "1e+06"},
+		{text: "NaN", expected: "NaN"},
+		{text: "12 ms", expected: "12 ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.expected, groupThousands(tt.text))
+		})
+	}
+}
+
+func TestExecuteQuery_ColumnTypes(t *testing.T) {
+	client := newFakeLogsClient([]string{"Computer", "Count"}, azquery.Row{"web-01", float64(42)})
+	long := azquery.LogsColumnTypeLong
+	client.results.Tables[0].Columns[1].Type = &long
+
+	result, err := executeQuery(client, QueryFile{WorkspaceID: "ws", Columns: []string{"Computer", "Count"}}, "Heartbeat", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"", "long"}, result.Types)
+}
+
+// numericTable is a table of text and numeric columns, typed the way a response types them
+func numericTable() *TableResp {
+	return &TableResp{
+		Header: []string{"Computer", "Count", "Latency", "Status"},
+		Types:  []string{"string", "long", "real", "string"},
+		Rows: []TableRow{
+			{"web-01", "1234567", "12.5", "200"},
+			{"web-02", "-42", "0.75", "500"},
+			{"[web-03]", "", "3", "n/a"},
+		},
+	}
+}
+
+func TestWidget_RenderTable_NumericAlignment(t *testing.T) {
+	widget := createTestWidget()
+	widget.tableData = numericTable()
+
+	_, content, _ := widget.renderTable("Test Title")
+
+	lines := strings.Split(content, "\n")
+	assert.Equal(t, "[lightblue]Computer[white] ¦[lightblue]   Count[white] ¦[lightblue] Latency[white] ¦[lightblue]Status  [white]", lines[0])
+	assert.Equal(t, "web-01   ¦ 1234567 ¦    12.5 ¦200     ", lines[2])
+	assert.Equal(t, "web-02   ¦     -42 ¦    0.75 ¦500     ", lines[3])
+	// Text columns stay left-aligned, even when they hold numbers
+	assert.Equal(t, "[web-03[] ¦         ¦       3 ¦n/a     ", lines[4])
+}
+
+func TestWidget_RenderTable_Thousands(t *testing.T) {
+	widget := createTestWidget()
+	widget.settings.NumberFormat = numberFormatThousands
+	widget.tableData = numericTable()
+	widget.tableData.Rows[0][1] = "123456789012"
+
+	_, content, _ := widget.renderTable("Test Title")
+
+	lines := strings.Split(content, "\n")
+	// The column is sized to the separated numbers
+	assert.Equal(t, "web-01   ¦123,456,789,012 ¦    12.5 ¦200     ", lines[2])
+	assert.Equal(t, "web-02   ¦            -42 ¦    0.75 ¦500     ", lines[3])
+
+	// The data itself keeps the numbers as the query returned them
+	assert.Equal(t, "123456789012", widget.tableData.Rows[0][1])
+}
+
+func TestWidget_RenderTable_NumericTruncation(t *testing.T) {
+	widget := createTestWidget()
+	widget.settings.NumberFormat = numberFormatThousands
+	widget.tableData = numericTable()
+	widget.tableData.Rows[0][1] = "123456789012345678901234567890"
+
+	var sb strings.Builder
+	widget.formatTableRows(&sb, widget.tableData.Rows[:2], nil, widget.tableData.Header, []int{8, 10, 8, 8})
+
+	lines := strings.Split(sb.String(), "\n")
+	assert.Equal(t, "web-01   ¦123,456... ¦    12.5 ¦200     ", lines[0])
+	assert.Equal(t, "web-02   ¦       -42 ¦    0.75 ¦500     ", lines[1])
+}
+
+func TestWidget_RenderTable_ThousandsAlert(t *testing.T) {
+	widget := createTestWidget()
+	widget.settings.NumberFormat = numberFormatThousands
+	widget.tableData = numericTable()
+	widget.alert = &AlertResult{Column: "Count", Color: "red", Threshold: AlertThreshold{Operator: ">", Value: "1000"}}
+
+	_, content, _ := widget.renderTable("Test Title")
+
+	// The alert compares the number the query returned, not the separated one
+	assert.Contains(t, content, "[red]1,234,567[white]")
+	assert.NotContains(t, content, "[red]     -42[white]")
+}
@@ -0,0 +1,112 @@
+package azurelogs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// portalLogsBlade is where the Azure Portal opens a query shared as a link
+const portalLogsBlade = "https://portal.azure.com/#blade/Microsoft_Azure_Monitoring_Logs/LogsBlade"
+
+// workspaceResourceResolver looks up the Azure resource ID of a Log Analytics workspace by
+// its ID, which the Azure Portal needs to open queries of the workspace
+type workspaceResourceResolver func(sess *Session, workspaceID string) (string, error)
+
+/* -------------------- Unexported Functions -------------------- */
+
+// portalLink returns the link that opens the query, run against the resource with the given
+// Azure resource ID, in the Logs of the Azure Portal. The portal takes the query gzipped,
+// then base64 encoded
+//
+// Example:
+//
+//	x, _ := portalLink("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.OperationalInsights/workspaces/logs", "Heartbeat")
+//	> "https://portal.azure.com/#blade/Microsoft_Azure_Monitoring_Logs/LogsBlade/resourceId/%2Fsubscriptions%2Fsub...
+//	   /source/LogsBlade.AnalyticsShareLinkToQuery/q/H4sIAAAAAAAA..."
+func portalLink(resourceID, query string) (string, error) {
+	if !strings.HasPrefix(resourceID, "/subscriptions/") {
+		return "", fmt.Errorf("invalid Azure resource ID %q", resourceID)
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+
+	_, err := writer.Write([]byte(query))
+	if err != nil {
+		return "", err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		"%s/resourceId/%s/source/LogsBlade.AnalyticsShareLinkToQuery/q/%s",
+		portalLogsBlade,
+		url.QueryEscape(resourceID),
+		url.QueryEscape(base64.StdEncoding.EncodeToString(compressed.Bytes())),
+	), nil
+}
+
+// portalResourceID returns the Azure resource ID the query of the session runs against: the
+// resource it's scoped to, or its workspace, looked up with resolve
+func portalResourceID(sess *Session, resolve workspaceResourceResolver) (string, error) {
+	if sess.QueryFile.ResourceID != "" {
+		return sess.QueryFile.ResourceID, nil
+	}
+
+	resourceID, err := resolve(sess, sess.QueryFile.WorkspaceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up workspace %s: %w", sess.QueryFile.WorkspaceID, err)
+	}
+
+	return resourceID, nil
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// openInPortal opens the query of the widget in the Logs of the Azure Portal, in the
+// browser. Workspaces are looked up in the background, as the portal needs their resource ID,
+// so the session is taken before, and the outcome is reported in a toast, which redraws
+func (widget *Widget) openInPortal() {
+	widget.mu.Lock()
+	sess := widget.sess
+	widget.mu.Unlock()
+
+	if sess == nil {
+		widget.setToast("[red]No query loaded yet[white]")
+		return
+	}
+
+	go func() {
+		link, err := widget.queryPortalLink(sess)
+		if err != nil {
+			widget.setToast(fmt.Sprintf("[red]Cannot open the query: %v[white]", err))
+			return
+		}
+
+		widget.openURL(link)
+		widget.setToast("[green]Opened the query in the Azure Portal[white]")
+	}()
+}
+
+// queryPortalLink returns the link that opens the expanded query of the session in the
+// Azure Portal
+func (widget *Widget) queryPortalLink(sess *Session) (string, error) {
+	query, err := expandQuery(sess.QueryFile)
+	if err != nil {
+		return "", err
+	}
+
+	resourceID, err := portalResourceID(sess, widget.resolveWorkspace)
+	if err != nil {
+		return "", err
+	}
+
+	return portalLink(resourceID, query)
+}
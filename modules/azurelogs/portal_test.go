@@ -0,0 +1,120 @@
+package azurelogs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWorkspaceResourceID = "/subscriptions/sub/resourceGroups/rg-logs/providers/Microsoft.OperationalInsights/workspaces/prod-la-weu"
+
+// decodePortalQuery reads the query back out of a portal link
+func decodePortalQuery(t *testing.T, link string) string {
+	t.Helper()
+
+	_, encoded, found := strings.Cut(link, "/q/")
+	require.True(t, found)
+
+	unescaped, err := url.QueryUnescape(encoded)
+	require.NoError(t, err)
+
+	compressed, err := base64.StdEncoding.DecodeString(unescaped)
+	require.NoError(t, err)
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+
+	query, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	return string(query)
+}
+
+func TestPortalLink(t *testing.T) {
+	query := "AppTraces\n| where Message has \"timeout\" and SeverityLevel >= 3\n| where Url contains \"/api?id=1&x=ü+%20\"\n| summarize count() by bin(TimeGenerated, 5m)"
+
+	link, err := portalLink(testWorkspaceResourceID, query)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(link, "https://portal.azure.com/#blade/Microsoft_Azure_Monitoring_Logs/LogsBlade/resourceId/"+
+		"%2Fsubscriptions%2Fsub%2FresourceGroups%2Frg-logs%2Fproviders%2FMicrosoft.OperationalInsights%2Fworkspaces%2Fprod-la-weu"+
+		"/source/LogsBlade.AnalyticsShareLinkToQuery/q/"), link)
+	assert.Equal(t, query, decodePortalQuery(t, link))
+
+	// Nothing in the encoded query breaks the link apart
+	_, encoded, _ := strings.Cut(link, "/q/")
+	assert.NotContains(t, encoded, "/")
+	assert.NotContains(t, encoded, "+")
+	assert.NotContains(t, encoded, "=")
+
+	_, err = portalLink("prod-la-weu", query)
+	assert.ErrorContains(t, err, `invalid Azure resource ID "prod-la-weu"`)
+}
+
+func TestPortalResourceID(t *testing.T) {
+	resolve := func(_ *Session, workspaceID string) (string, error) {
+		if workspaceID == testWorkspaceID {
+			return testWorkspaceResourceID, nil
+		}
+		return "", errors.New("workspace not found")
+	}
+
+	vmID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm-01"
+
+	resourceID, err := portalResourceID(&Session{QueryFile: QueryFile{ResourceID: vmID}}, resolve)
+	require.NoError(t, err)
+	assert.Equal(t, vmID, resourceID)
+
+	resourceID, err = portalResourceID(&Session{QueryFile: QueryFile{WorkspaceID: testWorkspaceID}}, resolve)
+	require.NoError(t, err)
+	assert.Equal(t, testWorkspaceResourceID, resourceID)
+
+	_, err = portalResourceID(&Session{QueryFile: QueryFile{WorkspaceID: "other"}}, resolve)
+	assert.EqualError(t, err, "failed to look up workspace other: workspace not found")
+}
+
+func TestWidget_OpenInPortal(t *testing.T) {
+	widget := createWorkspaceWidget(t, func(_ *Session, _ string) (string, error) { return "", nil })
+	widget.initSession = func(_ string, _ ProgressFunc) (*Session, error) {
+		return &Session{QueryFile: QueryFile{
+			WorkspaceID: testWorkspaceID,
+			Query:       "Heartbeat\n| where Computer == \"{{ .host }}\"",
+			Params:      map[string]string{"host": "web-01"},
+		}}, nil
+	}
+	widget.resolveWorkspace = func(_ *Session, _ string) (string, error) { return testWorkspaceResourceID, nil }
+
+	opened := make(chan string, 1)
+	widget.openURL = func(link string) { opened <- link }
+
+	// Nothing is opened before a query is loaded
+	widget.openInPortal()
+	assert.Equal(t, "[red]No query loaded yet[white]", widget.toast)
+
+	refreshAndWait(t, widget)
+	widget.openInPortal()
+
+	select {
+	case link := <-opened:
+		assert.Contains(t, link, "/resourceId/%2Fsubscriptions%2Fsub%2F")
+		assert.Equal(t, "Heartbeat\n| where Computer == \"web-01\"", decodePortalQuery(t, link))
+	case <-time.After(time.Second):
+		t.Fatal("the query wasn't opened")
+	}
+
+	assert.Eventually(t, func() bool {
+		widget.mu.Lock()
+		defer widget.mu.Unlock()
+
+		return widget.toast == "[green]Opened the query in the Azure Portal[white]"
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,119 @@
+package azurelogs
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wtfutil/wtf/view"
+)
+
+// FetchStage identifies a step of loading the widget data
+type FetchStage int
+
+const (
+	StageInit FetchStage = iota
+	StageQuery
+	StageProcess
+)
+
+const loadingMessage = "Loading Azure Logs data..."
+
+var (
+	stageLabels    = []string{"Initializing Azure session", "Executing query", "Processing results"}
+	stageCompleted = "✔"
+)
+
+// ProgressFunc is called when a fetch moves on to a new stage. It may be nil
+type ProgressFunc func(stage FetchStage)
+
+// report calls the progress function, if there is one
+func (fn ProgressFunc) report(stage FetchStage) {
+	if fn != nil {
+		fn(stage)
+	}
+}
+
+// fetchProgress tracks which stage a fetch is in, and how long each stage took
+type fetchProgress struct {
+	mu        sync.Mutex
+	current   FetchStage
+	started   bool
+	startedAt []time.Time
+	endedAt   []time.Time
+}
+
+func newFetchProgress() *fetchProgress {
+	return &fetchProgress{
+		startedAt: make([]time.Time, len(stageLabels)),
+		endedAt:   make([]time.Time, len(stageLabels)),
+	}
+}
+
+// reset clears the stages ahead of a new fetch
+func (progress *fetchProgress) reset() {
+	progress.mu.Lock()
+	defer progress.mu.Unlock()
+
+	progress.current = StageInit
+	progress.started = false
+	progress.startedAt = make([]time.Time, len(stageLabels))
+	progress.endedAt = make([]time.Time, len(stageLabels))
+}
+
+// advance completes the current stage and starts the given one
+func (progress *fetchProgress) advance(stage FetchStage) {
+	progress.mu.Lock()
+	defer progress.mu.Unlock()
+
+	now := time.Now()
+	if progress.started && progress.endedAt[progress.current].IsZero() {
+		progress.endedAt[progress.current] = now
+	}
+
+	progress.current = stage
+	progress.started = true
+	progress.startedAt[stage] = now
+}
+
+// since returns the time elapsed since the given stage started, or zero if it never did
+func (progress *fetchProgress) since(stage FetchStage) time.Duration {
+	progress.mu.Lock()
+	defer progress.mu.Unlock()
+
+	if progress.startedAt[stage].IsZero() {
+		return 0
+	}
+
+	return time.Since(progress.startedAt[stage])
+}
+
+// render lists every stage: completed ones with a checkmark and their duration, the current
+// one with a spinner and its elapsed time, and the pending ones dimmed
+func (progress *fetchProgress) render() string {
+	progress.mu.Lock()
+	defer progress.mu.Unlock()
+
+	now := time.Now()
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "[yellow]%s[white]\n\n", loadingMessage)
+
+	for i, label := range stageLabels {
+		stage := FetchStage(i)
+
+		switch {
+		case !progress.endedAt[stage].IsZero():
+			elapsed := progress.endedAt[stage].Sub(progress.startedAt[stage])
+			_, _ = fmt.Fprintf(&sb, "[green]%s[white] %s [dim](%s)[white]\n", stageCompleted, label, formatQueryDuration(elapsed))
+		case progress.started && stage == progress.current:
+			elapsed := now.Sub(progress.startedAt[stage])
+			_, _ = fmt.Fprintf(&sb, "[yellow]%s[white] %s [dim](%s)[white]\n", view.SpinnerFrame(elapsed), label, formatQueryDuration(elapsed))
+		default:
+			_, _ = fmt.Fprintf(&sb, "[dim]• %s[white]\n", label)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,85 @@
+package azurelogs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wtfutil/wtf/view"
+)
+
+func TestFetchProgress_Render(t *testing.T) {
+	progress := newFetchProgress()
+
+	content := progress.render()
+	for _, label := range stageLabels {
+		assert.Contains(t, content, "[dim]• "+label)
+	}
+
+	progress.advance(StageInit)
+	progress.advance(StageQuery)
+
+	content = progress.render()
+	assert.Contains(t, content, "[green]"+stageCompleted+"[white] "+stageLabels[StageInit])
+	assert.Contains(t, content, "[white] "+stageLabels[StageQuery]+" [dim](")
+	assert.NotContains(t, content, "[green]"+stageCompleted+"[white] "+stageLabels[StageQuery])
+	assert.Contains(t, content, "[dim]• "+stageLabels[StageProcess])
+}
+
+func TestWidget_FetchDataAsync_Stages(t *testing.T) {
+	widget := createTestWidget()
+
+	drainRedraws(t, widget)
+
+	// The fake session and runner pause after each stage until the test lets them continue
+	proceed := make(chan bool)
+	reached := make(chan FetchStage)
+	pause := func(progress ProgressFunc, stage FetchStage) {
+		progress(stage)
+		reached <- stage
+		<-proceed
+	}
+
+	widget.initSession = func(_ string, progress ProgressFunc) (*Session, error) {
+		pause(progress, StageInit)
+		return &Session{QueryFile: QueryFile{Columns: []string{"Level"}}}, nil
+	}
+	widget.runQuery = func(_ *Session, progress ProgressFunc) (*TableResp, error) {
+		pause(progress, StageQuery)
+		pause(progress, StageProcess)
+		return &TableResp{Header: []string{"Level"}, Rows: []TableRow{{"Error"}}}, nil
+	}
+
+	widget.StartLoading(loadingMessage)
+	finished := make(chan struct{})
+	go func() {
+		widget.fetchDataAsync()
+		close(finished)
+	}()
+
+	require.Equal(t, StageInit, <-reached)
+	_, content, _ := widget.content()
+	assert.Contains(t, content, "Loading Azure Logs data")
+	assert.Equal(t, 0, strings.Count(content, stageCompleted))
+
+	proceed <- true
+	require.Equal(t, StageQuery, <-reached)
+	_, content, _ = widget.content()
+	assert.Contains(t, content, "[green]"+stageCompleted+"[white] "+stageLabels[StageInit])
+	assert.Equal(t, 1, strings.Count(content, stageCompleted))
+
+	proceed <- true
+	require.Equal(t, StageProcess, <-reached)
+	_, content, _ = widget.content()
+	assert.Equal(t, 2, strings.Count(content, stageCompleted))
+	assert.NotContains(t, content, "[dim]• "+stageLabels[StageProcess])
+
+	proceed <- true
+	<-finished
+	assert.Equal(t, view.AsyncLoaded, widget.State())
+
+	_, content, _ = widget.content()
+	assert.Contains(t, content, "Error")
+	assert.NotContains(t, content, "Loading Azure Logs data")
+}
@@ -0,0 +1,57 @@
+package azurelogs
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	promptPage  = "azurelogs-prompt"
+	modalHeight = 5
+	modalWidth  = 60
+	offscreen   = -1000
+)
+
+// showPrompt opens a single-line input field over the widget. onDone receives the text
+// when Enter is pressed, onCancel (which may be nil) is called when Escape is pressed
+func (widget *Widget) showPrompt(label, text string, onDone func(string), onCancel func()) {
+	if widget.pages == nil {
+		return
+	}
+
+	input := tview.NewInputField()
+	input.SetLabel(label)
+	input.SetText(text)
+	input.SetFieldWidth(modalWidth - len(label) - 4)
+
+	closeFunc := func() {
+		widget.pages.RemovePage(promptPage)
+		widget.tviewApp.SetFocus(widget.View)
+	}
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		closeFunc()
+
+		switch key {
+		case tcell.KeyEnter:
+			onDone(input.GetText())
+		case tcell.KeyEscape:
+			if onCancel != nil {
+				onCancel()
+			}
+		}
+	})
+
+	frame := tview.NewFrame(input)
+	frame.SetBorder(true)
+	frame.SetBorders(1, 1, 0, 0, 1, 1)
+	frame.SetRect(offscreen, offscreen, modalWidth, modalHeight)
+	frame.SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+		w, h := screen.Size()
+		frame.SetRect((w/2)-(width/2), (h/2)-(height/2), width, height)
+		return x, y, width, height
+	})
+
+	widget.pages.AddPage(promptPage, frame, false, true)
+	widget.tviewApp.SetFocus(frame)
+}
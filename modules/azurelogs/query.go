@@ -22,24 +22,38 @@ type TableRow []string
 // TableResp represents the response from an Azure Log Analytics query
 type TableResp struct {
 	Header []string   // Column headers
+	Types  []string   // Column types, such as long or string, as the response gives them
 	Rows   []TableRow // Data rows
+
+	// FoundRows is how many rows the query found, as its statistics say, or 0 when unknown
+	FoundRows int
+}
+
+// LogsQuerier is the subset of the Azure Logs client used to run queries
+type LogsQuerier interface {
+	QueryWorkspace(ctx context.Context, workspaceID string, body azquery.Body, options *azquery.LogsClientQueryWorkspaceOptions) (azquery.LogsClientQueryWorkspaceResponse, error)
+	QueryResource(ctx context.Context, resourceID string, body azquery.Body, options *azquery.LogsClientQueryResourceOptions) (azquery.LogsClientQueryResourceResponse, error)
 }
 
 // RunQuery executes an Azure Log Analytics query and returns the formatted results
-func RunQuery(sess *Session) (*TableResp, error) {
+func RunQuery(sess *Session, progress ProgressFunc) (*TableResp, error) {
 	qf := sess.QueryFile
 	var err error
-	var tableResp TableResp
-	tableResp.Header = qf.Columns
 
-	if qf.WorkspaceID == "" {
-		return nil, fmt.Errorf("azure workspace ID is required but not configured")
+	err = validateQueryTarget(qf)
+	if err != nil {
+		return nil, err
 	}
 
 	if qf.SubscriptionID == "" {
 		return nil, fmt.Errorf("azure subscription ID is required but not configured")
 	}
 
+	query, err := expandQuery(qf)
+	if err != nil {
+		return nil, err
+	}
+
 	// Use read lock first to check if client exists
 	clientsMutex.RLock()
 	client := LogQueryClients[qf.SubscriptionID]
@@ -65,15 +79,37 @@ func RunQuery(sess *Session) (*TableResp, error) {
 		clientsMutex.Unlock()
 	}
 
-	res, err := client.QueryWorkspace(
-		context.Background(),
-		qf.WorkspaceID,
-		azquery.Body{
-			Query: to.Ptr(qf.Query),
-		},
-		nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute query on workspace %s: %w", qf.WorkspaceID, err)
+	return executeQuery(client, qf, query, progress)
+}
+
+// executeQuery runs the query against either the workspace or the resource configured in
+// the query file, and converts the single result table into a TableResp
+func executeQuery(client LogsQuerier, qf QueryFile, query string, progress ProgressFunc) (*TableResp, error) {
+	var tableResp TableResp
+	tableResp.Header = qf.Columns
+
+	body := azquery.Body{
+		Query: to.Ptr(query),
+	}
+
+	progress.report(StageQuery)
+
+	// The statistics say how many rows the query found, which tells when some were left out
+	options := &azquery.LogsQueryOptions{Statistics: to.Ptr(true)}
+
+	var res azquery.Results
+	if qf.ResourceID != "" {
+		resp, err := client.QueryResource(context.Background(), qf.ResourceID, body, &azquery.LogsClientQueryResourceOptions{Options: options})
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query on resource %s: %w", qf.ResourceID, err)
+		}
+		res = resp.Results
+	} else {
+		resp, err := client.QueryWorkspace(context.Background(), qf.WorkspaceID, body, &azquery.LogsClientQueryWorkspaceOptions{Options: options})
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query on workspace %s: %w", qf.WorkspaceID, err)
+		}
+		res = resp.Results
 	}
 
 	if res.Error != nil {
@@ -82,13 +118,23 @@ func RunQuery(sess *Session) (*TableResp, error) {
 
 	switch len(res.Tables) {
 	case 0:
-		return nil, fmt.Errorf("query returned no data tables: %s", qf.Query)
+		return nil, fmt.Errorf("query returned no data tables: %s", query)
 	case 1:
 		if len(res.Tables[0].Columns) == 0 {
-			return nil, fmt.Errorf("query returned table with no columns: %s", qf.Query)
+			return nil, fmt.Errorf("query returned table with no columns: %s", query)
 		}
 	default:
-		return nil, fmt.Errorf("query returned %d tables, expected 1: %s", len(res.Tables), qf.Query)
+		return nil, fmt.Errorf("query returned %d tables, expected 1: %s", len(res.Tables), query)
+	}
+
+	progress.report(StageProcess)
+
+	for _, column := range res.Tables[0].Columns {
+		columnType := ""
+		if column != nil && column.Type != nil {
+			columnType = string(*column.Type)
+		}
+		tableResp.Types = append(tableResp.Types, columnType)
 	}
 
 	// Process each row of data
@@ -114,5 +160,19 @@ func RunQuery(sess *Session) (*TableResp, error) {
 		tableResp.Rows = append(tableResp.Rows, r)
 	}
 
+	tableResp.FoundRows = statisticsRowCount(res.Statistics)
+
 	return &tableResp, nil
 }
+
+// validateQueryTarget ensures exactly one of the workspace ID and the resource ID is configured
+func validateQueryTarget(qf QueryFile) error {
+	switch {
+	case qf.WorkspaceID == "" && qf.ResourceID == "":
+		return fmt.Errorf("azure workspace ID is required but not configured, unless azure_resource_id is set")
+	case qf.WorkspaceID != "" && qf.ResourceID != "":
+		return fmt.Errorf("azure_workspace_id and azure_resource_id are mutually exclusive, configure only one")
+	}
+
+	return nil
+}
@@ -1,12 +1,45 @@
 package azurelogs
 
 import (
+	"context"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/monitor/azquery"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// fakeLogsClient records which query path was used and returns a canned result
+type fakeLogsClient struct {
+	workspaceCalls []string
+	resourceCalls  []string
+	queries        []string
+	results        azquery.Results
+}
+
+func (client *fakeLogsClient) QueryWorkspace(_ context.Context, workspaceID string, body azquery.Body, _ *azquery.LogsClientQueryWorkspaceOptions) (azquery.LogsClientQueryWorkspaceResponse, error) {
+	client.workspaceCalls = append(client.workspaceCalls, workspaceID)
+	client.queries = append(client.queries, *body.Query)
+	return azquery.LogsClientQueryWorkspaceResponse{Results: client.results}, nil
+}
+
+func (client *fakeLogsClient) QueryResource(_ context.Context, resourceID string, body azquery.Body, _ *azquery.LogsClientQueryResourceOptions) (azquery.LogsClientQueryResourceResponse, error) {
+	client.resourceCalls = append(client.resourceCalls, resourceID)
+	client.queries = append(client.queries, *body.Query)
+	return azquery.LogsClientQueryResourceResponse{Results: client.results}, nil
+}
+
+// newFakeLogsClient returns a fake client whose queries return a single table with the given rows
+func newFakeLogsClient(columns []string, rows ...azquery.Row) *fakeLogsClient {
+	table := &azquery.Table{Rows: rows}
+	for _, column := range columns {
+		name := column
+		table.Columns = append(table.Columns, &azquery.Column{Name: &name})
+	}
+
+	return &fakeLogsClient{results: azquery.Results{Tables: []*azquery.Table{table}}}
+}
+
 // createMockSession creates a mock session for testing
 func createMockSession() *Session {
 	return &Session{
@@ -26,7 +59,7 @@ func TestRunQuery_MissingWorkspaceID(t *testing.T) {
 
 	// Since we can't mock the Azure client easily, we expect this to fail
 	// during client creation or earlier validation
-	result, err := RunQuery(sess)
+	result, err := RunQuery(sess, nil)
 
 	assert.Nil(t, result)
 	assert.Error(t, err)
@@ -37,7 +70,7 @@ func TestRunQuery_MissingSubscriptionID(t *testing.T) {
 	sess := createMockSession()
 	sess.QueryFile.SubscriptionID = ""
 
-	result, err := RunQuery(sess)
+	result, err := RunQuery(sess, nil)
 
 	assert.Nil(t, result)
 	assert.Error(t, err)
@@ -93,3 +126,48 @@ func TestLogQueryClients_GlobalVariable(t *testing.T) {
 	// Test that the map exists and can be used
 	assert.IsType(t, map[string]*azquery.LogsClient{}, LogQueryClients)
 }
+
+func TestRunQuery_BothWorkspaceAndResourceID(t *testing.T) {
+	sess := createMockSession()
+	sess.QueryFile.ResourceID = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Insights/components/app"
+
+	result, err := RunQuery(sess, nil)
+
+	assert.Nil(t, result)
+	assert.ErrorContains(t, err, "mutually exclusive")
+}
+
+func TestValidateQueryTarget(t *testing.T) {
+	assert.NoError(t, validateQueryTarget(QueryFile{WorkspaceID: "ws"}))
+	assert.NoError(t, validateQueryTarget(QueryFile{ResourceID: "/subscriptions/sub"}))
+	assert.ErrorContains(t, validateQueryTarget(QueryFile{}), "azure workspace ID is required")
+	assert.ErrorContains(t, validateQueryTarget(QueryFile{WorkspaceID: "ws", ResourceID: "/subscriptions/sub"}), "mutually exclusive")
+}
+
+func TestExecuteQuery_ResourcePath(t *testing.T) {
+	client := newFakeLogsClient([]string{"TimeGenerated", "Count"}, azquery.Row{"2024-03-05T14:00:00Z", float64(42)})
+	qf := QueryFile{
+		ResourceID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm-01",
+		Columns:    []string{"TimeGenerated", "Count"},
+	}
+
+	result, err := executeQuery(client, qf, "Perf | count", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{qf.ResourceID}, client.resourceCalls)
+	assert.Empty(t, client.workspaceCalls)
+	assert.Equal(t, []string{"Perf | count"}, client.queries)
+	assert.Equal(t, []TableRow{{"2024-03-05T14:00:00Z", "42"}}, result.Rows)
+}
+
+func TestExecuteQuery_WorkspacePath(t *testing.T) {
+	client := newFakeLogsClient([]string{"Level"}, azquery.Row{"Error"}, azquery.Row{nil})
+	qf := QueryFile{WorkspaceID: "ws", Columns: []string{"Level"}}
+
+	result, err := executeQuery(client, qf, "AzureActivity", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ws"}, client.workspaceCalls)
+	assert.Empty(t, client.resourceCalls)
+	assert.Equal(t, []TableRow{{"Error"}, {""}}, result.Rows)
+}
@@ -0,0 +1,68 @@
+package azurelogs
+
+// displayedRows returns the rows that pass the filter and fit within the display cap.
+// Callers must hold mu
+func (widget *Widget) displayedRows() []TableRow {
+	if widget.tableData == nil {
+		return nil
+	}
+
+	rows := filterRows(widget.tableData.Rows, widget.filter)
+	if len(rows) > maxDisplayRows {
+		rows = rows[:maxDisplayRows]
+	}
+
+	return rows
+}
+
+// selectedRow returns the original, untruncated, selected row or nil if none is selected.
+// Callers must hold mu
+func (widget *Widget) selectedRow() TableRow {
+	rows := widget.displayedRows()
+	if widget.selected < 0 || widget.selected >= len(rows) {
+		return nil
+	}
+
+	return rows[widget.selected]
+}
+
+func (widget *Widget) next() {
+	widget.update(func() bool {
+		rows := widget.displayedRows()
+		if len(rows) == 0 {
+			widget.selected = -1
+			return false
+		}
+
+		widget.selected++
+		if widget.selected >= len(rows) {
+			widget.selected = 0
+		}
+
+		return true
+	})
+}
+
+func (widget *Widget) prev() {
+	widget.update(func() bool {
+		rows := widget.displayedRows()
+		if len(rows) == 0 {
+			widget.selected = -1
+			return false
+		}
+
+		widget.selected--
+		if widget.selected < 0 {
+			widget.selected = len(rows) - 1
+		}
+
+		return true
+	})
+}
+
+func (widget *Widget) unselect() {
+	widget.update(func() bool {
+		widget.selected = -1
+		return true
+	})
+}
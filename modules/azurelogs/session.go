@@ -1,11 +1,14 @@
 package azurelogs
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/monitor/azquery"
-	"os"
 )
 
 const (
@@ -15,7 +18,9 @@ const (
 )
 
 // Init initializes a new Azure session with the specified query file
-func Init(queryPath *string) (*Session, error) {
+func Init(queryPath *string, progress ProgressFunc) (*Session, error) {
+	progress.report(StageInit)
+
 	sess := &Session{}
 	sess.Azure = &AZSession{}
 
@@ -102,3 +107,26 @@ func CreateLogsClient(sess *Session, subscriptionID string) (*azquery.LogsClient
 
 	return client, nil
 }
+
+// forgetLogsClient removes the cached Logs client for a subscription, so that the next
+// query creates a new one with fresh credentials
+func forgetLogsClient(subscriptionID string) {
+	clientsMutex.Lock()
+	delete(LogQueryClients, subscriptionID)
+	clientsMutex.Unlock()
+}
+
+// isAuthError returns true if the error was caused by failed or rejected authentication
+func isAuthError(err error) bool {
+	var authFailed *azidentity.AuthenticationFailedError
+	if errors.As(err, &authFailed) {
+		return true
+	}
+
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.StatusCode == http.StatusUnauthorized || respErr.StatusCode == http.StatusForbidden
+	}
+
+	return false
+}
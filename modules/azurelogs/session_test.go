@@ -1,9 +1,13 @@
 package azurelogs
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -145,7 +149,7 @@ func TestInit_InvalidQueryPath(t *testing.T) {
 	// Test Init with invalid query path
 	invalidPath := "/nonexistent/path/to/query.yml"
 
-	sess, err := Init(&invalidPath)
+	sess, err := Init(&invalidPath, nil)
 
 	assert.Nil(t, sess)
 	assert.Error(t, err)
@@ -162,7 +166,7 @@ func TestInit_NilQueryPath(t *testing.T) {
 		}
 	}()
 
-	sess, err := Init(nil)
+	sess, err := Init(nil, nil)
 
 	// If we get here, the function handled nil gracefully
 	assert.Nil(t, sess)
@@ -175,3 +179,10 @@ func TestEnvironmentConstants(t *testing.T) {
 	assert.Equal(t, "AZURE_CLIENT_SECRET", envAzureClientSecret)
 	assert.Equal(t, "AZURE_TENANT_ID", envAzureTenantID)
 }
+
+func TestIsAuthError(t *testing.T) {
+	assert.True(t, isAuthError(fmt.Errorf("wrapped: %w", &azcore.ResponseError{StatusCode: http.StatusUnauthorized})))
+	assert.True(t, isAuthError(&azcore.ResponseError{StatusCode: http.StatusForbidden}))
+	assert.False(t, isAuthError(&azcore.ResponseError{StatusCode: http.StatusBadRequest}))
+	assert.False(t, isAuthError(errors.New("query failed")))
+}
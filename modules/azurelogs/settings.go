@@ -1,14 +1,18 @@
 package azurelogs
 
 import (
+	"time"
+
 	"github.com/olebedev/config"
 
 	"github.com/wtfutil/wtf/cfg"
+	"github.com/wtfutil/wtf/utils"
 )
 
 const (
-	defaultFocusable = true
-	defaultTitle     = "Azure Logs"
+	defaultFocusable       = true
+	defaultRefreshInterval = 60 * time.Second
+	defaultTitle           = "Azure Logs"
 )
 
 // Settings defines the configuration for the Azure Logs widget
@@ -16,7 +20,33 @@ type Settings struct {
 	*cfg.Common
 
 	// Queryfile is the path to the YAML file containing the Azure query configuration
-	Queryfile string `help:"Path to YAML file containing Azure Log Analytics query configuration"`
+	Queryfile string `help:"Path to YAML file containing Azure Log Analytics query configuration" key:"queryFile"`
+
+	// ShowWorkspaceName follows the title with the name of the workspace queried, looked up once
+	ShowWorkspaceName bool `help:"Whether or not to show the name of the workspace queried in the title" values:"true or false" optional:"true" default:"false"`
+
+	// NumberFormat is how the cells of numeric columns are shown: as they are, or with
+	// thousands separators
+	NumberFormat string `help:"How the cells of numeric columns are shown" values:"plain or thousands" optional:"true" default:"plain"`
+
+	// ShowDiff marks how the rows differ from the previous refresh, matching them by KeyColumns
+	ShowDiff bool `help:"Whether or not to mark the rows that are new, gone or changed since the previous refresh" values:"true or false" optional:"true" default:"false"`
+
+	// KeyColumns are the columns rows are matched by between refreshes
+	KeyColumns []string `help:"Columns whose values identify a row between refreshes, for showDiff. The first column by default" optional:"true"`
+
+	// ShowRemovedRows shows the rows gone since the previous refresh, struck through, for one refresh
+	ShowRemovedRows bool `help:"Whether or not showDiff shows the rows gone since the previous refresh, struck through" values:"true or false" optional:"true" default:"true"`
+
+	// ShowRowNumbers prefixes each row with its right-aligned index
+	ShowRowNumbers bool `help:"Whether or not to prefix each row with its row number" values:"true or false" optional:"true" default:"false"`
+
+	// RowCap is the most rows the workspace returns for a query. Results with as many rows
+	// are shown as possibly truncated
+	RowCap int `help:"The most rows the workspace returns for a query. Results with as many rows are flagged as possibly truncated, 0 never flags them" optional:"true" default:"30000"`
+
+	// WrapColumn is the name of a column that is wrapped onto continuation lines instead of truncated
+	WrapColumn string `help:"Name of a column to wrap onto continuation lines instead of truncating it, e.g. Message" optional:"true"`
 }
 
 // NewSettingsFromYAML creates a new Settings instance from YAML configuration
@@ -24,8 +54,21 @@ func NewSettingsFromYAML(name string, ymlConfig *config.Config, globalConfig *co
 	settings := Settings{
 		Common: cfg.NewCommonSettingsFromModule(name, defaultTitle, defaultFocusable, ymlConfig, globalConfig),
 
-		Queryfile: ymlConfig.UString("queryFile", ""),
+		Queryfile:         ymlConfig.UString("queryFile", ""),
+		NumberFormat:      ymlConfig.UString("numberFormat", numberFormatPlain),
+		RowCap:            ymlConfig.UInt("rowCap", defaultRowCap),
+		KeyColumns:        utils.ToStrs(ymlConfig.UList("keyColumns")),
+		ShowDiff:          ymlConfig.UBool("showDiff", false),
+		ShowRemovedRows:   ymlConfig.UBool("showRemovedRows", true),
+		ShowRowNumbers:    ymlConfig.UBool("showRowNumbers", false),
+		ShowWorkspaceName: ymlConfig.UBool("showWorkspaceName", false),
+		WrapColumn:        ymlConfig.UString("wrapColumn", ""),
 	}
 
+	// Queries are cheap enough to run more often than most modules refresh
+	settings.SetDefaultRefreshInterval(defaultRefreshInterval)
+
+	settings.CheckKeys(cfg.NewSchema(Settings{}))
+
 	return &settings
 }
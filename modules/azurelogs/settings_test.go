@@ -1,7 +1,11 @@
 package azurelogs
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/olebedev/config"
 	"github.com/stretchr/testify/assert"
@@ -136,6 +140,35 @@ wtf:
 	assert.Equal(t, "Production Azure Logs", settings.Title)
 	assert.Equal(t, "/etc/wtf/azure-query.yml", settings.Queryfile)
 	assert.NotNil(t, settings.Common)
+	assert.Equal(t, 5*time.Minute, settings.RefreshInterval)
+}
+
+func TestNewSettingsFromYAML_DefaultRefreshInterval(t *testing.T) {
+	ymlConfig, err := config.ParseYaml("queryFile: query.yml")
+	assert.NoError(t, err)
+
+	settings := NewSettingsFromYAML("azurelogs", ymlConfig, ymlConfig)
+
+	assert.Equal(t, defaultRefreshInterval, settings.RefreshInterval)
+}
+
+func TestNewSettingsFromYAML_UnknownKeys(t *testing.T) {
+	ymlConfig, err := config.ParseYaml(`
+queryfile: query.yml
+showRowNumbers: true
+wrapColum: Message
+colors:
+  rows:
+    even: white
+`)
+	assert.NoError(t, err)
+
+	settings := NewSettingsFromYAML("azurelogs", ymlConfig, ymlConfig)
+
+	assert.Equal(t, []cfg.UnknownKey{
+		{Key: "queryfile", Suggestion: "queryFile"},
+		{Key: "wrapColum", Suggestion: "wrapColumn"},
+	}, settings.UnknownKeys())
 }
 
 // Helper function to convert map to YAML string for testing
@@ -183,3 +216,68 @@ func interfaceToString(v interface{}) string {
 		return "null"
 	}
 }
+
+func TestNewSettingsFromYAML_Layout(t *testing.T) {
+	ymlConfig, err := config.ParseYaml(`
+showRowNumbers: true
+wrapColumn: Message
+`)
+	assert.NoError(t, err)
+
+	settings := NewSettingsFromYAML("azurelogs", ymlConfig, ymlConfig)
+
+	assert.True(t, settings.ShowRowNumbers)
+	assert.Equal(t, "Message", settings.WrapColumn)
+}
+
+func TestNewSettingsFromYAML_Expansion(t *testing.T) {
+	t.Setenv("WTF_TEST_AZURE_QUERY", "/etc/wtf/from-env.yml")
+
+	pathFile := filepath.Join(t.TempDir(), "query-path")
+	assert.NoError(t, os.WriteFile(pathFile, []byte(" /etc/wtf/from-file.yml \n"), 0o600))
+
+	tests := []struct {
+		name      string
+		queryFile string
+		expected  string
+		failed    bool
+	}{
+		{name: "literal", queryFile: "/etc/wtf/query.yml", expected: "/etc/wtf/query.yml"},
+		{name: "env", queryFile: "${WTF_TEST_AZURE_QUERY}", expected: "/etc/wtf/from-env.yml"},
+		{name: "file", queryFile: "file:" + pathFile, expected: "/etc/wtf/from-file.yml"},
+		{name: "missing env", queryFile: "${WTF_TEST_AZURE_MISSING}", expected: "${WTF_TEST_AZURE_MISSING}", failed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ymlConfig := &config.Config{Root: map[string]interface{}{"queryFile": tt.queryFile}}
+
+			settings := NewSettingsFromYAML("azurelogs", ymlConfig, ymlConfig)
+
+			assert.Equal(t, tt.expected, settings.Queryfile)
+
+			failed := false
+			for _, validation := range settings.Validations() {
+				if validation.HasError() && strings.Contains(validation.String(), "queryFile") {
+					failed = true
+				}
+			}
+			assert.Equal(t, tt.failed, failed)
+		})
+	}
+}
+
+func TestNewSettingsFromYAML_RowCap(t *testing.T) {
+	ymlConfig, err := config.ParseYaml("queryFile: query.yml")
+	assert.NoError(t, err)
+
+	settings := NewSettingsFromYAML("azurelogs", ymlConfig, ymlConfig)
+	assert.Equal(t, defaultRowCap, settings.RowCap)
+
+	ymlConfig, err = config.ParseYaml("rowCap: 500000")
+	assert.NoError(t, err)
+
+	settings = NewSettingsFromYAML("azurelogs", ymlConfig, ymlConfig)
+	assert.Equal(t, 500000, settings.RowCap)
+	assert.Empty(t, settings.UnknownKeys())
+}
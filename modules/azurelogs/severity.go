@@ -0,0 +1,112 @@
+package azurelogs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// severityNames are the severities rows can be filtered by, from least to most severe. Their
+// index is their level, which is also how Application Insights numbers them in SeverityLevel
+var severityNames = []string{"Verbose", "Information", "Warning", "Error", "Critical"}
+
+// severityAliases are the other names severities go by in logs
+var severityAliases = map[string]int{
+	"debug": 0,
+	"trace": 0,
+	"info":  1,
+	"warn":  2,
+	"fatal": 4,
+}
+
+// defaultSeverityColumns are the columns searched for severities when the query file names
+// none, in order
+var defaultSeverityColumns = []string{"Level", "SeverityLevel"}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// severityLevel returns the level of a severity, given by name, such as Warning, or by its
+// Application Insights number, such as 2. ok is false for anything else
+func severityLevel(value string) (level int, ok bool) {
+	value = strings.TrimSpace(value)
+
+	if number, err := strconv.Atoi(value); err == nil {
+		return number, number >= 0 && number < len(severityNames)
+	}
+
+	for level, name := range severityNames {
+		if strings.EqualFold(value, name) {
+			return level, true
+		}
+	}
+
+	level, ok = severityAliases[strings.ToLower(value)]
+	return level, ok
+}
+
+// validateMinSeverity checks that the minimum severity of a query file is one there is
+func validateMinSeverity(minSeverity string) error {
+	if minSeverity == "" {
+		return nil
+	}
+
+	if _, ok := severityLevel(minSeverity); !ok {
+		return fmt.Errorf("invalid minSeverity %q, expected one of %s", minSeverity, strings.Join(severityNames, ", "))
+	}
+
+	return nil
+}
+
+// severityColumn returns the index of the column holding the severity of the rows: the one
+// the query file names, or else the first of the default ones. It's -1 when there's none
+func severityColumn(headers []string, name string) int {
+	if name != "" {
+		return columnIndex(headers, name)
+	}
+
+	for _, name := range defaultSeverityColumns {
+		if idx := columnIndex(headers, name); idx >= 0 {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// filterSeverity returns the table without its rows below the minimum severity of the query
+// file, and how many were left out. Rows with a severity that isn't known are kept, as are
+// all of them when the table has no severity column
+func filterSeverity(table *TableResp, qf QueryFile) (*TableResp, int) {
+	minLevel, ok := severityLevel(qf.MinSeverity)
+	if qf.MinSeverity == "" || !ok {
+		return table, 0
+	}
+
+	col := severityColumn(table.Header, qf.SeverityColumn)
+	if col < 0 {
+		return table, 0
+	}
+
+	kept := make([]TableRow, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		if col < len(row) {
+			if level, ok := severityLevel(row[col]); ok && level < minLevel {
+				continue
+			}
+		}
+		kept = append(kept, row)
+	}
+
+	return &TableResp{Header: table.Header, Types: table.Types, Rows: kept}, len(table.Rows) - len(kept)
+}
+
+// severityIndicator says how many rows were left out for being below the minimum severity,
+// or "" when none were
+func severityIndicator(belowSeverity int, minSeverity string) string {
+	if belowSeverity == 0 {
+		return ""
+	}
+
+	level, _ := severityLevel(minSeverity)
+	return fmt.Sprintf(" (filtered %d rows below %s)", belowSeverity, severityNames[level])
+}
@@ -0,0 +1,193 @@
+package azurelogs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeverityLevel(t *testing.T) {
+	tests := []struct {
+		value      string
+		expected   int
+		expectedOk bool
+	}{
+		{value: "Verbose", expected: 0, expectedOk: true},
+		{value: "information", expected: 1, expectedOk: true},
+		{value: " WARNING ", expected: 2, expectedOk: true},
+		{value: "Error", expected: 3, expectedOk: true},
+		{value: "Critical", expected: 4, expectedOk: true},
+		{value: "warn", expected: 2, expectedOk: true},
+		{value: "Debug", expected: 0, expectedOk: true},
+		{value: "0", expected: 0, expectedOk: true},
+		{value: "3", expected: 3, expectedOk: true},
+		{value: "5", expected: 5, expectedOk: false},
+		{value: "-1", expected: -1, expectedOk: false},
+		{value: "Notice", expected: 0, expectedOk: false},
+		{value: "", expected: 0, expectedOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			level, ok := severityLevel(tt.value)
+
+			assert.Equal(t, tt.expectedOk, ok)
+			if tt.expectedOk {
+				assert.Equal(t, tt.expected, level)
+			}
+		})
+	}
+}
+
+func TestFilterSeverity(t *testing.T) {
+	tests := []struct {
+		name             string
+		table            *TableResp
+		qf               QueryFile
+		expectedRows     []TableRow
+		expectedFiltered int
+	}{
+		{
+			name: "string levels",
+			table: &TableResp{
+				Header: []string{"TimeGenerated", "Level", "Message"},
+				Rows: []TableRow{
+					{"10:00", "Information", "started"},
+					{"10:01", "Warning", "slow"},
+					{"10:02", "Verbose", "tick"},
+					{"10:03", "Error", "failed"},
+					{"10:04", "Critical", "down"},
+				},
+			},
+			qf: QueryFile{MinSeverity: "Warning"},
+			expectedRows: []TableRow{
+				{"10:01", "Warning", "slow"},
+				{"10:03", "Error", "failed"},
+				{"10:04", "Critical", "down"},
+			},
+			expectedFiltered: 2,
+		},
+		{
+			name: "numeric Application Insights levels",
+			table: &TableResp{
+				Header: []string{"timestamp", "severityLevel", "message"},
+				Rows: []TableRow{
+					{"10:00", "1", "started"},
+					{"10:01", "2", "slow"},
+					{"10:02", "0", "tick"},
+					{"10:03", "3", "failed"},
+				},
+			},
+			qf: QueryFile{MinSeverity: "3"},
+			expectedRows: []TableRow{
+				{"10:03", "3", "failed"},
+			},
+			expectedFiltered: 3,
+		},
+		{
+			name: "unknown values pass through",
+			table: &TableResp{
+				Header: []string{"Level", "Message"},
+				Rows: []TableRow{
+					{"Information", "started"},
+					{"Notice", "odd"},
+					{"", "blank"},
+					{"9", "out of range"},
+					{"Error", "failed"},
+				},
+			},
+			qf: QueryFile{MinSeverity: "Error"},
+			expectedRows: []TableRow{
+				{"Notice", "odd"},
+				{"", "blank"},
+				{"9", "out of range"},
+				{"Error", "failed"},
+			},
+			expectedFiltered: 1,
+		},
+		{
+			name: "named column",
+			table: &TableResp{
+				Header: []string{"Level", "Sev"},
+				Rows: []TableRow{
+					{"Error", "Information"},
+					{"Information", "Error"},
+				},
+			},
+			qf: QueryFile{MinSeverity: "Warning", SeverityColumn: "sev"},
+			expectedRows: []TableRow{
+				{"Information", "Error"},
+			},
+			expectedFiltered: 1,
+		},
+		{
+			name: "no severity column",
+			table: &TableResp{
+				Header: []string{"Computer", "Count"},
+				Rows:   []TableRow{{"web-01", "1"}},
+			},
+			qf:               QueryFile{MinSeverity: "Warning"},
+			expectedRows:     []TableRow{{"web-01", "1"}},
+			expectedFiltered: 0,
+		},
+		{
+			name: "no minimum",
+			table: &TableResp{
+				Header: []string{"Level"},
+				Rows:   []TableRow{{"Verbose"}},
+			},
+			qf:               QueryFile{},
+			expectedRows:     []TableRow{{"Verbose"}},
+			expectedFiltered: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, filtered := filterSeverity(tt.table, tt.qf)
+
+			assert.Equal(t, tt.table.Header, table.Header)
+			assert.Equal(t, tt.expectedRows, table.Rows)
+			assert.Equal(t, tt.expectedFiltered, filtered)
+		})
+	}
+}
+
+func TestReadQueryFileContent_MinSeverity(t *testing.T) {
+	qf, err := readQueryFileContent(writeTempQueryFile(t, "query: AppTraces\nminSeverity: warning\nseverityColumn: SeverityLevel"))
+	require.NoError(t, err)
+	assert.Equal(t, "warning", qf.MinSeverity)
+	assert.Equal(t, "SeverityLevel", qf.SeverityColumn)
+
+	_, err = readQueryFileContent(writeTempQueryFile(t, "query: AppTraces\nminSeverity: loud"))
+	assert.ErrorContains(t, err, `invalid minSeverity "loud"`)
+}
+
+func TestWidget_Footer_Severity(t *testing.T) {
+	widget := createWorkspaceWidget(t, func(_ *Session, _ string) (string, error) { return "", nil })
+	widget.initSession = func(_ string, _ ProgressFunc) (*Session, error) {
+		return &Session{QueryFile: QueryFile{MinSeverity: "warn"}}, nil
+	}
+	widget.runQuery = func(_ *Session, _ ProgressFunc) (*TableResp, error) {
+		return &TableResp{
+			Header: []string{"Level", "Message"},
+			Rows:   []TableRow{{"Information", "started"}, {"Warning", "slow"}, {"Verbose", "tick"}},
+		}, nil
+	}
+
+	refreshAndWait(t, widget)
+
+	// The fetch may still be redrawing the widget
+	widget.mu.Lock()
+	defer widget.mu.Unlock()
+
+	widget.lastDuration = 1800 * time.Millisecond
+	assert.Contains(t, widget.footer(), "query took 1.8s · 1 rows (filtered 2 rows below Warning)[white]")
+	assert.Len(t, widget.tableData.Rows, 1)
+
+	// Nothing is said when no rows were left out
+	widget.belowSeverity = 0
+	assert.Contains(t, widget.footer(), "query took 1.8s · 1 rows[white]")
+}
@@ -0,0 +1,52 @@
+package azurelogs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	templateNowFormat   = time.RFC3339
+	templateTodayFormat = "2006-01-02"
+)
+
+// nowFunc returns the current time, and is replaceable in tests
+var nowFunc = time.Now
+
+// expandQuery executes the query string as a Go text/template. Params from the query file are
+// available as fields (e.g. {{.Hostname}}), environment variables through {{env "NAME"}}, and
+// the current time through {{now}} and {{today}}. Literal braces can be written as {{"{{"}}.
+func expandQuery(qf QueryFile) (string, error) {
+	if !strings.Contains(qf.Query, "{{") {
+		return qf.Query, nil
+	}
+
+	now := nowFunc().UTC()
+
+	funcs := template.FuncMap{
+		"env":   os.Getenv,
+		"now":   func() string { return now.Format(templateNowFormat) },
+		"today": func() string { return now.Format(templateTodayFormat) },
+	}
+
+	tmpl, err := template.New("query").Funcs(funcs).Option("missingkey=error").Parse(qf.Query)
+	if err != nil {
+		return "", fmt.Errorf("invalid query template: %w", err)
+	}
+
+	params := qf.Params
+	if params == nil {
+		params = map[string]string{}
+	}
+
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand query template: %w", err)
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,98 @@
+package azurelogs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandQuery_Params(t *testing.T) {
+	qf := QueryFile{
+		Query:  `Heartbeat | where Computer == "{{.Hostname}}" | where TimeGenerated > ago({{.Window}})`,
+		Params: map[string]string{"Hostname": "web-01", "Window": "1h"},
+	}
+
+	query, err := expandQuery(qf)
+	require.NoError(t, err)
+	assert.Equal(t, `Heartbeat | where Computer == "web-01" | where TimeGenerated > ago(1h)`, query)
+}
+
+func TestExpandQuery_Env(t *testing.T) {
+	t.Setenv("WTF_AZURELOGS_TEST_HOST", "db-02")
+
+	query, err := expandQuery(QueryFile{Query: `Perf | where Computer == "{{env "WTF_AZURELOGS_TEST_HOST"}}"`})
+	require.NoError(t, err)
+	assert.Equal(t, `Perf | where Computer == "db-02"`, query)
+}
+
+func TestExpandQuery_TimeBuiltins(t *testing.T) {
+	originalNow := nowFunc
+	defer func() { nowFunc = originalNow }()
+	nowFunc = func() time.Time { return time.Date(2024, 3, 5, 14, 2, 11, 0, time.UTC) }
+
+	query, err := expandQuery(QueryFile{Query: `where TimeGenerated between (datetime({{today}}) .. datetime({{now}}))`})
+	require.NoError(t, err)
+	assert.Equal(t, `where TimeGenerated between (datetime(2024-03-05) .. datetime(2024-03-05T14:02:11Z))`, query)
+}
+
+func TestExpandQuery_MissingKey(t *testing.T) {
+	_, err := expandQuery(QueryFile{
+		Query:  `where Computer == "{{.Hostname}}"`,
+		Params: map[string]string{"Other": "x"},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "<.Hostname>")
+	assert.Contains(t, err.Error(), "failed to expand query template")
+}
+
+func TestExpandQuery_ParseError(t *testing.T) {
+	_, err := expandQuery(QueryFile{Query: `where Computer == "{{.Hostname"`})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid query template")
+}
+
+func TestExpandQuery_Braces(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "no template is returned untouched",
+			query:    `print d = dynamic({"a": [1, 2]})`,
+			expected: `print d = dynamic({"a": [1, 2]})`,
+		},
+		{
+			name:     "single braces alongside template actions",
+			query:    `print d = dynamic({"host": "{{.Hostname}}"})`,
+			expected: `print d = dynamic({"host": "web-01"})`,
+		},
+		{
+			name:     "escaped double braces",
+			query:    `print s = "{{"{{"}}literal}}"`,
+			expected: `print s = "{{literal}}"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := expandQuery(QueryFile{Query: tt.query, Params: map[string]string{"Hostname": "web-01"}})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestRunQuery_TemplateError(t *testing.T) {
+	sess := createMockSession()
+	sess.QueryFile.Query = `Heartbeat | where Computer == "{{.Hostname}}"`
+
+	result, err := RunQuery(sess, nil)
+
+	assert.Nil(t, result)
+	assert.ErrorContains(t, err, "<.Hostname>")
+}
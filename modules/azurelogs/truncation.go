@@ -0,0 +1,69 @@
+package azurelogs
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// defaultRowCap is the most rows Log Analytics returns for a query, by default
+const defaultRowCap = 30000
+
+// queryStatistics is the part of the statistics of a query that says how many rows it found
+type queryStatistics struct {
+	Query struct {
+		DatasetStatistics []struct {
+			TableRowCount int `json:"tableRowCount"`
+		} `json:"datasetStatistics"`
+	} `json:"query"`
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// statisticsRowCount returns how many rows the statistics of a query say it found, or 0
+// when they don't say
+func statisticsRowCount(statistics []byte) int {
+	if len(statistics) == 0 {
+		return 0
+	}
+
+	var stats queryStatistics
+	if err := json.Unmarshal(statistics, &stats); err != nil {
+		return 0
+	}
+
+	rows := 0
+	for _, dataset := range stats.Query.DatasetStatistics {
+		rows += dataset.TableRowCount
+	}
+
+	return rows
+}
+
+// truncatedAt returns how many rows the table was cut at, or 0 when it looks complete: it
+// was cut when it has exactly rowCap rows, or fewer rows than the query found
+func truncatedAt(table *TableResp, rowCap int) int {
+	if table == nil {
+		return 0
+	}
+
+	rows := len(table.Rows)
+
+	switch {
+	case rowCap > 0 && rows == rowCap:
+		return rows
+	case table.FoundRows > rows:
+		return rows
+	default:
+		return 0
+	}
+}
+
+// truncationBanner warns that the rows shown may not be all of them, or returns "" when
+// they are
+func truncationBanner(truncated int) string {
+	if truncated == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("[yellow]Results may be truncated at %d rows — add a narrower timespan or explicit limit[white]\n", truncated)
+}
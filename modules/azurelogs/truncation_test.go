@@ -0,0 +1,80 @@
+package azurelogs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/monitor/azquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCappedLogsClient returns a fake client whose queries return count rows, with the given
+// statistics
+func newCappedLogsClient(count int, statistics string) *fakeLogsClient {
+	rows := make([]azquery.Row, count)
+	for idx := range rows {
+		rows[idx] = azquery.Row{fmt.Sprintf("vm-%d", idx)}
+	}
+
+	client := newFakeLogsClient([]string{"Computer"}, rows...)
+	client.results.Statistics = []byte(statistics)
+
+	return client
+}
+
+func TestStatisticsRowCount(t *testing.T) {
+	assert.Equal(t, 0, statisticsRowCount(nil))
+	assert.Equal(t, 0, statisticsRowCount([]byte(`not json`)))
+	assert.Equal(t, 0, statisticsRowCount([]byte(`{"query": {"executionTime": 0.1}}`)))
+	assert.Equal(t, 1200, statisticsRowCount([]byte(`{"query": {"datasetStatistics": [{"tableRowCount": 1200, "tableSize": 48000}]}}`)))
+}
+
+func TestTruncatedAt(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows       int
+		statistics string
+		rowCap     int
+		expected   int
+	}{
+		{name: "below the cap", rows: 4, rowCap: 5, expected: 0},
+		{name: "at the cap", rows: 5, rowCap: 5, expected: 5},
+		{name: "no cap", rows: 5, rowCap: 0, expected: 0},
+		{name: "statistics found as many", rows: 4, statistics: `{"query": {"datasetStatistics": [{"tableRowCount": 4}]}}`, rowCap: 5, expected: 0},
+		{name: "statistics found more", rows: 4, statistics: `{"query": {"datasetStatistics": [{"tableRowCount": 9}]}}`, rowCap: 5, expected: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newCappedLogsClient(tt.rows, tt.statistics)
+
+			table, err := executeQuery(client, QueryFile{WorkspaceID: "ws", Columns: []string{"Computer"}}, "Heartbeat", nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, truncatedAt(table, tt.rowCap))
+		})
+	}
+}
+
+func TestWidget_TruncationBanner(t *testing.T) {
+	widget := createWorkspaceWidget(t, func(_ *Session, _ string) (string, error) { return "", nil })
+	widget.settings.RowCap = 2
+
+	rows := []TableRow{{"Error"}, {"Warning"}}
+	widget.runQuery = func(_ *Session, _ ProgressFunc) (*TableResp, error) {
+		return &TableResp{Header: []string{"Level"}, Rows: rows}, nil
+	}
+
+	refreshAndWait(t, widget)
+
+	_, content, _ := widget.content()
+	assert.Contains(t, content, "[yellow]Results may be truncated at 2 rows — add a narrower timespan or explicit limit[white]\n")
+
+	// Below the cap, nothing is said
+	rows = rows[:1]
+	refreshAndWait(t, widget)
+
+	_, content, _ = widget.content()
+	assert.NotContains(t, content, "truncated")
+}
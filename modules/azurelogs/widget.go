@@ -2,42 +2,105 @@ package azurelogs
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
-
 	"github.com/rivo/tview"
 
+	"github.com/wtfutil/wtf/utils"
 	"github.com/wtfutil/wtf/view"
 )
 
 const (
-	defaultTableWidth  = 120
-	minColumnWidth     = 8
-	maxColumnWidth     = 30
-	maxDisplayRows     = 50
-	truncateMarker     = "..."
-	sampleRowsForWidth = 15
+	defaultTableWidth = 120
+	minColumnWidth    = 8
+	maxColumnWidth    = 30
+	maxDisplayRows    = 50
+	truncateMarker    = "..."
+	footerTimeFormat  = "15:04:05"
 )
 
 type Widget struct {
-	view.TextWidget
-	settings   *Settings
-	loading    bool
-	lastError  error
-	dataLoaded bool
+	view.AsyncTextWidget
+	pages    *tview.Pages
+	settings *Settings
+	tviewApp *tview.Application
+
+	// mu guards the data of the last fetch, the session, the selection, filter and column
+	// layout, and the toast. The widget is rendered from the fetch and the spinner as well
+	// as from the key handlers, so rendering holds it throughout
+	mu sync.Mutex
+
 	tableData  *TableResp
+	alert      *AlertResult
+	renderMode string
+
+	lastFetchedAt time.Time
+	lastDuration  time.Duration
+
+	// belowSeverity is how many rows of the last fetch were left out for being below
+	// minSeverity
+	belowSeverity int
+	minSeverity   string
+
+	// truncated is how many rows the last fetch was cut at, or 0 when it looks complete
+	truncated int
+
+	// diff is how the last fetch differs from the one before, when showDiff is set
+	diff *tableDiff
+
+	filter      string
+	layout      *columnLayout
+	selected    int
+	selectedCol int
+	toast       string
+
+	sess        *Session
+	sessModTime time.Time
+
+	clipboard   utils.Clipboard
+	initSession sessionInitializer
+	progress    *fetchProgress
+	runQuery    queryRunner
+	workspaces  *workspaceNames
+
+	openURL          func(link string)
+	resolveWorkspace workspaceResourceResolver
 }
 
+// sessionInitializer creates a session from a query file, reporting progress
+type sessionInitializer func(queryPath string, progress ProgressFunc) (*Session, error)
+
+// queryRunner runs the query of a session, reporting progress
+type queryRunner func(sess *Session, progress ProgressFunc) (*TableResp, error)
+
 // NewWidget creates a new instance of a widget
-func NewWidget(tviewApp *tview.Application, redrawChan chan bool, _ *tview.Pages, settings *Settings) *Widget {
+func NewWidget(tviewApp *tview.Application, redrawChan chan bool, pages *tview.Pages, settings *Settings) *Widget {
 	widget := Widget{
-		TextWidget: view.NewTextWidget(tviewApp, redrawChan, nil, settings.Common),
-		settings:   settings,
+		AsyncTextWidget: view.NewAsyncTextWidget(tviewApp, redrawChan, pages, settings.Common),
+		pages:           pages,
+		settings:        settings,
+		tviewApp:        tviewApp,
+
+		layout:      newColumnLayout(),
+		selected:    -1,
+		selectedCol: -1,
+
+		clipboard:   utils.NewClipboard(),
+		initSession: initAzureSession,
+		progress:    newFetchProgress(),
+		runQuery:    RunQuery,
+		workspaces:  newWorkspaceNames(resolveWorkspaceName),
+
+		openURL:          utils.OpenFile,
+		resolveWorkspace: resolveWorkspaceResourceID,
 	}
 
-	widget.settings.RefreshInterval = 60 * time.Second
+	widget.DisplayFunction = widget.content
+	widget.initializeKeyboardControls()
+	widget.EnableRefreshFooter()
 
 	return &widget
 }
@@ -49,229 +112,429 @@ func (widget *Widget) Refresh() {
 		return
 	}
 
-	// Reset state to allow fresh data fetch
-	widget.loading = false
-	widget.lastError = nil
-	widget.dataLoaded = false
-	widget.tableData = nil
-
+	// The previous table is kept so that it stays visible, marked as refreshing, until the
+	// new data arrives
+	widget.load()
 	widget.Redraw(widget.content)
 }
 
 /* -------------------- Helper Functions -------------------- */
 
+// load starts a fetch, unless one is already running
+func (widget *Widget) load() {
+	if widget.StartLoading(loadingMessage) {
+		widget.progress.reset()
+		go widget.fetchDataAsync()
+	}
+}
+
+// fetchDataAsync runs a single fetch. Callers must have started loading, which is done
+// when the fetch ends with either the data or an error
 func (widget *Widget) fetchDataAsync() {
-	sess, err := Init(to.Ptr(widget.settings.Queryfile))
+	sess, err := widget.session()
 	if err != nil {
-		widget.setError(fmt.Errorf("failed to initialize Azure session: %w", err))
+		widget.SetError(fmt.Errorf("failed to initialize Azure session: %w", err))
 		return
 	}
 
-	// Execute Azure query directly
-	tableResp, err := RunQuery(sess)
+	tableResp, err := widget.runQuery(sess, widget.reportProgress)
 	if err != nil {
-		widget.setError(fmt.Errorf("failed to execute Azure query: %w", err))
+		if isAuthError(err) {
+			widget.invalidateSession()
+		}
+		widget.SetError(fmt.Errorf("failed to execute Azure query: %w", err))
 		return
 	}
+	duration := widget.progress.since(StageQuery)
 
 	// Check if we have valid data structure
 	if tableResp == nil || len(tableResp.Header) == 0 {
-		widget.setError(fmt.Errorf("no table structure returned from query"))
+		widget.SetError(fmt.Errorf("no table structure returned from query"))
 		return
 	}
 
+	// Truncation is told from the rows returned, before any are left out
+	truncated := truncatedAt(tableResp, widget.settings.RowCap)
+
+	// Alerts are evaluated on the rows shown, without those below the minimum severity
+	tableResp, belowSeverity := filterSeverity(tableResp, sess.QueryFile)
+
+	alert, err := sess.QueryFile.Alert.Evaluate(tableResp)
+	if err != nil {
+		widget.SetError(fmt.Errorf("failed to evaluate alert: %w", err))
+		return
+	}
+
+	if widget.settings.ShowWorkspaceName {
+		widget.workspaces.show(sess)
+	}
+
 	// Store the data and mark as loaded
+	widget.mu.Lock()
+	if widget.settings.ShowDiff {
+		widget.diff = diffTables(widget.tableData, tableResp, widget.settings.KeyColumns)
+	}
+	widget.alert = alert
+	widget.renderMode = sess.QueryFile.Render
 	widget.tableData = tableResp
-	widget.dataLoaded = true
-	widget.loading = false
-	widget.Redraw(widget.content)
+	widget.belowSeverity = belowSeverity
+	widget.minSeverity = sess.QueryFile.MinSeverity
+	widget.truncated = truncated
+	widget.lastFetchedAt = time.Now()
+	widget.lastDuration = duration
+	widget.toast = ""
+	widget.mu.Unlock()
+
+	widget.SetContent(widget.tableContent)
 }
 
-// setError is a helper function to set error state and trigger redraw
-func (widget *Widget) setError(err error) {
-	widget.lastError = err
-	widget.loading = false
+// reportProgress records the stage the fetch has reached and redraws the loading screen
+func (widget *Widget) reportProgress(stage FetchStage) {
+	widget.progress.advance(stage)
 	widget.Redraw(widget.content)
 }
 
+// session returns the cached session, creating a new one the first time and whenever
+// the query file has changed on disk since the session was created
+func (widget *Widget) session() (*Session, error) {
+	modTime := queryFileModTime(widget.settings.Queryfile)
+
+	widget.mu.Lock()
+	sess, sessModTime := widget.sess, widget.sessModTime
+	widget.mu.Unlock()
+
+	if sess != nil && modTime.Equal(sessModTime) {
+		widget.reportProgress(StageInit)
+		return sess, nil
+	}
+
+	sess, err := widget.initSession(widget.settings.Queryfile, widget.reportProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	widget.mu.Lock()
+	widget.sess = sess
+	widget.sessModTime = modTime
+	widget.mu.Unlock()
+
+	return sess, nil
+}
+
+// invalidateSession drops the cached session and its Logs client, so that the next
+// fetch re-reads the credentials and the query file
+func (widget *Widget) invalidateSession() {
+	widget.mu.Lock()
+	sess := widget.sess
+	widget.sess = nil
+	widget.mu.Unlock()
+
+	if sess != nil {
+		forgetLogsClient(sess.QueryFile.SubscriptionID)
+	}
+}
+
+// initAzureSession is the sessionInitializer that reads the query file and authenticates
+func initAzureSession(queryPath string, progress ProgressFunc) (*Session, error) {
+	return Init(&queryPath, progress)
+}
+
+// queryFileModTime returns the modification time of the query file, or the zero time if
+// it can't be read
+func queryFileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
+
+// tableContent renders the data of the last successful fetch
+func (widget *Widget) tableContent() (string, string, bool) {
+	widget.mu.Lock()
+	defer widget.mu.Unlock()
+
+	return widget.renderTable(widget.title())
+}
+
+// update changes the state of the widget while holding its lock, then redraws it unless
+// change returns false
+func (widget *Widget) update(change func() bool) {
+	widget.mu.Lock()
+	redraw := change()
+	widget.mu.Unlock()
+
+	if redraw {
+		widget.Redraw(widget.content)
+	}
+}
+
+// title returns the title of the widget, followed by the name of the workspace queried
+// when showWorkspaceName is set
+func (widget *Widget) title() string {
+	title := widget.CommonSettings().Title
+	if !widget.settings.ShowWorkspaceName {
+		return title
+	}
+
+	if name := widget.workspaces.current(); name != "" {
+		return fmt.Sprintf("%s — %s", title, tview.Escape(name))
+	}
+
+	return title
+}
+
+// renderTable renders the data of the last fetch. Callers must hold mu
 func (widget *Widget) renderTable(title string) (string, string, bool) {
 	if widget.tableData == nil {
 		return title, "[red]Error: No table data available[white]", true
 	}
 
+	var sb strings.Builder
+
+	sb.WriteString(truncationBanner(widget.truncated))
+
+	// Filter on the full rows, then only keep the visible columns in their display order
+	visible := widget.layout.apply(widget.tableData.Header)
+	shown := filterRowIndices(widget.tableData.Rows, widget.filter)
+	filtered := make([]TableRow, len(shown))
+	for i, idx := range shown {
+		filtered[i] = widget.tableData.Rows[idx]
+	}
+	table := projectTable(&TableResp{Header: widget.tableData.Header, Types: widget.tableData.Types, Rows: filtered}, visible)
+
+	if widget.filter != "" {
+		sb.WriteString(filterIndicator(widget.filter, len(table.Rows), len(widget.tableData.Rows)))
+	}
+
+	if widget.renderMode == renderBarChart {
+		chart, err := formatBarChart(table, widget.availableWidth())
+		if err == nil {
+			return widget.alertTitle(title), sb.String() + chart, false
+		}
+
+		// The data doesn't have a chartable shape, so explain why and fall back to the table
+		_, _ = fmt.Fprintf(&sb, "[yellow]Cannot render bar chart: %v[white]\n\n", err)
+	}
+
 	// Calculate column widths and format table - headers are always shown when available
-	colWidths := calculateAdaptiveColumnWidths(widget.tableData, defaultTableWidth)
+	colWidths := calculateAdaptiveColumnWidths(widget.displayTable(projectTable(widget.tableData, visible)), defaultTableWidth)
 
-	var sb strings.Builder
 	// Always show headers when we have table structure
-	widget.formatTableHeaders(&sb, widget.tableData.Header, colWidths)
-	widget.formatTableSeparator(&sb, widget.tableData.Header, colWidths)
+	widget.formatTableHeaders(&sb, table.Header, colWidths)
+	widget.formatTableSeparator(&sb, table.Header, colWidths)
 
 	// Show data rows if available, otherwise show informative message
-	if len(widget.tableData.Rows) == 0 {
+	switch {
+	case len(widget.tableData.Rows) == 0:
 		sb.WriteString("[dim](No data rows returned)[white]\n")
-	} else {
-		widget.formatTableRows(&sb, widget.tableData.Rows, widget.tableData.Header, colWidths)
+	case len(table.Rows) == 0:
+		sb.WriteString("[dim](No rows match the filter)[white]\n")
+	default:
+		widget.formatTableRows(&sb, table.Rows, widget.diff.marksFor(shown), table.Header, colWidths)
 	}
 
-	return title, sb.String(), false
+	sb.WriteString(widget.removedRows(visible, colWidths))
+
+	sb.WriteString(widget.footer())
+
+	return widget.alertTitle(title), sb.String(), false
 }
 
-// formatTableHeaders writes the table header row to the string builder
-func (widget *Widget) formatTableHeaders(sb *strings.Builder, headers []string, colWidths []int) {
-	for i, header := range headers {
-		if i > 0 {
-			sb.WriteString(" ¦")
-		}
-		headerText := header
-		if i < len(colWidths) && len(headerText) > colWidths[i] {
-			headerText = headerText[:colWidths[i]-len(truncateMarker)] + truncateMarker
-		}
-		_, _ = fmt.Fprintf(sb, "[lightblue]%-*s[white]", colWidths[i], headerText)
+// footer describes how fresh the displayed data is. Callers must hold mu
+func (widget *Widget) footer() string {
+	if widget.Loading() {
+		return "\n[dim]refreshing…[white]\n"
 	}
-	sb.WriteString("\n")
-}
 
-// formatTableSeparator writes the table separator row to the string builder
-func (widget *Widget) formatTableSeparator(sb *strings.Builder, headers []string, colWidths []int) {
-	for i := range headers {
-		if i > 0 {
-			sb.WriteString("---")
-		}
-		sb.WriteString(strings.Repeat("-", colWidths[i]))
+	if widget.lastFetchedAt.IsZero() {
+		return widget.toastLine()
 	}
-	sb.WriteString("\n")
-}
 
-// formatTableRows writes the table data rows to the string builder
-func (widget *Widget) formatTableRows(sb *strings.Builder, rows []TableRow, headers []string, colWidths []int) {
-	maxRows := maxDisplayRows
-	rowCount := len(rows)
-	if rowCount > maxRows {
-		rowCount = maxRows
+	rowCount := 0
+	if widget.tableData != nil {
+		rowCount = len(widget.tableData.Rows)
 	}
 
-	for rowIdx := 0; rowIdx < rowCount; rowIdx++ {
-		row := rows[rowIdx]
-		for colIdx, cell := range row {
-			if colIdx >= len(headers) {
-				break
-			}
+	// The refresh footer already says when the data was fetched
+	updated := "updated " + widget.lastFetchedAt.Format(footerTimeFormat) + " · "
+	if widget.CommonSettings().ShowRefreshFooter {
+		updated = ""
+	}
 
-			if colIdx > 0 {
-				sb.WriteString(" ¦")
-			}
+	return fmt.Sprintf(
+		"\n[dim]%squery took %s · %d rows%s[white]\n%s",
+		updated,
+		formatQueryDuration(widget.lastDuration),
+		rowCount,
+		severityIndicator(widget.belowSeverity, widget.minSeverity),
+		widget.toastLine(),
+	)
+}
 
-			cellText := strings.TrimSpace(cell)
-			if colIdx < len(colWidths) && len(cellText) > colWidths[colIdx] {
-				cellText = cellText[:colWidths[colIdx]-len(truncateMarker)] + truncateMarker
-			}
+// toastLine renders the pending toast message, if any
+func (widget *Widget) toastLine() string {
+	if widget.toast == "" {
+		return ""
+	}
 
-			_, _ = fmt.Fprintf(sb, "%-*s", colWidths[colIdx], cellText)
-		}
-		sb.WriteString("\n")
+	return widget.toast + "\n"
+}
+
+// formatQueryDuration formats a query duration as milliseconds below one second, and as
+// seconds with one decimal otherwise
+func formatQueryDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
 	}
 
-	if len(rows) > maxRows {
-		_, _ = fmt.Fprintf(sb, "\n[gray]... (%d more rows truncated for display)[white]\n", len(rows)-maxRows)
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+// availableWidth returns the inner width of the widget, or the default table width
+// if the widget has not been drawn yet
+func (widget *Widget) availableWidth() int {
+	_, _, width, _ := widget.View.GetInnerRect()
+	if width <= 0 {
+		return defaultTableWidth
 	}
+
+	return width
 }
 
-// calculateAdaptiveColumnWidths computes optimal column widths based on content and available space
-func calculateAdaptiveColumnWidths(tr *TableResp, availableWidth int) []int {
-	if len(tr.Header) == 0 {
-		return []int{}
+// alertTitle decorates the title with the prefix and color of the triggered alert, if any
+func (widget *Widget) alertTitle(title string) string {
+	if widget.alert == nil {
+		return title
 	}
 
-	// Calculate content-based widths
-	widths := make([]int, len(tr.Header))
+	return fmt.Sprintf("[%s]%s %s[white]", widget.alert.Color, widget.alert.Prefix, title)
+}
 
-	// Start with header widths
-	for i, header := range tr.Header {
-		widths[i] = len(header)
+// alertColumn returns the index of the column the triggered alert applies to, or -1
+func (widget *Widget) alertColumn(headers []string) int {
+	if widget.alert == nil {
+		return -1
 	}
 
-	// Check data rows to find maximum content width per column (if any rows exist)
-	if len(tr.Rows) > 0 {
-		maxRows := sampleRowsForWidth // Sample first N rows for width calculation
-		rowCount := len(tr.Rows)
-		if rowCount > maxRows {
-			rowCount = maxRows
-		}
+	return columnIndex(headers, widget.alert.Column)
+}
 
-		for rowIdx := 0; rowIdx < rowCount; rowIdx++ {
-			row := tr.Rows[rowIdx]
-			for colIdx, cell := range row {
-				if colIdx >= len(widths) {
-					break
-				}
-				cellLength := len(strings.TrimSpace(cell))
-				if cellLength > widths[colIdx] {
-					widths[colIdx] = cellLength
-				}
-			}
-		}
+// textTable lays the rows out with the widget's column widths, selection, row numbers,
+// wrapped column, filter highlighting and alert colors
+func (widget *Widget) textTable(rows []TableRow, headers []string, colWidths []int) *view.TextTable {
+	return widget.markedTextTable(rows, nil, headers, colWidths)
+}
+
+// markedTextTable is textTable with the rows marked the way they differ from the previous
+// refresh: new rows in green, and changed numbers in yellow, with how much they changed
+func (widget *Widget) markedTextTable(rows []TableRow, marks []rowMark, headers []string, colWidths []int) *view.TextTable {
+	table := view.NewTextTable().
+		SetHeaders(headers...).
+		SetMaxWidth(widget.availableWidth()).
+		SetMaxRows(maxDisplayRows).
+		SetColumnWidths(colWidths...).
+		SetHeaderSuffix(widget.hiddenMarker()).
+		SetRowNumberWidth(widget.gutterWidth()).
+		SetSelectedColumn(widget.selectedCol).
+		SetSelectedRow(widget.selected, widget.CommonSettings().DefaultFocusedRowColor())
+
+	if widget.settings.WrapColumn != "" {
+		table.SetWrapColumn(columnIndex(headers, widget.settings.WrapColumn))
 	}
 
-	// Apply minimum and maximum constraints
-	totalWidth := 0
-	for i := range widths {
-		if widths[i] < minColumnWidth {
-			widths[i] = minColumnWidth
+	numeric := widget.numericHeaders(headers)
+	table.SetRightAligned(numeric...)
+
+	// Alerts match the values the query returned, rather than the numbers as displayed
+	alertCol := widget.alertColumn(headers)
+	table.SetCellFormatter(func(row, col int, _, text string) string {
+		text = highlightMatch(text, widget.filter)
+		if col == alertCol && widget.alert.Matches(rows[row][col]) {
+			return fmt.Sprintf("[%s]%s[white]", widget.alert.Color, text)
 		}
-		if widths[i] > maxColumnWidth {
-			widths[i] = maxColumnWidth
+		if row < len(marks) {
+			if _, changed := marks[row].deltas[headers[col]]; changed {
+				return fmt.Sprintf("[yellow]%s[white]", text)
+			}
+			if marks[row].added {
+				return fmt.Sprintf("[green]%s[white]", text)
+			}
 		}
-		totalWidth += widths[i]
+		return text
+	})
+
+	for _, row := range widget.withDeltas(widget.displayRows(rows, numeric), headers, marks) {
+		table.AddRow(row...)
 	}
 
-	// Add space for separators: (n-1) * 2 chars for " ¦"
-	separatorSpace := (len(widths) - 1) * 2
-	totalUsed := totalWidth + separatorSpace
+	return table
+}
 
-	// If we exceed available width, proportionally reduce columns
-	if totalUsed > availableWidth {
-		scaleFactor := float64(availableWidth-separatorSpace) / float64(totalWidth)
-		for i := range widths {
-			widths[i] = int(float64(widths[i]) * scaleFactor)
-			if widths[i] < minColumnWidth {
-				widths[i] = minColumnWidth
-			}
-		}
+// displayTable returns the table with its numbers displayed the way numberFormat says, and
+// with how much they changed, to size the columns by
+func (widget *Widget) displayTable(tr *TableResp) *TableResp {
+	rows := widget.displayRows(tr.Rows, widget.numericHeaders(tr.Header))
+	return &TableResp{Header: tr.Header, Types: tr.Types, Rows: widget.withDeltas(rows, tr.Header, widget.diff.allMarks())}
+}
+
+// formatTableHeaders writes the table header row to the string builder
+func (widget *Widget) formatTableHeaders(sb *strings.Builder, headers []string, colWidths []int) {
+	sb.WriteString(widget.textTable(nil, headers, colWidths).RenderHeaders())
+}
+
+// formatTableSeparator writes the table separator row to the string builder
+func (widget *Widget) formatTableSeparator(sb *strings.Builder, headers []string, colWidths []int) {
+	sb.WriteString(widget.textTable(nil, headers, colWidths).RenderSeparator())
+}
+
+// formatTableRows writes the table data rows to the string builder, marked the way they
+// differ from the previous refresh
+func (widget *Widget) formatTableRows(sb *strings.Builder, rows []TableRow, marks []rowMark, headers []string, colWidths []int) {
+	sb.WriteString(widget.markedTextTable(rows, marks, headers, colWidths).RenderRows())
+}
+
+// calculateAdaptiveColumnWidths computes optimal column widths based on content and available space
+func calculateAdaptiveColumnWidths(tr *TableResp, availableWidth int) []int {
+	table := view.NewTextTable().
+		SetHeaders(tr.Header...).
+		SetMaxWidth(availableWidth).
+		SetColumnWidthLimits(minColumnWidth, maxColumnWidth)
+
+	for _, row := range tr.Rows {
+		table.AddRow(row...)
 	}
 
-	return widths
+	return table.ColumnWidths()
 }
 
 /* -------------------- Unexported Functions -------------------- */
 
+// content renders the widget in its current state. It only renders: fetches are started by
+// Refresh
 func (widget *Widget) content() (string, string, bool) {
-	title := widget.CommonSettings().Title
+	widget.mu.Lock()
+	defer widget.mu.Unlock()
+
+	title := widget.title()
 
 	// Check if query file is configured
 	if widget.settings.Queryfile == "" {
 		return title, "[red]Error: queryFile must be configured in widget settings[white]\n\n", false
 	}
 
-	// If we have a previous error, show it immediately
-	if widget.lastError != nil {
-		return title, fmt.Sprintf("[red]Error: %v[white]\n\n[dim]Press 'r' to retry[white]", widget.lastError), true
-	}
-
-	// If data is already loaded, show it
-	if widget.dataLoaded {
+	switch widget.State() {
+	case view.AsyncFailed:
+		return title, fmt.Sprintf("[red]Error: %v[white]\n\n[dim]Press 'r' to retry[white]\n%s", widget.Err(), widget.footer()), true
+	case view.AsyncLoaded:
 		return widget.renderTable(title)
 	}
 
-	// Show loading text while fetching data
-	if !widget.loading {
-		widget.loading = true
-
-		// Start async data fetch
-		go widget.fetchDataAsync()
-		return title, "[yellow]Loading Azure Logs data...[white]\n\n[dim]• Initializing Azure session\n• Executing query on workspace\n• Processing results[white]", false
+	// Keep showing the previous data, if any, while the new data loads
+	if widget.tableData != nil {
+		return widget.renderTable(title)
 	}
 
-	// Still loading, show loading text
-	return title, "[yellow]Loading Azure Logs data...[white]\n\n[dim]• Initializing Azure session\n• Executing query on workspace\n• Processing results[white]", false
+	return title, widget.progress.render(), false
 }
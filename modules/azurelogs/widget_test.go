@@ -1,13 +1,18 @@
 package azurelogs
 
 import (
+	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/rivo/tview"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/wtfutil/wtf/cfg"
+	"github.com/wtfutil/wtf/utils"
+	"github.com/wtfutil/wtf/view"
 )
 
 func TestNewWidget(t *testing.T) {
@@ -16,7 +21,8 @@ func TestNewWidget(t *testing.T) {
 
 	settings := &Settings{
 		Common: &cfg.Common{
-			Title: "Test Azure Logs",
+			RefreshInterval: 5 * time.Minute,
+			Title:           "Test Azure Logs",
 		},
 		Queryfile: "/path/to/query.yml",
 	}
@@ -25,10 +31,9 @@ func TestNewWidget(t *testing.T) {
 
 	assert.NotNil(t, widget)
 	assert.Equal(t, settings, widget.settings)
-	assert.Equal(t, 60*time.Second, widget.settings.RefreshInterval)
-	assert.False(t, widget.loading)
-	assert.False(t, widget.dataLoaded)
-	assert.Nil(t, widget.lastError)
+	assert.Equal(t, 5*time.Minute, widget.settings.RefreshInterval)
+	assert.Equal(t, view.AsyncIdle, widget.State())
+	assert.Nil(t, widget.Err())
 	assert.Nil(t, widget.tableData)
 }
 
@@ -37,13 +42,14 @@ func TestNewWidget(t *testing.T) {
 
 func TestWidget_SetError(t *testing.T) {
 	widget := createTestWidget()
-	widget.loading = true
+	drainRedraws(t, widget)
+	widget.StartLoading(loadingMessage)
 
 	testError := assert.AnError
-	widget.setError(testError)
+	widget.SetError(testError)
 
-	assert.Equal(t, testError, widget.lastError)
-	assert.False(t, widget.loading)
+	assert.Equal(t, testError, widget.Err())
+	assert.False(t, widget.Loading())
 }
 
 func TestWidget_RenderTable(t *testing.T) {
@@ -145,7 +151,7 @@ func TestWidget_FormatTableRows(t *testing.T) {
 		{"LongData", "Short"},
 	}
 
-	widget.formatTableRows(&sb, rows, headers, colWidths)
+	widget.formatTableRows(&sb, rows, nil, headers, colWidths)
 
 	result := sb.String()
 	lines := strings.Split(strings.TrimSpace(result), "\n")
@@ -169,7 +175,7 @@ func TestWidget_FormatTableRows_WithTruncation(t *testing.T) {
 		rows[i] = TableRow{"data1", "data2"}
 	}
 
-	widget.formatTableRows(&sb, rows, headers, colWidths)
+	widget.formatTableRows(&sb, rows, nil, headers, colWidths)
 
 	result := sb.String()
 	assert.Contains(t, result, "more rows truncated")
@@ -179,9 +185,7 @@ func TestWidget_Content(t *testing.T) {
 	tests := []struct {
 		name             string
 		queryfile        string
-		lastError        error
-		dataLoaded       bool
-		loading          bool
+		state            view.AsyncState
 		expectedTitle    string
 		expectedContains string
 	}{
@@ -194,28 +198,28 @@ func TestWidget_Content(t *testing.T) {
 		{
 			name:             "has error",
 			queryfile:        "/path/to/query.yml",
-			lastError:        assert.AnError,
+			state:            view.AsyncFailed,
 			expectedTitle:    "Test Azure Logs",
 			expectedContains: "[red]Error:",
 		},
 		{
 			name:             "data loaded",
 			queryfile:        "/path/to/query.yml",
-			dataLoaded:       true,
+			state:            view.AsyncLoaded,
 			expectedTitle:    "Test Azure Logs",
 			expectedContains: "[red]Error: No table data available", // Since tableData is nil
 		},
 		{
 			name:             "loading state",
 			queryfile:        "/path/to/query.yml",
-			loading:          false, // Will trigger loading
+			state:            view.AsyncIdle, // Waiting for the first refresh
 			expectedTitle:    "Test Azure Logs",
 			expectedContains: "[yellow]Loading Azure Logs data",
 		},
 		{
 			name:             "still loading",
 			queryfile:        "/path/to/query.yml",
-			loading:          true,
+			state:            view.AsyncLoading,
 			expectedTitle:    "Test Azure Logs",
 			expectedContains: "[yellow]Loading Azure Logs data",
 		},
@@ -225,9 +229,7 @@ func TestWidget_Content(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			widget := createTestWidget()
 			widget.settings.Queryfile = tt.queryfile
-			widget.lastError = tt.lastError
-			widget.dataLoaded = tt.dataLoaded
-			widget.loading = tt.loading
+			setState(t, widget, tt.state)
 
 			title, content, _ := widget.content()
 
@@ -237,6 +239,82 @@ func TestWidget_Content(t *testing.T) {
 	}
 }
 
+func TestWidget_Content_Footer(t *testing.T) {
+	fetchedAt := time.Date(2024, 3, 5, 14, 2, 11, 0, time.Local)
+	rows := make([]TableRow, maxDisplayRows+93)
+	for i := range rows {
+		rows[i] = TableRow{"value"}
+	}
+
+	tests := []struct {
+		name                string
+		state               view.AsyncState
+		lastFetchedAt       time.Time
+		expectedContains    string
+		expectedNotContains string
+	}{
+		{
+			name:             "loaded",
+			state:            view.AsyncLoaded,
+			lastFetchedAt:    fetchedAt,
+			expectedContains: "[dim]updated 14:02:11 · query took 1.8s · 143 rows[white]",
+		},
+		{
+			name:                "refreshing with previous data",
+			state:               view.AsyncLoading,
+			lastFetchedAt:       fetchedAt,
+			expectedContains:    "[dim]refreshing…[white]",
+			expectedNotContains: "updated 14:02:11",
+		},
+		{
+			name:             "error after a previous success",
+			state:            view.AsyncFailed,
+			lastFetchedAt:    fetchedAt,
+			expectedContains: "updated 14:02:11",
+		},
+		{
+			name:                "error without a previous success",
+			state:               view.AsyncFailed,
+			expectedContains:    "[red]Error:",
+			expectedNotContains: "updated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			widget := createTestWidget()
+			widget.tableData = &TableResp{Header: []string{"Col"}, Rows: rows}
+			setState(t, widget, tt.state)
+			widget.lastFetchedAt = tt.lastFetchedAt
+			widget.lastDuration = 1800 * time.Millisecond
+
+			_, content, _ := widget.content()
+
+			assert.Contains(t, content, tt.expectedContains)
+			if tt.expectedNotContains != "" {
+				assert.NotContains(t, content, tt.expectedNotContains)
+			}
+		})
+	}
+}
+
+func TestWidget_Footer_RefreshFooter(t *testing.T) {
+	widget := createTestWidget()
+	widget.tableData = &TableResp{Header: []string{"Col"}, Rows: []TableRow{{"value"}}}
+	widget.lastFetchedAt = time.Date(2024, 3, 5, 14, 2, 11, 0, time.Local)
+	widget.lastDuration = 1800 * time.Millisecond
+
+	// The refresh footer says when the data was fetched, so the widget's own footer doesn't
+	widget.CommonSettings().ShowRefreshFooter = true
+	assert.Equal(t, "\n[dim]query took 1.8s · 1 rows[white]\n", widget.footer())
+}
+
+func TestFormatQueryDuration(t *testing.T) {
+	assert.Equal(t, "340ms", formatQueryDuration(340*time.Millisecond))
+	assert.Equal(t, "1.8s", formatQueryDuration(1800*time.Millisecond))
+	assert.Equal(t, "12.0s", formatQueryDuration(12*time.Second))
+}
+
 func TestCalculateAdaptiveColumnWidths(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -332,3 +410,163 @@ func createTestWidget() *Widget {
 
 	return NewWidget(app, redrawChan, nil, settings)
 }
+
+// setState moves the widget into a state the way a fetch would, failing with assert.AnError
+func setState(t *testing.T, widget *Widget, state view.AsyncState) {
+	t.Helper()
+
+	drainRedraws(t, widget)
+
+	if state == view.AsyncIdle {
+		return
+	}
+
+	widget.StartLoading(loadingMessage)
+
+	switch state {
+	case view.AsyncLoaded:
+		widget.SetContent(widget.tableContent)
+	case view.AsyncFailed:
+		widget.SetError(assert.AnError)
+	}
+}
+
+// drainRedraws consumes redraw requests for the rest of the test, so that code paths which
+// redraw more than once don't block on the buffered channel
+func drainRedraws(t *testing.T, widget *Widget) {
+	t.Helper()
+
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+
+	go func() {
+		for {
+			select {
+			case <-widget.RedrawChan:
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+func TestWidget_Refresh_SingleFlight(t *testing.T) {
+	widget := createTestWidget()
+	drainRedraws(t, widget)
+
+	var queries atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	widget.initSession = func(_ string, _ ProgressFunc) (*Session, error) {
+		return &Session{QueryFile: QueryFile{Columns: []string{"Level"}}}, nil
+	}
+	widget.runQuery = func(_ *Session, _ ProgressFunc) (*TableResp, error) {
+		if queries.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return &TableResp{Header: []string{"Level"}, Rows: []TableRow{{"Error"}}}, nil
+	}
+
+	widget.Refresh()
+	<-started
+	widget.Refresh()
+
+	close(release)
+	assert.Eventually(t, func() bool { return !widget.Loading() }, time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, int32(1), queries.Load())
+}
+
+func TestWidget_Session_Cached(t *testing.T) {
+	widget := createTestWidget()
+	drainRedraws(t, widget)
+	widget.settings.Queryfile = writeTempQueryFile(t, "query: AzureActivity")
+
+	inits := 0
+	widget.initSession = func(_ string, _ ProgressFunc) (*Session, error) {
+		inits++
+		return &Session{QueryFile: QueryFile{SubscriptionID: "sub"}}, nil
+	}
+
+	first, err := widget.session()
+	require.NoError(t, err)
+	second, err := widget.session()
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, inits)
+	assert.Same(t, first, second)
+
+	// A changed query file invalidates the session
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(widget.settings.Queryfile, later, later))
+
+	_, err = widget.session()
+	require.NoError(t, err)
+	assert.Equal(t, 2, inits)
+
+	// So does an authentication error
+	widget.invalidateSession()
+	_, err = widget.session()
+	require.NoError(t, err)
+	assert.Equal(t, 3, inits)
+}
+
+func TestWidget_Content_DoesNotFetch(t *testing.T) {
+	widget := createTestWidget()
+	drainRedraws(t, widget)
+
+	var sessions atomic.Int32
+	widget.initSession = func(_ string, _ ProgressFunc) (*Session, error) {
+		sessions.Add(1)
+		return &Session{}, nil
+	}
+
+	// Only Refresh starts a fetch, however often the widget is drawn before it
+	widget.content()
+	widget.next()
+	widget.selectColumn(0)
+
+	assert.Equal(t, view.AsyncIdle, widget.State())
+	assert.Equal(t, int32(0), sessions.Load())
+}
+
+func TestWidget_KeysDuringFetch(t *testing.T) {
+	widget := createTestWidget()
+	drainRedraws(t, widget)
+	widget.settings.ShowDiff = true
+	widget.clipboard = &utils.FakeClipboard{}
+
+	widget.initSession = func(_ string, _ ProgressFunc) (*Session, error) {
+		return &Session{QueryFile: QueryFile{}}, nil
+	}
+	widget.runQuery = func(_ *Session, progress ProgressFunc) (*TableResp, error) {
+		progress.report(StageQuery)
+		return &TableResp{
+			Header: []string{"Level", "Computer", "Message"},
+			Rows:   []TableRow{{"Error", "web-01", "disk full"}, {"Warning", "web-02", "slow"}},
+		}, nil
+	}
+
+	// Run under -race: the keys change the selection, filter and layout while fetches
+	// replace the data the widget is rendered from
+	for i := 0; i < 20; i++ {
+		widget.Refresh()
+
+		widget.next()
+		widget.nextColumn()
+		widget.moveColumnRight()
+		widget.setFilter("web")
+		widget.hideColumn()
+		widget.unhideColumns()
+		widget.clearFilter()
+		widget.copySelectedRow()
+		widget.prev()
+
+		assert.Eventually(t, func() bool { return !widget.Loading() }, time.Second, time.Millisecond)
+	}
+
+	_, content, _ := widget.content()
+	assert.Contains(t, content, "disk full")
+}
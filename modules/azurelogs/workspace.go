@@ -0,0 +1,171 @@
+package azurelogs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	resourceGraphPath       = "/providers/Microsoft.ResourceGraph/resources"
+	resourceGraphAPIVersion = "2021-03-01"
+	workspaceNameTimeout    = 10 * time.Second
+)
+
+// workspaceIDPattern matches workspace IDs, which are GUIDs. Only those are looked up, so
+// that nothing else ends up in the Resource Graph query
+var workspaceIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// workspaceNameResolver looks up the display name of a Log Analytics workspace by its ID
+type workspaceNameResolver func(sess *Session, workspaceID string) (string, error)
+
+// workspaceNames keeps the names of the workspaces queried, each looked up once, and the
+// name of the one queried last, for the title
+type workspaceNames struct {
+	mu      sync.Mutex
+	resolve workspaceNameResolver
+	// names are by workspace ID. A workspace whose name couldn't be looked up is named
+	// after its ID, so that it isn't looked up again
+	names map[string]string
+	shown string
+}
+
+func newWorkspaceNames(resolve workspaceNameResolver) *workspaceNames {
+	return &workspaceNames{
+		resolve: resolve,
+		names:   map[string]string{},
+	}
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// show makes the workspace the session queries the one named in the title, looking its
+// name up the first time. Queries of a resource rather than a workspace name none
+func (names *workspaceNames) show(sess *Session) {
+	workspaceID := sess.QueryFile.WorkspaceID
+	if sess.QueryFile.ResourceID != "" || workspaceID == "" {
+		names.setShown("")
+		return
+	}
+
+	names.mu.Lock()
+	name, ok := names.names[workspaceID]
+	names.mu.Unlock()
+
+	if !ok {
+		name = workspaceID
+		if resolved, err := names.resolve(sess, workspaceID); err == nil && resolved != "" {
+			name = resolved
+		}
+
+		names.mu.Lock()
+		names.names[workspaceID] = name
+		names.mu.Unlock()
+	}
+
+	names.setShown(name)
+}
+
+func (names *workspaceNames) setShown(name string) {
+	names.mu.Lock()
+	defer names.mu.Unlock()
+
+	names.shown = name
+}
+
+// current returns the name of the workspace queried last, or "" when there's none
+func (names *workspaceNames) current() string {
+	names.mu.Lock()
+	defer names.mu.Unlock()
+
+	return names.shown
+}
+
+// resolveWorkspaceName is the workspaceNameResolver that looks the workspace up in the
+// Azure Resource Graph of the session's subscription, with the session's credential
+func resolveWorkspaceName(sess *Session, workspaceID string) (string, error) {
+	return lookupWorkspace(sess, workspaceID, "name")
+}
+
+// resolveWorkspaceResourceID is the workspaceResourceResolver that looks the workspace up
+// in the Azure Resource Graph, like resolveWorkspaceName
+func resolveWorkspaceResourceID(sess *Session, workspaceID string) (string, error) {
+	return lookupWorkspace(sess, workspaceID, "id")
+}
+
+// lookupWorkspace returns a property of the workspace, such as its name, from the Azure
+// Resource Graph of the session's subscription
+func lookupWorkspace(sess *Session, workspaceID, property string) (string, error) {
+	if sess.Azure == nil || sess.Azure.Credential == nil {
+		return "", fmt.Errorf("azure credentials not initialized")
+	}
+
+	if !workspaceIDPattern.MatchString(workspaceID) {
+		return "", fmt.Errorf("invalid workspace ID %q", workspaceID)
+	}
+
+	client, err := arm.NewClient("azurelogs", "v1.0.0", sess.Azure.Credential, nil)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), workspaceNameTimeout)
+	defer cancel()
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, runtime.JoinPaths(client.Endpoint(), resourceGraphPath))
+	if err != nil {
+		return "", err
+	}
+
+	query := req.Raw().URL.Query()
+	query.Set("api-version", resourceGraphAPIVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+
+	body := map[string]interface{}{
+		"query": fmt.Sprintf(
+			"resources | where type =~ 'microsoft.operationalinsights/workspaces' and properties.customerId =~ '%s' | project value = %s",
+			workspaceID, property,
+		),
+		"options": map[string]string{"resultFormat": "objectArray"},
+	}
+	if sess.QueryFile.SubscriptionID != "" {
+		body["subscriptions"] = []string{sess.QueryFile.SubscriptionID}
+	}
+
+	err = runtime.MarshalAsJSON(req, body)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Pipeline().Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return "", runtime.NewResponseError(resp)
+	}
+
+	var result struct {
+		Data []struct {
+			Value string `json:"value"`
+		} `json:"data"`
+	}
+
+	err = runtime.UnmarshalAsJSON(resp, &result)
+	if err != nil {
+		return "", err
+	}
+
+	if len(result.Data) == 0 {
+		return "", fmt.Errorf("workspace %s not found", workspaceID)
+	}
+
+	return result.Data[0].Value, nil
+}
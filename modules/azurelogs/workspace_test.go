@@ -0,0 +1,143 @@
+package azurelogs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+const testWorkspaceID = "3f1b9c2e-8d4a-4e6f-9a7b-1c2d3e4f5a6b"
+
+// fakeCredential is a credential that never gets a token
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{}, errors.New("no token")
+}
+
+// createWorkspaceWidget returns a widget that queries the workspace with a fake session,
+// and looks its name up with resolve
+func createWorkspaceWidget(t *testing.T, resolve workspaceNameResolver) *Widget {
+	t.Helper()
+
+	widget := createTestWidget()
+	drainRedraws(t, widget)
+
+	widget.settings.ShowWorkspaceName = true
+	widget.workspaces = newWorkspaceNames(resolve)
+	widget.initSession = func(_ string, _ ProgressFunc) (*Session, error) {
+		return &Session{QueryFile: QueryFile{WorkspaceID: testWorkspaceID, Columns: []string{"Level"}}}, nil
+	}
+	widget.runQuery = func(_ *Session, _ ProgressFunc) (*TableResp, error) {
+		return &TableResp{Header: []string{"Level"}, Rows: []TableRow{{"Error"}}}, nil
+	}
+
+	return widget
+}
+
+// refreshAndWait refreshes the widget and waits for the fetch to end
+func refreshAndWait(t *testing.T, widget *Widget) {
+	t.Helper()
+
+	widget.Refresh()
+	assert.Eventually(t, func() bool { return !widget.Loading() }, time.Second, 5*time.Millisecond)
+}
+
+func TestWorkspaceName_Resolved(t *testing.T) {
+	widget := createWorkspaceWidget(t, func(_ *Session, workspaceID string) (string, error) {
+		assert.Equal(t, testWorkspaceID, workspaceID)
+		return "prod-la-weu", nil
+	})
+
+	refreshAndWait(t, widget)
+
+	title, _, _ := widget.content()
+	assert.Equal(t, "Test Azure Logs — prod-la-weu", title)
+}
+
+func TestWorkspaceName_Failed(t *testing.T) {
+	widget := createWorkspaceWidget(t, func(_ *Session, _ string) (string, error) {
+		return "", errors.New("403 Forbidden")
+	})
+
+	refreshAndWait(t, widget)
+
+	title, content, hasError := widget.content()
+	assert.Equal(t, "Test Azure Logs — "+testWorkspaceID, title)
+	assert.False(t, hasError)
+	assert.NotContains(t, content, "Forbidden")
+	assert.NoError(t, widget.Err())
+}
+
+func TestWorkspaceName_Cached(t *testing.T) {
+	var lookups atomic.Int32
+	widget := createWorkspaceWidget(t, func(_ *Session, _ string) (string, error) {
+		if lookups.Add(1) > 1 {
+			return "", errors.New("looked up again")
+		}
+		return "prod-la-weu", nil
+	})
+
+	refreshAndWait(t, widget)
+	refreshAndWait(t, widget)
+	refreshAndWait(t, widget)
+
+	assert.Equal(t, int32(1), lookups.Load())
+	title, _, _ := widget.content()
+	assert.Equal(t, "Test Azure Logs — prod-la-weu", title)
+}
+
+func TestWorkspaceName_FailureCached(t *testing.T) {
+	var lookups atomic.Int32
+	widget := createWorkspaceWidget(t, func(_ *Session, _ string) (string, error) {
+		lookups.Add(1)
+		return "", errors.New("timeout")
+	})
+
+	refreshAndWait(t, widget)
+	refreshAndWait(t, widget)
+
+	assert.Equal(t, int32(1), lookups.Load())
+}
+
+func TestWorkspaceName_Hidden(t *testing.T) {
+	var lookups atomic.Int32
+	widget := createWorkspaceWidget(t, func(_ *Session, _ string) (string, error) {
+		lookups.Add(1)
+		return "prod-la-weu", nil
+	})
+	widget.settings.ShowWorkspaceName = false
+
+	refreshAndWait(t, widget)
+
+	assert.Equal(t, int32(0), lookups.Load())
+	title, _, _ := widget.content()
+	assert.Equal(t, "Test Azure Logs", title)
+}
+
+func TestWorkspaceNames_Resource(t *testing.T) {
+	names := newWorkspaceNames(func(_ *Session, _ string) (string, error) {
+		return "prod-la-weu", nil
+	})
+
+	names.show(&Session{QueryFile: QueryFile{WorkspaceID: testWorkspaceID}})
+	assert.Equal(t, "prod-la-weu", names.current())
+
+	// Resources have no workspace to name
+	names.show(&Session{QueryFile: QueryFile{ResourceID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/sites/app"}})
+	assert.Equal(t, "", names.current())
+}
+
+func TestResolveWorkspaceName_Invalid(t *testing.T) {
+	_, err := resolveWorkspaceName(&Session{Azure: &AZSession{}}, testWorkspaceID)
+	assert.ErrorContains(t, err, "credentials not initialized")
+
+	_, err = resolveWorkspaceName(&Session{Azure: &AZSession{Credential: fakeCredential{}}}, "x' or 1==1")
+	assert.ErrorContains(t, err, "invalid workspace ID")
+}
@@ -0,0 +1,31 @@
+package azurelogs
+
+import (
+	"strconv"
+
+	"github.com/wtfutil/wtf/utils"
+)
+
+// padTagged pads text with tview tags with spaces until it takes up width cells. The tags,
+// the escaping of brackets and wide runes are all accounted for, so that columns line up
+func padTagged(text string, width int) string {
+	return text + utils.RowPadding(utils.DisplayWidth(text), width)
+}
+
+// cellWidth returns how many terminal cells text takes up when it is displayed as is
+func cellWidth(text string) int {
+	return utils.DisplayWidth(utils.EscapeTview(text))
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// gutterWidth returns the width of the row number column, including its trailing space,
+// or zero when row numbers are disabled
+func (widget *Widget) gutterWidth() int {
+	if !widget.settings.ShowRowNumbers || widget.tableData == nil {
+		return 0
+	}
+
+	rowCount := min(len(widget.tableData.Rows), maxDisplayRows)
+	return len(strconv.Itoa(max(rowCount, 1))) + 1
+}
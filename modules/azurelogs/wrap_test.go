@@ -0,0 +1,70 @@
+package azurelogs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWidget_FormatTableRows_WrapColumn(t *testing.T) {
+	widget := createTestWidget()
+	widget.settings.WrapColumn = "Message"
+
+	headers := []string{"Level", "Message", "Computer"}
+	colWidths := []int{8, 12, 8}
+	rows := []TableRow{
+		{"Error", "[ERROR] disk [red] is full on /var", "web-01"},
+	}
+
+	var sb strings.Builder
+	widget.formatTableRows(&sb, rows, nil, headers, colWidths)
+
+	lines := strings.Split(strings.TrimRight(sb.String(), "\n"), "\n")
+	assert.Len(t, lines, 3)
+
+	// Brackets are escaped so tview displays them instead of treating them as tags
+	assert.Contains(t, lines[0], "[ERROR[] disk")
+	assert.Contains(t, lines[0], "web-01")
+	assert.Contains(t, lines[1], "[red[] is")
+
+	// Continuation lines start under the wrapped column
+	indent := colWidths[0] + len([]rune(" ¦"))
+	assert.Equal(t, strings.Repeat(" ", indent), lines[1][:indent])
+	assert.NotEqual(t, " ", string(lines[1][indent]))
+}
+
+func TestWidget_FormatTableRows_WrapCountsAgainstRowCap(t *testing.T) {
+	widget := createTestWidget()
+	widget.settings.WrapColumn = "Message"
+
+	rows := make([]TableRow, maxDisplayRows+5)
+	for i := range rows {
+		rows[i] = TableRow{"Error", "a message long enough to wrap onto several continuation lines"}
+	}
+
+	var sb strings.Builder
+	widget.formatTableRows(&sb, rows, nil, []string{"Level", "Message"}, []int{8, 10})
+
+	assert.Contains(t, sb.String(), "(5 more rows truncated for display)")
+}
+
+func TestWidget_FormatTableRows_RowNumbers(t *testing.T) {
+	widget := createTestWidget()
+	widget.settings.ShowRowNumbers = true
+
+	rows := make([]TableRow, 12)
+	for i := range rows {
+		rows[i] = TableRow{"value"}
+	}
+	widget.tableData = &TableResp{Header: []string{"Col"}, Rows: rows}
+
+	var sb strings.Builder
+	widget.formatTableHeaders(&sb, widget.tableData.Header, []int{8})
+	widget.formatTableRows(&sb, rows, nil, widget.tableData.Header, []int{8})
+
+	lines := strings.Split(strings.TrimRight(sb.String(), "\n"), "\n")
+	assert.True(t, strings.HasPrefix(lines[0], "   [lightblue]Col"))
+	assert.True(t, strings.HasPrefix(lines[1], " 1 value"))
+	assert.True(t, strings.HasPrefix(lines[12], "12 value"))
+}
@@ -0,0 +1,64 @@
+package feedreader
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wtfutil/wtf/utils"
+)
+
+const (
+	// ageWidth fits the longest age utils.RelativeAge returns for items up to a year old,
+	// such as "11mo29d"
+	ageWidth = 7
+	noAge    = "—"
+
+	// relativeDateFormat is the dateFormat that shows how long ago items were published,
+	// such as "3 hours ago"
+	relativeDateFormat = "relative"
+)
+
+// nowFunc is replaceable in tests
+var nowFunc = time.Now
+
+/* -------------------- Unexported Functions -------------------- */
+
+// ageColumn returns the item's age right-aligned in a fixed-width column, colored by how
+// fresh it is. Ages between the fresh and stale thresholds keep the row's color
+func (widget *Widget) ageColumn(feedItem *FeedItem, rowColor string) string {
+	date := feedItem.date()
+	if date == nil {
+		return fmt.Sprintf("%*s ", ageWidth, noAge)
+	}
+
+	age := nowFunc().Sub(*date)
+	text := fmt.Sprintf("%*s", ageWidth, utils.RelativeAge(age, true))
+
+	switch {
+	case age < widget.settings.ageFreshUnder:
+		return fmt.Sprintf("[%s]%s[%s] ", widget.settings.colors.ageFresh, text, rowColor)
+	case age >= widget.settings.ageStaleAfter:
+		return fmt.Sprintf("[%s]%s[%s] ", widget.settings.colors.ageStale, text, rowColor)
+	default:
+		return text + " "
+	}
+}
+
+// formatPublishDate returns when the item was published, or last updated, in the date format,
+// or "" when dates aren't shown or the item has neither
+func (widget *Widget) formatPublishDate(feedItem *FeedItem) string {
+	if !widget.settings.showPublishDate {
+		return ""
+	}
+
+	date := feedItem.date()
+	if date == nil {
+		return ""
+	}
+
+	if widget.settings.dateFormat == relativeDateFormat {
+		return utils.RelativeAge(nowFunc().Sub(*date), false)
+	}
+
+	return date.Format(widget.settings.dateFormat)
+}
@@ -0,0 +1,71 @@
+package feedreader
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"gotest.tools/assert"
+)
+
+func TestContent_ShowAge(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	originalNow := nowFunc
+	defer func() { nowFunc = originalNow }()
+	nowFunc = func() time.Time { return now }
+
+	at := func(age time.Duration) *time.Time {
+		date := now.Add(-age)
+		return &date
+	}
+
+	stories := []*FeedItem{
+		{item: &gofeed.Item{Title: "Fresh", PublishedParsed: at(3 * time.Hour)}, sourceTitle: "WTF"},
+		{item: &gofeed.Item{Title: "Recent", UpdatedParsed: at(30 * time.Hour)}, sourceTitle: "WTF"},
+		{item: &gofeed.Item{Title: "Stale", PublishedParsed: at(5 * 24 * time.Hour)}, sourceTitle: "WTF"},
+		{item: &gofeed.Item{Title: "Undated"}, sourceTitle: "WTF"},
+	}
+
+	tests := []struct {
+		name       string
+		showSource bool
+		expected   []string
+	}{
+		{
+			name:       "without source titles",
+			showSource: false,
+			expected: []string{
+				" 1. [green]     3h[white:transparent] [white:transparent]Fresh",
+				" 2.    1d6h [white:transparent]Recent",
+				" 3. [gray]     5d[white:transparent] [white:transparent]Stale",
+				" 4.       — [white:transparent]Undated",
+			},
+		},
+		{
+			name:       "with source titles",
+			showSource: true,
+			expected: []string{
+				" 1. [green]     3h[white:transparent] [green]WTF [white:transparent]Fresh",
+				" 2.    1d6h [green]WTF [white:transparent]Recent",
+				" 3. [gray]     5d[white:transparent] [green]WTF [white:transparent]Stale",
+				" 4.       — [green]WTF [white:transparent]Undated",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := newTestSettings(t, "showAge: true\ncolors:\n  rows:\n    even: white\n    odd: white")
+			settings.showSource = tt.showSource
+			widget := newTestWidget(t, settings)
+			widget.stories = stories
+
+			_, content, _ := widget.content()
+
+			for _, expected := range tt.expected {
+				assert.Assert(t, strings.Contains(content, expected), "missing %q in %s", expected, content)
+			}
+		})
+	}
+}
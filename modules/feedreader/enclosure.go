@@ -0,0 +1,76 @@
+package feedreader
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/wtfutil/wtf/utils"
+)
+
+// runPlayerCommand starts the player command without waiting for it to finish. It is
+// replaceable in tests
+var runPlayerCommand = func(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	go func() { _ = cmd.Wait() }()
+
+	return nil
+}
+
+// enclosure returns the item's enclosure to open, preferring audio over other media, or nil
+// if the item has none
+func (feedItem *FeedItem) enclosure() *gofeed.Enclosure {
+	var first *gofeed.Enclosure
+
+	for _, enclosure := range feedItem.item.Enclosures {
+		if enclosure == nil || enclosure.URL == "" {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(enclosure.Type), "audio/") {
+			return enclosure
+		}
+		if first == nil {
+			first = enclosure
+		}
+	}
+
+	return first
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// openEnclosure opens the selected item's enclosure in the browser, or passes its URL to
+// the player command if there is one
+func (widget *Widget) openEnclosure() {
+	story := widget.selectedStory()
+	if story == nil {
+		return
+	}
+
+	enclosure := story.enclosure()
+	if enclosure == nil {
+		return
+	}
+
+	widget.markRead([]*FeedItem{story})
+
+	command := strings.Fields(widget.settings.playerCommand)
+	if len(command) == 0 {
+		utils.OpenFile(enclosure.URL)
+		return
+	}
+
+	args := append(command[1:len(command):len(command)], enclosure.URL)
+	if err := runPlayerCommand(command[0], args...); err != nil {
+		widget.playerErr = fmt.Errorf("player command failed: %w", err)
+	} else {
+		widget.playerErr = nil
+	}
+
+	widget.Render()
+}
@@ -0,0 +1,106 @@
+package feedreader
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"gotest.tools/assert"
+)
+
+const podcastFeed = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Podcast</title>
+<item><title>Show notes</title><guid>notes</guid></item>
+<item><title>Episode 1</title><guid>ep1</guid>
+  <enclosure url="https://example.com/ep1.mp3" length="1024" type="audio/mpeg"/></item>
+<item><title>Cover art</title><guid>cover</guid>
+  <enclosure url="https://example.com/cover.jpg" length="512" type="image/jpeg"/></item>
+</channel></rss>`
+
+func enclosureTestStories(t *testing.T) []*FeedItem {
+	t.Helper()
+
+	feed, err := gofeed.NewParser().ParseString(podcastFeed)
+	assert.NilError(t, err)
+
+	stories := []*FeedItem{}
+	for _, item := range feed.Items {
+		stories = append(stories, &FeedItem{item: item, sourceTitle: feed.Title})
+	}
+
+	return stories
+}
+
+func TestFeedItem_Enclosure(t *testing.T) {
+	stories := enclosureTestStories(t)
+
+	assert.Assert(t, stories[0].enclosure() == nil)
+	assert.Equal(t, "https://example.com/ep1.mp3", stories[1].enclosure().URL)
+	assert.Equal(t, "https://example.com/cover.jpg", stories[2].enclosure().URL)
+}
+
+func TestFeedItem_EnclosurePrefersAudio(t *testing.T) {
+	feedItem := &FeedItem{item: &gofeed.Item{Enclosures: []*gofeed.Enclosure{
+		{URL: "https://example.com/cover.jpg", Type: "image/jpeg"},
+		{URL: "", Type: "audio/mpeg"},
+		{URL: "https://example.com/ep.m4a", Type: "Audio/MP4"},
+	}}}
+
+	assert.Equal(t, "https://example.com/ep.m4a", feedItem.enclosure().URL)
+}
+
+func TestStoryRows_EnclosureMarker(t *testing.T) {
+	widget := newTestWidget(t, &Settings{enclosureMarker: "♪"})
+	widget.stories = enclosureTestStories(t)
+	widget.SetItemCount(len(widget.stories))
+
+	rows := strings.Split(widget.storyRows(widget.stories), "\n")
+
+	assert.Assert(t, !strings.Contains(rows[0], "♪"), rows[0])
+	assert.Assert(t, strings.Contains(rows[1], " 2. ♪ [:]Episode 1"), rows[1])
+	assert.Assert(t, strings.Contains(rows[2], " 3. ♪ [:]Cover art"), rows[2])
+}
+
+func TestOpenEnclosure_PlayerCommand(t *testing.T) {
+	calls := []notifyCall{}
+	originalRun := runPlayerCommand
+	t.Cleanup(func() { runPlayerCommand = originalRun })
+	runPlayerCommand = func(name string, args ...string) error {
+		calls = append(calls, notifyCall{Name: name, Args: args})
+		return nil
+	}
+
+	widget := newTestWidget(t, &Settings{playerCommand: "mpv --no-video"})
+	widget.stories = enclosureTestStories(t)
+	widget.SetItemCount(len(widget.stories))
+
+	// Items without enclosures are left alone
+	widget.Selected = 0
+	widget.openEnclosure()
+	assert.Equal(t, 0, len(calls))
+	assert.Assert(t, !widget.stories[0].viewed)
+
+	widget.Selected = 1
+	widget.openEnclosure()
+	assert.DeepEqual(t, []notifyCall{{Name: "mpv", Args: []string{"--no-video", "https://example.com/ep1.mp3"}}}, calls)
+	assert.Assert(t, widget.stories[1].viewed)
+}
+
+func TestOpenEnclosure_PlayerCommandFails(t *testing.T) {
+	originalRun := runPlayerCommand
+	t.Cleanup(func() { runPlayerCommand = originalRun })
+	runPlayerCommand = func(string, ...string) error {
+		return errors.New("executable file not found")
+	}
+
+	widget := newTestWidget(t, &Settings{playerCommand: "mpv"})
+	widget.stories = enclosureTestStories(t)
+	widget.SetItemCount(len(widget.stories))
+	widget.Selected = 1
+
+	widget.openEnclosure()
+
+	_, content, _ := widget.content()
+	assert.Assert(t, strings.Contains(content, "player command failed: executable file not found"), content)
+}
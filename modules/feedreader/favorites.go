@@ -0,0 +1,178 @@
+package feedreader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/rivo/tview"
+	"github.com/wtfutil/wtf/cfg"
+	"gopkg.in/yaml.v2"
+)
+
+// favorite is a starred item, stored with enough detail to show and open it after it has
+// dropped out of its feed
+type favorite struct {
+	GUID   string `yaml:"guid"`
+	Title  string `yaml:"title"`
+	Link   string `yaml:"link"`
+	Source string `yaml:"source,omitempty"`
+}
+
+// favorites are the starred items, in the order they were starred, persisted to a YAML file
+type favorites struct {
+	path  string
+	items []favorite
+}
+
+// favoritesPath returns where the favorites file lives. Relative paths are relative to the
+// WTF config directory
+func favoritesPath(fileName string) string {
+	if filepath.IsAbs(fileName) {
+		return fileName
+	}
+
+	configDir, err := cfg.WtfConfigDir()
+	if err != nil {
+		return fileName
+	}
+
+	return filepath.Join(configDir, fileName)
+}
+
+// loadFavorites reads the favorites file. A missing file means there are no favorites yet
+func loadFavorites(path string) (*favorites, error) {
+	favs := &favorites{path: path}
+
+	fileData, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return favs, nil
+		}
+		return favs, fmt.Errorf("could not read favorites: %w", err)
+	}
+
+	if err := yaml.Unmarshal(fileData, &favs.items); err != nil {
+		return favs, fmt.Errorf("could not parse favorites %s: %w", path, err)
+	}
+
+	return favs, nil
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// feedItems returns the favorites as feed items, using the live item from stories when the
+// favorite is still in a feed
+func (favs *favorites) feedItems(stories []*FeedItem) []*FeedItem {
+	live := make(map[string]*FeedItem, len(stories))
+	for _, story := range stories {
+		live[story.key()] = story
+	}
+
+	feedItems := make([]*FeedItem, 0, len(favs.items))
+	for _, fav := range favs.items {
+		if story, ok := live[fav.GUID]; ok {
+			feedItems = append(feedItems, story)
+			continue
+		}
+
+		feedItems = append(feedItems, &FeedItem{
+			item:        &gofeed.Item{GUID: fav.GUID, Title: fav.Title, Link: fav.Link},
+			sourceTitle: fav.Source,
+		})
+	}
+
+	return feedItems
+}
+
+func (favs *favorites) has(feedItem *FeedItem) bool {
+	return favs.index(feedItem.key()) >= 0
+}
+
+func (favs *favorites) index(key string) int {
+	for i, fav := range favs.items {
+		if fav.GUID == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// save writes the favorites to disk
+func (favs *favorites) save() error {
+	fileData, err := yaml.Marshal(favs.items)
+	if err != nil {
+		return fmt.Errorf("could not write favorites: %w", err)
+	}
+
+	if err := os.WriteFile(favs.path, fileData, 0600); err != nil {
+		return fmt.Errorf("could not write favorites: %w", err)
+	}
+
+	return nil
+}
+
+// toggle stars the item if it isn't starred, and unstars it if it is, then saves
+func (favs *favorites) toggle(feedItem *FeedItem) error {
+	if idx := favs.index(feedItem.key()); idx >= 0 {
+		favs.items = append(favs.items[:idx], favs.items[idx+1:]...)
+	} else {
+		favs.items = append(favs.items, favorite{
+			GUID:   feedItem.key(),
+			Title:  feedItem.item.Title,
+			Link:   feedItem.link(),
+			Source: feedItem.source(),
+		})
+	}
+
+	return favs.save()
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// favoritesContent lists the starred items, including those no longer in any feed
+func (widget *Widget) favoritesContent(title string) (string, string, bool) {
+	title += " - Favorites"
+
+	str := widget.searchIndicator()
+	for _, warning := range widget.warnings() {
+		str += fmt.Sprintf("[yellow]⚠ %s[white]\n", tview.Escape(warning))
+	}
+
+	data := widget.visibleStories()
+	if len(data) == 0 {
+		return title, str + "No favorites", false
+	}
+
+	return title, str + widget.storyRows(data) + widget.pagingFooter(), false
+}
+
+// toggleFavorite stars or unstars the selected item
+func (widget *Widget) toggleFavorite() {
+	story := widget.selectedStory()
+	if story == nil {
+		return
+	}
+
+	widget.favoritesErr = widget.favorites.toggle(story)
+
+	// Unstarring in the favorites view removes the item from the list, so keep the
+	// selection within what's left
+	count := len(widget.listRows())
+	widget.SetItemCount(count)
+	if widget.Selected >= count {
+		widget.Selected = count - 1
+	}
+
+	widget.Render()
+}
+
+// toggleFavoritesView switches between listing every item and only the starred ones
+func (widget *Widget) toggleFavoritesView() {
+	widget.showFavorites = !widget.showFavorites
+	widget.SetItemCount(len(widget.listRows()))
+	widget.Unselect()
+}
@@ -0,0 +1,121 @@
+package feedreader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"gotest.tools/assert"
+)
+
+func favoritesTestStories() []*FeedItem {
+	return []*FeedItem{
+		{item: &gofeed.Item{GUID: "guid-1", Title: "First", Link: "https://example.com/1"}, sourceTitle: "Example"},
+		{item: &gofeed.Item{GUID: "guid-2", Title: "Second", Link: "https://example.com/2"}, sourceTitle: "Example"},
+		{item: &gofeed.Item{Title: "No GUID", Link: "https://example.com/3"}, sourceTitle: "Example"},
+	}
+}
+
+func TestFavorites_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "favorites.yml")
+	stories := favoritesTestStories()
+
+	favs, err := loadFavorites(path)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(favs.items))
+
+	assert.NilError(t, favs.toggle(stories[1]))
+	assert.NilError(t, favs.toggle(stories[2]))
+
+	reloaded, err := loadFavorites(path)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []favorite{
+		{GUID: "guid-2", Title: "Second", Link: "https://example.com/2", Source: "Example"},
+		{GUID: "https://example.com/3", Title: "No GUID", Link: "https://example.com/3", Source: "Example"},
+	}, reloaded.items)
+	assert.Assert(t, reloaded.has(stories[2]))
+	assert.Assert(t, !reloaded.has(stories[0]))
+
+	assert.NilError(t, reloaded.toggle(stories[1]))
+
+	reloaded, err = loadFavorites(path)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(reloaded.items))
+	assert.Equal(t, "https://example.com/3", reloaded.items[0].GUID)
+}
+
+func TestFavorites_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "favorites.yml")
+	assert.NilError(t, os.WriteFile(path, []byte("guid: [not a list"), 0600))
+
+	widget := newTestWidget(t, &Settings{favoritesPath: path})
+
+	_, content, _ := widget.content()
+	assert.Assert(t, strings.Contains(content, "[yellow]⚠ could not parse favorites"), content)
+}
+
+func TestFavoritesView_ShowsItemsNoLongerInFeeds(t *testing.T) {
+	widget := newTestWidget(t, &Settings{})
+	stories := favoritesTestStories()
+	widget.stories = stories
+
+	widget.Selected = 0
+	widget.toggleFavorite()
+	widget.Selected = 1
+	widget.toggleFavorite()
+
+	// The first favorite drops out of its feed
+	widget.stories = stories[1:]
+
+	widget.toggleFavoritesView()
+	title, content, _ := widget.content()
+
+	assert.Equal(t, "Feeds - Favorites", title)
+	assert.Equal(t, 2, len(widget.visibleStories()))
+	assert.Assert(t, strings.Contains(content, "First"), content)
+	assert.Assert(t, strings.Contains(content, "Second"), content)
+	assert.Assert(t, !strings.Contains(content, "No GUID"), content)
+	assert.Equal(t, "https://example.com/1", widget.visibleStories()[0].item.Link)
+
+	// The favorite that is still in its feed is the live item
+	assert.Equal(t, stories[1], widget.visibleStories()[1])
+}
+
+func TestFavoritesView_Toggle(t *testing.T) {
+	widget := newTestWidget(t, &Settings{favoriteMarker: "★", colors: colors{favorite: "yellow"}})
+	widget.stories = favoritesTestStories()
+	widget.SetItemCount(len(widget.stories))
+
+	widget.Selected = 1
+	widget.toggleFavorite()
+
+	_, content, _ := widget.content()
+	assert.Assert(t, strings.Contains(content, "[yellow]★"), content)
+	assert.Equal(t, 1, strings.Count(content, "★"))
+
+	widget.toggleFavoritesView()
+	assert.Assert(t, widget.showFavorites)
+	assert.Equal(t, -1, widget.Selected)
+
+	_, content, _ = widget.content()
+	assert.Assert(t, strings.Contains(content, " 1. [yellow]★"), content)
+	assert.Assert(t, strings.Contains(content, "Second"), content)
+	assert.Assert(t, !strings.Contains(content, "First"), content)
+
+	// Unstarring from the favorites view removes the item right away
+	widget.Selected = 0
+	widget.toggleFavorite()
+
+	_, content, _ = widget.content()
+	assert.Assert(t, strings.Contains(content, "No favorites"), content)
+	assert.Equal(t, -1, widget.Selected)
+
+	widget.toggleFavoritesView()
+	assert.Assert(t, !widget.showFavorites)
+
+	_, content, _ = widget.content()
+	assert.Assert(t, strings.Contains(content, "First"), content)
+	assert.Assert(t, !strings.Contains(content, "★"), content)
+}
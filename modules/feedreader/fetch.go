@@ -0,0 +1,198 @@
+package feedreader
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// FeedError records why a single feed could not be fetched
+type FeedError struct {
+	URL string
+	Err error
+}
+
+func (feedErr *FeedError) Error() string {
+	return fmt.Sprintf("%s: %s", feedErr.URL, feedErr.Err)
+}
+
+func (feedErr *FeedError) Unwrap() error {
+	return feedErr.Err
+}
+
+// summary describes the error in a few words, prefixed with the feed's host
+// (ex: "example.com: timeout")
+func (feedErr *FeedError) summary() string {
+	host := feedErr.URL
+	if parsed, err := url.Parse(feedErr.URL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+
+	return fmt.Sprintf("%s: %s", host, feedErr.reason())
+}
+
+// reason describes the error in a few words (ex: "timeout", "404 Not Found")
+func (feedErr *FeedError) reason() string {
+	var httpErr gofeed.HTTPError
+
+	switch {
+	case errors.Is(feedErr.Err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(feedErr.Err, &httpErr):
+		return httpErr.Status
+	default:
+		return feedErr.Err.Error()
+	}
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+// Fetch retrieves RSS and Atom feed data, fetching several feeds at a time. The items are
+// returned in feed order, along with an error for each feed that could not be fetched.
+// Feeds that could not be fetched keep the items of their last successful fetch
+func (widget *Widget) Fetch(feedURLs []string) ([]*FeedItem, []*FeedError) {
+	results := make([][]*FeedItem, len(feedURLs))
+	errs := make([]error, len(feedURLs))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for i := 0; i < widget.workerCount(len(feedURLs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				results[idx], errs[idx] = widget.fetchForFeed(feedURLs[idx])
+			}
+		}()
+	}
+
+	for idx := range feedURLs {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	var data []*FeedItem
+	var feedErrs []*FeedError
+
+	for idx, feedURL := range feedURLs {
+		if errs[idx] != nil {
+			feedErrs = append(feedErrs, &FeedError{URL: feedURL, Err: errs[idx]})
+		}
+
+		data = append(data, widget.recordFetch(feedURL, results[idx], errs[idx])...)
+	}
+
+	widget.pruneRedirects(data)
+	data = widget.sort(data)
+
+	return data, feedErrs
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// newHTTPClient returns the client to fetch feeds with, or nil for gofeed's default client
+func newHTTPClient(settings *Settings) *http.Client {
+	if !settings.disableHTTP2 && settings.proxyURL == nil {
+		return nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if settings.disableHTTP2 {
+		// If HTTP/2 is disabled, we override the parser client
+		// with a client using a simple HTTP transport which
+		// removes the client's default behavior of first
+		// trying HTTP/2 before downgrading to older protocol
+		// versions.
+		transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				MinVersion: tls.VersionTLS12,
+				MaxVersion: tls.VersionTLS13,
+			},
+		}
+	}
+
+	if settings.proxyURL != nil {
+		transport.Proxy = http.ProxyURL(settings.proxyURL)
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+func (widget *Widget) fetchForFeed(feedURL string) ([]*FeedItem, error) {
+	ctx := context.Background()
+	if widget.settings.feedTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, widget.settings.feedTimeout)
+		defer cancel()
+	}
+
+	feed, err := widget.newParser(feedURL).ParseURLWithContext(feedURL, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var feedItems []*FeedItem
+
+	limit := widget.settings.limitFor(feedURL)
+	base := feedBase(feed, feedURL)
+
+	for idx, gofeedItem := range feed.Items {
+		if limit >= 1 && idx >= limit {
+			// We only want to get the limit latest articles,
+			// not all of them. To get all, set feedLimit to < 1
+			break
+		}
+
+		gofeedItem.Link = resolveLink(base, gofeedItem.Link)
+
+		feedItem := &FeedItem{
+			item:        gofeedItem,
+			feedURL:     feedURL,
+			alias:       widget.settings.feedOptions[feedURL].alias,
+			sourceTitle: feed.Title,
+			viewed:      false,
+		}
+
+		feedItems = append(feedItems, feedItem)
+	}
+
+	widget.unwrapLinks(feedItems)
+
+	return feedItems, nil
+}
+
+// newParser returns a parser for a single feed. Parsers keep state while parsing, so every
+// concurrent fetch gets its own, sharing the widget's HTTP client
+func (widget *Widget) newParser(feedURL string) *gofeed.Parser {
+	parser := gofeed.NewParser()
+	parser.Client = widget.client
+	parser.UserAgent = widget.settings.userAgent
+
+	if auth, isPrivateRSS := widget.settings.credentials[feedURL]; isPrivateRSS {
+		parser.AuthConfig = &gofeed.Auth{
+			Username: auth.username,
+			Password: auth.password,
+		}
+	}
+
+	return parser
+}
+
+// workerCount returns how many feeds to fetch at the same time
+func (widget *Widget) workerCount(feedCount int) int {
+	workers := widget.settings.maxConcurrentFetches
+	if workers < 1 {
+		workers = 1
+	}
+
+	return min(workers, max(feedCount, 1))
+}
@@ -0,0 +1,264 @@
+package feedreader
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/rivo/tview"
+	"github.com/wtfutil/wtf/cfg"
+	"gotest.tools/assert"
+)
+
+func rssFeed(title string, itemTitles ...string) string {
+	var items strings.Builder
+	for _, itemTitle := range itemTitles {
+		fmt.Fprintf(&items, "<item><title>%s</title><link>https://example.com/%s</link></item>", itemTitle, itemTitle)
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0"?><rss version="2.0"><channel><title>%s</title>%s</channel></rss>`, title, items.String())
+}
+
+func newFeedServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// newTestWidget creates a widget whose favorites and read state live in a temporary directory and whose
+// redraws are discarded, so tests can render as often as they like
+func newTestWidget(t *testing.T, settings *Settings) *Widget {
+	t.Helper()
+
+	if settings.Common == nil {
+		settings.Common = &cfg.Common{Title: "Feeds"}
+	}
+	if settings.favoritesPath == "" {
+		settings.favoritesPath = filepath.Join(t.TempDir(), "favorites.yml")
+	}
+	if settings.readStatePath == "" {
+		settings.readStatePath = filepath.Join(t.TempDir(), "read.yml")
+	}
+
+	redrawChan := make(chan bool, 1)
+	go func() {
+		for range redrawChan {
+		}
+	}()
+	t.Cleanup(func() { close(redrawChan) })
+
+	widget := NewWidget(tview.NewApplication(), redrawChan, nil, settings)
+	// Wide enough that rows aren't cut at the edge of the widget
+	widget.View.SetRect(0, 0, 200, 40)
+
+	return widget
+}
+
+func TestFetch_IsolatesFailingFeeds(t *testing.T) {
+	fast := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(rssFeed("Fast", "one", "two")))
+	})
+	slow := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+	failing := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusNotFound)
+	})
+
+	widget := newTestWidget(t, &Settings{
+		feedLimit:            -1,
+		feedTimeout:          100 * time.Millisecond,
+		maxConcurrentFetches: 3,
+	})
+
+	started := time.Now()
+	items, feedErrs := widget.Fetch([]string{slow.URL, fast.URL, failing.URL})
+
+	assert.Assert(t, time.Since(started) < 2*time.Second, "a slow feed must not stall the others")
+
+	assert.Equal(t, 2, len(items))
+	assert.Equal(t, "one", items[0].item.Title)
+	assert.Equal(t, "two", items[1].item.Title)
+
+	assert.Equal(t, 2, len(feedErrs))
+	assert.Equal(t, slow.URL, feedErrs[0].URL)
+	assert.Equal(t, strings.TrimPrefix(slow.URL, "http://")+": timeout", feedErrs[0].summary())
+	assert.Equal(t, failing.URL, feedErrs[1].URL)
+	assert.Equal(t, strings.TrimPrefix(failing.URL, "http://")+": 404 Not Found", feedErrs[1].summary())
+}
+
+func TestFetch_DeterministicOrder(t *testing.T) {
+	// The first feed answers last, but its items still come first
+	first := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte(rssFeed("First", "a1", "a2")))
+	})
+	second := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(rssFeed("Second", "b1")))
+	})
+
+	widget := newTestWidget(t, &Settings{
+		feedLimit:            -1,
+		feedTimeout:          time.Second,
+		maxConcurrentFetches: 2,
+	})
+
+	items, feedErrs := widget.Fetch([]string{first.URL, second.URL})
+
+	assert.Equal(t, 0, len(feedErrs))
+	assert.Equal(t, 3, len(items))
+	assert.Equal(t, "a1", items[0].item.Title)
+	assert.Equal(t, "a2", items[1].item.Title)
+	assert.Equal(t, "b1", items[2].item.Title)
+	assert.Equal(t, "Second", items[2].sourceTitle)
+}
+
+func TestWorkerCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		max       int
+		feedCount int
+		expected  int
+	}{
+		{name: "bounded by the setting", max: 5, feedCount: 20, expected: 5},
+		{name: "bounded by the feeds", max: 5, feedCount: 2, expected: 2},
+		{name: "at least one worker", max: 0, feedCount: 0, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			widget := newTestWidget(t, &Settings{maxConcurrentFetches: tt.max})
+			assert.Equal(t, tt.expected, widget.workerCount(tt.feedCount))
+		})
+	}
+}
+
+func TestContent_FeedErrors(t *testing.T) {
+	widget := newTestWidget(t, &Settings{})
+	widget.feedErrors = []*FeedError{
+		{URL: "https://example.com/feed.xml", Err: context.DeadlineExceeded},
+	}
+	widget.stories = []*FeedItem{
+		{item: &gofeed.Item{Title: "Still here"}},
+	}
+
+	_, content, _ := widget.content()
+
+	assert.Assert(t, strings.Contains(content, "[gray]⚠ example.com: timeout[white]"), content)
+	assert.Assert(t, strings.Contains(content, "Still here"), content)
+}
+
+func TestFetch_LimitsEachFeedBeforeMerging(t *testing.T) {
+	chatty := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(rssFeed("Chatty", "c1", "c2", "c3")))
+	})
+	quiet := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(rssFeed("Quiet", "q1")))
+	})
+
+	widget := newTestWidget(t, &Settings{
+		feedLimit:            2,
+		feedTimeout:          time.Second,
+		maxConcurrentFetches: 2,
+		sortByDate:           true,
+	})
+
+	items, _ := widget.Fetch([]string{chatty.URL, quiet.URL})
+
+	assert.Equal(t, 3, len(items))
+	assert.Equal(t, "c1", items[0].item.Title)
+	assert.Equal(t, "c2", items[1].item.Title)
+	assert.Equal(t, "q1", items[2].item.Title)
+}
+
+func TestFetch_UserAgent(t *testing.T) {
+	userAgents := make(chan string, 1)
+	server := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		userAgents <- r.UserAgent()
+		_, _ = w.Write([]byte(rssFeed("Feed", "one")))
+	})
+
+	widget := newTestWidget(t, &Settings{
+		feedTimeout: time.Second,
+		userAgent:   "my-reader/1.0",
+	})
+
+	_, errs := widget.Fetch([]string{server.URL})
+
+	assert.Equal(t, 0, len(errs))
+	assert.Equal(t, "my-reader/1.0", <-userAgents)
+}
+
+func TestFetch_Proxy(t *testing.T) {
+	proxied := make(chan string, 1)
+	proxy := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		proxied <- r.URL.String()
+		_, _ = w.Write([]byte(rssFeed("Proxied", "one")))
+	})
+
+	proxyURL, err := parseProxyURL(proxy.URL)
+	assert.NilError(t, err)
+
+	widget := newTestWidget(t, &Settings{
+		feedTimeout: time.Second,
+		proxyURL:    proxyURL,
+	})
+
+	items, errs := widget.Fetch([]string{"http://feeds.example.invalid/feed.xml"})
+
+	assert.Equal(t, 0, len(errs))
+	assert.Equal(t, 1, len(items))
+	assert.Equal(t, "http://feeds.example.invalid/feed.xml", <-proxied)
+}
+
+func TestFetch_SummaryOnlyFeeds(t *testing.T) {
+	jsonFeed := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/feed+json")
+		_, _ = w.Write([]byte(`{
+			"version": "https://jsonfeed.org/version/1.1",
+			"title": "JSON",
+			"items": [
+				{"id": "1", "title": "Full", "content_html": "<p>one</p><p>two</p><p>three</p>"},
+				{"id": "2", "title": "Summary", "summary": "<p>one</p><p>two</p><p>three</p>"}
+			]
+		}`))
+	})
+	rss := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>RSS</title>
+			<item><title>Described</title><description>&lt;p&gt;one&lt;/p&gt;&lt;p&gt;two&lt;/p&gt;&lt;p&gt;three&lt;/p&gt;</description></item>
+		</channel></rss>`))
+	})
+
+	widget := newTestWidget(t, &Settings{
+		feedTimeout:     time.Second,
+		maxHeight:       2,
+		contentFallback: true,
+	})
+	widget.showType = SHOW_CONTENT
+
+	items, errs := widget.Fetch([]string{jsonFeed.URL, rss.URL})
+
+	assert.Equal(t, 0, len(errs))
+	assert.Equal(t, 3, len(items))
+	for _, item := range items {
+		assert.Equal(t, "[white]"+item.item.Title+"\none\ntwo…", widget.getShowText(item, "white"))
+	}
+
+	widget.stories = items
+	widget.SetItemCount(len(items))
+	_, rendered, _ := widget.content()
+	assert.Equal(t, 9, strings.Count(strings.TrimSpace(rendered), "\n")+1, rendered)
+
+	widget.settings.contentFallback = false
+	assert.Equal(t, "[white]Summary", widget.getShowText(items[1], "white"))
+}
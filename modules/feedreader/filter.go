@@ -0,0 +1,104 @@
+package feedreader
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// itemFilter holds the compiled patterns for one scope: all feeds, or a single feed
+type itemFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// feedFilters decides which feed items are kept, based on the filters settings
+type feedFilters struct {
+	global   itemFilter
+	scoped   map[string]itemFilter
+	warnings []string
+}
+
+// newFeedFilters compiles the configured patterns case-insensitively. Invalid patterns are
+// skipped and reported in warnings
+func newFeedFilters(settings filterSettings) *feedFilters {
+	filters := &feedFilters{
+		scoped: make(map[string]itemFilter, len(settings.feeds)),
+	}
+
+	filters.global = filters.compile(settings.filterPatterns)
+	for feed, patterns := range settings.feeds {
+		filters.scoped[feed] = filters.compile(patterns)
+	}
+
+	return filters
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// apply returns the items that pass the filters, and how many were removed
+func (filters *feedFilters) apply(feedItems []*FeedItem) ([]*FeedItem, int) {
+	kept := make([]*FeedItem, 0, len(feedItems))
+	for _, feedItem := range feedItems {
+		if filters.keep(feedItem) {
+			kept = append(kept, feedItem)
+		}
+	}
+
+	return kept, len(feedItems) - len(kept)
+}
+
+func (filters *feedFilters) compile(patterns filterPatterns) itemFilter {
+	return itemFilter{
+		include: filters.compileAll(patterns.include),
+		exclude: filters.compileAll(patterns.exclude),
+	}
+}
+
+func (filters *feedFilters) compileAll(patterns []string) []*regexp.Regexp {
+	compiled := []*regexp.Regexp{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			filters.warnings = append(filters.warnings, fmt.Sprintf("ignoring invalid filter %q: %s", pattern, err))
+			continue
+		}
+		compiled = append(compiled, re)
+	}
+
+	return compiled
+}
+
+// keep returns true if the item matches at least one include pattern (when there are any
+// for its feed) and no exclude pattern. Patterns scoped to a feed apply on top of the global
+// ones
+func (filters *feedFilters) keep(feedItem *FeedItem) bool {
+	include := filters.global.include
+	exclude := filters.global.exclude
+
+	for _, name := range feedItem.names() {
+		if scoped, ok := filters.scoped[name]; ok {
+			include = append(include[:len(include):len(include)], scoped.include...)
+			exclude = append(exclude[:len(exclude):len(exclude)], scoped.exclude...)
+		}
+	}
+
+	text := feedItem.item.Title + "\n" + feedItem.item.Content
+
+	for _, re := range exclude {
+		if re.MatchString(text) {
+			return false
+		}
+	}
+
+	if len(include) == 0 {
+		return true
+	}
+
+	for _, re := range include {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,135 @@
+package feedreader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"gotest.tools/assert"
+)
+
+func filterTestItems() []*FeedItem {
+	return []*FeedItem{
+		{item: &gofeed.Item{Title: "Kernel 6.9 released"}, sourceTitle: "LWN.net", feedURL: "https://lwn.net/headlines/rss"},
+		{item: &gofeed.Item{Title: "Distro news", Content: "<p>A new KERNEL scheduler</p>"}, sourceTitle: "LWN.net", feedURL: "https://lwn.net/headlines/rss"},
+		{item: &gofeed.Item{Title: "[Sponsored] Buy this"}, sourceTitle: "LWN.net", feedURL: "https://lwn.net/headlines/rss"},
+		{item: &gofeed.Item{Title: "Go 1.23 is out"}, sourceTitle: "Go Blog", feedURL: "https://go.dev/blog/feed.atom"},
+		{item: &gofeed.Item{Title: "Sponsored: hosting"}, sourceTitle: "Go Blog", feedURL: "https://go.dev/blog/feed.atom"},
+	}
+}
+
+func keptTitles(feedItems []*FeedItem) []string {
+	titles := []string{}
+	for _, feedItem := range feedItems {
+		titles = append(titles, feedItem.item.Title)
+	}
+
+	return titles
+}
+
+func TestFeedFilters_Apply(t *testing.T) {
+	tests := []struct {
+		name            string
+		settings        filterSettings
+		expectedTitles  []string
+		expectedRemoved int
+	}{
+		{
+			name:            "no filters",
+			settings:        filterSettings{},
+			expectedTitles:  []string{"Kernel 6.9 released", "Distro news", "[Sponsored] Buy this", "Go 1.23 is out", "Sponsored: hosting"},
+			expectedRemoved: 0,
+		},
+		{
+			name:            "include only",
+			settings:        filterSettings{filterPatterns: filterPatterns{include: []string{"kernel"}}},
+			expectedTitles:  []string{"Kernel 6.9 released", "Distro news"},
+			expectedRemoved: 3,
+		},
+		{
+			name:            "exclude only",
+			settings:        filterSettings{filterPatterns: filterPatterns{exclude: []string{"sponsored"}}},
+			expectedTitles:  []string{"Kernel 6.9 released", "Distro news", "Go 1.23 is out"},
+			expectedRemoved: 2,
+		},
+		{
+			name: "scoped to a feed by title",
+			settings: filterSettings{
+				filterPatterns: filterPatterns{exclude: []string{"sponsored"}},
+				feeds: map[string]filterPatterns{
+					"LWN.net": {include: []string{`^kernel`}},
+				},
+			},
+			expectedTitles:  []string{"Kernel 6.9 released", "Go 1.23 is out"},
+			expectedRemoved: 3,
+		},
+		{
+			name: "scoped to a feed by URL",
+			settings: filterSettings{
+				feeds: map[string]filterPatterns{
+					"https://go.dev/blog/feed.atom": {exclude: []string{"sponsored"}},
+				},
+			},
+			expectedTitles:  []string{"Kernel 6.9 released", "Distro news", "[Sponsored] Buy this", "Go 1.23 is out"},
+			expectedRemoved: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := newFeedFilters(tt.settings)
+			kept, removed := filters.apply(filterTestItems())
+
+			assert.DeepEqual(t, tt.expectedTitles, keptTitles(kept))
+			assert.Equal(t, tt.expectedRemoved, removed)
+			assert.Equal(t, 0, len(filters.warnings))
+		})
+	}
+}
+
+func TestFeedFilters_InvalidPattern(t *testing.T) {
+	filters := newFeedFilters(filterSettings{
+		filterPatterns: filterPatterns{exclude: []string{"(sponsored", "hosting"}},
+	})
+
+	kept, removed := filters.apply(filterTestItems())
+
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, 4, len(kept))
+	assert.Equal(t, 1, len(filters.warnings))
+	assert.Assert(t, strings.HasPrefix(filters.warnings[0], `ignoring invalid filter "(sponsored"`), filters.warnings[0])
+}
+
+func TestContent_FilterWarningsAndCount(t *testing.T) {
+	widget := newTestWidget(t, &Settings{
+		filters: filterSettings{filterPatterns: filterPatterns{exclude: []string{"[", "sponsored"}}},
+	})
+	widget.stories, widget.filteredCount = widget.filters.apply(filterTestItems())
+
+	_, content, _ := widget.content()
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+
+	assert.Assert(t, strings.HasPrefix(lines[0], `[yellow]⚠ ignoring invalid filter "["`), lines[0])
+	assert.Equal(t, "[gray](2 filtered)[white]", lines[len(lines)-1])
+}
+
+func TestParseFilterSettings(t *testing.T) {
+	settings := newTestSettings(t, `
+filters:
+  include:
+    - kernel
+  exclude:
+    - sponsored
+  feeds:
+    LWN.net:
+      include:
+        - "^kernel"
+      exclude:
+        - security
+`)
+
+	assert.DeepEqual(t, []string{"kernel"}, settings.filters.include)
+	assert.DeepEqual(t, []string{"sponsored"}, settings.filters.exclude)
+	assert.DeepEqual(t, []string{"^kernel"}, settings.filters.feeds["LWN.net"].include)
+	assert.DeepEqual(t, []string{"security"}, settings.filters.feeds["LWN.net"].exclude)
+}
@@ -0,0 +1,190 @@
+package feedreader
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+	"github.com/wtfutil/wtf/utils"
+)
+
+const (
+	collapsedMarker = "▸"
+	expandedMarker  = "▾"
+)
+
+// feedGroup is the items of one feed, listed under a header when grouping by feed
+type feedGroup struct {
+	name  string
+	color string
+	items []*FeedItem
+}
+
+// listRow is a selectable row of the list: an item, or the header of its feed's group
+type listRow struct {
+	story *FeedItem
+	group *feedGroup
+}
+
+// key identifies the row across refreshes
+func (row listRow) key() string {
+	if row.story == nil {
+		return "group:" + row.group.name
+	}
+
+	return row.story.key()
+}
+
+// groupStories groups the items by feed, in the order the feeds first appear
+func (widget *Widget) groupStories(stories []*FeedItem) []*feedGroup {
+	groups := []*feedGroup{}
+	byName := make(map[string]*feedGroup)
+
+	for _, story := range stories {
+		group, ok := byName[story.source()]
+		if !ok {
+			group = &feedGroup{
+				name:  story.source(),
+				color: widget.settings.sourceColorFor(story.feedURL, story.source()),
+			}
+			byName[group.name] = group
+			groups = append(groups, group)
+		}
+
+		group.items = append(group.items, story)
+	}
+
+	return groups
+}
+
+// grouping tells whether the list is grouped by feed. Favorites and the feed status are
+// never grouped
+func (widget *Widget) grouping() bool {
+	return widget.settings.groupByFeed && !widget.showFavorites && !widget.showFeedStatus
+}
+
+// listRows returns the rows the selection moves over: one per visible item or, when
+// grouping by feed, a header per feed followed by its items unless it is collapsed
+func (widget *Widget) listRows() []listRow {
+	stories := widget.visibleStories()
+	rows := make([]listRow, 0, len(stories))
+
+	if !widget.grouping() {
+		for _, story := range stories {
+			rows = append(rows, listRow{story: story})
+		}
+		return rows
+	}
+
+	for _, group := range widget.groupStories(stories) {
+		rows = append(rows, listRow{group: group})
+		if widget.collapsed[group.name] {
+			continue
+		}
+		for _, story := range group.items {
+			rows = append(rows, listRow{story: story, group: group})
+		}
+	}
+
+	return rows
+}
+
+// selectedRow returns the selected row, and whether there is one
+func (widget *Widget) selectedRow() (listRow, bool) {
+	rows := widget.listRows()
+	sel := widget.GetSelected()
+
+	if sel < 0 || sel >= len(rows) {
+		return listRow{}, false
+	}
+
+	return rows[sel], true
+}
+
+// selectedStory returns the selected item, or nil if nothing or a group header is selected
+func (widget *Widget) selectedStory() *FeedItem {
+	row, ok := widget.selectedRow()
+	if !ok {
+		return nil
+	}
+
+	return row.story
+}
+
+// groupedRows renders the rows of the list grouped by feed. Items are numbered as if the
+// list weren't grouped, so collapsing a group doesn't renumber the others
+func (widget *Widget) groupedRows(rows []listRow) string {
+	var str string
+
+	number := 0
+	for idx, row := range rows {
+		if row.story != nil {
+			number++
+			str += widget.storyRow(row.story, idx, number)
+			continue
+		}
+
+		marker := expandedMarker
+		if widget.collapsed[row.group.name] {
+			marker = collapsedMarker
+			number += len(row.group.items)
+		}
+
+		rowColor := widget.RowColor(idx)
+		header := fmt.Sprintf(
+			"[%s]%s [%s]%s[%s] (%d)[white]",
+			rowColor,
+			marker,
+			row.group.color,
+			tview.Escape(row.group.name),
+			rowColor,
+			len(row.group.items),
+		)
+
+		str += utils.HighlightableHelper(widget.View, header, idx, utils.DisplayWidth(header))
+	}
+
+	return str
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// setGroupCollapsed collapses or expands the group under the cursor. Collapsing selects its
+// header, as its items are no longer listed
+func (widget *Widget) setGroupCollapsed(collapsed bool) {
+	row, ok := widget.selectedRow()
+	if !widget.grouping() || !ok {
+		return
+	}
+
+	// The rows above the group's header don't change, so neither does its position
+	rows := widget.listRows()
+	header := widget.Selected
+	for header > 0 && rows[header].story != nil {
+		header--
+	}
+
+	widget.collapsed[row.group.name] = collapsed
+	widget.SetItemCount(len(widget.listRows()))
+	if collapsed {
+		widget.Selected = header
+	}
+
+	widget.Render()
+}
+
+func (widget *Widget) collapseGroup() {
+	widget.setGroupCollapsed(true)
+}
+
+func (widget *Widget) expandGroup() {
+	widget.setGroupCollapsed(false)
+}
+
+func (widget *Widget) toggleGroup() {
+	row, ok := widget.selectedRow()
+	if !ok || row.group == nil {
+		return
+	}
+
+	widget.setGroupCollapsed(!widget.collapsed[row.group.name])
+}
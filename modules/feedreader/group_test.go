@@ -0,0 +1,104 @@
+package feedreader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"gotest.tools/assert"
+)
+
+func groupTestStories() []*FeedItem {
+	return []*FeedItem{
+		{item: &gofeed.Item{GUID: "r1", Title: "v1.1"}, feedURL: "https://example.com/releases.xml", alias: "Releases"},
+		{item: &gofeed.Item{GUID: "n1", Title: "Headline"}, feedURL: "https://example.com/news.xml", sourceTitle: "News"},
+		{item: &gofeed.Item{GUID: "r2", Title: "v1.2"}, feedURL: "https://example.com/releases.xml", alias: "Releases"},
+	}
+}
+
+func newGroupTestWidget(t *testing.T) *Widget {
+	widget := newTestWidget(t, &Settings{
+		colors:      colors{source: "green"},
+		groupByFeed: true,
+		feedOptions: map[string]feedOptions{
+			"https://example.com/releases.xml": {alias: "Releases", color: "yellow"},
+		},
+	})
+	rebuild(widget, groupTestStories())
+
+	return widget
+}
+
+func rowKeys(rows []listRow) []string {
+	keys := []string{}
+	for _, row := range rows {
+		keys = append(keys, row.key())
+	}
+
+	return keys
+}
+
+func TestGroupedRows(t *testing.T) {
+	widget := newGroupTestWidget(t)
+
+	assert.DeepEqual(t, []string{"group:Releases", "r1", "r2", "group:News", "n1"}, rowKeys(widget.listRows()))
+
+	_, content, _ := widget.content()
+	rows := strings.Split(strings.TrimSpace(content), "\n")
+
+	assert.Equal(t, 5, len(rows), content)
+	assert.Assert(t, strings.Contains(rows[0], "[:]▾ [yellow]Releases[:] (2)[white]"), rows[0])
+	assert.Assert(t, strings.Contains(rows[1], " 1. [:]v1.1"), rows[1])
+	assert.Assert(t, strings.Contains(rows[2], " 2. [:]v1.2"), rows[2])
+	assert.Assert(t, strings.Contains(rows[3], "[:]▾ [green]News[:] (1)[white]"), rows[3])
+	assert.Assert(t, strings.Contains(rows[4], " 3. [:]Headline"), rows[4])
+}
+
+func TestCollapseGroup_SurvivesRefresh(t *testing.T) {
+	widget := newGroupTestWidget(t)
+
+	widget.Selected = 4
+	widget.collapseGroup()
+
+	assert.Equal(t, 3, widget.Selected, "collapsing selects the group's header")
+	assert.DeepEqual(t, []string{"group:Releases", "r1", "r2", "group:News"}, rowKeys(widget.listRows()))
+
+	stories := append(groupTestStories(), &FeedItem{
+		item:        &gofeed.Item{GUID: "n2", Title: "Breaking"},
+		feedURL:     "https://example.com/news.xml",
+		sourceTitle: "News",
+	})
+	rebuild(widget, stories)
+
+	assert.DeepEqual(t, []string{"group:Releases", "r1", "r2", "group:News"}, rowKeys(widget.listRows()))
+	assert.Equal(t, 3, widget.Selected)
+
+	_, content, _ := widget.content()
+	assert.Assert(t, strings.Contains(content, "▸ [green]News[:] (2)"), content)
+
+	widget.expandGroup()
+	assert.DeepEqual(t, []string{"group:Releases", "r1", "r2", "group:News", "n1", "n2"}, rowKeys(widget.listRows()))
+}
+
+func TestCollapseGroup_CollapsedFirstGroup(t *testing.T) {
+	widget := newGroupTestWidget(t)
+
+	widget.Selected = 0
+	widget.toggleGroup()
+	assert.DeepEqual(t, []string{"group:Releases", "group:News", "n1"}, rowKeys(widget.listRows()))
+
+	// Headers aren't items
+	assert.Assert(t, widget.selectedStory() == nil)
+
+	widget.Next()
+	widget.Next()
+	assert.Equal(t, 2, widget.Selected)
+	assert.Equal(t, "n1", widget.selectedStory().key())
+
+	widget.Next()
+	assert.Equal(t, 0, widget.Selected, "the collapsed items are skipped")
+
+	// Items keep their numbers while the group before them is collapsed
+	_, content, _ := widget.content()
+	assert.Assert(t, strings.Contains(content, " 3. [:]Headline"), content)
+}
@@ -0,0 +1,133 @@
+package feedreader
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	healthTimeFormat = "Jan 02 15:04"
+	failingMarker    = "⚠"
+)
+
+// feedHealth records how fetching a feed has been going
+type feedHealth struct {
+	title       string
+	lastSuccess time.Time
+	lastErr     error
+	failures    int // consecutive
+	items       []*FeedItem
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// recordFetch updates the health of a feed after fetching it, and returns the items to show
+// for it. A feed that failed keeps showing the items from its last successful fetch
+func (widget *Widget) recordFetch(feedURL string, feedItems []*FeedItem, err error) []*FeedItem {
+	if widget.health == nil {
+		widget.health = make(map[string]*feedHealth)
+	}
+
+	health, ok := widget.health[feedURL]
+	if !ok {
+		health = &feedHealth{}
+		widget.health[feedURL] = health
+	}
+
+	if err != nil {
+		health.lastErr = err
+		health.failures++
+		return health.items
+	}
+
+	health.lastSuccess = nowFunc()
+	health.lastErr = nil
+	health.failures = 0
+	health.items = feedItems
+	if len(feedItems) > 0 {
+		health.title = feedItems[0].sourceTitle
+	}
+
+	return feedItems
+}
+
+// isFailing returns true if the item's feed has failed too many times in a row
+func (widget *Widget) isFailing(feedItem *FeedItem) bool {
+	health, ok := widget.health[feedItem.feedURL]
+	if !ok || widget.settings.failureThreshold < 1 {
+		return false
+	}
+
+	return health.failures >= widget.settings.failureThreshold
+}
+
+// feedName returns the name to show for a feed: its alias, its title, or its host
+func (widget *Widget) feedName(feedURL string) string {
+	if alias := widget.settings.feedOptions[feedURL].alias; alias != "" {
+		return alias
+	}
+
+	if health, ok := widget.health[feedURL]; ok && health.title != "" {
+		return health.title
+	}
+
+	if parsed, err := url.Parse(feedURL); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+
+	return feedURL
+}
+
+// statusContent lists every configured feed with when it was last fetched, how many items it
+// has and why it last failed, if it did
+func (widget *Widget) statusContent(title string) (string, string, bool) {
+	title += " - Feed Status"
+
+	if len(widget.settings.feeds) == 0 {
+		return title, "No feeds configured", false
+	}
+
+	var str string
+	for _, feedURL := range widget.settings.feeds {
+		name := tview.Escape(widget.feedName(feedURL))
+
+		health, ok := widget.health[feedURL]
+		if !ok {
+			str += fmt.Sprintf("[gray]○ %s  never fetched[white]\n", name)
+			continue
+		}
+
+		lastSuccess := "never"
+		if !health.lastSuccess.IsZero() {
+			lastSuccess = health.lastSuccess.Format(healthTimeFormat)
+		}
+
+		if health.lastErr == nil {
+			str += fmt.Sprintf(
+				"[green]●[white] %s  [gray]updated %s · %d items[white]\n",
+				name, lastSuccess, len(health.items),
+			)
+			continue
+		}
+
+		feedErr := &FeedError{URL: feedURL, Err: health.lastErr}
+		str += fmt.Sprintf(
+			"[red]●[white] %s  [gray]updated %s · %d items · failed %d× · %s[white]\n",
+			name, lastSuccess, len(health.items), health.failures, tview.Escape(feedErr.reason()),
+		)
+	}
+
+	return title, str, false
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// toggleFeedStatus switches between the item list and the status of each feed
+func (widget *Widget) toggleFeedStatus() {
+	widget.showFeedStatus = !widget.showFeedStatus
+	widget.SetItemCount(len(widget.listRows()))
+	widget.Unselect()
+}
@@ -0,0 +1,97 @@
+package feedreader
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestStatusContent(t *testing.T) {
+	now := time.Date(2024, 5, 10, 9, 30, 0, 0, time.UTC)
+	originalNow := nowFunc
+	defer func() { nowFunc = originalNow }()
+	nowFunc = func() time.Time { return now }
+
+	healthy := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(rssFeed("Healthy Feed", "one", "two")))
+	})
+	failing := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusNotFound)
+	})
+	neverFetched := "https://never.example.com/feed.xml"
+
+	widget := newTestWidget(t, &Settings{
+		feeds:                []string{healthy.URL, failing.URL, neverFetched},
+		feedLimit:            -1,
+		feedTimeout:          time.Second,
+		maxConcurrentFetches: 2,
+		feedOptions: map[string]feedOptions{
+			failing.URL: {alias: "Broken"},
+		},
+	})
+
+	widget.Fetch([]string{healthy.URL, failing.URL})
+	widget.Fetch([]string{healthy.URL, failing.URL})
+
+	widget.toggleFeedStatus()
+	title, content, _ := widget.content()
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+
+	assert.Equal(t, "Feeds - Feed Status", title)
+	assert.DeepEqual(t, []string{
+		"[green]●[white] Healthy Feed  [gray]updated May 10 09:30 · 2 items[white]",
+		"[red]●[white] Broken  [gray]updated never · 0 items · failed 2× · 404 Not Found[white]",
+		"[gray]○ never.example.com  never fetched[white]",
+	}, lines)
+
+	assert.Equal(t, 0, len(widget.visibleStories()))
+
+	widget.toggleFeedStatus()
+	assert.Assert(t, !widget.showFeedStatus)
+}
+
+func TestFetch_KeepsItemsOfFailingFeeds(t *testing.T) {
+	var broken atomic.Bool
+	flaky := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if broken.Load() {
+			http.Error(w, "oops", http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(rssFeed("Flaky", "kept")))
+	})
+
+	widget := newTestWidget(t, &Settings{
+		feedLimit:            -1,
+		feedTimeout:          time.Second,
+		maxConcurrentFetches: 1,
+		failureThreshold:     2,
+	})
+
+	items, _ := widget.Fetch([]string{flaky.URL})
+	assert.Equal(t, 1, len(items))
+	assert.Assert(t, !widget.isFailing(items[0]))
+
+	broken.Store(true)
+
+	items, feedErrs := widget.Fetch([]string{flaky.URL})
+	assert.Equal(t, 1, len(feedErrs))
+	assert.Equal(t, 1, len(items))
+	assert.Equal(t, "kept", items[0].item.Title)
+	assert.Assert(t, !widget.isFailing(items[0]))
+
+	items, _ = widget.Fetch([]string{flaky.URL})
+	assert.Assert(t, widget.isFailing(items[0]))
+
+	widget.stories = items
+	_, content, _ := widget.content()
+	assert.Assert(t, strings.Contains(content, "[gray]⚠[:] [:]kept"), content)
+
+	// A successful fetch clears the marker
+	broken.Store(false)
+	items, _ = widget.Fetch([]string{flaky.URL})
+	assert.Assert(t, !widget.isFailing(items[0]))
+}
@@ -0,0 +1,87 @@
+package feedreader
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// autoMaxHeight is the maxHeight that fits the content of the items to the widget's height
+const autoMaxHeight = "auto"
+
+// queueUpdateDraw runs f on the UI goroutine, then draws the screen. It is replaceable in
+// tests
+var queueUpdateDraw = func(tviewApp *tview.Application, f func()) {
+	tviewApp.QueueUpdateDraw(f)
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// autoHeightBudget returns how many content lines each of count items gets when they share
+// rows: what's left of their share once their title line is taken, within minLines and
+// maxLines. A maxLines below 1 doesn't bound it. Every item gets at least one line, even
+// when there's no room for it
+//
+// Example:
+//
+//	x := autoHeightBudget(30, 5, 1, 0)
+//	> 5
+func autoHeightBudget(rows, count, minLines, maxLines int) int {
+	minLines = max(minLines, 1)
+
+	lines := minLines
+	if count > 0 {
+		lines = max(rows/count-1, minLines)
+	}
+	if maxLines > 0 {
+		lines = max(min(lines, maxLines), 1)
+	}
+
+	return lines
+}
+
+// innerRect returns the part of the rect inside the border, if the widget has one
+func innerRect(x, y, width, height int, bordered bool) (int, int, int, int) {
+	if bordered {
+		x, y, width, height = x+1, y+1, width-2, height-2
+	}
+
+	return x, y, max(width, 0), max(height, 0)
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// maxHeightFor returns the maximum number of content lines to show for a feed's items: its
+// own maxHeight, or else the global one, which with maxHeight: auto is the budget of the
+// last render
+func (widget *Widget) maxHeightFor(feedURL string) int {
+	if options, ok := widget.settings.feedOptions[feedURL]; (ok && options.maxHeight > 0) || !widget.settings.maxHeightAuto {
+		return widget.settings.maxHeightFor(feedURL)
+	}
+
+	return int(widget.autoHeight.Load())
+}
+
+// budgetHeight works out the content lines each of count items gets with maxHeight: auto,
+// from the height the widget was last drawn at
+func (widget *Widget) budgetHeight(count int) {
+	if !widget.settings.maxHeightAuto {
+		return
+	}
+
+	rows := int(widget.autoHeightRows.Load())
+	widget.autoHeight.Store(int64(autoHeightBudget(rows, count, widget.settings.autoHeightMin, widget.settings.autoHeightMax)))
+}
+
+// drawAutoHeight records the height the widget is drawn at, and renders the items again
+// when it changed, as when the terminal is resized. The render is queued on the UI
+// goroutine, from another goroutine as tview is drawing on this one and QueueUpdateDraw
+// waits for it
+func (widget *Widget) drawAutoHeight(_ tcell.Screen, x, y, width, height int) (int, int, int, int) {
+	x, y, width, height = innerRect(x, y, width, height, widget.Bordered())
+
+	if widget.autoHeightRows.Swap(int64(height)) != int64(height) {
+		go queueUpdateDraw(widget.tviewApp, widget.Render)
+	}
+
+	return x, y, width, height
+}
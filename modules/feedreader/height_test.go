@@ -0,0 +1,174 @@
+package feedreader
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mmcdole/gofeed"
+	"github.com/rivo/tview"
+	"github.com/wtfutil/wtf/cfg"
+	"gotest.tools/assert"
+)
+
+func Test_autoHeightBudget(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     int
+		count    int
+		minLines int
+		maxLines int
+		expected int
+	}{
+		{name: "tall cell", rows: 40, count: 5, minLines: 1, expected: 7},
+		{name: "short cell", rows: 12, count: 5, minLines: 1, expected: 1},
+		{name: "one story", rows: 20, count: 1, minLines: 1, expected: 19},
+		{name: "many stories", rows: 20, count: 25, minLines: 1, expected: 1},
+		{name: "bounded above", rows: 40, count: 2, minLines: 1, maxLines: 6, expected: 6},
+		{name: "bounded below", rows: 10, count: 5, minLines: 3, expected: 3},
+		{name: "no stories", rows: 20, count: 0, minLines: 2, expected: 2},
+		{name: "one row", rows: 1, count: 1, minLines: 1, expected: 1},
+		{name: "one row, no minimum", rows: 1, count: 3, minLines: 0, expected: 1},
+		{name: "no rows", rows: 0, count: 3, minLines: 1, expected: 1},
+		{name: "maximum below minimum", rows: 40, count: 2, minLines: 5, maxLines: 3, expected: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, autoHeightBudget(tt.rows, tt.count, tt.minLines, tt.maxLines))
+		})
+	}
+}
+
+func TestNewSettingsFromYAML_AutoHeight(t *testing.T) {
+	settings := newTestSettings(t, `
+maxHeight: auto
+autoHeight:
+  min: 2
+  max: 8
+`)
+
+	assert.Equal(t, true, settings.maxHeightAuto)
+	assert.Equal(t, 0, settings.maxHeight)
+	assert.Equal(t, 2, settings.autoHeightMin)
+	assert.Equal(t, 8, settings.autoHeightMax)
+
+	settings = newTestSettings(t, "maxHeight: 4")
+	assert.Equal(t, false, settings.maxHeightAuto)
+	assert.Equal(t, 4, settings.maxHeight)
+	assert.Equal(t, 1, settings.autoHeightMin)
+	assert.Equal(t, 0, settings.autoHeightMax)
+}
+
+func Test_content_autoHeight(t *testing.T) {
+	releases := "https://example.com/releases.xml"
+
+	widget := newTestWidget(t, &Settings{
+		maxHeightAuto: true,
+		autoHeightMin: 1,
+		feedOptions: map[string]feedOptions{
+			releases: {maxHeight: 1},
+		},
+	})
+	widget.showType = SHOW_CONTENT
+
+	content := "<p>one</p><p>two</p><p>three</p><p>four</p><p>five</p>"
+	widget.stories = []*FeedItem{
+		{item: &gofeed.Item{Title: "First", Content: content}},
+		{item: &gofeed.Item{Title: "Second", Content: content}},
+		{item: &gofeed.Item{Title: "v1.2", Content: content}, feedURL: releases},
+	}
+
+	tests := []struct {
+		height   int
+		expected int
+	}{
+		{height: 40, expected: 12},
+		{height: 9, expected: 2},
+		{height: 1, expected: 1},
+		{height: 0, expected: 1},
+	}
+
+	for _, tt := range tests {
+		widget.autoHeightRows.Store(int64(tt.height))
+		_, rendered, _ := widget.content()
+
+		assert.Equal(t, int64(tt.expected), widget.autoHeight.Load())
+
+		// A feed's own maxHeight still wins
+		lines := min(tt.expected, 5)
+		assert.Equal(t, 3+lines+lines+1, strings.Count(strings.TrimSpace(rendered), "\n")+1, rendered)
+	}
+}
+
+func Test_innerRect(t *testing.T) {
+	x, y, width, height := innerRect(2, 3, 80, 24, true)
+	assert.DeepEqual(t, []int{3, 4, 78, 22}, []int{x, y, width, height})
+
+	x, y, width, height = innerRect(2, 3, 80, 24, false)
+	assert.DeepEqual(t, []int{2, 3, 80, 24}, []int{x, y, width, height})
+
+	_, _, width, height = innerRect(0, 0, 1, 1, true)
+	assert.DeepEqual(t, []int{0, 0}, []int{width, height})
+}
+
+// stubQueueUpdateDraw collects the updates queued for the UI goroutine, for the test to run
+func stubQueueUpdateDraw(t *testing.T) chan func() {
+	original := queueUpdateDraw
+	t.Cleanup(func() { queueUpdateDraw = original })
+
+	queued := make(chan func(), 10)
+	queueUpdateDraw = func(_ *tview.Application, f func()) { queued <- f }
+
+	return queued
+}
+
+// runQueued runs the next update queued for the UI goroutine
+func runQueued(t *testing.T, queued chan func()) {
+	t.Helper()
+
+	select {
+	case f := <-queued:
+		f()
+	case <-time.After(time.Second):
+		t.Fatal("the widget wasn't rendered again")
+	}
+}
+
+func Test_drawAutoHeight(t *testing.T) {
+	queued := stubQueueUpdateDraw(t)
+
+	widget := newTestWidget(t, &Settings{
+		Common:        &cfg.Common{Title: "Feeds", Bordered: true},
+		maxHeightAuto: true,
+		autoHeightMin: 1,
+	})
+	widget.showType = SHOW_CONTENT
+	widget.stories = []*FeedItem{{item: &gofeed.Item{Title: "First"}}}
+
+	screen := tcell.NewSimulationScreen("")
+	assert.NilError(t, screen.Init())
+	defer screen.Fini()
+
+	// The height inside the border is recorded, and the items rendered again for it on
+	// the UI goroutine
+	widget.View.SetRect(0, 0, 200, 40)
+	widget.View.Draw(screen)
+	assert.Equal(t, int64(38), widget.autoHeightRows.Load())
+
+	runQueued(t, queued)
+	assert.Equal(t, int64(37), widget.autoHeight.Load())
+
+	// Drawn at the same height, they aren't
+	widget.View.Draw(screen)
+	assert.Equal(t, 0, len(queued))
+
+	// Drawn at another height, as when the terminal is resized, they are
+	widget.View.SetRect(0, 0, 200, 12)
+	widget.View.Draw(screen)
+	assert.Equal(t, int64(10), widget.autoHeightRows.Load())
+
+	runQueued(t, queued)
+	assert.Equal(t, int64(9), widget.autoHeight.Load())
+}
@@ -0,0 +1,182 @@
+package feedreader
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	listBullet = "• "
+	listIndent = "  "
+)
+
+var (
+	whitespace     = regexp.MustCompile(`\s+`)
+	blankLines     = regexp.MustCompile(`\n{3,}`)
+	trailingSpaces = regexp.MustCompile(`[ \t]+\n`)
+)
+
+// blockElements start and end on their own line
+var blockElements = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true,
+	atom.Dd: true, atom.Div: true, atom.Dl: true, atom.Dt: true, atom.Figcaption: true,
+	atom.Figure: true, atom.Footer: true, atom.H1: true, atom.H2: true, atom.H3: true,
+	atom.H4: true, atom.H5: true, atom.H6: true, atom.Header: true, atom.Hr: true,
+	atom.Ol: true, atom.P: true, atom.Section: true, atom.Table: true, atom.Tr: true,
+	atom.Ul: true,
+}
+
+// htmlConverter walks an HTML tree, writing it out as plain text
+type htmlConverter struct {
+	sb        strings.Builder
+	showLinks bool
+	listDepth int
+}
+
+// htmlToText converts feed item HTML to plain text: list items become bulleted lines, line
+// breaks and paragraphs become newlines, entities are decoded and preformatted blocks are
+// kept verbatim. Images are written as "[image: alt]". With showLinks, links are written as
+// "text (url)"
+func htmlToText(content string, showLinks bool) string {
+	nodes, err := html.ParseFragment(strings.NewReader(content), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return strings.TrimSpace(content)
+	}
+
+	converter := &htmlConverter{showLinks: showLinks}
+	for _, node := range nodes {
+		converter.walk(node)
+	}
+
+	text := trailingSpaces.ReplaceAllString(converter.sb.String(), "\n")
+	text = blankLines.ReplaceAllString(text, "\n\n")
+
+	return strings.TrimSpace(text)
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// newline ends the current line, unless it is already empty
+func (converter *htmlConverter) newline() {
+	text := converter.sb.String()
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		converter.sb.WriteString("\n")
+	}
+}
+
+// text writes inline text, collapsing whitespace the way a browser would
+func (converter *htmlConverter) text(data string) {
+	data = whitespace.ReplaceAllString(strings.ReplaceAll(data, " ", " "), " ")
+
+	current := converter.sb.String()
+	if current == "" || strings.HasSuffix(current, "\n") || strings.HasSuffix(current, " ") {
+		data = strings.TrimLeft(data, " ")
+	}
+
+	converter.sb.WriteString(data)
+}
+
+func (converter *htmlConverter) walk(node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		converter.text(node.Data)
+		return
+	case html.ElementNode:
+	default:
+		converter.walkChildren(node)
+		return
+	}
+
+	switch node.DataAtom {
+	case atom.Script, atom.Style, atom.Head, atom.Title:
+		return
+
+	case atom.Br:
+		converter.sb.WriteString("\n")
+
+	case atom.Img:
+		converter.text(imagePlaceholder(attr(node, "alt")))
+
+	case atom.Pre:
+		converter.newline()
+		converter.sb.WriteString(strings.Trim(textContent(node), "\n"))
+		converter.sb.WriteString("\n")
+
+	case atom.Li:
+		converter.newline()
+		converter.sb.WriteString(strings.Repeat(listIndent, max(converter.listDepth-1, 0)) + listBullet)
+		converter.walkChildren(node)
+		converter.newline()
+
+	case atom.Ul, atom.Ol:
+		converter.listDepth++
+		converter.newline()
+		converter.walkChildren(node)
+		converter.newline()
+		converter.listDepth--
+
+	case atom.A:
+		converter.walkChildren(node)
+		href := attr(node, "href")
+		if converter.showLinks && href != "" && href != strings.TrimSpace(textContent(node)) {
+			converter.text(" (" + href + ")")
+		}
+
+	default:
+		if blockElements[node.DataAtom] {
+			converter.newline()
+			converter.walkChildren(node)
+			converter.newline()
+			return
+		}
+		converter.walkChildren(node)
+	}
+}
+
+func (converter *htmlConverter) walkChildren(node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		converter.walk(child)
+	}
+}
+
+// attr returns the value of the named attribute, or "" if the node doesn't have it
+func attr(node *html.Node, name string) string {
+	for _, attribute := range node.Attr {
+		if attribute.Key == name {
+			return attribute.Val
+		}
+	}
+
+	return ""
+}
+
+// imagePlaceholder stands in for an image, which can't be shown as text, by its alt text
+func imagePlaceholder(alt string) string {
+	alt = strings.TrimSpace(whitespace.ReplaceAllString(alt, " "))
+	if alt == "" {
+		return "[image]"
+	}
+
+	return "[image: " + alt + "]"
+}
+
+// textContent returns all the text under node, as is
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+
+	var sb strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		sb.WriteString(textContent(child))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,96 @@
+package feedreader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"gotest.tools/assert"
+)
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name      string
+		html      string
+		showLinks bool
+		expected  string
+	}{
+		{
+			name:     "plain text",
+			html:     "Just some   text",
+			expected: "Just some text",
+		},
+		{
+			name:     "paragraphs and line breaks",
+			html:     "<p>First paragraph</p><p>Second<br>line two<br/>line three</p>",
+			expected: "First paragraph\nSecond\nline two\nline three",
+		},
+		{
+			name:     "list items",
+			html:     "<p>Changes:</p><ul><li>Faster</li><li>Smaller</li></ul><p>Done</p>",
+			expected: "Changes:\n• Faster\n• Smaller\nDone",
+		},
+		{
+			name:     "nested lists and tags",
+			html:     "<ol><li><b>Bold</b> and <em>emphasis</em><ul><li>child <code>code</code></li></ul></li><li>Last</li></ol>",
+			expected: "• Bold and emphasis\n  • child code\n• Last",
+		},
+		{
+			name:     "images",
+			html:     "<p>Before <img src=\"a.png\" alt=\" The  new\n logo \"> after</p><figure><img src=\"b.png\"></figure>",
+			expected: "Before [image: The new logo] after\n[image]",
+		},
+		{
+			name:     "entities",
+			html:     "Fish&nbsp;&amp;&nbsp;chips &lt;3 &#8212; caf&#xE9; &quot;ok&quot;",
+			expected: "Fish & chips <3 — café \"ok\"",
+		},
+		{
+			name:      "links with URLs",
+			html:      `Read <a href="https://example.com/post">the post</a> at <a href="https://example.com">https://example.com</a>`,
+			showLinks: true,
+			expected:  "Read the post (https://example.com/post) at https://example.com",
+		},
+		{
+			name:      "links without URLs",
+			html:      `Read <a href="https://example.com/post">the post</a>`,
+			showLinks: false,
+			expected:  "Read the post",
+		},
+		{
+			name:     "preformatted blocks are kept verbatim",
+			html:     "<p>Run:</p><pre>func main() {\n    fmt.Println(&quot;hi&quot;)\n}</pre><p>After</p>",
+			expected: "Run:\nfunc main() {\n    fmt.Println(\"hi\")\n}\nAfter",
+		},
+		{
+			name:     "scripts and styles are dropped",
+			html:     "<style>p { color: red }</style><p>Visible</p><script>alert(1)</script>",
+			expected: "Visible",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, htmlToText(tt.html, tt.showLinks))
+		})
+	}
+}
+
+func Test_getShowText_content(t *testing.T) {
+	widget := &Widget{
+		settings: &Settings{showLinksInContent: true},
+		showType: SHOW_CONTENT,
+	}
+
+	feedItem := &FeedItem{
+		item: &gofeed.Item{
+			Title:   "Release",
+			Content: `<ul><li>Fixed [red] tags</li><li>See <a href="https://example.com">notes</a></li></ul>`,
+		},
+	}
+
+	actual := widget.getShowText(feedItem, "white")
+
+	assert.Equal(t, "[white]Release\n• Fixed [red[] tags\n• See notes (https://example.com)", actual)
+	assert.Equal(t, 3, len(strings.Split(actual, "\n")))
+}
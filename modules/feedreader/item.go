@@ -0,0 +1,90 @@
+package feedreader
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/wtfutil/wtf/utils"
+	"github.com/wtfutil/wtf/view"
+)
+
+const (
+	itemPage = "feedreader-item"
+	// itemDateFormat is how the item view shows when an item was published, in full
+	itemDateFormat = "Mon, 02 Jan 2006 15:04"
+)
+
+/* -------------------- Widget Functions -------------------- */
+
+// showItem shows the whole of the selected item in a modal dialog, which scrolls with the
+// arrow keys. o opens the item in the browser, Escape closes the dialog. The item is marked
+// as read
+func (widget *Widget) showItem() {
+	story := widget.selectedStory()
+	if story == nil || widget.pages == nil {
+		return
+	}
+
+	widget.markRead([]*FeedItem{story})
+	widget.Render()
+
+	closeFunc := func() {
+		widget.pages.RemovePage(itemPage)
+		widget.tviewApp.SetFocus(widget.View)
+	}
+
+	modal := view.NewBillboardModal(widget.itemText(story), closeFunc)
+	modal.SetTitle(fmt.Sprintf("  %s  ", tview.Escape(story.source())))
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'o' {
+			utils.OpenFile(story.link())
+			return nil
+		}
+
+		return event
+	})
+
+	widget.pages.AddPage(itemPage, modal, false, true)
+	widget.tviewApp.SetFocus(modal)
+}
+
+// itemText renders an item for the item view: its title, where and when it was published,
+// its link, then all of its content
+func (widget *Widget) itemText(feedItem *FeedItem) string {
+	title := whitespace.ReplaceAllString(html.UnescapeString(feedItem.item.Title), " ")
+	str := fmt.Sprintf(" [::b]%s[::-]\n", tview.Escape(strings.TrimSpace(title)))
+
+	published := []string{}
+	if source := feedItem.source(); source != "" {
+		published = append(published, fmt.Sprintf("[%s]%s[white]", widget.settings.sourceColorFor(feedItem.feedURL, source), tview.Escape(source)))
+	}
+	if date := feedItem.date(); date != nil {
+		published = append(published, fmt.Sprintf("[%s]%s[white]", widget.settings.publishDate, date.Format(itemDateFormat)))
+	}
+	if len(published) > 0 {
+		str += " " + strings.Join(published, " · ") + "\n"
+	}
+
+	if link := feedItem.link(); link != "" {
+		str += fmt.Sprintf(" [gray]%s[white]\n", tview.Escape(link))
+	}
+
+	// Falling back on the title would only show it twice
+	content := feedItem.content(widget.settings.contentFallback)
+	if content == feedItem.item.Title {
+		content = ""
+	}
+
+	content = htmlToText(content, widget.settings.showLinksInContent)
+	if content != "" {
+		str += "\n"
+		for _, line := range strings.Split(content, "\n") {
+			str += " " + tview.Escape(line) + "\n"
+		}
+	}
+
+	return str
+}
@@ -0,0 +1,81 @@
+package feedreader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mmcdole/gofeed"
+	"github.com/rivo/tview"
+	"gotest.tools/assert"
+)
+
+func Test_itemText(t *testing.T) {
+	published := time.Date(2024, 5, 10, 9, 30, 0, 0, time.UTC)
+
+	widget := newTestWidget(t, &Settings{
+		colors:             colors{source: "green", publishDate: "orange"},
+		contentFallback:    true,
+		showLinksInContent: true,
+	})
+
+	feedItem := &FeedItem{
+		item: &gofeed.Item{
+			Title:           "Release  v1.2 &amp; [notes]",
+			Link:            "https://example.com/v1.2",
+			PublishedParsed: &published,
+			Content:         `<p>Highlights:</p><ul><li>Faster</li></ul><p><img src="chart.png" alt="Benchmarks"> See <a href="https://example.com/bench">the numbers</a></p>`,
+		},
+		sourceTitle: "Example Releases",
+	}
+
+	assert.Equal(t, " [::b]Release v1.2 & [notes[][::-]\n"+
+		" [green]Example Releases[white] · [orange]Fri, 10 May 2024 09:30[white]\n"+
+		" [gray]https://example.com/v1.2[white]\n"+
+		"\n"+
+		" Highlights:\n"+
+		" • Faster\n"+
+		" [image: Benchmarks[] See the numbers (https://example.com/bench)\n", widget.itemText(feedItem))
+
+	// Items without a date, link or content only show what they have
+	assert.Equal(t, " [::b]Untitled[::-]\n", widget.itemText(&FeedItem{item: &gofeed.Item{Title: "Untitled"}}))
+}
+
+func Test_showItem(t *testing.T) {
+	widget := newTestWidget(t, &Settings{})
+	widget.pages = tview.NewPages()
+
+	widget.stories = []*FeedItem{
+		{item: &gofeed.Item{Title: "First", Link: "https://example.com/1"}},
+		{item: &gofeed.Item{Title: "Second", Link: "https://example.com/2"}},
+		{item: &gofeed.Item{Title: "Third", Link: "https://example.com/3"}},
+	}
+	widget.SetItemCount(len(widget.stories))
+	widget.Selected = 1
+
+	widget.showItem()
+
+	assert.Assert(t, widget.pages.HasPage(itemPage))
+	assert.Assert(t, widget.stories[1].viewed)
+
+	// Escape closes the item, leaving the same item selected
+	name, modal := widget.pages.GetFrontPage()
+	assert.Equal(t, itemPage, name)
+	modal.InputHandler()(tcell.NewEventKey(tcell.KeyEsc, 0, tcell.ModNone), func(tview.Primitive) {})
+
+	assert.Assert(t, !widget.pages.HasPage(itemPage))
+	assert.Equal(t, 1, widget.Selected)
+	assert.Equal(t, "Second", widget.selectedStory().item.Title)
+}
+
+func Test_showItem_NoSelection(t *testing.T) {
+	widget := newTestWidget(t, &Settings{})
+	widget.pages = tview.NewPages()
+	widget.stories = []*FeedItem{{item: &gofeed.Item{Title: "First"}}}
+	widget.SetItemCount(1)
+	widget.Selected = -1
+
+	widget.showItem()
+
+	assert.Assert(t, !widget.pages.HasPage(itemPage))
+}
@@ -9,10 +9,25 @@ func (widget *Widget) initializeKeyboardControls() {
 	widget.SetKeyboardChar("j", widget.Next, "Select next item")
 	widget.SetKeyboardChar("k", widget.Prev, "Select previous item")
 	widget.SetKeyboardChar("o", widget.openStory, "Open story in browser")
+	widget.SetKeyboardChar("e", widget.openEnclosure, "Open the selected item's enclosure, such as a podcast episode")
+	widget.SetKeyboardChar("A", widget.markAllRead, "Mark every listed item as read")
 	widget.SetKeyboardChar("t", widget.toggleDisplayText, "Toggle display between title, link and title+content")
+	widget.SetKeyboardChar("*", widget.toggleFavorite, "Star or unstar the selected item")
+	widget.SetKeyboardChar("f", widget.toggleFavoritesView, "Toggle showing only starred items")
+	widget.SetKeyboardChar("s", widget.toggleFeedStatus, "Toggle showing the status of each feed")
+	widget.SetKeyboardChar("m", widget.loadMore, "Load more items")
+	widget.SetKeyboardChar("M", widget.collapsePages, "Show only the first page of items")
+	widget.SetKeyboardChar("z", widget.toggleGroup, "Collapse or expand the feed under the cursor, when grouping by feed")
+	widget.SetKeyboardChar("?", widget.showSearchPrompt, "Search items")
+	widget.SetKeyboardChar("n", widget.nextMatch, "Select next search match")
+	widget.SetKeyboardChar("N", widget.prevMatch, "Select previous search match")
+	widget.SetKeyboardChar("]", widget.nextSource, "Select next item from a different feed")
+	widget.SetKeyboardChar("[", widget.prevSource, "Select previous item from a different feed")
 
 	widget.SetKeyboardKey(tcell.KeyDown, widget.Next, "Select next item")
 	widget.SetKeyboardKey(tcell.KeyUp, widget.Prev, "Select previous item")
-	widget.SetKeyboardKey(tcell.KeyEnter, widget.openStory, "Open story in browser")
-	widget.SetKeyboardKey(tcell.KeyEsc, widget.Unselect, "Clear selection")
+	widget.SetKeyboardKey(tcell.KeyLeft, widget.collapseGroup, "Collapse the feed under the cursor, when grouping by feed")
+	widget.SetKeyboardKey(tcell.KeyRight, widget.expandGroup, "Expand the feed under the cursor, when grouping by feed")
+	widget.SetKeyboardKey(tcell.KeyEnter, widget.showItem, "Read the selected item, with o to open it in the browser")
+	widget.SetKeyboardKey(tcell.KeyEsc, widget.clearSearch, "Clear search, or selection")
 }
@@ -0,0 +1,155 @@
+package feedreader
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// link returns the URL to show and open for the item: where its link redirects to, when
+// redirects were unwrapped, or else its link
+func (feedItem *FeedItem) link() string {
+	if feedItem.finalLink != "" {
+		return feedItem.finalLink
+	}
+
+	return feedItem.item.Link
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// feedBase returns the URL relative item links are resolved against: the feed's site link,
+// or the URL the feed was fetched from when it has no absolute one
+func feedBase(feed *gofeed.Feed, feedURL string) string {
+	if parsed, err := url.Parse(feed.Link); err == nil && parsed.IsAbs() {
+		return feed.Link
+	}
+
+	return feedURL
+}
+
+// resolveLink resolves a link relative to base. Links that are absolute, or can't be parsed,
+// are returned as they are
+func resolveLink(base, link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return link
+	}
+
+	ref, err := url.Parse(link)
+	if err != nil || ref.IsAbs() {
+		return link
+	}
+
+	baseURL, err := url.Parse(base)
+	if err != nil || !baseURL.IsAbs() {
+		return link
+	}
+
+	return baseURL.ResolveReference(ref).String()
+}
+
+// isWebLink reports whether link is an http or https URL, the only ones redirects are
+// followed for
+func isWebLink(link string) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	return parsed.Scheme == "http" || parsed.Scheme == "https"
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// unwrapLinks follows the redirects of the items' links, several at a time, storing where
+// they end up. Links already unwrapped on an earlier refresh aren't requested again
+func (widget *Widget) unwrapLinks(feedItems []*FeedItem) {
+	if !widget.settings.unwrapRedirects {
+		return
+	}
+
+	slots := make(chan struct{}, widget.workerCount(len(feedItems)))
+	var wg sync.WaitGroup
+
+	for _, feedItem := range feedItems {
+		if !isWebLink(feedItem.item.Link) {
+			continue
+		}
+
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(feedItem *FeedItem) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			feedItem.finalLink = widget.unwrapRedirect(feedItem.item.Link)
+		}(feedItem)
+	}
+
+	wg.Wait()
+}
+
+// unwrapRedirect returns where link redirects to, found with a HEAD request. The link itself
+// is returned when the request fails or times out, and is tried again on the next refresh
+func (widget *Widget) unwrapRedirect(link string) string {
+	widget.redirectsMu.Lock()
+	final, ok := widget.redirects[link]
+	widget.redirectsMu.Unlock()
+	if ok {
+		return final
+	}
+
+	ctx := context.Background()
+	if widget.settings.unwrapTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, widget.settings.unwrapTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, link, http.NoBody)
+	if err != nil {
+		return link
+	}
+	req.Header.Set("User-Agent", widget.settings.userAgent)
+
+	client := widget.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return link
+	}
+	_ = resp.Body.Close()
+
+	final = resp.Request.URL.String()
+
+	widget.redirectsMu.Lock()
+	widget.redirects[link] = final
+	widget.redirectsMu.Unlock()
+
+	return final
+}
+
+// pruneRedirects forgets where the links of items no longer in any feed redirect to
+func (widget *Widget) pruneRedirects(feedItems []*FeedItem) {
+	links := make(map[string]bool, len(feedItems))
+	for _, feedItem := range feedItems {
+		links[feedItem.item.Link] = true
+	}
+
+	widget.redirectsMu.Lock()
+	defer widget.redirectsMu.Unlock()
+
+	for link := range widget.redirects {
+		if !links[link] {
+			delete(widget.redirects, link)
+		}
+	}
+}
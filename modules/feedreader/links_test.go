@@ -0,0 +1,144 @@
+package feedreader
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"gotest.tools/assert"
+)
+
+func TestResolveLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		link     string
+		expected string
+	}{
+		{name: "absolute", base: "https://example.com/blog/", link: "https://other.com/a", expected: "https://other.com/a"},
+		{name: "root relative", base: "https://example.com/blog/feed.xml", link: "/posts/a", expected: "https://example.com/posts/a"},
+		{name: "path relative", base: "https://example.com/blog/", link: "posts/a", expected: "https://example.com/blog/posts/a"},
+		{name: "parent", base: "https://example.com/blog/feed/", link: "../a", expected: "https://example.com/blog/a"},
+		{name: "scheme relative", base: "https://example.com/", link: "//cdn.example.com/a", expected: "https://cdn.example.com/a"},
+		{name: "whitespace", base: "https://example.com/", link: "  /a\n", expected: "https://example.com/a"},
+		{name: "empty", base: "https://example.com/", link: "", expected: ""},
+		{name: "relative base", base: "blog/", link: "/a", expected: "/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolveLink(tt.base, tt.link))
+		})
+	}
+}
+
+func TestFeedBase(t *testing.T) {
+	assert.Equal(t, "https://example.com/", feedBase(&gofeed.Feed{Link: "https://example.com/"}, "https://feeds.example.com/rss"))
+	assert.Equal(t, "https://feeds.example.com/rss", feedBase(&gofeed.Feed{Link: "/"}, "https://feeds.example.com/rss"))
+	assert.Equal(t, "https://feeds.example.com/rss", feedBase(&gofeed.Feed{}, "https://feeds.example.com/rss"))
+}
+
+func TestFetch_RelativeLinks(t *testing.T) {
+	server := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>Blog</title>
+<item><title>one</title><link>/posts/one</link></item>
+<item><title>two</title><link>https://example.com/two</link></item>
+</channel></rss>`))
+	})
+
+	widget := newTestWidget(t, &Settings{feedLimit: -1, feedTimeout: time.Second})
+	items, feedErrs := widget.Fetch([]string{server.URL + "/blog/feed.xml"})
+
+	assert.Equal(t, 0, len(feedErrs))
+	assert.Equal(t, 2, len(items))
+	assert.Equal(t, server.URL+"/posts/one", items[0].link())
+	assert.Equal(t, "https://example.com/two", items[1].link())
+}
+
+func TestFetch_UnwrapRedirects(t *testing.T) {
+	var heads atomic.Int32
+
+	server := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed.xml":
+			fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Blog</title>
+<item><title>tracked</title><link>%s/track/one</link></item>
+<item><title>slow</title><link>%s/slow</link></item>
+<item><title>mail</title><link>mailto:me@example.com</link></item>
+</channel></rss>`, "http://"+r.Host, "http://"+r.Host)
+		case "/track/one":
+			heads.Add(1)
+			assert.Equal(t, http.MethodHead, r.Method)
+			http.Redirect(w, r, "/posts/one", http.StatusFound)
+		case "/slow":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+	})
+
+	widget := newTestWidget(t, &Settings{
+		feedLimit:       -1,
+		feedTimeout:     time.Second,
+		unwrapRedirects: true,
+		unwrapTimeout:   100 * time.Millisecond,
+	})
+
+	started := time.Now()
+	items, feedErrs := widget.Fetch([]string{server.URL + "/feed.xml"})
+
+	assert.Assert(t, time.Since(started) < 2*time.Second, "a slow redirect must time out")
+	assert.Equal(t, 0, len(feedErrs))
+	assert.Equal(t, 3, len(items))
+
+	assert.Equal(t, server.URL+"/posts/one", items[0].link())
+	// The item is still known by its own link
+	assert.Equal(t, server.URL+"/track/one", items[0].key())
+
+	// Timeouts fall back to the original link
+	assert.Equal(t, server.URL+"/slow", items[1].link())
+	assert.Equal(t, "mailto:me@example.com", items[2].link())
+
+	widget.showType = SHOW_LINK
+	assert.Equal(t, server.URL+"/posts/one", widget.getShowText(items[0], "white"))
+
+	// Unwrapped links aren't requested again on the next refresh
+	items, _ = widget.Fetch([]string{server.URL + "/feed.xml"})
+	assert.Equal(t, server.URL+"/posts/one", items[0].link())
+	assert.Equal(t, int32(1), heads.Load())
+}
+
+func TestFetch_UnwrapRedirectsOff(t *testing.T) {
+	var heads atomic.Int32
+
+	server := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			heads.Add(1)
+		}
+		fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Blog</title>
+<item><title>tracked</title><link>http://%s/track/one</link></item>
+</channel></rss>`, r.Host)
+	})
+
+	widget := newTestWidget(t, &Settings{feedLimit: -1, feedTimeout: time.Second})
+	items, _ := widget.Fetch([]string{server.URL + "/feed.xml"})
+
+	assert.Equal(t, server.URL+"/track/one", items[0].link())
+	assert.Equal(t, int32(0), heads.Load())
+}
+
+func TestPruneRedirects(t *testing.T) {
+	widget := newTestWidget(t, &Settings{})
+	widget.redirects = map[string]string{
+		"https://example.com/a": "https://example.com/final-a",
+		"https://example.com/b": "https://example.com/final-b",
+	}
+
+	widget.pruneRedirects([]*FeedItem{{item: &gofeed.Item{Link: "https://example.com/b"}}})
+
+	assert.DeepEqual(t, map[string]string{"https://example.com/b": "https://example.com/final-b"}, widget.redirects)
+}
@@ -0,0 +1,77 @@
+package feedreader
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const newItemMarker = "NEW"
+
+// ringBell sounds the terminal bell. It is replaceable in tests
+var ringBell = func() {
+	_, _ = fmt.Fprint(os.Stdout, "\a")
+}
+
+// runNotifyCommand starts the notify command without waiting for it to finish. It is
+// replaceable in tests
+var runNotifyCommand = func(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	go func() { _ = cmd.Wait() }()
+
+	return nil
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// trackNewItems marks the items of notify-enabled feeds that haven't been seen before, and
+// notifies about them. Nothing is new on the first refresh, as everything would be
+func (widget *Widget) trackNewItems(feedItems []*FeedItem) {
+	firstRefresh := widget.seen == nil
+	if firstRefresh {
+		widget.seen = make(map[string]bool, len(feedItems))
+	}
+
+	widget.newItems = make(map[string]bool)
+	fresh := []*FeedItem{}
+
+	for _, feedItem := range feedItems {
+		key := feedItem.key()
+		if !firstRefresh && !widget.seen[key] && widget.settings.feedOptions[feedItem.feedURL].notify {
+			widget.newItems[key] = true
+			fresh = append(fresh, feedItem)
+		}
+
+		widget.seen[key] = true
+	}
+
+	if len(fresh) > 0 {
+		widget.notify(fresh)
+	}
+}
+
+// notify rings the bell once, then runs the notify command, if there is one, for each item
+// with its title and link as arguments
+func (widget *Widget) notify(feedItems []*FeedItem) {
+	ringBell()
+
+	command := strings.Fields(widget.settings.notifyCommand)
+	if len(command) == 0 {
+		return
+	}
+
+	for _, feedItem := range feedItems {
+		args := append(command[1:len(command):len(command)], feedItem.item.Title, feedItem.link())
+		if err := runNotifyCommand(command[0], args...); err != nil {
+			widget.notifyErr = fmt.Errorf("notify command failed: %w", err)
+			return
+		}
+	}
+
+	widget.notifyErr = nil
+}
@@ -0,0 +1,114 @@
+package feedreader
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+type notifyCall struct {
+	Name string
+	Args []string
+}
+
+func stubNotifications(t *testing.T) (*int, *[]notifyCall) {
+	bells := 0
+	calls := []notifyCall{}
+
+	originalBell, originalRun := ringBell, runNotifyCommand
+	t.Cleanup(func() { ringBell, runNotifyCommand = originalBell, originalRun })
+
+	ringBell = func() { bells++ }
+	runNotifyCommand = func(name string, args ...string) error {
+		calls = append(calls, notifyCall{Name: name, Args: args})
+		return nil
+	}
+
+	return &bells, &calls
+}
+
+func TestRefresh_NotifiesAboutNewItems(t *testing.T) {
+	bells, calls := stubNotifications(t)
+
+	var refreshes atomic.Int32
+	loud := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if refreshes.Load() == 0 {
+			_, _ = w.Write([]byte(rssFeed("Loud", "old")))
+			return
+		}
+		_, _ = w.Write([]byte(rssFeed("Loud", "fresh", "old")))
+	})
+	quiet := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if refreshes.Load() == 0 {
+			_, _ = w.Write([]byte(rssFeed("Quiet", "q-old")))
+			return
+		}
+		_, _ = w.Write([]byte(rssFeed("Quiet", "q-fresh", "q-old")))
+	})
+
+	widget := newTestWidget(t, &Settings{
+		feeds:                []string{loud.URL, quiet.URL},
+		feedLimit:            -1,
+		feedTimeout:          time.Second,
+		maxConcurrentFetches: 2,
+		notifyCommand:        "notify-send -u critical",
+		colors:               colors{newItem: "red"},
+		feedOptions: map[string]feedOptions{
+			loud.URL: {notify: true},
+		},
+	})
+
+	// The first refresh must not notify about everything
+	widget.Refresh()
+	refreshes.Add(1)
+
+	assert.Equal(t, 0, *bells)
+	assert.Equal(t, 0, len(*calls))
+	assert.Equal(t, 0, len(widget.newItems))
+
+	widget.Refresh()
+	refreshes.Add(1)
+
+	assert.Equal(t, 1, *bells)
+	assert.DeepEqual(t, []notifyCall{
+		{Name: "notify-send", Args: []string{"-u", "critical", "fresh", "https://example.com/fresh"}},
+	}, *calls)
+
+	_, content, _ := widget.content()
+	assert.Assert(t, strings.Contains(content, "[red]NEW[:] [:]fresh"), content)
+	assert.Equal(t, 1, strings.Count(content, "NEW"), content)
+
+	// The marker only lasts one cycle
+	widget.Refresh()
+
+	assert.Equal(t, 1, *bells)
+	_, content, _ = widget.content()
+	assert.Assert(t, !strings.Contains(content, "NEW"), content)
+}
+
+func TestTrackNewItems_WithoutCommand(t *testing.T) {
+	bells, calls := stubNotifications(t)
+
+	widget := newTestWidget(t, &Settings{
+		feedOptions: map[string]feedOptions{"https://example.com/feed.xml": {notify: true}},
+	})
+
+	widget.trackNewItems(favoritesTestStories())
+	assert.Equal(t, 0, *bells)
+
+	stories := favoritesTestStories()
+	for _, story := range stories {
+		story.feedURL = "https://example.com/feed.xml"
+	}
+	stories[0].item.GUID = "guid-new"
+
+	widget.trackNewItems(stories)
+
+	assert.Equal(t, 1, *bells)
+	assert.Equal(t, 0, len(*calls))
+	assert.DeepEqual(t, map[string]bool{"guid-new": true}, widget.newItems)
+}
@@ -0,0 +1,54 @@
+package feedreader
+
+import "fmt"
+
+// pagedStories returns the pages of stories loaded so far. A page size below 1 loads
+// everything at once
+func (widget *Widget) pagedStories(stories []*FeedItem) []*FeedItem {
+	if widget.settings.pageSize < 1 {
+		return stories
+	}
+
+	return stories[:min(len(stories), widget.settings.pageSize*max(widget.loadedPages, 1))]
+}
+
+// moreCount returns how many listed stories aren't loaded yet
+func (widget *Widget) moreCount() int {
+	return len(widget.listedStories()) - len(widget.visibleStories())
+}
+
+// pagingFooter tells how many stories aren't loaded yet, and how to load them
+func (widget *Widget) pagingFooter() string {
+	more := widget.moreCount()
+	if more == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("[gray]… %d more (press m)[white]\n", more)
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// loadMore loads the next page of stories, below those already loaded
+func (widget *Widget) loadMore() {
+	if widget.moreCount() == 0 {
+		return
+	}
+
+	widget.loadedPages = max(widget.loadedPages, 1) + 1
+	widget.SetItemCount(len(widget.listRows()))
+	widget.Render()
+}
+
+// collapsePages goes back to only the first page of stories
+func (widget *Widget) collapsePages() {
+	widget.loadedPages = 1
+
+	count := len(widget.listRows())
+	widget.SetItemCount(count)
+	if widget.Selected >= count {
+		widget.Selected = count - 1
+	}
+
+	widget.Render()
+}
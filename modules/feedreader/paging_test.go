@@ -0,0 +1,93 @@
+package feedreader
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func pagingTestStories(count int) []*FeedItem {
+	guids := make([]string, count)
+	for i := range guids {
+		guids[i] = fmt.Sprintf("item-%d", i+1)
+	}
+
+	return selectionTestStories(guids...)
+}
+
+func TestPagingFooter(t *testing.T) {
+	tests := []struct {
+		name     string
+		pageSize int
+		pages    int
+		count    int
+		expected string
+	}{
+		{name: "first page", pageSize: 25, pages: 1, count: 162, expected: "[gray]… 137 more (press m)[white]\n"},
+		{name: "two pages", pageSize: 25, pages: 2, count: 162, expected: "[gray]… 112 more (press m)[white]\n"},
+		{name: "last page partly filled", pageSize: 25, pages: 7, count: 162, expected: ""},
+		{name: "exactly one page", pageSize: 25, pages: 1, count: 25, expected: ""},
+		{name: "paging off", pageSize: 0, pages: 1, count: 162, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			widget := newTestWidget(t, &Settings{pageSize: tt.pageSize})
+			widget.stories = pagingTestStories(tt.count)
+			widget.loadedPages = tt.pages
+
+			assert.Equal(t, tt.expected, widget.pagingFooter())
+		})
+	}
+}
+
+func TestLoadMore(t *testing.T) {
+	widget := newTestWidget(t, &Settings{pageSize: 2})
+	rebuild(widget, pagingTestStories(5))
+
+	assert.Equal(t, 2, len(widget.visibleStories()))
+	_, content, _ := widget.content()
+	assert.Assert(t, strings.Contains(content, "… 3 more (press m)"), content)
+	assert.Assert(t, !strings.Contains(content, "item-3"), content)
+
+	widget.loadMore()
+	assert.Equal(t, 4, len(widget.visibleStories()))
+
+	widget.loadMore()
+	widget.loadMore()
+	assert.Equal(t, 5, len(widget.visibleStories()))
+	assert.Equal(t, 3, widget.loadedPages)
+
+	_, content, _ = widget.content()
+	assert.Assert(t, strings.Contains(content, "item-5"), content)
+	assert.Assert(t, !strings.Contains(content, "more (press m)"), content)
+
+	// Everything stays searchable, loaded or not
+	assert.Equal(t, 5, len(widget.stories))
+}
+
+func TestLoadMore_SelectionBeyondFirstPage(t *testing.T) {
+	widget := newTestWidget(t, &Settings{pageSize: 2})
+	rebuild(widget, pagingTestStories(5))
+
+	widget.Selected = 1
+	widget.Next()
+	assert.Equal(t, 0, widget.Selected, "selection wraps within the first page")
+
+	widget.loadMore()
+	widget.Selected = 1
+	widget.Next()
+	widget.Next()
+	assert.Equal(t, 3, widget.Selected)
+	assert.Equal(t, "item-4", widget.visibleStories()[widget.Selected].key())
+
+	// A refresh keeps the loaded pages, and the selection
+	rebuild(widget, pagingTestStories(6))
+	assert.Equal(t, "item-4", widget.visibleStories()[widget.Selected].key())
+
+	widget.collapsePages()
+	assert.Equal(t, 2, len(widget.visibleStories()))
+	assert.Equal(t, 1, widget.Selected)
+}
@@ -0,0 +1,163 @@
+package feedreader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// readState is the set of keys of the items that have been read, persisted to a YAML file
+type readState struct {
+	path string
+	keys map[string]bool
+}
+
+// loadReadState reads the read-state file. A missing file means nothing has been read yet
+func loadReadState(path string) (*readState, error) {
+	state := &readState{path: path, keys: make(map[string]bool)}
+
+	fileData, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return state, fmt.Errorf("could not read read state: %w", err)
+	}
+
+	keys := []string{}
+	if err := yaml.Unmarshal(fileData, &keys); err != nil {
+		return state, fmt.Errorf("could not parse read state %s: %w", path, err)
+	}
+
+	for _, key := range keys {
+		state.keys[key] = true
+	}
+
+	return state, nil
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+func (state *readState) has(feedItem *FeedItem) bool {
+	return state.keys[feedItem.key()]
+}
+
+// mark adds the items to the set, and saves it if any of them weren't read yet
+func (state *readState) mark(feedItems []*FeedItem) error {
+	changed := false
+	for _, feedItem := range feedItems {
+		if !state.keys[feedItem.key()] {
+			state.keys[feedItem.key()] = true
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return state.save()
+}
+
+// prune forgets the items that are no longer in any feed, so the file doesn't grow forever
+func (state *readState) prune(feedItems []*FeedItem) error {
+	current := make(map[string]bool, len(feedItems))
+	for _, feedItem := range feedItems {
+		current[feedItem.key()] = true
+	}
+
+	changed := false
+	for key := range state.keys {
+		if !current[key] {
+			delete(state.keys, key)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return state.save()
+}
+
+// save writes the read items' keys to disk, sorted so the file diffs well
+func (state *readState) save() error {
+	keys := make([]string, 0, len(state.keys))
+	for key := range state.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fileData, err := yaml.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("could not write read state: %w", err)
+	}
+
+	if err := os.WriteFile(state.path, fileData, 0600); err != nil {
+		return fmt.Errorf("could not write read state: %w", err)
+	}
+
+	return nil
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// applyReadState marks the freshly fetched items that were read before
+func (widget *Widget) applyReadState(feedItems []*FeedItem) {
+	for _, feedItem := range feedItems {
+		feedItem.viewed = widget.readState.has(feedItem)
+	}
+}
+
+// markRead marks the items as read and remembers them. With hideRead on they drop out of
+// the list, so the selection is kept within what's left
+func (widget *Widget) markRead(feedItems []*FeedItem) {
+	for _, feedItem := range feedItems {
+		feedItem.viewed = true
+	}
+
+	widget.readStateErr = widget.readState.mark(feedItems)
+
+	count := len(widget.listRows())
+	widget.SetItemCount(count)
+	if widget.Selected >= count {
+		widget.Selected = count - 1
+	}
+}
+
+// markAllRead marks every listed item as read
+func (widget *Widget) markAllRead() {
+	widget.markRead(widget.visibleStories())
+	widget.Render()
+}
+
+// unreadItems returns the items that haven't been read
+func unreadItems(feedItems []*FeedItem) []*FeedItem {
+	unread := []*FeedItem{}
+	for _, feedItem := range feedItems {
+		if !feedItem.viewed {
+			unread = append(unread, feedItem)
+		}
+	}
+
+	return unread
+}
+
+// unreadCount returns how many of the items in the list haven't been read
+func (widget *Widget) unreadCount() int {
+	return len(unreadItems(widget.stories))
+}
+
+// unreadTitle adds the unread count to the title (ex: "Feeds (12)"), when showing it
+func (widget *Widget) unreadTitle(title string) string {
+	if !widget.settings.showUnreadCountInTitle {
+		return title
+	}
+
+	return fmt.Sprintf("%s (%d)", title, widget.unreadCount())
+}
@@ -0,0 +1,96 @@
+package feedreader
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestReadState_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "read.yml")
+	stories := favoritesTestStories()
+
+	state, err := loadReadState(path)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(state.keys))
+
+	assert.NilError(t, state.mark(stories[1:]))
+
+	reloaded, err := loadReadState(path)
+	assert.NilError(t, err)
+	assert.Assert(t, !reloaded.has(stories[0]))
+	assert.Assert(t, reloaded.has(stories[1]))
+	assert.Assert(t, reloaded.has(stories[2]))
+
+	assert.NilError(t, reloaded.prune(stories[:2]))
+
+	reloaded, err = loadReadState(path)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]bool{"guid-2": true}, reloaded.keys)
+}
+
+func TestUnreadCount_RespectsFilters(t *testing.T) {
+	var refreshes atomic.Int32
+	server := newFeedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		refreshes.Add(1)
+		_, _ = w.Write([]byte(rssFeed("Feed", "one", "two", "sponsored-three")))
+	})
+
+	widget := newTestWidget(t, &Settings{
+		feeds:                  []string{server.URL},
+		feedTimeout:            time.Second,
+		showUnreadCountInTitle: true,
+		filters:                filterSettings{filterPatterns: filterPatterns{exclude: []string{"sponsored"}}},
+	})
+
+	widget.Refresh()
+	title, _, _ := widget.content()
+	assert.Equal(t, "Feeds (2)", title)
+
+	widget.Selected = 0
+	widget.markRead(widget.visibleStories()[:1])
+	title, _, _ = widget.content()
+	assert.Equal(t, "Feeds (1)", title)
+
+	// Read items stay read across refreshes
+	widget.Refresh()
+	assert.Equal(t, int32(2), refreshes.Load())
+	assert.Equal(t, 1, widget.unreadCount())
+
+	widget.markAllRead()
+	title, _, _ = widget.content()
+	assert.Equal(t, "Feeds (0)", title)
+
+	widget.settings.showUnreadCountInTitle = false
+	title, _, _ = widget.content()
+	assert.Equal(t, "Feeds", title)
+}
+
+func TestMarkAllRead_HideRead(t *testing.T) {
+	widget := newTestWidget(t, &Settings{})
+	rebuild(widget, favoritesTestStories())
+	widget.markRead(widget.stories[:1])
+
+	_, content, _ := widget.content()
+	assert.Assert(t, strings.Contains(content, "[gray] 1. [gray]First"), content)
+
+	widget.settings.hideRead = true
+	rebuild(widget, widget.stories)
+	assert.Equal(t, 2, len(widget.visibleStories()))
+	assert.Equal(t, 2, widget.unreadCount())
+
+	widget.Selected = 1
+	widget.markAllRead()
+
+	assert.Equal(t, 0, len(widget.visibleStories()))
+	assert.Equal(t, -1, widget.Selected)
+	assert.Equal(t, 3, len(widget.stories), "read items are hidden, not dropped")
+
+	_, content, _ = widget.content()
+	assert.Assert(t, !strings.Contains(content, "Second"), content)
+}
@@ -0,0 +1,103 @@
+package feedreader
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// matchesSearch returns true if the item's title or content contains the query, ignoring case
+func matchesSearch(feedItem *FeedItem, query string) bool {
+	query = strings.ToLower(query)
+
+	return strings.Contains(strings.ToLower(feedItem.item.Title), query) ||
+		strings.Contains(strings.ToLower(feedItem.item.Content), query)
+}
+
+// searchItems returns the items matching the query
+func searchItems(feedItems []*FeedItem, query string) []*FeedItem {
+	matches := []*FeedItem{}
+	for _, feedItem := range feedItems {
+		if matchesSearch(feedItem, query) {
+			matches = append(matches, feedItem)
+		}
+	}
+
+	return matches
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// clearSearch restores the full list, or clears the selection if there is no search
+func (widget *Widget) clearSearch() {
+	if widget.search == "" {
+		widget.Unselect()
+		return
+	}
+
+	widget.setSearch("")
+}
+
+// nextMatch selects the next item matching the search, wrapping around to the top
+func (widget *Widget) nextMatch() {
+	widget.selectMatch(1)
+	widget.Render()
+}
+
+// prevMatch selects the previous item matching the search, wrapping around to the bottom
+func (widget *Widget) prevMatch() {
+	widget.selectMatch(-1)
+	widget.Render()
+}
+
+// searchIndicator describes the current search, for the top of the list
+func (widget *Widget) searchIndicator() string {
+	if widget.search == "" {
+		return ""
+	}
+
+	count := len(searchItems(widget.unsearchedStories(), widget.search))
+
+	return fmt.Sprintf("[yellow]Search: %s[white] (%d matches)\n", tview.Escape(widget.search), count)
+}
+
+// selectMatch moves the selection by direction to the nearest matching item
+func (widget *Widget) selectMatch(direction int) {
+	rows := widget.listRows()
+	if widget.search == "" || len(rows) == 0 {
+		return
+	}
+
+	for step := 1; step <= len(rows); step++ {
+		idx := ((widget.Selected+direction*step)%len(rows) + len(rows)) % len(rows)
+		if widget.Selected < 0 && direction < 0 {
+			idx = len(rows) - step
+		}
+
+		if rows[idx].story != nil && matchesSearch(rows[idx].story, widget.search) {
+			widget.Selected = idx
+			return
+		}
+	}
+}
+
+// setSearch searches for query. When filtering, the list only shows the matching items
+func (widget *Widget) setSearch(query string) {
+	widget.search = strings.TrimSpace(query)
+	widget.SetItemCount(len(widget.listRows()))
+
+	if widget.settings.filterOnSearch {
+		widget.Unselect()
+		return
+	}
+
+	widget.Selected = -1
+	widget.selectMatch(1)
+	widget.Render()
+}
+
+// showSearchPrompt opens an input field to type the search into. Escape clears the search
+func (widget *Widget) showSearchPrompt() {
+	widget.showPrompt("Search: ", widget.search, widget.setSearch, func() { widget.setSearch("") })
+}
@@ -0,0 +1,99 @@
+package feedreader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"gotest.tools/assert"
+)
+
+func searchTestStories() []*FeedItem {
+	return []*FeedItem{
+		{item: &gofeed.Item{Title: "Kernel news", Link: "https://example.com/1"}},
+		{item: &gofeed.Item{Title: "Gardening", Link: "https://example.com/2"}},
+		{item: &gofeed.Item{Title: "Weekly", Content: "<p>The KERNEL grew</p>", Link: "https://example.com/3"}},
+		{item: &gofeed.Item{Title: "Cooking", Link: "https://example.com/4"}},
+	}
+}
+
+func TestSearch_Filters(t *testing.T) {
+	widget := newTestWidget(t, &Settings{filterOnSearch: true})
+	widget.stories = searchTestStories()
+	widget.SetItemCount(len(widget.stories))
+
+	widget.setSearch("kernel")
+
+	visible := widget.visibleStories()
+	assert.Equal(t, 2, len(visible))
+	assert.Equal(t, "Kernel news", visible[0].item.Title)
+	assert.Equal(t, "Weekly", visible[1].item.Title)
+
+	_, content, _ := widget.content()
+	assert.Assert(t, strings.HasPrefix(content, "[yellow]Search: kernel[white] (2 matches)\n"), content)
+	assert.Assert(t, !strings.Contains(content, "Gardening"), content)
+
+	// The selection maps to the filtered items, so the second row is the third story
+	widget.Next()
+	widget.Next()
+	assert.Equal(t, 1, widget.Selected)
+	assert.Equal(t, "https://example.com/3", widget.visibleStories()[widget.Selected].item.Link)
+
+	// Next wraps around within the matches
+	widget.Next()
+	assert.Equal(t, 0, widget.Selected)
+}
+
+func TestSearch_SurvivesRefresh(t *testing.T) {
+	widget := newTestWidget(t, &Settings{filterOnSearch: true})
+	widget.stories = searchTestStories()
+	widget.setSearch("kernel")
+
+	widget.stories = append(searchTestStories(), &FeedItem{item: &gofeed.Item{Title: "Kernel 7.0"}})
+
+	assert.Equal(t, 3, len(widget.visibleStories()))
+	assert.Equal(t, "kernel", widget.search)
+}
+
+func TestSearch_JumpBetweenMatches(t *testing.T) {
+	widget := newTestWidget(t, &Settings{filterOnSearch: false})
+	widget.stories = searchTestStories()
+	widget.SetItemCount(len(widget.stories))
+
+	widget.setSearch("KERNEL")
+
+	assert.Equal(t, 4, len(widget.visibleStories()))
+	assert.Equal(t, 0, widget.Selected)
+
+	widget.nextMatch()
+	assert.Equal(t, 2, widget.Selected)
+
+	widget.nextMatch()
+	assert.Equal(t, 0, widget.Selected)
+
+	widget.prevMatch()
+	assert.Equal(t, 2, widget.Selected)
+}
+
+func TestSearch_Clear(t *testing.T) {
+	widget := newTestWidget(t, &Settings{filterOnSearch: true})
+	widget.stories = searchTestStories()
+	widget.setSearch("kernel")
+	widget.Next()
+
+	widget.clearSearch()
+
+	assert.Equal(t, "", widget.search)
+	assert.Equal(t, 4, len(widget.visibleStories()))
+	assert.Equal(t, -1, widget.Selected)
+
+	_, content, _ := widget.content()
+	assert.Assert(t, !strings.Contains(content, "Search:"), content)
+	assert.Assert(t, strings.Contains(content, "Gardening"), content)
+
+	// Without a search, Escape clears the selection
+	widget.SetItemCount(4)
+	widget.Next()
+	widget.clearSearch()
+	assert.Equal(t, -1, widget.Selected)
+}
@@ -0,0 +1,75 @@
+package feedreader
+
+// selectionAnchor remembers what was selected, and where it was on screen, so the selection
+// can be restored after the list is rebuilt
+type selectionAnchor struct {
+	keys     []string // keys of the listed rows, in order
+	selected int
+	row      int // scroll offset of the view
+	column   int
+}
+
+// captureSelection records the current selection and scroll position
+func (widget *Widget) captureSelection() selectionAnchor {
+	rows := widget.listRows()
+
+	anchor := selectionAnchor{
+		keys:     make([]string, len(rows)),
+		selected: widget.Selected,
+	}
+	for i, row := range rows {
+		anchor.keys[i] = row.key()
+	}
+
+	anchor.row, anchor.column = widget.View.GetScrollOffset()
+
+	return anchor
+}
+
+// restoreSelection selects the previously selected item in the rebuilt list or, if it is
+// gone, its nearest neighbor that survived, and restores the scroll offset. When drawn, the
+// view still scrolls as needed to keep the selected item visible
+func (widget *Widget) restoreSelection(anchor selectionAnchor) {
+	rows := widget.listRows()
+
+	if widget.settings.jumpToTopOnRefresh {
+		widget.Selected = -1
+		widget.View.ScrollToBeginning()
+		return
+	}
+
+	widget.View.ScrollTo(anchor.row, anchor.column)
+
+	if anchor.selected < 0 || anchor.selected >= len(anchor.keys) || len(rows) == 0 {
+		return
+	}
+
+	positions := make(map[string]int, len(rows))
+	for i, row := range rows {
+		positions[row.key()] = i
+	}
+
+	widget.Selected = min(anchor.selected, len(rows)-1)
+
+	// Look outwards from the selected item, trying the ones after it first
+	for distance := 0; distance < len(anchor.keys); distance++ {
+		if idx, ok := anchorPosition(anchor, positions, anchor.selected+distance); ok {
+			widget.Selected = idx
+			break
+		}
+		if idx, ok := anchorPosition(anchor, positions, anchor.selected-distance); ok {
+			widget.Selected = idx
+			break
+		}
+	}
+}
+
+// anchorPosition returns where the item that was at oldIdx is in the rebuilt list
+func anchorPosition(anchor selectionAnchor, positions map[string]int, oldIdx int) (int, bool) {
+	if oldIdx < 0 || oldIdx >= len(anchor.keys) {
+		return 0, false
+	}
+
+	idx, ok := positions[anchor.keys[oldIdx]]
+	return idx, ok
+}
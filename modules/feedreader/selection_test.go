@@ -0,0 +1,115 @@
+package feedreader
+
+import (
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"gotest.tools/assert"
+)
+
+func selectionTestStories(guids ...string) []*FeedItem {
+	stories := []*FeedItem{}
+	for _, guid := range guids {
+		stories = append(stories, &FeedItem{item: &gofeed.Item{GUID: guid, Title: guid}})
+	}
+
+	return stories
+}
+
+// rebuild replaces the stories the way Refresh does
+func rebuild(widget *Widget, stories []*FeedItem) {
+	anchor := widget.captureSelection()
+	widget.stories = stories
+	widget.SetItemCount(len(widget.listRows()))
+	widget.restoreSelection(anchor)
+}
+
+func TestRestoreSelection(t *testing.T) {
+	tests := []struct {
+		name        string
+		before      []string
+		selected    int
+		after       []string
+		jumpToTop   bool
+		expectedKey string
+	}{
+		{
+			name:        "selected item moved down",
+			before:      []string{"a", "b", "c"},
+			selected:    1,
+			after:       []string{"new1", "new2", "a", "b", "c"},
+			expectedKey: "b",
+		},
+		{
+			name:        "selected item moved up",
+			before:      []string{"a", "b", "c", "d"},
+			selected:    3,
+			after:       []string{"b", "d"},
+			expectedKey: "d",
+		},
+		{
+			name:        "selected item disappeared, next neighbor survives",
+			before:      []string{"a", "b", "c", "d"},
+			selected:    1,
+			after:       []string{"new", "a", "c", "d"},
+			expectedKey: "c",
+		},
+		{
+			name:        "selected item and later ones disappeared",
+			before:      []string{"a", "b", "c", "d"},
+			selected:    2,
+			after:       []string{"new", "a", "b"},
+			expectedKey: "b",
+		},
+		{
+			name:        "nothing survived",
+			before:      []string{"a", "b", "c"},
+			selected:    2,
+			after:       []string{"x", "y"},
+			expectedKey: "y",
+		},
+		{
+			name:        "jump to top",
+			before:      []string{"a", "b", "c"},
+			selected:    1,
+			after:       []string{"new", "a", "b", "c"},
+			jumpToTop:   true,
+			expectedKey: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			widget := newTestWidget(t, &Settings{jumpToTopOnRefresh: tt.jumpToTop})
+			widget.stories = selectionTestStories(tt.before...)
+			widget.SetItemCount(len(widget.stories))
+			widget.Selected = tt.selected
+
+			rebuild(widget, selectionTestStories(tt.after...))
+
+			if tt.expectedKey == "" {
+				assert.Equal(t, -1, widget.Selected)
+				return
+			}
+			assert.Equal(t, tt.expectedKey, widget.stories[widget.Selected].key())
+		})
+	}
+}
+
+func TestRestoreSelection_ScrollOffset(t *testing.T) {
+	widget := newTestWidget(t, &Settings{})
+	widget.stories = selectionTestStories("a", "b", "c")
+	widget.View.ScrollTo(7, 0)
+
+	rebuild(widget, selectionTestStories("new", "a", "b", "c"))
+
+	row, _ := widget.View.GetScrollOffset()
+	assert.Equal(t, 7, row)
+	assert.Equal(t, -1, widget.Selected)
+
+	widget.settings.jumpToTopOnRefresh = true
+	rebuild(widget, selectionTestStories("a"))
+
+	row, _ = widget.View.GetScrollOffset()
+	assert.Equal(t, 0, row)
+}
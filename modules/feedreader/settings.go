@@ -1,19 +1,38 @@
 package feedreader
 
 import (
+	"fmt"
+	"hash/fnv"
+	"log"
+	"net/url"
+	"runtime/debug"
+	"sort"
+	"time"
+
 	"github.com/olebedev/config"
 	"github.com/wtfutil/wtf/cfg"
 	"github.com/wtfutil/wtf/utils"
 )
 
 const (
-	defaultFocusable = true
-	defaultTitle     = "Feed Reader"
+	defaultFocusable            = true
+	defaultTitle                = "Feed Reader"
+	defaultFeedTimeout          = 10
+	defaultMaxConcurrentFetches = 5
+	defaultFailureThreshold     = 3
+	defaultPageSize             = 25
+	defaultUnwrapTimeout        = 5
 )
 
 type colors struct {
 	source      string `help:"Color to use for feed source titles." optional:"true" default:"green"`
 	publishDate string `help:"Color to use for publish dates." optional:"true" default:"orange"`
+	ageFresh    string `help:"Color to use for the age of items younger than ageThresholds.fresh." optional:"true" default:"green"`
+	ageStale    string `help:"Color to use for the age of items older than ageThresholds.stale." optional:"true" default:"gray"`
+	favorite    string `help:"Color to use for the favorite marker." optional:"true" default:"yellow"`
+	newItem     string `help:"Color to use for the NEW marker of items that just arrived in feeds with notify on." optional:"true" default:"red"`
+
+	sourcePalette []string `help:"Colors to pick each feed's source color from, by a hash of its alias or title, so every feed keeps the same one. A feed's own color takes precedence. When empty, every feed uses colors.source." optional:"true"`
 }
 
 // auth stores [username, password]-credentials for private RSS feeds using Basic Auth
@@ -22,20 +41,75 @@ type auth struct {
 	password string
 }
 
+// feedOptions override the global settings for a single feed
+type feedOptions struct {
+	alias     string
+	color     string
+	maxHeight int
+	limit     int
+	notify    bool
+}
+
+// filterPatterns are the regular expressions an item's title and content are matched against
+type filterPatterns struct {
+	include []string
+	exclude []string
+}
+
+// filterSettings are the patterns applied to every feed, plus those scoped to a single feed by
+// its URL, title or alias
+type filterSettings struct {
+	filterPatterns
+	feeds map[string]filterPatterns
+}
+
 // Settings defines the configuration properties for this module
 type Settings struct {
 	*cfg.Common
 
 	colors
 
-	feeds           []string        `help:"An array of RSS and Atom feed URLs"`
-	feedLimit       int             `help:"The maximum number of stories to display for each feed"`
-	showSource      bool            `help:"Wether or not to show feed source in front of item titles." values:"true or false" optional:"true" default:"true"`
-	showPublishDate bool            `help:"Wether or not to show publish date in front of item titles." values:"true or false" optional:"true" default:"false"`
-	dateFormat      string          `help:"Date format to use for publish dates" values:"Any valid Go time layout which is handled by Time.Format" optional:"true" default:"Jan 02"`
-	credentials     map[string]auth `help:"Map of private feed URLs with required authentication credentials"`
-	disableHTTP2    bool            `help:"Wether or not to use the HTTP/2 protocol. Certain sites, such as reddit.com, will not work unless HTTP/2 is disabled." values:"true or false" optional:"true" default:"false"`
-	userAgent       string          `help:"HTTP User-Agent to use when fetching RSS feeds." optional:"true"`
+	feeds           []string               `help:"An array of RSS and Atom feed URLs"`
+	feedLimit       int                    `help:"The maximum number of stories to display for each feed. Also settable as maxItemsPerFeed"`
+	showSource      bool                   `help:"Wether or not to show feed source in front of item titles." values:"true or false" optional:"true" default:"true"`
+	showPublishDate bool                   `help:"Wether or not to show publish date in front of item titles." values:"true or false" optional:"true" default:"false"`
+	dateFormat      string                 `help:"Date format to use for publish dates" values:"Any valid Go time layout which is handled by Time.Format, or relative for how long ago, such as 3 hours ago. Items without a publish date show when they were last updated" optional:"true" default:"Jan 02"`
+	credentials     map[string]auth        `help:"Map of private feed URLs with required authentication credentials"`
+	feedOptions     map[string]feedOptions `help:"Per-feed alias, color, maxHeight, limit and notify settings, given by listing feeds as a map of URLs to settings" optional:"true"`
+	disableHTTP2    bool                   `help:"Wether or not to use the HTTP/2 protocol. Certain sites, such as reddit.com, will not work unless HTTP/2 is disabled." values:"true or false" optional:"true" default:"false"`
+	userAgent       string                 `help:"HTTP User-Agent to use when fetching RSS feeds." optional:"true" default:"wtfutil/<version>"`
+	proxyURL        *url.URL               `help:"HTTP proxy to fetch feeds through, instead of the one set in the environment." optional:"true"`
+
+	feedTimeout            time.Duration  `help:"The maximum number of seconds to wait for a single feed to be fetched." optional:"true" default:"10"`
+	maxConcurrentFetches   int            `help:"The maximum number of feeds to fetch at the same time." optional:"true" default:"5"`
+	groupByFeed            bool           `help:"Whether to list items under a header for each feed, which can be collapsed with z or the left and right keys." values:"true or false" optional:"true" default:"false"`
+	sortByDate             bool           `help:"Whether or not to interleave the items of all feeds, newest first. When false, items are grouped by feed." values:"true or false" optional:"true" default:"false"`
+	showAge                bool           `help:"Whether or not to show how old each item is, such as 3h or 2d." values:"true or false" optional:"true" default:"false"`
+	ageFreshUnder          time.Duration  `help:"Items younger than this are shown with colors.ageFresh." values:"A number of seconds or a duration such as 6h" optional:"true" default:"6h"`
+	ageStaleAfter          time.Duration  `help:"Items older than this are shown with colors.ageStale." values:"A number of seconds or a duration such as 48h" optional:"true" default:"48h"`
+	maxHeight              int            `help:"The maximum number of content lines to show for each item when displaying title+content. 0 shows all of them. auto shares the widget's height between the items." optional:"true" default:"0"`
+	autoHeightMin          int            `help:"The minimum number of content lines each item gets with maxHeight: auto. Set as autoHeight.min." optional:"true" default:"1"`
+	autoHeightMax          int            `help:"The maximum number of content lines each item gets with maxHeight: auto. 0 doesn't bound it. Set as autoHeight.max." optional:"true" default:"0"`
+	contentFallback        bool           `help:"Whether items without content show their description, or failing that their title, when displaying title+content." values:"true or false" optional:"true" default:"true"`
+	showLinksInContent     bool           `help:"Whether or not to show the URL after each link in the item content." values:"true or false" optional:"true" default:"true"`
+	favoritesPath          string         `help:"File the starred items are saved to. Relative paths are relative to the WTF config directory." optional:"true" default:"<module name>-favorites.yml"`
+	readStatePath          string         `help:"File the keys of read items are saved to. Relative paths are relative to the WTF config directory." optional:"true" default:"<module name>-read.yml"`
+	hideRead               bool           `help:"Whether to hide items once they have been read." values:"true or false" optional:"true" default:"false"`
+	showUnreadCountInTitle bool           `help:"Whether to show the number of unread items in the title, such as Feeds (12)." values:"true or false" optional:"true" default:"false"`
+	favoriteMarker         string         `help:"Marker shown in front of starred items." optional:"true" default:"★"`
+	enclosureMarker        string         `help:"Marker shown in front of items with enclosures, such as podcast episodes." optional:"true" default:"♪"`
+	playerCommand          string         `help:"Command to open enclosures with, such as mpv. The enclosure URL is passed as the last argument. When empty, enclosures open in the browser." optional:"true"`
+	notifyCommand          string         `help:"Command to run for each new item of feeds with notify on. The item title and link are passed as the last two arguments." optional:"true"`
+	filterOnSearch         bool           `help:"Whether searching lists only the matching items. When false, the list stays whole and n/N jump between matches." values:"true or false" optional:"true" default:"true"`
+	failureThreshold       int            `help:"Items of feeds that failed this many times in a row are marked. 0 disables the marker." optional:"true" default:"3"`
+	pageSize               int            `help:"The number of items to show at first. More are loaded a page at a time with m. 0 shows every item." optional:"true" default:"25"`
+	jumpToTopOnRefresh     bool           `help:"Whether to clear the selection and scroll back to the top on every refresh, instead of keeping the selected item selected." values:"true or false" optional:"true" default:"false"`
+	unwrapRedirects        bool           `help:"Whether to follow the redirects of item links, such as those of feedburner, with a HEAD request, and show and open the URL they end up at." values:"true or false" optional:"true" default:"false"`
+	unwrapTimeout          time.Duration  `help:"The maximum number of seconds to wait for the redirects of a single link to be followed." optional:"true" default:"5"`
+	filters                filterSettings `help:"Case-insensitive regular expressions to include or exclude items by title and content. Takes include and exclude lists, and a feeds map of the same, keyed by feed URL, title or alias." optional:"true"`
+
+	// maxHeightAuto is set by maxHeight: auto
+	maxHeightAuto bool
 }
 
 // NewSettingsFromYAML creates a new settings instance from a YAML config block
@@ -43,46 +117,219 @@ func NewSettingsFromYAML(name string, ymlConfig, globalConfig *config.Config) *S
 	settings := &Settings{
 		Common:          cfg.NewCommonSettingsFromModule(name, defaultTitle, defaultFocusable, ymlConfig, globalConfig),
 		feeds:           utils.ToStrs(ymlConfig.UList("feeds")),
-		feedLimit:       ymlConfig.UInt("feedLimit", -1),
+		feedLimit:       ymlConfig.UInt("maxItemsPerFeed", ymlConfig.UInt("feedLimit", -1)),
 		showSource:      ymlConfig.UBool("showSource", true),
 		showPublishDate: ymlConfig.UBool("showPublishDate", false),
 		dateFormat:      ymlConfig.UString("dateFormat", "Jan 02"),
 		credentials:     make(map[string]auth),
+		feedOptions:     make(map[string]feedOptions),
 		disableHTTP2:    ymlConfig.UBool("disableHTTP2", false),
-		userAgent:       ymlConfig.UString("userAgent", "wtfutil (https://github.com/wtfutil/wtf)"),
+		userAgent:       ymlConfig.UString("userAgent", defaultUserAgent()),
+
+		feedTimeout:            time.Duration(ymlConfig.UInt("feedTimeout", defaultFeedTimeout)) * time.Second,
+		maxConcurrentFetches:   ymlConfig.UInt("maxConcurrentFetches", defaultMaxConcurrentFetches),
+		groupByFeed:            ymlConfig.UBool("groupByFeed", false),
+		sortByDate:             ymlConfig.UBool("sortByDate", false),
+		showAge:                ymlConfig.UBool("showAge", false),
+		ageFreshUnder:          cfg.ParseTimeString(ymlConfig, "ageThresholds.fresh", "6h"),
+		ageStaleAfter:          cfg.ParseTimeString(ymlConfig, "ageThresholds.stale", "48h"),
+		maxHeight:              ymlConfig.UInt("maxHeight", 0),
+		maxHeightAuto:          ymlConfig.UString("maxHeight", "") == autoMaxHeight,
+		autoHeightMin:          ymlConfig.UInt("autoHeight.min", 1),
+		autoHeightMax:          ymlConfig.UInt("autoHeight.max", 0),
+		contentFallback:        ymlConfig.UBool("contentFallback", true),
+		showLinksInContent:     ymlConfig.UBool("showLinksInContent", true),
+		favoritesPath:          favoritesPath(ymlConfig.UString("favoritesFile", name+"-favorites.yml")),
+		readStatePath:          favoritesPath(ymlConfig.UString("readStateFile", name+"-read.yml")),
+		hideRead:               ymlConfig.UBool("hideRead", false),
+		showUnreadCountInTitle: ymlConfig.UBool("showUnreadCountInTitle", false),
+		favoriteMarker:         ymlConfig.UString("favoriteMarker", "★"),
+		enclosureMarker:        ymlConfig.UString("enclosureMarker", "♪"),
+		playerCommand:          ymlConfig.UString("playerCommand", ""),
+		notifyCommand:          ymlConfig.UString("notifyCommand", ""),
+		filterOnSearch:         ymlConfig.UBool("filterOnSearch", true),
+		pageSize:               ymlConfig.UInt("pageSize", defaultPageSize),
+		jumpToTopOnRefresh:     ymlConfig.UBool("jumpToTopOnRefresh", false),
+		failureThreshold:       ymlConfig.UInt("failureThreshold", defaultFailureThreshold),
+		unwrapRedirects:        ymlConfig.UBool("unwrapRedirects", false),
+		unwrapTimeout:          time.Duration(ymlConfig.UInt("unwrapTimeout", defaultUnwrapTimeout)) * time.Second,
+	}
+
+	settings.filters = parseFilterSettings(ymlConfig)
+
+	proxyURL, err := parseProxyURL(ymlConfig.UString("proxyURL", ""))
+	if err != nil {
+		log.Fatalf("%s: %v", name, err)
 	}
+	settings.proxyURL = proxyURL
 
 	settings.source = ymlConfig.UString("colors.source", "green")
 	settings.publishDate = ymlConfig.UString("colors.publishDate", "orange")
+	settings.colors.ageFresh = ymlConfig.UString("colors.ageFresh", "green")
+	settings.colors.ageStale = ymlConfig.UString("colors.ageStale", "gray")
+	settings.colors.favorite = ymlConfig.UString("colors.favorite", "yellow")
+	settings.colors.newItem = ymlConfig.UString("colors.newItem", "red")
+	settings.colors.sourcePalette = utils.ToStrs(ymlConfig.UList("colors.sourcePalette"))
 
-	// If feeds cannot be parsed as list try parsing as a map with username+password fields
+	// If feeds cannot be parsed as list try parsing as a map of per-feed settings
 	if len(settings.feeds) == 0 {
-		credentials := make(map[string]auth)
-		feeds := make([]string, 0)
-		for url, creds := range ymlConfig.UMap("feeds") {
-			parsed, ok := creds.(map[string]interface{})
-			if !ok {
-				continue
-			}
+		settings.feeds, settings.credentials, settings.feedOptions = parseFeedMap(ymlConfig)
+	}
 
-			user, ok := parsed["username"].(string)
-			if !ok {
-				continue
-			}
-			pass, ok := parsed["password"].(string)
-			if !ok {
-				continue
-			}
+	return settings
+}
+
+// defaultUserAgent identifies WTF and its version (ex: "wtfutil/v0.43.0")
+func defaultUserAgent() string {
+	version := "dev"
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		version = info.Main.Version
+	}
+
+	return "wtfutil/" + version
+}
+
+// parseProxyURL parses the proxyURL setting. An empty setting means no proxy
+func parseProxyURL(raw string) (*url.URL, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxyURL %q: %w", raw, err)
+	}
+
+	if proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxyURL %q: expected a URL such as http://proxy.example.com:8080", raw)
+	}
+
+	return proxyURL, nil
+}
+
+// parseFeedMap reads feeds given as a map of URLs to their settings, which are all optional:
+//
+//	feeds:
+//	  https://example.com/releases.xml:
+//	    alias: Releases
+//	    color: yellow
+//	    maxHeight: 6
+//	    limit: 3
+//	    notify: true
+//	  https://example.com/private.xml:
+//	    username: me
+//	    password: secret
+//
+// Feeds are returned sorted by URL, as YAML maps are unordered
+func parseFeedMap(ymlConfig *config.Config) ([]string, map[string]auth, map[string]feedOptions) {
+	feeds := make([]string, 0)
+	credentials := make(map[string]auth)
+	options := make(map[string]feedOptions)
 
+	for url, value := range ymlConfig.UMap("feeds") {
+		feeds = append(feeds, url)
+
+		parsed, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		user, hasUser := parsed["username"].(string)
+		pass, hasPass := parsed["password"].(string)
+		if hasUser && hasPass {
 			credentials[url] = auth{
 				username: user,
 				password: pass,
 			}
-			feeds = append(feeds, url)
 		}
-		settings.feeds = feeds
-		settings.credentials = credentials
+
+		alias, _ := parsed["alias"].(string)
+		color, _ := parsed["color"].(string)
+		maxHeight, _ := parsed["maxHeight"].(int)
+		limit, _ := parsed["limit"].(int)
+		notify, _ := parsed["notify"].(bool)
+
+		options[url] = feedOptions{
+			alias:     alias,
+			color:     color,
+			maxHeight: maxHeight,
+			limit:     limit,
+			notify:    notify,
+		}
 	}
 
-	return settings
+	sort.Strings(feeds)
+
+	return feeds, credentials, options
+}
+
+// limitFor returns the maximum number of items to take from a feed. Below 1 means all of them
+func (settings *Settings) limitFor(feedURL string) int {
+	if options, ok := settings.feedOptions[feedURL]; ok && options.limit > 0 {
+		return options.limit
+	}
+
+	return settings.feedLimit
+}
+
+// maxHeightFor returns the maximum number of content lines to show for a feed's items.
+// Below 1 means all of them
+func (settings *Settings) maxHeightFor(feedURL string) int {
+	if options, ok := settings.feedOptions[feedURL]; ok && options.maxHeight > 0 {
+		return options.maxHeight
+	}
+
+	return settings.maxHeight
+}
+
+// sourceColorFor returns the color of a feed's source title: its own color, or else the one
+// its source name hashes to in the palette, or else colors.source
+func (settings *Settings) sourceColorFor(feedURL, source string) string {
+	if options, ok := settings.feedOptions[feedURL]; ok && options.color != "" {
+		return options.color
+	}
+
+	if len(settings.sourcePalette) > 0 {
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(source))
+		return settings.sourcePalette[hash.Sum32()%uint32(len(settings.sourcePalette))]
+	}
+
+	return settings.source
+}
+
+// parseFilterSettings reads the filters block:
+//
+//	filters:
+//	  exclude: ["sponsored"]
+//	  feeds:
+//	    LWN.net:
+//	      include: ["kernel"]
+func parseFilterSettings(ymlConfig *config.Config) filterSettings {
+	filters := filterSettings{
+		filterPatterns: filterPatterns{
+			include: utils.ToStrs(ymlConfig.UList("filters.include")),
+			exclude: utils.ToStrs(ymlConfig.UList("filters.exclude")),
+		},
+		feeds: make(map[string]filterPatterns),
+	}
+
+	for feed, value := range ymlConfig.UMap("filters.feeds") {
+		parsed, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		patterns := filterPatterns{}
+		if include, ok := parsed["include"].([]interface{}); ok {
+			patterns.include = utils.ToStrs(include)
+		}
+		if exclude, ok := parsed["exclude"].([]interface{}); ok {
+			patterns.exclude = utils.ToStrs(exclude)
+		}
+
+		filters.feeds[feed] = patterns
+	}
+
+	return filters
 }
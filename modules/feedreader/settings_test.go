@@ -0,0 +1,107 @@
+package feedreader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/olebedev/config"
+	"gotest.tools/assert"
+)
+
+func newTestSettings(t *testing.T, yaml string) *Settings {
+	t.Helper()
+
+	ymlConfig, err := config.ParseYaml(yaml)
+	assert.NilError(t, err)
+
+	globalConfig, err := config.ParseYaml("global: {}")
+	assert.NilError(t, err)
+
+	settings := NewSettingsFromYAML("feedreader", ymlConfig, globalConfig)
+	// Leave the favorites and read state paths for newTestWidget to point at temporary files
+	settings.favoritesPath = ""
+	settings.readStatePath = ""
+
+	return settings
+}
+
+func TestNewSettingsFromYAML_FeedLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		expected int
+	}{
+		{name: "unlimited by default", yaml: "enabled: true", expected: -1},
+		{name: "feedLimit", yaml: "feedLimit: 3", expected: 3},
+		{name: "maxItemsPerFeed", yaml: "maxItemsPerFeed: 4", expected: 4},
+		{name: "maxItemsPerFeed wins", yaml: "feedLimit: 3\nmaxItemsPerFeed: 4", expected: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := newTestSettings(t, tt.yaml)
+			assert.Equal(t, tt.expected, settings.feedLimit)
+		})
+	}
+}
+
+func TestNewSettingsFromYAML_FeedMap(t *testing.T) {
+	settings := newTestSettings(t, `
+maxHeight: 1
+feedLimit: 10
+feeds:
+  https://example.com/releases.xml:
+    alias: Releases
+    color: yellow
+    maxHeight: 6
+    limit: 3
+  https://example.com/private.xml:
+    username: me
+    password: secret
+  https://example.com/news.xml:
+`)
+
+	assert.DeepEqual(t, []string{
+		"https://example.com/news.xml",
+		"https://example.com/private.xml",
+		"https://example.com/releases.xml",
+	}, settings.feeds)
+
+	assert.Equal(t, auth{username: "me", password: "secret"}, settings.credentials["https://example.com/private.xml"])
+	assert.Equal(t, 1, len(settings.credentials))
+
+	releases := "https://example.com/releases.xml"
+	assert.Equal(t, feedOptions{alias: "Releases", color: "yellow", maxHeight: 6, limit: 3}, settings.feedOptions[releases])
+	assert.Equal(t, 3, settings.limitFor(releases))
+	assert.Equal(t, 6, settings.maxHeightFor(releases))
+	assert.Equal(t, "yellow", settings.sourceColorFor(releases, "Releases"))
+
+	news := "https://example.com/news.xml"
+	assert.Equal(t, 10, settings.limitFor(news))
+	assert.Equal(t, 1, settings.maxHeightFor(news))
+	assert.Equal(t, "green", settings.sourceColorFor(news, "News"))
+}
+
+func TestNewSettingsFromYAML_UserAgent(t *testing.T) {
+	settings := newTestSettings(t, "enabled: true")
+	assert.Assert(t, strings.HasPrefix(settings.userAgent, "wtfutil/"), settings.userAgent)
+
+	settings = newTestSettings(t, "userAgent: my-reader/1.0")
+	assert.Equal(t, "my-reader/1.0", settings.userAgent)
+}
+
+func TestParseProxyURL(t *testing.T) {
+	proxyURL, err := parseProxyURL("")
+	assert.NilError(t, err)
+	assert.Assert(t, proxyURL == nil)
+
+	proxyURL, err = parseProxyURL("http://proxy.example.com:8080")
+	assert.NilError(t, err)
+	assert.Equal(t, "proxy.example.com:8080", proxyURL.Host)
+
+	_, err = parseProxyURL("::bad")
+	assert.ErrorContains(t, err, `invalid proxyURL "::bad"`)
+
+	_, err = parseProxyURL("proxy.example.com:8080")
+	assert.ErrorContains(t, err, "expected a URL such as")
+}
@@ -0,0 +1,50 @@
+package feedreader
+
+/* -------------------- Unexported Functions -------------------- */
+
+// rowSource returns the name of the feed a row belongs to
+func rowSource(row listRow) string {
+	if row.story == nil {
+		return row.group.name
+	}
+
+	return row.story.source()
+}
+
+// nextSource selects the next item from a different feed than the selected one
+func (widget *Widget) nextSource() {
+	widget.selectSource(1)
+	widget.Render()
+}
+
+// prevSource selects the previous item from a different feed than the selected one
+func (widget *Widget) prevSource() {
+	widget.selectSource(-1)
+	widget.Render()
+}
+
+// selectSource moves the selection by direction to the nearest row from a different feed.
+// Without a selection, the first or last row is selected. The selection stays put when
+// there's no such row, as the feeds may be interleaved, so wrapping around is confusing
+func (widget *Widget) selectSource(direction int) {
+	rows := widget.listRows()
+	if len(rows) == 0 {
+		return
+	}
+
+	if widget.Selected < 0 || widget.Selected >= len(rows) {
+		widget.Selected = 0
+		if direction < 0 {
+			widget.Selected = len(rows) - 1
+		}
+		return
+	}
+
+	source := rowSource(rows[widget.Selected])
+	for idx := widget.Selected + direction; idx >= 0 && idx < len(rows); idx += direction {
+		if rowSource(rows[idx]) != source {
+			widget.Selected = idx
+			return
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package feedreader
+
+import (
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"gotest.tools/assert"
+)
+
+func TestSourceColorFor_Palette(t *testing.T) {
+	settings := &Settings{
+		colors: colors{
+			source:        "green",
+			sourcePalette: []string{"red", "blue", "yellow", "purple", "teal"},
+		},
+		feedOptions: map[string]feedOptions{
+			"https://example.com/releases.xml": {alias: "Releases", color: "orange"},
+		},
+	}
+
+	// The same name always gets the same color, whatever the feed
+	lwn := settings.sourceColorFor("https://lwn.net/headlines/rss", "LWN.net")
+	assert.Equal(t, lwn, settings.sourceColorFor("https://lwn.net/headlines/rss", "LWN.net"))
+	assert.Equal(t, lwn, settings.sourceColorFor("https://lwn.net/other.xml", "LWN.net"))
+	assert.Assert(t, lwn != "green")
+
+	colorsUsed := map[string]bool{}
+	for _, name := range []string{"LWN.net", "Hacker News", "Go Blog", "Lobsters", "Phoronix", "Ars Technica"} {
+		color := settings.sourceColorFor("https://example.com/"+name, name)
+		assert.Assert(t, color != "green", name)
+		colorsUsed[color] = true
+	}
+	assert.Assert(t, len(colorsUsed) > 1, "names should spread over the palette")
+
+	// A feed's own color takes precedence
+	assert.Equal(t, "orange", settings.sourceColorFor("https://example.com/releases.xml", "Releases"))
+
+	// Without a palette, every feed uses the source color
+	settings.sourcePalette = nil
+	assert.Equal(t, "green", settings.sourceColorFor("https://lwn.net/headlines/rss", "LWN.net"))
+	assert.Equal(t, "orange", settings.sourceColorFor("https://example.com/releases.xml", "Releases"))
+}
+
+func TestNewSettingsFromYAML_SourcePalette(t *testing.T) {
+	settings := newTestSettings(t, "colors:\n  sourcePalette: [red, blue]")
+	assert.DeepEqual(t, []string{"red", "blue"}, settings.sourcePalette)
+
+	settings = newTestSettings(t, "enabled: true")
+	assert.Equal(t, 0, len(settings.sourcePalette))
+}
+
+func TestGetShowText_SourcePalette(t *testing.T) {
+	widget := newTestWidget(t, &Settings{
+		colors:     colors{source: "green", sourcePalette: []string{"red", "blue", "yellow"}},
+		showSource: true,
+	})
+	story := &FeedItem{item: &gofeed.Item{Title: "Headline"}, sourceTitle: "News"}
+
+	color := widget.settings.sourceColorFor("", "News")
+	assert.Equal(t, "["+color+"]News [white]Headline", widget.getShowText(story, "white"))
+}
+
+func interleavedStories() []*FeedItem {
+	story := func(guid, source string) *FeedItem {
+		return &FeedItem{item: &gofeed.Item{GUID: guid, Title: guid}, sourceTitle: source}
+	}
+
+	return []*FeedItem{
+		story("a1", "A"),
+		story("a2", "A"),
+		story("b1", "B"),
+		story("a3", "A"),
+		story("c1", "C"),
+		story("c2", "C"),
+	}
+}
+
+func TestSelectSource(t *testing.T) {
+	widget := newTestWidget(t, &Settings{})
+	rebuild(widget, interleavedStories())
+
+	// Without a selection, the first row is selected
+	widget.selectSource(1)
+	assert.Equal(t, 0, widget.Selected)
+
+	var selected []int
+	for i := 0; i < 5; i++ {
+		widget.selectSource(1)
+		selected = append(selected, widget.Selected)
+	}
+	// The selection stops at the last feed
+	assert.DeepEqual(t, []int{2, 3, 4, 4, 4}, selected)
+
+	widget.Selected = 5
+	selected = nil
+	for i := 0; i < 5; i++ {
+		widget.selectSource(-1)
+		selected = append(selected, widget.Selected)
+	}
+	assert.DeepEqual(t, []int{3, 2, 1, 1, 1}, selected)
+
+	// Without a selection, going back selects the last row
+	widget.Selected = -1
+	widget.selectSource(-1)
+	assert.Equal(t, 5, widget.Selected)
+}
+
+func TestSelectSource_Grouped(t *testing.T) {
+	widget := newGroupTestWidget(t)
+
+	// Releases, v1.1, v1.2, News, Headline
+	widget.Selected = 1
+	widget.selectSource(1)
+	assert.Equal(t, 3, widget.Selected)
+	assert.Equal(t, "group:News", widget.listRows()[widget.Selected].key())
+
+	widget.selectSource(-1)
+	assert.Equal(t, 2, widget.Selected)
+}
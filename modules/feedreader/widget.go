@@ -1,19 +1,20 @@
 package feedreader
 
 import (
-	"crypto/tls"
 	"fmt"
 	"html"
 	"net/http"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/mmcdole/gofeed"
 	"github.com/rivo/tview"
 	"github.com/wtfutil/wtf/utils"
 	"github.com/wtfutil/wtf/view"
-	"jaytaylor.com/html2text"
 )
 
 type ShowType int
@@ -27,19 +28,97 @@ const (
 // FeedItem represents an item returned from an RSS or Atom feed
 type FeedItem struct {
 	item        *gofeed.Item
+	feedURL     string
+	alias       string
 	sourceTitle string
 	viewed      bool
+	// finalLink is where the link redirects to, when redirects are unwrapped
+	finalLink string
+}
+
+// key identifies the item across refreshes: its GUID, falling back to its link, then title
+func (feedItem *FeedItem) key() string {
+	switch {
+	case feedItem.item.GUID != "":
+		return feedItem.item.GUID
+	case feedItem.item.Link != "":
+		return feedItem.item.Link
+	default:
+		return feedItem.item.Title
+	}
+}
+
+// names returns the names a feed can be referred to by in the settings: its URL, title and alias
+func (feedItem *FeedItem) names() []string {
+	return []string{feedItem.feedURL, feedItem.sourceTitle, feedItem.alias}
+}
+
+// source returns the name to show for the item's feed: its alias, or else its title
+func (feedItem *FeedItem) source() string {
+	if feedItem.alias != "" {
+		return feedItem.alias
+	}
+
+	return feedItem.sourceTitle
+}
+
+// content returns the item's content. With fallback, items without content, such as those
+// of feeds that only publish summaries, fall back to their description, then their title
+func (feedItem *FeedItem) content(fallback bool) string {
+	if !fallback || strings.TrimSpace(feedItem.item.Content) != "" {
+		return feedItem.item.Content
+	}
+	if strings.TrimSpace(feedItem.item.Description) != "" {
+		return feedItem.item.Description
+	}
+
+	return feedItem.item.Title
+}
+
+// date returns when the item was published, falling back to when it was last updated.
+// Returns nil if the item has neither
+func (feedItem *FeedItem) date() *time.Time {
+	if feedItem.item.PublishedParsed != nil {
+		return feedItem.item.PublishedParsed
+	}
+
+	return feedItem.item.UpdatedParsed
 }
 
 // Widget is the container for RSS and Atom data
 type Widget struct {
 	view.ScrollableWidget
 
-	stories  []*FeedItem
-	parser   *gofeed.Parser
-	settings *Settings
-	err      error
-	showType ShowType
+	stories        []*FeedItem
+	client         *http.Client
+	redirects      map[string]string
+	redirectsMu    sync.Mutex
+	settings       *Settings
+	filters        *feedFilters
+	filteredCount  int
+	feedErrors     []*FeedError
+	favorites      *favorites
+	favoritesErr   error
+	readState      *readState
+	readStateErr   error
+	showFavorites  bool
+	collapsed      map[string]bool
+	seen           map[string]bool
+	newItems       map[string]bool
+	notifyErr      error
+	playerErr      error
+	search         string
+	loadedPages    int
+	health         map[string]*feedHealth
+	showFeedStatus bool
+	pages          *tview.Pages
+	tviewApp       *tview.Application
+	showType       ShowType
+
+	// autoHeight is the number of content lines each item gets with maxHeight: auto, budgeted
+	// for the autoHeightRows rows the widget was last drawn with
+	autoHeight     atomic.Int64
+	autoHeightRows atomic.Int64
 }
 
 func rotateShowType(showtype ShowType) ShowType {
@@ -57,72 +136,56 @@ func rotateShowType(showtype ShowType) ShowType {
 
 // NewWidget creates a new instance of a widget
 func NewWidget(tviewApp *tview.Application, redrawChan chan bool, pages *tview.Pages, settings *Settings) *Widget {
-	parser := gofeed.NewParser()
-	if settings.disableHTTP2 {
-		// If HTTP/2 is disabled, we override the parser client
-		// with a client using a simple HTTP transport which
-		// removes the client's default behavior of first
-		// trying HTTP/2 before downgrading to older protocol
-		// versions.
-		parser.Client = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					MinVersion: tls.VersionTLS12,
-					MaxVersion: tls.VersionTLS13,
-				},
-			},
-		}
-	}
-
-	parser.UserAgent = settings.userAgent
-
 	widget := &Widget{
 		ScrollableWidget: view.NewScrollableWidget(tviewApp, redrawChan, pages, settings.Common),
 
-		parser:   parser,
-		settings: settings,
-		showType: SHOW_TITLE,
+		client:      newHTTPClient(settings),
+		pages:       pages,
+		settings:    settings,
+		tviewApp:    tviewApp,
+		filters:     newFeedFilters(settings.filters),
+		showType:    SHOW_TITLE,
+		loadedPages: 1,
+		collapsed:   make(map[string]bool),
+		redirects:   make(map[string]string),
 	}
 
+	widget.favorites, widget.favoritesErr = loadFavorites(settings.favoritesPath)
+	widget.readState, widget.readStateErr = loadReadState(settings.readStatePath)
+
 	widget.SetRenderFunction(widget.Render)
 	widget.initializeKeyboardControls()
 
+	if settings.maxHeightAuto {
+		widget.View.SetDrawFunc(widget.drawAutoHeight)
+	}
+
 	return widget
 }
 
 /* -------------------- Exported Functions -------------------- */
 
-// Fetch retrieves RSS and Atom feed data
-func (widget *Widget) Fetch(feedURLs []string) ([]*FeedItem, error) {
-	var data []*FeedItem
-
-	for _, feedURL := range feedURLs {
-		feedItems, err := widget.fetchForFeed(feedURL)
-		if err != nil {
-			return nil, err
+// Refresh updates the data in the widget
+func (widget *Widget) Refresh() {
+	feedItems, feedErrors := widget.Fetch(widget.settings.feeds)
+	widget.applyReadState(feedItems)
+	if len(feedErrors) == 0 {
+		// Only forget read items when every feed could be checked for them
+		if err := widget.readState.prune(feedItems); err != nil {
+			widget.readStateErr = err
 		}
-
-		data = append(data, feedItems...)
 	}
+	feedItems, filteredCount := widget.filters.apply(feedItems)
+	widget.trackNewItems(feedItems)
 
-	data = widget.sort(data)
+	anchor := widget.captureSelection()
 
-	return data, nil
-}
-
-// Refresh updates the data in the widget
-func (widget *Widget) Refresh() {
-	feedItems, err := widget.Fetch(widget.settings.feeds)
-	if err != nil {
-		widget.err = err
-		widget.stories = nil
-		widget.SetItemCount(0)
-	} else {
-		widget.err = nil
-		widget.stories = feedItems
-		widget.SetItemCount(len(feedItems))
-	}
+	widget.feedErrors = feedErrors
+	widget.filteredCount = filteredCount
+	widget.stories = feedItems
+	widget.SetItemCount(len(widget.listRows()))
 
+	widget.restoreSelection(anchor)
 	widget.Render()
 }
 
@@ -133,82 +196,153 @@ func (widget *Widget) Render() {
 
 /* -------------------- Unexported Functions -------------------- */
 
-func (widget *Widget) fetchForFeed(feedURL string) ([]*FeedItem, error) {
-	var (
-		feed *gofeed.Feed
-		err  error
-	)
-	if auth, isPrivateRSS := widget.settings.credentials[feedURL]; isPrivateRSS {
-		widget.parser.AuthConfig = &gofeed.Auth{
-			Username: auth.username,
-			Password: auth.password,
-		}
-		feed, err = widget.parser.ParseURL(feedURL)
-		widget.parser.AuthConfig = nil
-	} else {
-		feed, err = widget.parser.ParseURL(feedURL)
+func (widget *Widget) content() (string, string, bool) {
+	title := widget.unreadTitle(widget.CommonSettings().Title)
+	if widget.showFeedStatus {
+		return widget.statusContent(title)
+	}
+	if widget.showFavorites {
+		return widget.favoritesContent(title)
 	}
 
-	if err != nil {
-		return nil, err
+	data := widget.visibleStories()
+	widget.budgetHeight(len(data))
+
+	if len(widget.stories) == 0 && len(widget.feedErrors) == 0 && len(widget.warnings()) == 0 && widget.filteredCount == 0 {
+		return title, "No data", false
 	}
 
-	var feedItems []*FeedItem
+	str := widget.searchIndicator()
+	for _, warning := range widget.warnings() {
+		str += fmt.Sprintf("[yellow]⚠ %s[white]\n", tview.Escape(warning))
+	}
 
-	for idx, gofeedItem := range feed.Items {
-		if widget.settings.feedLimit >= 1 && idx >= widget.settings.feedLimit {
-			// We only want to get the widget.settings.feedLimit latest articles,
-			// not all of them. To get all, set feedLimit to < 1
-			break
-		}
+	// Feeds that failed get a single line each, so they don't hide the ones that worked
+	for _, feedErr := range widget.feedErrors {
+		str += fmt.Sprintf("[gray]⚠ %s[white]\n", tview.Escape(feedErr.summary()))
+	}
 
-		feedItem := &FeedItem{
-			item:        gofeedItem,
-			sourceTitle: feed.Title,
-			viewed:      false,
-		}
+	if widget.grouping() {
+		str += widget.groupedRows(widget.listRows())
+	} else {
+		str += widget.storyRows(data)
+	}
+	str += widget.pagingFooter()
 
-		feedItems = append(feedItems, feedItem)
+	if widget.filteredCount > 0 {
+		str += fmt.Sprintf("[gray](%d filtered)[white]\n", widget.filteredCount)
 	}
 
-	return feedItems, nil
+	return title, str, false
 }
 
-func (widget *Widget) content() (string, string, bool) {
-	title := widget.CommonSettings().Title
-	if widget.err != nil {
-		return title, widget.err.Error(), true
-	}
-	data := widget.stories
-	if len(data) == 0 {
-		return title, "No data", false
-	}
+// storyRows renders one highlightable row per item
+func (widget *Widget) storyRows(data []*FeedItem) string {
 	var str string
 
 	for idx, feedItem := range data {
-		rowColor := widget.RowColor(idx)
-
-		if feedItem.viewed {
-			// Grays out viewed items in the list, while preserving background highlighting when selected
-			rowColor = "gray"
-			if idx == widget.Selected {
-				rowColor = fmt.Sprintf("gray:%s", widget.settings.Colors.HighlightedBackground)
-			}
+		str += widget.storyRow(feedItem, idx, idx+1)
+	}
+
+	return str
+}
+
+// storyRow renders the item on row idx, numbered number
+func (widget *Widget) storyRow(feedItem *FeedItem, idx, number int) string {
+	rowColor := widget.RowColor(idx)
+
+	if feedItem.viewed {
+		// Grays out viewed items in the list, while preserving background highlighting when selected
+		rowColor = "gray"
+		if idx == widget.Selected {
+			rowColor = fmt.Sprintf("gray:%s", widget.settings.Colors.HighlightedBackground)
 		}
+	}
 
-		displayText := widget.getShowText(feedItem, rowColor)
+	displayText := widget.getShowText(feedItem, rowColor)
 
-		row := fmt.Sprintf(
-			"[%s]%2d. %s[white]",
-			rowColor,
-			idx+1,
-			displayText,
-		)
+	// The age only goes on the first line, so multiline items keep their block shape
+	age := ""
+	if widget.settings.showAge {
+		age = widget.ageColumn(feedItem, rowColor)
+	}
 
-		str += utils.HighlightableHelper(widget.View, row, idx, len(feedItem.item.Title))
+	marker := ""
+	if widget.newItems[feedItem.key()] {
+		marker += fmt.Sprintf("[%s]%s[%s] ", widget.settings.colors.newItem, newItemMarker, rowColor)
+	}
+	if widget.favorites.has(feedItem) {
+		marker += fmt.Sprintf("[%s]%s[%s] ", widget.settings.colors.favorite, widget.settings.favoriteMarker, rowColor)
+	}
+	if feedItem.enclosure() != nil && widget.settings.enclosureMarker != "" {
+		marker += widget.settings.enclosureMarker + " "
+	}
+	if widget.isFailing(feedItem) {
+		marker += fmt.Sprintf("[gray]%s[%s] ", failingMarker, rowColor)
 	}
 
-	return title, str, false
+	row := fmt.Sprintf(
+		"[%s]%2d. %s%s%s[white]",
+		rowColor,
+		number,
+		age,
+		marker,
+		displayText,
+	)
+
+	// Long lines are cut at the edge of the widget rather than pushing the layout off it
+	_, _, width, _ := widget.View.GetInnerRect()
+	return utils.HighlightableBlockHelper(widget.View, row, idx, width, "…")
+}
+
+// visibleStories returns the listed items on the pages loaded so far
+func (widget *Widget) visibleStories() []*FeedItem {
+	return widget.pagedStories(widget.listedStories())
+}
+
+// listedStories returns the items currently listed: every story, or only the favorites,
+// narrowed down to those matching the search when filtering
+func (widget *Widget) listedStories() []*FeedItem {
+	stories := widget.unsearchedStories()
+	if widget.search != "" && widget.settings.filterOnSearch {
+		return searchItems(stories, widget.search)
+	}
+
+	return stories
+}
+
+// unsearchedStories returns the items currently listed, ignoring any search
+func (widget *Widget) unsearchedStories() []*FeedItem {
+	if widget.showFeedStatus {
+		return nil
+	}
+	if widget.showFavorites {
+		return widget.favorites.feedItems(widget.stories)
+	}
+	if widget.settings.hideRead {
+		return unreadItems(widget.stories)
+	}
+
+	return widget.stories
+}
+
+// warnings returns the problems with the widget's own configuration and files
+func (widget *Widget) warnings() []string {
+	warnings := append([]string{}, widget.filters.warnings...)
+	if widget.favoritesErr != nil {
+		warnings = append(warnings, widget.favoritesErr.Error())
+	}
+	if widget.readStateErr != nil {
+		warnings = append(warnings, widget.readStateErr.Error())
+	}
+	if widget.notifyErr != nil {
+		warnings = append(warnings, widget.notifyErr.Error())
+	}
+	if widget.playerErr != nil {
+		warnings = append(warnings, widget.playerErr.Error())
+	}
+
+	return warnings
 }
 
 func (widget *Widget) getShowText(feedItem *FeedItem, rowColor string) string {
@@ -221,11 +355,11 @@ func (widget *Widget) getShowText(feedItem *FeedItem, rowColor string) string {
 	publishDate := ""
 	title := space.ReplaceAllString(feedItem.item.Title, " ")
 
-	if widget.settings.showSource && feedItem.sourceTitle != "" {
-		source = "[" + widget.settings.source + "]" + feedItem.sourceTitle + " "
+	if widget.settings.showSource && feedItem.source() != "" {
+		source = "[" + widget.settings.sourceColorFor(feedItem.feedURL, feedItem.source()) + "]" + feedItem.source() + " "
 	}
-	if widget.settings.showPublishDate && feedItem.item.Published != "" {
-		publishDate = "[" + widget.settings.publishDate + "]" + feedItem.item.PublishedParsed.Format(widget.settings.dateFormat) + " "
+	if date := widget.formatPublishDate(feedItem); date != "" {
+		publishDate = "[" + widget.settings.publishDate + "]" + date + " "
 	}
 
 	// Convert any escaped characters to their character representation
@@ -233,35 +367,60 @@ func (widget *Widget) getShowText(feedItem *FeedItem, rowColor string) string {
 
 	switch widget.showType {
 	case SHOW_LINK:
-		return feedItem.item.Link
+		return feedItem.link()
 	case SHOW_CONTENT:
-		text, _ := html2text.FromString(feedItem.item.Content, html2text.Options{PrettyTables: true})
-		return strings.TrimSpace(title + "\n" + strings.TrimSpace(text))
+		text := tview.Escape(htmlToText(feedItem.content(widget.settings.contentFallback), widget.settings.showLinksInContent))
+		text = truncateLines(text, widget.maxHeightFor(feedItem.feedURL))
+		return strings.TrimSpace(title + "\n" + text)
 	default:
 		return title
 	}
 }
 
-// feedItems are sorted by published date
+// truncateLines keeps the first maxLines lines of text, marking the cut with an ellipsis.
+// Below 1 keeps every line
+func truncateLines(text string, maxLines int) string {
+	lines := strings.Split(text, "\n")
+	if maxLines < 1 || len(lines) <= maxLines {
+		return text
+	}
+
+	return strings.Join(lines[:maxLines], "\n") + "…"
+}
+
+// sort interleaves the items of every feed newest first, when sortByDate is on. Items without
+// a date go last, keeping their feed order
 func (widget *Widget) sort(feedItems []*FeedItem) []*FeedItem {
-	sort.Slice(feedItems, func(i, j int) bool {
-		return feedItems[i].item.PublishedParsed != nil &&
-			feedItems[j].item.PublishedParsed != nil &&
-			feedItems[i].item.PublishedParsed.After(*feedItems[j].item.PublishedParsed)
+	if !widget.settings.sortByDate {
+		return feedItems
+	}
+
+	sort.SliceStable(feedItems, func(i, j int) bool {
+		iDate := feedItems[i].date()
+		jDate := feedItems[j].date()
+
+		switch {
+		case iDate == nil:
+			return false
+		case jDate == nil:
+			return true
+		default:
+			return iDate.After(*jDate)
+		}
 	})
 
 	return feedItems
 }
 
 func (widget *Widget) openStory() {
-	sel := widget.GetSelected()
+	story := widget.selectedStory()
+	if story == nil {
+		return
+	}
 
-	if sel >= 0 && widget.stories != nil && sel < len(widget.stories) {
-		story := widget.stories[sel]
-		story.viewed = true
+	widget.markRead([]*FeedItem{story})
 
-		utils.OpenFile(story.item.Link)
-	}
+	utils.OpenFile(story.link())
 }
 
 func (widget *Widget) toggleDisplayText() {
@@ -1,18 +1,30 @@
 package feedreader
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mmcdole/gofeed"
 	"gotest.tools/assert"
 )
 
 func Test_getShowText(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	originalNow := nowFunc
+	defer func() { nowFunc = originalNow }()
+	nowFunc = func() time.Time { return now }
+
+	published := now.Add(-3 * time.Hour)
+	updated := now.Add(-2 * 24 * time.Hour)
+
 	tests := []struct {
-		name     string
-		feedItem *FeedItem
-		showType ShowType
-		expected string
+		name       string
+		feedItem   *FeedItem
+		showType   ShowType
+		dateFormat string
+		maxHeight  int
+		expected   string
 	}{
 		{
 			name:     "with nil FeedItem",
@@ -61,6 +73,52 @@ func Test_getShowText(t *testing.T) {
 			showType: SHOW_LINK,
 			expected: "https://cats.com/dog.xml",
 		},
+		{
+			name: "with publish date",
+			feedItem: &FeedItem{
+				item: &gofeed.Item{Title: "Cats and Dogs", Published: "Fri, 10 May 2024 09:00:00 GMT", PublishedParsed: &published},
+			},
+			showType:   SHOW_TITLE,
+			dateFormat: "Jan 02 15:04",
+			expected:   "[orange]May 10 09:00 [white]Cats and Dogs",
+		},
+		{
+			name: "with relative publish date",
+			feedItem: &FeedItem{
+				item: &gofeed.Item{Title: "Cats and Dogs", PublishedParsed: &published},
+			},
+			showType:   SHOW_TITLE,
+			dateFormat: relativeDateFormat,
+			expected:   "[orange]3 hours ago [white]Cats and Dogs",
+		},
+		{
+			name: "with update date only",
+			feedItem: &FeedItem{
+				item: &gofeed.Item{Title: "Cats and Dogs", UpdatedParsed: &updated},
+			},
+			showType:   SHOW_TITLE,
+			dateFormat: "Jan 02",
+			expected:   "[orange]May 08 [white]Cats and Dogs",
+		},
+		{
+			name: "with unparsed publish date",
+			feedItem: &FeedItem{
+				item: &gofeed.Item{Title: "Cats and Dogs", Published: "sometime last week"},
+			},
+			showType:   SHOW_TITLE,
+			dateFormat: "Jan 02",
+			expected:   "[white]Cats and Dogs",
+		},
+		{
+			name: "with publish date and content",
+			feedItem: &FeedItem{
+				item: &gofeed.Item{Title: "Cats and Dogs", Content: "<p>one</p><p>two</p>", PublishedParsed: &published},
+			},
+			showType:   SHOW_CONTENT,
+			dateFormat: relativeDateFormat,
+			maxHeight:  2,
+			expected:   "[orange]3 hours ago [white]Cats and Dogs\none\ntwo",
+		},
 	}
 
 	for _, tt := range tests {
@@ -71,7 +129,10 @@ func Test_getShowText(t *testing.T) {
 						source:      "green",
 						publishDate: "orange",
 					},
-					showSource: true,
+					showSource:      true,
+					showPublishDate: tt.dateFormat != "",
+					dateFormat:      tt.dateFormat,
+					maxHeight:       tt.maxHeight,
 				},
 				showType: tt.showType,
 			}
@@ -82,3 +143,127 @@ func Test_getShowText(t *testing.T) {
 		})
 	}
 }
+
+func Test_sort(t *testing.T) {
+	day := func(d int) *time.Time {
+		date := time.Date(2024, 5, d, 12, 0, 0, 0, time.UTC)
+		return &date
+	}
+
+	items := []*FeedItem{
+		{item: &gofeed.Item{Title: "a-old", PublishedParsed: day(1)}, sourceTitle: "A"},
+		{item: &gofeed.Item{Title: "a-undated"}, sourceTitle: "A"},
+		{item: &gofeed.Item{Title: "a-updated", UpdatedParsed: day(3)}, sourceTitle: "A"},
+		{item: &gofeed.Item{Title: "b-new", PublishedParsed: day(4)}, sourceTitle: "B"},
+		{item: &gofeed.Item{Title: "b-undated"}, sourceTitle: "B"},
+		{item: &gofeed.Item{Title: "b-mid", PublishedParsed: day(2), UpdatedParsed: day(5)}, sourceTitle: "B"},
+	}
+
+	titles := func(feedItems []*FeedItem) []string {
+		result := []string{}
+		for _, feedItem := range feedItems {
+			result = append(result, feedItem.item.Title)
+		}
+		return result
+	}
+
+	t.Run("in feed order by default", func(t *testing.T) {
+		widget := &Widget{settings: &Settings{}}
+		sorted := widget.sort(append([]*FeedItem{}, items...))
+
+		assert.DeepEqual(t, titles(items), titles(sorted))
+	})
+
+	t.Run("newest first with undated items last", func(t *testing.T) {
+		widget := &Widget{settings: &Settings{sortByDate: true}}
+		sorted := widget.sort(append([]*FeedItem{}, items...))
+
+		assert.DeepEqual(
+			t,
+			[]string{"b-new", "a-updated", "b-mid", "a-old", "a-undated", "b-undated"},
+			titles(sorted),
+		)
+	})
+}
+
+func Test_content_numbersSortedItems(t *testing.T) {
+	widget := newTestWidget(t, &Settings{sortByDate: true})
+
+	older := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	widget.stories = widget.sort([]*FeedItem{
+		{item: &gofeed.Item{Title: "Older", PublishedParsed: &older}},
+		{item: &gofeed.Item{Title: "Newer", PublishedParsed: &newer}},
+	})
+
+	_, content, _ := widget.content()
+
+	assert.Assert(t, strings.Contains(content, " 1. [:]Newer"), content)
+	assert.Assert(t, strings.Contains(content, " 2. [:]Older"), content)
+	assert.Equal(t, "Newer", widget.stories[0].item.Title)
+}
+
+func Test_content_perFeedHeights(t *testing.T) {
+	releases := "https://example.com/releases.xml"
+	news := "https://example.com/news.xml"
+
+	widget := newTestWidget(t, &Settings{
+		colors:     colors{source: "green"},
+		showSource: true,
+		maxHeight:  1,
+		feedOptions: map[string]feedOptions{
+			releases: {alias: "Releases", color: "yellow", maxHeight: 3},
+		},
+	})
+	widget.showType = SHOW_CONTENT
+
+	content := "<p>one</p><p>two</p><p>three</p><p>four</p>"
+	widget.stories = []*FeedItem{
+		{item: &gofeed.Item{Title: "v1.2", Content: content}, feedURL: releases, alias: "Releases", sourceTitle: "Example Releases"},
+		{item: &gofeed.Item{Title: "Headline", Content: content}, feedURL: news, sourceTitle: "Example News"},
+	}
+
+	assert.Equal(t, "[yellow]Releases [white]v1.2\none\ntwo\nthree…", widget.getShowText(widget.stories[0], "white"))
+	assert.Equal(t, "[green]Example News [white]Headline\none…", widget.getShowText(widget.stories[1], "white"))
+
+	_, rendered, _ := widget.content()
+	assert.Equal(t, 6, strings.Count(strings.TrimSpace(rendered), "\n")+1, rendered)
+}
+
+func Test_content_cutsLongLines(t *testing.T) {
+	widget := newTestWidget(t, &Settings{})
+	widget.View.SetRect(0, 0, 16, 10)
+	widget.showType = SHOW_CONTENT
+
+	widget.stories = []*FeedItem{
+		{item: &gofeed.Item{Title: "Über", Content: "<p>A paragraph that is too long</p><p>Short</p>"}},
+	}
+
+	_, content, _ := widget.content()
+	lines := strings.Split(content, "\n")
+
+	assert.Equal(t, `["0"][""][:] 1. [:]Über        `, lines[0])
+	assert.Equal(t, "A paragraph tha…", lines[1])
+	assert.Equal(t, "Short[white]"+strings.Repeat(" ", 11)+`[""]`, lines[2])
+}
+
+func Test_content_fallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     *gofeed.Item
+		fallback bool
+		expected string
+	}{
+		{name: "content", item: &gofeed.Item{Title: "T", Description: "D", Content: "C"}, fallback: true, expected: "C"},
+		{name: "description", item: &gofeed.Item{Title: "T", Description: "D"}, fallback: true, expected: "D"},
+		{name: "blank content", item: &gofeed.Item{Title: "T", Description: "D", Content: " \n"}, fallback: true, expected: "D"},
+		{name: "title", item: &gofeed.Item{Title: "T"}, fallback: true, expected: "T"},
+		{name: "disabled", item: &gofeed.Item{Title: "T", Description: "D"}, fallback: false, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, (&FeedItem{item: tt.item}).content(tt.fallback))
+		})
+	}
+}
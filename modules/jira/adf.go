@@ -0,0 +1,200 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// adfInlineNodes are the ADF nodes that are part of a line of text. Any other node is a
+// block, shown on lines of its own
+var adfInlineNodes = map[string]bool{
+	"date":        true,
+	"emoji":       true,
+	"hardBreak":   true,
+	"inlineCard":  true,
+	"mediaInline": true,
+	"mention":     true,
+	"status":      true,
+	"text":        true,
+}
+
+// ADFNode is a node of an Atlassian Document Format document, the JSON the Jira API sends
+// rich text such as descriptions in. A document is a tree of nodes, with a node of type
+// "doc" at its root
+type ADFNode struct {
+	Type    string                 `json:"type"`
+	Text    string                 `json:"text"`
+	Attrs   map[string]interface{} `json:"attrs"`
+	Content []ADFNode              `json:"content"`
+}
+
+// UnmarshalJSON reads a node, or plain text, which older Jira APIs send rich text as
+func (node *ADFNode) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*node = ADFNode{Type: "text", Text: text}
+		return nil
+	}
+
+	// A type of its own, so that json doesn't call UnmarshalJSON again
+	type adfNode ADFNode
+
+	return json.Unmarshal(data, (*adfNode)(node))
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+// ADFToText converts an ADF document to plain text. Blocks, such as paragraphs, are put on
+// lines of their own, and list items start with a dash or their number. Mentions, emoji,
+// dates and cards are shown as the text they stand for
+//
+// Example:
+//
+//	x := ADFToText(description)
+//	> "Crash on start, see @Jane Doe\n- Open the app\n- Wait 😀"
+func ADFToText(node *ADFNode) string {
+	if node == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(adfText(*node))
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// adfFirstParagraph returns the plain text of the first paragraph of an ADF document that
+// has any, on one line, or else of the first line of text in it
+func adfFirstParagraph(node *ADFNode) string {
+	if node == nil {
+		return ""
+	}
+
+	if paragraph, ok := findADFParagraph(*node); ok {
+		return paragraph
+	}
+
+	for _, line := range strings.Split(ADFToText(node), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			return line
+		}
+	}
+
+	return ""
+}
+
+// findADFParagraph returns the text of the first paragraph under node that has any, with
+// its hard breaks and runs of spaces turned into single spaces
+func findADFParagraph(node ADFNode) (string, bool) {
+	if node.Type == "paragraph" {
+		text := strings.Join(strings.Fields(adfText(node)), " ")
+		return text, text != ""
+	}
+
+	for _, child := range node.Content {
+		if text, ok := findADFParagraph(child); ok {
+			return text, true
+		}
+	}
+
+	return "", false
+}
+
+// adfText renders a node, and the nodes under it, as plain text
+func adfText(node ADFNode) string {
+	switch node.Type {
+	case "text":
+		return node.Text
+	case "hardBreak":
+		return "\n"
+	case "mention":
+		text := adfAttr(node, "text")
+		if text == "" {
+			text = adfAttr(node, "id")
+		}
+		return "@" + strings.TrimPrefix(text, "@")
+	case "emoji":
+		if text := adfAttr(node, "text"); text != "" {
+			return text
+		}
+		return adfAttr(node, "shortName")
+	case "date":
+		return adfDate(adfAttr(node, "timestamp"))
+	case "inlineCard", "blockCard", "embedCard":
+		return adfAttr(node, "url")
+	case "status":
+		return adfAttr(node, "text")
+	case "rule":
+		return "---"
+	case "bulletList", "orderedList":
+		return adfList(node)
+	}
+
+	parts := make([]string, 0, len(node.Content))
+	inline := true
+	for _, child := range node.Content {
+		parts = append(parts, adfText(child))
+		inline = inline && adfInlineNodes[child.Type]
+	}
+
+	if inline {
+		return strings.Join(parts, "")
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// adfList renders the items of a list on lines of their own, starting with a dash, or with
+// their number for ordered lists. The lines of an item after the first are indented
+func adfList(node ADFNode) string {
+	number := 1
+	if order := adfAttr(node, "order"); order != "" {
+		if start, err := strconv.Atoi(order); err == nil {
+			number = start
+		}
+	}
+
+	lines := []string{}
+	for _, item := range node.Content {
+		marker := "-"
+		if node.Type == "orderedList" {
+			marker = fmt.Sprintf("%d.", number)
+			number++
+		}
+
+		text := strings.ReplaceAll(adfText(item), "\n", "\n  ")
+		lines = append(lines, marker+" "+text)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// adfAttr returns the attribute key of node as text, or "" when it has none
+func adfAttr(node ADFNode, key string) string {
+	value, ok := node.Attrs[key]
+	if !ok || value == nil {
+		return ""
+	}
+
+	switch value := value.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// adfDate formats the timestamp of a date node, in milliseconds since the epoch, as a day.
+// Timestamps that can't be read are returned as they are
+func adfDate(timestamp string) string {
+	millis, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return timestamp
+	}
+
+	return time.UnixMilli(millis).UTC().Format("2006-01-02")
+}
@@ -0,0 +1,207 @@
+package jira
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/olebedev/config"
+	"gotest.tools/assert"
+)
+
+// adfDescription is a description as the Jira API sends it, with a mention, emoji, hard
+// breaks and a list
+const adfDescription = `{
+	"type": "doc",
+	"version": 1,
+	"content": [
+		{"type": "heading", "attrs": {"level": 2}, "content": [{"type": "text", "text": "Deploy failed"}]},
+		{"type": "paragraph", "content": [
+			{"type": "text", "text": "Nightly deploy failed, "},
+			{"type": "mention", "attrs": {"id": "5b10ac8d82e05b22cc7d4ef5", "text": "@Jane Doe", "accessLevel": ""}},
+			{"type": "text", "text": " can you look? "},
+			{"type": "emoji", "attrs": {"shortName": ":pray:", "id": "1f64f", "text": "🙏"}},
+			{"type": "hardBreak"},
+			{"type": "text", "text": "Started ", "marks": [{"type": "strong"}]},
+			{"type": "date", "attrs": {"timestamp": "1710460800000"}}
+		]},
+		{"type": "bulletList", "content": [
+			{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Check the logs"}]}]},
+			{"type": "listItem", "content": [{"type": "paragraph", "content": [
+				{"type": "text", "text": "Rerun it "},
+				{"type": "emoji", "attrs": {"shortName": ":custom_ship_it:", "id": "atlassian-ship"}}
+			]}]}
+		]}
+	]
+}`
+
+func parseADF(t *testing.T, data string) *ADFNode {
+	t.Helper()
+
+	node := &ADFNode{}
+	assert.NilError(t, json.Unmarshal([]byte(data), node))
+
+	return node
+}
+
+func TestADFToText(t *testing.T) {
+	assert.Equal(
+		t,
+		"Deploy failed\n"+
+			"Nightly deploy failed, @Jane Doe can you look? 🙏\n"+
+			"Started 2024-03-15\n"+
+			"- Check the logs\n"+
+			"- Rerun it :custom_ship_it:",
+		ADFToText(parseADF(t, adfDescription)),
+	)
+}
+
+func TestADFToText_Nodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		adf      string
+		expected string
+	}{
+		{
+			name:     "nil",
+			adf:      `null`,
+			expected: "",
+		},
+		{
+			name:     "plain text",
+			adf:      `"Crash on start\nafter the update"`,
+			expected: "Crash on start\nafter the update",
+		},
+		{
+			name:     "mention without text",
+			adf:      `{"type": "doc", "content": [{"type": "paragraph", "content": [{"type": "mention", "attrs": {"id": "5b10ac8d"}}]}]}`,
+			expected: "@5b10ac8d",
+		},
+		{
+			name:     "mention without @",
+			adf:      `{"type": "doc", "content": [{"type": "paragraph", "content": [{"type": "mention", "attrs": {"id": "5b10ac8d", "text": "Jane"}}]}]}`,
+			expected: "@Jane",
+		},
+		{
+			name:     "ordered list",
+			adf:      `{"type": "doc", "content": [{"type": "orderedList", "attrs": {"order": 3}, "content": [{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "one"}, {"type": "hardBreak"}, {"type": "text", "text": "more"}]}]}, {"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "two"}]}]}]}]}`,
+			expected: "3. one\n  more\n4. two",
+		},
+		{
+			name:     "cards and statuses",
+			adf:      `{"type": "doc", "content": [{"type": "paragraph", "content": [{"type": "status", "attrs": {"text": "IN QA", "color": "blue"}}, {"type": "text", "text": " "}, {"type": "inlineCard", "attrs": {"url": "https://example.com/1"}}]}]}`,
+			expected: "IN QA https://example.com/1",
+		},
+		{
+			name:     "unknown nodes",
+			adf:      `{"type": "doc", "content": [{"type": "panel", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Heads up"}]}]}, {"type": "mediaSingle", "content": [{"type": "media", "attrs": {"id": "1"}}]}]}`,
+			expected: "Heads up",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var node *ADFNode
+			assert.NilError(t, json.Unmarshal([]byte(tt.adf), &node))
+
+			assert.Equal(t, tt.expected, ADFToText(node))
+		})
+	}
+}
+
+func TestADFFirstParagraph(t *testing.T) {
+	tests := []struct {
+		name     string
+		adf      string
+		expected string
+	}{
+		{
+			name:     "first paragraph, on one line",
+			adf:      adfDescription,
+			expected: "Nightly deploy failed, @Jane Doe can you look? 🙏 Started 2024-03-15",
+		},
+		{
+			name:     "blank paragraphs are skipped",
+			adf:      `{"type": "doc", "content": [{"type": "paragraph", "content": [{"type": "hardBreak"}]}, {"type": "paragraph", "content": [{"type": "text", "text": "Second"}]}]}`,
+			expected: "Second",
+		},
+		{
+			name:     "no paragraph",
+			adf:      `{"type": "doc", "content": [{"type": "codeBlock", "content": [{"type": "text", "text": "\n  panic: nil map\n"}]}]}`,
+			expected: "panic: nil map",
+		},
+		{
+			name:     "plain text",
+			adf:      `"\nFirst line\nSecond line"`,
+			expected: "First line",
+		},
+		{
+			name:     "empty",
+			adf:      `{"type": "doc", "content": []}`,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, adfFirstParagraph(parseADF(t, tt.adf)))
+		})
+	}
+}
+
+func TestContent_Description(t *testing.T) {
+	ymlConfig, err := config.ParseYaml("enabled: true")
+	assert.NilError(t, err)
+	globalConfig, err := config.ParseYaml("global: {}")
+	assert.NilError(t, err)
+
+	widget := NewWidget(nil, make(chan bool, 1), nil, NewSettingsFromYAML("jira", ymlConfig, globalConfig))
+	widget.View.SetRect(0, 0, 60, 10)
+
+	issue := func(summary string, description *ADFNode) Issue {
+		return Issue{
+			Key: "WTF-1",
+			IssueFields: &IssueFields{
+				Summary:     summary,
+				Description: description,
+				IssueType:   &IssueType{Name: "Task"},
+				IssueStatus: &IssueStatus{IName: "Open"},
+			},
+		}
+	}
+
+	widget.result = &SearchResult{Issues: []Issue{
+		issue("Crash on start", parseADF(t, adfDescription)),
+		issue("", parseADF(t, adfDescription)),
+		issue("", parseADF(t, `"[Build] failed"`)),
+		issue("", nil),
+	}}
+
+	_, content, _ := widget.content()
+
+	lines := strings.Split(strings.TrimSuffix(displayed(content), "\n"), "\n")
+	assert.Equal(t, 5, len(lines))
+	assert.Assert(t, strings.HasSuffix(strings.TrimRight(lines[1], " "), " Crash on start"), lines[1])
+	// Cut to the width of the widget, less its border
+	assert.Equal(t, " Task  WTF-1    Open  (desc) Nightly deploy failed, @Jane…", strings.TrimRight(lines[2], " "))
+	assert.Assert(t, strings.HasSuffix(strings.TrimRight(lines[3], " "), " Open  (desc) [Build] failed"), lines[3])
+	assert.Assert(t, strings.HasSuffix(strings.TrimRight(lines[4], " "), " Open"), lines[4])
+}
+
+func TestDetailsText_Description(t *testing.T) {
+	widget := newTestWidget(t)
+
+	issue := issueWithLinks()
+	issue.IssueFields.Description = parseADF(t, adfDescription)
+
+	text := displayed(widget.detailsText(issue))
+	assert.Assert(
+		t,
+		strings.Contains(text, " Description\n  Deploy failed\n  Nightly deploy failed, @Jane Doe can you look? 🙏\n  Started 2024-03-15\n  - Check the logs\n"),
+		text,
+	)
+
+	issue.IssueFields.Description = nil
+	text = displayed(widget.detailsText(issue))
+	assert.Assert(t, !strings.Contains(text, "Description"), text)
+}
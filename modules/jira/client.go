@@ -2,7 +2,6 @@ package jira
 
 import (
 	"bytes"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,6 +14,13 @@ import (
 	"github.com/wtfutil/wtf/utils"
 )
 
+// maxResponseBytes is the largest response the Jira API is expected to send. Larger
+// responses are rejected rather than read into memory
+const maxResponseBytes = 10 << 20
+
+// retryPolicy is how requests that are rate limited, or hit a server error, are retried
+var retryPolicy = utils.DefaultRetryPolicy()
+
 // UserIDCache represent a cached username to account ID mapping
 type UserIDCache struct {
 	AccountID string
@@ -27,11 +33,6 @@ type UserIDCacheMap struct {
 	mutex sync.RWMutex
 }
 
-// Global cache instance
-var userIDCache = &UserIDCacheMap{
-	cache: make(map[string]UserIDCache),
-}
-
 // JQLConversionRequest represents the request body for the JQL conversion API
 type JQLConversionRequest struct {
 	QueryStrings []string `json:"queryStrings"`
@@ -106,7 +107,8 @@ func (c *UserIDCacheMap) Clear() {
 // ConvertJQLWithUsername converts a JQL query containing username to account ID
 func (widget *Widget) ConvertJQLWithUsername(username string) (string, error) {
 	// Check cache first
-	if accountID, found := userIDCache.Get(username); found {
+	userIDs := widget.shared().userIDs
+	if accountID, found := userIDs.Get(username); found {
 		return fmt.Sprintf("assignee = \"%s\"", accountID), nil
 	}
 
@@ -125,13 +127,8 @@ func (widget *Widget) ConvertJQLWithUsername(username string) (string, error) {
 	}
 
 	// Make the POST request to the JQL conversion API
-	resp, err := widget.jiraPostRequest("/rest/api/3/jql/pdcleaner", jsonData)
-	if err != nil {
-		return "", err
-	}
-
 	var conversionResult JQLConversionResponse
-	err = utils.ParseJSON(&conversionResult, bytes.NewReader(resp))
+	err = widget.jiraPostRequest("/rest/api/3/jql/pdcleaner", jsonData, &conversionResult)
 	if err != nil {
 		return "", err
 	}
@@ -150,7 +147,7 @@ func (widget *Widget) ConvertJQLWithUsername(username string) (string, error) {
 	}
 
 	// Cache the result for 10 minutes
-	userIDCache.Set(username, accountID, 10*time.Minute)
+	userIDs.Set(username, accountID, 10*time.Minute)
 
 	return convertedQuery, nil
 }
@@ -216,11 +213,6 @@ func (widget *Widget) searchWithNewAPI(jql string) (*SearchResult, error) {
 
 	jqlURL := fmt.Sprintf("/rest/api/3/search/jql?%s", v.Encode())
 
-	resp, err := widget.jiraRequest(jqlURL)
-	if err != nil {
-		return nil, err
-	}
-
 	// Parse the JQL response which contains issue IDs
 	type JQLSearchResult struct {
 		Issues []struct {
@@ -229,9 +221,9 @@ func (widget *Widget) searchWithNewAPI(jql string) (*SearchResult, error) {
 	}
 
 	jqlResult := &JQLSearchResult{}
-	err = utils.ParseJSON(jqlResult, bytes.NewReader(resp))
+	err := widget.jiraRequest(jqlURL, jqlResult)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse JQL search response: %v", err)
+		return nil, err
 	}
 
 	if len(jqlResult.Issues) == 0 {
@@ -250,8 +242,8 @@ func (widget *Widget) searchWithNewAPI(jql string) (*SearchResult, error) {
 
 		fullIssue, err := widget.getIssueByID(issue.ID)
 		if err != nil {
-			// Log error but continue with other issues
-			fmt.Printf("Error fetching issue %s: %v\n", issue.ID, err)
+			// Keep the error, to show it, and continue with the other issues
+			searchResult.fetchErrors = append(searchResult.fetchErrors, err)
 			continue
 		}
 		searchResult.Issues = append(searchResult.Issues, *fullIssue)
@@ -261,16 +253,14 @@ func (widget *Widget) searchWithNewAPI(jql string) (*SearchResult, error) {
 } // getIssueByID fetches full issue details by ID
 func (widget *Widget) getIssueByID(issueID string) (*Issue, error) {
 	url := fmt.Sprintf("/rest/api/3/issue/%s", issueID)
-
-	resp, err := widget.jiraRequest(url)
-	if err != nil {
-		return nil, err
+	if widget.settings.showTimeInStatus && widget.settings.timeInStatusFromChangelog {
+		url += "?expand=changelog"
 	}
 
 	issue := &Issue{}
-	err = utils.ParseJSON(issue, bytes.NewReader(resp))
+	err := widget.jiraRequest(url, issue)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse issue %s: %v", issueID, err)
+		return nil, fmt.Errorf("failed to fetch issue %s: %v", issueID, err)
 	}
 
 	return issue, nil
@@ -282,88 +272,69 @@ func buildJql(key string, value string) string {
 
 /* -------------------- Unexported Functions -------------------- */
 
-func (widget *Widget) jiraRequest(path string) ([]byte, error) {
-	url := fmt.Sprintf("%s%s", widget.settings.domain, path)
-
-	req, err := http.NewRequest("GET", url, http.NoBody)
-	if err != nil {
-		return nil, err
-	}
-	if widget.settings.personalAccessToken != "" {
-		req.Header.Set("Authorization", "Bearer "+widget.settings.personalAccessToken)
-	} else {
-		req.SetBasicAuth(widget.settings.email, widget.settings.apiKey)
-	}
+// jiraRequest GETs path from the Jira API, and decodes the JSON response into result
+func (widget *Widget) jiraRequest(path string, result interface{}) error {
+	return widget.doRequest("GET", path, nil, "JIRA API error", result)
+}
 
-	httpClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: !widget.settings.verifyServerCertificate,
-			},
-			Proxy: http.ProxyFromEnvironment,
-		},
-	}
+// jiraPostRequest POSTs data to path on the Jira API, and decodes the JSON response into
+// result
+func (widget *Widget) jiraPostRequest(path string, data []byte, result interface{}) error {
+	return widget.doRequest("POST", path, data, "JIRA API POST error", result)
+}
 
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = resp.Body.Close() }()
+// doRequest makes a request to the Jira API, and decodes the JSON response into result. The
+// same request, made by another widget on the domain while this one runs, shares its
+// response. Responses that aren't a success are errors, starting with errLabel
+func (widget *Widget) doRequest(method, path string, data []byte, errLabel string, result interface{}) error {
+	url := fmt.Sprintf("%s%s", widget.settings.domain, path)
 
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("JIRA API error - %s: %s (URL: %s)", resp.Status, string(body), url)
+	body := io.Reader(http.NoBody)
+	if data != nil {
+		body = bytes.NewReader(data)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	req, err := http.NewRequest(method, url, body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return body, nil
-}
-
-func (widget *Widget) jiraPostRequest(path string, data []byte) ([]byte, error) {
-	url := fmt.Sprintf("%s%s", widget.settings.domain, path)
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
-	if err != nil {
-		return nil, err
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
 	}
-
-	req.Header.Set("Content-Type", "application/json")
 	if widget.settings.personalAccessToken != "" {
 		req.Header.Set("Authorization", "Bearer "+widget.settings.personalAccessToken)
 	} else {
 		req.SetBasicAuth(widget.settings.email, widget.settings.apiKey)
 	}
 
-	httpClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: !widget.settings.verifyServerCertificate,
-			},
-			Proxy: http.ProxyFromEnvironment,
-		},
-	}
+	httpClient := widget.shared().client(widget.settings.verifyServerCertificate)
 
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = resp.Body.Close() }()
+	response, err, _ := inflight.Do(requestKey(req, data), func() (interface{}, error) {
+		resp, err := utils.DoWithRetry(httpClient, req, retryPolicy)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+			return nil, fmt.Errorf("%s - %s: %s (URL: %s)", errLabel, resp.Status, string(body), url)
+		}
 
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("JIRA API POST error - %s: %s (URL: %s)", resp.Status, string(body), url)
+		// One byte past the limit is read, for parsing to tell oversized responses apart
+		return io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	})
+	if err != nil {
+		return err
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	err = utils.ParseJSONLimited(result, bytes.NewReader(response.([]byte)), maxResponseBytes)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to parse the response of %s: %w", path, err)
 	}
 
-	return body, nil
+	return nil
 }
 
 func getProjectQuery(projects []string) string {
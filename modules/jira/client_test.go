@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -126,18 +127,19 @@ func TestExtractAccountIDFromJQL(t *testing.T) {
 }
 
 func TestConvertJQLWithUsername_CacheHit(t *testing.T) {
-	// Setup a mock widget (minimal setup for testing)
-	widget := &Widget{}
+	useSharedDomains(t)
 
-	// Clear and setup cache
-	userIDCache = &UserIDCacheMap{
-		cache: make(map[string]UserIDCache),
+	// Setup a mock widget (minimal setup for testing)
+	widget := &Widget{
+		settings: &Settings{
+			domain: "https://jira.example.com",
+		},
 	}
 
 	// Pre-populate cache
 	username := "cacheduser"
 	accountID := "account:cached123"
-	userIDCache.Set(username, accountID, 5*time.Minute)
+	sharedFor("https://jira.example.com").userIDs.Set(username, accountID, 5*time.Minute)
 
 	// Test that cached value is returned without API call
 	result, err := widget.ConvertJQLWithUsername(username)
@@ -178,9 +180,7 @@ func TestConvertJQLWithUsername_APICalls(t *testing.T) {
 	}
 
 	// Clear cache
-	userIDCache = &UserIDCacheMap{
-		cache: make(map[string]UserIDCache),
-	}
+	useSharedDomains(t)
 
 	// Test API call
 	result, err := widget.ConvertJQLWithUsername("testuser")
@@ -189,12 +189,14 @@ func TestConvertJQLWithUsername_APICalls(t *testing.T) {
 	assert.Equal(t, `assignee = "account:5b10ac8d82e05b22cc7d4ef5"`, result)
 
 	// Verify it was cached
-	cachedID, found := userIDCache.Get("testuser")
+	cachedID, found := widget.shared().userIDs.Get("testuser")
 	assert.Equal(t, true, found)
 	assert.Equal(t, "account:5b10ac8d82e05b22cc7d4ef5", cachedID)
 }
 
 func TestConvertJQLWithUsername_APIError(t *testing.T) {
+	useFastRetries(t)
+
 	// Create a mock server that returns an error
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -210,9 +212,7 @@ func TestConvertJQLWithUsername_APIError(t *testing.T) {
 	}
 
 	// Clear cache
-	userIDCache = &UserIDCacheMap{
-		cache: make(map[string]UserIDCache),
-	}
+	useSharedDomains(t)
 
 	// Test API error handling
 	result, err := widget.ConvertJQLWithUsername("testuser")
@@ -241,9 +241,7 @@ func TestConvertJQLWithUsername_EmptyResponse(t *testing.T) {
 	}
 
 	// Clear cache
-	userIDCache = &UserIDCacheMap{
-		cache: make(map[string]UserIDCache),
-	}
+	useSharedDomains(t)
 
 	// Test empty response handling
 	result, err := widget.ConvertJQLWithUsername("testuser")
@@ -278,9 +276,7 @@ func TestConvertJQLWithUsername_InvalidAccountID(t *testing.T) {
 	}
 
 	// Clear cache
-	userIDCache = &UserIDCacheMap{
-		cache: make(map[string]UserIDCache),
-	}
+	useSharedDomains(t)
 
 	// Test invalid account ID handling
 	result, err := widget.ConvertJQLWithUsername("testuser")
@@ -288,3 +284,153 @@ func TestConvertJQLWithUsername_InvalidAccountID(t *testing.T) {
 	assert.ErrorContains(t, err, "failed to extract account ID from converted query")
 	assert.Equal(t, "", result)
 }
+
+func TestJiraRequest_Responses(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			body: `{"id": "10001", "key": "AB-1"}`,
+		},
+		{
+			name:        "truncated",
+			body:        `{"id": "10001", "ke`,
+			expectedErr: "failed to parse the response of /rest/api/3/issue/10001: invalid JSON at byte 19",
+		},
+		{
+			name:        "oversized",
+			body:        `{"id": "10001", "key": "` + strings.Repeat("a", maxResponseBytes) + `"}`,
+			expectedErr: "failed to parse the response of /rest/api/3/issue/10001: response exceeded 10485760 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			widget := &Widget{
+				settings: &Settings{
+					domain: server.URL,
+				},
+			}
+
+			issue, err := widget.getIssueByID("10001")
+
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, "AB-1", issue.Key)
+		})
+	}
+}
+
+func TestJiraRequest_Retries(t *testing.T) {
+	useFastRetries(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "10001", "key": "AB-1"}`))
+	}))
+	defer server.Close()
+
+	widget := &Widget{
+		settings: &Settings{
+			domain: server.URL,
+		},
+	}
+
+	issue, err := widget.getIssueByID("10001")
+
+	assert.NilError(t, err)
+	assert.Equal(t, "AB-1", issue.Key)
+	assert.Equal(t, 2, requests)
+}
+
+func TestSearchWithNewAPI_PartialFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/rest/api/3/search/jql":
+			_, _ = w.Write([]byte(`{"issues": [{"id": "10001"}, {"id": "10002"}]}`))
+		case "/rest/api/3/issue/10001":
+			_, _ = w.Write([]byte(`{"id": "10001", "key": "AB-1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	widget := &Widget{
+		settings: &Settings{
+			domain: server.URL,
+		},
+	}
+
+	result, err := widget.searchWithNewAPI("project = AB")
+
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(result.Issues))
+	assert.Equal(t, 1, len(result.fetchErrors))
+	assert.ErrorContains(t, result.fetchErrors[0], "failed to fetch issue 10002")
+}
+
+func TestGetIssueByID_Changelog(t *testing.T) {
+	tests := []struct {
+		name          string
+		settings      Settings
+		expectedQuery string
+	}{
+		{name: "off", settings: Settings{}, expectedQuery: ""},
+		{name: "status category", settings: Settings{showTimeInStatus: true}, expectedQuery: ""},
+		{name: "changelog", settings: Settings{showTimeInStatus: true, timeInStatusFromChangelog: true}, expectedQuery: "expand=changelog"},
+		{name: "changelog without time in status", settings: Settings{timeInStatusFromChangelog: true}, expectedQuery: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := "unset"
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.RawQuery
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id": "10001", "key": "AB-1", "changelog": {"histories": [{"created": "2024-03-14T12:00:00.000+0000", "items": [{"field": "status"}]}]}}`))
+			}))
+			defer server.Close()
+
+			tt.settings.domain = server.URL
+			widget := &Widget{settings: &tt.settings}
+
+			issue, err := widget.getIssueByID("10001")
+
+			assert.NilError(t, err)
+			assert.Equal(t, tt.expectedQuery, query)
+			assert.Equal(t, 1, len(issue.Changelog.Histories))
+		})
+	}
+}
+
+// useFastRetries shortens the delay between retries for the duration of the test
+func useFastRetries(t *testing.T) {
+	previous := retryPolicy
+	t.Cleanup(func() { retryPolicy = previous })
+
+	retryPolicy.BaseDelay = time.Millisecond
+	retryPolicy.MaxDelay = time.Millisecond
+}
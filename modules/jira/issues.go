@@ -7,13 +7,25 @@ type Issue struct {
 	Key    string `json:"key"`
 
 	IssueFields *IssueFields `json:"fields"`
+	Changelog   *Changelog   `json:"changelog"`
 }
 
 type IssueFields struct {
 	Summary string `json:"summary"`
+	// Description is sent with every issue, as all its fields are requested. Rows show it when
+	// the summary is empty
+	Description *ADFNode `json:"description"`
 
 	IssueType   *IssueType   `json:"issuetype"`
 	IssueStatus *IssueStatus `json:"status"`
+	IssueLinks  []IssueLink  `json:"issuelinks"`
+
+	// StatusCategoryChangeDate is when the issue last moved to another status category
+	StatusCategoryChangeDate string `json:"statuscategorychangedate"`
+
+	// Resolution and ResolutionDate are only set for resolved issues
+	Resolution     *Resolution `json:"resolution"`
+	ResolutionDate string      `json:"resolutiondate"`
 }
 
 type IssueType struct {
@@ -29,4 +41,50 @@ type IssueStatus struct {
 	ISelf        string `json:"self"`
 	IDescription string `json:"description"`
 	IName        string `json:"name"`
+
+	StatusCategory *StatusCategory `json:"statusCategory"`
+}
+
+// StatusCategory is the category a status belongs to: to do, in progress or done
+type StatusCategory struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// IssueLink links an issue to another one. Only one of InwardIssue and OutwardIssue is set:
+// the issue on the other end of the link
+type IssueLink struct {
+	ID           string         `json:"id"`
+	Type         *IssueLinkType `json:"type"`
+	InwardIssue  *Issue         `json:"inwardIssue"`
+	OutwardIssue *Issue         `json:"outwardIssue"`
+}
+
+// IssueLinkType is the kind of an IssueLink, such as Blocks, and how each end of it reads,
+// such as "is blocked by" and "blocks"
+type IssueLinkType struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Inward  string `json:"inward"`
+	Outward string `json:"outward"`
+}
+
+// Changelog is the history of the changes to an issue, sent when the issue is requested
+// with expand=changelog
+type Changelog struct {
+	Histories []ChangelogHistory `json:"histories"`
+}
+
+// ChangelogHistory is a change to one or more fields of an issue
+type ChangelogHistory struct {
+	ID      string          `json:"id"`
+	Created string          `json:"created"`
+	Items   []ChangelogItem `json:"items"`
+}
+
+// ChangelogItem is the change to one field of an issue
+type ChangelogItem struct {
+	Field      string `json:"field"`
+	FromString string `json:"fromString"`
+	ToString   string `json:"toString"`
 }
@@ -0,0 +1,122 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	blockerIcon = "⛔"
+	linkIcon    = "🔗"
+
+	blockedByRelation  = "is blocked by"
+	defaultLinkType    = "Blocks"
+	doneStatusCategory = "done"
+	doneStatusName     = "Done"
+
+	// detailsPage is the page the details of the selected issue are shown on
+	detailsPage = "details"
+)
+
+/* -------------------- Unexported Functions -------------------- */
+
+// linksOfInterest returns the links of the issue whose type is one of linkTypes, given by
+// name, such as Blocks, or by relation, such as "is blocked by", in any case
+func linksOfInterest(issue *Issue, linkTypes []string) []IssueLink {
+	links := []IssueLink{}
+	if issue.IssueFields == nil {
+		return links
+	}
+
+	for _, link := range issue.IssueFields.IssueLinks {
+		if link.Type == nil || link.linked() == nil {
+			continue
+		}
+
+		for _, linkType := range linkTypes {
+			if strings.EqualFold(linkType, link.Type.Name) || strings.EqualFold(linkType, link.relation()) {
+				links = append(links, link)
+				break
+			}
+		}
+	}
+
+	return links
+}
+
+// renderLinks returns the suffix shown after an issue's summary for its links: the link
+// itself when there's one, such as "⛔ blocked by PROJ-88", and how many there are when
+// there are more. It's red when one of them blocks the issue, and "" when there are none
+func renderLinks(links []IssueLink) string {
+	if len(links) == 0 {
+		return ""
+	}
+
+	color, icon := "gray", linkIcon
+	for _, link := range links {
+		if link.blocks() {
+			color, icon = "red", blockerIcon
+			break
+		}
+	}
+
+	if len(links) > 1 {
+		return fmt.Sprintf("[%s]%s %d links[white]", color, icon, len(links))
+	}
+
+	link := links[0]
+	return fmt.Sprintf(
+		"[%s]%s %s %s[white]",
+		color,
+		icon,
+		tview.Escape(strings.TrimPrefix(link.relation(), "is ")),
+		tview.Escape(link.linked().Key),
+	)
+}
+
+// linked returns the issue on the other end of the link
+func (link IssueLink) linked() *Issue {
+	if link.InwardIssue != nil {
+		return link.InwardIssue
+	}
+
+	return link.OutwardIssue
+}
+
+// relation returns how the link reads from the issue that has it, such as "is blocked by"
+func (link IssueLink) relation() string {
+	if link.Type == nil {
+		return ""
+	}
+
+	if link.InwardIssue != nil {
+		return link.Type.Inward
+	}
+
+	return link.Type.Outward
+}
+
+// blocks returns true if the linked issue blocks the issue that has the link, and isn't
+// done yet
+func (link IssueLink) blocks() bool {
+	return link.InwardIssue != nil &&
+		strings.EqualFold(link.relation(), blockedByRelation) &&
+		!link.InwardIssue.isDone()
+}
+
+// isDone returns true if the issue's status is in the done category, or is named Done when
+// the category isn't known
+func (issue *Issue) isDone() bool {
+	if issue.IssueFields == nil || issue.IssueFields.IssueStatus == nil {
+		return false
+	}
+
+	status := issue.IssueFields.IssueStatus
+	if status.StatusCategory != nil {
+		return status.StatusCategory.Key == doneStatusCategory
+	}
+
+	return strings.EqualFold(status.IName, doneStatusName)
+}
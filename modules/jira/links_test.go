@@ -0,0 +1,199 @@
+package jira
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/olebedev/config"
+	"gotest.tools/assert"
+)
+
+var (
+	blocksType  = &IssueLinkType{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"}
+	relatesType = &IssueLinkType{Name: "Relates", Inward: "relates to", Outward: "relates to"}
+)
+
+func linkedIssue(key, status, category string) *Issue {
+	issueStatus := &IssueStatus{IName: status}
+	if category != "" {
+		issueStatus.StatusCategory = &StatusCategory{Key: category}
+	}
+
+	return &Issue{
+		Key: key,
+		IssueFields: &IssueFields{
+			Summary:     "Summary of " + key,
+			IssueStatus: issueStatus,
+		},
+	}
+}
+
+func issueWithLinks(links ...IssueLink) *Issue {
+	return &Issue{
+		Key: "WTF-1",
+		IssueFields: &IssueFields{
+			Summary:     "Crash on start",
+			IssueType:   &IssueType{Name: "Bug"},
+			IssueStatus: &IssueStatus{IName: "Open"},
+			IssueLinks:  links,
+		},
+	}
+}
+
+func linkKeys(links []IssueLink) []string {
+	keys := []string{}
+	for _, link := range links {
+		keys = append(keys, link.linked().Key)
+	}
+
+	return keys
+}
+
+func TestLinksOfInterest(t *testing.T) {
+	issue := issueWithLinks(
+		IssueLink{Type: blocksType, InwardIssue: linkedIssue("PROJ-88", "In Progress", "indeterminate")},
+		IssueLink{Type: blocksType, OutwardIssue: linkedIssue("PROJ-90", "To Do", "new")},
+		IssueLink{Type: relatesType, OutwardIssue: linkedIssue("PROJ-12", "Done", "done")},
+		IssueLink{Type: nil, InwardIssue: linkedIssue("PROJ-1", "Open", "")},
+		IssueLink{Type: blocksType},
+	)
+
+	tests := []struct {
+		name      string
+		linkTypes []string
+		expected  []string
+	}{
+		{name: "none", linkTypes: []string{}, expected: []string{}},
+		{name: "by name", linkTypes: []string{"Blocks"}, expected: []string{"PROJ-88", "PROJ-90"}},
+		{name: "by name in any case", linkTypes: []string{"blocks"}, expected: []string{"PROJ-88", "PROJ-90"}},
+		{name: "by relation", linkTypes: []string{"is blocked by"}, expected: []string{"PROJ-88"}},
+		{name: "several", linkTypes: []string{"is blocked by", "Relates"}, expected: []string{"PROJ-88", "PROJ-12"}},
+		{name: "unknown", linkTypes: []string{"Duplicates"}, expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, tt.expected, linkKeys(linksOfInterest(issue, tt.linkTypes)))
+		})
+	}
+}
+
+func TestLinksOfInterest_NoFields(t *testing.T) {
+	assert.Equal(t, 0, len(linksOfInterest(&Issue{Key: "WTF-1"}, []string{"Blocks"})))
+}
+
+func TestRenderLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		links    []IssueLink
+		expected string
+	}{
+		{
+			name:     "zero",
+			links:    []IssueLink{},
+			expected: "",
+		},
+		{
+			name:     "open blocker",
+			links:    []IssueLink{{Type: blocksType, InwardIssue: linkedIssue("PROJ-88", "In Progress", "indeterminate")}},
+			expected: "[red]⛔ blocked by PROJ-88[white]",
+		},
+		{
+			name:     "done blocker",
+			links:    []IssueLink{{Type: blocksType, InwardIssue: linkedIssue("PROJ-88", "Closed", "done")}},
+			expected: "[gray]🔗 blocked by PROJ-88[white]",
+		},
+		{
+			name:     "done blocker without a category",
+			links:    []IssueLink{{Type: blocksType, InwardIssue: linkedIssue("PROJ-88", "Done", "")}},
+			expected: "[gray]🔗 blocked by PROJ-88[white]",
+		},
+		{
+			name:     "blocking another",
+			links:    []IssueLink{{Type: blocksType, OutwardIssue: linkedIssue("PROJ-90", "To Do", "new")}},
+			expected: "[gray]🔗 blocks PROJ-90[white]",
+		},
+		{
+			name:     "escaped",
+			links:    []IssueLink{{Type: relatesType, OutwardIssue: linkedIssue("[PROJ-1]", "To Do", "new")}},
+			expected: "[gray]🔗 relates to [PROJ-1[][white]",
+		},
+		{
+			name: "many with a blocker",
+			links: []IssueLink{
+				{Type: blocksType, OutwardIssue: linkedIssue("PROJ-90", "To Do", "new")},
+				{Type: blocksType, InwardIssue: linkedIssue("PROJ-88", "In Progress", "indeterminate")},
+				{Type: relatesType, OutwardIssue: linkedIssue("PROJ-12", "Done", "done")},
+			},
+			expected: "[red]⛔ 3 links[white]",
+		},
+		{
+			name: "many without a blocker",
+			links: []IssueLink{
+				{Type: blocksType, InwardIssue: linkedIssue("PROJ-88", "Done", "done")},
+				{Type: relatesType, OutwardIssue: linkedIssue("PROJ-12", "Done", "done")},
+			},
+			expected: "[gray]🔗 2 links[white]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, renderLinks(tt.links))
+		})
+	}
+}
+
+func TestContent_Links(t *testing.T) {
+	ymlConfig, err := config.ParseYaml("showLinkTypes: [Blocks, Relates]")
+	assert.NilError(t, err)
+	globalConfig, err := config.ParseYaml("global: {}")
+	assert.NilError(t, err)
+
+	widget := NewWidget(nil, make(chan bool, 1), nil, NewSettingsFromYAML("jira", ymlConfig, globalConfig))
+	widget.result = &SearchResult{Issues: []Issue{
+		*issueWithLinks(),
+		*issueWithLinks(IssueLink{Type: blocksType, InwardIssue: linkedIssue("PROJ-88", "In Progress", "indeterminate")}),
+		*issueWithLinks(
+			IssueLink{Type: blocksType, InwardIssue: linkedIssue("PROJ-88", "In Progress", "indeterminate")},
+			IssueLink{Type: relatesType, OutwardIssue: linkedIssue("PROJ-12", "Done", "done")},
+		),
+	}}
+
+	_, content, _ := widget.content()
+
+	lines := strings.Split(strings.TrimSuffix(displayed(content), "\n"), "\n")
+	assert.Equal(t, 4, len(lines))
+	assert.Assert(t, strings.HasSuffix(lines[1], "Crash on start"), lines[1])
+	assert.Assert(t, strings.HasSuffix(lines[2], "Crash on start ⛔ blocked by PROJ-88"), lines[2])
+	assert.Assert(t, strings.HasSuffix(lines[3], "Crash on start ⛔ 2 links"), lines[3])
+}
+
+func TestShowLinkTypes_Default(t *testing.T) {
+	widget := newTestWidget(t)
+	assert.DeepEqual(t, []string{"Blocks"}, widget.settings.showLinkTypes)
+
+	widget.result = &SearchResult{Issues: []Issue{
+		*issueWithLinks(IssueLink{Type: relatesType, OutwardIssue: linkedIssue("PROJ-12", "Done", "done")}),
+	}}
+
+	_, content, _ := widget.content()
+	assert.Assert(t, !strings.Contains(content, "PROJ-12"), content)
+}
+
+func TestDetailsText(t *testing.T) {
+	widget := newTestWidget(t)
+
+	text := displayed(widget.detailsText(issueWithLinks(
+		IssueLink{Type: blocksType, InwardIssue: linkedIssue("PROJ-88", "In Progress", "indeterminate")},
+		IssueLink{Type: relatesType, OutwardIssue: linkedIssue("PROJ-12", "[Done]", "done")},
+	)))
+
+	assert.Assert(t, strings.HasPrefix(text, " Crash on start\n"), text)
+	assert.Assert(t, strings.Contains(text, " Status: Open\n"), text)
+	assert.Assert(t, strings.Contains(text, "  ⛔ blocked by PROJ-88 Summary of PROJ-88 (In Progress)\n"), text)
+	assert.Assert(t, strings.Contains(text, "  🔗 relates to PROJ-12 Summary of PROJ-12 ([Done])\n"), text)
+
+	text = displayed(widget.detailsText(issueWithLinks()))
+	assert.Assert(t, strings.HasSuffix(text, " Links\n  None\n"), text)
+}
@@ -0,0 +1,202 @@
+package jira
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/olebedev/config"
+	"github.com/wtfutil/wtf/utils"
+)
+
+const (
+	// modeIssues shows the issues of the widget's query
+	modeIssues = "issues"
+	// modeResolvedRecently shows the issues resolved by the current user lately, by day
+	modeResolvedRecently = "resolvedRecently"
+
+	defaultResolvedStatus = "Done"
+	defaultResolvedWindow = "7d"
+)
+
+// Resolution is how an issue was resolved, such as Fixed or Won't Do
+type Resolution struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// resolvedDay holds the issues resolved on one day, as indexes into the issues of the result
+type resolvedDay struct {
+	day    time.Time
+	issues []int
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// parseResolvedWindow reads how far back the resolved issues go, such as 7d or 36h. Windows
+// that can't be read, or aren't positive, are the default week
+func parseResolvedWindow(ymlConfig *config.Config) time.Duration {
+	window, err := utils.ParseDuration(ymlConfig.UString("resolvedWindow", defaultResolvedWindow))
+	if err != nil || window <= 0 {
+		window, _ = utils.ParseDuration(defaultResolvedWindow)
+	}
+
+	return window
+}
+
+// resolvedRecentlyJQL returns the query of the resolvedRecently mode: the issues that were
+// assigned to the current user when they moved to the resolved status, within the window,
+// most recently resolved first. It's scoped to the projects and the query of the widget
+//
+// Example:
+//
+//	x := resolvedRecentlyJQL(&Settings{resolvedStatus: "Done", resolvedWindow: 7 * 24 * time.Hour})
+//	> `assignee was currentUser() AND status changed to "Done" after -7d ORDER BY resolutiondate DESC`
+func resolvedRecentlyJQL(settings *Settings) string {
+	query := []string{}
+
+	if projQuery := getProjectQuery(settings.projects); projQuery != "" {
+		query = append(query, projQuery)
+	}
+	if settings.jql != "" {
+		query = append(query, "("+settings.jql+")")
+	}
+
+	query = append(query,
+		"assignee was currentUser()",
+		fmt.Sprintf("status changed to \"%s\" after %s", settings.resolvedStatus, jqlRelativeDate(settings.resolvedWindow)),
+	)
+
+	return strings.Join(query, " AND ") + " ORDER BY resolutiondate DESC"
+}
+
+// jqlRelativeDate writes how long ago window is as a JQL relative date, in the largest of
+// days, hours and minutes it's a whole number of
+func jqlRelativeDate(window time.Duration) string {
+	switch {
+	case window%(24*time.Hour) == 0:
+		return fmt.Sprintf("-%dd", window/(24*time.Hour))
+	case window%time.Hour == 0:
+		return fmt.Sprintf("-%dh", window/time.Hour)
+	default:
+		return fmt.Sprintf("-%dm", max(window/time.Minute, 1))
+	}
+}
+
+// resolvedAt returns when the issue was resolved
+func (issue *Issue) resolvedAt() (time.Time, bool) {
+	if issue.IssueFields == nil || issue.IssueFields.ResolutionDate == "" {
+		return time.Time{}, false
+	}
+
+	resolvedAt, err := time.Parse(jiraTimeLayout, issue.IssueFields.ResolutionDate)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return resolvedAt, true
+}
+
+// sortByResolution orders the issues most recently resolved first, those without a
+// resolution date last
+func sortByResolution(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		iAt, iOK := issues[i].resolvedAt()
+		jAt, jOK := issues[j].resolvedAt()
+
+		if iOK != jOK {
+			return iOK
+		}
+		return iAt.After(jAt)
+	})
+}
+
+// groupByResolvedDay groups the issues, sorted by resolution, by the day they were resolved
+// on in loc. Issues without a resolution date are grouped last, under a zero day
+func groupByResolvedDay(issues []Issue, loc *time.Location) []resolvedDay {
+	days := []resolvedDay{}
+
+	for idx := range issues {
+		var day time.Time
+		if resolvedAt, ok := issues[idx].resolvedAt(); ok {
+			local := resolvedAt.In(loc)
+			day = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		}
+
+		if len(days) == 0 || !days[len(days)-1].day.Equal(day) {
+			days = append(days, resolvedDay{day: day})
+		}
+		days[len(days)-1].issues = append(days[len(days)-1].issues, idx)
+	}
+
+	return days
+}
+
+// dayHeader names a day relative to now: Today, Yesterday, or its date
+func dayHeader(day, now time.Time) string {
+	if day.IsZero() {
+		return "Resolution date unknown"
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch {
+	case day.Equal(today):
+		return "Today"
+	case day.Equal(today.AddDate(0, 0, -1)):
+		return "Yesterday"
+	default:
+		return day.Format("Monday, Jan 2")
+	}
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// resolvedContent renders the resolved issues under the day they were resolved on, with
+// when and how they were resolved
+func (widget *Widget) resolvedContent(now time.Time) string {
+	str := fmt.Sprintf(" [%s]Resolved in the last %s[white]\n", widget.settings.Colors.Subheading, utils.HumanDuration(widget.settings.resolvedWindow))
+
+	if widget.result == nil || len(widget.result.Issues) == 0 {
+		return str + " None"
+	}
+
+	issues := widget.result.Issues
+
+	longestKey := 0
+	for _, issue := range issues {
+		longestKey = max(longestKey, utils.DisplayWidth(issue.Key))
+	}
+
+	for _, day := range groupByResolvedDay(issues, now.Location()) {
+		str += fmt.Sprintf(" [%s]%s[white]\n", widget.settings.Colors.Label, dayHeader(day.day, now))
+
+		for _, idx := range day.issues {
+			issue := &issues[idx]
+
+			row := utils.SafeSprintf("[%s]  [green]%-*s[white] [%s]%s", widget.RowColor(idx), longestKey, issue.Key, widget.RowColor(idx), issue.IssueFields.Summary)
+			if details := resolutionDetails(issue, now.Location()); details != "" {
+				row += " [gray](" + details + ")[white]"
+			}
+
+			str += utils.HighlightableHelper(widget.View, row, idx, utils.DisplayWidth(row))
+		}
+	}
+
+	return str
+}
+
+// resolutionDetails returns how the issue was resolved and at what time, as far as known
+func resolutionDetails(issue *Issue, loc *time.Location) string {
+	details := []string{}
+
+	if resolution := issue.IssueFields.Resolution; resolution != nil && resolution.Name != "" {
+		details = append(details, utils.SafeSprintf("%s", resolution.Name))
+	}
+	if resolvedAt, ok := issue.resolvedAt(); ok {
+		details = append(details, resolvedAt.In(loc).Format("15:04"))
+	}
+
+	return strings.Join(details, ", ")
+}
@@ -0,0 +1,210 @@
+package jira
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/olebedev/config"
+	"github.com/rivo/tview"
+	"gotest.tools/assert"
+)
+
+func TestResolvedRecentlyJQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		expected string
+	}{
+		{
+			name:     "a week",
+			settings: Settings{resolvedStatus: "Done", resolvedWindow: 7 * 24 * time.Hour},
+			expected: `assignee was currentUser() AND status changed to "Done" after -7d ORDER BY resolutiondate DESC`,
+		},
+		{
+			name:     "hours",
+			settings: Settings{resolvedStatus: "Done", resolvedWindow: 36 * time.Hour},
+			expected: `assignee was currentUser() AND status changed to "Done" after -36h ORDER BY resolutiondate DESC`,
+		},
+		{
+			name:     "minutes",
+			settings: Settings{resolvedStatus: "Closed", resolvedWindow: 90 * time.Minute},
+			expected: `assignee was currentUser() AND status changed to "Closed" after -90m ORDER BY resolutiondate DESC`,
+		},
+		{
+			name: "scoped",
+			settings: Settings{
+				resolvedStatus: "Done",
+				resolvedWindow: 14 * 24 * time.Hour,
+				projects:       []string{"OPS", "WEB"},
+				jql:            "type = Bug OR type = Task",
+			},
+			expected: `project in ("OPS", "WEB") AND (type = Bug OR type = Task) AND assignee was currentUser() AND status changed to "Done" after -14d ORDER BY resolutiondate DESC`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolvedRecentlyJQL(&tt.settings))
+		})
+	}
+}
+
+func TestNewSettingsFromYAML_ResolvedRecently(t *testing.T) {
+	tests := []struct {
+		yaml     string
+		expected time.Duration
+	}{
+		{yaml: "mode: resolvedRecently", expected: 7 * 24 * time.Hour},
+		{yaml: "resolvedWindow: 2w", expected: 14 * 24 * time.Hour},
+		{yaml: "resolvedWindow: 36h", expected: 36 * time.Hour},
+		{yaml: "resolvedWindow: soon", expected: 7 * 24 * time.Hour},
+		{yaml: "resolvedWindow: -1d", expected: 7 * 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.yaml, func(t *testing.T) {
+			ymlConfig, err := config.ParseYaml(tt.yaml)
+			assert.NilError(t, err)
+			globalConfig, err := config.ParseYaml("global: {}")
+			assert.NilError(t, err)
+
+			settings := NewSettingsFromYAML("jira", ymlConfig, globalConfig)
+			assert.Equal(t, tt.expected, settings.resolvedWindow)
+			assert.Equal(t, 0, len(settings.UnknownKeys()))
+		})
+	}
+}
+
+func TestBuildSearches_ResolvedRecently(t *testing.T) {
+	settings := &Settings{
+		mode:           modeResolvedRecently,
+		resolvedStatus: "Done",
+		resolvedWindow: 24 * time.Hour,
+		projects:       []string{"OPS"},
+		username:       "me",
+		savedSearches:  []savedSearch{{name: "Bugs", jql: "type = Bug"}},
+	}
+
+	searches := buildSearches(settings)
+
+	assert.Equal(t, 2, len(searches))
+	// The username is left out: the issues are the current user's
+	assert.Equal(t, `project = "OPS" AND assignee was currentUser() AND status changed to "Done" after -1d ORDER BY resolutiondate DESC`, searches[0].jql)
+	assert.Assert(t, searches[0].raw)
+	assert.Assert(t, searches[0].resolved)
+	assert.Assert(t, !searches[1].resolved)
+}
+
+func resolvedIssue(key, resolvedAt, resolution string) Issue {
+	issue := Issue{Key: key, IssueFields: &IssueFields{
+		Summary:        "Summary of " + key,
+		IssueType:      &IssueType{Name: "Task"},
+		IssueStatus:    &IssueStatus{IName: "Done"},
+		ResolutionDate: resolvedAt,
+	}}
+	if resolution != "" {
+		issue.IssueFields.Resolution = &Resolution{Name: resolution}
+	}
+
+	return issue
+}
+
+func TestGroupByResolvedDay_Timezones(t *testing.T) {
+	issues := []Issue{
+		resolvedIssue("WTF-3", "2026-10-16T01:30:00.000+0000", "Fixed"),
+		resolvedIssue("WTF-2", "2026-10-15T23:30:00.000+0000", "Fixed"),
+		resolvedIssue("WTF-1", "2026-10-15T20:00:00.000+0000", "Won't Do"),
+		resolvedIssue("WTF-0", "", ""),
+	}
+
+	days := func(loc *time.Location) [][]int {
+		grouped := [][]int{}
+		for _, day := range groupByResolvedDay(issues, loc) {
+			grouped = append(grouped, day.issues)
+		}
+		return grouped
+	}
+
+	// Midnight UTC splits the first two
+	assert.DeepEqual(t, [][]int{{0}, {1, 2}, {3}}, days(time.UTC))
+	// Four hours behind, they're all on the 15th
+	assert.DeepEqual(t, [][]int{{0, 1, 2}, {3}}, days(time.FixedZone("EDT", -4*60*60)))
+	// Nine hours ahead, the last one is on the 16th with the others
+	assert.DeepEqual(t, [][]int{{0, 1, 2}, {3}}, days(time.FixedZone("JST", 9*60*60)))
+	// Three hours ahead, only the last one is still on the 15th
+	assert.DeepEqual(t, [][]int{{0, 1}, {2}, {3}}, days(time.FixedZone("EEST", 3*60*60)))
+
+	grouped := groupByResolvedDay(issues, time.FixedZone("JST", 9*60*60))
+	assert.Equal(t, "2026-10-16", grouped[0].day.Format(time.DateOnly))
+	assert.Assert(t, grouped[1].day.IsZero())
+}
+
+func TestSortByResolution(t *testing.T) {
+	issues := []Issue{
+		resolvedIssue("WTF-0", "", ""),
+		resolvedIssue("WTF-1", "2026-10-15T20:00:00.000+0000", ""),
+		resolvedIssue("WTF-3", "2026-10-16T03:30:00.000+0200", ""),
+		resolvedIssue("WTF-2", "2026-10-15T23:30:00.000+0000", ""),
+	}
+
+	sortByResolution(issues)
+
+	keys := []string{}
+	for _, issue := range issues {
+		keys = append(keys, issue.Key)
+	}
+	assert.DeepEqual(t, []string{"WTF-3", "WTF-2", "WTF-1", "WTF-0"}, keys)
+}
+
+func TestDayHeader(t *testing.T) {
+	loc := time.FixedZone("EDT", -4*60*60)
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, loc)
+
+	assert.Equal(t, "Today", dayHeader(time.Date(2026, 10, 16, 0, 0, 0, 0, loc), now))
+	assert.Equal(t, "Yesterday", dayHeader(time.Date(2026, 10, 15, 0, 0, 0, 0, loc), now))
+	assert.Equal(t, "Tuesday, Oct 13", dayHeader(time.Date(2026, 10, 13, 0, 0, 0, 0, loc), now))
+	assert.Equal(t, "Resolution date unknown", dayHeader(time.Time{}, now))
+}
+
+func TestContent_ResolvedRecently(t *testing.T) {
+	ymlConfig, err := config.ParseYaml("mode: resolvedRecently\nresolvedWindow: 3d")
+	assert.NilError(t, err)
+	globalConfig, err := config.ParseYaml("global: {}")
+	assert.NilError(t, err)
+
+	widget := NewWidget(tview.NewApplication(), make(chan bool, 1), nil, NewSettingsFromYAML("jira", ymlConfig, globalConfig))
+
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	widget.setResult(&SearchResult{Issues: []Issue{
+		resolvedIssue("WTF-1", yesterday.Format(jiraTimeLayout), "Won't Do"),
+		resolvedIssue("WTF-12", now.Format(jiraTimeLayout), "Fixed"),
+	}}, nil)
+
+	// The most recently resolved issue is the first row
+	assert.Equal(t, "WTF-12", widget.result.Issues[0].Key)
+
+	_, content, _ := widget.content()
+	lines := strings.Split(strings.TrimRight(displayed(content), "\n"), "\n")
+
+	assert.DeepEqual(t, []string{
+		" Resolved in the last 3 days",
+		" Today",
+		"  WTF-12 Summary of WTF-12 (Fixed, " + now.Format("15:04") + ")",
+		" Yesterday",
+		"  WTF-1  Summary of WTF-1 (Won't Do, " + yesterday.Format("15:04") + ")",
+	}, trimRows(lines))
+
+	widget.setResult(&SearchResult{Issues: []Issue{}}, nil)
+	_, content, _ = widget.content()
+	assert.Equal(t, " Resolved in the last 3 days\n None", displayed(content))
+}
+
+func trimRows(lines []string) []string {
+	trimmed := make([]string, len(lines))
+	for idx, line := range lines {
+		trimmed[idx] = strings.TrimRight(line, " ")
+	}
+	return trimmed
+}
@@ -5,4 +5,7 @@ type SearchResult struct {
 	MaxResults int     `json:"maxResults"`
 	Total      int     `json:"total"`
 	Issues     []Issue `json:"issues"`
+
+	// fetchErrors are why some of the issues the search found couldn't be fetched
+	fetchErrors []error
 }
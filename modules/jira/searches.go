@@ -0,0 +1,219 @@
+package jira
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/olebedev/config"
+	"github.com/rivo/tview"
+)
+
+const (
+	// maxSearchKeys is how many searches the number keys switch to: 0 for the widget's own
+	// query, and 1 to 9 for the first saved searches
+	maxSearchKeys = 10
+
+	pickerPage   = "jira-searches"
+	pickerWidth  = 60
+	pickerMargin = 4
+	offscreen    = -1000
+)
+
+// savedSearch is a named JQL query the widget can switch to
+type savedSearch struct {
+	name string
+	jql  string
+	// raw searches are run as they are, without the username and projects of the widget
+	raw bool
+	// resolved searches are shown by the day their issues were resolved on
+	resolved bool
+}
+
+// searchCache is the last result of a search, kept so that switching back to it is instant
+type searchCache struct {
+	result *SearchResult
+	err    error
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// parseSavedSearches reads the saved searches, in order. Searches without JQL are left out,
+// and those without a name are named after their JQL
+//
+// Example:
+//
+//	savedSearches:
+//	  - name: My issues
+//	    jql: assignee = currentUser()
+//	  - name: Released this week
+//	    jql: fixVersion in releasedVersions() AND resolved >= -1w
+//	    raw: true
+func parseSavedSearches(ymlConfig *config.Config) []savedSearch {
+	searches := []savedSearch{}
+
+	for _, rawSearch := range ymlConfig.UList("savedSearches") {
+		search, ok := rawSearch.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		jql, _ := search["jql"].(string)
+		if jql == "" {
+			continue
+		}
+
+		name := jql
+		if value, ok := search["name"]; ok && value != nil {
+			name = fmt.Sprintf("%v", value)
+		}
+
+		raw, _ := search["raw"].(bool)
+
+		searches = append(searches, savedSearch{name: name, jql: jql, raw: raw})
+	}
+
+	return searches
+}
+
+// buildSearches returns the searches the widget switches between: its own query first, and
+// then the saved searches. In the resolvedRecently mode, its own query finds the issues
+// resolved lately instead, scoped to its projects already
+func buildSearches(settings *Settings) []savedSearch {
+	own := savedSearch{jql: settings.jql}
+	if settings.mode == modeResolvedRecently {
+		own = savedSearch{jql: resolvedRecentlyJQL(settings), raw: true, resolved: true}
+	}
+
+	return append([]savedSearch{own}, settings.savedSearches...)
+}
+
+// issuesForSearch runs a search, scoped to the username and projects of the widget unless
+// it's raw
+func (widget *Widget) issuesForSearch(search savedSearch) (*SearchResult, error) {
+	if search.raw {
+		return widget.IssuesFor("", []string{}, search.jql)
+	}
+
+	return widget.IssuesFor(widget.settings.username, widget.settings.projects, search.jql)
+}
+
+// activeSearch returns the index of the search the widget shows
+func (widget *Widget) activeSearch() int {
+	widget.searchMu.Lock()
+	defer widget.searchMu.Unlock()
+
+	return widget.active
+}
+
+// cacheSearch keeps the result of the search at idx, and returns true if it's still the
+// one the widget shows
+func (widget *Widget) cacheSearch(idx int, result *SearchResult, err error) bool {
+	widget.searchMu.Lock()
+	defer widget.searchMu.Unlock()
+
+	widget.cache[idx] = searchCache{result: result, err: err}
+
+	return idx == widget.active
+}
+
+// switchSearch makes the search at idx the one the widget shows. Its last result is shown
+// right away when there's one, and it's run otherwise
+func (widget *Widget) switchSearch(idx int) {
+	if idx < 0 || idx >= len(widget.searches) {
+		return
+	}
+
+	widget.searchMu.Lock()
+	widget.active = idx
+	cached, ok := widget.cache[idx]
+	widget.searchMu.Unlock()
+
+	widget.Selected = -1
+
+	if !ok {
+		widget.Refresh()
+		return
+	}
+
+	widget.setResult(cached.result, cached.err)
+	widget.Render()
+}
+
+// title returns the title of the widget, with the name of the search it shows when it's
+// a saved one
+func (widget *Widget) title() string {
+	title := widget.CommonSettings().Title
+
+	if search := widget.searches[widget.activeSearch()]; search.name != "" {
+		title = fmt.Sprintf("%s - %s", title, tview.Escape(search.name))
+	}
+
+	return title
+}
+
+// initializeSearchKeyboardControls binds the number keys to the searches, and s to the
+// picker, when there are saved searches
+func (widget *Widget) initializeSearchKeyboardControls() {
+	if len(widget.searches) < 2 {
+		return
+	}
+
+	for idx := 0; idx < min(len(widget.searches), maxSearchKeys); idx++ {
+		helpText := "Switch to the widget's own query"
+		if idx > 0 {
+			helpText = fmt.Sprintf("Switch to the %s search", widget.searches[idx].name)
+		}
+
+		widget.SetKeyboardChar(strconv.Itoa(idx), func() { widget.switchSearch(idx) }, helpText)
+	}
+
+	widget.SetKeyboardChar("s", widget.showSearchPicker, "Pick the search to show")
+}
+
+// showSearchPicker lists the searches over the widget, to switch to the one picked
+func (widget *Widget) showSearchPicker() {
+	if widget.pages == nil {
+		return
+	}
+
+	closeFunc := func() {
+		widget.pages.RemovePage(pickerPage)
+		widget.tviewApp.SetFocus(widget.View)
+	}
+
+	list := tview.NewList()
+	list.ShowSecondaryText(false)
+	list.SetDoneFunc(closeFunc)
+
+	for idx, search := range widget.searches {
+		name := search.name
+		if idx == 0 {
+			name = widget.CommonSettings().Title
+		}
+
+		shortcut := rune(0)
+		if idx < maxSearchKeys {
+			shortcut = rune('0' + idx)
+		}
+
+		list.AddItem(tview.Escape(name), "", shortcut, func() {
+			closeFunc()
+			widget.switchSearch(idx)
+		})
+	}
+	list.SetCurrentItem(widget.activeSearch())
+
+	frame := tview.NewFrame(list)
+	frame.SetBorder(true)
+	frame.SetBorders(1, 1, 0, 0, 1, 1)
+	frame.SetRect(offscreen, offscreen, pickerWidth, len(widget.searches)+pickerMargin)
+	frame.SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+		w, h := screen.Size()
+		frame.SetRect((w/2)-(width/2), (h/2)-(height/2), width, height)
+		return x, y, width, height
+	})
+
+	widget.pages.AddPage(pickerPage, frame, false, true)
+	widget.tviewApp.SetFocus(frame)
+}
@@ -0,0 +1,211 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/olebedev/config"
+	"github.com/rivo/tview"
+	"gotest.tools/assert"
+)
+
+// searchServer answers every search with a single issue named after its JQL, and records
+// the JQL of each search
+type searchServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	queries []string
+}
+
+func newSearchServer(t *testing.T) *searchServer {
+	t.Helper()
+
+	server := &searchServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/rest/api/3/search/jql":
+			server.mu.Lock()
+			server.queries = append(server.queries, r.URL.Query().Get("jql"))
+			id := len(server.queries)
+			server.mu.Unlock()
+
+			_, _ = fmt.Fprintf(w, `{"issues": [{"id": "%d"}]}`, id)
+		case strings.HasPrefix(r.URL.Path, "/rest/api/3/issue/"):
+			id := strings.TrimPrefix(r.URL.Path, "/rest/api/3/issue/")
+
+			server.mu.Lock()
+			jql := server.queries[len(server.queries)-1]
+			server.mu.Unlock()
+
+			_, _ = fmt.Fprintf(w, `{"id": "%s", "key": "AB-%s", "fields": {"summary": %q, "issuetype": {"name": "Task"}, "status": {"name": "Open"}}}`, id, id, jql)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func (server *searchServer) searches() []string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return append([]string{}, server.queries...)
+}
+
+func newSearchWidget(t *testing.T, server *searchServer) *Widget {
+	t.Helper()
+
+	ymlConfig, err := config.ParseYaml(fmt.Sprintf(`
+domain: %s
+project: AB
+jql: assignee = currentUser()
+savedSearches:
+  - name: Team triage
+    jql: status = Triage
+  - name: Released this week
+    jql: fixVersion in releasedVersions()
+    raw: true
+`, server.URL))
+	assert.NilError(t, err)
+	globalConfig, err := config.ParseYaml("global: {}")
+	assert.NilError(t, err)
+
+	redrawChan := make(chan bool, 1)
+	go func() {
+		for range redrawChan {
+		}
+	}()
+	t.Cleanup(func() { close(redrawChan) })
+
+	return NewWidget(tview.NewApplication(), redrawChan, nil, NewSettingsFromYAML("jira", ymlConfig, globalConfig))
+}
+
+func shownSummary(widget *Widget) string {
+	if widget.result == nil || len(widget.result.Issues) == 0 {
+		return ""
+	}
+
+	return widget.result.Issues[0].IssueFields.Summary
+}
+
+func TestParseSavedSearches(t *testing.T) {
+	ymlConfig, err := config.ParseYaml(`
+savedSearches:
+  - name: My issues
+    jql: assignee = currentUser()
+  - jql: status = Triage
+  - name: Nothing to search
+  - name: 2024
+    jql: fixVersion = 2024
+    raw: true
+  - just a string
+`)
+	assert.NilError(t, err)
+
+	expected := []savedSearch{
+		{name: "My issues", jql: "assignee = currentUser()"},
+		{name: "status = Triage", jql: "status = Triage"},
+		{name: "2024", jql: "fixVersion = 2024", raw: true},
+	}
+
+	actual := parseSavedSearches(ymlConfig)
+	assert.Equal(t, len(expected), len(actual))
+	for i := range expected {
+		assert.Equal(t, expected[i], actual[i])
+	}
+}
+
+func TestSwitchSearch(t *testing.T) {
+	server := newSearchServer(t)
+	widget := newSearchWidget(t, server)
+
+	widget.Refresh()
+	assert.Equal(t, `project = "AB" AND assignee = currentUser()`, shownSummary(widget))
+	assert.Equal(t, "Jira", widget.title())
+
+	widget.switchSearch(1)
+	assert.Equal(t, `project = "AB" AND status = Triage`, shownSummary(widget))
+	assert.Equal(t, "Jira - Team triage", widget.title())
+
+	// Raw searches aren't scoped to the projects
+	widget.switchSearch(2)
+	assert.Equal(t, "fixVersion in releasedVersions()", shownSummary(widget))
+	assert.Equal(t, "Jira - Released this week", widget.title())
+
+	// There's no search 3
+	widget.switchSearch(3)
+	assert.Equal(t, 2, widget.activeSearch())
+
+	assert.DeepEqual(t, []string{
+		`project = "AB" AND assignee = currentUser()`,
+		`project = "AB" AND status = Triage`,
+		"fixVersion in releasedVersions()",
+	}, server.searches())
+}
+
+func TestSwitchSearch_Cached(t *testing.T) {
+	server := newSearchServer(t)
+	widget := newSearchWidget(t, server)
+
+	widget.Refresh()
+	widget.switchSearch(1)
+	assert.Equal(t, 2, len(server.searches()))
+
+	// Switching back shows the last result, without searching again
+	widget.switchSearch(0)
+	assert.Equal(t, `project = "AB" AND assignee = currentUser()`, shownSummary(widget))
+	widget.switchSearch(1)
+	assert.Equal(t, `project = "AB" AND status = Triage`, shownSummary(widget))
+	assert.Equal(t, 2, len(server.searches()))
+
+	// Refreshing only runs the search shown, and updates its cache
+	widget.Refresh()
+	assert.Equal(t, 3, len(server.searches()))
+	assert.Equal(t, "AB-3", widget.result.Issues[0].Key)
+
+	widget.switchSearch(0)
+	assert.Equal(t, "AB-1", widget.result.Issues[0].Key)
+	widget.switchSearch(1)
+	assert.Equal(t, "AB-3", widget.result.Issues[0].Key)
+	assert.Equal(t, 3, len(server.searches()))
+}
+
+func TestSwitchSearch_CachedError(t *testing.T) {
+	server := newSearchServer(t)
+	widget := newSearchWidget(t, server)
+
+	widget.Refresh()
+	widget.cacheSearch(1, nil, fmt.Errorf("JIRA search failed: boom"))
+
+	widget.switchSearch(1)
+	assert.ErrorContains(t, widget.err, "boom")
+	assert.Assert(t, widget.result == nil)
+
+	widget.switchSearch(0)
+	assert.NilError(t, widget.err)
+	assert.Equal(t, 1, len(widget.result.Issues))
+	assert.Equal(t, 1, len(server.searches()))
+}
+
+func TestSearchKeyboardControls(t *testing.T) {
+	server := newSearchServer(t)
+	widget := newSearchWidget(t, server)
+
+	help := widget.HelpText()
+	assert.Assert(t, strings.Contains(help, "Switch to the widget's own query"), help)
+	assert.Assert(t, strings.Contains(help, "Switch to the Released this week search"), help)
+	assert.Assert(t, strings.Contains(help, "Pick the search to show"), help)
+
+	// Without saved searches, the number keys are left to the other widgets
+	help = newTestWidget(t).HelpText()
+	assert.Assert(t, !strings.Contains(help, "Switch to"), help)
+}
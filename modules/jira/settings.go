@@ -2,9 +2,11 @@ package jira
 
 import (
 	"os"
+	"time"
 
 	"github.com/olebedev/config"
 	"github.com/wtfutil/wtf/cfg"
+	"github.com/wtfutil/wtf/utils"
 )
 
 const (
@@ -23,14 +25,22 @@ type Settings struct {
 	colors
 	*cfg.Common
 
-	apiKey                  string   `help:"Your Jira API key (or password for basic auth)."`
-	personalAccessToken     string   `help:"Access Token to use instead of username / password auth"`
-	domain                  string   `help:"Your Jira corporate domain."`
-	email                   string   `help:"The email address associated with your Jira account (or username for basic auth)."`
-	jql                     string   `help:"Custom JQL to be appended to the search query." values:"See Search Jira like a boss with JQL for details." optional:"true"`
-	projects                []string `help:"An array of projects to get data from"`
-	username                string   `help:"Your Jira username. If provided, will filter issues by this username." optional:"true"`
-	verifyServerCertificate bool     `help:"Determines whether or not the server’s certificate chain and host name are verified." values:"true or false" optional:"true"`
+	apiKey                    string                   `help:"Your Jira API key (or password for basic auth)."`
+	personalAccessToken       string                   `help:"Access Token to use instead of username / password auth"`
+	domain                    string                   `help:"Your Jira corporate domain."`
+	email                     string                   `help:"The email address associated with your Jira account (or username for basic auth)."`
+	jql                       string                   `help:"Custom JQL to be appended to the search query." values:"See Search Jira like a boss with JQL for details." optional:"true"`
+	mode                      string                   `help:"What the widget's own query shows: the issues it finds, or the issues you resolved lately, by day, for standups." values:"issues or resolvedRecently" optional:"true" default:"issues"`
+	projects                  []string                 `help:"An array of projects to get data from" key:"project"`
+	resolvedStatus            string                   `help:"The status issues move to when they're resolved, for the resolvedRecently mode." optional:"true" default:"Done"`
+	resolvedWindow            time.Duration            `help:"How far back the resolvedRecently mode goes." values:"A duration such as 7d, 2w or 36h" optional:"true" default:"7d"`
+	savedSearches             []savedSearch            `help:"Named JQL queries to switch the widget to, with the number keys 1 to 9, in order, or by picking them with s. 0 switches back to the widget's own query. They're run for the username and projects of the widget, unless they set raw: true." values:"A list of name, jql and, optionally, raw" optional:"true"`
+	showTimeInStatus          bool                     `help:"Whether to show how long each issue has been in its status after it, such as (in review 3d)." values:"true or false" optional:"true" default:"false"`
+	statusThresholds          map[string]time.Duration `help:"How long issues can be in a status before the time they've been in it is shown in red, by status." values:"A map of status names to durations such as 36h, 2d or 1w, such as In Review: 2d" optional:"true"`
+	timeInStatusFromChangelog bool                     `help:"Whether to read when the status of each issue last changed from its changelog, which makes the responses larger, instead of from when its status category, such as in progress, last changed." values:"true or false" optional:"true" default:"false"`
+	showLinkTypes             []string                 `help:"The types of issue links to show after each issue's summary, by name, such as Blocks, or by how they read, such as is blocked by. Open issues that block an issue are shown in red." values:"A list of link types" optional:"true" default:"Blocks"`
+	username                  string                   `help:"Your Jira username. If provided, will filter issues by this username." optional:"true"`
+	verifyServerCertificate   bool                     `help:"Determines whether or not the server’s certificate chain and host name are verified." values:"true or false" optional:"true"`
 }
 
 func NewSettingsFromYAML(name string, ymlConfig *config.Config, globalConfig *config.Config) *Settings {
@@ -43,6 +53,9 @@ func NewSettingsFromYAML(name string, ymlConfig *config.Config, globalConfig *co
 		domain:                  ymlConfig.UString("domain"),
 		email:                   ymlConfig.UString("email"),
 		jql:                     ymlConfig.UString("jql"),
+		mode:                    ymlConfig.UString("mode", modeIssues),
+		resolvedStatus:          ymlConfig.UString("resolvedStatus", defaultResolvedStatus),
+		resolvedWindow:          parseResolvedWindow(ymlConfig),
 		username:                ymlConfig.UString("username"),
 		verifyServerCertificate: ymlConfig.UBool("verifyServerCertificate", true),
 	}
@@ -54,6 +67,16 @@ func NewSettingsFromYAML(name string, ymlConfig *config.Config, globalConfig *co
 	settings.rows.odd = ymlConfig.UString("colors.odd", "white")
 
 	settings.projects = settings.arrayifyProjects(ymlConfig)
+	settings.savedSearches = parseSavedSearches(ymlConfig)
+	settings.showTimeInStatus = ymlConfig.UBool("showTimeInStatus", false)
+	settings.statusThresholds = parseStatusThresholds(ymlConfig)
+	settings.timeInStatusFromChangelog = ymlConfig.UBool("timeInStatusFromChangelog", false)
+	settings.showLinkTypes = utils.ToStrs(ymlConfig.UList("showLinkTypes", []interface{}{defaultLinkType}))
+
+	schema := cfg.NewSchema(Settings{}).Keys("apikey")
+	schema.Block("colors").Keys("even", "odd")
+	schema.Block("savedSearches").Keys("name", "jql", "raw")
+	settings.CheckKeys(schema)
 
 	return &settings
 }
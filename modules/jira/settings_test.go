@@ -0,0 +1,179 @@
+package jira
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/olebedev/config"
+	"github.com/wtfutil/wtf/cfg"
+	"gotest.tools/assert"
+)
+
+func TestNewSettingsFromYAML_Expansion(t *testing.T) {
+	t.Setenv("WTF_TEST_JIRA_KEY", "key-from-env")
+	t.Setenv("WTF_JIRA_API_KEY", "")
+
+	keyFile := filepath.Join(t.TempDir(), "jira-key")
+	assert.NilError(t, os.WriteFile(keyFile, []byte("key-from-file\n"), 0o600))
+
+	tests := []struct {
+		name     string
+		apiKey   string
+		expected string
+		failed   bool
+	}{
+		{name: "literal", apiKey: "plain-key", expected: "plain-key"},
+		{name: "env", apiKey: "${WTF_TEST_JIRA_KEY}", expected: "key-from-env"},
+		{name: "file", apiKey: "file:" + keyFile, expected: "key-from-file"},
+		{name: "missing env", apiKey: "${WTF_TEST_JIRA_MISSING}", expected: "${WTF_TEST_JIRA_MISSING}", failed: true},
+		{name: "missing file", apiKey: "file:" + keyFile + ".missing", expected: "file:" + keyFile + ".missing", failed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ymlConfig := &config.Config{Root: map[string]interface{}{
+				"apiKey": tt.apiKey,
+				"domain": "https://jira.example.com",
+			}}
+			globalConfig, err := config.ParseYaml("global: {}")
+			assert.NilError(t, err)
+
+			settings := NewSettingsFromYAML("jira", ymlConfig, globalConfig)
+
+			assert.Equal(t, tt.expected, settings.apiKey)
+			assert.Equal(t, "https://jira.example.com", settings.domain)
+			assert.Equal(t, tt.failed, hasValidationError(settings, "apiKey"))
+		})
+	}
+}
+
+func TestNewSettingsFromYAML_RefreshInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval interface{}
+		expected time.Duration
+		failed   bool
+	}{
+		{name: "seconds", interval: 90, expected: 90 * time.Second},
+		{name: "duration", interval: "1h30m", expected: 90 * time.Minute},
+		{name: "negative", interval: "-5m", expected: 0},
+		{name: "sub-second", interval: "10ms", expected: 300 * time.Second, failed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ymlConfig := &config.Config{Root: map[string]interface{}{
+				"refreshInterval": tt.interval,
+			}}
+			globalConfig, err := config.ParseYaml("global: {}")
+			assert.NilError(t, err)
+
+			settings := NewSettingsFromYAML("jira", ymlConfig, globalConfig)
+
+			assert.Equal(t, tt.expected, settings.RefreshInterval)
+			assert.Equal(t, tt.failed, hasValidationError(settings, "refreshInterval"))
+		})
+	}
+}
+
+// hasValidationError returns whether the settings failed to load the given key
+func hasValidationError(settings *Settings, key string) bool {
+	for _, validation := range settings.Validations() {
+		if validation.HasError() && strings.Contains(validation.String(), key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestNewSettingsFromYAML_UnknownKeys(t *testing.T) {
+	ymlConfig, err := config.ParseYaml(`
+apikey: abc
+domain: https://jira.example.com
+project: [ABC, DEF]
+projects: [GHI]
+colors:
+  even: white
+  odd: gray
+  od: gray
+`)
+	assert.NilError(t, err)
+	globalConfig, err := config.ParseYaml("global: {}")
+	assert.NilError(t, err)
+
+	settings := NewSettingsFromYAML("jira", ymlConfig, globalConfig)
+
+	assert.DeepEqual(t, []cfg.UnknownKey{
+		{Key: "colors.od", Suggestion: "colors.odd"},
+		{Key: "projects", Suggestion: "project"},
+	}, settings.UnknownKeys())
+}
+
+func TestNewSettingsFromYAML_ModuleDefaults(t *testing.T) {
+	t.Setenv("WTF_JIRA_API_KEY", "")
+
+	globalConfig, err := config.ParseYaml(`
+wtf:
+  moduleDefaults:
+    jira:
+      domain: https://jira.example.com
+      email: me@example.com
+      colors:
+        even: gray
+        odd: white
+      statusThresholds:
+        In Review: 2d
+        Blocked: 1d
+      savedSearches:
+        - name: Mine
+          jql: assignee = currentUser()
+        - name: Bugs
+          jql: type = Bug
+`)
+	assert.NilError(t, err)
+
+	ymlConfig, err := config.ParseYaml(`
+type: jira
+project: OPS
+colors:
+  odd: yellow
+statusThresholds:
+  Blocked: 4h
+savedSearches:
+  - name: Incidents
+    jql: type = Incident
+`)
+	assert.NilError(t, err)
+
+	settings := NewSettingsFromYAML("ops", ymlConfig, globalConfig)
+
+	assert.Equal(t, "https://jira.example.com", settings.domain)
+	assert.Equal(t, "me@example.com", settings.email)
+	assert.DeepEqual(t, []string{"OPS"}, settings.projects)
+
+	// Maps are merged, the widget's values winning
+	assert.Equal(t, "gray", settings.rows.even)
+	assert.Equal(t, "yellow", settings.rows.odd)
+	assert.DeepEqual(t, map[string]time.Duration{
+		"in review": 48 * time.Hour,
+		"blocked":   4 * time.Hour,
+	}, settings.statusThresholds)
+
+	// Lists are replaced
+	assert.Equal(t, 1, len(settings.savedSearches))
+	assert.Equal(t, "Incidents", settings.savedSearches[0].name)
+
+	// The defaults of other types don't apply
+	other, err := config.ParseYaml("type: jira")
+	assert.NilError(t, err)
+	settings = NewSettingsFromYAML("other", other, &config.Config{Root: map[string]interface{}{
+		"wtf": map[string]interface{}{"moduleDefaults": map[string]interface{}{
+			"github": map[string]interface{}{"domain": "https://github.example.com"},
+		}},
+	}})
+	assert.Equal(t, "", settings.domain)
+}
@@ -0,0 +1,88 @@
+package jira
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// sharedDomain is what the widgets pointing at the same Jira domain share
+type sharedDomain struct {
+	userIDs *UserIDCacheMap
+	// clients are the HTTP clients of the domain, by whether they verify its certificate
+	clients map[bool]*http.Client
+}
+
+var (
+	sharedDomains   = map[string]*sharedDomain{}
+	sharedDomainsMu sync.Mutex
+
+	// inflight coalesces identical requests made at the same time, by widgets on the same
+	// domain, into one
+	inflight singleflight.Group
+)
+
+/* -------------------- Unexported Functions -------------------- */
+
+// sharedFor returns what the widgets pointing at domain share, creating it for the first
+func sharedFor(domain string) *sharedDomain {
+	domain = strings.TrimRight(domain, "/")
+
+	sharedDomainsMu.Lock()
+	defer sharedDomainsMu.Unlock()
+
+	shared, ok := sharedDomains[domain]
+	if !ok {
+		shared = &sharedDomain{
+			userIDs: &UserIDCacheMap{cache: make(map[string]UserIDCache)},
+			clients: map[bool]*http.Client{},
+		}
+		sharedDomains[domain] = shared
+	}
+
+	return shared
+}
+
+// client returns the HTTP client of the domain that does, or doesn't, verify its certificate
+func (shared *sharedDomain) client(verifyServerCertificate bool) *http.Client {
+	sharedDomainsMu.Lock()
+	defer sharedDomainsMu.Unlock()
+
+	client, ok := shared.clients[verifyServerCertificate]
+	if !ok {
+		client = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: !verifyServerCertificate,
+				},
+				Proxy: http.ProxyFromEnvironment,
+			},
+		}
+		shared.clients[verifyServerCertificate] = client
+	}
+
+	return client
+}
+
+// requestKey identifies a request for coalescing. It includes who makes the request, as
+// widgets with different credentials may not see the same things
+func requestKey(req *http.Request, data []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Header.Get("Authorization")))
+	hash.Write([]byte{0})
+	hash.Write(data)
+
+	return req.Method + " " + req.URL.String() + " " + hex.EncodeToString(hash.Sum(nil))
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// shared returns what the widget shares with the other widgets on its domain
+func (widget *Widget) shared() *sharedDomain {
+	return sharedFor(widget.settings.domain)
+}
@@ -0,0 +1,161 @@
+package jira
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestSharedFor(t *testing.T) {
+	useSharedDomains(t)
+
+	widgetA := &Widget{settings: &Settings{domain: "https://jira.example.com", verifyServerCertificate: true}}
+	widgetB := &Widget{settings: &Settings{domain: "https://jira.example.com/", verifyServerCertificate: true}}
+	other := &Widget{settings: &Settings{domain: "https://other.example.com", verifyServerCertificate: true}}
+
+	assert.Assert(t, widgetA.shared() == widgetB.shared())
+	assert.Assert(t, widgetA.shared().userIDs == widgetB.shared().userIDs)
+	assert.Assert(t, widgetA.shared().client(true) == widgetB.shared().client(true))
+
+	assert.Assert(t, widgetA.shared() != other.shared())
+
+	// Not verifying the certificate takes a client of its own
+	assert.Assert(t, widgetA.shared().client(true) != widgetA.shared().client(false))
+}
+
+func TestDoRequest_Coalesces(t *testing.T) {
+	useSharedDomains(t)
+
+	var requests atomic.Int32
+	arrived := make(chan struct{}, 10)
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		arrived <- struct{}{}
+		<-release
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "10001", "key": "AB-1"}`))
+	}))
+	defer server.Close()
+
+	widgets := []*Widget{}
+	for range 5 {
+		widgets = append(widgets, &Widget{settings: &Settings{domain: server.URL, email: "me@example.com", apiKey: "key"}})
+	}
+
+	keys := make([]string, len(widgets))
+	var wg sync.WaitGroup
+	fetch := func(idx int) {
+		defer wg.Done()
+
+		issue, err := widgets[idx].getIssueByID("10001")
+		assert.NilError(t, err)
+		keys[idx] = issue.Key
+	}
+
+	wg.Add(1)
+	go fetch(0)
+	waitFor(t, arrived)
+
+	// The others ask while the first request is in flight
+	for idx := 1; idx < len(widgets); idx++ {
+		wg.Add(1)
+		go fetch(idx)
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), requests.Load())
+	assert.DeepEqual(t, []string{"AB-1", "AB-1", "AB-1", "AB-1", "AB-1"}, keys)
+}
+
+func TestDoRequest_CredentialsNotCoalesced(t *testing.T) {
+	useSharedDomains(t)
+
+	var requests atomic.Int32
+	arrived := make(chan struct{}, 10)
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		arrived <- struct{}{}
+		<-release
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"queryStrings": [{"convertedQuery": "assignee = \"account:` + r.Header.Get("Authorization") + `\""}]}`))
+	}))
+	defer server.Close()
+
+	widgets := []*Widget{
+		{settings: &Settings{domain: server.URL, email: "me@example.com", apiKey: "key"}},
+		{settings: &Settings{domain: server.URL, personalAccessToken: "token"}},
+	}
+
+	results := make([]JQLConversionResponse, len(widgets))
+	var wg sync.WaitGroup
+	for idx, widget := range widgets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NilError(t, widget.jiraPostRequest("/rest/api/3/jql/pdcleaner", []byte(`{"queryStrings": ["assignee = \"me\""]}`), &results[idx]))
+		}()
+	}
+
+	// Both requests reach the server, or this times out
+	waitFor(t, arrived)
+	waitFor(t, arrived)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, `assignee = "account:Basic bWVAZXhhbXBsZS5jb206a2V5"`, results[0].QueryStrings[0].ConvertedQuery)
+	assert.Equal(t, `assignee = "account:Bearer token"`, results[1].QueryStrings[0].ConvertedQuery)
+}
+
+func TestRequestKey(t *testing.T) {
+	newRequest := func(method, token string) *http.Request {
+		req := httptest.NewRequest(method, "https://jira.example.com/rest/api/3/jql/pdcleaner", http.NoBody)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return req
+	}
+
+	key := requestKey(newRequest("POST", "a"), []byte(`{"a": 1}`))
+
+	assert.Equal(t, key, requestKey(newRequest("POST", "a"), []byte(`{"a": 1}`)))
+	assert.Assert(t, key != requestKey(newRequest("POST", "b"), []byte(`{"a": 1}`)))
+	assert.Assert(t, key != requestKey(newRequest("POST", "a"), []byte(`{"a": 2}`)))
+	assert.Assert(t, key != requestKey(newRequest("GET", "a"), []byte(`{"a": 1}`)))
+}
+
+// useSharedDomains starts the test without anything shared between widgets
+func useSharedDomains(t *testing.T) {
+	sharedDomainsMu.Lock()
+	previous := sharedDomains
+	sharedDomains = map[string]*sharedDomain{}
+	sharedDomainsMu.Unlock()
+
+	t.Cleanup(func() {
+		sharedDomainsMu.Lock()
+		sharedDomains = previous
+		sharedDomainsMu.Unlock()
+	})
+}
+
+// waitFor waits for a request to reach the test server
+func waitFor(t *testing.T, arrived chan struct{}) {
+	t.Helper()
+
+	select {
+	case <-arrived:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a request")
+	}
+}
@@ -0,0 +1,118 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/olebedev/config"
+	"github.com/rivo/tview"
+	"github.com/wtfutil/wtf/utils"
+)
+
+const (
+	// jiraTimeLayout is how Jira writes times: 2024-01-10T12:34:56.789+0000
+	jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+	statusField    = "status"
+)
+
+/* -------------------- Unexported Functions -------------------- */
+
+// parseStatusThresholds reads how long issues can be in each status, given as a map of
+// status names to durations such as 2d. Durations that can't be read are left out
+//
+// Example:
+//
+//	statusThresholds:
+//	  In Review: 2d
+//	  In Progress: 1w
+func parseStatusThresholds(ymlConfig *config.Config) map[string]time.Duration {
+	thresholds := map[string]time.Duration{}
+
+	rawThresholds, err := ymlConfig.Map("statusThresholds")
+	if err != nil {
+		return thresholds
+	}
+
+	for status, value := range rawThresholds {
+		threshold, err := utils.ParseDuration(fmt.Sprintf("%v", value))
+		if err != nil {
+			continue
+		}
+
+		thresholds[strings.ToLower(status)] = threshold
+	}
+
+	return thresholds
+}
+
+// statusChangedAt returns when the issue moved to its current status: the last status
+// change in its changelog, when it was requested with one, or else when its status
+// category last changed
+func (issue *Issue) statusChangedAt() (time.Time, bool) {
+	if issue.Changelog != nil {
+		var changedAt time.Time
+
+		for _, history := range issue.Changelog.Histories {
+			created, err := time.Parse(jiraTimeLayout, history.Created)
+			if err != nil || !created.After(changedAt) {
+				continue
+			}
+
+			for _, item := range history.Items {
+				if item.Field == statusField {
+					changedAt = created
+					break
+				}
+			}
+		}
+
+		if !changedAt.IsZero() {
+			return changedAt, true
+		}
+	}
+
+	if issue.IssueFields == nil || issue.IssueFields.StatusCategoryChangeDate == "" {
+		return time.Time{}, false
+	}
+
+	changedAt, err := time.Parse(jiraTimeLayout, issue.IssueFields.StatusCategoryChangeDate)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return changedAt, true
+}
+
+// timeInStatus returns how long the issue has been in its current status at now
+func timeInStatus(issue *Issue, now time.Time) (time.Duration, bool) {
+	changedAt, ok := issue.statusChangedAt()
+	if !ok {
+		return 0, false
+	}
+
+	return max(now.Sub(changedAt), 0), true
+}
+
+// renderTimeInStatus returns how long the issue has been in its status, such as
+// "(in review 3d)". It's red when that's longer than the threshold of the status, and ""
+// when it isn't known
+func renderTimeInStatus(issue *Issue, now time.Time, thresholds map[string]time.Duration) string {
+	if issue.IssueFields == nil || issue.IssueFields.IssueStatus == nil {
+		return ""
+	}
+
+	inStatus, ok := timeInStatus(issue, now)
+	if !ok {
+		return ""
+	}
+
+	status := strings.ToLower(issue.IssueFields.IssueStatus.IName)
+
+	color := "gray"
+	if threshold, ok := thresholds[status]; ok && inStatus > threshold {
+		color = "red"
+	}
+
+	return fmt.Sprintf("[%s](%s %s)[white]", color, tview.Escape(status), utils.CompactDuration(inStatus))
+}
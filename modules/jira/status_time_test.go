@@ -0,0 +1,215 @@
+package jira
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/olebedev/config"
+	"gotest.tools/assert"
+)
+
+var statusClock = time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+func issueInStatus(status, changedAt string) *Issue {
+	return &Issue{
+		Key: "WTF-1",
+		IssueFields: &IssueFields{
+			Summary:                  "Crash on start",
+			IssueType:                &IssueType{Name: "Bug"},
+			IssueStatus:              &IssueStatus{IName: status},
+			StatusCategoryChangeDate: changedAt,
+		},
+	}
+}
+
+func TestTimeInStatus(t *testing.T) {
+	statusChange := func(created string) ChangelogHistory {
+		return ChangelogHistory{
+			Created: created,
+			Items:   []ChangelogItem{{Field: "status", FromString: "In Progress", ToString: "In Review"}},
+		}
+	}
+	otherChange := func(created string) ChangelogHistory {
+		return ChangelogHistory{
+			Created: created,
+			Items:   []ChangelogItem{{Field: "assignee", ToString: "Chris"}},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		changedAt  string
+		changelog  *Changelog
+		expected   time.Duration
+		expectedOk bool
+	}{
+		{
+			name:       "status category change",
+			changedAt:  "2024-03-12T12:00:00.000+0000",
+			expected:   72 * time.Hour,
+			expectedOk: true,
+		},
+		{
+			name:       "time zone",
+			changedAt:  "2024-03-15T09:30:00.000-0200",
+			expected:   30 * time.Minute,
+			expectedOk: true,
+		},
+		{
+			name:       "in the future",
+			changedAt:  "2024-03-16T12:00:00.000+0000",
+			expected:   0,
+			expectedOk: true,
+		},
+		{
+			name:      "unknown",
+			changedAt: "",
+		},
+		{
+			name:      "unreadable",
+			changedAt: "March 12th",
+		},
+		{
+			name:      "latest status change in the changelog",
+			changedAt: "2024-03-01T12:00:00.000+0000",
+			changelog: &Changelog{Histories: []ChangelogHistory{
+				statusChange("2024-03-10T12:00:00.000+0000"),
+				statusChange("2024-03-14T12:00:00.000+0000"),
+				otherChange("2024-03-15T11:00:00.000+0000"),
+				statusChange("2024-03-12T12:00:00.000+0000"),
+			}},
+			expected:   24 * time.Hour,
+			expectedOk: true,
+		},
+		{
+			name:      "changelog without status changes",
+			changedAt: "2024-03-13T12:00:00.000+0000",
+			changelog: &Changelog{Histories: []ChangelogHistory{
+				otherChange("2024-03-15T11:00:00.000+0000"),
+			}},
+			expected:   48 * time.Hour,
+			expectedOk: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := issueInStatus("In Review", tt.changedAt)
+			issue.Changelog = tt.changelog
+
+			actual, ok := timeInStatus(issue, statusClock)
+
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestRenderTimeInStatus(t *testing.T) {
+	thresholds := map[string]time.Duration{
+		"in review": 48 * time.Hour,
+	}
+
+	tests := []struct {
+		name      string
+		status    string
+		changedAt string
+		expected  string
+	}{
+		{
+			name:      "under the threshold",
+			status:    "In Review",
+			changedAt: "2024-03-14T12:00:00.000+0000",
+			expected:  "[gray](in review 1d)[white]",
+		},
+		{
+			name:      "at the threshold",
+			status:    "In Review",
+			changedAt: "2024-03-13T12:00:00.000+0000",
+			expected:  "[gray](in review 2d)[white]",
+		},
+		{
+			name:      "over the threshold",
+			status:    "In Review",
+			changedAt: "2024-03-12T06:00:00.000+0000",
+			expected:  "[red](in review 3d6h)[white]",
+		},
+		{
+			name:      "status in another case",
+			status:    "IN REVIEW",
+			changedAt: "2024-03-10T12:00:00.000+0000",
+			expected:  "[red](in review 5d)[white]",
+		},
+		{
+			name:      "no threshold",
+			status:    "In Progress",
+			changedAt: "2024-01-15T12:00:00.000+0000",
+			expected:  "[gray](in progress 2mo)[white]",
+		},
+		{
+			name:      "escaped",
+			status:    "[Blocked]",
+			changedAt: "2024-03-15T11:15:00.000+0000",
+			expected:  "[gray]([blocked[] 45m)[white]",
+		},
+		{
+			name:      "unknown",
+			status:    "In Review",
+			changedAt: "",
+			expected:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, renderTimeInStatus(issueInStatus(tt.status, tt.changedAt), statusClock, thresholds))
+		})
+	}
+}
+
+func TestParseStatusThresholds(t *testing.T) {
+	ymlConfig, err := config.ParseYaml(`
+statusThresholds:
+  In Review: 2d
+  In Progress: 1w2d
+  Blocked: 36h
+  Triage: 3600
+  Waiting: soon
+`)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, map[string]time.Duration{
+		"in review":   48 * time.Hour,
+		"in progress": 9 * 24 * time.Hour,
+		"blocked":     36 * time.Hour,
+		"triage":      time.Hour,
+	}, parseStatusThresholds(ymlConfig))
+
+	ymlConfig, err = config.ParseYaml("enabled: true")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]time.Duration{}, parseStatusThresholds(ymlConfig))
+}
+
+func TestContent_TimeInStatus(t *testing.T) {
+	ymlConfig, err := config.ParseYaml("showTimeInStatus: true")
+	assert.NilError(t, err)
+	globalConfig, err := config.ParseYaml("global: {}")
+	assert.NilError(t, err)
+
+	widget := NewWidget(nil, make(chan bool, 1), nil, NewSettingsFromYAML("jira", ymlConfig, globalConfig))
+
+	changedAt := time.Now().Add(-50 * time.Hour).UTC().Format(jiraTimeLayout)
+	widget.result = &SearchResult{Issues: []Issue{
+		*issueInStatus("In Review", changedAt),
+		*issueInStatus("Open", ""),
+	}}
+
+	_, content, _ := widget.content()
+
+	lines := strings.Split(strings.TrimSuffix(displayed(content), "\n"), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.Assert(t, strings.HasSuffix(lines[1], " In Review  (in review 2d2h) Crash on start"), lines[1])
+	// Summaries stay aligned when the time isn't known
+	assert.Assert(t, strings.HasSuffix(lines[2], " Open"+strings.Repeat(" ", 7+17)+"Crash on start"), lines[2])
+}
@@ -2,6 +2,9 @@ package jira
 
 import (
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/rivo/tview"
 	"github.com/wtfutil/wtf/utils"
@@ -11,20 +14,36 @@ import (
 type Widget struct {
 	view.ScrollableWidget
 
+	pages    *tview.Pages
 	result   *SearchResult
 	settings *Settings
+	tviewApp *tview.Application
 	err      error
+
+	// searches are the widget's own query, then the saved searches. active is the index of
+	// the one shown, and cache keeps the last result of each
+	searches []savedSearch
+	active   int
+	cache    map[int]searchCache
+	searchMu sync.Mutex
 }
 
 func NewWidget(tviewApp *tview.Application, redrawChan chan bool, pages *tview.Pages, settings *Settings) *Widget {
 	widget := Widget{
 		ScrollableWidget: view.NewScrollableWidget(tviewApp, redrawChan, pages, settings.Common),
 
+		pages:    pages,
 		settings: settings,
+		tviewApp: tviewApp,
+
+		searches: buildSearches(settings),
+		cache:    map[int]searchCache{},
 	}
 
 	widget.SetRenderFunction(widget.Render)
 	widget.initializeKeyboardControls()
+	widget.initializeSearchKeyboardControls()
+	widget.EnableRefreshFooter()
 
 	return &widget
 }
@@ -32,47 +51,168 @@ func NewWidget(tviewApp *tview.Application, redrawChan chan bool, pages *tview.P
 /* -------------------- Exported Functions -------------------- */
 
 func (widget *Widget) Refresh() {
-	searchResult, err := widget.IssuesFor(
-		widget.settings.username,
-		widget.settings.projects,
-		widget.settings.jql,
-	)
+	active := widget.activeSearch()
+
+	searchResult, err := widget.issuesForSearch(widget.searches[active])
+
+	if !widget.cacheSearch(active, searchResult, err) {
+		// Another search was switched to while this one ran
+		return
+	}
+
+	widget.setResult(searchResult, err)
+	widget.MarkRefreshed()
+	widget.Render()
+}
+
+func (widget *Widget) Render() {
+	widget.Redraw(widget.content)
+}
 
+/* -------------------- Unexported Functions -------------------- */
+
+// setResult shows the result of a search, or why it failed
+func (widget *Widget) setResult(searchResult *SearchResult, err error) {
 	if err != nil {
 		widget.err = err
 		widget.result = nil
 		widget.SetItemCount(0)
 	} else {
+		if widget.searches[widget.activeSearch()].resolved {
+			// Rows are numbered in the order they're shown
+			sortByResolution(searchResult.Issues)
+		}
+
 		widget.err = nil
 		widget.result = searchResult
 		widget.SetItemCount(len(searchResult.Issues))
 	}
-	widget.Render()
+	widget.setBanner()
 }
 
-func (widget *Widget) Render() {
-	widget.Redraw(widget.content)
+func (widget *Widget) openItem() {
+	if issue := widget.selectedIssue(); issue != nil {
+		utils.OpenFile(widget.settings.domain + "/browse/" + issue.Key)
+	}
 }
 
-/* -------------------- Unexported Functions -------------------- */
+// showDetails shows the selected issue, with every one of its links, in a modal dialog
+func (widget *Widget) showDetails() {
+	issue := widget.selectedIssue()
+	if issue == nil || widget.pages == nil {
+		return
+	}
 
-func (widget *Widget) openItem() {
+	closeFunc := func() {
+		widget.pages.RemovePage(detailsPage)
+		widget.tviewApp.SetFocus(widget.View)
+	}
+
+	modal := view.NewBillboardModal(widget.detailsText(issue), closeFunc)
+	modal.SetTitle(fmt.Sprintf("  %s  ", tview.Escape(issue.Key)))
+
+	widget.pages.AddPage(detailsPage, modal, false, true)
+	widget.tviewApp.SetFocus(modal)
+}
+
+// selectedIssue returns the selected issue, or nil when none is
+func (widget *Widget) selectedIssue() *Issue {
 	sel := widget.GetSelected()
-	if sel >= 0 && widget.result != nil && sel < len(widget.result.Issues) {
-		issue := &widget.result.Issues[sel]
-		utils.OpenFile(widget.settings.domain + "/browse/" + issue.Key)
+	if sel < 0 || widget.result == nil || sel >= len(widget.result.Issues) {
+		return nil
+	}
+
+	return &widget.result.Issues[sel]
+}
+
+// detailsText renders an issue and all its links, of any type, for the details dialog
+func (widget *Widget) detailsText(issue *Issue) string {
+	fields := issue.IssueFields
+	if fields == nil {
+		fields = &IssueFields{}
+	}
+
+	str := utils.SafeSprintf(" [%s::b]%s[white::-]\n\n", widget.settings.Colors.Subheading, fields.Summary)
+	if fields.IssueType != nil {
+		str += utils.SafeSprintf(" [%s]Type:[white]   %s\n", widget.settings.Colors.Label, fields.IssueType.Name)
+	}
+	if fields.IssueStatus != nil {
+		str += utils.SafeSprintf(" [%s]Status:[white] %s\n", widget.settings.Colors.Label, fields.IssueStatus.IName)
+	}
+
+	if description := ADFToText(fields.Description); description != "" {
+		str += fmt.Sprintf("\n [%s]Description[white]\n", widget.settings.Colors.Subheading)
+		for _, line := range strings.Split(description, "\n") {
+			str += "  " + tview.Escape(line) + "\n"
+		}
+	}
+
+	str += fmt.Sprintf("\n [%s]Links[white]\n", widget.settings.Colors.Subheading)
+	if len(fields.IssueLinks) == 0 {
+		return str + "  None\n"
+	}
+
+	for _, link := range fields.IssueLinks {
+		linked := link.linked()
+		if link.Type == nil || linked == nil {
+			continue
+		}
+
+		str += "  " + renderLinks([]IssueLink{link})
+		if linked.IssueFields != nil {
+			str += " " + tview.Escape(linked.IssueFields.Summary)
+			if linked.IssueFields.IssueStatus != nil {
+				str += " [gray](" + tview.Escape(linked.IssueFields.IssueStatus.IName) + ")[white]"
+			}
+		}
+		str += "\n"
 	}
+
+	return str
 }
 
 const MaxIssueTypeLength = 7
 const MaxStatusNameLength = 14
 
+const (
+	// descriptionPrefix marks rows that show the description of an issue without a summary
+	descriptionPrefix = "(desc) "
+	// minDescriptionWidth is the least of a description a row shows, however narrow the widget
+	minDescriptionWidth = 20
+)
+
+// setBanner shows why the last refresh failed, or which issues it couldn't fetch
+func (widget *Widget) setBanner() {
+	switch {
+	case widget.err != nil:
+		widget.SetBanner(view.BannerError, widget.err.Error())
+	case widget.result != nil && len(widget.result.fetchErrors) > 0:
+		failed := len(widget.result.fetchErrors)
+		widget.SetBanner(
+			view.BannerWarning,
+			fmt.Sprintf(
+				"Could not fetch %d of %d issues: %v",
+				failed,
+				failed+len(widget.result.Issues),
+				widget.result.fetchErrors[0],
+			),
+		)
+	default:
+		widget.ClearBanner()
+	}
+}
+
 func (widget *Widget) content() (string, string, bool) {
+	title := widget.title()
+
 	if widget.err != nil {
-		return widget.CommonSettings().Title, widget.err.Error(), true
+		// The banner says why
+		return title, "", false
 	}
 
-	title := widget.CommonSettings().Title
+	if widget.searches[widget.activeSearch()].resolved {
+		return title, widget.resolvedContent(time.Now()), false
+	}
 
 	str := fmt.Sprintf(" [%s]Assigned Issues[white]\n", widget.settings.Colors.Subheading)
 
@@ -82,9 +222,12 @@ func (widget *Widget) content() (string, string, bool) {
 
 	longestIssueTypeLength, longestKeyLength, longestStatusNameLength := getLongestColumnLengths(widget.result.Issues)
 
+	timesInStatus, longestTimeInStatusLength := widget.timesInStatus(time.Now())
+
 	for idx, issue := range widget.result.Issues {
-		row := fmt.Sprintf(
-			`[%s] [%s]%-*s[white] [green]%-*s[white] [yellow]%-*s[white] [%s]%s`,
+		// The issue's fields are escaped, the colors have no brackets to escape
+		row := utils.SafeSprintf(
+			`[%s] [%s]%-*s[white] [green]%-*s[white] [yellow]%-*s[white] `,
 			widget.RowColor(idx),
 			widget.issueTypeColor(&issue),
 			longestIssueTypeLength+1,
@@ -93,16 +236,65 @@ func (widget *Widget) content() (string, string, bool) {
 			issue.Key,
 			longestStatusNameLength+1,
 			trimToMaxLength(issue.IssueFields.IssueStatus.IName, MaxStatusNameLength),
-			widget.RowColor(idx),
-			tview.Escape(issue.IssueFields.Summary),
 		)
 
-		str += utils.HighlightableHelper(widget.View, row, idx, len(issue.IssueFields.Summary))
+		if longestTimeInStatusLength > 0 {
+			row += timesInStatus[idx] + utils.RowPadding(utils.DisplayWidth(timesInStatus[idx]), longestTimeInStatusLength+1)
+		}
+
+		links := renderLinks(linksOfInterest(&issue, widget.settings.showLinkTypes))
+
+		if links != "" {
+			links = " " + links
+		}
+
+		if issue.IssueFields.Summary != "" {
+			row += utils.SafeSprintf("[%s]%s", widget.RowColor(idx), issue.IssueFields.Summary)
+		} else {
+			row += fmt.Sprintf("[%s]%s", widget.RowColor(idx), widget.descriptionSummary(&issue, utils.DisplayWidth(row+links)))
+		}
+
+		row += links
+
+		str += utils.HighlightableHelper(widget.View, row, idx, utils.DisplayWidth(row))
 	}
 
 	return title, str, false
 }
 
+// descriptionSummary returns what's shown for an issue without a summary: the first
+// paragraph of its description, prefixed with (desc), and cut to the room left in the row
+// by the used columns
+func (widget *Widget) descriptionSummary(issue *Issue, used int) string {
+	description := adfFirstParagraph(issue.IssueFields.Description)
+	if description == "" {
+		return ""
+	}
+
+	_, _, width, _ := widget.View.GetInnerRect()
+	room := max(width-used, minDescriptionWidth)
+
+	return utils.TruncateTagged(tview.Escape(descriptionPrefix+description), room, "…")
+}
+
+// timesInStatus renders how long each issue has been in its status at now, and returns the
+// width of the longest
+func (widget *Widget) timesInStatus(now time.Time) ([]string, int) {
+	if !widget.settings.showTimeInStatus {
+		return nil, 0
+	}
+
+	rendered := make([]string, len(widget.result.Issues))
+	longest := 0
+
+	for idx := range widget.result.Issues {
+		rendered[idx] = renderTimeInStatus(&widget.result.Issues[idx], now, widget.settings.statusThresholds)
+		longest = max(longest, utils.DisplayWidth(rendered[idx]))
+	}
+
+	return rendered, longest
+}
+
 func getLongestColumnLengths(issues []Issue) (int, int, int) {
 	longestIssueTypeLength := 0
 	longestKeyLength := 0
@@ -0,0 +1,113 @@
+package jira
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/olebedev/config"
+	"github.com/rivo/tview"
+	"gotest.tools/assert"
+)
+
+// displayed strips the tview tags from rendered content, leaving the text as it appears on
+// screen, by running it through a TextView
+func displayed(content string) string {
+	view := tview.NewTextView()
+	view.SetDynamicColors(true)
+	view.SetRegions(true)
+	view.SetText(content)
+
+	return view.GetText(true)
+}
+
+func newTestWidget(t *testing.T) *Widget {
+	t.Helper()
+
+	ymlConfig, err := config.ParseYaml("enabled: true")
+	assert.NilError(t, err)
+	globalConfig, err := config.ParseYaml("global: {}")
+	assert.NilError(t, err)
+
+	return NewWidget(tview.NewApplication(), make(chan bool, 1), nil, NewSettingsFromYAML("jira", ymlConfig, globalConfig))
+}
+
+func TestContent_EscapesSummaries(t *testing.T) {
+	summaries := []string{
+		"[red]Crash on start",
+		"Empty list [] in the response",
+		`Selection ["x"] lost`,
+		`Region [""] closed early`,
+		"Unmatched [bracket and ] the other",
+	}
+
+	for _, summary := range summaries {
+		t.Run(summary, func(t *testing.T) {
+			widget := newTestWidget(t)
+			widget.result = &SearchResult{Issues: []Issue{{
+				Key: "WTF-1",
+				IssueFields: &IssueFields{
+					Summary:     summary,
+					IssueType:   &IssueType{Name: "Bug"},
+					IssueStatus: &IssueStatus{IName: "[Open]"},
+				},
+			}}}
+
+			_, content, _ := widget.content()
+
+			text := displayed(content)
+			assert.Assert(t, strings.Contains(text, " [Open]  "+summary), text)
+			assert.Assert(t, strings.HasSuffix(text, summary+"\n"), text)
+		})
+	}
+}
+
+func TestSetBanner(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		result   *SearchResult
+		expected string
+	}{
+		{
+			name:     "failed",
+			err:      errors.New("JIRA search failed: 401 Unauthorized"),
+			expected: "✖ JIRA search failed: 401 Unauthorized",
+		},
+		{
+			name: "partial",
+			result: &SearchResult{
+				Issues:      []Issue{{Key: "WTF-1"}, {Key: "WTF-2"}},
+				fetchErrors: []error{errors.New("failed to fetch issue 10003: timeout")},
+			},
+			expected: "⚠ Could not fetch 1 of 3 issues: failed to fetch issue 10003: timeout",
+		},
+		{
+			name:     "complete",
+			result:   &SearchResult{Issues: []Issue{{Key: "WTF-1"}}},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			widget := newTestWidget(t)
+			widget.View.SetRect(0, 0, 120, 10)
+			widget.err = tt.err
+			widget.result = tt.result
+
+			widget.setBanner()
+			widget.Redraw(func() (string, string, bool) { return "", "content", false })
+
+			lines := strings.Split(widget.View.GetText(true), "\n")
+			if tt.expected == "" {
+				assert.Equal(t, "content", lines[0])
+				assert.Equal(t, 0, len(widget.LastErrors()))
+				return
+			}
+
+			assert.Equal(t, tt.expected, lines[0])
+			assert.Equal(t, 1, len(widget.LastErrors()))
+		})
+	}
+}
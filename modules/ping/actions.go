@@ -0,0 +1,276 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/wtfutil/wtf/view"
+)
+
+const (
+	selectedRegion = "selected"
+	commandPage    = "ping command"
+)
+
+// runCommandOutput runs a command and returns its output. It is replaceable in tests
+var runCommandOutput = func(ctx context.Context, name string, args []string) (string, error) {
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	return string(output), err
+}
+
+// runInteractiveCommand runs a command in the terminal, while the widgets are suspended.
+// It is replaceable in tests
+var runInteractiveCommand = func(name string, args []string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+/* -------------------- Selection -------------------- */
+
+// listedHosts returns the indexes of the hosts that have a line of their own, in the order
+// they are listed
+func (widget *Widget) listedHosts() []int {
+	listed := []int{}
+	for _, sec := range widget.sections() {
+		for _, idx := range sec.hosts {
+			if widget.settings.hideUp && widget.hosts[idx].Up && !widget.hosts[idx].isDegraded() {
+				continue
+			}
+			listed = append(listed, idx)
+		}
+	}
+
+	return listed
+}
+
+// selectedHost returns the index of the selected host, and false if none is selected
+func (widget *Widget) selectedHost() (int, bool) {
+	if widget.selectedKey == "" {
+		return 0, false
+	}
+
+	for _, idx := range widget.listedHosts() {
+		if widget.hosts[idx].key() == widget.selectedKey {
+			return idx, true
+		}
+	}
+
+	return 0, false
+}
+
+// moveSelection selects the host offset lines away from the selected one, wrapping
+// around. With no host selected, it starts from either end of the list
+func (widget *Widget) moveSelection(offset int) {
+	listed := widget.listedHosts()
+	if len(listed) == 0 {
+		widget.selectedKey = ""
+		return
+	}
+
+	pos := -1
+	if offset < 0 {
+		pos = len(listed)
+	}
+	if selected, ok := widget.selectedHost(); ok {
+		for i, idx := range listed {
+			if idx == selected {
+				pos = i
+			}
+		}
+	}
+
+	pos = ((pos+offset)%len(listed) + len(listed)) % len(listed)
+	widget.selectedKey = widget.hosts[listed[pos]].key()
+}
+
+func (widget *Widget) next() {
+	widget.moveSelection(1)
+	widget.display()
+}
+
+func (widget *Widget) prev() {
+	widget.moveSelection(-1)
+	widget.display()
+}
+
+// escape cancels the command running for the selected host, if there is one, and clears
+// the selection
+func (widget *Widget) escape() {
+	widget.cancelCommand()
+	widget.selectedKey = ""
+	widget.display()
+}
+
+/* -------------------- Actions -------------------- */
+
+// recheck checks the selected host again right away, or every host if none is selected.
+// The check runs in the background, and is skipped while a refresh, which checks the hosts
+// already, is running
+func (widget *Widget) recheck() {
+	idx, ok := widget.selectedHost()
+	if !ok {
+		go widget.Refresh()
+		return
+	}
+
+	if !widget.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	key := widget.hosts[idx].key()
+	go func() {
+		defer widget.refreshing.Store(false)
+
+		widget.recheckHost(key)
+		widget.display()
+	}()
+}
+
+// recheckHost checks a single host, leaving the others be. Its state changes are picked up
+// by the next refresh. Callers must hold the refreshing guard, for the hosts not to change
+// while it's checked
+func (widget *Widget) recheckHost(key string) {
+	idx, ok := widget.hostIndex(key)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	if interval := widget.CommonSettings().RefreshInterval; interval > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, interval)
+		defer cancel()
+	}
+
+	result := checkWithRetries(ctx, widget.hosts[idx], widget.settings.retries, widget.settings.retryDelay)
+	widget.hosts[idx].applyResult(result)
+	widget.hosts[idx].CheckedAt = nowFunc()
+}
+
+// hostIndex returns the index of the host with the given key
+func (widget *Widget) hostIndex(key string) (int, bool) {
+	for idx, host := range widget.hosts {
+		if host.key() == key {
+			return idx, true
+		}
+	}
+
+	return 0, false
+}
+
+// traceroute runs tracerouteCommand for the selected host, showing its output in a modal.
+// Closing the modal with Escape stops the command
+func (widget *Widget) traceroute() {
+	idx, ok := widget.selectedHost()
+	if !ok {
+		return
+	}
+
+	host := widget.hosts[idx]
+	name, args := expandCommand(widget.settings.tracerouteCommand, host)
+	if name == "" {
+		return
+	}
+
+	widget.cancelCommand()
+	ctx, cancel := context.WithCancel(context.Background())
+	widget.commandCancel = cancel
+
+	widget.showModal(fmt.Sprintf("Tracing the route to %s…\n\nPress Esc to cancel", host.Hostname), cancel)
+
+	go func() {
+		output, err := runCommandOutput(ctx, name, args)
+		if ctx.Err() != nil {
+			return // cancelled
+		}
+		cancel()
+
+		text := tview.Escape(output)
+		if err != nil {
+			text += fmt.Sprintf("\n[red]%s failed: %s", name, tview.Escape(err.Error()))
+		}
+
+		widget.tviewApp.QueueUpdateDraw(func() {
+			widget.showModal(text, cancel)
+		})
+	}()
+}
+
+// ssh runs sshCommand for the selected host in the terminal, suspending the widgets until
+// it exits
+func (widget *Widget) ssh() {
+	idx, ok := widget.selectedHost()
+	if !ok {
+		return
+	}
+
+	name, args := expandCommand(widget.settings.sshCommand, widget.hosts[idx])
+	if name == "" {
+		return
+	}
+
+	widget.tviewApp.Suspend(func() {
+		widget.actionErr = nil
+		if err := runInteractiveCommand(name, args); err != nil {
+			widget.actionErr = fmt.Errorf("sshCommand failed: %w", err)
+		}
+	})
+	widget.display()
+}
+
+// cancelCommand stops the command started for a host, if it is still running
+func (widget *Widget) cancelCommand() {
+	if widget.commandCancel != nil {
+		widget.commandCancel()
+		widget.commandCancel = nil
+	}
+}
+
+// showModal shows text over the widgets, replacing the modal already shown, if any.
+// onClose is called when it is closed
+func (widget *Widget) showModal(text string, onClose func()) {
+	if widget.pages == nil {
+		return
+	}
+
+	closeFunc := func() {
+		onClose()
+		widget.pages.RemovePage(commandPage)
+		widget.tviewApp.SetFocus(widget.View)
+	}
+
+	modal := view.NewBillboardModal(text, closeFunc)
+
+	widget.pages.RemovePage(commandPage)
+	widget.pages.AddPage(commandPage, modal, false, true)
+	widget.tviewApp.SetFocus(modal)
+}
+
+// expandCommand splits a command template into the command to run and its arguments, then
+// fills in {hostname}, {label} and {addr}, the address the host last resolved to, in each
+func expandCommand(template string, host Host) (string, []string) {
+	fields := strings.Fields(template)
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	addr := host.Addr
+	if addr == "" {
+		addr = host.Hostname
+	}
+
+	replacer := strings.NewReplacer("{hostname}", host.Hostname, "{label}", host.Label, "{addr}", addr)
+	for i, field := range fields {
+		fields[i] = replacer.Replace(field)
+	}
+
+	return fields[0], fields[1:]
+}
@@ -0,0 +1,149 @@
+package ping
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wtfutil/wtf/cfg"
+	"gotest.tools/assert"
+)
+
+func Test_expandCommand(t *testing.T) {
+	host := Host{Label: "Web server", Hostname: "web.example.com", Addr: "203.0.113.7"}
+
+	name, args := expandCommand("ssh -t admin@{hostname} echo {label} at {addr}", host)
+	assert.Equal(t, "ssh", name)
+	assert.DeepEqual(t, []string{"-t", "admin@web.example.com", "echo", "Web server", "at", "203.0.113.7"}, args)
+
+	// Hosts that weren't resolved yet use their hostname for {addr}
+	host.Addr = ""
+	name, args = expandCommand("traceroute -n {addr}", host)
+	assert.Equal(t, "traceroute", name)
+	assert.DeepEqual(t, []string{"-n", "web.example.com"}, args)
+
+	name, args = expandCommand("   ", host)
+	assert.Equal(t, "", name)
+	assert.Equal(t, 0, len(args))
+}
+
+func Test_moveSelection(t *testing.T) {
+	widget := &Widget{hosts: orderTestHosts(), settings: &Settings{sortBy: sortByLabel}}
+
+	selected := func() string {
+		idx, ok := widget.selectedHost()
+		if !ok {
+			return ""
+		}
+		return widget.hosts[idx].Label
+	}
+
+	labels := []string{}
+	for range widget.hosts {
+		widget.moveSelection(1)
+		labels = append(labels, selected())
+	}
+	assert.DeepEqual(t, sectionLabels(widget)[0][1:], labels)
+
+	// Wraps around
+	widget.moveSelection(1)
+	assert.Equal(t, labels[0], selected())
+	widget.moveSelection(-1)
+	assert.Equal(t, labels[len(labels)-1], selected())
+
+	widget.selectedKey = ""
+	widget.moveSelection(-1)
+	assert.Equal(t, labels[len(labels)-1], selected())
+}
+
+func Test_recheckHost(t *testing.T) {
+	checked := []string{}
+	stubCheckHost(t, func(host Host) checkResult {
+		checked = append(checked, host.Label)
+		return checkResult{AvgRtt: 4 * time.Millisecond, Addr: "192.168.1.1"}
+	})
+
+	widget := newPoolTestWidget(3, &Settings{
+		common:        &cfg.Common{RefreshInterval: time.Minute},
+		maxConcurrent: 1,
+	})
+	widget.hosts[2].Err = "timeout"
+
+	widget.recheckHost(widget.hosts[2].key())
+
+	assert.DeepEqual(t, []string{"host-2"}, checked)
+	assert.Assert(t, !widget.hosts[0].Up)
+	assert.Assert(t, widget.hosts[2].Up)
+	assert.Equal(t, 4*time.Millisecond, widget.hosts[2].AvgRtt)
+	assert.Equal(t, "", widget.hosts[2].Err)
+	assert.Equal(t, "192.168.1.1", widget.hosts[2].Addr)
+
+	// Hosts that are gone are left be
+	widget.recheckHost(Host{Label: "gone"}.key())
+	assert.Equal(t, 1, len(checked))
+}
+
+func Test_recheck_OverlapsRefresh(t *testing.T) {
+	var calls atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	// Each check waits for the test to let it through
+	stubCheckHost(t, func(Host) checkResult {
+		calls.Add(1)
+		started <- struct{}{}
+		<-release
+		return checkResult{AvgRtt: 4 * time.Millisecond}
+	})
+
+	widget := newPoolTestWidget(2, &Settings{
+		common:        &cfg.Common{RefreshInterval: time.Minute},
+		maxConcurrent: 1,
+	})
+	widget.selectedKey = widget.hosts[1].key()
+
+	// A recheck while a refresh is checking the hosts is skipped
+	done := make(chan struct{})
+	go func() {
+		widget.Refresh()
+		close(done)
+	}()
+	<-started
+
+	widget.recheck()
+	assert.Equal(t, int32(1), calls.Load())
+
+	release <- struct{}{}
+	<-started
+	release <- struct{}{}
+	<-done
+	<-widget.RedrawChan
+	assert.Equal(t, int32(2), calls.Load())
+
+	// A recheck runs in the background, and a refresh while it does is skipped
+	widget.hosts[1].AvgRtt = 0
+	widget.recheck()
+	<-started
+
+	widget.Refresh()
+	assert.Equal(t, int32(3), calls.Load())
+
+	release <- struct{}{}
+	<-widget.RedrawChan
+	assert.Assert(t, widget.hosts[1].Up)
+	assert.Equal(t, 4*time.Millisecond, widget.hosts[1].AvgRtt)
+}
+
+func Test_content_selection(t *testing.T) {
+	hosts := []Host{
+		{Label: "router", Up: true},
+		{Label: "offline", Up: false},
+	}
+
+	widget := &Widget{hosts: hosts, settings: &Settings{}, selectedKey: hosts[1].key()}
+	assert.Equal(t,
+		"[white]router      : [green]Up\n"+
+			`["selected"][white]offline     : [red]DOWN[""]`,
+		widget.content(),
+	)
+}
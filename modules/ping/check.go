@@ -0,0 +1,276 @@
+package ping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	probing "github.com/prometheus-community/pro-bing"
+)
+
+const (
+	checkICMP = "icmp"
+	checkTCP  = "tcp"
+	checkHTTP = "http"
+)
+
+// checkResult is the outcome of checking a host
+type checkResult struct {
+	MinRtt     time.Duration
+	AvgRtt     time.Duration
+	MaxRtt     time.Duration
+	PacketLoss float64
+	Err        string
+	Addr       string // the address the host resolved to
+	DNSErr     bool   // whether the host could not be resolved
+	Attempts   int    // how many times the host was checked, counting retries
+	DNSTime    time.Duration
+}
+
+// lookupIP resolves a hostname. It is replaceable in tests
+var lookupIP = net.DefaultResolver.LookupIP
+
+// checkHost checks a host, giving up when ctx is done. It is replaceable in tests
+var checkHost = check
+
+// sleepJitter waits for a random time of up to jitter, returning false if ctx is done first
+func sleepJitter(ctx context.Context, jitter time.Duration) bool {
+	if jitter <= 0 {
+		return ctx.Err() == nil
+	}
+
+	return sleep(ctx, time.Duration(rand.Int63n(int64(jitter))))
+}
+
+// sleep waits for delay, returning false if ctx is done first
+func sleep(ctx context.Context, delay time.Duration) bool {
+	if delay <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// checkWithRetries checks a host, then checks it again, up to retries more times and delay
+// apart, for as long as it is down. It stops retrying when another try might not be done
+// before ctx's deadline
+func checkWithRetries(ctx context.Context, host Host, retries int, delay time.Duration) checkResult {
+	result := checkHost(ctx, host)
+	result.Attempts = 1
+
+	for result.Attempts <= retries && !host.isUp(result.PacketLoss) {
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay+host.Timeout {
+			break
+		}
+		if !sleep(ctx, delay) {
+			break
+		}
+
+		attempts := result.Attempts + 1
+		result = checkHost(ctx, host)
+		result.Attempts = attempts
+	}
+
+	return result
+}
+
+// check resolves a host over its IP version, then checks it the way its type says to,
+// within its timeout or until ctx is done, whichever comes first
+func check(ctx context.Context, host Host) checkResult {
+	switch host.Type {
+	case checkICMP, "", checkHTTP:
+	case checkTCP:
+		if host.Port == 0 {
+			return checkResult{PacketLoss: 100, Err: "tcp checks need a port"}
+		}
+	default:
+		return checkResult{PacketLoss: 100, Err: fmt.Sprintf("unknown check type %q", host.Type)}
+	}
+
+	ip, dnsTime, err := resolve(ctx, host)
+	if err != nil {
+		return checkResult{PacketLoss: 100, Err: errorSummary(err), DNSErr: true, DNSTime: dnsTime}
+	}
+
+	var result checkResult
+	switch host.Type {
+	case checkTCP:
+		result = checkAttempts(ctx, host, func(ctx context.Context) error {
+			return dialTCP(ctx, host, ip)
+		})
+	case checkHTTP:
+		result = checkAttempts(ctx, host, func(ctx context.Context) error {
+			return getHTTP(ctx, host, ip)
+		})
+	default:
+		result = checkICMPHost(ctx, host, ip)
+	}
+
+	result.Addr = ip.String()
+	result.DNSTime = dnsTime
+
+	return result
+}
+
+// network returns the IP network to resolve and check the host over: ip4, ip6, or ip for
+// either
+func (host Host) network() string {
+	switch host.IPVersion {
+	case ipVersion4:
+		return "ip4"
+	case ipVersion6:
+		return "ip6"
+	default:
+		return "ip"
+	}
+}
+
+// target returns the name to resolve for the host: its URL's host for http checks, and its
+// hostname for the others
+func (host Host) target() string {
+	if host.Type == checkHTTP {
+		if parsed, err := url.Parse(host.URL); err == nil && parsed.Hostname() != "" {
+			return parsed.Hostname()
+		}
+	}
+
+	return host.Hostname
+}
+
+// checkICMPHost pings the host at ip
+func checkICMPHost(ctx context.Context, host Host, ip net.IP) checkResult {
+	pinger := probing.New(host.Hostname)
+	pinger.SetNetwork(host.network())
+	pinger.SetIPAddr(&net.IPAddr{IP: ip})
+
+	pinger.Count = host.Count
+	pinger.Timeout = host.Timeout
+
+	// Blocks until finished
+	if err := pinger.RunWithContext(ctx); err != nil {
+		return checkResult{PacketLoss: 100, Err: err.Error()}
+	}
+
+	stats := pinger.Statistics() // get send/receive/duplicate/rtt stats
+
+	return checkResult{MinRtt: stats.MinRtt, AvgRtt: stats.AvgRtt, MaxRtt: stats.MaxRtt, PacketLoss: stats.PacketLoss}
+}
+
+// checkAttempts makes the host's count of attempts one after the other, all within its
+// timeout, and reports their latency and the percentage that failed the way pings are
+// reported. The error is that of the last failed attempt
+func checkAttempts(ctx context.Context, host Host, attempt func(context.Context) error) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, host.Timeout)
+	defer cancel()
+
+	count := max(host.Count, 1)
+	failed := 0
+	var total, fastest, slowest time.Duration
+	var lastErr error
+
+	for i := 0; i < count; i++ {
+		start := time.Now()
+		if err := attempt(ctx); err != nil {
+			failed++
+			lastErr = err
+			continue
+		}
+
+		took := time.Since(start)
+		if fastest == 0 || took < fastest {
+			fastest = took
+		}
+		slowest = max(slowest, took)
+		total += took
+	}
+
+	result := checkResult{PacketLoss: float64(failed) / float64(count) * 100}
+	if failed < count {
+		result.MinRtt = fastest
+		result.AvgRtt = total / time.Duration(count-failed)
+		result.MaxRtt = slowest
+	}
+	if lastErr != nil {
+		result.Err = errorSummary(lastErr)
+	}
+
+	return result
+}
+
+// dialTCP opens, then closes, a TCP connection to the host's port at ip
+func dialTCP(ctx context.Context, host Host, ip net.IP) error {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip.String(), strconv.Itoa(host.Port)))
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+// getHTTP requests the host's URL from ip, failing unless it answers with the expected
+// status. The request still names the URL's host, so virtual hosts and TLS work as usual
+func getHTTP(ctx context.Context, host Host, ip net.IP) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, host.URL, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		_, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+	}
+	defer transport.CloseIdleConnections()
+
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != host.ExpectStatus {
+		return errors.New(resp.Status)
+	}
+
+	return nil
+}
+
+// errorSummary describes a failed check in a few words (ex: "timeout",
+// "connect: connection refused", "503 Service Unavailable", "no such host")
+func errorSummary(err error) string {
+	var netErr net.Error
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.As(err, &dnsErr):
+		return dnsErr.Err
+	case errors.As(err, &opErr):
+		return opErr.Err.Error()
+	default:
+		return err.Error()
+	}
+}
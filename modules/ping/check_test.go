@@ -0,0 +1,257 @@
+package ping
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func newCheckServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// closedPort returns a local port nothing listens on
+func closedPort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	assert.NilError(t, listener.Close())
+
+	return port
+}
+
+func Test_check_HTTP(t *testing.T) {
+	ok := newCheckServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	unavailable := newCheckServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	slow := newCheckServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+
+	tests := []struct {
+		name        string
+		url         string
+		expectedUp  bool
+		expectedErr string
+	}{
+		{name: "expected status", url: ok.URL, expectedUp: true},
+		{name: "wrong status", url: unavailable.URL, expectedErr: "503 Service Unavailable"},
+		{name: "connection refused", url: "http://127.0.0.1:" + strconv.Itoa(closedPort(t)), expectedErr: "connect: connection refused"},
+		{name: "timeout", url: slow.URL, expectedErr: "timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host := Host{
+				Type:                 checkHTTP,
+				URL:                  tt.url,
+				ExpectStatus:         http.StatusNoContent,
+				Count:                2,
+				Timeout:              200 * time.Millisecond,
+				LossThresholdPercent: 100,
+			}
+
+			result := check(context.Background(), host)
+
+			assert.Equal(t, tt.expectedUp, host.isUp(result.PacketLoss))
+			assert.Equal(t, tt.expectedErr, result.Err)
+			if tt.expectedUp {
+				assert.Equal(t, 0.0, result.PacketLoss)
+				assert.Assert(t, result.AvgRtt > 0)
+			} else {
+				assert.Equal(t, 100.0, result.PacketLoss)
+				assert.Equal(t, time.Duration(0), result.AvgRtt)
+			}
+		})
+	}
+}
+
+func Test_check_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	open := Host{
+		Type:                 checkTCP,
+		Hostname:             "127.0.0.1",
+		Port:                 listener.Addr().(*net.TCPAddr).Port,
+		Count:                3,
+		Timeout:              time.Second,
+		LossThresholdPercent: 100,
+	}
+	result := check(context.Background(), open)
+	assert.Assert(t, open.isUp(result.PacketLoss))
+	assert.Equal(t, "", result.Err)
+	assert.Assert(t, result.AvgRtt > 0)
+	assert.Assert(t, result.MinRtt > 0 && result.MinRtt <= result.AvgRtt && result.AvgRtt <= result.MaxRtt)
+
+	closed := open
+	closed.Port = closedPort(t)
+	result = check(context.Background(), closed)
+	assert.Assert(t, !closed.isUp(result.PacketLoss))
+	assert.Equal(t, "connect: connection refused", result.Err)
+
+	noPort := open
+	noPort.Port = 0
+	assert.Equal(t, "tcp checks need a port", check(context.Background(), noPort).Err)
+}
+
+func Test_check_UnknownType(t *testing.T) {
+	result := check(context.Background(), Host{Type: "udp"})
+
+	assert.Equal(t, 100.0, result.PacketLoss)
+	assert.Equal(t, `unknown check type "udp"`, result.Err)
+}
+
+func stubLookupIP(t *testing.T, fake func(ctx context.Context, network, host string) ([]net.IP, error)) {
+	originalLookup := lookupIP
+	t.Cleanup(func() { lookupIP = originalLookup })
+
+	lookupIP = fake
+}
+
+func Test_check_ResolvesOverIPVersion(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	lookups := []string{}
+	stubLookupIP(t, func(ctx context.Context, network, host string) ([]net.IP, error) {
+		lookups = append(lookups, network+" "+host)
+		return []net.IP{net.ParseIP("127.0.0.1")}, nil
+	})
+
+	host := Host{
+		Type:                 checkTCP,
+		Hostname:             "db.example.com",
+		Port:                 listener.Addr().(*net.TCPAddr).Port,
+		IPVersion:            ipVersion4,
+		Count:                1,
+		Timeout:              time.Second,
+		LossThresholdPercent: 100,
+	}
+
+	result := check(context.Background(), host)
+
+	assert.DeepEqual(t, []string{"ip4 db.example.com"}, lookups)
+	assert.Equal(t, "", result.Err)
+	assert.Equal(t, "127.0.0.1", result.Addr)
+	assert.Assert(t, host.isUp(result.PacketLoss))
+}
+
+func Test_check_DNSError(t *testing.T) {
+	stubLookupIP(t, func(ctx context.Context, network, host string) ([]net.IP, error) {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	})
+
+	result := check(context.Background(), Host{Hostname: "v6only.example.com", IPVersion: ipVersion6, Timeout: time.Second})
+
+	assert.Assert(t, result.DNSErr)
+	assert.Equal(t, "no such host", result.Err)
+	assert.Equal(t, 100.0, result.PacketLoss)
+}
+
+// failFirst fakes a pinger whose first failures checks of each host lose every packet
+func failFirst(t *testing.T, failures int) *int {
+	calls := 0
+	stubCheckHost(t, func(Host) checkResult {
+		calls++
+		if calls <= failures {
+			return checkResult{PacketLoss: 100, Err: "timeout"}
+		}
+		return checkResult{AvgRtt: 3 * time.Millisecond}
+	})
+
+	return &calls
+}
+
+func Test_checkWithRetries(t *testing.T) {
+	host := Host{Hostname: "192.168.1.1", LossThresholdPercent: 100}
+
+	t.Run("up on the second try", func(t *testing.T) {
+		calls := failFirst(t, 1)
+
+		result := checkWithRetries(context.Background(), host, 2, time.Millisecond)
+		assert.Equal(t, 2, *calls)
+		assert.Equal(t, 2, result.Attempts)
+		assert.Equal(t, 0.0, result.PacketLoss)
+		assert.Equal(t, "", result.Err)
+	})
+
+	t.Run("up on the first try", func(t *testing.T) {
+		calls := failFirst(t, 0)
+
+		result := checkWithRetries(context.Background(), host, 2, time.Millisecond)
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, 1, result.Attempts)
+	})
+
+	t.Run("down after every retry", func(t *testing.T) {
+		calls := failFirst(t, 5)
+
+		result := checkWithRetries(context.Background(), host, 2, time.Millisecond)
+		assert.Equal(t, 3, *calls)
+		assert.Equal(t, 3, result.Attempts)
+		assert.Equal(t, 100.0, result.PacketLoss)
+	})
+
+	t.Run("no retries", func(t *testing.T) {
+		calls := failFirst(t, 1)
+
+		result := checkWithRetries(context.Background(), host, 0, time.Millisecond)
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, 100.0, result.PacketLoss)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		calls := failFirst(t, 1)
+
+		// Another try would need a second, and the deadline is sooner than that
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		slow := host
+		slow.Timeout = time.Second
+
+		start := time.Now()
+		result := checkWithRetries(ctx, slow, 2, time.Millisecond)
+		assert.Assert(t, time.Since(start) < 100*time.Millisecond)
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, 100.0, result.PacketLoss)
+	})
+}
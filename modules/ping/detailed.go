@@ -0,0 +1,122 @@
+package ping
+
+import (
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/wtfutil/wtf/utils"
+)
+
+const (
+	viewCompact  = "compact"
+	viewDetailed = "detailed"
+
+	// columnGap is the space between the columns of the detailed layout
+	columnGap = "  "
+)
+
+// detailColumn is a column of the detailed layout
+type detailColumn struct {
+	name  string
+	right bool // numbers are right-aligned
+}
+
+var detailColumns = []detailColumn{
+	{name: "Host"},
+	{name: "Status"},
+	{name: "Min", right: true},
+	{name: "Avg", right: true},
+	{name: "Max", right: true},
+	{name: "Loss", right: true},
+	{name: "Changed"},
+	{name: "Address"},
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// columnWidths returns the display width of the widest cell of each column
+func columnWidths(rows [][]string) []int {
+	widths := []int{}
+	for _, row := range rows {
+		for col, cell := range row {
+			if col >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[col] = max(widths[col], utils.DisplayWidth(cell))
+		}
+	}
+
+	return widths
+}
+
+// alignCells pads each cell to the width of its column, on the left for right-aligned ones
+func alignCells(cells []string, widths []int) []string {
+	aligned := make([]string, len(cells))
+	for col, cell := range cells {
+		padding := strings.Repeat(" ", max(widths[col]-utils.DisplayWidth(cell), 0))
+		if detailColumns[col].right {
+			aligned[col] = padding + cell
+		} else {
+			aligned[col] = cell + padding
+		}
+	}
+
+	return aligned
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// toggleView switches between the compact and detailed layouts, for the rest of the session
+func (widget *Widget) toggleView() {
+	widget.detailed = !widget.detailed
+	widget.display()
+}
+
+// detailCells returns the cells of the host at idx in the detailed layout, escaped but not
+// colored. Round-trip times are only shown for hosts that are up, and packet loss for hosts
+// that were checked
+func (widget *Widget) detailCells(idx int) []string {
+	t := widget.hosts[idx]
+	_, status := widget.hostStatus(t)
+
+	cells := []string{tview.Escape(t.Label), status, "", "", "", "", "", t.Addr}
+
+	if t.Up && !t.TimedOut {
+		cells[2] = formatLatency(t.MinRtt)
+		cells[3] = formatLatency(t.AvgRtt)
+		cells[4] = formatLatency(t.MaxRtt)
+	}
+	if t.Attempts > 0 && !t.DNSErr {
+		cells[5] = formatLoss(t.PacketLoss)
+	}
+	if idx < len(widget.states) && !widget.states[idx].since.IsZero() {
+		cells[6] = formatDuration(nowFunc().Sub(widget.states[idx].since)) + " ago"
+	}
+
+	return cells
+}
+
+// detailedLine returns the header of the detailed layout, and the func rendering the host at
+// idx as a row of it, with its columns as wide as those of every host
+func (widget *Widget) detailedLine() (string, func(idx int) string) {
+	header := make([]string, len(detailColumns))
+	for col, column := range detailColumns {
+		header[col] = column.name
+	}
+
+	rows := [][]string{header}
+	for idx := range widget.hosts {
+		rows = append(rows, widget.detailCells(idx))
+	}
+	widths := columnWidths(rows)
+
+	render := func(idx int) string {
+		cells := alignCells(rows[idx+1], widths)
+		color, _ := widget.hostStatus(widget.hosts[idx])
+		cells[1] = "[" + color + "]" + cells[1] + "[white]"
+
+		return "[white]" + strings.TrimRight(strings.Join(cells, columnGap), " ")
+	}
+
+	return "[gray]" + strings.TrimRight(strings.Join(alignCells(header, widths), columnGap), " "), render
+}
@@ -0,0 +1,83 @@
+package ping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wtfutil/wtf/cfg"
+	"gotest.tools/assert"
+)
+
+// layoutTestWidget is a widget with hosts in every state, shown with both layouts
+func layoutTestWidget(t *testing.T) *Widget {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	originalNow := nowFunc
+	t.Cleanup(func() { nowFunc = originalNow })
+	nowFunc = func() time.Time { return now }
+
+	hosts := []Host{
+		{
+			Label: "router", Up: true, Attempts: 1, Addr: "192.168.1.1",
+			MinRtt: 700 * time.Microsecond, AvgRtt: 2 * time.Millisecond, MaxRtt: 4 * time.Millisecond,
+		},
+		{
+			Label: "api.example.com", Up: true, Attempts: 1, Addr: "203.0.113.7",
+			MinRtt: 90 * time.Millisecond, AvgRtt: 112 * time.Millisecond, MaxRtt: 1500 * time.Millisecond,
+			PacketLoss: 25, LossThresholdPercent: 100,
+		},
+		{Label: "[nas]", Attempts: 3, PacketLoss: 100, Addr: "192.168.1.20"},
+		{Label: "gone.example", Attempts: 1, PacketLoss: 100, DNSErr: true, Err: "no such host"},
+	}
+
+	return &Widget{
+		hosts: hosts,
+		states: []hostState{
+			{since: now.Add(-3 * time.Hour)},
+			{since: now.Add(-90 * time.Second)},
+			{since: now.Add(-2 * 24 * time.Hour)},
+			{},
+		},
+		settings: &Settings{showLatency: true},
+	}
+}
+
+func Test_content_compact(t *testing.T) {
+	widget := layoutTestWidget(t)
+
+	assert.Equal(t,
+		"[white]router           : [green]Up     2ms\n"+
+			"[white]api.example.com  : [green]Up   112ms [yellow]25% loss\n"+
+			"[white][nas]            : [red]DOWN       [yellow]100% loss\n"+
+			"[white]gone.example     : [red]DNS ERR",
+		widget.content(),
+	)
+}
+
+func Test_content_detailed(t *testing.T) {
+	widget := layoutTestWidget(t)
+	widget.detailed = true
+
+	// Columns are as wide as their widest cell, and numbers are right-aligned
+	assert.Equal(t,
+		"[gray]Host             Status    Min    Avg   Max  Loss  Changed    Address\n"+
+			"[white]router           [green]Up     [white]  <1ms    2ms   4ms    0%  3h ago     192.168.1.1\n"+
+			"[white]api.example.com  [green]Up     [white]  90ms  112ms  1.5s   25%  1m30s ago  203.0.113.7\n"+
+			"[white][nas[]            [red]DOWN   [white]                     100%  2d ago     192.168.1.20\n"+
+			"[white]gone.example     [red]DNS ERR[white]",
+		widget.content(),
+	)
+}
+
+func Test_NewSettingsFromYAML_DefaultView(t *testing.T) {
+	assert.Equal(t, viewCompact, newTestSettings(t, "enabled: true").defaultView)
+	assert.Equal(t, viewDetailed, newTestSettings(t, "defaultView: detailed").defaultView)
+}
+
+func Test_toggleView(t *testing.T) {
+	widget := newPoolTestWidget(1, &Settings{common: &cfg.Common{}, defaultView: viewDetailed})
+	assert.Assert(t, widget.detailed)
+
+	widget.toggleView()
+	<-widget.RedrawChan
+	assert.Assert(t, !widget.detailed)
+}
@@ -0,0 +1,96 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// dnsEntry is a cached lookup of a host's address
+type dnsEntry struct {
+	ip      net.IP
+	took    time.Duration // how long the lookup took
+	expires time.Time
+}
+
+// dnsCache keeps the addresses hosts resolved to, so they aren't looked up on every check.
+// It is shared by the workers checking hosts
+type dnsCache struct {
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+}
+
+func newDNSCache() *dnsCache {
+	return &dnsCache{entries: make(map[string]dnsEntry)}
+}
+
+// resolvedIPs caches the addresses of every host checked
+var resolvedIPs = newDNSCache()
+
+// get returns the cached lookup for key, if it hasn't expired by now
+func (cache *dnsCache) get(key string, now time.Time) (dnsEntry, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		delete(cache.entries, key)
+		return dnsEntry{}, false
+	}
+
+	return entry, true
+}
+
+func (cache *dnsCache) put(key string, entry dnsEntry) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.entries[key] = entry
+}
+
+// resolve looks up the host's address over its IP version, along with how long the lookup
+// took. Addresses are cached for the host's DNSCacheTTL, and failed lookups aren't cached
+func resolve(ctx context.Context, host Host) (net.IP, time.Duration, error) {
+	key := host.network() + " " + host.target()
+	if host.DNSCacheTTL > 0 {
+		if entry, ok := resolvedIPs.get(key, nowFunc()); ok {
+			return entry.ip, entry.took, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, host.Timeout)
+	defer cancel()
+
+	start := nowFunc()
+	ips, err := lookupIP(ctx, host.network(), host.target())
+	took := nowFunc().Sub(start)
+	if err != nil {
+		return nil, took, err
+	}
+	if len(ips) == 0 {
+		return nil, took, &net.DNSError{Err: "no addresses found", Name: host.target(), IsNotFound: true}
+	}
+
+	if host.DNSCacheTTL > 0 {
+		resolvedIPs.put(key, dnsEntry{ip: ips[0], took: took, expires: nowFunc().Add(host.DNSCacheTTL)})
+	}
+
+	return ips[0], took, nil
+}
+
+// dnsColumn renders how long looking up the host's address took, in yellow when longer
+// than dnsWarn. Blank until the host has been looked up
+func (widget *Widget) dnsColumn(host Host, width int) string {
+	if host.Addr == "" && !host.DNSErr {
+		return fmt.Sprintf("    %*s", width, "")
+	}
+
+	color := "gray"
+	if widget.settings.dnsWarn > 0 && host.DNSTime > widget.settings.dnsWarn {
+		color = "yellow"
+	}
+
+	return fmt.Sprintf("[%s]dns %*s", color, width, formatLatency(host.DNSTime))
+}
@@ -0,0 +1,113 @@
+package ping
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+// stubDNS fakes a resolver whose lookups take lookupTime on a fake clock, returning the
+// clock and the lookups made
+func stubDNS(t *testing.T, lookupTime time.Duration) (*time.Time, *[]string) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	lookups := []string{}
+
+	originalNow, originalCache := nowFunc, resolvedIPs
+	t.Cleanup(func() { nowFunc, resolvedIPs = originalNow, originalCache })
+	nowFunc = func() time.Time { return now }
+	resolvedIPs = newDNSCache()
+
+	stubLookupIP(t, func(ctx context.Context, network, host string) ([]net.IP, error) {
+		lookups = append(lookups, network+" "+host)
+		now = now.Add(lookupTime)
+		if host == "missing.example.com" {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		return []net.IP{net.ParseIP("192.0.2.10")}, nil
+	})
+
+	return &now, &lookups
+}
+
+func Test_resolve_Timing(t *testing.T) {
+	stubDNS(t, 180*time.Millisecond)
+
+	ip, took, err := resolve(context.Background(), Host{Hostname: "db.example.com", Timeout: time.Second})
+	assert.NilError(t, err)
+	assert.Equal(t, "192.0.2.10", ip.String())
+	assert.Equal(t, 180*time.Millisecond, took)
+
+	_, took, err = resolve(context.Background(), Host{Hostname: "missing.example.com", Timeout: time.Second})
+	assert.ErrorContains(t, err, "no such host")
+	assert.Equal(t, 180*time.Millisecond, took)
+}
+
+func Test_check_DNSTime(t *testing.T) {
+	stubDNS(t, 250*time.Millisecond)
+
+	result := check(context.Background(), Host{Hostname: "missing.example.com", Timeout: time.Second})
+	assert.Assert(t, result.DNSErr)
+	assert.Equal(t, 250*time.Millisecond, result.DNSTime)
+}
+
+func Test_resolve_Cache(t *testing.T) {
+	now, lookups := stubDNS(t, 20*time.Millisecond)
+	host := Host{Hostname: "db.example.com", Timeout: time.Second, DNSCacheTTL: time.Minute}
+
+	_, took, err := resolve(context.Background(), host)
+	assert.NilError(t, err)
+	assert.Equal(t, 20*time.Millisecond, took)
+
+	// Cached lookups report how long the lookup they reuse took
+	*now = now.Add(59 * time.Second)
+	_, took, err = resolve(context.Background(), host)
+	assert.NilError(t, err)
+	assert.Equal(t, 20*time.Millisecond, took)
+	assert.Equal(t, 1, len(*lookups))
+
+	// Each IP version is cached on its own
+	v6 := host
+	v6.IPVersion = ipVersion6
+	_, _, err = resolve(context.Background(), v6)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(*lookups))
+
+	// Expired
+	*now = now.Add(time.Second)
+	_, _, err = resolve(context.Background(), host)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"ip db.example.com", "ip6 db.example.com", "ip db.example.com"}, *lookups)
+
+	// Failed lookups aren't cached
+	missing := Host{Hostname: "missing.example.com", Timeout: time.Second, DNSCacheTTL: time.Minute}
+	_, _, _ = resolve(context.Background(), missing)
+	_, _, _ = resolve(context.Background(), missing)
+	assert.Equal(t, 5, len(*lookups))
+
+	// Without a TTL, hosts are looked up every time
+	host.DNSCacheTTL = 0
+	_, _, _ = resolve(context.Background(), host)
+	_, _, _ = resolve(context.Background(), host)
+	assert.Equal(t, 7, len(*lookups))
+}
+
+func Test_content_DNS(t *testing.T) {
+	hosts := []Host{
+		{Label: "router", Up: true, AvgRtt: 2 * time.Millisecond, Addr: "192.168.1.1", DNSTime: 300 * time.Microsecond},
+		{Label: "slowdns", Up: true, AvgRtt: 20 * time.Millisecond, Addr: "192.0.2.10", DNSTime: 480 * time.Millisecond},
+		{Label: "missing", Up: false, PacketLoss: 100, DNSErr: true, Err: "no such host", DNSTime: 15 * time.Millisecond},
+		{Label: "new", Up: false},
+	}
+
+	widget := &Widget{hosts: hosts, settings: &Settings{showLatency: true, showDNS: true, dnsWarn: 100 * time.Millisecond}}
+	assert.Equal(t,
+		"[white]router      : [green]Up    2ms [gray]dns  <1ms\n"+
+			"[white]slowdns     : [green]Up   20ms [yellow]dns 480ms\n"+
+			"[white]missing     : [red]DNS ERR [gray]dns  15ms\n"+
+			"[white]new         : [red]DOWN",
+		widget.content(),
+	)
+}
@@ -0,0 +1,167 @@
+package ping
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// exportedStatus is the document written to exportFile and served on exportListen
+type exportedStatus struct {
+	Updated time.Time      `json:"updated"`
+	Hosts   []exportedHost `json:"hosts"`
+}
+
+type exportedHost struct {
+	Label       string     `json:"label"`
+	Hostname    string     `json:"hostname"`
+	Group       string     `json:"group,omitempty"`
+	State       string     `json:"state"` // up, down or slow
+	RttMs       float64    `json:"rttMs"`
+	LossPercent float64    `json:"lossPercent"`
+	Error       string     `json:"error,omitempty"`
+	LastChange  *time.Time `json:"lastChange,omitempty"` // when the host entered its state
+}
+
+// exporter keeps the last exported status, for the HTTP handler to serve
+type exporter struct {
+	mu       sync.RWMutex
+	document []byte
+}
+
+func (exp *exporter) set(document []byte) {
+	exp.mu.Lock()
+	defer exp.mu.Unlock()
+
+	exp.document = document
+}
+
+// ServeHTTP serves the last exported status
+func (exp *exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	exp.mu.RLock()
+	document := exp.document
+	exp.mu.RUnlock()
+
+	if document == nil {
+		http.Error(w, "no hosts checked yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(document)
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// exportStatus describes the state of every host, as of the last refresh
+func (widget *Widget) exportStatus() exportedStatus {
+	status := exportedStatus{Updated: nowFunc(), Hosts: []exportedHost{}}
+
+	for idx, host := range widget.hosts {
+		exported := exportedHost{
+			Label:       host.Label,
+			Hostname:    host.Hostname,
+			Group:       host.Group,
+			State:       stateName(host),
+			LossPercent: host.PacketLoss,
+			Error:       host.Err,
+		}
+		if host.TimedOut {
+			exported.Error = timedOutErr
+		}
+		if host.Up {
+			exported.RttMs = float64(host.AvgRtt) / float64(time.Millisecond)
+		}
+		if idx < len(widget.states) && !widget.states[idx].since.IsZero() {
+			since := widget.states[idx].since
+			exported.LastChange = &since
+		}
+
+		status.Hosts = append(status.Hosts, exported)
+	}
+
+	return status
+}
+
+// export writes the state of every host to exportFile, and hands it to the exportListen
+// server, when they are set
+func (widget *Widget) export() {
+	if widget.settings.exportFile == "" && widget.exporter == nil {
+		return
+	}
+
+	document, err := json.MarshalIndent(widget.exportStatus(), "", "  ")
+	if err != nil {
+		widget.exportErr = fmt.Errorf("could not export: %w", err)
+		return
+	}
+
+	if widget.exporter != nil {
+		widget.exporter.set(document)
+	}
+
+	widget.exportErr = nil
+	if widget.settings.exportFile != "" {
+		if err := writeFileAtomic(widget.settings.exportFile, document); err != nil {
+			widget.exportErr = fmt.Errorf("could not export: %w", err)
+		}
+	}
+}
+
+// listenForExport starts serving the exported status on exportListen
+func (widget *Widget) listenForExport() {
+	if widget.settings.exportListen == "" {
+		return
+	}
+
+	listener, err := net.Listen("tcp", widget.settings.exportListen)
+	if err != nil {
+		widget.exportErr = fmt.Errorf("could not serve the export: %w", err)
+		return
+	}
+
+	widget.exporter = &exporter{}
+	widget.exportServer = &http.Server{Handler: widget.exporter, ReadHeaderTimeout: 5 * time.Second}
+
+	server := widget.exportServer
+	go func() { _ = server.Serve(listener) }()
+}
+
+// stopExport closes the exportListen server, if it was started, releasing its address
+func (widget *Widget) stopExport() {
+	if widget.exportServer == nil {
+		return
+	}
+
+	_ = widget.exportServer.Close()
+	widget.exportServer = nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path, then renames it over path,
+// so readers never see a partly written file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
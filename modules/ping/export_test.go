@@ -0,0 +1,146 @@
+package ping
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+	"gotest.tools/assert"
+)
+
+func newExportTestWidget(t *testing.T, exportFile string) *Widget {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	originalNow := nowFunc
+	t.Cleanup(func() { nowFunc = originalNow })
+	nowFunc = func() time.Time { return now }
+
+	widget := &Widget{
+		hosts: []Host{
+			{Label: "Router", Hostname: "192.168.1.1", Up: true, AvgRtt: 1500 * time.Microsecond, LossThresholdPercent: 100},
+			{Label: "NAS", Hostname: "nas.local", Group: "Home", Up: false, PacketLoss: 100, Err: "timeout", LossThresholdPercent: 100},
+		},
+		settings: &Settings{exportFile: exportFile},
+	}
+	widget.trackTransitions()
+
+	now = now.Add(time.Minute)
+	return widget
+}
+
+func Test_exportStatus(t *testing.T) {
+	widget := newExportTestWidget(t, "")
+
+	document, err := json.Marshal(widget.exportStatus())
+	assert.NilError(t, err)
+
+	assert.Equal(t, `{"updated":"2024-05-01T12:01:00Z","hosts":[`+
+		`{"label":"Router","hostname":"192.168.1.1","state":"up","rttMs":1.5,"lossPercent":0,"lastChange":"2024-05-01T12:00:00Z"},`+
+		`{"label":"NAS","hostname":"nas.local","group":"Home","state":"down","rttMs":0,"lossPercent":100,"error":"timeout","lastChange":"2024-05-01T12:00:00Z"}`+
+		`]}`, string(document))
+
+	// Hosts that weren't checked yet have no last change
+	widget.states = nil
+	status := widget.exportStatus()
+	assert.Assert(t, status.Hosts[0].LastChange == nil)
+}
+
+func Test_export_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ping.json")
+	widget := newExportTestWidget(t, path)
+
+	assert.NilError(t, os.WriteFile(path, []byte("old"), 0644))
+	reader, err := os.Open(path)
+	assert.NilError(t, err)
+	defer func() { _ = reader.Close() }()
+
+	widget.export()
+	assert.NilError(t, widget.exportErr)
+
+	var status exportedStatus
+	fileData, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.NilError(t, json.Unmarshal(fileData, &status))
+	assert.Equal(t, 2, len(status.Hosts))
+	assert.Equal(t, "down", status.Hosts[1].State)
+
+	// The file is replaced rather than rewritten, so a reader that opened it before keeps
+	// seeing the whole of the old one, and no temporary file is left behind
+	old, err := io.ReadAll(reader)
+	assert.NilError(t, err)
+	assert.Equal(t, "old", string(old))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(entries))
+}
+
+func Test_export_FileError(t *testing.T) {
+	widget := newExportTestWidget(t, filepath.Join(t.TempDir(), "missing", "ping.json"))
+
+	widget.export()
+	assert.ErrorContains(t, widget.exportErr, "could not export")
+}
+
+func Test_exporter(t *testing.T) {
+	widget := newExportTestWidget(t, "")
+	widget.exporter = &exporter{}
+
+	recorder := httptest.NewRecorder()
+	widget.exporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	widget.export()
+
+	recorder = httptest.NewRecorder()
+	widget.exporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var status exportedStatus
+	assert.NilError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+	assert.Equal(t, "Router", status.Hosts[0].Label)
+	assert.Equal(t, 1.5, status.Hosts[0].RttMs)
+}
+
+func Test_listenForExport_Restart(t *testing.T) {
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(closedPort(t)))
+	settings := newTestSettings(t, "exportListen: "+addr+"\n")
+
+	get := func() int {
+		t.Helper()
+
+		resp, err := http.Get("http://" + addr + "/")
+		assert.NilError(t, err)
+		_ = resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	widget := NewWidget(tview.NewApplication(), make(chan bool, 1), nil, settings)
+	assert.NilError(t, widget.exportErr)
+	assert.Equal(t, http.StatusServiceUnavailable, get())
+
+	// Stopping the widget, as the app does when the config changes, releases the address
+	go func() { <-widget.QuitChan() }()
+	widget.Stop()
+	assert.Assert(t, widget.exportServer == nil)
+
+	_, err := http.Get("http://" + addr + "/")
+	assert.Assert(t, err != nil)
+
+	// The widget that replaces it serves its own export on the same address
+	replacement := NewWidget(tview.NewApplication(), make(chan bool, 1), nil, settings)
+	t.Cleanup(replacement.stopExport)
+	assert.NilError(t, replacement.exportErr)
+
+	replacement.export()
+	assert.Equal(t, http.StatusOK, get())
+}
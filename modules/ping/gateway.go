@@ -0,0 +1,202 @@
+package ping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// discoveryInterval is how long the gateway and DNS servers found are kept before they
+	// are looked for again
+	discoveryInterval = 5 * time.Minute
+	// discoveryTimeout is how long looking for the gateway may take
+	discoveryTimeout = 5 * time.Second
+	// maxDNSHosts is how many of the system's DNS servers are checked
+	maxDNSHosts = 2
+
+	gatewayLabel = "Gateway"
+)
+
+// resolvConfPath is where the system's DNS servers are listed. It is replaceable in tests
+var resolvConfPath = "/etc/resolv.conf"
+
+// discoverFunc looks for the gateway and the DNS servers. It is replaceable in tests
+var discoverFunc = discover
+
+// discovery is what looking for the gateway and the DNS servers found
+type discovery struct {
+	gateway    string
+	gatewayErr error
+	dns        []string
+	dnsErr     error
+	at         time.Time
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// discover looks for the default gateway and the system's DNS servers
+func discover(now time.Time) discovery {
+	found := discovery{at: now}
+	found.gateway, found.gatewayErr = discoverGateway()
+	found.dns, found.dnsErr = discoverDNS()
+
+	return found
+}
+
+// discoverGateway returns the address of the default gateway, from "ip route" on Linux and
+// "route -n get default" on macOS and the BSDs
+func discoverGateway() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+
+	if runtime.GOOS == "linux" {
+		out, err := runCommandOutput(ctx, "ip", []string{"route", "show", "default"})
+		if err != nil {
+			return "", fmt.Errorf("ip route: %w", err)
+		}
+		return parseIPRoute(out)
+	}
+
+	out, err := runCommandOutput(ctx, "route", []string{"-n", "get", "default"})
+	if err != nil {
+		return "", fmt.Errorf("route: %w", err)
+	}
+	return parseRouteGet(out)
+}
+
+// parseIPRoute reads the gateway of the first default route "ip route" lists, in lines
+// such as "default via 192.168.1.1 dev wlan0 proto dhcp metric 600"
+func parseIPRoute(out string) (string, error) {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != "default" {
+			continue
+		}
+
+		for idx := 1; idx < len(fields)-1; idx++ {
+			if fields[idx] == "via" && net.ParseIP(fields[idx+1]) != nil {
+				return fields[idx+1], nil
+			}
+		}
+	}
+
+	return "", errors.New("no default route")
+}
+
+// parseRouteGet reads the gateway "route -n get default" shows, in a line such as
+// "    gateway: 192.168.1.1"
+func parseRouteGet(out string) (string, error) {
+	for _, line := range strings.Split(out, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found || key != "gateway" {
+			continue
+		}
+
+		if gateway := strings.TrimSpace(value); net.ParseIP(gateway) != nil {
+			return gateway, nil
+		}
+	}
+
+	return "", errors.New("no default route")
+}
+
+// discoverDNS returns the first DNS servers listed in the resolv.conf of the system
+func discoverDNS() ([]string, error) {
+	data, err := os.ReadFile(resolvConfPath)
+	if err != nil {
+		return nil, err
+	}
+
+	servers := parseResolvConf(string(data))
+	if len(servers) == 0 {
+		return nil, errors.New("no nameservers")
+	}
+
+	return servers, nil
+}
+
+// parseResolvConf reads the nameserver lines of a resolv.conf, such as
+// "nameserver 1.1.1.1", keeping the first maxDNSHosts servers
+func parseResolvConf(text string) []string {
+	servers := []string{}
+
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+
+		// Link-local servers end with their zone, as in fe80::1%eth0
+		server, _, _ := strings.Cut(fields[1], "%")
+		if net.ParseIP(server) == nil {
+			continue
+		}
+
+		servers = append(servers, fields[1])
+		if len(servers) == maxDNSHosts {
+			break
+		}
+	}
+
+	return servers
+}
+
+// implicitHosts returns the hosts found, the gateway labeled Gateway and the DNS servers
+// labeled DNS 1 and DNS 2
+func implicitHosts(found discovery, defaults Host) []Host {
+	rawHosts := []interface{}{}
+	if found.gateway != "" {
+		rawHosts = append(rawHosts, map[string]interface{}{"hostname": found.gateway, "label": gatewayLabel})
+	}
+	for idx, server := range found.dns {
+		rawHosts = append(rawHosts, map[string]interface{}{"hostname": server, "label": fmt.Sprintf("DNS %d", idx+1)})
+	}
+
+	return parseHosts(rawHosts, "", defaults)
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// discoverHosts looks for the gateway and the DNS servers, when monitorGateway is on and
+// they weren't looked for in the last discoveryInterval, and lists them first
+func (widget *Widget) discoverHosts() {
+	if !widget.settings.monitorGateway {
+		return
+	}
+
+	now := nowFunc()
+	if !widget.discovered.at.IsZero() && now.Sub(widget.discovered.at) < discoveryInterval {
+		return
+	}
+
+	widget.discovered = discoverFunc(now)
+	widget.implicitHosts = implicitHosts(widget.discovered, widget.settings.hostDefaults)
+	widget.mergeHosts()
+}
+
+// discoveryLine says what couldn't be found of the gateway and the DNS servers, or returns
+// "" when they were
+func (widget *Widget) discoveryLine() string {
+	if !widget.settings.monitorGateway || widget.discovered.at.IsZero() {
+		return ""
+	}
+
+	unknown := []string{}
+	if widget.discovered.gatewayErr != nil {
+		unknown = append(unknown, "gateway: unknown")
+	}
+	if widget.discovered.dnsErr != nil {
+		unknown = append(unknown, "DNS: unknown")
+	}
+	if len(unknown) == 0 {
+		return ""
+	}
+
+	return "[gray]" + strings.Join(unknown, " · ")
+}
@@ -0,0 +1,200 @@
+package ping
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func Test_parseIPRoute(t *testing.T) {
+	gateway, err := parseIPRoute(`default via 192.168.1.1 dev wlp2s0 proto dhcp src 192.168.1.42 metric 600
+default via 10.8.0.1 dev tun0 metric 700
+`)
+	assert.NilError(t, err)
+	assert.Equal(t, "192.168.1.1", gateway)
+
+	gateway, err = parseIPRoute("default via fe80::1 dev eth0 proto ra metric 1024 pref medium\n")
+	assert.NilError(t, err)
+	assert.Equal(t, "fe80::1", gateway)
+
+	// A point-to-point default route has no gateway
+	_, err = parseIPRoute("default dev wg0 scope link\n")
+	assert.ErrorContains(t, err, "no default route")
+
+	_, err = parseIPRoute("")
+	assert.ErrorContains(t, err, "no default route")
+}
+
+func Test_parseRouteGet(t *testing.T) {
+	gateway, err := parseRouteGet(`   route to: default
+destination: default
+       mask: default
+    gateway: 192.168.0.254
+  interface: en0
+      flags: <UP,GATEWAY,DONE,STATIC,PRCLONING,GLOBAL>
+`)
+	assert.NilError(t, err)
+	assert.Equal(t, "192.168.0.254", gateway)
+
+	_, err = parseRouteGet("route: writing to routing socket: not in table\n")
+	assert.ErrorContains(t, err, "no default route")
+}
+
+func Test_parseResolvConf(t *testing.T) {
+	servers := parseResolvConf(`# Generated by NetworkManager
+search lan
+nameserver 192.168.1.1
+nameserver   fe80::1%wlp2s0
+; nameserver 9.9.9.9
+nameserver 1.1.1.1
+`)
+	assert.DeepEqual(t, []string{"192.168.1.1", "fe80::1%wlp2s0"}, servers)
+
+	assert.DeepEqual(t, []string{"8.8.8.8"}, parseResolvConf("nameserver dns.example\nnameserver 8.8.8.8\n"))
+	assert.DeepEqual(t, []string{}, parseResolvConf("search lan\n"))
+}
+
+func Test_discover(t *testing.T) {
+	originalRun, originalPath := runCommandOutput, resolvConfPath
+	t.Cleanup(func() { runCommandOutput, resolvConfPath = originalRun, originalPath })
+
+	runCommandOutput = func(_ context.Context, name string, _ []string) (string, error) {
+		if runtime.GOOS == "linux" {
+			assert.Equal(t, "ip", name)
+			return "default via 192.168.1.1 dev eth0\n", nil
+		}
+		assert.Equal(t, "route", name)
+		return "    gateway: 192.168.1.1\n", nil
+	}
+	resolvConfPath = filepath.Join(t.TempDir(), "resolv.conf")
+	assert.NilError(t, os.WriteFile(resolvConfPath, []byte("nameserver 1.1.1.1\n"), 0o600))
+
+	now := time.Now()
+	found := discover(now)
+	assert.Equal(t, "192.168.1.1", found.gateway)
+	assert.NilError(t, found.gatewayErr)
+	assert.DeepEqual(t, []string{"1.1.1.1"}, found.dns)
+	assert.NilError(t, found.dnsErr)
+	assert.Equal(t, now, found.at)
+
+	runCommandOutput = func(_ context.Context, _ string, _ []string) (string, error) {
+		return "", errors.New("executable file not found in $PATH")
+	}
+	assert.NilError(t, os.Remove(resolvConfPath))
+
+	found = discover(now)
+	assert.ErrorContains(t, found.gatewayErr, "executable file not found")
+	assert.Assert(t, found.dnsErr != nil)
+}
+
+func stubDiscover(t *testing.T, found *discovery) *int {
+	originalDiscover, originalNow := discoverFunc, nowFunc
+	t.Cleanup(func() { discoverFunc, nowFunc = originalDiscover, originalNow })
+
+	calls := 0
+	discoverFunc = func(now time.Time) discovery {
+		calls++
+		result := *found
+		result.at = now
+		return result
+	}
+
+	return &calls
+}
+
+func Test_discoverHosts_Merge(t *testing.T) {
+	found := &discovery{gateway: "192.168.1.1", dns: []string{"192.168.1.1", "1.1.1.1"}}
+	stubDiscover(t, found)
+
+	path := filepath.Join(t.TempDir(), "hosts.txt")
+	writeHostsFile(t, path, "1.1.1.1,Cloudflare\n", time.Now())
+
+	settings := newTestSettings(t, "monitorGateway: true\nhosts:\n  - hostname: example.com\n")
+	settings.hostsFile = path
+
+	widget := &Widget{settings: settings, hosts: settings.hosts}
+	widget.reloadHostsFile()
+	widget.discoverHosts()
+
+	// The configured hosts win over the DNS servers with the same hostname
+	assert.DeepEqual(t, []string{
+		"Gateway=192.168.1.1",
+		"example.com=example.com",
+		"Cloudflare=1.1.1.1",
+	}, hostLabels(widget.hosts))
+	assert.Equal(t, settings.count, widget.hosts[0].Count)
+	assert.Equal(t, settings.timeout, widget.hosts[0].Timeout)
+
+	// Once the hosts file drops a host, the DNS server shows again
+	writeHostsFile(t, path, "nas.local,NAS\n", time.Now().Add(time.Minute))
+	widget.reloadHostsFile()
+	assert.DeepEqual(t, []string{
+		"Gateway=192.168.1.1",
+		"DNS 2=1.1.1.1",
+		"example.com=example.com",
+		"NAS=nas.local",
+	}, hostLabels(widget.hosts))
+}
+
+func Test_discoverHosts_Interval(t *testing.T) {
+	found := &discovery{gateway: "192.168.1.1"}
+	calls := stubDiscover(t, found)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	nowFunc = func() time.Time { return now }
+
+	settings := newTestSettings(t, "monitorGateway: true\n")
+	widget := &Widget{settings: settings}
+	widget.discoverHosts()
+	widget.hosts[0].Up = true
+
+	// The gateway found is kept until discoveryInterval is up
+	found.gateway = "10.0.0.1"
+	now = now.Add(discoveryInterval - time.Second)
+	widget.discoverHosts()
+	assert.Equal(t, 1, *calls)
+	assert.DeepEqual(t, []string{"Gateway=192.168.1.1"}, hostLabels(widget.hosts))
+	assert.Equal(t, true, widget.hosts[0].Up)
+
+	now = now.Add(time.Second)
+	widget.discoverHosts()
+	assert.Equal(t, 2, *calls)
+	assert.DeepEqual(t, []string{"Gateway=10.0.0.1"}, hostLabels(widget.hosts))
+	assert.Equal(t, false, widget.hosts[0].Up)
+}
+
+func Test_discoverHosts_Off(t *testing.T) {
+	calls := stubDiscover(t, &discovery{gateway: "192.168.1.1"})
+
+	settings := newTestSettings(t, "hosts:\n  - hostname: example.com\n")
+	widget := &Widget{settings: settings, hosts: settings.hosts}
+	widget.discoverHosts()
+
+	assert.Equal(t, 0, *calls)
+	assert.DeepEqual(t, []string{"example.com=example.com"}, hostLabels(widget.hosts))
+	assert.Equal(t, "", widget.discoveryLine())
+}
+
+func Test_content_discoveryUnknown(t *testing.T) {
+	found := &discovery{gatewayErr: errors.New("no default route"), dns: []string{"1.1.1.1"}}
+	stubDiscover(t, found)
+
+	settings := newTestSettings(t, "monitorGateway: true\nshowLatency: false\n")
+	widget := &Widget{settings: settings}
+	widget.discoverHosts()
+
+	assert.Equal(t, "[gray]gateway: unknown\n[white]DNS 1       : [red]DOWN", widget.content())
+
+	found.dnsErr, found.dns = errors.New("no nameservers"), nil
+	widget.discovered.at = time.Time{}
+	widget.discoverHosts()
+
+	assert.Equal(t, "[gray]gateway: unknown · DNS: unknown", widget.content())
+	assert.Equal(t, 0, len(widget.hosts))
+}
@@ -0,0 +1,184 @@
+package ping
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/olebedev/config"
+)
+
+// loadHostsFile reads the hosts listed in a hosts file, along with how many of its entries
+// were malformed and skipped. The file is either a YAML list of hosts, just like the hosts
+// setting, or a host per line, as hostname or hostname,label. Blank lines and lines
+// starting with # are ignored
+func loadHostsFile(path string, defaults Host) ([]Host, int, error) {
+	fileData, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not read hostsFile: %w", err)
+	}
+
+	rawHosts, ok := yamlHosts(fileData)
+	if !ok {
+		var malformed int
+		rawHosts, malformed = lineHosts(string(fileData))
+		hosts := parseHosts(rawHosts, "", defaults)
+		return hosts, malformed + len(rawHosts) - len(hosts), nil
+	}
+
+	hosts := parseHosts(rawHosts, "", defaults)
+	return hosts, len(rawHosts) - len(hosts), nil
+}
+
+// yamlHosts returns the hosts of a hosts file written as a YAML list
+func yamlHosts(fileData []byte) ([]interface{}, bool) {
+	ymlConfig, err := config.ParseYaml(string(fileData))
+	if err != nil {
+		return nil, false
+	}
+
+	rawHosts, ok := ymlConfig.Root.([]interface{})
+	return rawHosts, ok
+}
+
+// lineHosts returns the hosts of a hosts file written a host per line, in the same shape
+// as those of a YAML list, along with how many lines were malformed
+func lineHosts(text string) ([]interface{}, int) {
+	rawHosts := []interface{}{}
+	malformed := 0
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, ",")
+		hostname := strings.TrimSpace(fields[0])
+		if len(fields) > 2 || hostname == "" || strings.ContainsAny(hostname, " \t") {
+			malformed++
+			continue
+		}
+
+		host := map[string]interface{}{"hostname": hostname}
+		if len(fields) == 2 && strings.TrimSpace(fields[1]) != "" {
+			host["label"] = strings.TrimSpace(fields[1])
+		}
+
+		rawHosts = append(rawHosts, host)
+	}
+
+	return rawHosts, malformed
+}
+
+// reloadHostsFile re-reads the hosts file when it has changed since it was last read, and
+// lists its hosts after those of the settings. If the file can't be read, the hosts it
+// listed last are kept
+func (widget *Widget) reloadHostsFile() {
+	path := widget.settings.hostsFile
+	if path == "" {
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		widget.hostsFileErr = fmt.Errorf("could not read hostsFile: %w", err)
+		return
+	}
+	if info.ModTime().Equal(widget.hostsFileModTime) {
+		return
+	}
+
+	fileHosts, malformed, err := loadHostsFile(path, widget.settings.hostDefaults)
+	if err != nil {
+		widget.hostsFileErr = err
+		return
+	}
+
+	widget.hostsFileModTime = info.ModTime()
+	widget.hostsFileErr = nil
+	widget.malformedHosts = malformed
+	widget.fileHosts = fileHosts
+	widget.mergeHosts()
+}
+
+// mergeHosts lists the gateway and DNS servers found, then the hosts of the settings, then
+// those of the hosts file. The gateway and DNS servers aren't listed again when a host of
+// the settings or of the hosts file has the same hostname
+func (widget *Widget) mergeHosts() {
+	hosts := append(append([]Host{}, widget.settings.hosts...), widget.fileHosts...)
+
+	configured := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		configured[host.Hostname] = true
+	}
+
+	implicit := []Host{}
+	for _, host := range widget.implicitHosts {
+		if !configured[host.Hostname] {
+			configured[host.Hostname] = true
+			implicit = append(implicit, host)
+		}
+	}
+
+	widget.setHosts(append(implicit, hosts...))
+}
+
+// setHosts replaces the hosts to check. Hosts that were already listed keep their last
+// result and their history of state changes
+func (widget *Widget) setHosts(hosts []Host) {
+	previous := make(map[string]int, len(widget.hosts))
+	for idx, host := range widget.hosts {
+		previous[host.key()] = idx
+	}
+
+	states := make([]hostState, len(hosts))
+	for idx := range hosts {
+		old, ok := previous[hosts[idx].key()]
+		if !ok {
+			continue
+		}
+
+		hosts[idx].keepResult(widget.hosts[old])
+		if old < len(widget.states) {
+			states[idx] = widget.states[old]
+		}
+	}
+
+	widget.hosts = hosts
+	widget.states = states
+}
+
+// key identifies a host across reloads of the hosts file
+func (host Host) key() string {
+	return host.Label + "\x00" + host.Hostname
+}
+
+// keepResult copies the result of the last check of other, the same host before a reload
+func (host *Host) keepResult(other Host) {
+	host.Up = other.Up
+	host.MinRtt = other.MinRtt
+	host.AvgRtt = other.AvgRtt
+	host.MaxRtt = other.MaxRtt
+	host.PacketLoss = other.PacketLoss
+	host.Err = other.Err
+	host.Addr = other.Addr
+	host.DNSErr = other.DNSErr
+	host.Attempts = other.Attempts
+	host.DNSTime = other.DNSTime
+	host.CheckedAt = other.CheckedAt
+	host.TimedOut = other.TimedOut
+}
+
+// malformedFooter notes how many entries of the hosts file were skipped
+func (widget *Widget) malformedFooter() string {
+	switch widget.malformedHosts {
+	case 0:
+		return ""
+	case 1:
+		return "[gray]1 malformed entry skipped in hostsFile"
+	default:
+		return fmt.Sprintf("[gray]%d malformed entries skipped in hostsFile", widget.malformedHosts)
+	}
+}
@@ -0,0 +1,135 @@
+package ping
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func writeHostsFile(t *testing.T, path, text string, modTime time.Time) {
+	t.Helper()
+
+	assert.NilError(t, os.WriteFile(path, []byte(text), 0o600))
+	assert.NilError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func hostLabels(hosts []Host) []string {
+	labels := []string{}
+	for _, host := range hosts {
+		labels = append(labels, host.Label+"="+host.Hostname)
+	}
+
+	return labels
+}
+
+func Test_loadHostsFile_Lines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.txt")
+	writeHostsFile(t, path, `
+# generated by ansible
+192.168.1.1,Router
+db.example.com
+  nas.local , NAS
+
+bad host,Bad
+,Missing hostname
+a,b,c
+`, time.Now())
+
+	hosts, malformed, err := loadHostsFile(path, Host{Count: 3, Timeout: time.Second})
+	assert.NilError(t, err)
+	assert.Equal(t, 3, malformed)
+	assert.DeepEqual(t, []string{"Router=192.168.1.1", "db.example.com=db.example.com", "NAS=nas.local"}, hostLabels(hosts))
+	assert.Equal(t, 3, hosts[0].Count)
+	assert.Equal(t, time.Second, hosts[0].Timeout)
+	assert.Equal(t, checkICMP, hosts[0].Type)
+}
+
+func Test_loadHostsFile_YAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.yml")
+	writeHostsFile(t, path, `
+- hostname: 192.168.1.1
+  label: Router
+- hostname: api.example.com
+  type: http
+  expectStatus: 204
+- label: No hostname
+`, time.Now())
+
+	hosts, malformed, err := loadHostsFile(path, Host{Count: 1})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, malformed)
+	assert.DeepEqual(t, []string{"Router=192.168.1.1", "api.example.com=api.example.com"}, hostLabels(hosts))
+	assert.Equal(t, checkHTTP, hosts[1].Type)
+	assert.Equal(t, 204, hosts[1].ExpectStatus)
+}
+
+func Test_loadHostsFile_Missing(t *testing.T) {
+	_, _, err := loadHostsFile(filepath.Join(t.TempDir(), "missing.txt"), Host{})
+	assert.ErrorContains(t, err, "could not read hostsFile")
+}
+
+func newHostsFileTestWidget(t *testing.T, path string) *Widget {
+	settings := newTestSettings(t, "hosts:\n  - hostname: 10.0.0.1\n    label: Gateway\n")
+	settings.hostsFile = path
+
+	widget := &Widget{settings: settings, hosts: settings.hosts}
+	widget.reloadHostsFile()
+
+	return widget
+}
+
+func Test_reloadHostsFile_Merge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.txt")
+	writeHostsFile(t, path, "192.168.1.1,Router\nbad host\n", time.Now())
+
+	widget := newHostsFileTestWidget(t, path)
+	assert.DeepEqual(t, []string{"Gateway=10.0.0.1", "Router=192.168.1.1"}, hostLabels(widget.hosts))
+	assert.Equal(t, "[gray]1 malformed entry skipped in hostsFile", widget.malformedFooter())
+	assert.DeepEqual(t, []string{"Gateway=10.0.0.1"}, hostLabels(widget.settings.hosts))
+}
+
+func Test_reloadHostsFile_KeepsHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.txt")
+	start := time.Now().Add(-time.Hour)
+	writeHostsFile(t, path, "192.168.1.1,Router\nnas.local,NAS\n", start)
+
+	widget := newHostsFileTestWidget(t, path)
+	for idx := range widget.hosts {
+		widget.hosts[idx].Up = true
+		widget.hosts[idx].AvgRtt = time.Duration(idx+1) * time.Millisecond
+	}
+	widget.states = []hostState{{checked: true}, {checked: true, streak: 4}, {checked: true, streak: 7}}
+
+	// Unchanged files aren't read again
+	writeHostsFile(t, path, "192.168.1.1,Router\n", start)
+	widget.reloadHostsFile()
+	assert.Equal(t, 3, len(widget.hosts))
+
+	writeHostsFile(t, path, "printer.local,Printer\nnas.local,NAS\n", start.Add(time.Minute))
+	widget.reloadHostsFile()
+
+	assert.DeepEqual(t, []string{"Gateway=10.0.0.1", "Printer=printer.local", "NAS=nas.local"}, hostLabels(widget.hosts))
+	assert.Equal(t, 1*time.Millisecond, widget.hosts[0].AvgRtt)
+	assert.Equal(t, false, widget.hosts[1].Up)
+	assert.Equal(t, time.Duration(0), widget.hosts[1].AvgRtt)
+	assert.Equal(t, true, widget.hosts[2].Up)
+	assert.Equal(t, 3*time.Millisecond, widget.hosts[2].AvgRtt)
+	assert.DeepEqual(t, []int{0, 0, 7}, []int{widget.states[0].streak, widget.states[1].streak, widget.states[2].streak})
+	assert.Equal(t, "", widget.malformedFooter())
+}
+
+func Test_reloadHostsFile_Unreadable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.txt")
+	writeHostsFile(t, path, "192.168.1.1,Router\n", time.Now())
+
+	widget := newHostsFileTestWidget(t, path)
+	assert.NilError(t, os.Remove(path))
+	widget.reloadHostsFile()
+
+	// The hosts listed last are kept, with a warning
+	assert.DeepEqual(t, []string{"Gateway=10.0.0.1", "Router=192.168.1.1"}, hostLabels(widget.hosts))
+	assert.ErrorContains(t, widget.hostsFileErr, "could not read hostsFile")
+}
@@ -0,0 +1,20 @@
+package ping
+
+import "github.com/gdamore/tcell/v2"
+
+func (widget *Widget) initializeKeyboardControls() {
+	widget.InitializeHelpTextKeyboardControl(widget.ShowHelp)
+	widget.InitializeErrorsKeyboardControl(widget.ShowLastErrors)
+
+	widget.SetKeyboardChar("j", widget.next, "Select next host")
+	widget.SetKeyboardChar("k", widget.prev, "Select previous host")
+	widget.SetKeyboardChar("r", widget.recheck, "Check the selected host again, or every host if none is selected")
+	widget.SetKeyboardChar("t", widget.traceroute, "Trace the route to the selected host")
+	widget.SetKeyboardChar("s", widget.ssh, "Run sshCommand for the selected host")
+	widget.SetKeyboardChar("d", widget.toggleView, "Switch between the compact and detailed layouts")
+	widget.SetKeyboardChar("U", widget.resetUptime, "Reset the uptime of the selected host, or of every host if none is selected")
+
+	widget.SetKeyboardKey(tcell.KeyDown, widget.next, "Select next host")
+	widget.SetKeyboardKey(tcell.KeyUp, widget.prev, "Select previous host")
+	widget.SetKeyboardKey(tcell.KeyEsc, widget.escape, "Stop the running command, and clear the selection")
+}
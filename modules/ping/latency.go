@@ -0,0 +1,35 @@
+package ping
+
+// How a host's round-trip time compares to its warnLatency and critLatency
+const (
+	latencyOK = iota
+	latencyWarn
+	latencyCrit
+)
+
+// latencyLevel returns how the round-trip time of a host that is up compares to its
+// thresholds. A threshold is only exceeded by a longer round-trip time, and 0 turns it off
+func (host Host) latencyLevel() int {
+	switch {
+	case !host.Up:
+		return latencyOK
+	case host.CritLatency > 0 && host.AvgRtt > host.CritLatency:
+		return latencyCrit
+	case host.WarnLatency > 0 && host.AvgRtt > host.WarnLatency:
+		return latencyWarn
+	default:
+		return latencyOK
+	}
+}
+
+// latencyColor is the color to show a round-trip time at the given level in
+func latencyColor(level int) string {
+	switch level {
+	case latencyCrit:
+		return "red"
+	case latencyWarn:
+		return "yellow"
+	default:
+		return "green"
+	}
+}
@@ -0,0 +1,121 @@
+package ping
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func Test_Host_latencyLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     Host
+		expected int
+	}{
+		{"below warn", Host{Up: true, AvgRtt: 99 * time.Millisecond}, latencyOK},
+		{"at warn", Host{Up: true, AvgRtt: 100 * time.Millisecond}, latencyOK},
+		{"above warn", Host{Up: true, AvgRtt: 101 * time.Millisecond}, latencyWarn},
+		{"at crit", Host{Up: true, AvgRtt: 500 * time.Millisecond}, latencyWarn},
+		{"above crit", Host{Up: true, AvgRtt: 501 * time.Millisecond}, latencyCrit},
+		{"down", Host{Up: false, AvgRtt: time.Second}, latencyOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.host.WarnLatency = 100 * time.Millisecond
+			tt.host.CritLatency = 500 * time.Millisecond
+			assert.Equal(t, tt.expected, tt.host.latencyLevel())
+		})
+	}
+
+	t.Run("thresholds off", func(t *testing.T) {
+		assert.Equal(t, latencyOK, Host{Up: true, AvgRtt: time.Hour}.latencyLevel())
+	})
+
+	t.Run("crit only", func(t *testing.T) {
+		host := Host{Up: true, AvgRtt: 200 * time.Millisecond, CritLatency: 100 * time.Millisecond}
+		assert.Equal(t, latencyCrit, host.latencyLevel())
+	})
+}
+
+func Test_buildhosts_LatencyThresholds(t *testing.T) {
+	settings := newTestSettings(t, `
+warnLatency: 100ms
+critLatency: 1
+hosts:
+  - hostname: router
+  - hostname: far.example.com
+    warnLatency: 300ms
+    critLatency: 2s
+`)
+
+	assert.Equal(t, 100*time.Millisecond, settings.hosts[0].WarnLatency)
+	assert.Equal(t, time.Second, settings.hosts[0].CritLatency)
+	assert.Equal(t, 300*time.Millisecond, settings.hosts[1].WarnLatency)
+	assert.Equal(t, 2*time.Second, settings.hosts[1].CritLatency)
+
+	settings = newTestSettings(t, "hosts:\n  - hostname: router\n")
+	assert.Equal(t, time.Duration(0), settings.hosts[0].WarnLatency)
+	assert.Equal(t, time.Duration(0), settings.hosts[0].CritLatency)
+}
+
+func Test_content_latencyThresholds(t *testing.T) {
+	thresholds := func(host Host) Host {
+		host.WarnLatency = 100 * time.Millisecond
+		host.CritLatency = 500 * time.Millisecond
+		return host
+	}
+
+	hosts := []Host{
+		thresholds(Host{Label: "router", Up: true, AvgRtt: 2 * time.Millisecond}),
+		thresholds(Host{Label: "vpn", Up: true, AvgRtt: 250 * time.Millisecond}),
+		thresholds(Host{Label: "satellite", Up: true, AvgRtt: 700 * time.Millisecond}),
+		thresholds(Host{Label: "offline", Up: false}),
+	}
+
+	widget := &Widget{hosts: hosts, settings: &Settings{showLatency: true}}
+	assert.Equal(t,
+		"[white]router      : [green]Up     2ms\n"+
+			"[white]vpn         : [green]Up   [yellow]250ms\n"+
+			"[white]satellite   : [green]Up   [red]700ms\n"+
+			"[white]offline     : [red]DOWN",
+		widget.content(),
+	)
+
+	widget.settings.showSlow = true
+	assert.Equal(t,
+		"[white]router      : [green]Up     2ms\n"+
+			"[white]vpn         : [yellow]SLOW [yellow]250ms\n"+
+			"[white]satellite   : [red]SLOW [red]700ms\n"+
+			"[white]offline     : [red]DOWN",
+		widget.content(),
+	)
+
+	widget.settings.showLatency = false
+	assert.Equal(t,
+		"[white]router      : [green]Up\n"+
+			"[white]vpn         : [yellow]SLOW\n"+
+			"[white]satellite   : [red]SLOW\n"+
+			"[white]offline     : [red]DOWN",
+		widget.content(),
+	)
+}
+
+func Test_trackTransitions_Slow(t *testing.T) {
+	calls := stubStateChanges(t)
+
+	widget := newTransitionTestWidget(1)
+	widget.hosts[0].Up = true
+	widget.hosts[0].WarnLatency = 100 * time.Millisecond
+	widget.hosts[0].CritLatency = 500 * time.Millisecond
+
+	for _, rtt := range []time.Duration{10, 200, 800, 900, 10} {
+		widget.hosts[0].AvgRtt = rtt * time.Millisecond
+		widget.trackTransitions()
+	}
+
+	// Going over warnLatency only changes the color, going over critLatency changes the state
+	assert.DeepEqual(t, []string{"slow", "up"}, callStates(*calls))
+	assert.Equal(t, "WTF_PING_STATE=up", (*calls)[1].Env[2])
+}
@@ -0,0 +1,106 @@
+package ping
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	sortByConfig  = "config"
+	sortByStatus  = "status"
+	sortByLatency = "latency"
+	sortByLabel   = "label"
+)
+
+// section is a group of hosts, listed under its name
+type section struct {
+	name  string
+	hosts []int // indices into the widget's hosts, in display order
+}
+
+// sections returns the hosts grouped in the order their groups first appear in the
+// config, the ungrouped ones first, each group sorted by sortBy. Hosts that compare equal
+// keep their config order, so the order is the same on every refresh
+func (widget *Widget) sections() []section {
+	sections := []section{}
+	byName := make(map[string]int)
+
+	for idx, host := range widget.hosts {
+		pos, ok := byName[host.Group]
+		if !ok {
+			pos = len(sections)
+			byName[host.Group] = pos
+			sections = append(sections, section{name: host.Group})
+		}
+
+		sections[pos].hosts = append(sections[pos].hosts, idx)
+	}
+
+	// Ungrouped hosts are listed before any group
+	sort.SliceStable(sections, func(i, j int) bool {
+		return sections[i].name == "" && sections[j].name != ""
+	})
+
+	for _, sec := range sections {
+		widget.sortHosts(sec.hosts)
+	}
+
+	return sections
+}
+
+// sortHosts orders host indices according to the sortBy setting
+func (widget *Widget) sortHosts(indices []int) {
+	less := widget.hostLess()
+	if less == nil {
+		return
+	}
+
+	sort.SliceStable(indices, func(i, j int) bool {
+		return less(widget.hosts[indices[i]], widget.hosts[indices[j]])
+	})
+}
+
+// hostLess returns how sortBy compares hosts, or nil to keep the config order
+func (widget *Widget) hostLess() func(a, b Host) bool {
+	switch widget.settings.sortBy {
+	case sortByStatus:
+		return func(a, b Host) bool {
+			return statusRank(a) < statusRank(b)
+		}
+	case sortByLatency:
+		return func(a, b Host) bool {
+			if statusRank(a) != statusRank(b) {
+				return statusRank(a) < statusRank(b)
+			}
+			return a.AvgRtt > b.AvgRtt
+		}
+	case sortByLabel:
+		return func(a, b Host) bool {
+			return strings.ToLower(a.Label) < strings.ToLower(b.Label)
+		}
+	default:
+		return nil
+	}
+}
+
+// statusRank is where a host's state sorts: down, then degraded, then up
+func statusRank(host Host) int {
+	switch {
+	case !host.Up:
+		return 0
+	case host.isDegraded():
+		return 1
+	default:
+		return 2
+	}
+}
+
+// upSummary sums up the hosts that are up (ex: "42 hosts up")
+func upSummary(count int) string {
+	if count == 1 {
+		return "[green]1 host up"
+	}
+
+	return fmt.Sprintf("[green]%d hosts up", count)
+}
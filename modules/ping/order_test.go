@@ -0,0 +1,133 @@
+package ping
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func orderTestHosts() []Host {
+	return []Host{
+		{Label: "web", Up: true, AvgRtt: 40 * time.Millisecond},
+		{Label: "DB", Up: false},
+		{Label: "cache", Up: true, AvgRtt: 90 * time.Millisecond},
+		{Label: "api", Up: false},
+		{Label: "Backup", Up: true, AvgRtt: 40 * time.Millisecond},
+	}
+}
+
+func sectionLabels(widget *Widget) [][]string {
+	labels := [][]string{}
+	for _, sec := range widget.sections() {
+		names := []string{sec.name}
+		for _, idx := range sec.hosts {
+			names = append(names, widget.hosts[idx].Label)
+		}
+		labels = append(labels, names)
+	}
+
+	return labels
+}
+
+func Test_sections_SortBy(t *testing.T) {
+	tests := []struct {
+		sortBy   string
+		expected []string
+	}{
+		{sortBy: "config", expected: []string{"", "web", "DB", "cache", "api", "Backup"}},
+		{sortBy: "", expected: []string{"", "web", "DB", "cache", "api", "Backup"}},
+		{sortBy: "status", expected: []string{"", "DB", "api", "web", "cache", "Backup"}},
+		{sortBy: "latency", expected: []string{"", "DB", "api", "cache", "web", "Backup"}},
+		{sortBy: "label", expected: []string{"", "api", "Backup", "cache", "DB", "web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			widget := &Widget{hosts: orderTestHosts(), settings: &Settings{sortBy: tt.sortBy}}
+
+			assert.DeepEqual(t, [][]string{tt.expected}, sectionLabels(widget))
+			// Sorting only changes the display order
+			assert.DeepEqual(t, orderTestHosts(), widget.hosts)
+		})
+	}
+}
+
+func Test_sections_Degraded(t *testing.T) {
+	hosts := append(orderTestHosts(), Host{Label: "lossy", Up: true, AvgRtt: 10 * time.Millisecond, PacketLoss: 40, DegradedLossPercent: 1})
+
+	widget := &Widget{hosts: hosts, settings: &Settings{sortBy: sortByStatus}}
+	assert.DeepEqual(t, [][]string{{"", "DB", "api", "lossy", "web", "cache", "Backup"}}, sectionLabels(widget))
+
+	widget.settings.sortBy = sortByLatency
+	assert.DeepEqual(t, [][]string{{"", "DB", "api", "lossy", "cache", "web", "Backup"}}, sectionLabels(widget))
+}
+
+func Test_sections_Groups(t *testing.T) {
+	settings := newTestSettings(t, `
+sortBy: label
+hosts:
+  - hostname: laptop
+groups:
+  - name: Servers
+    hosts:
+      - hostname: web
+      - hostname: api
+  - name: Network
+    hosts:
+      - hostname: switch
+      - hostname: router
+`)
+
+	widget := &Widget{hosts: settings.hosts, settings: settings}
+
+	assert.DeepEqual(t, [][]string{
+		{"", "laptop"},
+		{"Servers", "api", "web"},
+		{"Network", "router", "switch"},
+	}, sectionLabels(widget))
+
+	assert.Equal(t,
+		"[white]laptop      : [red]DOWN\n"+
+			"[yellow]Servers\n"+
+			"[white]api         : [red]DOWN\n"+
+			"[white]web         : [red]DOWN\n"+
+			"[yellow]Network\n"+
+			"[white]router      : [red]DOWN\n"+
+			"[white]switch      : [red]DOWN",
+		widget.content(),
+	)
+}
+
+func Test_content_HideUp(t *testing.T) {
+	hosts := orderTestHosts()
+	hosts[4].Group = "Storage"
+	hosts = append(hosts, Host{Label: "nas", Group: "Storage", Up: true, AvgRtt: time.Millisecond})
+
+	widget := &Widget{hosts: hosts, settings: &Settings{sortBy: "status", hideUp: true}}
+
+	assert.Equal(t,
+		"[white]DB          : [red]DOWN\n"+
+			"[white]api         : [red]DOWN\n"+
+			"[green]2 hosts up\n"+
+			"[yellow]Storage\n"+
+			"[green]2 hosts up",
+		widget.content(),
+	)
+
+	widget.hosts[0].Up = false
+	widget.hosts[2].Up = false
+	assert.Equal(t,
+		"[white]web         : [red]DOWN\n"+
+			"[white]DB          : [red]DOWN\n"+
+			"[white]cache       : [red]DOWN\n"+
+			"[white]api         : [red]DOWN\n"+
+			"[yellow]Storage\n"+
+			"[green]2 hosts up",
+		widget.content(),
+	)
+
+	widget.hosts[5].Up = false
+	assert.Assert(t, strings.HasSuffix(widget.content(), "[yellow]Storage\n[white]nas         : [red]DOWN\n[green]1 host up"), widget.content())
+}
@@ -2,40 +2,221 @@ package ping
 
 import (
 	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
 
 	"github.com/olebedev/config"
 	"github.com/wtfutil/wtf/cfg"
+	"github.com/wtfutil/wtf/utils"
 )
 
 const (
-	defaultFocusable = false
-	defaultTitle     = "Pings"
+	defaultFocusable            = true
+	defaultTitle                = "Pings"
+	defaultCount                = 1
+	defaultTimeout              = "10s"
+	defaultLossThresholdPercent = 100.0
+	defaultDegradedLossPercent  = 1.0
+	defaultMaxConcurrent        = 10
+	defaultRetries              = 2
+	defaultDNSCacheTTL          = "5m"
+	defaultTracerouteCommand    = "traceroute {hostname}"
+	defaultSSHCommand           = "ssh {hostname}"
+	defaultRetryDelay           = "500ms"
+	defaultUptimeWindow         = 100
+	defaultUptimeMaxAge         = "168h"
+	defaultUptimeWarn           = 99.0
+	defaultUptimeCrit           = 95.0
+
+	ipVersion4    = "4"
+	ipVersion6    = "6"
+	ipVersionAuto = "auto"
 )
 
 type Host struct {
-	Label    string `help:"Label: The name to use for the host you want to ping. Uses hostname if blank."`
-	Hostname string `help:"Hostname: IP address or hostname to ping"`
-	Up       bool   // not meant to be set by user
+	Label                string        `help:"Label: The name to use for the host you want to ping. Uses hostname if blank."`
+	Hostname             string        `help:"Hostname: IP address or hostname to ping"`
+	Count                int           `help:"Count: The number of packets to send. Overrides the module's count." optional:"true"`
+	Timeout              time.Duration `help:"Timeout: How long to wait for the replies. Overrides the module's timeout." optional:"true"`
+	LossThresholdPercent float64       `help:"LossThresholdPercent: Overrides the module's lossThresholdPercent." optional:"true"`
+	DegradedLossPercent  float64       `help:"DegradedLossPercent: Overrides the module's degradedLossPercent." optional:"true"`
+	Type                 string        `help:"Type: How to check the host: icmp to ping it, tcp to connect to its port, or http to get its url." values:"icmp, tcp or http" optional:"true" default:"icmp"`
+	Port                 int           `help:"Port: The port to connect to, for tcp checks." optional:"true"`
+	URL                  string        `help:"URL: The URL to get, for http checks." optional:"true" default:"http://<hostname>" key:"url"`
+	ExpectStatus         int           `help:"ExpectStatus: The HTTP status the url must answer with, for http checks." optional:"true" default:"200"`
+	WarnLatency          time.Duration `help:"WarnLatency: Overrides the module's warnLatency." optional:"true"`
+	CritLatency          time.Duration `help:"CritLatency: Overrides the module's critLatency." optional:"true"`
+	Interval             time.Duration `help:"Interval: How often to check the host, if less often than every refresh. Hosts are checked at most once per refresh." values:"A number of seconds or a duration such as 5m" optional:"true"`
+	IPVersion            string        `help:"IPVersion: The IP version to resolve and check the host over. List a host twice to check it over both." values:"4, 6 or auto" optional:"true" default:"auto" key:"ipVersion"`
+	Group                string        // set by listing the host in a group
+	DNSCacheTTL          time.Duration // set from the module's dnsCacheTTL
+
+	Up         bool          // not meant to be set by user
+	MinRtt     time.Duration // not meant to be set by user
+	AvgRtt     time.Duration // not meant to be set by user
+	MaxRtt     time.Duration // not meant to be set by user
+	PacketLoss float64       // not meant to be set by user
+	Err        string        // not meant to be set by user
+	Addr       string        // not meant to be set by user
+	DNSErr     bool          // not meant to be set by user
+	Attempts   int           // not meant to be set by user
+	DNSTime    time.Duration // not meant to be set by user
+	CheckedAt  time.Time     // not meant to be set by user
+	TimedOut   bool          // not meant to be set by user
+}
+
+// applyResult records the result of checking the host
+func (host *Host) applyResult(result checkResult) {
+	host.Up = host.isUp(result.PacketLoss)
+	host.MinRtt = result.MinRtt
+	host.AvgRtt = result.AvgRtt
+	host.MaxRtt = result.MaxRtt
+	host.PacketLoss = result.PacketLoss
+	host.Err = result.Err
+	host.Addr = result.Addr
+	host.DNSErr = result.DNSErr
+	host.Attempts = result.Attempts
+	host.DNSTime = result.DNSTime
+	host.TimedOut = false
 }
 
 type Settings struct {
-	common *cfg.Common
-	hosts  []Host
+	common       *cfg.Common
+	hosts        []Host
+	hostDefaults Host // what hosts that don't set their count, timeout and thresholds use
+
+	count                int           `help:"The number of packets to send to each host." optional:"true" default:"1"`
+	timeout              time.Duration `help:"How long to wait for the replies from each host." values:"A number of seconds or a duration such as 2s" optional:"true" default:"10s"`
+	lossThresholdPercent float64       `help:"Hosts that lose at least this percentage of the packets sent are shown as down." optional:"true" default:"100"`
+	degradedLossPercent  float64       `help:"Hosts that lose at least this percentage of the packets sent, but less than lossThresholdPercent, are shown as degraded. 0 turns this off." optional:"true" default:"1"`
+	showLatency          bool          `help:"Whether or not to show the round-trip time of hosts that are up." values:"true or false" optional:"true" default:"true"`
+	warnLatency          time.Duration `help:"Round-trip times longer than this are shown in yellow. 0 turns this off." values:"A number of seconds or a duration such as 100ms" optional:"true" default:"0"`
+	critLatency          time.Duration `help:"Round-trip times longer than this are shown in red, and the host's state is slow for onStateChange. 0 turns this off." values:"A number of seconds or a duration such as 500ms" optional:"true" default:"0"`
+	showSlow             bool          `help:"Whether to show SLOW instead of Up for hosts slower than warnLatency or critLatency." values:"true or false" optional:"true" default:"false"`
+	retries              int           `help:"The number of times to check a host that is down again, retryDelay apart, before showing it as down. Retries stop when they wouldn't be done by the next refresh." optional:"true" default:"2"`
+	retryDelay           time.Duration `help:"How long to wait before checking a host that is down again." values:"A number of seconds or a duration such as 500ms" optional:"true" default:"500ms"`
+	showRetries          bool          `help:"Whether to show which try found a host up, such as (2nd try), when it took more than one." values:"true or false" optional:"true" default:"false"`
+	onStateChange        string        `help:"Command to run when a host changes state. The host's label, hostname, new state (up, degraded, down, or slow when slower than critLatency) and consecutive failures are passed as the last four arguments, and as the WTF_PING_LABEL, WTF_PING_HOSTNAME, WTF_PING_STATE and WTF_PING_FAILURES environment variables." optional:"true"`
+	flapDampening        int           `help:"The number of checks in a row a host must be in its new state before onStateChange runs." optional:"true" default:"1"`
+	maxConcurrent        int           `help:"The maximum number of hosts to check at the same time." optional:"true" default:"10"`
+	jitter               time.Duration `help:"The maximum random delay before checking each host, to spread the checks out." values:"A number of seconds or a duration such as 500ms" optional:"true" default:"0"`
+	sortBy               string        `help:"The order to list hosts in, within each group." values:"config, status (down, then degraded, then up), latency (down, then degraded, then slowest first) or label" optional:"true" default:"config"`
+	showDNS              bool          `help:"Whether to show how long looking up each host's address took." values:"true or false" optional:"true" default:"false"`
+	dnsWarn              time.Duration `help:"Lookups longer than this are shown in yellow. 0 turns this off." values:"A number of seconds or a duration such as 100ms" optional:"true" default:"0"`
+	dnsCacheTTL          time.Duration `help:"How long to reuse the address a host resolved to before looking it up again. 0 looks hosts up on every check." values:"A number of seconds or a duration such as 5m" optional:"true" default:"5m"`
+	showResolvedIP       bool          `help:"Whether to show the address each host resolved to." values:"true or false" optional:"true" default:"false"`
+	hostsFile            string        `help:"File to read more hosts from, re-read whenever it changes: a YAML list of hosts like the hosts setting, or a host per line as hostname or hostname,label. Relative paths are relative to the WTF config directory." optional:"true"`
+	showUptime           bool          `help:"Whether to show the percentage of each host's last checks that found it up." values:"true or false" optional:"true" default:"false"`
+	uptimeWindow         int           `help:"The number of checks of each host the uptime is computed over." optional:"true" default:"100"`
+	uptimeMaxAge         time.Duration `help:"Checks older than this don't count towards the uptime. 0 keeps every check in the window." values:"A number of seconds or a duration such as 24h" optional:"true" default:"168h"`
+	uptimeWarn           float64       `help:"Uptimes below this percentage are shown in yellow." optional:"true" default:"99"`
+	uptimeCrit           float64       `help:"Uptimes below this percentage are shown in red." optional:"true" default:"95"`
+	uptimePath           string        `help:"File the checks the uptime is computed from are saved to, so they survive restarts. Relative paths are relative to the WTF config directory." optional:"true" default:"<module name>-uptime.json" key:"uptimeFile"`
+	tracerouteCommand    string        `help:"Command to trace the route to the selected host with. {hostname}, {label} and {addr}, the address the host resolved to, are replaced by the host's." optional:"true" default:"traceroute {hostname}"`
+	sshCommand           string        `help:"Command to run in the terminal for the selected host. {hostname}, {label} and {addr} are replaced by the host's." optional:"true" default:"ssh {hostname}"`
+	showSummary          bool          `help:"Whether to sum up how many hosts are up, down or couldn't be checked, and which is the slowest, on the first line." values:"true or false" optional:"true" default:"false"`
+	showDownInTitle      bool          `help:"Whether to add how many hosts are down to the title, such as Pings (2 down)." values:"true or false" optional:"true" default:"false"`
+	exportFile           string        `help:"File to write the state of every host to as JSON after each refresh, for other tools to read. Relative paths are relative to the WTF config directory." optional:"true"`
+	exportListen         string        `help:"Address to serve the same JSON on over HTTP, such as 127.0.0.1:9123." optional:"true"`
+	defaultView          string        `help:"The layout to start in: compact, a line per host, or detailed, a table of each host's round-trip times, packet loss, last change and address. d switches between them." values:"compact or detailed" optional:"true" default:"compact"`
+	hideUp               bool          `help:"Whether to sum the hosts that are up, and not degraded, in a single line, such as 42 hosts up, listing only the others." values:"true or false" optional:"true" default:"false"`
+	monitorGateway       bool          `help:"Whether to also check the default gateway and the first two DNS servers of the system, listed first as Gateway, DNS 1 and DNS 2. They are looked for again every 5 minutes." values:"true or false" optional:"true" default:"false"`
 }
 
 func NewSettingsFromYAML(name string, ymlConfig *config.Config, globalConfig *config.Config) *Settings {
 	settings := Settings{
 		common: cfg.NewCommonSettingsFromModule(name, defaultTitle, defaultFocusable, ymlConfig, globalConfig),
-		hosts:  buildhosts(ymlConfig),
+
+		count:                ymlConfig.UInt("count", defaultCount),
+		timeout:              cfg.ParseTimeString(ymlConfig, "timeout", defaultTimeout),
+		lossThresholdPercent: ymlConfig.UFloat64("lossThresholdPercent", defaultLossThresholdPercent),
+		degradedLossPercent:  ymlConfig.UFloat64("degradedLossPercent", defaultDegradedLossPercent),
+		showLatency:          ymlConfig.UBool("showLatency", true),
+		warnLatency:          cfg.ParseTimeString(ymlConfig, "warnLatency", "0s"),
+		critLatency:          cfg.ParseTimeString(ymlConfig, "critLatency", "0s"),
+		showSlow:             ymlConfig.UBool("showSlow", false),
+		retries:              ymlConfig.UInt("retries", defaultRetries),
+		retryDelay:           cfg.ParseTimeString(ymlConfig, "retryDelay", defaultRetryDelay),
+		showRetries:          ymlConfig.UBool("showRetries", false),
+		onStateChange:        ymlConfig.UString("onStateChange", ""),
+		flapDampening:        ymlConfig.UInt("flapDampening", 1),
+		maxConcurrent:        ymlConfig.UInt("maxConcurrent", defaultMaxConcurrent),
+		jitter:               cfg.ParseTimeString(ymlConfig, "jitter", "0s"),
+		sortBy:               ymlConfig.UString("sortBy", sortByConfig),
+		hideUp:               ymlConfig.UBool("hideUp", false),
+		monitorGateway:       ymlConfig.UBool("monitorGateway", false),
+		defaultView:          ymlConfig.UString("defaultView", viewCompact),
+		showSummary:          ymlConfig.UBool("showSummary", false),
+		exportFile:           configFilePath(ymlConfig.UString("exportFile", "")),
+		exportListen:         ymlConfig.UString("exportListen", ""),
+		showDownInTitle:      ymlConfig.UBool("showDownInTitle", false),
+		showResolvedIP:       ymlConfig.UBool("showResolvedIP", false),
+		showDNS:              ymlConfig.UBool("showDNS", false),
+		dnsWarn:              cfg.ParseTimeString(ymlConfig, "dnsWarn", "0s"),
+		dnsCacheTTL:          cfg.ParseTimeString(ymlConfig, "dnsCacheTTL", defaultDNSCacheTTL),
+		hostsFile:            configFilePath(ymlConfig.UString("hostsFile", "")),
+		tracerouteCommand:    ymlConfig.UString("tracerouteCommand", defaultTracerouteCommand),
+		sshCommand:           ymlConfig.UString("sshCommand", defaultSSHCommand),
+		showUptime:           ymlConfig.UBool("showUptime", false),
+		uptimeWindow:         ymlConfig.UInt("uptimeWindow", defaultUptimeWindow),
+		uptimeMaxAge:         cfg.ParseTimeString(ymlConfig, "uptimeMaxAge", defaultUptimeMaxAge),
+		uptimeWarn:           ymlConfig.UFloat64("uptimeWarn", defaultUptimeWarn),
+		uptimeCrit:           ymlConfig.UFloat64("uptimeCrit", defaultUptimeCrit),
+		uptimePath:           configFilePath(ymlConfig.UString("uptimeFile", name+"-uptime.json")),
+	}
+	settings.hostDefaults = Host{
+		Count:                settings.count,
+		Timeout:              settings.timeout,
+		LossThresholdPercent: settings.lossThresholdPercent,
+		DegradedLossPercent:  settings.degradedLossPercent,
+		WarnLatency:          settings.warnLatency,
+		CritLatency:          settings.critLatency,
+		DNSCacheTTL:          settings.dnsCacheTTL,
 	}
+	settings.hosts = buildhosts(ymlConfig, settings.hostDefaults)
+
+	schema := cfg.NewSchema(Settings{})
+	schema.Block("hosts", Host{})
+	schema.Block("groups").Keys("name").Block("hosts", Host{})
+	settings.common.CheckKeys(schema)
 
 	return &settings
 }
 
-func buildhosts(ymlConfig *config.Config) []Host {
+// buildhosts reads the hosts to ping: those in the hosts list, then those of each group:
+//
+//	groups:
+//	  - name: Network
+//	    hosts:
+//	      - hostname: 192.168.1.1
+//
+// Hosts that don't set their count, timeout, loss threshold or latency thresholds take them
+// from defaults
+func buildhosts(ymlConfig *config.Config, defaults Host) []Host {
+	hosts := parseHosts(ymlConfig.UList("hosts"), "", defaults)
+
+	for _, rawGroup := range ymlConfig.UList("groups") {
+		group, ok := rawGroup.(map[string]interface{})
+		if !ok {
+			continue // bad group, skip
+		}
 
+		name := ""
+		if value, ok := group["name"]; ok && value != nil {
+			name = fmt.Sprintf("%v", value)
+		}
+
+		rawHosts, _ := group["hosts"].([]interface{})
+		hosts = append(hosts, parseHosts(rawHosts, name, defaults)...)
+	}
+
+	return hosts
+}
+
+// parseHosts reads a list of hosts, all in the named group
+func parseHosts(yaml []interface{}, group string, defaults Host) []Host {
 	hosts := []Host{}
-	yaml := ymlConfig.UList("hosts")
 
 	// Iterate through each host in the config
 	for _, rawHost := range yaml {
@@ -54,7 +235,13 @@ func buildhosts(ymlConfig *config.Config) []Host {
 			continue // hostname is required, skip
 		}
 
+		version := parseIPVersion(host["ipVersion"])
+
 		label := hostname // a default if missing from config
+		if version != ipVersionAuto {
+			// Tells apart the same host listed for both IP versions
+			label = fmt.Sprintf("%s (v%s)", hostname, version)
+		}
 		if value, ok := host["label"]; ok {
 			// Using Sprintf here instead of a string assert. This is to cover the
 			// case where someone puts a number as the label instead of a YAML string.
@@ -62,7 +249,120 @@ func buildhosts(ymlConfig *config.Config) []Host {
 			label = fmt.Sprintf("%v", value)
 		}
 
-		hosts = append(hosts, Host{Label: label, Hostname: hostname, Up: false})
+		hosts = append(hosts, Host{
+			Label:                label,
+			Hostname:             hostname,
+			Count:                hostInt(host["count"], defaults.Count),
+			Timeout:              hostDuration(host["timeout"], defaults.Timeout),
+			LossThresholdPercent: hostFloat(host["lossThresholdPercent"], defaults.LossThresholdPercent),
+			DegradedLossPercent:  hostFloat(host["degradedLossPercent"], defaults.DegradedLossPercent),
+			Type:                 hostString(host["type"], checkICMP),
+			Port:                 hostInt(host["port"], 0),
+			URL:                  hostString(host["url"], "http://"+hostname),
+			ExpectStatus:         hostInt(host["expectStatus"], http.StatusOK),
+			WarnLatency:          hostDuration(host["warnLatency"], defaults.WarnLatency),
+			CritLatency:          hostDuration(host["critLatency"], defaults.CritLatency),
+			Interval:             hostDuration(host["interval"], 0),
+			IPVersion:            version,
+			DNSCacheTTL:          defaults.DNSCacheTTL,
+			Group:                group,
+			Up:                   false,
+		})
 	}
 	return hosts
 }
+
+// parseIPVersion reads a host's ipVersion, given as 4, 6 or auto. Anything else is auto
+func parseIPVersion(value interface{}) string {
+	switch version := fmt.Sprintf("%v", value); version {
+	case ipVersion4, ipVersion6:
+		return version
+	default:
+		return ipVersionAuto
+	}
+}
+
+// hostString returns a host's string setting, or fallback if the host doesn't set it
+func hostString(value interface{}, fallback string) string {
+	if s, ok := value.(string); ok && s != "" {
+		return s
+	}
+
+	return fallback
+}
+
+// hostInt returns a host's integer setting, or the module's if the host doesn't set it
+func hostInt(value interface{}, fallback int) int {
+	if i, ok := value.(int); ok && i > 0 {
+		return i
+	}
+
+	return fallback
+}
+
+// hostFloat returns a host's numeric setting, or the module's if the host doesn't set it
+func hostFloat(value interface{}, fallback float64) float64 {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	default:
+		return fallback
+	}
+}
+
+// hostDuration returns a host's duration setting, given as a number of seconds or a
+// duration such as 500ms, or the module's if the host doesn't set it
+func hostDuration(value interface{}, fallback time.Duration) time.Duration {
+	switch v := value.(type) {
+	case int:
+		return time.Duration(v) * time.Second
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+
+	return fallback
+}
+
+// isUp tells whether a host that lost lossPercent of its packets is up, which it is unless
+// it lost at least its threshold
+func (host Host) isUp(lossPercent float64) bool {
+	return lossPercent < host.LossThresholdPercent
+}
+
+// isDegraded returns true if the host is up but loses at least DegradedLossPercent of the
+// packets sent. 0 turns this off
+func (host Host) isDegraded() bool {
+	return host.Up && host.DegradedLossPercent > 0 && host.PacketLoss >= host.DegradedLossPercent
+}
+
+// isDue returns true if the host should be checked in the refresh starting at now: hosts
+// are checked on every refresh, unless they have an interval that hasn't elapsed yet
+func (host Host) isDue(now time.Time) bool {
+	return host.Interval <= 0 || host.CheckedAt.IsZero() || now.Sub(host.CheckedAt) >= host.Interval
+}
+
+// configFilePath resolves a file setting. Relative paths are relative to the WTF config
+// directory
+func configFilePath(fileName string) string {
+	if fileName == "" {
+		return ""
+	}
+
+	if expanded, err := utils.ExpandHomeDir(fileName); err == nil {
+		fileName = expanded
+	}
+	if filepath.IsAbs(fileName) {
+		return fileName
+	}
+
+	configDir, err := cfg.WtfConfigDir()
+	if err != nil {
+		return fileName
+	}
+
+	return filepath.Join(configDir, fileName)
+}
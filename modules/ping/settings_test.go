@@ -0,0 +1,269 @@
+package ping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/olebedev/config"
+	"github.com/wtfutil/wtf/cfg"
+	"gotest.tools/assert"
+)
+
+func newTestSettings(t *testing.T, yaml string) *Settings {
+	t.Helper()
+
+	ymlConfig, err := config.ParseYaml(yaml)
+	assert.NilError(t, err)
+
+	globalConfig, err := config.ParseYaml("global: {}")
+	assert.NilError(t, err)
+
+	return NewSettingsFromYAML("ping", ymlConfig, globalConfig)
+}
+
+func Test_buildhosts_Defaults(t *testing.T) {
+	settings := newTestSettings(t, `
+hosts:
+  - hostname: example.com
+`)
+
+	assert.DeepEqual(t, []Host{{
+		Label:                "example.com",
+		Hostname:             "example.com",
+		Count:                1,
+		Timeout:              10 * time.Second,
+		LossThresholdPercent: 100,
+		DegradedLossPercent:  1,
+		Type:                 "icmp",
+		URL:                  "http://example.com",
+		ExpectStatus:         200,
+		IPVersion:            "auto",
+		DNSCacheTTL:          5 * time.Minute,
+	}}, settings.hosts)
+}
+
+func Test_buildhosts_Overrides(t *testing.T) {
+	settings := newTestSettings(t, `
+count: 4
+timeout: 5
+lossThresholdPercent: 50
+hosts:
+  - hostname: router
+  - hostname: flaky.example.com
+    label: Flaky
+    count: 10
+    timeout: 2500ms
+    lossThresholdPercent: 20.5
+  - hostname: slow.example.com
+    timeout: 30
+`)
+
+	assert.Equal(t, 3, len(settings.hosts))
+
+	assert.Equal(t, 4, settings.hosts[0].Count)
+	assert.Equal(t, 5*time.Second, settings.hosts[0].Timeout)
+	assert.Equal(t, 50.0, settings.hosts[0].LossThresholdPercent)
+
+	assert.Equal(t, "Flaky", settings.hosts[1].Label)
+	assert.Equal(t, 10, settings.hosts[1].Count)
+	assert.Equal(t, 2500*time.Millisecond, settings.hosts[1].Timeout)
+	assert.Equal(t, 20.5, settings.hosts[1].LossThresholdPercent)
+
+	assert.Equal(t, 4, settings.hosts[2].Count)
+	assert.Equal(t, 30*time.Second, settings.hosts[2].Timeout)
+	assert.Equal(t, 50.0, settings.hosts[2].LossThresholdPercent)
+}
+
+func Test_Host_isUp(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		loss      float64
+		expected  bool
+	}{
+		{name: "no loss", threshold: 100, loss: 0, expected: true},
+		{name: "some loss under the default threshold", threshold: 100, loss: 75, expected: true},
+		{name: "everything lost", threshold: 100, loss: 100, expected: false},
+		{name: "under the threshold", threshold: 50, loss: 30, expected: true},
+		{name: "at the threshold", threshold: 50, loss: 50, expected: false},
+		{name: "over the threshold", threshold: 50, loss: 60, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host := Host{LossThresholdPercent: tt.threshold}
+			assert.Equal(t, tt.expected, host.isUp(tt.loss))
+		})
+	}
+}
+
+func Test_Host_isDegraded(t *testing.T) {
+	tests := []struct {
+		name     string
+		loss     float64
+		expected bool
+	}{
+		{name: "no loss", loss: 0, expected: false},
+		{name: "under the band", loss: 9.9, expected: false},
+		{name: "at the bottom of the band", loss: 10, expected: true},
+		{name: "in the band", loss: 40, expected: true},
+		{name: "at the loss threshold", loss: 50, expected: false},
+		{name: "everything lost", loss: 100, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host := Host{LossThresholdPercent: 50, DegradedLossPercent: 10, PacketLoss: tt.loss}
+			host.Up = host.isUp(tt.loss)
+			assert.Equal(t, tt.expected, host.isDegraded())
+		})
+	}
+
+	t.Run("turned off", func(t *testing.T) {
+		host := Host{LossThresholdPercent: 100, Up: true, PacketLoss: 40}
+		assert.Equal(t, false, host.isDegraded())
+	})
+}
+
+func Test_buildhosts_CheckTypes(t *testing.T) {
+	settings := newTestSettings(t, `
+hosts:
+  - hostname: db.example.com
+    type: tcp
+    port: 5432
+  - hostname: example.com
+    type: http
+    url: https://example.com/health
+    expectStatus: 204
+`)
+
+	assert.Equal(t, 2, len(settings.hosts))
+
+	assert.Equal(t, "tcp", settings.hosts[0].Type)
+	assert.Equal(t, 5432, settings.hosts[0].Port)
+
+	assert.Equal(t, "http", settings.hosts[1].Type)
+	assert.Equal(t, "https://example.com/health", settings.hosts[1].URL)
+	assert.Equal(t, 204, settings.hosts[1].ExpectStatus)
+}
+
+func Test_buildhosts_IPVersion(t *testing.T) {
+	settings := newTestSettings(t, `
+hosts:
+  - hostname: example.com
+    ipVersion: 4
+  - hostname: example.com
+    ipVersion: 6
+  - hostname: example.com
+    label: Example over v6
+    ipVersion: "6"
+  - hostname: example.org
+    ipVersion: auto
+  - hostname: example.net
+    ipVersion: 5
+`)
+
+	versions := []string{}
+	labels := []string{}
+	for _, host := range settings.hosts {
+		versions = append(versions, host.IPVersion)
+		labels = append(labels, host.Label)
+	}
+
+	assert.DeepEqual(t, []string{"4", "6", "6", "auto", "auto"}, versions)
+	assert.DeepEqual(t, []string{"example.com (v4)", "example.com (v6)", "Example over v6", "example.org", "example.net"}, labels)
+	assert.Equal(t, "ip4", settings.hosts[0].network())
+	assert.Equal(t, "ip6", settings.hosts[1].network())
+	assert.Equal(t, "ip", settings.hosts[3].network())
+}
+
+func Test_NewSettingsFromYAML_RefreshInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		expected time.Duration
+	}{
+		{name: "seconds", yaml: "refreshInterval: 30", expected: 30 * time.Second},
+		{name: "duration", yaml: "refreshInterval: 5m", expected: 5 * time.Minute},
+		{name: "sub-second", yaml: "refreshInterval: 250ms", expected: 300 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := newTestSettings(t, tt.yaml)
+
+			assert.Equal(t, tt.expected, settings.common.RefreshInterval)
+		})
+	}
+}
+
+func Test_NewSettingsFromYAML_UnknownKeys(t *testing.T) {
+	settings := newTestSettings(t, `
+hosts:
+  - hostname: 192.168.1.1
+    ipVersion: 6
+  - hostnme: 192.168.1.2
+groups:
+  - name: Network
+    hosts:
+      - hostname: router
+        url: http://router
+        expectedStatus: 200
+uptimeFile: uptime.json
+showLatancy: true
+`)
+
+	assert.DeepEqual(t, []cfg.UnknownKey{
+		{Key: "groups.0.hosts.0.expectedStatus", Suggestion: "groups.0.hosts.0.expectStatus"},
+		{Key: "hosts.1.hostnme", Suggestion: "hosts.1.hostname"},
+		{Key: "showLatancy", Suggestion: "showLatency"},
+	}, settings.common.UnknownKeys())
+}
+
+func Test_NewSettingsFromYAML_ModuleDefaults(t *testing.T) {
+	globalConfig, err := config.ParseYaml(`
+wtf:
+  moduleDefaults:
+    ping:
+      count: 3
+      timeout: 2s
+      showLatency: false
+      hosts:
+        - hostname: router
+`)
+	assert.NilError(t, err)
+
+	settings := func(yaml string) *Settings {
+		ymlConfig, err := config.ParseYaml(yaml)
+		assert.NilError(t, err)
+
+		return NewSettingsFromYAML("office", ymlConfig, globalConfig)
+	}
+
+	// Every ping widget gets the defaults
+	office := settings("type: ping")
+	assert.Equal(t, 3, office.count)
+	assert.Equal(t, 2*time.Second, office.timeout)
+	assert.Equal(t, false, office.showLatency)
+	assert.Equal(t, 1, len(office.hosts))
+	assert.Equal(t, "router", office.hosts[0].Hostname)
+	// Hosts take the defaults of the widget
+	assert.Equal(t, 3, office.hosts[0].Count)
+
+	// The widget's own settings win, and its hosts replace the default ones
+	lab := settings(`
+type: ping
+count: 5
+hosts:
+  - hostname: lab1
+  - hostname: lab2
+`)
+	assert.Equal(t, 5, lab.count)
+	assert.Equal(t, 2*time.Second, lab.timeout)
+	assert.Equal(t, 2, len(lab.hosts))
+	assert.Equal(t, "lab1", lab.hosts[0].Hostname)
+	assert.Equal(t, 5, lab.hosts[0].Count)
+
+	// Defaults are checked like the widget's own settings
+	assert.Equal(t, 0, len(lab.common.UnknownKeys()))
+}
@@ -0,0 +1,75 @@
+package ping
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// hostCounts is how many hosts are in each state
+type hostCounts struct {
+	up   int
+	down int
+	err  int // hosts that couldn't be checked, such as those that don't resolve
+}
+
+// countHosts counts the hosts in each state
+func (widget *Widget) countHosts() hostCounts {
+	counts := hostCounts{}
+	for _, host := range widget.hosts {
+		switch {
+		case host.Up:
+			counts.up++
+		case host.Err != "" && host.Addr == "":
+			counts.err++
+		default:
+			counts.down++
+		}
+	}
+
+	return counts
+}
+
+// summary sums up the state of every host on a line, along with the slowest host that is
+// up (ex: "23 up · 2 down · 1 err · worst: db-replica 480ms")
+func (widget *Widget) summary() string {
+	counts := widget.countHosts()
+
+	parts := []string{fmt.Sprintf("[green]%d up[white]", counts.up)}
+	if counts.down > 0 {
+		parts = append(parts, fmt.Sprintf("[red]%d down[white]", counts.down))
+	}
+	if counts.err > 0 {
+		parts = append(parts, fmt.Sprintf("[yellow]%d err[white]", counts.err))
+	}
+
+	worst := -1
+	for idx, host := range widget.hosts {
+		if host.Up && (worst < 0 || host.AvgRtt > widget.hosts[worst].AvgRtt) {
+			worst = idx
+		}
+	}
+	if worst >= 0 {
+		host := widget.hosts[worst]
+		parts = append(parts, fmt.Sprintf("worst: %s %s", tview.Escape(host.Label), formatLatency(host.AvgRtt)))
+	}
+
+	return strings.Join(parts, " · ")
+}
+
+// title returns the widget's title, followed by how many hosts are down when
+// showDownInTitle is on (ex: "Pings (2 down)")
+func (widget *Widget) title() string {
+	title := widget.settings.common.Title
+	if !widget.settings.showDownInTitle {
+		return title
+	}
+
+	counts := widget.countHosts()
+	if down := counts.down + counts.err; down > 0 {
+		return fmt.Sprintf("%s (%d down)", title, down)
+	}
+
+	return title
+}
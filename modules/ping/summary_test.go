@@ -0,0 +1,79 @@
+package ping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wtfutil/wtf/cfg"
+	"gotest.tools/assert"
+)
+
+func Test_summary(t *testing.T) {
+	tests := []struct {
+		name     string
+		hosts    []Host
+		expected string
+		title    string
+	}{
+		{
+			name: "all up",
+			hosts: []Host{
+				{Label: "router", Up: true, AvgRtt: 2 * time.Millisecond, Addr: "192.168.1.1"},
+				{Label: "web", Up: true, AvgRtt: 40 * time.Millisecond, Addr: "192.0.2.10"},
+			},
+			expected: "[green]2 up[white] · worst: web 40ms",
+			title:    "Pings",
+		},
+		{
+			name: "mixed",
+			hosts: []Host{
+				{Label: "router", Up: true, AvgRtt: 2 * time.Millisecond, Addr: "192.168.1.1"},
+				{Label: "db-replica", Up: true, AvgRtt: 480 * time.Millisecond, Addr: "192.0.2.11"},
+				{Label: "nas", Up: false, PacketLoss: 100, Addr: "192.168.1.5"},
+				{Label: "api", Up: false, PacketLoss: 100, Err: "connection refused", Addr: "192.0.2.12"},
+				{Label: "old", Up: false, PacketLoss: 100, Err: "no such host", DNSErr: true},
+			},
+			expected: "[green]2 up[white] · [red]2 down[white] · [yellow]1 err[white] · worst: db-replica 480ms",
+			title:    "Pings (3 down)",
+		},
+		{
+			name: "all down",
+			hosts: []Host{
+				{Label: "nas", Up: false, PacketLoss: 100, Addr: "192.168.1.5"},
+				{Label: "printer", Up: false, PacketLoss: 100, Addr: "192.168.1.6"},
+			},
+			expected: "[green]0 up[white] · [red]2 down[white]",
+			title:    "Pings (2 down)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			widget := &Widget{
+				hosts:    tt.hosts,
+				settings: &Settings{common: &cfg.Common{Title: "Pings"}, showDownInTitle: true},
+			}
+
+			assert.Equal(t, tt.expected, widget.summary())
+			assert.Equal(t, tt.title, widget.title())
+
+			widget.settings.showDownInTitle = false
+			assert.Equal(t, "Pings", widget.title())
+		})
+	}
+}
+
+func Test_content_summary(t *testing.T) {
+	hosts := []Host{
+		{Label: "router", Up: true, AvgRtt: 2 * time.Millisecond},
+		{Label: "offline", Up: false},
+	}
+
+	widget := &Widget{hosts: hosts, settings: &Settings{showSummary: true}}
+	assert.Equal(t,
+		"[green]1 up[white] · [red]1 down[white] · worst: router 2ms\n"+
+			"[white]router      : [green]Up\n"+
+			"[white]offline     : [red]DOWN",
+		widget.content(),
+	)
+}
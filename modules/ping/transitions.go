@@ -0,0 +1,178 @@
+package ping
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// hostState is what the widget remembers about a host between refreshes
+type hostState struct {
+	checked  bool   // whether the host has been checked yet
+	reported string // the state last reported through onStateChange
+	last     string // the state of the last check
+	streak   int    // how many checks in a row ended in the last state
+	failures int    // how many checks in a row failed
+
+	downSince time.Time // when the first of the failed checks in a row happened
+	since     time.Time // when the first check in the last state happened
+	trackedAt time.Time // when the check last tracked was made
+}
+
+// nowFunc returns the current time. It is replaceable in tests
+var nowFunc = time.Now
+
+// runStateChangeCommand starts the onStateChange command without waiting for it to finish.
+// It is replaceable in tests
+var runStateChangeCommand = func(name string, args []string, env []string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	go func() { _ = cmd.Wait() }()
+
+	return nil
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// formatDuration formats a duration, such as how long a host has been down for, in its two
+// largest units (ex: "2h14m", "3d4h", "45s")
+func formatDuration(d time.Duration) string {
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+
+	for i, unit := range units {
+		if d < unit.size && i < len(units)-1 {
+			continue
+		}
+
+		str := fmt.Sprintf("%d%s", d/unit.size, unit.suffix)
+		if i+1 < len(units) {
+			if rest := (d % unit.size) / units[i+1].size; rest > 0 {
+				str += fmt.Sprintf("%d%s", rest, units[i+1].suffix)
+			}
+		}
+
+		return str
+	}
+
+	return ""
+}
+
+// downStreak describes how long the host at idx has been down for, and how many checks in
+// a row failed (ex: "2h14m (x17)"), or "" if it isn't down
+func (widget *Widget) downStreak(idx int) string {
+	if idx >= len(widget.states) || widget.states[idx].failures == 0 {
+		return ""
+	}
+
+	state := widget.states[idx]
+
+	return fmt.Sprintf("%s (x%d)", formatDuration(nowFunc().Sub(state.downSince)), state.failures)
+}
+
+// stateName is how a host's state is passed to the onStateChange command: down, degraded
+// when it is up but losing packets, slow when it is up but slower than its critLatency, or
+// up
+func stateName(host Host) string {
+	switch {
+	case !host.Up:
+		return "down"
+	case host.isDegraded():
+		return "degraded"
+	case host.latencyLevel() == latencyCrit:
+		return "slow"
+	default:
+		return "up"
+	}
+}
+
+// trackTransitions records the result of the last check of every host, including how long
+// it has been down for, and runs the onStateChange command for the hosts whose state
+// changed. A change only counts once the host has been in its new state for flapDampening
+// checks in a row. The first check of a host only establishes its state
+func (widget *Widget) trackTransitions() {
+	if len(widget.states) != len(widget.hosts) {
+		widget.states = make([]hostState, len(widget.hosts))
+	}
+
+	dampening := max(widget.settings.flapDampening, 1)
+
+	for idx, host := range widget.hosts {
+		state := &widget.states[idx]
+
+		// Hosts that weren't due in the last refresh keep their state
+		if !host.CheckedAt.IsZero() && host.CheckedAt.Equal(state.trackedAt) {
+			continue
+		}
+		state.trackedAt = host.CheckedAt
+
+		current := stateName(host)
+		if current == state.last && state.checked {
+			state.streak++
+		} else {
+			state.last = current
+			state.streak = 1
+			state.since = nowFunc()
+		}
+
+		if host.Up {
+			state.failures = 0
+			state.downSince = time.Time{}
+		} else {
+			if state.failures == 0 {
+				state.downSince = nowFunc()
+			}
+			state.failures++
+		}
+
+		if !state.checked {
+			state.checked = true
+			state.reported = current
+			continue
+		}
+
+		if current != state.reported && state.streak >= dampening {
+			state.reported = current
+			widget.stateChanged(host, state.failures)
+		}
+	}
+}
+
+// stateChanged runs the onStateChange command, if there is one, for a host that went up,
+// down, degraded or slow. The host's label, hostname, new state and consecutive failures are passed
+// both as arguments and as WTF_PING_* environment variables
+func (widget *Widget) stateChanged(host Host, failures int) {
+	command := strings.Fields(widget.settings.onStateChange)
+	if len(command) == 0 {
+		return
+	}
+
+	args := append(command[1:len(command):len(command)], host.Label, host.Hostname, stateName(host), strconv.Itoa(failures))
+	env := []string{
+		"WTF_PING_LABEL=" + host.Label,
+		"WTF_PING_HOSTNAME=" + host.Hostname,
+		"WTF_PING_STATE=" + stateName(host),
+		"WTF_PING_FAILURES=" + strconv.Itoa(failures),
+	}
+
+	if err := runStateChangeCommand(command[0], args, env); err != nil {
+		widget.commandErr = fmt.Errorf("onStateChange failed: %w", err)
+		return
+	}
+
+	widget.commandErr = nil
+}
@@ -0,0 +1,224 @@
+package ping
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+type stateChangeCall struct {
+	Name string
+	Args []string
+	Env  []string
+}
+
+func stubStateChanges(t *testing.T) *[]stateChangeCall {
+	calls := []stateChangeCall{}
+
+	originalRun := runStateChangeCommand
+	t.Cleanup(func() { runStateChangeCommand = originalRun })
+
+	runStateChangeCommand = func(name string, args []string, env []string) error {
+		calls = append(calls, stateChangeCall{Name: name, Args: args, Env: env})
+		return nil
+	}
+
+	return &calls
+}
+
+// checkSequence feeds the widget one check result per refresh for its only host
+func checkSequence(widget *Widget, results ...bool) {
+	for _, up := range results {
+		widget.hosts[0].Up = up
+		widget.trackTransitions()
+	}
+}
+
+func newTransitionTestWidget(dampening int) *Widget {
+	return &Widget{
+		hosts: []Host{{Label: "Router", Hostname: "192.168.1.1"}},
+		settings: &Settings{
+			onStateChange: "notify-send --urgency=critical",
+			flapDampening: dampening,
+		},
+	}
+}
+
+func callStates(calls []stateChangeCall) []string {
+	states := []string{}
+	for _, call := range calls {
+		states = append(states, call.Args[len(call.Args)-2])
+	}
+
+	return states
+}
+
+func Test_trackTransitions(t *testing.T) {
+	calls := stubStateChanges(t)
+	widget := newTransitionTestWidget(1)
+
+	// The first check only establishes the state, and repeating it is no transition
+	checkSequence(widget, true, true)
+	assert.Equal(t, 0, len(*calls))
+
+	checkSequence(widget, false, false, false)
+	assert.DeepEqual(t, []stateChangeCall{{
+		Name: "notify-send",
+		Args: []string{"--urgency=critical", "Router", "192.168.1.1", "down", "1"},
+		Env: []string{
+			"WTF_PING_LABEL=Router",
+			"WTF_PING_HOSTNAME=192.168.1.1",
+			"WTF_PING_STATE=down",
+			"WTF_PING_FAILURES=1",
+		},
+	}}, *calls)
+
+	checkSequence(widget, true)
+	assert.DeepEqual(t, []string{"down", "up"}, callStates(*calls))
+	assert.Equal(t, "0", (*calls)[1].Args[4])
+}
+
+func Test_trackTransitions_FirstCheckDown(t *testing.T) {
+	calls := stubStateChanges(t)
+	widget := newTransitionTestWidget(1)
+
+	checkSequence(widget, false, false, true)
+
+	assert.DeepEqual(t, []string{"up"}, callStates(*calls))
+}
+
+func Test_trackTransitions_Degraded(t *testing.T) {
+	calls := stubStateChanges(t)
+
+	widget := newTransitionTestWidget(1)
+	widget.hosts[0].LossThresholdPercent = 100
+	widget.hosts[0].DegradedLossPercent = 1
+
+	for _, loss := range []float64{0, 40, 40, 100, 0} {
+		widget.hosts[0].PacketLoss = loss
+		widget.hosts[0].Up = widget.hosts[0].isUp(loss)
+		widget.trackTransitions()
+	}
+
+	assert.DeepEqual(t, []string{"degraded", "down", "up"}, callStates(*calls))
+}
+
+func Test_trackTransitions_NotDue(t *testing.T) {
+	calls := stubStateChanges(t)
+
+	widget := newTransitionTestWidget(2)
+	checkedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	widget.hosts[0].CheckedAt = checkedAt
+	checkSequence(widget, true)
+
+	// Refreshes that didn't check the host don't count towards its dampening
+	widget.hosts[0].CheckedAt = checkedAt.Add(time.Minute)
+	checkSequence(widget, false, false, false)
+	assert.Equal(t, 0, len(*calls))
+	assert.Equal(t, 1, widget.states[0].failures)
+
+	widget.hosts[0].CheckedAt = checkedAt.Add(2 * time.Minute)
+	checkSequence(widget, false)
+	assert.DeepEqual(t, []string{"down"}, callStates(*calls))
+}
+
+func Test_trackTransitions_Dampening(t *testing.T) {
+	calls := stubStateChanges(t)
+	widget := newTransitionTestWidget(3)
+
+	checkSequence(widget, true, false, true, false, false, true)
+	assert.Equal(t, 0, len(*calls), "flapping never lasts three checks")
+
+	checkSequence(widget, false, false)
+	assert.Equal(t, 0, len(*calls))
+
+	checkSequence(widget, false)
+	assert.DeepEqual(t, []string{"down"}, callStates(*calls))
+	assert.Equal(t, "3", (*calls)[0].Args[4])
+
+	checkSequence(widget, false, true, true, true, true)
+	assert.DeepEqual(t, []string{"down", "up"}, callStates(*calls))
+}
+
+func Test_trackTransitions_CommandFails(t *testing.T) {
+	originalRun := runStateChangeCommand
+	t.Cleanup(func() { runStateChangeCommand = originalRun })
+	runStateChangeCommand = func(string, []string, []string) error {
+		return errors.New("executable file not found")
+	}
+
+	widget := newTransitionTestWidget(1)
+	checkSequence(widget, true, false)
+
+	assert.DeepEqual(t, []string{"onStateChange failed: executable file not found"}, widget.warnings())
+}
+
+func Test_formatDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{duration: 0, expected: "0s"},
+		{duration: 45 * time.Second, expected: "45s"},
+		{duration: 5*time.Minute + 12*time.Second, expected: "5m12s"},
+		{duration: 2*time.Hour + 14*time.Minute + 59*time.Second, expected: "2h14m"},
+		{duration: 2 * time.Hour, expected: "2h"},
+		{duration: 3*24*time.Hour + 4*time.Hour + 30*time.Minute, expected: "3d4h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatDuration(tt.duration))
+		})
+	}
+}
+
+func Test_content_downStreak(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	originalNow := nowFunc
+	t.Cleanup(func() { nowFunc = originalNow })
+	nowFunc = func() time.Time { return now }
+
+	widget := &Widget{
+		hosts: []Host{
+			{Label: "router", Up: true, AvgRtt: 2 * time.Millisecond},
+			{Label: "nas"},
+		},
+		settings: &Settings{showLatency: true},
+	}
+
+	// A refresh every minute, with the NAS going down on the second one
+	refresh := func(nasUp bool) {
+		widget.hosts[1].Up = nasUp
+		widget.trackTransitions()
+		now = now.Add(time.Minute)
+	}
+
+	refresh(true)
+	for i := 0; i < 17; i++ {
+		refresh(false)
+	}
+	now = now.Add(2 * time.Hour)
+
+	assert.Equal(t,
+		"[white]router      : [green]Up   2ms\n"+
+			"[white]nas         : [red]DOWN 2h17m (x17)",
+		widget.content(),
+	)
+
+	refresh(true)
+	widget.hosts[1].AvgRtt = 3 * time.Millisecond
+	assert.Equal(t,
+		"[white]router      : [green]Up   2ms\n"+
+			"[white]nas         : [green]Up   3ms",
+		widget.content(),
+	)
+
+	// A new streak starts over
+	refresh(false)
+	now = now.Add(30 * time.Second)
+	assert.Assert(t, strings.HasSuffix(widget.content(), "[red]DOWN 1m30s (x1)"), widget.content())
+}
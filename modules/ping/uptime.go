@@ -0,0 +1,214 @@
+package ping
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// uptimeCheck is the result of a single check of a host
+type uptimeCheck struct {
+	At time.Time `json:"at"`
+	Up bool      `json:"up"`
+}
+
+// hostUptime is the results of the last checks of a host, oldest first
+type hostUptime struct {
+	Label    string        `json:"label"`
+	Hostname string        `json:"hostname"`
+	Checks   []uptimeCheck `json:"checks"`
+}
+
+// uptimeStats keeps the results of the last window checks of every host, saved to a file
+// so they survive restarts
+type uptimeStats struct {
+	path   string
+	window int
+	maxAge time.Duration
+	hosts  map[string]*hostUptime
+}
+
+// loadUptime reads the uptime file. A missing file means no host has been checked yet
+func loadUptime(path string, window int, maxAge time.Duration) (*uptimeStats, error) {
+	stats := &uptimeStats{
+		path:   path,
+		window: window,
+		maxAge: maxAge,
+		hosts:  make(map[string]*hostUptime),
+	}
+
+	if path == "" {
+		return stats, nil
+	}
+
+	fileData, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return stats, nil
+		}
+		return stats, fmt.Errorf("could not read uptime: %w", err)
+	}
+
+	hosts := []*hostUptime{}
+	if err := json.Unmarshal(fileData, &hosts); err != nil {
+		return stats, fmt.Errorf("could not read uptime: %w", err)
+	}
+
+	for _, uptime := range hosts {
+		stats.hosts[Host{Label: uptime.Label, Hostname: uptime.Hostname}.key()] = uptime
+	}
+
+	return stats, nil
+}
+
+// record adds the result of the last check of a host, forgetting those that fell out of
+// the window
+func (stats *uptimeStats) record(host Host, at time.Time) {
+	uptime, ok := stats.hosts[host.key()]
+	if !ok {
+		uptime = &hostUptime{Label: host.Label, Hostname: host.Hostname}
+		stats.hosts[host.key()] = uptime
+	}
+
+	uptime.Checks = append(uptime.Checks, uptimeCheck{At: at, Up: host.Up})
+	if len(uptime.Checks) > stats.window {
+		uptime.Checks = append([]uptimeCheck{}, uptime.Checks[len(uptime.Checks)-stats.window:]...)
+	}
+}
+
+// prune forgets the checks older than maxAge, and the hosts that are left without any. A
+// maxAge of 0 keeps every check in the window
+func (stats *uptimeStats) prune(now time.Time) {
+	if stats.maxAge <= 0 {
+		return
+	}
+
+	cutoff := now.Add(-stats.maxAge)
+	for key, uptime := range stats.hosts {
+		kept := uptime.Checks[:0]
+		for _, check := range uptime.Checks {
+			if !check.At.Before(cutoff) {
+				kept = append(kept, check)
+			}
+		}
+		uptime.Checks = kept
+
+		if len(uptime.Checks) == 0 {
+			delete(stats.hosts, key)
+		}
+	}
+}
+
+// percent returns the percentage of the host's checks in the window that found it up, and
+// false if it has none
+func (stats *uptimeStats) percent(host Host) (float64, bool) {
+	uptime, ok := stats.hosts[host.key()]
+	if !ok || len(uptime.Checks) == 0 {
+		return 0, false
+	}
+
+	up := 0
+	for _, check := range uptime.Checks {
+		if check.Up {
+			up++
+		}
+	}
+
+	return float64(up) * 100 / float64(len(uptime.Checks)), true
+}
+
+// reset forgets the checks of the given hosts
+func (stats *uptimeStats) reset(hosts []Host) {
+	for _, host := range hosts {
+		delete(stats.hosts, host.key())
+	}
+}
+
+// save writes the checks of every host to the uptime file
+func (stats *uptimeStats) save() error {
+	if stats.path == "" {
+		return nil
+	}
+
+	hosts := []*hostUptime{}
+	for _, uptime := range stats.hosts {
+		hosts = append(hosts, uptime)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].Label+hosts[i].Hostname < hosts[j].Label+hosts[j].Hostname
+	})
+
+	fileData, err := json.Marshal(hosts)
+	if err != nil {
+		return fmt.Errorf("could not write uptime: %w", err)
+	}
+
+	if err := os.WriteFile(stats.path, fileData, 0600); err != nil {
+		return fmt.Errorf("could not write uptime: %w", err)
+	}
+
+	return nil
+}
+
+/* -------------------- Widget Functions -------------------- */
+
+// recordUptime adds the results of the hosts checked in the last refresh to their uptime,
+// and saves it
+func (widget *Widget) recordUptime(checked []bool) {
+	now := nowFunc()
+
+	for idx, host := range widget.hosts {
+		if idx < len(checked) && checked[idx] {
+			widget.uptime.record(host, now)
+		}
+	}
+	widget.uptime.prune(now)
+
+	widget.uptimeErr = widget.uptime.save()
+}
+
+// resetUptime forgets the uptime of the selected host, or of every host if none is selected
+func (widget *Widget) resetUptime() {
+	if widget.uptime == nil {
+		return
+	}
+
+	if idx, ok := widget.selectedHost(); ok {
+		widget.uptime.reset([]Host{widget.hosts[idx]})
+	} else {
+		widget.uptime.reset(widget.hosts)
+	}
+	widget.uptimeErr = widget.uptime.save()
+	widget.display()
+}
+
+// uptimeColumn renders the host's uptime, colored by uptimeWarn and uptimeCrit. Blank until
+// the host has been checked
+func (widget *Widget) uptimeColumn(host Host) string {
+	percent, ok := widget.uptime.percent(host)
+	if !ok {
+		return fmt.Sprintf("%*s", uptimeWidth, "")
+	}
+
+	color := "green"
+	switch {
+	case percent < widget.settings.uptimeCrit:
+		color = "red"
+	case percent < widget.settings.uptimeWarn:
+		color = "yellow"
+	}
+
+	return fmt.Sprintf("[%s]%*s", color, uptimeWidth, formatUptime(percent))
+}
+
+// uptimeWidth fits the widest uptime, 100.0%
+const uptimeWidth = 6
+
+// formatUptime formats an uptime percentage with a single decimal (ex: "99.0%")
+func formatUptime(percent float64) string {
+	return fmt.Sprintf("%.1f%%", percent)
+}
@@ -0,0 +1,111 @@
+package ping
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+var uptimeStart = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// recordChecks records one check of the host per minute, from uptimeStart
+func recordChecks(stats *uptimeStats, host Host, results ...bool) {
+	for idx, up := range results {
+		host.Up = up
+		stats.record(host, uptimeStart.Add(time.Duration(idx)*time.Minute))
+	}
+}
+
+func Test_uptimeStats_percent(t *testing.T) {
+	router := Host{Label: "Router", Hostname: "192.168.1.1"}
+
+	stats, err := loadUptime("", 4, 0)
+	assert.NilError(t, err)
+
+	_, ok := stats.percent(router)
+	assert.Equal(t, false, ok)
+
+	recordChecks(stats, router, true, false, true)
+	percent, ok := stats.percent(router)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "66.7%", formatUptime(percent))
+
+	// Only the last 4 checks count
+	recordChecks(stats, router, true, true, true)
+	percent, _ = stats.percent(router)
+	assert.Equal(t, "100.0%", formatUptime(percent))
+	assert.Equal(t, 4, len(stats.hosts[router.key()].Checks))
+
+	stats.reset([]Host{router})
+	_, ok = stats.percent(router)
+	assert.Equal(t, false, ok)
+}
+
+func Test_uptimeStats_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ping-uptime.json")
+	router := Host{Label: "Router", Hostname: "192.168.1.1"}
+	nas := Host{Label: "NAS", Hostname: "nas.local"}
+
+	stats, err := loadUptime(path, 100, 0)
+	assert.NilError(t, err)
+	recordChecks(stats, router, true, true, false, true)
+	recordChecks(stats, nas, false)
+	assert.NilError(t, stats.save())
+
+	loaded, err := loadUptime(path, 100, 0)
+	assert.NilError(t, err)
+	percent, _ := loaded.percent(router)
+	assert.Equal(t, 75.0, percent)
+	percent, _ = loaded.percent(nas)
+	assert.Equal(t, 0.0, percent)
+	assert.Equal(t, uptimeStart.Add(3*time.Minute), loaded.hosts[router.key()].Checks[3].At)
+}
+
+func Test_uptimeStats_prune(t *testing.T) {
+	router := Host{Label: "Router", Hostname: "192.168.1.1"}
+	gone := Host{Label: "Gone", Hostname: "gone.local"}
+
+	stats, err := loadUptime("", 100, 10*time.Minute)
+	assert.NilError(t, err)
+	recordChecks(stats, router, false, false, true, true)
+	recordChecks(stats, gone, false)
+
+	// Keeps the checks of the last 10 minutes: those at +2 and +3 minutes
+	stats.prune(uptimeStart.Add(12 * time.Minute))
+
+	percent, _ := stats.percent(router)
+	assert.Equal(t, 100.0, percent)
+	assert.Equal(t, 2, len(stats.hosts[router.key()].Checks))
+	_, ok := stats.percent(gone)
+	assert.Equal(t, false, ok)
+}
+
+func Test_content_uptime(t *testing.T) {
+	hosts := []Host{
+		{Label: "router", Up: true, AvgRtt: 2 * time.Millisecond},
+		{Label: "flaky", Up: true, AvgRtt: 30 * time.Millisecond},
+		{Label: "offline", Up: false},
+		{Label: "new", Up: true, AvgRtt: 5 * time.Millisecond},
+	}
+
+	stats, err := loadUptime("", 100, 0)
+	assert.NilError(t, err)
+	recordChecks(stats, hosts[0], true, true, true)
+	recordChecks(stats, hosts[1], true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, false)
+	recordChecks(stats, hosts[2], true, false, false)
+
+	widget := &Widget{
+		hosts:    hosts,
+		uptime:   stats,
+		settings: &Settings{showLatency: true, showUptime: true, uptimeWarn: 99, uptimeCrit: 95},
+	}
+	assert.Equal(t,
+		"[white]router      : [green]Up    2ms [green]100.0%\n"+
+			"[white]flaky       : [green]Up   30ms [yellow] 95.0%\n"+
+			"[white]offline     : [red]DOWN      [red] 33.3%\n"+
+			"[white]new         : [green]Up    5ms",
+		widget.content(),
+	)
+}
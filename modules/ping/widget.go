@@ -1,78 +1,246 @@
 package ping
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"math"
+	"net/http"
+	"strconv"
 	"strings"
-	"sync"
+	"sync/atomic"
 	"time"
 
-	probing "github.com/prometheus-community/pro-bing"
 	"github.com/rivo/tview"
 	"github.com/wtfutil/wtf/view"
 )
 
+const (
+	// deadlineMargin is the most of the refresh interval kept back from checking hosts, for
+	// the rest of the refresh to be done on schedule
+	deadlineMargin = 2 * time.Second
+
+	timedOutErr = "not checked before the refresh deadline"
+)
+
 // Widget is the container for your module's data
 type Widget struct {
 	view.TextWidget
-	hosts []Host
+	hosts      []Host
+	states     []hostState
+	commandErr error
+	actionErr  error
+	refreshing atomic.Bool
+	detailed   bool
+
+	selectedKey   string
+	refreshedAt   time.Time
+	commandCancel context.CancelFunc
+
+	hostsFileModTime time.Time
+	hostsFileErr     error
+	malformedHosts   int
+	fileHosts        []Host
+
+	discovered    discovery
+	implicitHosts []Host
+
+	uptime    *uptimeStats
+	uptimeErr error
+
+	exporter     *exporter
+	exportServer *http.Server
+	exportErr    error
 
+	pages    *tview.Pages
+	tviewApp *tview.Application
 	settings *Settings
 }
 
 // NewWidget creates and returns an instance of Widget
-func NewWidget(tviewApp *tview.Application, redrawChan chan bool, settings *Settings) *Widget {
+func NewWidget(tviewApp *tview.Application, redrawChan chan bool, pages *tview.Pages, settings *Settings) *Widget {
 	widget := Widget{
-		TextWidget: view.NewTextWidget(tviewApp, redrawChan, nil, settings.common),
+		TextWidget: view.NewTextWidget(tviewApp, redrawChan, pages, settings.common),
 
+		pages:    pages,
+		tviewApp: tviewApp,
 		settings: settings,
+		detailed: settings.defaultView == viewDetailed,
 	}
+	widget.View.SetRegions(true)
 	widget.hosts = widget.settings.hosts
+	widget.reloadHostsFile()
+
+	if settings.showUptime {
+		widget.uptime, widget.uptimeErr = loadUptime(settings.uptimePath, settings.uptimeWindow, settings.uptimeMaxAge)
+	}
+
+	widget.listenForExport()
+	widget.initializeKeyboardControls()
+	widget.EnableRefreshFooter()
 
 	return &widget
 }
 
 /* -------------------- Exported Functions -------------------- */
 
-func (widget *Widget) doPings() {
-	var wg sync.WaitGroup
-	for i := range widget.hosts {
-		idx := i
-		host := widget.hosts[idx]
-		widget.hosts[idx].Up = false // reset to false each time
-		wg.Add(1)
+// Stop stops serving the export, for the widget that replaces this one when the config
+// changes to be able to listen on the same address
+func (widget *Widget) Stop() {
+	widget.stopExport()
+	widget.TextWidget.Stop()
+}
+
+// doPings checks every host that is due, maxConcurrent at a time, each after a random delay
+// of up to jitter so the checks spread out. Checking stops shortly before the refresh
+// interval, so that a slow run doesn't overlap the next one, and the checks still running
+// are cancelled. Hosts that weren't checked by then keep their last result, marked as timed
+// out. Returns which hosts were checked
+func (widget *Widget) doPings() []bool {
+	ctx := context.Background()
+	if interval := widget.CommonSettings().RefreshInterval; interval > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, refreshDeadline(interval))
+		defer cancel()
+	}
+
+	type hostResult struct {
+		idx    int
+		result checkResult
+	}
+
+	start := nowFunc()
+	widget.refreshedAt = start
+
+	due := []int{}
+	for idx, host := range widget.hosts {
+		if host.isDue(start) {
+			due = append(due, idx)
+		}
+	}
+
+	// Workers that are still running after the deadline must not read the hosts while
+	// they are being updated, so they check copies
+	hosts := append([]Host{}, widget.hosts...)
+
+	jobs := make(chan int, len(due))
+	for _, idx := range due {
+		jobs <- idx
+	}
+	close(jobs)
+
+	// Buffered for every host, so workers that finish after the deadline don't block
+	results := make(chan hostResult, len(due))
+
+	for i := 0; i < min(max(widget.settings.maxConcurrent, 1), len(due)); i++ {
 		go func() {
-			defer wg.Done()
-			pinger, err := probing.NewPinger(host.Hostname)
-			if err == nil {
-				pinger.Count = 1
-				pinger.Timeout = 10 * time.Second
-				err = pinger.Run() // Blocks until finished.
-				if err == nil {
-					stats := pinger.Statistics() // get send/receive/duplicate/rtt stats
-					if stats.PacketsRecv > 0 {
-						widget.hosts[idx].Up = true
-					} else {
-						widget.hosts[idx].Up = false
-					}
-				} else {
-					log.Fatalf("error sending ping: %v", err)
+			for idx := range jobs {
+				if !sleepJitter(ctx, widget.settings.jitter) {
+					return
 				}
+				result := checkWithRetries(ctx, hosts[idx], widget.settings.retries, widget.settings.retryDelay)
+				results <- hostResult{idx: idx, result: result}
 			}
-
 		}()
 	}
-	wg.Wait()
+
+	checked := make([]bool, len(widget.hosts))
+	for range due {
+		select {
+		case res := <-results:
+			widget.hosts[res.idx].applyResult(res.result)
+			widget.hosts[res.idx].CheckedAt = start
+			checked[res.idx] = true
+		case <-ctx.Done():
+			for _, idx := range due {
+				if !checked[idx] {
+					widget.hosts[idx].TimedOut = true
+				}
+			}
+			return checked
+		}
+	}
+
+	return checked
 }
+
+// Refresh checks the hosts and redraws them. A refresh that starts while another is still
+// running, such as one asked for by hand, is skipped
 func (widget *Widget) Refresh() {
+	if !widget.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+	defer widget.refreshing.Store(false)
 
-	widget.doPings()
+	widget.reloadHostsFile()
+	widget.discoverHosts()
+	checked := widget.doPings()
+	if widget.settings.showUptime {
+		widget.recordUptime(checked)
+	}
+	widget.trackTransitions()
+	widget.export()
+	widget.MarkRefreshed()
 	widget.display()
 }
 
 /* -------------------- Unexported Functions -------------------- */
 
+// refreshDeadline returns how long a refresh every interval may spend checking hosts: the
+// interval, less a tenth of it up to deadlineMargin
+func refreshDeadline(interval time.Duration) time.Duration {
+	return interval - min(interval/10, deadlineMargin)
+}
+
 func (widget *Widget) content() string {
+	header, render := "", widget.compactLine()
+	if widget.detailed {
+		header, render = widget.detailedLine()
+	}
+
+	s := []string{}
+	if widget.settings.showSummary {
+		s = append(s, widget.summary())
+	}
+	if header != "" {
+		s = append(s, header)
+	}
+	if line := widget.discoveryLine(); line != "" {
+		s = append(s, line)
+	}
+
+	for _, sec := range widget.sections() {
+		if sec.name != "" {
+			s = append(s, fmt.Sprintf("[yellow]%s", tview.Escape(sec.name)))
+		}
+
+		up := 0
+		for _, idx := range sec.hosts {
+			if widget.settings.hideUp && widget.hosts[idx].Up && !widget.hosts[idx].isDegraded() {
+				up++
+				continue
+			}
+			line := render(idx)
+			if widget.hosts[idx].key() == widget.selectedKey {
+				line = fmt.Sprintf(`["%s"]%s[""]`, selectedRegion, line)
+			}
+			s = append(s, line)
+		}
+
+		if up > 0 {
+			s = append(s, upSummary(up))
+		}
+	}
+
+	if footer := widget.malformedFooter(); footer != "" {
+		s = append(s, footer)
+	}
+
+	return strings.Join(s, "\n")
+}
+
+// compactLine returns the func rendering the host at idx as a line of the compact layout,
+// with its columns as wide as those of every host
+func (widget *Widget) compactLine() func(idx int) string {
 	nameWidth := 12
 	for _, t := range widget.hosts {
 		if len(t.Label) > nameWidth {
@@ -80,23 +248,199 @@ func (widget *Widget) content() string {
 		}
 	}
 
-	s := []string{}
-	for _, t := range widget.hosts {
-		var status string
-		if t.Up {
-			status = "[green]Up"
-		} else {
-			status = "[red]DOWN"
+	latencyWidth := 0
+	if widget.settings.showLatency {
+		for _, t := range widget.hosts {
+			if t.Up && !t.TimedOut && len(formatLatency(t.AvgRtt)) > latencyWidth {
+				latencyWidth = len(formatLatency(t.AvgRtt))
+			}
 		}
-		statusLine := fmt.Sprintf("[white]%-*s: %s", nameWidth, t.Label, status)
-		s = append(s, statusLine)
 	}
 
-	return strings.Join(s, "\n")
+	dnsWidth := 0
+	if widget.settings.showDNS {
+		for _, t := range widget.hosts {
+			dnsWidth = max(dnsWidth, len(formatLatency(t.DNSTime)))
+		}
+	}
+
+	return func(idx int) string {
+		return widget.statusLine(idx, nameWidth, latencyWidth, dnsWidth)
+	}
+}
+
+// warnings returns the problems the widget ran into, the hosts' own errors summed up first
+func (widget *Widget) warnings() []string {
+	warnings := []string{}
+
+	if hostErrors := widget.hostErrors(); hostErrors != "" {
+		warnings = append(warnings, hostErrors)
+	}
+
+	for _, err := range []error{
+		widget.commandErr,
+		widget.hostsFileErr,
+		widget.uptimeErr,
+		widget.exportErr,
+		widget.actionErr,
+	} {
+		if err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+
+	return warnings
+}
+
+// hostErrors sums up the errors the hosts' checks ran into: the error of the host checked
+// last and, when more than one failed, how many did. It is "" when none did
+func (widget *Widget) hostErrors() string {
+	failed := 0
+	var last Host
+	for _, host := range widget.hosts {
+		if host.Err == "" {
+			continue
+		}
+		if failed == 0 || host.CheckedAt.After(last.CheckedAt) {
+			last = host
+		}
+		failed++
+	}
+
+	switch failed {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("%s: %s", last.Label, last.Err)
+	default:
+		return fmt.Sprintf("%d hosts failed, last %s: %s", failed, last.Label, last.Err)
+	}
+}
+
+// statusLine renders the label and status of the host at idx
+func (widget *Widget) statusLine(idx, nameWidth, latencyWidth, dnsWidth int) string {
+	t := widget.hosts[idx]
+
+	level := t.latencyLevel()
+
+	color, name := widget.hostStatus(t)
+	status := fmt.Sprintf("[%s]%-4s", color, name)
+
+	if streak := widget.downStreak(idx); streak != "" && !t.Up {
+		status = fmt.Sprintf("%s %s", status, streak)
+	} else if latencyWidth > 0 && !t.DNSErr {
+		latency := ""
+		if t.Up && !t.TimedOut {
+			latency = formatLatency(t.AvgRtt)
+		}
+
+		color := ""
+		if level != latencyOK {
+			color = "[" + latencyColor(level) + "]"
+		}
+		status = fmt.Sprintf("%s %s%*s", status, color, latencyWidth, latency)
+	}
+
+	if widget.settings.showRetries && t.Up && t.Attempts > 1 {
+		status = fmt.Sprintf("%s [yellow](%s try)", status, ordinal(t.Attempts))
+	}
+	if widget.settings.showDNS {
+		status = fmt.Sprintf("%s %s", status, widget.dnsColumn(t, dnsWidth))
+	}
+	if age := widget.checkAge(t); age != "" {
+		status = fmt.Sprintf("%s [gray](checked %s ago)", status, age)
+	}
+	if widget.settings.showUptime {
+		status = fmt.Sprintf("%s %s", status, widget.uptimeColumn(t))
+	}
+
+	if t.PacketLoss > 0 && !t.DNSErr {
+		status = fmt.Sprintf("%s [yellow]%s loss", status, formatLoss(t.PacketLoss))
+	}
+	if widget.settings.showResolvedIP && t.Addr != "" && t.Addr != t.Hostname {
+		status = fmt.Sprintf("%s [gray](%s)", status, t.Addr)
+	}
+
+	return fmt.Sprintf("[white]%-*s: %s", nameWidth, t.Label, strings.TrimRight(status, " "))
+}
+
+// hostStatus returns the state to show for a host, and its color
+func (widget *Widget) hostStatus(t Host) (color, name string) {
+	level := t.latencyLevel()
+
+	switch {
+	case t.TimedOut:
+		return "red", "TIMEOUT"
+	case t.isDegraded():
+		return "yellow", "DEGRADED"
+	case t.Up && widget.settings.showSlow && level != latencyOK:
+		return latencyColor(level), "SLOW"
+	case t.Up:
+		return "green", "Up"
+	case t.DNSErr:
+		return "red", "DNS ERR"
+	default:
+		return "red", "DOWN"
+	}
+}
+
+// checkAge returns how long ago a host with its own interval was last checked, when it
+// wasn't checked in the last refresh, or ""
+func (widget *Widget) checkAge(host Host) string {
+	if host.Interval <= 0 || host.CheckedAt.IsZero() || !host.CheckedAt.Before(widget.refreshedAt) {
+		return ""
+	}
+
+	return formatDuration(nowFunc().Sub(host.CheckedAt))
+}
+
+// ordinal formats a number as an ordinal (ex: "2nd", "3rd", "11th")
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+
+	return strconv.Itoa(n) + suffix
+}
+
+// formatLoss formats a packet loss percentage, without decimals unless it has some
+// (ex: "33.3%", "50%")
+func formatLoss(lossPercent float64) string {
+	return strconv.FormatFloat(math.Round(lossPercent*10)/10, 'f', -1, 64) + "%"
+}
+
+// formatLatency formats a round-trip time in milliseconds, or seconds for slow hosts
+// (ex: "<1ms", "12ms", "1.5s")
+func formatLatency(rtt time.Duration) string {
+	if rtt < time.Millisecond {
+		return "<1ms"
+	}
+
+	rtt = rtt.Round(time.Millisecond)
+	if rtt < time.Second {
+		return fmt.Sprintf("%dms", rtt.Milliseconds())
+	}
+
+	return fmt.Sprintf("%.1fs", rtt.Seconds())
 }
 
 func (widget *Widget) display() {
+	widget.SetBanner(view.BannerWarning, strings.Join(widget.warnings(), "; "))
 	widget.Redraw(func() (string, string, bool) {
-		return widget.CommonSettings().Title, widget.content(), false
+		return widget.title(), widget.content(), false
 	})
+
+	if _, ok := widget.selectedHost(); ok {
+		widget.View.Highlight(selectedRegion)
+		widget.View.ScrollToHighlight()
+	} else {
+		widget.View.Highlight()
+	}
 }
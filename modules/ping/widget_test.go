@@ -0,0 +1,549 @@
+package ping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/wtfutil/wtf/cfg"
+	"gotest.tools/assert"
+)
+
+func Test_formatLatency(t *testing.T) {
+	tests := []struct {
+		name     string
+		rtt      time.Duration
+		expected string
+	}{
+		{name: "microseconds", rtt: 350 * time.Microsecond, expected: "<1ms"},
+		{name: "one millisecond", rtt: time.Millisecond, expected: "1ms"},
+		{name: "milliseconds", rtt: 12*time.Millisecond + 400*time.Microsecond, expected: "12ms"},
+		{name: "rounds to the millisecond", rtt: 12*time.Millisecond + 600*time.Microsecond, expected: "13ms"},
+		{name: "just under a second", rtt: 999*time.Millisecond + 700*time.Microsecond, expected: "1.0s"},
+		{name: "seconds", rtt: 1520 * time.Millisecond, expected: "1.5s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatLatency(tt.rtt))
+		})
+	}
+}
+
+func Test_content(t *testing.T) {
+	hosts := []Host{
+		{Label: "router", Up: true, AvgRtt: 800 * time.Microsecond},
+		{Label: "example.com", Up: true, AvgRtt: 112 * time.Millisecond},
+		{Label: "offline", Up: false},
+	}
+
+	widget := &Widget{hosts: hosts, settings: &Settings{showLatency: true}}
+	assert.Equal(t,
+		"[white]router      : [green]Up    <1ms\n"+
+			"[white]example.com : [green]Up   112ms\n"+
+			"[white]offline     : [red]DOWN",
+		widget.content(),
+	)
+
+	widget.settings.showLatency = false
+	assert.Equal(t,
+		"[white]router      : [green]Up\n"+
+			"[white]example.com : [green]Up\n"+
+			"[white]offline     : [red]DOWN",
+		widget.content(),
+	)
+}
+
+func Test_content_packetLoss(t *testing.T) {
+	hosts := []Host{
+		{Label: "flaky", Up: true, AvgRtt: 20 * time.Millisecond, PacketLoss: 100.0 / 3},
+		{Label: "lossy", Up: false, PacketLoss: 50},
+		{Label: "router", Up: true, AvgRtt: 2 * time.Millisecond},
+	}
+
+	widget := &Widget{hosts: hosts, settings: &Settings{showLatency: true}}
+	assert.Equal(t,
+		"[white]flaky       : [green]Up   20ms [yellow]33.3% loss\n"+
+			"[white]lossy       : [red]DOWN      [yellow]50% loss\n"+
+			"[white]router      : [green]Up    2ms",
+		widget.content(),
+	)
+}
+
+func Test_content_degraded(t *testing.T) {
+	hosts := []Host{
+		{Label: "lossy", Up: true, AvgRtt: 20 * time.Millisecond, PacketLoss: 40, DegradedLossPercent: 1},
+		{Label: "offline", Up: false, PacketLoss: 100, DegradedLossPercent: 1},
+		{Label: "router", Up: true, AvgRtt: 2 * time.Millisecond, DegradedLossPercent: 1},
+	}
+
+	widget := &Widget{hosts: hosts, settings: &Settings{showLatency: true}}
+	assert.Equal(t,
+		"[white]lossy       : [yellow]DEGRADED 20ms [yellow]40% loss\n"+
+			"[white]offline     : [red]DOWN      [yellow]100% loss\n"+
+			"[white]router      : [green]Up    2ms",
+		widget.content(),
+	)
+
+	// Degraded hosts aren't summed up with those that are up
+	widget.settings.hideUp = true
+	assert.Equal(t,
+		"[white]lossy       : [yellow]DEGRADED 20ms [yellow]40% loss\n"+
+			"[white]offline     : [red]DOWN      [yellow]100% loss\n"+
+			"[green]1 host up",
+		widget.content(),
+	)
+}
+
+func Test_content_checkErrors(t *testing.T) {
+	hosts := []Host{
+		{Label: "api", Up: false, PacketLoss: 100, Err: "503 Service Unavailable"},
+	}
+
+	// The error is shown in the banner rather than next to the host
+	widget := &Widget{hosts: hosts, settings: &Settings{showLatency: true}}
+	assert.Equal(t, "[white]api         : [red]DOWN [yellow]100% loss", widget.content())
+	assert.DeepEqual(t, []string{"api: 503 Service Unavailable"}, widget.warnings())
+}
+
+func Test_hostErrors(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	widget := &Widget{hosts: []Host{
+		{Label: "router", Up: true},
+		{Label: "api", Err: "503 Service Unavailable", CheckedAt: now},
+		{Label: "nas", Err: "timeout", CheckedAt: now.Add(time.Minute)},
+		{Label: "old", Err: "no such host", CheckedAt: now},
+	}}
+	assert.Equal(t, "3 hosts failed, last nas: timeout", widget.hostErrors())
+
+	widget.hosts = widget.hosts[:2]
+	assert.Equal(t, "api: 503 Service Unavailable", widget.hostErrors())
+
+	widget.hosts = widget.hosts[:1]
+	assert.Equal(t, "", widget.hostErrors())
+}
+
+func Test_content_retries(t *testing.T) {
+	hosts := []Host{
+		{Label: "router", Up: true, AvgRtt: 2 * time.Millisecond, Attempts: 1},
+		{Label: "wifi", Up: true, AvgRtt: 40 * time.Millisecond, Attempts: 2},
+		{Label: "offline", Up: false, PacketLoss: 100, Attempts: 3},
+	}
+
+	widget := &Widget{hosts: hosts, settings: &Settings{showLatency: true}}
+	assert.Equal(t,
+		"[white]router      : [green]Up    2ms\n"+
+			"[white]wifi        : [green]Up   40ms\n"+
+			"[white]offline     : [red]DOWN      [yellow]100% loss",
+		widget.content(),
+	)
+
+	widget.settings.showRetries = true
+	assert.Equal(t,
+		"[white]router      : [green]Up    2ms\n"+
+			"[white]wifi        : [green]Up   40ms [yellow](2nd try)\n"+
+			"[white]offline     : [red]DOWN      [yellow]100% loss",
+		widget.content(),
+	)
+}
+
+func Test_ordinal(t *testing.T) {
+	for n, expected := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd", 101: "101st", 111: "111th"} {
+		assert.Equal(t, expected, ordinal(n))
+	}
+}
+
+func Test_doPings_Retries(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	stubCheckHost(t, func(host Host) checkResult {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// host-0 fails its first check, host-1 every check
+		calls[host.Label]++
+		if host.Label == "host-1" || calls[host.Label] == 1 {
+			return checkResult{PacketLoss: 100}
+		}
+		return checkResult{AvgRtt: time.Millisecond}
+	})
+
+	widget := newPoolTestWidget(2, &Settings{
+		common:        &cfg.Common{RefreshInterval: time.Minute},
+		maxConcurrent: 2,
+		retries:       2,
+		retryDelay:    time.Millisecond,
+	})
+
+	widget.doPings()
+
+	assert.Assert(t, widget.hosts[0].Up)
+	assert.Equal(t, 2, widget.hosts[0].Attempts)
+	assert.Assert(t, !widget.hosts[1].Up)
+	assert.Equal(t, 3, widget.hosts[1].Attempts)
+}
+
+func stubCheckHost(t *testing.T, fake func(Host) checkResult) {
+	originalCheck := checkHost
+	t.Cleanup(func() { checkHost = originalCheck })
+
+	checkHost = func(_ context.Context, host Host) checkResult { return fake(host) }
+}
+
+func newPoolTestWidget(hostCount int, settings *Settings) *Widget {
+	for i := 0; i < hostCount; i++ {
+		name := fmt.Sprintf("host-%d", i)
+		settings.hosts = append(settings.hosts, Host{Label: name, Hostname: name, LossThresholdPercent: 100})
+	}
+
+	return NewWidget(tview.NewApplication(), make(chan bool, 1), nil, settings)
+}
+
+func Test_doPings_BoundedConcurrency(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	stubCheckHost(t, func(Host) checkResult {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			highest := maxInFlight.Load()
+			if current <= highest || maxInFlight.CompareAndSwap(highest, current) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		return checkResult{}
+	})
+
+	widget := newPoolTestWidget(40, &Settings{
+		common:        &cfg.Common{RefreshInterval: time.Minute},
+		maxConcurrent: 4,
+	})
+
+	widget.doPings()
+
+	assert.Assert(t, maxInFlight.Load() <= 4, "%d checks were in flight at once", maxInFlight.Load())
+	assert.Assert(t, maxInFlight.Load() > 1, "checks did not run concurrently")
+	for _, host := range widget.hosts {
+		assert.Assert(t, host.Up, host.Label)
+	}
+}
+
+func Test_doPings_Deadline(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	stubCheckHost(t, func(host Host) checkResult {
+		if host.Label == "host-1" {
+			<-release
+		}
+		return checkResult{}
+	})
+
+	widget := newPoolTestWidget(3, &Settings{
+		common:        &cfg.Common{RefreshInterval: 50 * time.Millisecond},
+		maxConcurrent: 3,
+	})
+	widget.hosts[1].Up = true
+
+	start := time.Now()
+	widget.doPings()
+
+	assert.Assert(t, time.Since(start) < time.Second)
+	assert.Assert(t, !widget.hosts[0].TimedOut)
+	assert.Assert(t, widget.hosts[1].TimedOut)
+	assert.Assert(t, widget.hosts[1].Up, "unchecked hosts keep their last result")
+	assert.Assert(t, !widget.hosts[2].TimedOut)
+
+	assert.Equal(t, "[white]host-1      : [red]TIMEOUT", widget.statusLine(1, 12, 0, 0))
+}
+
+func Test_doPings_CancelsProbes(t *testing.T) {
+	var cancelled atomic.Int32
+	checkStarted := make(chan struct{}, 2)
+
+	originalCheck := checkHost
+	t.Cleanup(func() { checkHost = originalCheck })
+	checkHost = func(ctx context.Context, host Host) checkResult {
+		if host.Label == "host-0" {
+			return checkResult{AvgRtt: time.Millisecond}
+		}
+
+		// Slower than its own timeout allows, like a host that doesn't answer
+		checkStarted <- struct{}{}
+		select {
+		case <-ctx.Done():
+			cancelled.Add(1)
+			return checkResult{PacketLoss: 100, Err: "timeout"}
+		case <-time.After(10 * time.Second):
+			return checkResult{}
+		}
+	}
+
+	widget := newPoolTestWidget(3, &Settings{
+		common:        &cfg.Common{RefreshInterval: 100 * time.Millisecond},
+		maxConcurrent: 3,
+	})
+	widget.hosts[2].Up = true
+	widget.hosts[2].AvgRtt = 1500 * time.Millisecond
+
+	start := time.Now()
+	checked := widget.doPings()
+
+	// The refresh is done on schedule, before the interval is up
+	assert.Assert(t, time.Since(start) < 100*time.Millisecond, "took %s", time.Since(start))
+	assert.DeepEqual(t, []bool{true, false, false}, checked)
+	assert.Assert(t, widget.hosts[0].Up && !widget.hosts[0].TimedOut)
+	assert.Assert(t, widget.hosts[1].TimedOut)
+	assert.Assert(t, widget.hosts[2].TimedOut)
+
+	// The probes still running are told to stop
+	<-checkStarted
+	<-checkStarted
+	for deadline := time.Now().Add(time.Second); cancelled.Load() < 2 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, int32(2), cancelled.Load())
+
+	// Timed out hosts don't show their last latency
+	widget.settings.showLatency = true
+	assert.Equal(t, "[white]host-0      : [green]Up   1ms\n"+
+		"[white]host-1      : [red]TIMEOUT\n"+
+		"[white]host-2      : [red]TIMEOUT", widget.content())
+
+	// The next check clears the timeout
+	widget.hosts[2].applyResult(checkResult{AvgRtt: time.Millisecond})
+	assert.Assert(t, !widget.hosts[2].TimedOut)
+}
+
+func Test_refreshDeadline(t *testing.T) {
+	assert.Equal(t, 45*time.Millisecond, refreshDeadline(50*time.Millisecond))
+	assert.Equal(t, 9*time.Second, refreshDeadline(10*time.Second))
+	assert.Equal(t, 58*time.Second, refreshDeadline(time.Minute))
+	assert.Equal(t, 298*time.Second, refreshDeadline(5*time.Minute))
+}
+
+func Test_Refresh_SingleFlight(t *testing.T) {
+	var calls atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	stubCheckHost(t, func(Host) checkResult {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return checkResult{}
+	})
+
+	widget := newPoolTestWidget(1, &Settings{
+		common:        &cfg.Common{RefreshInterval: time.Minute},
+		maxConcurrent: 1,
+	})
+
+	done := make(chan struct{})
+	go func() {
+		widget.Refresh()
+		close(done)
+	}()
+	<-started
+
+	// Returns straight away, without checking the host again
+	widget.Refresh()
+	assert.Equal(t, int32(1), calls.Load())
+
+	close(release)
+	<-done
+	<-widget.RedrawChan
+	assert.Assert(t, widget.hosts[0].Up)
+
+	// Once the first is done, refreshes run again
+	widget.Refresh()
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func Test_doPings_Jitter(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	stubCheckHost(t, func(Host) checkResult {
+		mu.Lock()
+		defer mu.Unlock()
+		times = append(times, time.Now())
+		return checkResult{}
+	})
+
+	widget := newPoolTestWidget(10, &Settings{
+		common:        &cfg.Common{RefreshInterval: time.Minute},
+		maxConcurrent: 10,
+		jitter:        100 * time.Millisecond,
+	})
+
+	widget.doPings()
+
+	assert.Equal(t, 10, len(times))
+	earliest, latest := times[0], times[0]
+	for _, checked := range times {
+		if checked.Before(earliest) {
+			earliest = checked
+		}
+		if checked.After(latest) {
+			latest = checked
+		}
+	}
+	assert.Assert(t, latest.Sub(earliest) > 10*time.Millisecond, "checks were not spread out")
+}
+
+func Test_doPings_Interval(t *testing.T) {
+	var mu sync.Mutex
+	probed := []string{}
+	stubCheckHost(t, func(host Host) checkResult {
+		mu.Lock()
+		defer mu.Unlock()
+		probed = append(probed, host.Label)
+		return checkResult{AvgRtt: time.Millisecond}
+	})
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	originalNow := nowFunc
+	t.Cleanup(func() { nowFunc = originalNow })
+	nowFunc = func() time.Time { return now }
+
+	widget := newPoolTestWidget(3, &Settings{
+		common:        &cfg.Common{RefreshInterval: 15 * time.Second},
+		maxConcurrent: 1,
+	})
+	widget.hosts[0].Label = "gateway" // every refresh
+	widget.hosts[1].Label = "office"
+	widget.hosts[1].Interval = 30 * time.Second
+	widget.hosts[2].Label = "far"
+	widget.hosts[2].Interval = time.Minute
+
+	// Intervals shorter than the refresh interval are checked on every refresh
+	widget.hosts = append(widget.hosts, Host{Label: "eager", Hostname: "eager", Interval: time.Second, LossThresholdPercent: 100})
+
+	ticks := [][]string{}
+	for tick := 0; tick < 6; tick++ {
+		probed = []string{}
+		widget.doPings()
+		sort.Strings(probed)
+		ticks = append(ticks, probed)
+		now = now.Add(15 * time.Second)
+	}
+
+	assert.DeepEqual(t, [][]string{
+		{"eager", "far", "gateway", "office"},
+		{"eager", "gateway"},
+		{"eager", "gateway", "office"},
+		{"eager", "gateway"},
+		{"eager", "far", "gateway", "office"},
+		{"eager", "gateway"},
+	}, ticks)
+
+	// Hosts that weren't due keep their result
+	assert.Assert(t, widget.hosts[2].Up)
+	assert.Equal(t, now.Add(-30*time.Second), widget.hosts[2].CheckedAt)
+}
+
+func Test_content_checkAge(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	originalNow := nowFunc
+	t.Cleanup(func() { nowFunc = originalNow })
+	nowFunc = func() time.Time { return now }
+
+	hosts := []Host{
+		{Label: "gateway", Up: true, AvgRtt: 2 * time.Millisecond, CheckedAt: now},
+		{Label: "far", Up: true, AvgRtt: 90 * time.Millisecond, Interval: 5 * time.Minute, CheckedAt: now.Add(-4*time.Minute - 30*time.Second)},
+		{Label: "office", Up: true, AvgRtt: 9 * time.Millisecond, Interval: time.Minute, CheckedAt: now},
+	}
+
+	widget := &Widget{hosts: hosts, refreshedAt: now, settings: &Settings{showLatency: true}}
+	assert.Equal(t,
+		"[white]gateway     : [green]Up    2ms\n"+
+			"[white]far         : [green]Up   90ms [gray](checked 4m30s ago)\n"+
+			"[white]office      : [green]Up    9ms",
+		widget.content(),
+	)
+}
+
+func Test_sleepJitter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	assert.Assert(t, sleepJitter(ctx, 0))
+	assert.Assert(t, sleepJitter(ctx, time.Millisecond))
+
+	cancel()
+	assert.Assert(t, !sleepJitter(ctx, 0))
+	assert.Assert(t, !sleepJitter(ctx, time.Hour))
+}
+
+func Test_content_resolution(t *testing.T) {
+	hosts := []Host{
+		{Label: "example.com (v4)", Hostname: "example.com", Up: true, AvgRtt: 20 * time.Millisecond, Addr: "93.184.215.14"},
+		{Label: "example.com (v6)", Hostname: "example.com", PacketLoss: 100, DNSErr: true, Err: "no such host"},
+		{Label: "router", Hostname: "192.168.1.1", Up: true, AvgRtt: 2 * time.Millisecond, Addr: "192.168.1.1"},
+	}
+
+	widget := &Widget{hosts: hosts, settings: &Settings{showLatency: true, showResolvedIP: true}}
+	assert.Equal(t,
+		"[white]example.com (v4)  : [green]Up   20ms [gray](93.184.215.14)\n"+
+			"[white]example.com (v6)  : [red]DNS ERR\n"+
+			"[white]router            : [green]Up    2ms",
+		widget.content(),
+	)
+}
+
+func Test_display_Banner(t *testing.T) {
+	widget := newPoolTestWidget(1, &Settings{common: &cfg.Common{}})
+	widget.View.SetRect(0, 0, 120, 10)
+	widget.hosts[0].Up = true
+
+	widget.exportErr = errors.New("could not export: permission denied")
+	widget.actionErr = errors.New("sshCommand failed: exit status 255")
+	widget.display()
+	<-widget.RedrawChan
+
+	lines := strings.Split(widget.View.GetText(true), "\n")
+	assert.Equal(t, "⚠ could not export: permission denied; sshCommand failed: exit status 255", lines[0])
+	assert.Equal(t, "host-0      : Up", lines[1])
+	assert.Equal(t, 1, len(widget.LastErrors()))
+
+	widget.exportErr = nil
+	widget.actionErr = nil
+	widget.display()
+	<-widget.RedrawChan
+
+	assert.Equal(t, "host-0      : Up", widget.View.GetText(true))
+}
+
+func Test_display_HostErrors(t *testing.T) {
+	widget := newPoolTestWidget(2, &Settings{common: &cfg.Common{}})
+	widget.View.SetRect(0, 0, 120, 10)
+	widget.hosts[0].Up = true
+	widget.hosts[1].Err = "timeout"
+
+	widget.uptimeErr = errors.New("could not read uptime: permission denied")
+	widget.display()
+	<-widget.RedrawChan
+
+	lines := strings.Split(widget.View.GetText(true), "\n")
+	assert.Equal(t, "⚠ host-1: timeout; could not read uptime: permission denied", lines[0])
+	assert.Equal(t, "host-1      : DOWN", lines[2])
+
+	// Each new set of errors is kept in the history
+	widget.hosts[0].Up, widget.hosts[0].Err = false, "connection refused"
+	widget.display()
+	<-widget.RedrawChan
+
+	errs := widget.LastErrors()
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, "2 hosts failed, last host-0: connection refused; could not read uptime: permission denied", errs[0].Message)
+	assert.Equal(t, "host-1: timeout; could not read uptime: permission denied", errs[1].Message)
+}
@@ -0,0 +1,53 @@
+package security
+
+import (
+	"fmt"
+	"maps"
+	"time"
+)
+
+// clone copies the data, so that it can be fetched into while the copy is being drawn. The
+// probes replace the values they return rather than modifying them, so it doesn't need to go
+// deeper than the maps
+func (data *SecurityData) clone() *SecurityData {
+	clone := *data
+	clone.Errors = maps.Clone(data.Errors)
+	clone.FetchedAt = maps.Clone(data.FetchedAt)
+	clone.Changed = maps.Clone(data.Changed)
+
+	return &clone
+}
+
+// expired returns whether the data of the section is older than ttl, or was never fetched
+func (data *SecurityData) expired(section string, ttl time.Duration, now time.Time) bool {
+	fetchedAt, ok := data.FetchedAt[section]
+	return !ok || !now.Before(fetchedAt.Add(ttl))
+}
+
+// snapshot renders the data of a section as a string, to tell whether it changed
+func (data *SecurityData) snapshot(section string) string {
+	var values []any
+
+	switch section {
+	case sectionCertificates:
+		values = []any{data.Certificates}
+	case sectionDNS:
+		values = []any{data.Dns, data.DNSHijack}
+	case sectionEncryption:
+		values = []any{data.Encryption}
+	case sectionFirewall:
+		values = []any{data.FirewallEnabled, data.FirewallStealth}
+	case sectionListening:
+		values = []any{data.Listening}
+	case sectionLogins:
+		values = []any{data.FailedLogins}
+	case sectionSSH:
+		values = []any{data.SSH}
+	case sectionUsers:
+		values = []any{data.LoggedInUsers, sessionsSnapshot(data.Sessions), data.AdminUsers, data.AccountCount, data.Sudo}
+	case sectionWifi:
+		values = []any{data.WifiName, data.WifiEncryption}
+	}
+
+	return fmt.Sprintf("%+v %v", values, data.Errors[section])
+}
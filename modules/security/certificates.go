@@ -0,0 +1,82 @@
+package security
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"sync"
+	"time"
+)
+
+// certificateTimeout is how long connecting to a host and the TLS handshake may take
+const certificateTimeout = 5 * time.Second
+
+// certificateRoots are the authorities the certificates are verified with, the system's
+// when nil. It is replaceable in tests
+var certificateRoots *x509.CertPool
+
+// Certificate is when the certificate a host presents expires, or why it couldn't be read
+type Certificate struct {
+	Target   string
+	NotAfter time.Time
+	Err      error
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+// CheckCertificates reads the certificates of the "host:port" targets concurrently, in order
+func CheckCertificates(targets []string) []Certificate {
+	certs := make([]Certificate, len(targets))
+
+	var wg sync.WaitGroup
+	for idx, target := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			certs[idx] = checkCertificate(target)
+		}()
+	}
+	wg.Wait()
+
+	return certs
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// checkCertificate connects to the target and reads its leaf certificate. The certificate
+// is verified as usual, so an invalid one is reported as the error of the handshake
+func checkCertificate(target string) Certificate {
+	cert := Certificate{Target: target}
+
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		// No port, as in "example.com"
+		host = target
+		target = net.JoinHostPort(target, "443")
+	}
+
+	dialer := &net.Dialer{Timeout: certificateTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", target, &tls.Config{
+		ServerName: host,
+		RootCAs:    certificateRoots,
+	})
+	if err != nil {
+		cert.Err = err
+		return cert
+	}
+	defer func() { _ = conn.Close() }()
+
+	cert.NotAfter = conn.ConnectionState().PeerCertificates[0].NotAfter
+	return cert
+}
+
+// daysLeft returns how many whole days are left until the certificate expires, which is
+// negative once it has
+func (cert Certificate) daysLeft(now time.Time) int {
+	left := cert.NotAfter.Sub(now)
+	if left < 0 {
+		return -int((-left).Hours()/24) - 1
+	}
+
+	return int(left.Hours() / 24)
+}
@@ -0,0 +1,151 @@
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+// serveCertificate listens with TLS on localhost, presenting a self-signed certificate that
+// expires at notAfter, and trusts it for the rest of the test
+func serveCertificate(t *testing.T, notAfter time.Time) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NilError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	assert.NilError(t, err)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(leaf)
+
+	original := certificateRoots
+	t.Cleanup(func() { certificateRoots = original })
+	certificateRoots = roots
+
+	return listener.Addr().String()
+}
+
+// unreachableAddr returns the address of a port nothing listens on
+func unreachableAddr(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+
+	addr := listener.Addr().String()
+	assert.NilError(t, listener.Close())
+
+	return addr
+}
+
+func Test_CheckCertificates(t *testing.T) {
+	notAfter := time.Now().Add(3*24*time.Hour + time.Hour).Truncate(time.Second)
+	trusted := serveCertificate(t, notAfter)
+	unreachable := unreachableAddr(t)
+
+	certs := CheckCertificates([]string{trusted, unreachable})
+
+	assert.Equal(t, 2, len(certs))
+
+	assert.Equal(t, trusted, certs[0].Target)
+	assert.NilError(t, certs[0].Err)
+	assert.Assert(t, certs[0].NotAfter.Equal(notAfter), certs[0].NotAfter)
+	assert.Equal(t, 3, certs[0].daysLeft(time.Now()))
+
+	assert.Equal(t, unreachable, certs[1].Target)
+	assert.ErrorContains(t, certs[1].Err, "connection refused")
+}
+
+func Test_CheckCertificates_Untrusted(t *testing.T) {
+	addr := serveCertificate(t, time.Now().Add(90*24*time.Hour))
+	certificateRoots = x509.NewCertPool()
+
+	certs := CheckCertificates([]string{addr})
+	assert.ErrorContains(t, certs[0].Err, "certificate signed by unknown authority")
+}
+
+func Test_certificateLabel(t *testing.T) {
+	widget, _ := newTestWidget(t, "enabled: true", nil)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		cert     Certificate
+		expected string
+	}{
+		{name: "far off", cert: Certificate{NotAfter: now.Add(90 * 24 * time.Hour)}, expected: "90 days"},
+		{name: "at the warning", cert: Certificate{NotAfter: now.Add(21 * 24 * time.Hour)}, expected: "21 days"},
+		{name: "under the warning", cert: Certificate{NotAfter: now.Add(20 * 24 * time.Hour)}, expected: "[yellow]20 days[white]"},
+		{name: "under the critical", cert: Certificate{NotAfter: now.Add(6*24*time.Hour + time.Hour)}, expected: "[red]6 days[white]"},
+		{name: "expiring today", cert: Certificate{NotAfter: now.Add(time.Hour)}, expected: "[red]0 days[white]"},
+		{name: "expired", cert: Certificate{NotAfter: now.Add(-time.Hour)}, expected: "[red]expired[white]"},
+		{name: "handshake failure", cert: Certificate{Err: &net.OpError{Op: "dial", Err: errExitStatus}}, expected: "[red]dial: exit status 1[white]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, widget.certificateLabel(tt.cert, now))
+		})
+	}
+}
+
+func Test_certificatesSection(t *testing.T) {
+	widget, _ := newTestWidget(t, "enabled: true", nil)
+
+	assert.Equal(t, "", widget.certificatesSection(NewSecurityData()))
+
+	data := NewSecurityData()
+	data.Certificates = []Certificate{
+		{Target: "example.com:443", NotAfter: time.Now().Add(48 * time.Hour)},
+		{Target: "a.io", NotAfter: time.Now().Add(-time.Hour)},
+	}
+
+	lines := strings.Split(widget.certificatesSection(data), "\n")
+	assert.Equal(t, "  example.com:443: [red]1 day[white]", lines[1])
+	assert.Equal(t, "  a.io           : [red]expired[white]", lines[2])
+}
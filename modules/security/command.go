@@ -0,0 +1,105 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// errNotInstalled is wrapped by the errors of commands that aren't installed, so that probes
+// can fall back to another tool
+var errNotInstalled = errors.New("not installed")
+
+const (
+	// defaultCommandTimeout is how long a command may run before it is killed, so that a
+	// tool that hangs doesn't hold up the other probes
+	defaultCommandTimeout = 5 * time.Second
+
+	// commandWaitDelay is how long to wait for the output of a killed command to be closed,
+	// as children it started may hold on to it
+	commandWaitDelay = 500 * time.Millisecond
+)
+
+// CommandRunner runs the commands the probes shell out to, and returns what they print
+type CommandRunner interface {
+	Run(name string, args ...string) (string, error)
+}
+
+// CommandError is why a command failed, along with what it printed to stderr
+type CommandError struct {
+	Name    string
+	Stderr  string
+	Timeout time.Duration
+	Err     error
+}
+
+func (cmdErr *CommandError) Error() string {
+	stderr := strings.TrimSpace(cmdErr.Stderr)
+
+	switch {
+	case errors.Is(cmdErr.Err, errNotInstalled):
+		return fmt.Sprintf("%s %s", cmdErr.Name, errNotInstalled)
+	case errors.Is(cmdErr.Err, context.DeadlineExceeded):
+		return fmt.Sprintf("%s: timed out after %s", cmdErr.Name, cmdErr.Timeout)
+	case stderr != "":
+		return fmt.Sprintf("%s: %s", cmdErr.Name, strings.SplitN(stderr, "\n", 2)[0])
+	default:
+		return fmt.Sprintf("%s: %s", cmdErr.Name, cmdErr.Err)
+	}
+}
+
+func (cmdErr *CommandError) Unwrap() error {
+	return cmdErr.Err
+}
+
+// execRunner runs commands on this machine, killing those that run longer than its timeout
+type execRunner struct {
+	timeout time.Duration
+}
+
+func newExecRunner(timeout time.Duration) execRunner {
+	if timeout <= 0 {
+		timeout = defaultCommandTimeout
+	}
+
+	return execRunner{timeout: timeout}
+}
+
+func (runner execRunner) Run(name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), runner.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = commandWaitDelay
+
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		cmdErr := commandError(name, stderr.String(), err)
+		cmdErr.Timeout = runner.timeout
+		return stdout.String(), cmdErr
+	}
+
+	return stdout.String(), nil
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// commandError wraps why a command failed with what it printed to stderr
+func commandError(name, stderr string, err error) *CommandError {
+	if errors.Is(err, exec.ErrNotFound) {
+		err = fmt.Errorf("%w: %w", errNotInstalled, err)
+	}
+
+	return &CommandError{Name: name, Stderr: stderr, Err: err}
+}
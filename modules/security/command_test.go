@@ -0,0 +1,115 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+// cannedOutput is what a faked command prints, and how it fails
+type cannedOutput struct {
+	out string
+	err error
+}
+
+// fakeRunner runs commands by returning their canned outputs, by command line. Commands
+// without one behave as if they weren't installed
+type fakeRunner map[string]cannedOutput
+
+func (runner fakeRunner) Run(name string, args ...string) (string, error) {
+	canned, ok := runner[strings.Join(append([]string{name}, args...), " ")]
+	if !ok {
+		return "", commandError(name, "", exec.ErrNotFound)
+	}
+	if canned.err != nil {
+		return canned.out, commandError(name, "", canned.err)
+	}
+
+	return canned.out, nil
+}
+
+// errExitStatus is how a command that exits with an error fails
+var errExitStatus = errors.New("exit status 1")
+
+func Test_commandError(t *testing.T) {
+	err := commandError("ufw", "", &exec.Error{Name: "ufw", Err: exec.ErrNotFound})
+	assert.Equal(t, "ufw not installed", err.Error())
+	assert.Assert(t, errors.Is(err, errNotInstalled))
+	assert.Assert(t, errors.Is(err, exec.ErrNotFound))
+
+	err = commandError("nmcli", "Error: NetworkManager is not running.\nmore\n", errExitStatus)
+	assert.Equal(t, "nmcli: Error: NetworkManager is not running.", err.Error())
+	assert.Assert(t, !errors.Is(err, errNotInstalled))
+
+	err = commandError("netsh", "", context.DeadlineExceeded)
+	err.Timeout = 5 * time.Second
+	assert.Equal(t, "netsh: timed out after 5s", err.Error())
+
+	err = commandError("who", "", errExitStatus)
+	assert.Equal(t, "who: exit status 1", err.Error())
+	assert.Assert(t, errors.Is(err, errExitStatus))
+}
+
+func skipWithoutShell(t *testing.T) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+}
+
+func Test_execRunner_Stderr(t *testing.T) {
+	skipWithoutShell(t)
+
+	out, err := newExecRunner(time.Second).Run("sh", "-c", "echo partial; echo 'permission denied' >&2; echo more >&2; exit 3")
+
+	assert.Equal(t, "partial\n", out)
+	assert.Equal(t, "sh: permission denied", err.Error())
+
+	var cmdErr *CommandError
+	assert.Assert(t, errors.As(err, &cmdErr))
+	assert.Equal(t, "permission denied\nmore\n", cmdErr.Stderr)
+
+	var exitErr *exec.ExitError
+	assert.Assert(t, errors.As(err, &exitErr))
+	assert.Equal(t, 3, exitErr.ExitCode())
+
+	// Successful commands don't fail because of what they print to stderr
+	out, err = newExecRunner(time.Second).Run("sh", "-c", "echo warning >&2; echo ok")
+	assert.NilError(t, err)
+	assert.Equal(t, "ok\n", out)
+}
+
+func Test_execRunner_Timeout(t *testing.T) {
+	skipWithoutShell(t)
+
+	started := time.Now()
+	// The background sleep keeps the output open after sh is killed
+	_, err := newExecRunner(100*time.Millisecond).Run("sh", "-c", "echo waiting >&2; sleep 10 & sleep 10")
+
+	assert.Assert(t, time.Since(started) < 3*time.Second, "took %s", time.Since(started))
+	assert.Equal(t, "sh: timed out after 100ms", err.Error())
+	assert.Assert(t, errors.Is(err, context.DeadlineExceeded))
+
+	var cmdErr *CommandError
+	assert.Assert(t, errors.As(err, &cmdErr))
+	assert.Equal(t, "waiting\n", cmdErr.Stderr)
+}
+
+func Test_execRunner_NotInstalled(t *testing.T) {
+	_, err := newExecRunner(time.Second).Run("wtf-no-such-command")
+
+	assert.Equal(t, "wtf-no-such-command not installed", err.Error())
+	assert.Assert(t, errors.Is(err, errNotInstalled))
+}
+
+func Test_newExecRunner(t *testing.T) {
+	assert.Equal(t, defaultCommandTimeout, newExecRunner(0).timeout)
+	assert.Equal(t, 2*time.Second, newExecRunner(2*time.Second).timeout)
+}
@@ -1,66 +1,189 @@
 package security
 
 import (
-	"os/exec"
+	"net/netip"
+	"os"
 	"runtime"
+	"slices"
 	"strings"
-
-	"github.com/wtfutil/wtf/utils"
 )
 
+// resolvConfPath is where the resolver configuration is read from on Linux
+var resolvConfPath = "/etc/resolv.conf"
+
 /* -------------------- Exported Functions -------------------- */
 
-func DnsServers() []string {
+func DnsServers(commands CommandRunner) ([]string, error) {
 	switch runtime.GOOS {
 	case "linux":
-		return dnsLinux()
+		return dnsLinux(commands)
 	case "darwin":
-		return dnsMacOS()
+		return dnsMacOS(commands)
 	case "windows":
-		return dnsWindows()
+		return dnsWindows(commands)
 	default:
-		return []string{runtime.GOOS}
+		return []string{runtime.GOOS}, nil
 	}
 }
 
 /* -------------------- Unexported Functions -------------------- */
 
-func dnsLinux() []string {
-	// This may be very Ubuntu specific
-	cmd := exec.Command("nmcli", "device", "show")
-	out := utils.ExecuteCommand(cmd)
+// dnsLinux returns the name servers in resolv.conf or, when that only points at the local
+// systemd-resolved stub, the ones systemd-resolved forwards to
+func dnsLinux(commands CommandRunner) ([]string, error) {
+	servers, err := resolvConfServers(resolvConfPath)
+	if err != nil {
+		if upstream, resolvectlErr := resolvectlServers(commands); resolvectlErr == nil {
+			return upstream, nil
+		}
+		return nil, err
+	}
 
-	lines := strings.Split(out, "\n")
+	if !onlyResolvedStub(servers) {
+		return servers, nil
+	}
+
+	upstream, err := resolvectlServers(commands)
+	if err != nil || len(upstream) == 0 {
+		return servers, nil
+	}
+
+	return upstream, nil
+}
+
+// resolvConfServers returns the name servers listed in a resolv.conf file
+func resolvConfServers(path string) ([]string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	servers := []string{}
+	for _, line := range strings.Split(string(contents), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			servers = append(servers, fields[1])
+		}
+	}
+
+	return servers, nil
+}
+
+// onlyResolvedStub returns whether the servers are just systemd-resolved's local stub
+func onlyResolvedStub(servers []string) bool {
+	if len(servers) == 0 {
+		return false
+	}
+
+	for _, server := range servers {
+		if server != "127.0.0.53" && server != "127.0.0.54" {
+			return false
+		}
+	}
+
+	return true
+}
 
-	dns := []string{}
+// resolvectlServers returns the servers systemd-resolved uses, globally and for every link,
+// without duplicates
+func resolvectlServers(commands CommandRunner) ([]string, error) {
+	out, err := commands.Run("resolvectl", "dns")
+	if err != nil {
+		return nil, err
+	}
+
+	servers := []string{}
+	seen := map[string]bool{}
+
+	// Lines look like "Link 2 (wlan0): 192.168.1.1 fe80::1%2"
+	for _, line := range strings.Split(out, "\n") {
+		_, list, ok := strings.Cut(line, "):")
+		if !ok {
+			_, list, ok = strings.Cut(line, "Global:")
+		}
+		if !ok {
+			continue
+		}
 
-	for _, l := range lines {
-		if strings.HasPrefix(l, "IP4.DNS") {
-			parts := strings.Split(l, ":")
-			dns = append(dns, strings.TrimSpace(parts[1]))
+		for _, server := range strings.Fields(list) {
+			if !seen[server] {
+				seen[server] = true
+				servers = append(servers, server)
+			}
 		}
 	}
 
-	return dns
+	return servers, nil
 }
 
-func dnsMacOS() []string {
+func dnsMacOS(commands CommandRunner) ([]string, error) {
 	cmdString := `scutil --dns | head -n 7 | grep -o '[0-9]\{1,3\}\.[0-9]\{1,3\}\.[0-9]\{1,3\}\.[0-9]\{1,3\}'`
-	cmd := exec.Command("sh", "-c", cmdString)
-	out := utils.ExecuteCommand(cmd)
+	out, err := commands.Run("sh", "-c", cmdString)
+	if err != nil {
+		return nil, err
+	}
 
 	lines := strings.Split(out, "\n")
 
 	if len(lines) > 0 {
-		return lines
+		return lines, nil
+	}
+
+	return []string{}, nil
+}
+
+// dnsWindows returns the servers of every interface, without duplicates, asking PowerShell
+// or, when that fails, reading them from ipconfig
+func dnsWindows(commands CommandRunner) ([]string, error) {
+	out, err := commands.Run("powershell.exe", "-NoProfile", "-Command", "Get-DnsClientServerAddress | Select-Object -ExpandProperty ServerAddresses")
+	if err == nil {
+		return uniqueServers(strings.Fields(out)), nil
+	}
+
+	ipconfig, ipconfigErr := commands.Run("ipconfig", "/all")
+	if ipconfigErr != nil {
+		return nil, err
 	}
 
-	return []string{}
+	return parseIpconfigDNS(ipconfig), nil
 }
 
-func dnsWindows() []string {
+// parseIpconfigDNS returns the servers "ipconfig /all" lists, without duplicates. The first
+// server of an interface is on its "DNS Servers" line, the others on the lines after it
+func parseIpconfigDNS(out string) []string {
+	servers := []string{}
+	inServers := false
 
-	cmd := exec.Command("powershell.exe", "-NoProfile", "Get-DnsClientServerAddress | Select-Object –ExpandProperty ServerAddresses")
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+
+		if key, value, ok := strings.Cut(line, " : "); ok {
+			inServers = strings.HasPrefix(key, "DNS Servers")
+			line = strings.TrimSpace(value)
+		}
+
+		if !inServers {
+			continue
+		}
+		if _, err := netip.ParseAddr(line); err != nil {
+			inServers = false
+			continue
+		}
+
+		servers = append(servers, line)
+	}
+
+	return uniqueServers(servers)
+}
+
+// uniqueServers returns the servers without duplicates, in order
+func uniqueServers(servers []string) []string {
+	unique := []string{}
+	for _, server := range servers {
+		if !slices.Contains(unique, server) {
+			unique = append(unique, server)
+		}
+	}
 
-	return []string{utils.ExecuteCommand(cmd)}
+	return unique
 }
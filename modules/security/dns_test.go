@@ -0,0 +1,162 @@
+package security
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const resolvectlOutput = `Global:
+Link 2 (enp0s31f6): 192.168.1.1 fe80::1%2
+Link 3 (wlp2s0): 192.168.1.1 1.1.1.1
+Link 4 (docker0):
+`
+
+func useResolvConf(t *testing.T, contents string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "resolv.conf")
+	if contents != "" {
+		assert.NilError(t, os.WriteFile(path, []byte(contents), 0o644))
+	}
+
+	original := resolvConfPath
+	t.Cleanup(func() { resolvConfPath = original })
+	resolvConfPath = path
+}
+
+func Test_dnsLinux(t *testing.T) {
+	tests := []struct {
+		name       string
+		resolvConf string
+		outputs    map[string]cannedOutput
+		expected   []string
+		err        bool
+	}{
+		{
+			name:       "resolv.conf",
+			resolvConf: "# Generated by NetworkManager\nsearch lan\nnameserver 9.9.9.9\nnameserver  149.112.112.112\n",
+			outputs: map[string]cannedOutput{
+				"resolvectl dns": {out: resolvectlOutput},
+			},
+			expected: []string{"9.9.9.9", "149.112.112.112"},
+		},
+		{
+			name:       "systemd-resolved stub",
+			resolvConf: "nameserver 127.0.0.53\noptions edns0 trust-ad\n",
+			outputs: map[string]cannedOutput{
+				"resolvectl dns": {out: resolvectlOutput},
+			},
+			expected: []string{"192.168.1.1", "fe80::1%2", "1.1.1.1"},
+		},
+		{
+			name:       "systemd-resolved stub without resolvectl",
+			resolvConf: "nameserver 127.0.0.53\n",
+			outputs:    map[string]cannedOutput{},
+			expected:   []string{"127.0.0.53"},
+		},
+		{
+			name: "no resolv.conf",
+			outputs: map[string]cannedOutput{
+				"resolvectl dns": {out: resolvectlOutput},
+			},
+			expected: []string{"192.168.1.1", "fe80::1%2", "1.1.1.1"},
+		},
+		{
+			name:    "no resolv.conf nor resolvectl",
+			outputs: map[string]cannedOutput{},
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useResolvConf(t, tt.resolvConf)
+			commands := fakeRunner(tt.outputs)
+
+			servers, err := dnsLinux(commands)
+			if tt.err {
+				assert.Assert(t, os.IsNotExist(err), err)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tt.expected, servers)
+		})
+	}
+}
+
+const ipconfigOutput = `
+Windows IP Configuration
+
+   Host Name . . . . . . . . . . . . : DESKTOP-1234
+   Primary Dns Suffix  . . . . . . . :
+
+Ethernet adapter Ethernet:
+
+   Physical Address. . . . . . . . . : 3C-58-C2-AA-BB-CC
+   DHCP Enabled. . . . . . . . . . . : Yes
+   IPv4 Address. . . . . . . . . . . : 192.168.1.20(Preferred)
+   Default Gateway . . . . . . . . . : 192.168.1.1
+   DNS Servers . . . . . . . . . . . : fe80::1%12
+                                       192.168.1.1
+                                       1.1.1.1
+   NetBIOS over Tcpip. . . . . . . . : Enabled
+
+Wireless LAN adapter Wi-Fi:
+
+   DNS Servers . . . . . . . . . . . : 192.168.1.1
+   NetBIOS over Tcpip. . . . . . . . : Enabled
+`
+
+const dnsPowerShellCommand = "powershell.exe -NoProfile -Command Get-DnsClientServerAddress | Select-Object -ExpandProperty ServerAddresses"
+
+func Test_dnsWindows(t *testing.T) {
+	tests := []struct {
+		name     string
+		outputs  map[string]cannedOutput
+		expected []string
+		err      string
+	}{
+		{
+			name: "PowerShell",
+			outputs: map[string]cannedOutput{
+				dnsPowerShellCommand: {out: "192.168.1.1\r\n1.1.1.1\r\n192.168.1.1\r\n"},
+			},
+			expected: []string{"192.168.1.1", "1.1.1.1"},
+		},
+		{
+			name: "ipconfig when PowerShell fails",
+			outputs: map[string]cannedOutput{
+				dnsPowerShellCommand: {err: errExitStatus},
+				"ipconfig /all":      {out: strings.ReplaceAll(ipconfigOutput, "\n", "\r\n")},
+			},
+			expected: []string{"fe80::1%12", "192.168.1.1", "1.1.1.1"},
+		},
+		{
+			name: "both failing",
+			outputs: map[string]cannedOutput{
+				dnsPowerShellCommand: {err: errExitStatus},
+			},
+			err: "powershell.exe: exit status 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands := fakeRunner(tt.outputs)
+
+			servers, err := dnsWindows(commands)
+			if tt.err != "" {
+				assert.Error(t, err, tt.err)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tt.expected, servers)
+		})
+	}
+}
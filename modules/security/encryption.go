@@ -0,0 +1,171 @@
+package security
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+)
+
+const (
+	encryptionEnabled  = "Enabled"
+	encryptionDisabled = "Disabled"
+	encryptionUnknown  = "unknown (needs sudo)"
+)
+
+// DiskEncryption is whether a volume is encrypted
+type DiskEncryption struct {
+	Volume string
+	State  string
+	// Boot is set for the volume the system runs from
+	Boot bool
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+func DiskEncryptionState(commands CommandRunner) ([]DiskEncryption, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return diskEncryptionMacOS(commands)
+	case "linux":
+		return diskEncryptionLinux(commands)
+	default:
+		return []DiskEncryption{}, nil
+	}
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+func diskEncryptionMacOS(commands CommandRunner) ([]DiskEncryption, error) {
+	out, err := commands.Run("fdesetup", "status")
+	if needsPrivileges(err) {
+		return []DiskEncryption{{Volume: "/", State: encryptionUnknown, Boot: true}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state := encryptionDisabled
+	if strings.Contains(out, "FileVault is On") {
+		state = encryptionEnabled
+	}
+
+	return []DiskEncryption{{Volume: "/", State: state, Boot: true}}, nil
+}
+
+// diskEncryptionLinux reports every mounted volume as encrypted when it sits on a dm-crypt
+// device, looking at the tree of block devices
+func diskEncryptionLinux(commands CommandRunner) ([]DiskEncryption, error) {
+	out, err := commands.Run("lsblk", "-r", "-n", "-o", "NAME,KNAME,PKNAME,TYPE,FSTYPE,MOUNTPOINT")
+	if errors.Is(err, errNotInstalled) {
+		return diskEncryptionDmsetup(commands)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	type blockDevice struct {
+		name, parent, kind, mountpoint string
+	}
+
+	devices := map[string]blockDevice{}
+	order := []string{}
+
+	for _, line := range strings.Split(out, "\n") {
+		// Empty columns are left empty, between single spaces
+		fields := strings.Split(line, " ")
+		if len(fields) != 6 {
+			continue
+		}
+
+		devices[fields[1]] = blockDevice{
+			name:       fields[0],
+			parent:     fields[2],
+			kind:       fields[3],
+			mountpoint: unescapeLsblk(fields[5]),
+		}
+		order = append(order, fields[1])
+	}
+
+	volumes := []DiskEncryption{}
+	for _, kname := range order {
+		device := devices[kname]
+		if !relevantMountpoint(device.mountpoint) || device.kind == "loop" || device.kind == "rom" {
+			continue
+		}
+
+		state := encryptionDisabled
+		// The parent chain is short, the limit only guards against a malformed output
+		for parent, depth := kname, 0; parent != "" && depth < 16; parent, depth = devices[parent].parent, depth+1 {
+			if devices[parent].kind == "crypt" {
+				state = encryptionEnabled
+				break
+			}
+		}
+
+		volumes = append(volumes, DiskEncryption{Volume: device.mountpoint, State: state, Boot: device.mountpoint == "/"})
+	}
+
+	return volumes, nil
+}
+
+// diskEncryptionDmsetup reports whether there are dm-crypt devices, for systems without lsblk
+func diskEncryptionDmsetup(commands CommandRunner) ([]DiskEncryption, error) {
+	out, err := commands.Run("dmsetup", "table", "--target", "crypt")
+	if needsPrivileges(err) {
+		return []DiskEncryption{{Volume: "disks", State: encryptionUnknown}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := []DiskEncryption{}
+	for _, line := range strings.Split(out, "\n") {
+		// Lines look like "luks-3f2a…: 0 1951133696 crypt aes-xts-plain64 …"
+		name, target, ok := strings.Cut(line, ":")
+		if ok && strings.Contains(target, " crypt ") {
+			volumes = append(volumes, DiskEncryption{Volume: name, State: encryptionEnabled})
+		}
+	}
+
+	if len(volumes) == 0 {
+		return []DiskEncryption{{Volume: "disks", State: encryptionDisabled}}, nil
+	}
+
+	return volumes, nil
+}
+
+// relevantMountpoint returns whether a volume mounted there holds data worth encrypting,
+// which rules out swap and the boot partitions the firmware has to read
+func relevantMountpoint(mountpoint string) bool {
+	if !strings.HasPrefix(mountpoint, "/") {
+		return false
+	}
+
+	switch mountpoint {
+	case "/boot", "/boot/efi", "/efi":
+		return false
+	}
+
+	return true
+}
+
+// unescapeLsblk undoes the escaping of spaces in lsblk's raw output
+func unescapeLsblk(value string) string {
+	return strings.ReplaceAll(value, `\x20`, " ")
+}
+
+// needsPrivileges returns whether a command failed because it has to run as root
+func needsPrivileges(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, hint := range []string{"permission denied", "must be root", "operation not permitted", "run as root", "sudo"} {
+		if strings.Contains(msg, hint) {
+			return true
+		}
+	}
+
+	return false
+}
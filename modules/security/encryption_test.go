@@ -0,0 +1,111 @@
+package security
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const lsblkCommand = "lsblk -r -n -o NAME,KNAME,PKNAME,TYPE,FSTYPE,MOUNTPOINT"
+
+func Test_diskEncryptionLinux(t *testing.T) {
+	tests := []struct {
+		name     string
+		outputs  map[string]cannedOutput
+		expected []DiskEncryption
+	}{
+		{
+			name: "LVM on LUKS",
+			outputs: map[string]cannedOutput{
+				lsblkCommand: {out: "nvme0n1 nvme0n1  disk  \n" +
+					"nvme0n1p1 nvme0n1p1 nvme0n1 part vfat /boot/efi\n" +
+					"nvme0n1p2 nvme0n1p2 nvme0n1 part ext4 /boot\n" +
+					"nvme0n1p3 nvme0n1p3 nvme0n1 part crypto_LUKS \n" +
+					"luks-3f2a dm-0 nvme0n1p3 crypt LVM2_member \n" +
+					"vg-root dm-1 dm-0 lvm ext4 /\n" +
+					"vg-swap dm-2 dm-0 lvm swap [SWAP]\n" +
+					"loop0 loop0  loop squashfs /snap/core/123\n"},
+			},
+			expected: []DiskEncryption{{Volume: "/", State: encryptionEnabled, Boot: true}},
+		},
+		{
+			name: "unencrypted",
+			outputs: map[string]cannedOutput{
+				lsblkCommand: {out: "sda sda  disk  \n" +
+					"sda1 sda1 sda part ext4 /\n" +
+					"sdb sdb  disk  \n" +
+					"sdb1 sdb1 sdb part crypto_LUKS \n" +
+					"backup dm-0 sdb1 crypt ext4 /mnt/my\\x20backup\n"},
+			},
+			expected: []DiskEncryption{
+				{Volume: "/", State: encryptionDisabled, Boot: true},
+				{Volume: "/mnt/my backup", State: encryptionEnabled},
+			},
+		},
+		{
+			name: "dmsetup without lsblk",
+			outputs: map[string]cannedOutput{
+				"dmsetup table --target crypt": {out: "luks-3f2a: 0 1951133696 crypt aes-xts-plain64 :64:logon:cryptsetup:3f2a-d0 0 259:3 32768\n"},
+			},
+			expected: []DiskEncryption{{Volume: "luks-3f2a", State: encryptionEnabled}},
+		},
+		{
+			name: "dmsetup without crypt devices",
+			outputs: map[string]cannedOutput{
+				"dmsetup table --target crypt": {out: "No devices found\n"},
+			},
+			expected: []DiskEncryption{{Volume: "disks", State: encryptionDisabled}},
+		},
+		{
+			name: "dmsetup without root",
+			outputs: map[string]cannedOutput{
+				"dmsetup table --target crypt": {err: errors.New("/dev/mapper/control: open failed: Permission denied")},
+			},
+			expected: []DiskEncryption{{Volume: "disks", State: encryptionUnknown}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands := fakeRunner(tt.outputs)
+
+			volumes, err := diskEncryptionLinux(commands)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tt.expected, volumes)
+		})
+	}
+}
+
+func Test_diskEncryptionMacOS(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   cannedOutput
+		expected string
+	}{
+		{name: "on", output: cannedOutput{out: "FileVault is On.\n"}, expected: encryptionEnabled},
+		{name: "off", output: cannedOutput{out: "FileVault is Off.\n"}, expected: encryptionDisabled},
+		{name: "without root", output: cannedOutput{err: errors.New("This command must be run as root")}, expected: encryptionUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands := fakeRunner(map[string]cannedOutput{"fdesetup status": tt.output})
+
+			volumes, err := diskEncryptionMacOS(commands)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, []DiskEncryption{{Volume: "/", State: tt.expected, Boot: true}}, volumes)
+		})
+	}
+
+	commands := fakeRunner(map[string]cannedOutput{})
+	_, err := diskEncryptionMacOS(commands)
+	assert.Error(t, err, "fdesetup not installed")
+}
+
+func Test_encryptionLabel(t *testing.T) {
+	assert.Equal(t, "[red]Disabled[white]", encryptionLabel(DiskEncryption{State: encryptionDisabled, Boot: true}))
+	assert.Equal(t, "Disabled", encryptionLabel(DiskEncryption{State: encryptionDisabled}))
+	assert.Equal(t, "[green]Enabled[white]", encryptionLabel(DiskEncryption{State: encryptionEnabled, Boot: true}))
+	assert.Equal(t, "[yellow]unknown (needs sudo)[white]", encryptionLabel(DiskEncryption{State: encryptionUnknown}))
+}
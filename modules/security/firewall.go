@@ -1,111 +1,121 @@
 package security
 
 import (
-	"os/exec"
+	"errors"
+	"fmt"
 	"runtime"
 	"strings"
-
-	"github.com/wtfutil/wtf/utils"
 )
 
 const osxFirewallCmd = "/usr/libexec/ApplicationFirewall/socketfilterfw"
 
 /* -------------------- Exported Functions -------------------- */
 
-func FirewallState() string {
+func FirewallState(commands CommandRunner) (string, error) {
 	switch runtime.GOOS {
 	case "darwin":
-		return firewallStateMacOS()
+		return firewallStateMacOS(commands)
 	case "linux":
-		return firewallStateLinux()
+		return firewallStateLinux(commands), nil
 	case "windows":
-		return firewallStateWindows()
+		return firewallStateWindows(commands)
 	default:
-		return ""
+		return "", nil
 	}
 }
 
-func FirewallStealthState() string {
+func FirewallStealthState(commands CommandRunner) (string, error) {
 	switch runtime.GOOS {
 	case "linux":
-		return firewallStealthStateLinux()
+		return firewallStealthStateLinux(), nil
 	case "darwin":
-		return firewallStealthStateMacOS()
+		return firewallStealthStateMacOS(commands)
 	case "windows":
-		return firewallStealthStateWindows()
+		return firewallStealthStateWindows(), nil
 	default:
-		return ""
+		return "", nil
 	}
 }
 
 /* -------------------- Unexported Functions -------------------- */
 
-func firewallStateLinux() string {
-	// Check UFW first
-	if hasUfw := checkUfw(); hasUfw != "" {
-		return hasUfw
+// firewallStateLinux reports the first firewall found, trying the front-ends before the
+// packet filters they manage
+func firewallStateLinux(commands CommandRunner) string {
+	for _, check := range []func(CommandRunner) string{checkUfw, checkFirewalld, checkNftables, checkIptables} {
+		if state := check(commands); state != "" {
+			return state
+		}
 	}
 
-	// Check nftables
-	if hasNft := checkNftables(); hasNft != "" {
-		return hasNft
+	return "[red]No firewall[white]"
+}
+
+func checkUfw(commands CommandRunner) string {
+	out, err := commands.Run("ufw", "status")
+	if errors.Is(err, errNotInstalled) {
+		return ""
 	}
 
-	// Check iptables as last resort
-	if hasIpt := checkIptables(); hasIpt != "" {
-		return hasIpt
+	switch {
+	case strings.Contains(out, "Status: active"):
+		return "[green]Enabled (ufw)[white]"
+	case strings.Contains(out, "Status: inactive"):
+		return "[red]Disabled (ufw)[white]"
 	}
 
-	return "[red]No firewall[white]"
+	// "ufw status" needs root, the state of the service is the next best thing
+	if serviceActive(commands, "ufw") {
+		return "[green]Enabled (ufw)[white]"
+	}
+	return "[red]Disabled (ufw)[white]"
 }
 
-func checkUfw() string {
-	// First check if UFW is installed
-	checkInstalled := exec.Command("which", "ufw")
-	if err := checkInstalled.Run(); err != nil {
+func checkFirewalld(commands CommandRunner) string {
+	// Prints "not running", and exits with an error, when the daemon is stopped
+	out, err := commands.Run("firewall-cmd", "--state")
+	if errors.Is(err, errNotInstalled) {
 		return ""
 	}
 
-	// Then check if service is running
-	cmd := exec.Command("systemctl", "is-active", "ufw")
-	err := cmd.Run()
-	if err == nil {
-		return "[green]Enabled (ufw)[white]"
+	if err == nil && strings.TrimSpace(out) == "running" {
+		return "[green]Enabled (firewalld)[white]"
 	}
-	return "[red]Disabled (ufw)[white]"
+	return "[red]Disabled (firewalld)[white]"
 }
 
-func checkNftables() string {
-	// First check if nftables is installed
-	checkInstalled := exec.Command("which", "nft")
-	if err := checkInstalled.Run(); err != nil {
+func checkNftables(commands CommandRunner) string {
+	out, err := commands.Run("nft", "list", "ruleset")
+	if errors.Is(err, errNotInstalled) {
 		return ""
 	}
 
-	// Then check if service is running
-	cmd := exec.Command("systemctl", "is-active", "nftables")
-	err := cmd.Run()
 	if err == nil {
+		if strings.Contains(out, "chain ") {
+			return "[green]Enabled (nftables)[white]"
+		}
+		return "[red]Disabled (nftables)[white]"
+	}
+
+	// Listing the rules needs root, fall back to the state of the service
+	if serviceActive(commands, "nftables") {
 		return "[green]Enabled (nftables)[white]"
 	}
 	return "[red]Disabled (nftables)[white]"
 }
 
-func checkIptables() string {
+func checkIptables(commands CommandRunner) string {
 	// First check if iptables is installed
-	checkInstalled := exec.Command("which", "iptables")
-	if strings.Contains(utils.ExecuteCommand(checkInstalled), "not found") {
+	if _, err := commands.Run("which", "iptables"); err != nil {
 		return ""
 	}
 
 	// Check if iptables module is loaded
-	cmd := exec.Command("lsmod")
-	out := utils.ExecuteCommand(cmd)
+	out, _ := commands.Run("lsmod")
 
 	if strings.Contains(out, "ip_tables") {
 		// Check for any active rules
-		cmd := exec.Command("iptables", "-L")
-		out := utils.ExecuteCommand(cmd)
+		out, _ := commands.Run("iptables", "-L")
 		if strings.Contains(out, "Chain") && !strings.Contains(out, "0 references") {
 			return "[green]Enabled (iptables)[white]"
 		}
@@ -114,37 +124,78 @@ func checkIptables() string {
 	return ""
 }
 
-func firewallStateMacOS() string {
-	cmd := exec.Command(osxFirewallCmd, "--getglobalstate")
-	str := utils.ExecuteCommand(cmd)
+// serviceActive returns whether systemd reports the service as running
+func serviceActive(commands CommandRunner, service string) bool {
+	_, err := commands.Run("systemctl", "is-active", "--quiet", service)
+	return err == nil
+}
+
+func firewallStateMacOS(commands CommandRunner) (string, error) {
+	str, err := commands.Run(osxFirewallCmd, "--getglobalstate")
+	if err != nil {
+		return "", err
+	}
 
-	return statusLabel(str)
+	return statusLabel(str), nil
 }
 
-func firewallStateWindows() string {
-	// The raw way to do this in PS, not using netsh, nor registry, is the following:
-	//   if (((Get-NetFirewallProfile | select name,enabled)
-	//                                | where { $_.Enabled -eq $True } | measure ).Count -eq 3)
-	//   { Write-Host "OK" -ForegroundColor Green} else { Write-Host "OFF" -ForegroundColor Red }
-
-	cmd := exec.Command("powershell.exe", "-NoProfile",
-		"-Command", "& { ((Get-NetFirewallProfile | select name,enabled) | where { $_.Enabled -eq $True } | measure ).Count }")
-
-	fwStat := utils.ExecuteCommand(cmd)
-	fwStat = strings.TrimSpace(fwStat) // Always sanitize PowerShell output:  "3\r\n"
-
-	switch fwStat {
-	case "3":
-		return "[green]Good[white] (3/3)"
-	case "2":
-		return "[orange]Poor[white] (2/3)"
-	case "1":
-		return "[yellow]Bad[white] (1/3)"
-	case "0":
-		return "[red]Disabled[white]"
+// firewallStateWindows rates the firewall by how many of its profiles (domain, private and
+// public) are on, naming the ones that are off
+func firewallStateWindows(commands CommandRunner) (string, error) {
+	out, err := commands.Run("netsh", "advfirewall", "show", "allprofiles")
+	if err != nil {
+		return "", err
+	}
+
+	profiles, off := parseFirewallProfiles(out)
+	on := profiles - len(off)
+
+	var label string
+	switch {
+	case profiles == 0:
+		return "[white]N/A[white]", nil
+	case on == 0:
+		return "[red]Disabled[white]", nil
+	case on == profiles:
+		return fmt.Sprintf("[green]Good[white] (%d/%d)", on, profiles), nil
+	case on*2 >= profiles:
+		label = "[orange]Poor[white]"
 	default:
-		return "[white]N/A[white]"
+		label = "[yellow]Bad[white]"
+	}
+
+	return fmt.Sprintf("%s (%d/%d, %s off)", label, on, profiles, strings.Join(off, ", ")), nil
+}
+
+// parseFirewallProfiles returns how many profiles "netsh advfirewall show allprofiles"
+// lists, and the names of the ones whose state is off
+func parseFirewallProfiles(out string) (int, []string) {
+	profiles := 0
+	off := []string{}
+	profile := ""
+
+	// Each profile starts with "Domain Profile Settings:", and has a "State   ON" line
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+
+		if name, ok := strings.CutSuffix(line, " Profile Settings:"); ok {
+			profile = name
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if profile == "" || len(fields) != 2 || fields[0] != "State" {
+			continue
+		}
+
+		profiles++
+		if strings.ToUpper(fields[1]) != "ON" {
+			off = append(off, profile)
+		}
+		profile = ""
 	}
+
+	return profiles, off
 }
 
 /* -------------------- Getting Stealth State ------------------- */
@@ -154,11 +205,13 @@ func firewallStealthStateLinux() string {
 	return "[white]N/A[white]"
 }
 
-func firewallStealthStateMacOS() string {
-	cmd := exec.Command(osxFirewallCmd, "--getstealthmode")
-	str := utils.ExecuteCommand(cmd)
+func firewallStealthStateMacOS(commands CommandRunner) (string, error) {
+	str, err := commands.Run(osxFirewallCmd, "--getstealthmode")
+	if err != nil {
+		return "", err
+	}
 
-	return statusLabel(str)
+	return statusLabel(str), nil
 }
 
 func firewallStealthStateWindows() string {
@@ -0,0 +1,157 @@
+package security
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_firewallStateLinux(t *testing.T) {
+	tests := []struct {
+		name     string
+		outputs  map[string]cannedOutput
+		expected string
+	}{
+		{
+			name: "ufw active",
+			outputs: map[string]cannedOutput{
+				"ufw status": {out: "Status: active\n\nTo                         Action      From\n"},
+			},
+			expected: "[green]Enabled (ufw)[white]",
+		},
+		{
+			name: "ufw inactive",
+			outputs: map[string]cannedOutput{
+				"ufw status": {out: "Status: inactive\n"},
+			},
+			expected: "[red]Disabled (ufw)[white]",
+		},
+		{
+			name: "ufw without root falls back to the service",
+			outputs: map[string]cannedOutput{
+				"ufw status":                      {err: errExitStatus},
+				"systemctl is-active --quiet ufw": {},
+			},
+			expected: "[green]Enabled (ufw)[white]",
+		},
+		{
+			name: "firewalld running",
+			outputs: map[string]cannedOutput{
+				"firewall-cmd --state": {out: "running\n"},
+			},
+			expected: "[green]Enabled (firewalld)[white]",
+		},
+		{
+			name: "firewalld stopped",
+			outputs: map[string]cannedOutput{
+				"firewall-cmd --state": {out: "not running\n", err: errExitStatus},
+			},
+			expected: "[red]Disabled (firewalld)[white]",
+		},
+		{
+			name: "nftables with rules",
+			outputs: map[string]cannedOutput{
+				"nft list ruleset": {out: "table inet filter {\n\tchain input {\n\t}\n}\n"},
+			},
+			expected: "[green]Enabled (nftables)[white]",
+		},
+		{
+			name: "nftables without rules",
+			outputs: map[string]cannedOutput{
+				"nft list ruleset": {},
+			},
+			expected: "[red]Disabled (nftables)[white]",
+		},
+		{
+			name: "nftables without root and a stopped service",
+			outputs: map[string]cannedOutput{
+				"nft list ruleset":                     {err: errExitStatus},
+				"systemctl is-active --quiet nftables": {err: errExitStatus},
+			},
+			expected: "[red]Disabled (nftables)[white]",
+		},
+		{
+			name:     "nothing installed",
+			outputs:  map[string]cannedOutput{},
+			expected: "[red]No firewall[white]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands := fakeRunner(tt.outputs)
+			assert.Equal(t, tt.expected, firewallStateLinux(commands))
+		})
+	}
+}
+
+const netshAllProfilesOutput = `
+Domain Profile Settings:
+----------------------------------------------------------------------
+State                                 ON
+Firewall Policy                       BlockInbound,AllowOutbound
+LocalFirewallRules                    N/A (GPO-store only)
+
+Private Profile Settings:
+----------------------------------------------------------------------
+State                                 ON
+Firewall Policy                       BlockInbound,AllowOutbound
+
+Public Profile Settings:
+----------------------------------------------------------------------
+State                                 %s
+Firewall Policy                       BlockInbound,AllowOutbound
+Ok.
+`
+
+func Test_firewallStateWindows(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   cannedOutput
+		expected string
+		err      string
+	}{
+		{
+			name:     "every profile on",
+			output:   cannedOutput{out: fmt.Sprintf(netshAllProfilesOutput, "ON")},
+			expected: "[green]Good[white] (3/3)",
+		},
+		{
+			name:     "public profile off",
+			output:   cannedOutput{out: strings.ReplaceAll(fmt.Sprintf(netshAllProfilesOutput, "OFF"), "\n", "\r\n")},
+			expected: "[orange]Poor[white] (2/3, Public off)",
+		},
+		{
+			name:     "every profile off",
+			output:   cannedOutput{out: strings.ReplaceAll(fmt.Sprintf(netshAllProfilesOutput, "OFF"), "ON", "OFF")},
+			expected: "[red]Disabled[white]",
+		},
+		{
+			name:     "nothing listed",
+			output:   cannedOutput{out: "Ok.\n"},
+			expected: "[white]N/A[white]",
+		},
+		{
+			name:   "firewall service stopped",
+			output: cannedOutput{err: errExitStatus},
+			err:    "netsh: exit status 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands := fakeRunner(map[string]cannedOutput{"netsh advfirewall show allprofiles": tt.output})
+
+			state, err := firewallStateWindows(commands)
+			if tt.err != "" {
+				assert.Error(t, err, tt.err)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, tt.expected, state)
+		})
+	}
+}
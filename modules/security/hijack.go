@@ -0,0 +1,65 @@
+package security
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"time"
+)
+
+// hijackTimeout is how long resolving the nonexistent domain may take
+const hijackTimeout = 3 * time.Second
+
+// lookupHost resolves names for the hijack check. It is replaceable in tests
+var lookupHost = net.DefaultResolver.LookupHost
+
+// DNSHijack is the result of checking whether DNS responses are intercepted
+type DNSHijack struct {
+	Checked bool
+	// Answers are the addresses a domain that doesn't exist resolved to
+	Answers []string
+	Err     error
+}
+
+// Hijacked returns whether a domain that doesn't exist resolved to something, which means
+// that a resolver along the way makes answers up
+func (hijack DNSHijack) Hijacked() bool {
+	return len(hijack.Answers) > 0
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+// CheckDNSHijack resolves a random domain, which doesn't exist
+func CheckDNSHijack() DNSHijack {
+	ctx, cancel := context.WithTimeout(context.Background(), hijackTimeout)
+	defer cancel()
+
+	check := DNSHijack{Checked: true}
+
+	answers, err := lookupHost(ctx, nonexistentDomain())
+
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		// What an honest resolver answers
+	case err != nil:
+		check.Err = err
+	default:
+		check.Answers = answers
+	}
+
+	return check
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// nonexistentDomain returns a domain that is very unlikely to be registered. It is random so
+// that answers can't be cached, or special-cased
+func nonexistentDomain() string {
+	label := make([]byte, 12)
+	_, _ = rand.Read(label)
+
+	return "wtf-" + hex.EncodeToString(label) + ".com"
+}
@@ -0,0 +1,155 @@
+package security
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+// stubResolver answers every DNS query over UDP on localhost. It answers A queries with
+// answer when there is one, and says the name doesn't exist otherwise. Lookups go to it for
+// the rest of the test
+func stubResolver(t *testing.T, answer net.IP) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if reply := stubReply(buf[:n], answer); reply != nil {
+				_, _ = conn.WriteTo(reply, addr)
+			}
+		}
+	}()
+
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "udp", conn.LocalAddr().String())
+		},
+	}
+
+	original := lookupHost
+	t.Cleanup(func() { lookupHost = original })
+	lookupHost = resolver.LookupHost
+}
+
+// stubReply builds the reply to a query, which repeats its question
+func stubReply(query []byte, answer net.IP) []byte {
+	// The question follows the 12 bytes of the header, as labels and then its type and class
+	end := 12
+	for end < len(query) && query[end] != 0 {
+		end += int(query[end]) + 1
+	}
+	end += 5
+	if end > len(query) {
+		return nil
+	}
+
+	qtype := binary.BigEndian.Uint16(query[end-4:])
+
+	header := make([]byte, 12)
+	copy(header, query[:2])
+	binary.BigEndian.PutUint16(header[2:], 0x8183) // A response, for a name that doesn't exist
+	binary.BigEndian.PutUint16(header[4:], 1)
+
+	reply := append(header, query[12:end]...)
+	if answer == nil {
+		return reply
+	}
+
+	binary.BigEndian.PutUint16(reply[2:], 0x8180)
+	if qtype != 1 {
+		return reply
+	}
+
+	binary.BigEndian.PutUint16(reply[6:], 1)
+	reply = append(reply,
+		0xc0, 0x0c, // The name in the question
+		0, 1, 0, 1, // A, IN
+		0, 0, 0, 60, // TTL
+		0, 4,
+	)
+	return append(reply, answer.To4()...)
+}
+
+func Test_CheckDNSHijack(t *testing.T) {
+	t.Run("honest resolver", func(t *testing.T) {
+		stubResolver(t, nil)
+
+		check := CheckDNSHijack()
+		assert.NilError(t, check.Err)
+		assert.Assert(t, check.Checked)
+		assert.Assert(t, !check.Hijacked())
+	})
+
+	t.Run("made-up answer", func(t *testing.T) {
+		stubResolver(t, net.ParseIP("198.51.100.7"))
+
+		check := CheckDNSHijack()
+		assert.NilError(t, check.Err)
+		assert.Assert(t, check.Hijacked())
+		assert.DeepEqual(t, []string{"198.51.100.7"}, check.Answers)
+	})
+
+	t.Run("failed lookup", func(t *testing.T) {
+		original := lookupHost
+		t.Cleanup(func() { lookupHost = original })
+		lookupHost = func(context.Context, string) ([]string, error) {
+			return nil, &net.DNSError{Err: "i/o timeout", IsTimeout: true}
+		}
+
+		check := CheckDNSHijack()
+		assert.Assert(t, !check.Hijacked())
+		assert.ErrorContains(t, check.Err, "i/o timeout")
+	})
+}
+
+func Test_nonexistentDomain(t *testing.T) {
+	domain := nonexistentDomain()
+
+	assert.Assert(t, strings.HasPrefix(domain, "wtf-") && strings.HasSuffix(domain, ".com"), domain)
+	assert.Assert(t, domain != nonexistentDomain())
+}
+
+func Test_dnsSection(t *testing.T) {
+	widget, _ := newTestWidget(t, `{enabled: true, expectedDNS: ["1.1.1.1", "1.0.0.1"]}`, nil)
+
+	data := NewSecurityData()
+	data.Dns = []string{"1.1.1.1", "203.0.113.53"}
+
+	assert.Equal(t, " [red]DNS[white]\n"+
+		"      1.1.1.1\n"+
+		" [red]203.0.113.53[white]\n\n", widget.dnsSection(data))
+
+	data.DNSHijack = DNSHijack{Checked: true, Answers: []string{"198.51.100.7"}}
+	assert.Assert(t, strings.HasSuffix(widget.dnsSection(data), "  [red]hijacked: made-up answers (198.51.100.7)[white]\n\n"))
+
+	data.DNSHijack = DNSHijack{Checked: true, Err: errors.New("lookup timed out")}
+	assert.Assert(t, strings.HasSuffix(widget.dnsSection(data), "  [yellow]hijack check failed (lookup timed out)[white]\n\n"))
+
+	data.DNSHijack = DNSHijack{Checked: true}
+	assert.Assert(t, strings.HasSuffix(widget.dnsSection(data), "  [green]not hijacked[white]\n\n"))
+}
+
+func Test_dnsSection_NothingExpected(t *testing.T) {
+	widget, _ := newTestWidget(t, "enabled: true", nil)
+
+	data := NewSecurityData()
+	data.Dns = []string{"203.0.113.53"}
+
+	assert.Equal(t, " [red]DNS[white]\n"+
+		" 203.0.113.53\n\n", widget.dnsSection(data))
+}
@@ -0,0 +1,8 @@
+package security
+
+func (widget *Widget) initializeKeyboardControls() {
+	widget.InitializeHelpTextKeyboardControl(widget.ShowHelp)
+	widget.InitializeRefreshKeyboardControl(widget.Refresh)
+
+	widget.SetKeyboardChar("R", widget.forceRefresh, "Refresh every section, ignoring the cached ones")
+}
@@ -0,0 +1,159 @@
+package security
+
+import (
+	"cmp"
+	"errors"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Listener is a process listening for TCP connections
+type Listener struct {
+	Port    int
+	Process string
+	Address string
+}
+
+// isLocalhost returns whether the listener only accepts connections from this machine
+func (listener Listener) isLocalhost() bool {
+	return listener.Address == "localhost" || listener.Address == "::1" ||
+		strings.HasPrefix(listener.Address, "127.")
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+func ListeningPorts(commands CommandRunner) ([]Listener, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return listeningPortsMacOS(commands)
+	case "linux":
+		return listeningPortsLinux(commands)
+	default:
+		return []Listener{}, nil
+	}
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+func listeningPortsMacOS(commands CommandRunner) ([]Listener, error) {
+	out, err := commands.Run("lsof", "-iTCP", "-sTCP:LISTEN", "-P", "-n")
+	if err != nil {
+		// lsof exits with an error, and prints nothing, when nothing is listening
+		if strings.TrimSpace(out) == "" && !errors.Is(err, errNotInstalled) {
+			return []Listener{}, nil
+		}
+		return nil, err
+	}
+
+	listeners := []Listener{}
+
+	// Lines look like "postgres 700 me 7u IPv6 0x6f 0t0 TCP [::1]:5432 (LISTEN)"
+	for _, line := range strings.Split(out, "\n")[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 10 || fields[len(fields)-1] != "(LISTEN)" {
+			continue
+		}
+
+		listener, ok := parseListener(fields[len(fields)-2], strings.ReplaceAll(fields[0], `\x20`, " "))
+		if ok {
+			listeners = append(listeners, listener)
+		}
+	}
+
+	return uniqueListeners(listeners), nil
+}
+
+func listeningPortsLinux(commands CommandRunner) ([]Listener, error) {
+	out, err := commands.Run("ss", "-tlnp")
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := []Listener{}
+
+	// Lines look like `LISTEN 0 128 0.0.0.0:22 0.0.0.0:* users:(("sshd",pid=900,fd=3))`. The
+	// processes of other users are only shown to root
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[0] != "LISTEN" {
+			continue
+		}
+
+		process := ""
+		if len(fields) > 5 {
+			process = ssProcess(fields[5])
+		}
+
+		listener, ok := parseListener(fields[3], process)
+		if ok {
+			listeners = append(listeners, listener)
+		}
+	}
+
+	return uniqueListeners(listeners), nil
+}
+
+// parseListener splits a local address such as "127.0.0.1:8080", "[::1]:5432" or
+// "*:80" into the listener's address and port
+func parseListener(local, process string) (Listener, bool) {
+	idx := strings.LastIndex(local, ":")
+	if idx < 0 {
+		return Listener{}, false
+	}
+
+	port, err := strconv.Atoi(local[idx+1:])
+	if err != nil {
+		return Listener{}, false
+	}
+
+	address := strings.Trim(local[:idx], "[]")
+	// ss names the interface a listener is bound to, as in "127.0.0.53%lo"
+	address, _, _ = strings.Cut(address, "%")
+
+	return Listener{Port: port, Process: process, Address: address}, true
+}
+
+// ssProcess returns the name of the first process in ss's `users:(("sshd",pid=900,fd=3))`
+func ssProcess(users string) string {
+	_, name, ok := strings.Cut(users, `(("`)
+	if !ok {
+		return ""
+	}
+
+	name, _, _ = strings.Cut(name, `"`)
+	return name
+}
+
+// uniqueListeners sorts the listeners by port, dropping the ones listed twice
+func uniqueListeners(listeners []Listener) []Listener {
+	slices.SortFunc(listeners, func(a, b Listener) int {
+		return cmp.Or(
+			cmp.Compare(a.Port, b.Port),
+			cmp.Compare(a.Address, b.Address),
+			cmp.Compare(a.Process, b.Process),
+		)
+	})
+
+	return slices.Compact(listeners)
+}
+
+// shownListeners filters out the allowed listeners and, if asked to, the localhost ones.
+// Returns at most limit of them, and how many more there are
+func shownListeners(listeners []Listener, allowedPorts []int, hideLocalhost bool, limit int) ([]Listener, int) {
+	shown := []Listener{}
+
+	for _, listener := range listeners {
+		if slices.Contains(allowedPorts, listener.Port) || (hideLocalhost && listener.isLocalhost()) {
+			continue
+		}
+		shown = append(shown, listener)
+	}
+
+	if limit > 0 && len(shown) > limit {
+		return shown[:limit], len(shown) - limit
+	}
+
+	return shown, 0
+}
@@ -0,0 +1,105 @@
+package security
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const lsofOutput = `COMMAND     PID USER   FD   TYPE             DEVICE SIZE/OFF NODE NAME
+rapportd    512   me    4u  IPv4 0x4f1c2a7d3b9e1c01      0t0  TCP *:49152 (LISTEN)
+rapportd    512   me    5u  IPv6 0x4f1c2a7d3b9e1c02      0t0  TCP *:49152 (LISTEN)
+postgres    700   me    7u  IPv6 0x4f1c2a7d3b9e1c03      0t0  TCP [::1]:5432 (LISTEN)
+postgres    700   me    8u  IPv4 0x4f1c2a7d3b9e1c04      0t0  TCP 127.0.0.1:5432 (LISTEN)
+Code\x20H  900   me   31u  IPv4 0x4f1c2a7d3b9e1c05      0t0  TCP 192.168.1.20:3000 (LISTEN)
+`
+
+const ssOutput = `State  Recv-Q Send-Q Local Address:Port  Peer Address:PortProcess
+LISTEN 0      4096   127.0.0.53%lo:53         0.0.0.0:*     users:(("systemd-resolve",pid=612,fd=14))
+LISTEN 0      128          0.0.0.0:22         0.0.0.0:*     users:(("sshd",pid=900,fd=3))
+LISTEN 0      128             [::]:22            [::]:*     users:(("sshd",pid=900,fd=4))
+LISTEN 0      511                *:80               *:*
+LISTEN 0      128      [fe80::1%eth0]:8080          [::]:*     users:(("node",pid=1200,fd=20),("node",pid=1201,fd=20))
+`
+
+func Test_listeningPortsMacOS(t *testing.T) {
+	commands := fakeRunner(map[string]cannedOutput{
+		"lsof -iTCP -sTCP:LISTEN -P -n": {out: lsofOutput},
+	})
+
+	listeners, err := listeningPortsMacOS(commands)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []Listener{
+		{Port: 3000, Process: "Code H", Address: "192.168.1.20"},
+		{Port: 5432, Process: "postgres", Address: "127.0.0.1"},
+		{Port: 5432, Process: "postgres", Address: "::1"},
+		{Port: 49152, Process: "rapportd", Address: "*"},
+	}, listeners)
+}
+
+func Test_listeningPortsMacOS_NothingListening(t *testing.T) {
+	commands := fakeRunner(map[string]cannedOutput{
+		"lsof -iTCP -sTCP:LISTEN -P -n": {err: errExitStatus},
+	})
+
+	listeners, err := listeningPortsMacOS(commands)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(listeners))
+
+	commands = fakeRunner(map[string]cannedOutput{})
+	_, err = listeningPortsMacOS(commands)
+	assert.Error(t, err, "lsof not installed")
+}
+
+func Test_listeningPortsLinux(t *testing.T) {
+	commands := fakeRunner(map[string]cannedOutput{
+		"ss -tlnp": {out: ssOutput},
+	})
+
+	listeners, err := listeningPortsLinux(commands)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []Listener{
+		{Port: 22, Process: "sshd", Address: "0.0.0.0"},
+		{Port: 22, Process: "sshd", Address: "::"},
+		{Port: 53, Process: "systemd-resolve", Address: "127.0.0.53"},
+		{Port: 80, Process: "", Address: "*"},
+		{Port: 8080, Process: "node", Address: "fe80::1"},
+	}, listeners)
+}
+
+func Test_shownListeners(t *testing.T) {
+	listeners := []Listener{
+		{Port: 22, Address: "0.0.0.0"},
+		{Port: 53, Address: "127.0.0.53"},
+		{Port: 80, Address: "*"},
+		{Port: 5432, Address: "::1"},
+		{Port: 8080, Address: "fe80::1"},
+	}
+
+	shown, more := shownListeners(listeners, []int{22}, false, 0)
+	assert.Equal(t, 4, len(shown))
+	assert.Equal(t, 0, more)
+
+	shown, more = shownListeners(listeners, []int{22}, true, 0)
+	assert.DeepEqual(t, []Listener{{Port: 80, Address: "*"}, {Port: 8080, Address: "fe80::1"}}, shown)
+	assert.Equal(t, 0, more)
+
+	shown, more = shownListeners(listeners, nil, false, 2)
+	assert.DeepEqual(t, listeners[:2], shown)
+	assert.Equal(t, 3, more)
+}
+
+func Test_listeningSection(t *testing.T) {
+	widget, _ := newTestWidget(t, "{enabled: true, allowedPorts: [22], maxListening: 1}", nil)
+
+	data := NewSecurityData()
+	data.Listening = []Listener{
+		{Port: 22, Process: "sshd", Address: "0.0.0.0"},
+		{Port: 80, Address: "*"},
+		{Port: 8080, Process: "node", Address: "[::]"},
+	}
+
+	assert.Equal(t, " [red]Listening[white]\n"+
+		" [yellow]   80 ?               *[white]\n"+
+		"  [gray]… and 1 more[white]\n\n", widget.listeningSection(data))
+}
@@ -0,0 +1,207 @@
+package security
+
+import (
+	"cmp"
+	"errors"
+	"io/fs"
+	"os"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// loginsWindow is how far back failed logins are counted
+const loginsWindow = 24 * time.Hour
+
+// authLogPaths are the logs sshd writes to on Linux, Debian's then Red Hat's
+var authLogPaths = []string{"/var/log/auth.log", "/var/log/secure"}
+
+// failedLoginPattern matches sshd's "Failed password for invalid user admin from 203.0.113.9
+// port 50022 ssh2", capturing the user and where the attempt came from
+var failedLoginPattern = regexp.MustCompile(`Failed \S+ for (?:invalid user )?(\S+) from (\S+)`)
+
+// FailedLogin is an authentication attempt that failed
+type FailedLogin struct {
+	At   time.Time
+	User string
+	From string
+}
+
+// FailedLogins are the failed logins of the last day, most recent first
+type FailedLogins struct {
+	Attempts []FailedLogin
+	// NeedsPrivileges is set when the logs of failed logins can't be read
+	NeedsPrivileges bool
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+func FailedLoginAttempts(commands CommandRunner) (FailedLogins, error) {
+	var (
+		attempts []FailedLogin
+		err      error
+	)
+
+	switch runtime.GOOS {
+	case "darwin":
+		attempts, err = failedLoginsMacOS(commands)
+	case "linux":
+		attempts, err = failedLoginsLinux(commands)
+	default:
+		return FailedLogins{}, nil
+	}
+
+	if needsPrivileges(err) {
+		return FailedLogins{NeedsPrivileges: true}, nil
+	}
+	if err != nil {
+		return FailedLogins{}, err
+	}
+
+	return recentFailedLogins(attempts, nowFunc()), nil
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+func failedLoginsMacOS(commands CommandRunner) ([]FailedLogin, error) {
+	out, err := commands.Run("log", "show", "--style", "syslog", "--last", "24h",
+		"--predicate", `process == "sshd" AND eventMessage CONTAINS "Failed"`)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lines look like "2026-10-16 09:12:01.123456+0200  localhost sshd[123]: Failed password…"
+	return parseFailedLogins(out, func(line string) (time.Time, bool) {
+		if len(line) < 31 {
+			return time.Time{}, false
+		}
+		at, err := time.Parse("2006-01-02 15:04:05.000000-0700", line[:31])
+		return at, err == nil
+	}), nil
+}
+
+// failedLoginsLinux reads the failed logins from lastb, which needs root, or else from the
+// auth log, which users of the adm group can usually read
+func failedLoginsLinux(commands CommandRunner) ([]FailedLogin, error) {
+	out, lastbErr := commands.Run("lastb", "--time-format", "iso")
+	if lastbErr == nil {
+		return parseLastb(out), nil
+	}
+
+	var logErr error
+	for _, path := range authLogPaths {
+		contents, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			logErr = err
+			continue
+		}
+
+		now := nowFunc()
+		return parseFailedLogins(string(contents), func(line string) (time.Time, bool) {
+			return parseSyslogTime(line, now)
+		}), nil
+	}
+
+	if needsPrivileges(lastbErr) {
+		return nil, lastbErr
+	}
+	return nil, cmp.Or(logErr, lastbErr)
+}
+
+// parseLastb reads lastb's lines, such as "alice ssh:notty 203.0.113.9
+// 2026-10-16T09:12:01+02:00 - 2026-10-16T09:12:01+02:00 (00:00)". The host is left out for
+// local attempts
+func parseLastb(out string) []FailedLogin {
+	attempts := []FailedLogin{}
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] == "btmp" {
+			continue
+		}
+
+		for idx := 2; idx <= 3 && idx < len(fields); idx++ {
+			at, err := time.Parse(time.RFC3339, fields[idx])
+			if err != nil {
+				continue
+			}
+
+			attempt := FailedLogin{At: at, User: fields[0], From: "local"}
+			if idx == 3 {
+				attempt.From = fields[2]
+			}
+			attempts = append(attempts, attempt)
+			break
+		}
+	}
+
+	return attempts
+}
+
+// parseFailedLogins reads the failed logins in sshd's log lines, timed by parseTime
+func parseFailedLogins(out string, parseTime func(line string) (time.Time, bool)) []FailedLogin {
+	attempts := []FailedLogin{}
+
+	for _, line := range strings.Split(out, "\n") {
+		match := failedLoginPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		at, ok := parseTime(line)
+		if !ok {
+			continue
+		}
+
+		attempts = append(attempts, FailedLogin{At: at, User: match[1], From: match[2]})
+	}
+
+	return attempts
+}
+
+// parseSyslogTime reads the time a syslog line starts with, either precise as in
+// "2026-10-16T09:12:01.123456+02:00" or traditional as in "Oct 16 09:12:01". Traditional
+// times have no year, so they're taken to be from the last twelve months
+func parseSyslogTime(line string, now time.Time) (time.Time, bool) {
+	first, _, _ := strings.Cut(line, " ")
+	if at, err := time.Parse(time.RFC3339Nano, first); err == nil {
+		return at, true
+	}
+
+	if len(line) < 15 {
+		return time.Time{}, false
+	}
+
+	at, err := time.ParseInLocation("Jan _2 15:04:05", line[:15], now.Location())
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	at = at.AddDate(now.Year(), 0, 0)
+	if at.After(now) {
+		at = at.AddDate(-1, 0, 0)
+	}
+
+	return at, true
+}
+
+// recentFailedLogins keeps the attempts of the last day, most recent first
+func recentFailedLogins(attempts []FailedLogin, now time.Time) FailedLogins {
+	recent := []FailedLogin{}
+	for _, attempt := range attempts {
+		if !attempt.At.Before(now.Add(-loginsWindow)) && !attempt.At.After(now) {
+			recent = append(recent, attempt)
+		}
+	}
+
+	slices.SortStableFunc(recent, func(a, b FailedLogin) int {
+		return b.At.Compare(a.At)
+	})
+
+	return FailedLogins{Attempts: recent}
+}
@@ -0,0 +1,194 @@
+package security
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+var loginsNow = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func useNow(t *testing.T, now time.Time) {
+	t.Helper()
+
+	original := nowFunc
+	t.Cleanup(func() { nowFunc = original })
+	nowFunc = func() time.Time { return now }
+}
+
+func useAuthLog(t *testing.T, contents string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "auth.log")
+	assert.NilError(t, os.WriteFile(path, []byte(contents), 0o600))
+
+	original := authLogPaths
+	t.Cleanup(func() { authLogPaths = original })
+	authLogPaths = []string{filepath.Join(t.TempDir(), "secure"), path}
+}
+
+const authLogExcerpt = `Oct 15 08:00:00 host sshd[1001]: Failed password for alice from 198.51.100.1 port 50000 ssh2
+Oct 16 09:12:01 host sshd[1234]: Invalid user admin from 203.0.113.9 port 50022
+Oct 16 09:12:01 host sshd[1234]: Failed password for invalid user admin from 203.0.113.9 port 50022 ssh2
+Oct 16 10:30:45 host sshd[1240]: Accepted publickey for alice from 192.168.1.20 port 51000 ssh2
+2026-10-16T11:02:13.512345+00:00 host sshd[1250]: Failed publickey for root from 2001:db8::7 port 40000 ssh2
+Oct 16 11:45:00 host sudo: pam_unix(sudo:auth): authentication failure; logname=alice uid=1000
+`
+
+func Test_failedLoginsLinux_AuthLog(t *testing.T) {
+	useNow(t, loginsNow)
+	useAuthLog(t, authLogExcerpt)
+	commands := fakeRunner(map[string]cannedOutput{
+		"lastb --time-format iso": {err: fmt.Errorf("lastb: /var/log/btmp: Permission denied")},
+	})
+
+	attempts, err := failedLoginsLinux(commands)
+	assert.NilError(t, err)
+
+	logins := recentFailedLogins(attempts, loginsNow)
+	assert.DeepEqual(t, []FailedLogin{
+		{At: time.Date(2026, 10, 16, 11, 2, 13, 512345000, time.UTC), User: "root", From: "2001:db8::7"},
+		{At: time.Date(2026, 10, 16, 9, 12, 1, 0, time.UTC), User: "admin", From: "203.0.113.9"},
+	}, logins.Attempts)
+}
+
+func Test_failedLoginsLinux_Lastb(t *testing.T) {
+	commands := fakeRunner(map[string]cannedOutput{
+		"lastb --time-format iso": {out: "admin    ssh:notty    203.0.113.9      2026-10-16T09:12:01+00:00 - 2026-10-16T09:12:01+00:00  (00:00)\n" +
+			"alice    tty2                          2026-10-16T08:00:00+00:00 - 2026-10-16T08:00:00+00:00  (00:00)\n" +
+			"\n" +
+			"btmp begins 2026-10-01T00:00:00+00:00\n"},
+	})
+
+	attempts, err := failedLoginsLinux(commands)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []FailedLogin{
+		{At: time.Date(2026, 10, 16, 9, 12, 1, 0, time.UTC), User: "admin", From: "203.0.113.9"},
+		{At: time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC), User: "alice", From: "local"},
+	}, attempts)
+}
+
+func Test_failedLoginsLinux_NeedsPrivileges(t *testing.T) {
+	dir := t.TempDir()
+	unreadable := filepath.Join(dir, "auth.log")
+	assert.NilError(t, os.WriteFile(unreadable, nil, 0o000))
+	if _, err := os.ReadFile(unreadable); err == nil {
+		t.Skip("running as root, every file is readable")
+	}
+
+	original := authLogPaths
+	t.Cleanup(func() { authLogPaths = original })
+	authLogPaths = []string{unreadable}
+
+	commands := fakeRunner(map[string]cannedOutput{
+		"lastb --time-format iso": {err: fmt.Errorf("lastb: /var/log/btmp: Permission denied")},
+	})
+
+	_, err := failedLoginsLinux(commands)
+	assert.Assert(t, needsPrivileges(err), err)
+}
+
+func Test_failedLoginsMacOS(t *testing.T) {
+	commands := fakeRunner(map[string]cannedOutput{
+		`log show --style syslog --last 24h --predicate process == "sshd" AND eventMessage CONTAINS "Failed"`: {
+			out: "Timestamp                       (process)[PID]\n" +
+				"2026-10-16 09:12:01.123456+0000  localhost sshd[123]: Failed password for alice from 203.0.113.9 port 50022 ssh2\n",
+		},
+	})
+
+	attempts, err := failedLoginsMacOS(commands)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []FailedLogin{
+		{At: time.Date(2026, 10, 16, 9, 12, 1, 123456000, time.UTC), User: "alice", From: "203.0.113.9"},
+	}, attempts)
+}
+
+func Test_parseSyslogTime(t *testing.T) {
+	at, ok := parseSyslogTime("Dec 31 23:59:59 host sshd[1]: …", loginsNow)
+	assert.Assert(t, ok)
+	assert.Equal(t, time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC), at)
+
+	_, ok = parseSyslogTime("garbage", loginsNow)
+	assert.Assert(t, !ok)
+}
+
+func Test_loginsSection(t *testing.T) {
+	useNow(t, loginsNow)
+	widget, _ := newTestWidget(t, "{enabled: true, failedLoginsThreshold: 5, maxFailedLogins: 2}", nil)
+
+	many := func(n int) []FailedLogin {
+		attempts := []FailedLogin{}
+		for i := 0; i < n; i++ {
+			attempts = append(attempts, FailedLogin{
+				At:   loginsNow.Add(-time.Duration(i) * time.Minute),
+				User: "admin",
+				From: fmt.Sprintf("203.0.113.%d", i%250),
+			})
+		}
+		return attempts
+	}
+
+	tests := []struct {
+		name     string
+		logins   FailedLogins
+		expected string
+	}{
+		{
+			name:     "none",
+			logins:   FailedLogins{},
+			expected: "   Failed: 0 in 24h\n",
+		},
+		{
+			name:   "a handful",
+			logins: FailedLogins{Attempts: many(4)},
+			expected: "   Failed: 4 in 24h\n" +
+				"  12:00 admin from 203.0.113.0\n" +
+				"  11:59 admin from 203.0.113.1\n",
+		},
+		{
+			name:   "hundreds",
+			logins: FailedLogins{Attempts: many(400)},
+			expected: "   Failed: [red]400 in 24h[white]\n" +
+				"  12:00 admin from 203.0.113.0\n" +
+				"  11:59 admin from 203.0.113.1\n",
+		},
+		{
+			name:     "unreadable",
+			logins:   FailedLogins{NeedsPrivileges: true},
+			expected: "   Failed: [yellow]needs privileges[white]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := NewSecurityData()
+			data.FailedLogins = tt.logins
+
+			section := widget.loginsSection(data)
+			assert.Equal(t, " [red]Logins[white]\n"+tt.expected+"\n", section)
+			assert.Assert(t, strings.Count(section, "\n") <= 5)
+		})
+	}
+}
+
+func Test_recentFailedLogins(t *testing.T) {
+	attempts := []FailedLogin{
+		{At: loginsNow.Add(-25 * time.Hour), User: "old"},
+		{At: loginsNow.Add(-time.Hour), User: "earlier"},
+		{At: loginsNow.Add(-24 * time.Hour), User: "at the edge"},
+		{At: loginsNow.Add(-time.Minute), User: "latest"},
+	}
+
+	logins := recentFailedLogins(attempts, loginsNow)
+
+	users := []string{}
+	for _, attempt := range logins.Attempts {
+		users = append(users, attempt.User)
+	}
+	assert.DeepEqual(t, []string{"latest", "earlier", "at the edge"}, users)
+}
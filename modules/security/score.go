@@ -0,0 +1,186 @@
+package security
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The checks that make up the score, which are also the keys of the scoreWeights setting
+const (
+	checkCertificates = "certificates"
+	checkDNS          = "dns"
+	checkDNSHijack    = "dnsHijack"
+	checkEncryption   = "encryption"
+	checkFirewall     = "firewall"
+	checkListening    = "listening"
+	checkLogins       = "logins"
+	checkSSHPasswords = "sshPasswords"
+	checkSSHRoot      = "sshRoot"
+	checkStealth      = "stealth"
+	checkSudo         = "sudo"
+	checkTrustedWifi  = "trustedWifi"
+	checkWifi         = "wifi"
+)
+
+var scoreCheckNames = []string{
+	checkCertificates, checkDNS, checkDNSHijack, checkEncryption, checkFirewall, checkListening,
+	checkLogins, checkSSHPasswords, checkSSHRoot, checkStealth, checkSudo, checkTrustedWifi, checkWifi,
+}
+
+// scoreCheck is one of the checks that make up the security score
+type scoreCheck struct {
+	name   string
+	label  string
+	passed bool
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// scoreChecks returns the checks that apply to the data, failing ones first. A check only
+// applies when its section was fetched without errors, and has something to check
+func scoreChecks(data *SecurityData, settings *Settings) []scoreCheck {
+	checks := []scoreCheck{}
+
+	add := func(section, name, label string, applies bool, passed func() bool) {
+		if !slices.Contains(settings.sections, section) || data.Errors[section] != nil || !applies {
+			return
+		}
+		if settings.scoreWeights[name] <= 0 {
+			return
+		}
+		checks = append(checks, scoreCheck{name: name, label: label, passed: passed()})
+	}
+
+	add(sectionFirewall, checkFirewall, "Firewall on", data.FirewallEnabled != "", func() bool {
+		return firewallOn(data.FirewallEnabled)
+	})
+	add(sectionFirewall, checkStealth, "Stealth mode on", data.FirewallStealth == "on" || data.FirewallStealth == "off", func() bool {
+		return data.FirewallStealth == "on"
+	})
+
+	bootVolume := slices.IndexFunc(data.Encryption, func(volume DiskEncryption) bool {
+		return volume.Boot && volume.State != encryptionUnknown
+	})
+	add(sectionEncryption, checkEncryption, "Boot disk encrypted", bootVolume >= 0, func() bool {
+		return data.Encryption[bootVolume].State == encryptionEnabled
+	})
+
+	connected := data.WifiName != ""
+	add(sectionWifi, checkWifi, "Wi-Fi encrypted", connected && cryptoStrength(data.WifiEncryption) != cryptoUnknown, func() bool {
+		return cryptoStrength(data.WifiEncryption) == cryptoStrong
+	})
+	add(sectionWifi, checkTrustedWifi, "Trusted Wi-Fi network", connected && len(settings.trustedNetworks) > 0, func() bool {
+		return isTrustedNetwork(data.WifiName, settings.trustedNetworks)
+	})
+
+	add(sectionDNS, checkDNS, "Expected DNS servers", len(settings.expectedDNS) > 0 && len(data.Dns) > 0, func() bool {
+		return !slices.ContainsFunc(data.Dns, func(server string) bool {
+			return !slices.Contains(settings.expectedDNS, server)
+		})
+	})
+	add(sectionDNS, checkDNSHijack, "DNS answers genuine", data.DNSHijack.Checked && data.DNSHijack.Err == nil, func() bool {
+		return !data.DNSHijack.Hijacked()
+	})
+
+	add(sectionSSH, checkSSHPasswords, "SSH password logins off", data.SSH.SSHDInstalled, func() bool {
+		return data.SSH.PasswordAuthentication != "yes"
+	})
+	add(sectionSSH, checkSSHRoot, "SSH root logins off", data.SSH.SSHDInstalled, func() bool {
+		return data.SSH.PermitRootLogin != "yes"
+	})
+
+	add(sectionLogins, checkLogins, "Few failed logins", !data.FailedLogins.NeedsPrivileges, func() bool {
+		return len(data.FailedLogins.Attempts) <= settings.failedLoginsThreshold
+	})
+	add(sectionUsers, checkSudo, "sudo asks for a password", data.Sudo != "", func() bool {
+		return data.Sudo != sudoPasswordless
+	})
+
+	add(sectionListening, checkListening, "Only allowed ports open", len(settings.allowedPorts) > 0, func() bool {
+		shown, _ := shownListeners(data.Listening, settings.allowedPorts, settings.hideLocalhost, 0)
+		return len(shown) == 0
+	})
+	add(sectionCertificates, checkCertificates, "Certificates valid", len(data.Certificates) > 0, func() bool {
+		now := nowFunc()
+		return !slices.ContainsFunc(data.Certificates, func(cert Certificate) bool {
+			return cert.Err != nil || cert.daysLeft(now) < settings.critDays
+		})
+	})
+
+	slices.SortStableFunc(checks, func(a, b scoreCheck) int {
+		switch {
+		case a.passed == b.passed:
+			return 0
+		case !a.passed:
+			return -1
+		default:
+			return 1
+		}
+	})
+
+	return checks
+}
+
+// firewallOn returns whether the state of the firewall, as the platform reports it, is on
+func firewallOn(state string) bool {
+	return state == "on" || strings.Contains(state, "Enabled") || strings.Contains(state, "Good")
+}
+
+// score adds up the weights of the checks that passed, out of the weights of all of them
+func score(checks []scoreCheck, weights map[string]int) (int, int) {
+	passed, total := 0, 0
+
+	for _, check := range checks {
+		total += weights[check.name]
+		if check.passed {
+			passed += weights[check.name]
+		}
+	}
+
+	return passed, total
+}
+
+// scoreColor colors a score by the percentage of it that passed
+func (widget *Widget) scoreColor(passed, total int) string {
+	percent := 100 * passed / max(total, 1)
+
+	switch {
+	case percent >= widget.settings.scoreWarn:
+		return "green"
+	case percent >= widget.settings.scoreCrit:
+		return "yellow"
+	default:
+		return "red"
+	}
+}
+
+// scoreTitle appends the score to the title, as in "Security 7/9", colored
+func (widget *Widget) scoreTitle(title string, data *SecurityData) string {
+	checks := scoreChecks(data, widget.settings)
+	passed, total := score(checks, widget.settings.scoreWeights)
+	if total == 0 {
+		return title
+	}
+
+	return fmt.Sprintf("[%s]%s %d/%d[white]", widget.scoreColor(passed, total), title, passed, total)
+}
+
+// scoreSection lists the checks that make up the score, failing ones first
+func (widget *Widget) scoreSection(data *SecurityData) string {
+	checks := scoreChecks(data, widget.settings)
+	if len(checks) == 0 {
+		return ""
+	}
+
+	str := widget.sectionHeader(data, sectionScore, "Checks")
+	for _, check := range checks {
+		if check.passed {
+			str += fmt.Sprintf("  [green]✓[white] %s\n", check.label)
+		} else {
+			str += fmt.Sprintf("  [red]✗[white] %s\n", check.label)
+		}
+	}
+
+	return str + "\n"
+}
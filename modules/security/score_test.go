@@ -0,0 +1,152 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func checkNames(checks []scoreCheck) []string {
+	names := []string{}
+	for _, check := range checks {
+		names = append(names, check.name)
+	}
+	return names
+}
+
+func Test_scoreChecks(t *testing.T) {
+	useNow(t, loginsNow)
+
+	tests := []struct {
+		name    string
+		yaml    string
+		data    *SecurityData
+		failing []string
+		passing []string
+		passed  int
+		total   int
+	}{
+		{
+			name: "hardened laptop",
+			yaml: `{trustedNetworks: [Home], expectedDNS: [9.9.9.9]}`,
+			data: &SecurityData{
+				FirewallEnabled: "on",
+				FirewallStealth: "on",
+				Encryption:      []DiskEncryption{{Volume: "/", State: encryptionEnabled, Boot: true}},
+				WifiName:        "Home",
+				WifiEncryption:  "wpa3-sae",
+				Dns:             []string{"9.9.9.9"},
+				SSH:             SSHAudit{SSHDInstalled: true, PasswordAuthentication: "no", PermitRootLogin: "no"},
+				Sudo:            sudoPasswordRequired,
+			},
+			passing: []string{checkFirewall, checkStealth, checkEncryption, checkWifi, checkTrustedWifi, checkDNS, checkSSHPasswords, checkSSHRoot, checkLogins, checkSudo},
+			passed:  10,
+			total:   10,
+		},
+		{
+			name: "coffee shop",
+			yaml: `{trustedNetworks: [Home], scoreWeights: {wifi: 3, stealth: 0}}`,
+			data: &SecurityData{
+				FirewallEnabled: "[red]Disabled (ufw)[white]",
+				FirewallStealth: "[white]N/A[white]",
+				Encryption:      []DiskEncryption{{Volume: "/", State: encryptionEnabled, Boot: true}},
+				WifiName:        "Free WiFi",
+				WifiEncryption:  "",
+				Sudo:            sudoPasswordless,
+			},
+			failing: []string{checkFirewall, checkWifi, checkTrustedWifi, checkSudo},
+			passing: []string{checkEncryption, checkLogins},
+			passed:  2,
+			total:   8,
+		},
+		{
+			name: "failed and unknown probes are left out",
+			yaml: `{sections: [firewall, encryption, logins, certificates], critDays: 7}`,
+			data: &SecurityData{
+				Errors:       map[string]error{sectionFirewall: errExitStatus},
+				Encryption:   []DiskEncryption{{Volume: "/", State: encryptionUnknown, Boot: true}},
+				FailedLogins: FailedLogins{NeedsPrivileges: true},
+				Certificates: []Certificate{
+					{Target: "example.com", NotAfter: loginsNow.Add(30 * 24 * time.Hour)},
+					{Target: "expiring.example.com", NotAfter: loginsNow.Add(2 * 24 * time.Hour)},
+				},
+				WifiName:       "Not fetched",
+				WifiEncryption: "WEP",
+			},
+			failing: []string{checkCertificates},
+			passed:  0,
+			total:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := newTestSettings(t, tt.yaml)
+			if tt.data.Errors == nil {
+				tt.data.Errors = map[string]error{}
+			}
+
+			checks := scoreChecks(tt.data, settings)
+
+			expected := append(append([]string{}, tt.failing...), tt.passing...)
+			assert.DeepEqual(t, expected, checkNames(checks))
+			for idx, check := range checks {
+				assert.Equal(t, idx >= len(tt.failing), check.passed, check.name)
+			}
+
+			passed, total := score(checks, settings.scoreWeights)
+			assert.Equal(t, tt.passed, passed)
+			assert.Equal(t, tt.total, total)
+		})
+	}
+}
+
+func Test_scoreTitle(t *testing.T) {
+	widget, _ := newTestWidget(t, "{enabled: true, scoreWarn: 80, scoreCrit: 50}", nil)
+
+	tests := []struct {
+		name     string
+		data     *SecurityData
+		expected string
+	}{
+		{
+			name:     "nothing to score",
+			data:     &SecurityData{FailedLogins: FailedLogins{NeedsPrivileges: true}, Errors: map[string]error{}},
+			expected: "Security",
+		},
+		{
+			name:     "all passed",
+			data:     &SecurityData{FirewallEnabled: "on", FirewallStealth: "on", Errors: map[string]error{}},
+			expected: "[green]Security 3/3[white]",
+		},
+		{
+			name:     "some failed",
+			data:     &SecurityData{FirewallEnabled: "on", FirewallStealth: "off", Errors: map[string]error{}},
+			expected: "[yellow]Security 2/3[white]",
+		},
+		{
+			name:     "most failed",
+			data:     &SecurityData{FirewallEnabled: "off", FirewallStealth: "off", Errors: map[string]error{}},
+			expected: "[red]Security 1/3[white]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, widget.scoreTitle("Security", tt.data))
+		})
+	}
+}
+
+func Test_scoreSection(t *testing.T) {
+	widget, _ := newTestWidget(t, "enabled: true", nil)
+
+	data := &SecurityData{FirewallEnabled: "on", FirewallStealth: "off", Sudo: sudoPasswordless, Errors: map[string]error{}}
+
+	assert.Equal(t, " [red]Checks[white]\n"+
+		"  [red]✗[white] Stealth mode on\n"+
+		"  [red]✗[white] sudo asks for a password\n"+
+		"  [green]✓[white] Firewall on\n"+
+		"  [green]✓[white] Few failed logins\n\n", widget.scoreSection(data))
+}
@@ -1,16 +1,58 @@
 package security
 
+import (
+	"cmp"
+	"time"
+)
+
+const (
+	sectionCertificates = "certificates"
+	sectionDNS          = "dns"
+	sectionEncryption   = "encryption"
+	sectionFirewall     = "firewall"
+	sectionListening    = "listening"
+	sectionLogins       = "logins"
+	sectionScore        = "score"
+	sectionSSH          = "ssh"
+	sectionUsers        = "users"
+	sectionWifi         = "wifi"
+)
+
+// nowFunc returns the current time. It is replaceable in tests
+var nowFunc = time.Now
+
 type SecurityData struct {
+	Certificates    []Certificate
 	Dns             []string
+	DNSHijack       DNSHijack
+	Encryption      []DiskEncryption
 	FirewallEnabled string
 	FirewallStealth string
+	Listening       []Listener
+	FailedLogins    FailedLogins
 	LoggedInUsers   []string
+	Sessions        []UserSession
+	AdminUsers      []string
+	AccountCount    int
+	Sudo            string
+	SSH             SSHAudit
 	WifiEncryption  string
 	WifiName        string
+
+	// Errors holds why the probes of a section failed, by section
+	Errors map[string]error
+	// FetchedAt is when the probes of each section last ran
+	FetchedAt map[string]time.Time
+	// Changed holds the sections whose data changed in the last fetch
+	Changed map[string]bool
 }
 
 func NewSecurityData() *SecurityData {
-	return &SecurityData{}
+	return &SecurityData{
+		Errors:    make(map[string]error),
+		FetchedAt: make(map[string]time.Time),
+		Changed:   make(map[string]bool),
+	}
 }
 
 func (data SecurityData) DnsAt(idx int) string {
@@ -20,11 +62,117 @@ func (data SecurityData) DnsAt(idx int) string {
 	return ""
 }
 
-func (data *SecurityData) Fetch() {
-	data.Dns = DnsServers()
-	data.FirewallEnabled = FirewallState()
-	data.FirewallStealth = FirewallStealthState()
-	data.LoggedInUsers = LoggedInUsers()
-	data.WifiName = WifiName()
-	data.WifiEncryption = WifiEncryption()
+// sectionFetchers run the probes of each section
+var sectionFetchers = map[string]func(commands CommandRunner, data *SecurityData, settings *Settings) error{
+	sectionCertificates: fetchCertificates,
+	sectionDNS:          fetchDNS,
+	sectionEncryption:   fetchEncryption,
+	sectionFirewall:     fetchFirewall,
+	sectionListening:    fetchListening,
+	sectionLogins:       fetchLogins,
+	sectionSSH:          fetchSSH,
+	sectionUsers:        fetchUsers,
+	sectionWifi:         fetchWifi,
+}
+
+// Fetch runs the probes of the enabled sections only, as every probe shells out, and only
+// once the data of the section is older than its cache TTL unless forced. A probe that fails
+// only affects its own section
+func (data *SecurityData) Fetch(settings *Settings, commands CommandRunner, force bool) {
+	now := nowFunc()
+	data.Changed = make(map[string]bool)
+
+	for _, section := range settings.sections {
+		fetch, ok := sectionFetchers[section]
+		if !ok || (!force && !data.expired(section, settings.cacheTTLs[section], now)) {
+			continue
+		}
+
+		fetchedBefore := !data.FetchedAt[section].IsZero()
+		before := data.snapshot(section)
+
+		delete(data.Errors, section)
+		data.setError(section, fetch(commands, data, settings))
+		data.FetchedAt[section] = now
+
+		data.Changed[section] = fetchedBefore && data.snapshot(section) != before
+	}
+}
+
+func fetchCertificates(_ CommandRunner, data *SecurityData, settings *Settings) error {
+	data.Certificates = CheckCertificates(settings.certificates)
+	return nil
+}
+
+func fetchDNS(commands CommandRunner, data *SecurityData, settings *Settings) (err error) {
+	if settings.checkDNSHijack {
+		data.DNSHijack = CheckDNSHijack()
+	}
+
+	data.Dns, err = DnsServers(commands)
+	return err
+}
+
+func fetchEncryption(commands CommandRunner, data *SecurityData, _ *Settings) (err error) {
+	data.Encryption, err = DiskEncryptionState(commands)
+	return err
+}
+
+func fetchFirewall(commands CommandRunner, data *SecurityData, _ *Settings) error {
+	var err, stealthErr error
+
+	data.FirewallEnabled, err = FirewallState(commands)
+	data.FirewallStealth, stealthErr = FirewallStealthState(commands)
+
+	return cmp.Or(err, stealthErr)
+}
+
+func fetchListening(commands CommandRunner, data *SecurityData, _ *Settings) (err error) {
+	data.Listening, err = ListeningPorts(commands)
+	return err
+}
+
+func fetchLogins(commands CommandRunner, data *SecurityData, _ *Settings) (err error) {
+	data.FailedLogins, err = FailedLoginAttempts(commands)
+	return err
+}
+
+func fetchSSH(commands CommandRunner, data *SecurityData, _ *Settings) (err error) {
+	data.SSH, err = SSHState(commands)
+	return err
+}
+
+func fetchUsers(commands CommandRunner, data *SecurityData, _ *Settings) error {
+	var err, adminsErr, countErr, sudoErr error
+
+	// The sessions name their users. Where they aren't listed, or "who" can't list them,
+	// the users are listed on their own
+	data.Sessions, err = UserSessions(commands)
+	if err != nil || data.Sessions == nil {
+		data.Sessions = nil
+		data.LoggedInUsers, err = LoggedInUsers(commands)
+	} else {
+		data.LoggedInUsers = sessionUsers(data.Sessions)
+	}
+	data.AdminUsers, adminsErr = AdminUsers(commands)
+	data.AccountCount, countErr = LocalAccountCount(commands)
+	data.Sudo, sudoErr = SudoState(commands)
+
+	return cmp.Or(err, adminsErr, countErr, sudoErr)
+}
+
+func fetchWifi(commands CommandRunner, data *SecurityData, _ *Settings) error {
+	var err, encryptionErr error
+
+	data.WifiName, err = WifiName(commands)
+	data.WifiEncryption, encryptionErr = WifiEncryption(commands)
+
+	return cmp.Or(err, encryptionErr)
+}
+
+// setError records why a probe of the section failed, keeping the first failure
+func (data *SecurityData) setError(section string, err error) {
+	if err != nil && data.Errors[section] == nil {
+		data.Errors[section] = err
+	}
 }
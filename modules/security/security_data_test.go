@@ -0,0 +1,139 @@
+package security
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func Test_Fetch_OnlyEnabledSections(t *testing.T) {
+	original := sectionFetchers
+	t.Cleanup(func() { sectionFetchers = original })
+
+	fetched := []string{}
+	sectionFetchers = map[string]func(commands CommandRunner, data *SecurityData, settings *Settings) error{}
+	for _, section := range defaultSections {
+		sectionFetchers[section] = func(_ CommandRunner, data *SecurityData, settings *Settings) error {
+			fetched = append(fetched, section)
+			if section == sectionUsers {
+				return errors.New("who not installed")
+			}
+			return nil
+		}
+	}
+
+	data := NewSecurityData()
+	data.Fetch(&Settings{sections: []string{sectionUsers, sectionFirewall}}, fakeRunner{}, false)
+
+	assert.DeepEqual(t, []string{sectionUsers, sectionFirewall}, fetched)
+	assert.Error(t, data.Errors[sectionUsers], "who not installed")
+	assert.Equal(t, 1, len(data.Errors))
+}
+
+// fakeFetchers replaces the probes of every section with one that records the sections
+// it fetched, and sets the firewall state to firewall
+func fakeFetchers(t *testing.T, fetched *[]string, firewall *string) {
+	t.Helper()
+
+	original := sectionFetchers
+	t.Cleanup(func() { sectionFetchers = original })
+
+	sectionFetchers = map[string]func(commands CommandRunner, data *SecurityData, settings *Settings) error{}
+	for _, section := range defaultSections {
+		sectionFetchers[section] = func(_ CommandRunner, data *SecurityData, settings *Settings) error {
+			*fetched = append(*fetched, section)
+			if section == sectionFirewall {
+				data.FirewallEnabled = *firewall
+			}
+			return nil
+		}
+	}
+}
+
+func Test_Fetch_CacheTTLs(t *testing.T) {
+	fetched := []string{}
+	firewall := "Enabled"
+	fakeFetchers(t, &fetched, &firewall)
+
+	settings := &Settings{
+		sections:  []string{sectionFirewall, sectionUsers, sectionWifi},
+		cacheTTLs: map[string]time.Duration{sectionFirewall: 10 * time.Minute, sectionUsers: time.Minute},
+	}
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		after    time.Duration
+		force    bool
+		expected []string
+	}{
+		{name: "first fetch", after: 0, expected: []string{sectionFirewall, sectionUsers, sectionWifi}},
+		{name: "everything cached but wifi", after: 30 * time.Second, expected: []string{sectionWifi}},
+		{name: "users expired", after: time.Minute, expected: []string{sectionUsers, sectionWifi}},
+		{name: "firewall expired", after: 10 * time.Minute, expected: []string{sectionFirewall, sectionUsers, sectionWifi}},
+		{name: "forced", after: 10*time.Minute + time.Second, force: true, expected: []string{sectionFirewall, sectionUsers, sectionWifi}},
+		{name: "cached again after the forced fetch", after: 10*time.Minute + 2*time.Second, expected: []string{sectionWifi}},
+	}
+
+	data := NewSecurityData()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetched = []string{}
+			useNow(t, start.Add(tt.after))
+
+			data.Fetch(settings, fakeRunner{}, tt.force)
+
+			assert.DeepEqual(t, tt.expected, fetched)
+		})
+	}
+}
+
+func Test_Fetch_Changed(t *testing.T) {
+	fetched := []string{}
+	firewall := "Enabled"
+	fakeFetchers(t, &fetched, &firewall)
+
+	settings := &Settings{sections: []string{sectionFirewall, sectionUsers}, cacheTTLs: map[string]time.Duration{}}
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		firewall string
+		expected bool
+	}{
+		{name: "first fetch", firewall: "Enabled", expected: false},
+		{name: "unchanged", firewall: "Enabled", expected: false},
+		{name: "changed", firewall: "Disabled", expected: true},
+		{name: "marked for one fetch only", firewall: "Disabled", expected: false},
+	}
+
+	data := NewSecurityData()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			firewall = tt.firewall
+			useNow(t, start.Add(time.Duration(i)*time.Minute))
+
+			data.Fetch(settings, fakeRunner{}, false)
+
+			assert.Equal(t, tt.expected, data.Changed[sectionFirewall])
+			assert.Equal(t, false, data.Changed[sectionUsers])
+		})
+	}
+}
+
+func Test_SecurityData_clone(t *testing.T) {
+	data := NewSecurityData()
+	data.FirewallEnabled = "Enabled"
+	data.Errors[sectionWifi] = errors.New("nmcli not installed")
+
+	clone := data.clone()
+	clone.FirewallEnabled = "Disabled"
+	delete(clone.Errors, sectionWifi)
+	clone.FetchedAt[sectionWifi] = time.Now()
+
+	assert.Equal(t, "Enabled", data.FirewallEnabled)
+	assert.Error(t, data.Errors[sectionWifi], "nmcli not installed")
+	assert.Equal(t, 0, len(data.FetchedAt))
+}
@@ -0,0 +1,201 @@
+package security
+
+import (
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// idleUnknown is the idle time of sessions "who" can't tell it for
+	idleUnknown = time.Duration(-1)
+	// idleOld is the idle time of sessions "who" shows as "old", idle for more than a day
+	idleOld = 24 * time.Hour
+)
+
+// displayPattern matches the X displays local graphical sessions show as their origin, such
+// as ":0" or ":1.0"
+var displayPattern = regexp.MustCompile(`^:\d+(\.\d+)?$`)
+
+// multiplexerSuffix matches what screen and tmux add to the origin of the sessions they
+// run in, as in "192.168.1.20:S.0"
+var multiplexerSuffix = regexp.MustCompile(`:S\.\d+$`)
+
+// UserSession is a login of a user, as listed by "who -u"
+type UserSession struct {
+	User    string
+	TTY     string
+	LoginAt time.Time
+	// Idle is how long nothing was typed in the session, idleOld past a day, or idleUnknown
+	Idle time.Duration
+	// From is the host a remote session came from, empty for local sessions
+	From string
+}
+
+// sessionGroup holds the sessions of one user
+type sessionGroup struct {
+	user     string
+	sessions []UserSession
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+// UserSessions returns the sessions of the users logged in. Only Linux lists them, other
+// systems return nil
+func UserSessions(commands CommandRunner) ([]UserSession, error) {
+	if runtime.GOOS != "linux" {
+		return nil, nil
+	}
+
+	out, err := commands.Run("who", "-u")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseWho(out, nowFunc()), nil
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// parseWho reads the sessions "who -u" lists, in lines such as
+// "bob  pts/0  2026-10-16 09:12  00:05  5678 (192.168.1.20)". Login times are local, and
+// some locales write them as "Oct 16 09:12", without the year
+func parseWho(out string, now time.Time) []UserSession {
+	sessions := []UserSession{}
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+
+		loginAt, rest, ok := parseLoginTime(fields[2:], now)
+		if !ok {
+			continue
+		}
+
+		session := UserSession{
+			User:    fields[0],
+			TTY:     fields[1],
+			LoginAt: loginAt,
+			Idle:    idleUnknown,
+		}
+		if len(rest) > 0 {
+			session.Idle = parseIdle(rest[0])
+		}
+		session.From = sessionOrigin(line, session.TTY)
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions
+}
+
+// parseLoginTime reads the login time at the start of fields, returning the fields after it
+func parseLoginTime(fields []string, now time.Time) (time.Time, []string, bool) {
+	loginAt, err := time.ParseInLocation("2006-01-02 15:04", fields[0]+" "+fields[1], now.Location())
+	if err == nil {
+		return loginAt, fields[2:], true
+	}
+
+	if len(fields) < 3 {
+		return time.Time{}, nil, false
+	}
+
+	loginAt, err = time.ParseInLocation("Jan 2 15:04", strings.Join(fields[:3], " "), now.Location())
+	if err != nil {
+		return time.Time{}, nil, false
+	}
+
+	// Without a year, a date after today is from last year
+	loginAt = loginAt.AddDate(now.Year(), 0, 0)
+	if loginAt.After(now) {
+		loginAt = loginAt.AddDate(-1, 0, 0)
+	}
+
+	return loginAt, fields[3:], true
+}
+
+// parseIdle reads the idle column of "who -u": "." for a session used in the last minute,
+// "old" past a day, or hours and minutes such as "01:20"
+func parseIdle(idle string) time.Duration {
+	switch idle {
+	case ".":
+		return 0
+	case "old":
+		return idleOld
+	}
+
+	hours, minutes, found := strings.Cut(idle, ":")
+	if !found {
+		return idleUnknown
+	}
+
+	h, hErr := strconv.Atoi(hours)
+	m, mErr := strconv.Atoi(minutes)
+	if hErr != nil || mErr != nil {
+		return idleUnknown
+	}
+
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+}
+
+// sessionOrigin returns the host a session came from, read from the end of its line. Local
+// sessions show their display or terminal there, or nothing at all
+func sessionOrigin(line, tty string) string {
+	line = strings.TrimSpace(line)
+	start := strings.LastIndex(line, "(")
+	if start < 0 || !strings.HasSuffix(line, ")") {
+		return ""
+	}
+
+	origin := multiplexerSuffix.ReplaceAllString(line[start+1:len(line)-1], "")
+	if origin == "" || origin == tty || strings.HasPrefix(origin, "tty") || displayPattern.MatchString(origin) {
+		return ""
+	}
+
+	return origin
+}
+
+// sessionUsers returns the users of the sessions, once no matter how many they have
+func sessionUsers(sessions []UserSession) []string {
+	users := []string{}
+	for _, group := range groupSessions(sessions) {
+		users = append(users, group.user)
+	}
+
+	return users
+}
+
+// groupSessions groups the sessions by user, in the order the users first log in
+func groupSessions(sessions []UserSession) []sessionGroup {
+	groups := []sessionGroup{}
+	indexes := map[string]int{}
+
+	for _, session := range sessions {
+		idx, ok := indexes[session.User]
+		if !ok {
+			idx = len(groups)
+			indexes[session.User] = idx
+			groups = append(groups, sessionGroup{user: session.User})
+		}
+
+		groups[idx].sessions = append(groups[idx].sessions, session)
+	}
+
+	return groups
+}
+
+// sessionsSnapshot returns the sessions without their idle times, which change on every
+// fetch, so that only logins and logouts count as changes
+func sessionsSnapshot(sessions []UserSession) []UserSession {
+	snapshot := make([]UserSession, len(sessions))
+	for idx, session := range sessions {
+		session.Idle = 0
+		snapshot[idx] = session
+	}
+
+	return snapshot
+}
@@ -0,0 +1,118 @@
+package security
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+var sessionsNow = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+const whoOutput = `root     console      2026-10-16 07:58   .          812
+alice    tty2         2026-10-16 08:01 00:13        1234 (tty2)
+alice    pts/1        2026-10-16 09:30   .          2345 (:0)
+bob      pts/0        2026-10-16 09:12 00:02        5678 (2001:db8::1f)
+carol    pts/2        2026-10-12 18:40  old         9012 (203.0.113.9:S.0)
+dave     pts/3        2026-10-16 10:15   ?          3456 (::1)
+`
+
+func Test_parseWho(t *testing.T) {
+	sessions := parseWho(whoOutput, sessionsNow)
+
+	assert.DeepEqual(t, []UserSession{
+		{User: "root", TTY: "console", LoginAt: time.Date(2026, 10, 16, 7, 58, 0, 0, time.UTC), Idle: 0},
+		{User: "alice", TTY: "tty2", LoginAt: time.Date(2026, 10, 16, 8, 1, 0, 0, time.UTC), Idle: 13 * time.Minute},
+		{User: "alice", TTY: "pts/1", LoginAt: time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC), Idle: 0},
+		{User: "bob", TTY: "pts/0", LoginAt: time.Date(2026, 10, 16, 9, 12, 0, 0, time.UTC), Idle: 2 * time.Minute, From: "2001:db8::1f"},
+		{User: "carol", TTY: "pts/2", LoginAt: time.Date(2026, 10, 12, 18, 40, 0, 0, time.UTC), Idle: idleOld, From: "203.0.113.9"},
+		{User: "dave", TTY: "pts/3", LoginAt: time.Date(2026, 10, 16, 10, 15, 0, 0, time.UTC), Idle: idleUnknown, From: "::1"},
+	}, sessions)
+}
+
+func Test_parseWho_MonthDates(t *testing.T) {
+	sessions := parseWho("alice    pts/0        Oct 16 08:01 01:20        1234 (198.51.100.4)\n"+
+		"bob      pts/1        Dec 30 22:10  old         5678\n"+
+		"not a session\n", sessionsNow)
+
+	assert.DeepEqual(t, []UserSession{
+		{User: "alice", TTY: "pts/0", LoginAt: time.Date(2026, 10, 16, 8, 1, 0, 0, time.UTC), Idle: 80 * time.Minute, From: "198.51.100.4"},
+		// A date after today is from last year
+		{User: "bob", TTY: "pts/1", LoginAt: time.Date(2025, 12, 30, 22, 10, 0, 0, time.UTC), Idle: idleOld},
+	}, sessions)
+}
+
+func Test_groupSessions(t *testing.T) {
+	groups := groupSessions(parseWho(whoOutput, sessionsNow))
+
+	assert.Equal(t, 5, len(groups))
+	assert.Equal(t, "alice", groups[1].user)
+	assert.Equal(t, 2, len(groups[1].sessions))
+	assert.DeepEqual(t, []string{"root", "alice", "bob", "carol", "dave"}, sessionUsers(parseWho(whoOutput, sessionsNow)))
+}
+
+func Test_fetchUsers_Sessions(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("sessions are only listed on Linux")
+	}
+	useNow(t, sessionsNow)
+
+	commands := fakeRunner(map[string]cannedOutput{"who -u": {out: whoOutput}})
+
+	data := NewSecurityData()
+	_ = fetchUsers(commands, data, newTestSettings(t, "enabled: true"))
+	assert.Equal(t, 6, len(data.Sessions))
+	assert.DeepEqual(t, []string{"root", "alice", "bob", "carol", "dave"}, data.LoggedInUsers)
+
+	// Without -u, only the users are listed
+	commands = fakeRunner(map[string]cannedOutput{
+		"who -u": {err: errExitStatus},
+		"who":    {out: "alice    tty2         2026-10-16 08:01 (tty2)\n"},
+	})
+
+	data = NewSecurityData()
+	_ = fetchUsers(commands, data, newTestSettings(t, "enabled: true"))
+	assert.Assert(t, data.Sessions == nil)
+	assert.DeepEqual(t, []string{"alice"}, data.LoggedInUsers)
+}
+
+func Test_usersSection_Sessions(t *testing.T) {
+	useNow(t, sessionsNow)
+	widget, _ := newTestWidget(t, "idleThreshold: 1h", nil)
+
+	data := NewSecurityData()
+	data.Sessions = parseWho(whoOutput, sessionsNow)
+	data.LoggedInUsers = sessionUsers(data.Sessions)
+	data.AdminUsers = []string{"alice"}
+	data.AccountCount = 6
+
+	assert.Equal(t, " [red]Users[white]\n"+
+		"  root\n"+
+		"    console local        07:58  active\n"+
+		"  alice [yellow](admin)[white] ×2\n"+
+		"    tty2    local        08:01  idle 13m\n"+
+		"    pts/1   local        09:30  active\n"+
+		"  bob\n"+
+		"    [yellow]pts/0   2001:db8::1f 09:12  idle 2m[white]\n"+
+		"  carol\n"+
+		"    [gray]pts/2   203.0.113.9  Oct 12 idle >1d[white]\n"+
+		"  dave\n"+
+		"    [yellow]pts/3   ::1          10:15[white]\n"+
+		"  [gray]5 of 6 accounts logged in[white]\n\n", widget.usersSection(data))
+}
+
+func Test_snapshot_SessionsIdle(t *testing.T) {
+	data := NewSecurityData()
+	data.Sessions = parseWho(whoOutput, sessionsNow)
+	before := data.snapshot(sectionUsers)
+
+	// Idle times changing isn't a change
+	data.Sessions = parseWho(whoOutput, sessionsNow)
+	data.Sessions[1].Idle = 20 * time.Minute
+	assert.Equal(t, before, data.snapshot(sectionUsers))
+
+	// A logout is
+	data.Sessions = data.Sessions[1:]
+	assert.Assert(t, before != data.snapshot(sectionUsers))
+}
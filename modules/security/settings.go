@@ -1,23 +1,151 @@
 package security
 
 import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/olebedev/config"
 	"github.com/wtfutil/wtf/cfg"
+	"github.com/wtfutil/wtf/utils"
 )
 
 const (
-	defaultFocusable = false
-	defaultTitle     = "Security"
+	defaultFocusable       = true
+	defaultTitle           = "Security"
+	defaultMaxListen       = 10
+	defaultWarnDays        = 21
+	defaultCritDays        = 7
+	defaultMaxLogins       = 3
+	defaultLoginsThreshold = 10
+	defaultScoreWarn       = 80
+	defaultScoreCrit       = 50
 )
 
+// defaultSections are the sections shown, in order, when none are configured
+var defaultSections = []string{sectionScore, sectionWifi, sectionFirewall, sectionEncryption, sectionListening, sectionSSH, sectionLogins, sectionUsers, sectionDNS, sectionCertificates}
+
+// defaultCacheTTLs are how long the data of each section is kept before its probes run
+// again, for the sections that rarely change. The other sections are fetched on every refresh
+var defaultCacheTTLs = map[string]string{
+	sectionCertificates: "1h",
+	sectionDNS:          "1m",
+	sectionEncryption:   "10m",
+	sectionFirewall:     "10m",
+	sectionListening:    "1m",
+	sectionLogins:       "5m",
+	sectionSSH:          "10m",
+	sectionUsers:        "1m",
+}
+
 type Settings struct {
 	*cfg.Common
+
+	sections  []string                 `help:"The sections to show, in order. Sections that aren't listed aren't shown, nor fetched." values:"A list of score, wifi, firewall, encryption, listening, ssh, logins, users, dns or certificates" optional:"true"`
+	cacheTTLs map[string]time.Duration `help:"How long the data of each section is kept before it is fetched again, by section. Pressing R fetches every section anyway." values:"A duration (ex: 30s, 10m), 0 to fetch on every refresh. Defaults to 10m for firewall, encryption and ssh, 5m for logins, 1h for certificates, 1m for users, dns and listening, and 0 for wifi" optional:"true"`
+
+	scoreWeights map[string]int `help:"How much each check counts in the score, by name, 1 by default. A weight of 0 leaves the check out." values:"certificates, dns, dnsHijack, encryption, firewall, listening, logins, sshPasswords, sshRoot, stealth, sudo, trustedWifi or wifi" optional:"true"`
+	scoreWarn    int            `help:"The percentage of the score under which the title is yellow." optional:"true" default:"80"`
+	scoreCrit    int            `help:"The percentage of the score under which the title is red." optional:"true" default:"50"`
+
+	certificates []string `help:"The hosts whose TLS certificates are checked for expiry." values:"A list of host:port, the port defaulting to 443" optional:"true"`
+	warnDays     int      `help:"Certificates expiring in fewer days than this are shown in yellow." optional:"true" default:"21"`
+	critDays     int      `help:"Certificates expiring in fewer days than this are shown in red." optional:"true" default:"7"`
+
+	allowedPorts  []int `help:"Ports that are expected to be listened on, which the listening section leaves out." values:"A list of port numbers" optional:"true"`
+	hideLocalhost bool  `help:"Whether the listening section leaves out the ports that only accept connections from this machine." optional:"true" default:"false"`
+	maxListening  int   `help:"How many listening ports to show at most." values:"A positive integer, or 0 for all" optional:"true" default:"10"`
+
+	failedLoginsThreshold int `help:"More failed logins than this in the last day are shown in red." optional:"true" default:"10"`
+	maxFailedLogins       int `help:"How many of the most recent failed logins to show." optional:"true" default:"3"`
+
+	expectedDNS    []string `help:"The DNS servers you expect to be configured. Any other server is shown in red." values:"A list of IP addresses" optional:"true"`
+	checkDNSHijack bool     `help:"Whether to check that DNS answers aren't made up along the way, by resolving a domain that doesn't exist." optional:"true" default:"false"`
+
+	idleThreshold time.Duration `help:"Sessions idle for longer than this are shown in gray." values:"A duration (ex: 30m, 2h)" optional:"true" default:"1h"`
+
+	commandTimeout time.Duration `help:"How long each command the probes run may take before it is killed." values:"A duration (ex: 5s, 500ms)" optional:"true" default:"5s"`
+
+	trustedNetworks []string `help:"The Wi-Fi networks you trust, by SSID. Any other network is shown in yellow." values:"A list of SSIDs, matched exactly" optional:"true"`
+
+	// unknownSections are the names in sections that aren't a section
+	unknownSections []string
 }
 
 func NewSettingsFromYAML(name string, ymlConfig *config.Config, globalConfig *config.Config) *Settings {
 	settings := Settings{
 		Common: cfg.NewCommonSettingsFromModule(name, defaultTitle, defaultFocusable, ymlConfig, globalConfig),
+
+		cacheTTLs:    map[string]time.Duration{},
+		scoreWeights: map[string]int{},
+		scoreWarn:    ymlConfig.UInt("scoreWarn", defaultScoreWarn),
+		scoreCrit:    ymlConfig.UInt("scoreCrit", defaultScoreCrit),
+
+		certificates: utils.ToStrs(ymlConfig.UList("certificates")),
+		warnDays:     ymlConfig.UInt("warnDays", defaultWarnDays),
+		critDays:     ymlConfig.UInt("critDays", defaultCritDays),
+
+		allowedPorts:  utils.ToInts(ymlConfig.UList("allowedPorts")),
+		hideLocalhost: ymlConfig.UBool("hideLocalhost", false),
+		maxListening:  ymlConfig.UInt("maxListening", defaultMaxListen),
+
+		failedLoginsThreshold: ymlConfig.UInt("failedLoginsThreshold", defaultLoginsThreshold),
+		maxFailedLogins:       ymlConfig.UInt("maxFailedLogins", defaultMaxLogins),
+
+		expectedDNS:    utils.ToStrs(ymlConfig.UList("expectedDNS")),
+		checkDNSHijack: ymlConfig.UBool("checkDNSHijack", false),
+
+		idleThreshold: cfg.ParseTimeString(ymlConfig, "idleThreshold", "1h"),
+
+		commandTimeout: cfg.ParseTimeString(ymlConfig, "commandTimeout", "5s"),
+
+		trustedNetworks: utils.ToStrs(ymlConfig.UList("trustedNetworks")),
+	}
+
+	for _, check := range scoreCheckNames {
+		settings.scoreWeights[check] = ymlConfig.UInt("scoreWeights."+check, 1)
+	}
+
+	for _, section := range defaultSections {
+		settings.cacheTTLs[section] = cfg.ParseTimeString(ymlConfig, "cacheTTLs."+section, cmp.Or(defaultCacheTTLs[section], "0s"))
+	}
+
+	names := defaultSections
+	if list, err := ymlConfig.List("sections"); err == nil {
+		names = utils.ToStrs(list)
 	}
+	settings.sections, settings.unknownSections = parseSections(names)
 
 	return &settings
 }
+
+// parseSections splits the configured section names into the known sections, without
+// duplicates, and the unknown names
+func parseSections(names []string) (sections []string, unknown []string) {
+	sections = []string{}
+	unknown = []string{}
+
+	for _, name := range names {
+		switch {
+		case !slices.Contains(defaultSections, name):
+			if !slices.Contains(unknown, name) {
+				unknown = append(unknown, name)
+			}
+		case !slices.Contains(sections, name):
+			sections = append(sections, name)
+		}
+	}
+
+	return sections, unknown
+}
+
+// unknownSectionsErr describes the section names that were ignored, or returns nil
+func (settings *Settings) unknownSectionsErr() error {
+	if len(settings.unknownSections) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("unknown sections ignored: %s", strings.Join(settings.unknownSections, ", "))
+}
@@ -0,0 +1,31 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func Test_NewSettingsFromYAML_Sections(t *testing.T) {
+	settings := newTestSettings(t, "enabled: true")
+	assert.DeepEqual(t, defaultSections, settings.sections)
+	assert.NilError(t, settings.unknownSectionsErr())
+
+	settings = newTestSettings(t, "sections: [firewall, Users, dns, firewall, Users, wlan]")
+	assert.DeepEqual(t, []string{sectionFirewall, sectionDNS}, settings.sections)
+	assert.Error(t, settings.unknownSectionsErr(), "unknown sections ignored: Users, wlan")
+}
+
+func Test_NewSettingsFromYAML_CacheTTLs(t *testing.T) {
+	settings := newTestSettings(t, "enabled: true")
+	assert.Equal(t, 10*time.Minute, settings.cacheTTLs[sectionFirewall])
+	assert.Equal(t, time.Minute, settings.cacheTTLs[sectionUsers])
+	assert.Equal(t, time.Duration(0), settings.cacheTTLs[sectionWifi])
+
+	settings = newTestSettings(t, "cacheTTLs: {firewall: 30s, users: 120, wifi: 1m}")
+	assert.Equal(t, 30*time.Second, settings.cacheTTLs[sectionFirewall])
+	assert.Equal(t, 2*time.Minute, settings.cacheTTLs[sectionUsers])
+	assert.Equal(t, time.Minute, settings.cacheTTLs[sectionWifi])
+	assert.Equal(t, 10*time.Minute, settings.cacheTTLs[sectionSSH])
+}
@@ -0,0 +1,138 @@
+package security
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// sshdConfigPath is where the configuration of the SSH server is read from
+var sshdConfigPath = defaultSSHDConfigPath()
+
+// SSHAudit is the configuration of the SSH server, and the keys loaded in the SSH agent
+type SSHAudit struct {
+	// SSHDInstalled is set when there is an SSH server configuration to audit
+	SSHDInstalled          bool
+	PasswordAuthentication string
+	PermitRootLogin        string
+	Ports                  []string
+	// Includes are the files the configuration includes, which aren't evaluated
+	Includes []string
+
+	AgentKeys []string
+	// AgentStatus says why there are no agent keys, if there aren't any
+	AgentStatus string
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+func SSHState(commands CommandRunner) (SSHAudit, error) {
+	audit := SSHAudit{}
+
+	contents, err := os.ReadFile(sshdConfigPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return audit, err
+	default:
+		audit = parseSSHDConfig(string(contents))
+	}
+
+	audit.AgentKeys, audit.AgentStatus = sshAgentKeys(commands)
+
+	return audit, nil
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+func defaultSSHDConfigPath() string {
+	if runtime.GOOS == "windows" {
+		return `C:\ProgramData\ssh\sshd_config`
+	}
+	return "/etc/ssh/sshd_config"
+}
+
+// parseSSHDConfig reads the settings the audit is about. As sshd does, the first value of a
+// setting is the one used, and settings in Match blocks only apply to some connections so
+// they're ignored
+func parseSSHDConfig(contents string) SSHAudit {
+	audit := SSHAudit{SSHDInstalled: true}
+
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Keywords and values are separated by spaces or an equal sign
+		keyword, value, _ := strings.Cut(strings.Replace(line, "=", " ", 1), " ")
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(keyword) {
+		case "match":
+			return withSSHDDefaults(audit)
+		case "include":
+			audit.Includes = append(audit.Includes, strings.Fields(value)...)
+		case "passwordauthentication":
+			if audit.PasswordAuthentication == "" {
+				audit.PasswordAuthentication = strings.ToLower(value)
+			}
+		case "permitrootlogin":
+			if audit.PermitRootLogin == "" {
+				audit.PermitRootLogin = strings.ToLower(value)
+			}
+		case "port":
+			// Unlike the others, every port listed is listened on
+			audit.Ports = append(audit.Ports, value)
+		}
+	}
+
+	return withSSHDDefaults(audit)
+}
+
+// withSSHDDefaults fills in what sshd uses for the settings that aren't configured
+func withSSHDDefaults(audit SSHAudit) SSHAudit {
+	if audit.PasswordAuthentication == "" {
+		audit.PasswordAuthentication = "yes"
+	}
+	if audit.PermitRootLogin == "" {
+		audit.PermitRootLogin = "prohibit-password"
+	}
+	if len(audit.Ports) == 0 {
+		audit.Ports = []string{"22"}
+	}
+
+	return audit
+}
+
+// sshAgentKeys lists the keys loaded in the SSH agent, as "comment (type)", or says why
+// there are none
+func sshAgentKeys(commands CommandRunner) ([]string, string) {
+	out, err := commands.Run("ssh-add", "-l")
+
+	// Exits with an error when the agent has no keys, or isn't running
+	if strings.Contains(out, "no identities") {
+		return nil, "no keys loaded"
+	}
+	if errors.Is(err, errNotInstalled) {
+		return nil, "ssh-add not installed"
+	}
+	if err != nil {
+		return nil, "no agent running"
+	}
+
+	keys := []string{}
+
+	// Lines look like "256 SHA256:Jt1k… alice@laptop (ED25519)"
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		keys = append(keys, strings.Join(fields[2:], " "))
+	}
+
+	return keys, ""
+}
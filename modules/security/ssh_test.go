@@ -0,0 +1,125 @@
+package security
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const hardenedSSHDConfig = `# Generated by the installer
+Include /etc/ssh/sshd_config.d/*.conf
+
+Port 2222
+Port 22
+PermitRootLogin no
+PasswordAuthentication=no
+PasswordAuthentication yes
+
+Match User deploy
+	PasswordAuthentication yes
+	PermitRootLogin yes
+`
+
+const defaultSSHDConfig = `#Port 22
+#PermitRootLogin prohibit-password
+#PasswordAuthentication yes
+KbdInteractiveAuthentication no
+UsePAM yes
+`
+
+func useSSHDConfig(t *testing.T, contents string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "sshd_config")
+	if contents != "" {
+		assert.NilError(t, os.WriteFile(path, []byte(contents), 0o644))
+	}
+
+	original := sshdConfigPath
+	t.Cleanup(func() { sshdConfigPath = original })
+	sshdConfigPath = path
+}
+
+func Test_SSHState(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		agent    cannedOutput
+		expected SSHAudit
+	}{
+		{
+			name:   "hardened, with keys",
+			config: hardenedSSHDConfig,
+			agent: cannedOutput{out: "256 SHA256:Jt1kq4nMFTl0kpvfHc0G2jwgGmfL8W/E4gZ7cDptMDs alice@laptop (ED25519)\n" +
+				"3072 SHA256:0c3MYq5z3N0a+xRk2tJzLfO3kq2yY8Yk1hQ1XyY2X2o work key (RSA)\n"},
+			expected: SSHAudit{
+				SSHDInstalled:          true,
+				PasswordAuthentication: "no",
+				PermitRootLogin:        "no",
+				Ports:                  []string{"2222", "22"},
+				Includes:               []string{"/etc/ssh/sshd_config.d/*.conf"},
+				AgentKeys:              []string{"alice@laptop (ED25519)", "work key (RSA)"},
+			},
+		},
+		{
+			name:   "defaults, without keys",
+			config: defaultSSHDConfig,
+			agent:  cannedOutput{out: "The agent has no identities.\n", err: errExitStatus},
+			expected: SSHAudit{
+				SSHDInstalled:          true,
+				PasswordAuthentication: "yes",
+				PermitRootLogin:        "prohibit-password",
+				Ports:                  []string{"22"},
+				AgentStatus:            "no keys loaded",
+			},
+		},
+		{
+			name:  "no sshd, nor agent",
+			agent: cannedOutput{err: errExitStatus},
+			expected: SSHAudit{
+				AgentStatus: "no agent running",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useSSHDConfig(t, tt.config)
+			commands := fakeRunner(map[string]cannedOutput{"ssh-add -l": tt.agent})
+
+			audit, err := SSHState(commands)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tt.expected, audit)
+		})
+	}
+}
+
+func Test_sshSection(t *testing.T) {
+	widget, _ := newTestWidget(t, "enabled: true", nil)
+
+	data := NewSecurityData()
+	data.SSH = SSHAudit{
+		SSHDInstalled:          true,
+		PasswordAuthentication: "yes",
+		PermitRootLogin:        "yes",
+		Ports:                  []string{"22"},
+		Includes:               []string{"/etc/ssh/sshd_config.d/*.conf"},
+		AgentKeys:              []string{"alice@laptop (ED25519)", "work key (RSA)"},
+	}
+
+	assert.Equal(t, " [red]SSH[white]\n"+
+		" Password: [red]yes[white]\n"+
+		"     Root: [red]yes[white]\n"+
+		"     Port: 22\n"+
+		"  [gray]not evaluated: /etc/ssh/sshd_config.d/*.conf[white]\n"+
+		"    Agent: alice@laptop (ED25519)\n"+
+		"           work key (RSA)\n\n", widget.sshSection(data))
+
+	data.SSH = SSHAudit{AgentStatus: "ssh-add not installed"}
+
+	assert.Equal(t, " [red]SSH[white]\n"+
+		"     sshd: not installed\n"+
+		"    Agent: ssh-add not installed\n\n", widget.sshSection(data))
+}
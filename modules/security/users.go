@@ -3,25 +3,25 @@ package security
 // http://applehelpwriter.com/2017/05/21/how-to-reveal-hidden-users/
 
 import (
-	"os/exec"
+	"errors"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
-
-	"github.com/wtfutil/wtf/utils"
 )
 
 /* -------------------- Exported Functions -------------------- */
 
-func LoggedInUsers() []string {
+func LoggedInUsers(commands CommandRunner) ([]string, error) {
 	switch runtime.GOOS {
 	case "linux":
-		return loggedInUsersLinux()
+		return loggedInUsersLinux(commands)
 	case "darwin":
-		return loggedInUsersMacOs()
+		return loggedInUsersMacOs(commands)
 	case "windows":
-		return loggedInUsersWindows()
+		return loggedInUsersWindows(commands)
 	default:
-		return []string{}
+		return []string{}, nil
 	}
 }
 
@@ -49,40 +49,71 @@ func cleanUsers(users []string) []string {
 	return cleaned
 }
 
-func loggedInUsersLinux() []string {
-	cmd := exec.Command("who", "-us")
-	users := utils.ExecuteCommand(cmd)
+// loggedInUsersLinux returns everyone with a session, once no matter how many they have
+func loggedInUsersLinux(commands CommandRunner) ([]string, error) {
+	out, err := commands.Run("who")
+	if err != nil {
+		return nil, err
+	}
 
-	cleaned := []string{}
+	users := []string{}
+	seen := map[string]bool{}
 
-	for _, user := range strings.Split(users, "\n") {
-		clean := true
-		col := strings.Split(user, " ")
-
-		if len(col) > 0 {
-			for _, cleanedU := range cleaned {
-				u := strings.TrimSpace(col[0])
-				if u == "" || strings.Compare(cleanedU, col[0]) == 0 {
-					clean = false
-				}
-			}
-			if clean {
-				cleaned = append(cleaned, col[0])
-			}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || seen[fields[0]] {
+			continue
 		}
+
+		seen[fields[0]] = true
+		users = append(users, fields[0])
 	}
 
-	return cleaned
+	return users, nil
 }
 
-func loggedInUsersMacOs() []string {
-	cmd := exec.Command("dscl", []string{".", "-list", "/Users"}...)
-	users := utils.ExecuteCommand(cmd)
+func loggedInUsersMacOs(commands CommandRunner) ([]string, error) {
+	users, err := commands.Run("dscl", ".", "-list", "/Users")
+	if err != nil {
+		return nil, err
+	}
+
+	return cleanUsers(strings.Split(users, "\n")), nil
+}
+
+// loggedInUsersWindows returns everyone with a session, as listed by "query user". That
+// isn't installed on every edition, which leaves the current user
+func loggedInUsersWindows(commands CommandRunner) ([]string, error) {
+	out, err := commands.Run("query", "user")
+	if !errors.Is(err, errNotInstalled) {
+		if err != nil {
+			return nil, err
+		}
+		return parseQueryUser(out), nil
+	}
+
+	return currentUserWindows(commands)
+}
+
+// parseQueryUser returns the users "query user" lists, once no matter how many sessions they
+// have. The current session is marked with a ">" in front of its user
+func parseQueryUser(out string) []string {
+	users := []string{}
+
+	for i, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), ">"))
+		// The first line is the header (" USERNAME   SESSIONNAME   ID  STATE ...")
+		if i == 0 || len(fields) == 0 || slices.Contains(users, fields[0]) {
+			continue
+		}
+
+		users = append(users, fields[0])
+	}
 
-	return cleanUsers(strings.Split(users, "\n"))
+	return users
 }
 
-func loggedInUsersWindows() []string {
+func currentUserWindows(commands CommandRunner) ([]string, error) {
 	// We can use either one:
 	// 		(Get-WMIObject -class Win32_ComputerSystem | select username).username
 	// 		[System.Security.Principal.WindowsIdentity]::GetCurrent().Name
@@ -92,9 +123,166 @@ func loggedInUsersWindows() []string {
 	// The real powershell command reads:
 	// 	 powershell.exe -NoProfile -Command "& { [System.Security.Principal.WindowsIdentity]::GetCurrent().Name }"
 	// But we here have to write it as:
-	cmd := exec.Command("powershell.exe", "-NoProfile", "-Command", "& { [System.Security.Principal.WindowsIdentity]::GetCurrent().Name }")
-	// ToDo:  Make list for multi-user systems
+	users, err := commands.Run("powershell.exe", "-NoProfile", "-Command", "& { [System.Security.Principal.WindowsIdentity]::GetCurrent().Name }")
+	if err != nil {
+		return nil, err
+	}
+
+	// PowerShell ends its lines with "\r\n"
+	return cleanUsers(strings.Split(strings.ReplaceAll(users, "\r", ""), "\n")), nil
+}
+
+/* -------------------- Admins and sudo -------------------- */
+
+const (
+	sudoPasswordless     = "passwordless"
+	sudoPasswordRequired = "password required"
+	sudoNotAllowed       = "not allowed"
+)
+
+// adminGroups are the groups whose members can become root
+var adminGroups = []string{"admin", "sudo", "wheel"}
+
+// AdminUsers returns the members of the admin groups
+func AdminUsers(commands CommandRunner) ([]string, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return adminUsersLinux(commands)
+	case "darwin":
+		return adminUsersMacOS(commands)
+	default:
+		return []string{}, nil
+	}
+}
+
+// LocalAccountCount returns how many accounts there are for people, leaving out the ones of
+// system services
+func LocalAccountCount(commands CommandRunner) (int, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return localAccountCountLinux(commands)
+	case "darwin":
+		return localAccountCountMacOS(commands)
+	default:
+		return 0, nil
+	}
+}
 
-	users := utils.ExecuteCommand(cmd)
-	return cleanUsers(strings.Split(users, "\n"))
+// SudoState returns whether the current user can use sudo, and if so whether a password is
+// needed for any of the commands
+func SudoState(commands CommandRunner) (string, error) {
+	if runtime.GOOS == "windows" {
+		return "", nil
+	}
+
+	// -n fails rather than prompting when a password would be needed to list the commands
+	out, err := commands.Run("sudo", "-l", "-n")
+	switch {
+	case errors.Is(err, errNotInstalled):
+		return "", nil
+	case err != nil && strings.Contains(err.Error(), "password is required"):
+		return sudoPasswordRequired, nil
+	case err != nil && strings.Contains(err.Error(), "may not run sudo"):
+		return sudoNotAllowed, nil
+	case err != nil:
+		return "", err
+	}
+
+	return parseSudoList(out), nil
+}
+
+func adminUsersLinux(commands CommandRunner) ([]string, error) {
+	// Exits with an error when some of the groups don't exist, but lists the others
+	out, err := commands.Run("getent", append([]string{"group"}, adminGroups...)...)
+	if errors.Is(err, errNotInstalled) {
+		return nil, err
+	}
+
+	admins := []string{}
+
+	// Lines look like "sudo:x:27:alice,bob"
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), ":")
+		if len(fields) != 4 || fields[3] == "" {
+			continue
+		}
+
+		for _, member := range strings.Split(fields[3], ",") {
+			if !slices.Contains(admins, member) {
+				admins = append(admins, member)
+			}
+		}
+	}
+
+	return admins, nil
+}
+
+func adminUsersMacOS(commands CommandRunner) ([]string, error) {
+	out, err := commands.Run("dscl", ".", "-read", "/Groups/admin", "GroupMembership")
+	if err != nil {
+		return nil, err
+	}
+
+	// Looks like "GroupMembership: root alice"
+	_, members, _ := strings.Cut(out, ":")
+	return strings.Fields(members), nil
+}
+
+func localAccountCountLinux(commands CommandRunner) (int, error) {
+	out, err := commands.Run("getent", "passwd")
+	if err != nil {
+		return 0, err
+	}
+
+	// Lines look like "alice:x:1000:1000:Alice,,,:/home/alice:/bin/bash". People get the
+	// ids from 1000 on, up to nobody's
+	return countAccounts(out, ":", 2, 1000), nil
+}
+
+func localAccountCountMacOS(commands CommandRunner) (int, error) {
+	out, err := commands.Run("dscl", ".", "-list", "/Users", "UniqueID")
+	if err != nil {
+		return 0, err
+	}
+
+	// Lines look like "alice   501". People get the ids from 500 on
+	return countAccounts(out, "", 1, 500), nil
+}
+
+// countAccounts counts the lines whose user id, in the given field, is at least minUID.
+// Fields are split by sep, or by spaces when it's empty
+func countAccounts(out string, sep string, uidField int, minUID int) int {
+	count := 0
+
+	for _, line := range strings.Split(out, "\n") {
+		var fields []string
+		if sep == "" {
+			fields = strings.Fields(line)
+		} else {
+			fields = strings.Split(line, sep)
+		}
+		if len(fields) <= uidField {
+			continue
+		}
+
+		uid, err := strconv.Atoi(fields[uidField])
+		if err == nil && uid >= minUID && uid != 65534 {
+			count++
+		}
+	}
+
+	return count
+}
+
+// parseSudoList reads the commands "sudo -l" lists, such as "(ALL) NOPASSWD: /usr/bin/apt"
+func parseSudoList(out string) string {
+	_, commands, found := strings.Cut(out, "may run the following commands")
+	if !found {
+		return sudoNotAllowed
+	}
+
+	if strings.Contains(commands, "NOPASSWD") {
+		return sudoPasswordless
+	}
+	return sudoPasswordRequired
 }
@@ -0,0 +1,168 @@
+package security
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_loggedInUsersLinux(t *testing.T) {
+	commands := fakeRunner(map[string]cannedOutput{
+		"who": {out: "alice    tty2         2026-10-16 08:01 (tty2)\n" +
+			"bob      pts/0        2026-10-16 09:12 (192.168.1.20)\n" +
+			"alice    pts/1        2026-10-16 09:30 (:0)\n"},
+	})
+
+	users, err := loggedInUsersLinux(commands)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"alice", "bob"}, users)
+}
+
+func Test_loggedInUsersLinux_NotInstalled(t *testing.T) {
+	commands := fakeRunner(map[string]cannedOutput{})
+
+	_, err := loggedInUsersLinux(commands)
+	assert.Error(t, err, "who not installed")
+}
+
+func Test_loggedInUsersWindows(t *testing.T) {
+	commands := fakeRunner(map[string]cannedOutput{
+		"query user": {out: " USERNAME              SESSIONNAME        ID  STATE   IDLE TIME  LOGON TIME\r\n" +
+			">alice                 console             1  Active      none   10/16/2026 8:01 AM\r\n" +
+			" bob                   rdp-tcp#3           2  Active         5   10/16/2026 9:12 AM\r\n" +
+			" alice                                     3  Disc        1:02   10/15/2026 6:40 PM\r\n"},
+	})
+
+	users, err := loggedInUsersWindows(commands)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"alice", "bob"}, users)
+}
+
+func Test_loggedInUsersWindows_WithoutQuery(t *testing.T) {
+	currentUser := "powershell.exe -NoProfile -Command & { [System.Security.Principal.WindowsIdentity]::GetCurrent().Name }"
+	commands := fakeRunner(map[string]cannedOutput{currentUser: {out: "DESKTOP-1234\\alice\r\n"}})
+
+	users, err := loggedInUsersWindows(commands)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"DESKTOP-1234\\alice"}, users)
+
+	commands = fakeRunner(map[string]cannedOutput{"query user": {err: errExitStatus}})
+
+	_, err = loggedInUsersWindows(commands)
+	assert.Error(t, err, "query: exit status 1")
+}
+
+func Test_adminUsersLinux(t *testing.T) {
+	commands := fakeRunner(map[string]cannedOutput{
+		// There's no admin group, which getent reports by exiting with an error
+		"getent group admin sudo wheel": {out: "sudo:x:27:alice,bob\nwheel:x:10:\n", err: errExitStatus},
+	})
+
+	admins, err := adminUsersLinux(commands)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"alice", "bob"}, admins)
+}
+
+func Test_adminUsersMacOS(t *testing.T) {
+	commands := fakeRunner(map[string]cannedOutput{
+		"dscl . -read /Groups/admin GroupMembership": {out: "GroupMembership: root alice\n"},
+	})
+
+	admins, err := adminUsersMacOS(commands)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"root", "alice"}, admins)
+}
+
+func Test_localAccountCount(t *testing.T) {
+	commands := fakeRunner(map[string]cannedOutput{
+		"getent passwd": {out: "root:x:0:0:root:/root:/bin/bash\n" +
+			"systemd-network:x:998:998:systemd Network Management:/:/usr/sbin/nologin\n" +
+			"nobody:x:65534:65534:nobody:/nonexistent:/usr/sbin/nologin\n" +
+			"alice:x:1000:1000:Alice,,,:/home/alice:/bin/bash\n" +
+			"bob:x:1001:1001:Bob,,,:/home/bob:/bin/zsh\n"},
+		"dscl . -list /Users UniqueID": {out: "_www     70\nalice    501\nbob      502\ndaemon   1\nnobody   -2\nroot     0\n"},
+	})
+
+	count, err := localAccountCountLinux(commands)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = localAccountCountMacOS(commands)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func Test_SudoState(t *testing.T) {
+	const sudoList = `Matching Defaults entries for alice on laptop:
+    env_reset, mail_badpass, secure_path=/usr/local/sbin\:/usr/local/bin\:/usr/sbin\:/usr/bin
+
+User alice may run the following commands on laptop:
+    (ALL : ALL) ALL
+`
+
+	tests := []struct {
+		name     string
+		output   *cannedOutput
+		expected string
+	}{
+		{
+			name:     "password required",
+			output:   &cannedOutput{out: sudoList},
+			expected: sudoPasswordRequired,
+		},
+		{
+			name:     "passwordless",
+			output:   &cannedOutput{out: sudoList + "    (ALL) NOPASSWD: /usr/bin/apt\n"},
+			expected: sudoPasswordless,
+		},
+		{
+			name:     "prompts",
+			output:   &cannedOutput{err: errors.New("sudo: a password is required")},
+			expected: sudoPasswordRequired,
+		},
+		{
+			name:     "not allowed",
+			output:   &cannedOutput{err: errors.New("Sorry, user bob may not run sudo on laptop.")},
+			expected: sudoNotAllowed,
+		},
+		{
+			name:     "not installed",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputs := map[string]cannedOutput{}
+			if tt.output != nil {
+				outputs["sudo -l -n"] = *tt.output
+			}
+			commands := fakeRunner(outputs)
+
+			state, err := SudoState(commands)
+			assert.NilError(t, err)
+			assert.Equal(t, tt.expected, state)
+		})
+	}
+}
+
+func Test_usersSection(t *testing.T) {
+	widget, _ := newTestWidget(t, "enabled: true", nil)
+
+	data := NewSecurityData()
+	data.LoggedInUsers = []string{"alice", "bob"}
+	data.AdminUsers = []string{"root", "alice"}
+	data.AccountCount = 5
+	data.Sudo = sudoPasswordless
+
+	assert.Equal(t, " [red]Users[white]\n"+
+		"  alice [yellow](admin)[white]\n"+
+		"  bob\n"+
+		"  [gray]2 of 5 accounts logged in[white]\n"+
+		"  [yellow]passwordless sudo enabled[white]\n\n", widget.usersSection(data))
+
+	data.Sudo = sudoPasswordRequired
+	assert.Assert(t, !strings.Contains(widget.usersSection(data), "sudo"))
+}
@@ -1,16 +1,32 @@
 package security
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/rivo/tview"
+	"github.com/wtfutil/wtf/utils"
 	"github.com/wtfutil/wtf/view"
 )
 
 type Widget struct {
 	view.TextWidget
 
+	// data is the result of the last fetch, nil until the first one is done
+	data     *SecurityData
+	mu       sync.Mutex
+	inFlight atomic.Bool
+	// force is set when the next fetch has to run every probe, ignoring the caches
+	force atomic.Bool
+	fetch func(prev *SecurityData, settings *Settings, commands CommandRunner, force bool) *SecurityData
+	// commands runs the commands the probes shell out to
+	commands CommandRunner
+
 	settings *Settings
 }
 
@@ -18,56 +34,483 @@ func NewWidget(tviewApp *tview.Application, redrawChan chan bool, settings *Sett
 	widget := Widget{
 		TextWidget: view.NewTextWidget(tviewApp, redrawChan, nil, settings.Common),
 
+		fetch:    fetchSecurityData,
+		commands: newExecRunner(settings.commandTimeout),
+
 		settings: settings,
 	}
 
+	// Its content is taller than most cells. It is redrawn on every refresh and again when
+	// the fetch is done, so the scroll position is kept rather than going back to the top
+	widget.EnableScrolling(true)
+	widget.initializeKeyboardControls()
+
 	return &widget
 }
 
 /* -------------------- Exported Functions -------------------- */
 
+// Refresh starts fetching the data in the background, unless a fetch is already running,
+// and redraws what is cached in the meantime
 func (widget *Widget) Refresh() {
-
 	if widget.Disabled() {
 		return
 	}
 
+	if widget.inFlight.CompareAndSwap(false, true) {
+		go widget.fetchDataAsync()
+	}
+
 	widget.Redraw(widget.content)
 }
 
 /* -------------------- Unexported Functions -------------------- */
 
-func (widget *Widget) content() (string, string, bool) {
+// forceRefresh refreshes, running every probe whether or not its data is cached
+func (widget *Widget) forceRefresh() {
+	widget.force.Store(true)
+	widget.Refresh()
+}
+
+// fetchSecurityData fetches into a copy of the previous data, so that the data that is still
+// cached is kept
+func fetchSecurityData(prev *SecurityData, settings *Settings, commands CommandRunner, force bool) *SecurityData {
 	data := NewSecurityData()
-	data.Fetch()
+	if prev != nil {
+		data = prev.clone()
+	}
+
+	data.Fetch(settings, commands, force)
+	return data
+}
+
+// fetchDataAsync runs a single fetch. Callers must have claimed the in-flight guard,
+// which is released when the fetch is done
+func (widget *Widget) fetchDataAsync() {
+	defer widget.inFlight.Store(false)
+
+	data := widget.fetch(widget.cachedData(), widget.settings, widget.commands, widget.force.Swap(false))
+
+	widget.mu.Lock()
+	widget.data = data
+	widget.mu.Unlock()
+
+	widget.Redraw(widget.content)
+}
+
+func (widget *Widget) cachedData() *SecurityData {
+	widget.mu.Lock()
+	defer widget.mu.Unlock()
+
+	return widget.data
+}
+
+func (widget *Widget) content() (string, string, bool) {
+	title := widget.CommonSettings().Title
+
 	var str string
+	if err := widget.settings.unknownSectionsErr(); err != nil {
+		str += fmt.Sprintf(" [yellow]%s[white]\n\n", tview.Escape(err.Error()))
+	}
+
+	data := widget.cachedData()
+	if data == nil {
+		return title, str + " Loading…", false
+	}
+
+	renderers := map[string]func(data *SecurityData) string{
+		sectionCertificates: widget.certificatesSection,
+		sectionDNS:          widget.dnsSection,
+		sectionEncryption:   widget.encryptionSection,
+		sectionFirewall:     widget.firewallSection,
+		sectionListening:    widget.listeningSection,
+		sectionLogins:       widget.loginsSection,
+		sectionScore:        widget.scoreSection,
+		sectionSSH:          widget.sshSection,
+		sectionUsers:        widget.usersSection,
+		sectionWifi:         widget.wifiSection,
+	}
+
+	for _, section := range widget.settings.sections {
+		str += renderers[section](data)
+	}
+
+	if slices.Contains(widget.settings.sections, sectionScore) {
+		title = widget.scoreTitle(title, data)
+	}
+
+	return title, str, false
+}
+
+func (widget *Widget) wifiSection(data *SecurityData) string {
+	if err := data.Errors[sectionWifi]; err != nil {
+		return widget.sectionHeader(data, sectionWifi, "WiFi") + sectionError(err) + "\n"
+	}
+
+	if data.WifiName == "" {
+		return ""
+	}
+
+	name := tview.Escape(data.WifiName)
+	if !isTrustedNetwork(data.WifiName, widget.settings.trustedNetworks) {
+		name = "[yellow]" + name + "[white]"
+	}
+
+	str := widget.sectionHeader(data, sectionWifi, "WiFi")
+	str += fmt.Sprintf(" %8s: %s\n", "Network", name)
+	str += fmt.Sprintf(" %8s: %s\n", "Crypto", cryptoLabel(data.WifiEncryption))
+	return str + "\n"
+}
+
+// cryptoLabel colors the encryption of a network by how strong it is
+func cryptoLabel(crypto string) string {
+	label := tview.Escape(strings.TrimSpace(crypto))
+	if label == "" || label == "--" {
+		label = "Open"
+	}
+
+	switch cryptoStrength(crypto) {
+	case cryptoStrong:
+		return "[green]" + label + "[white]"
+	case cryptoWeak:
+		return "[red]" + label + " (weak)[white]"
+	default:
+		return label
+	}
+}
 
-	if data.WifiName != "" {
-		str += fmt.Sprintf(" [%s]WiFi[white]\n", widget.settings.Colors.Subheading)
-		str += fmt.Sprintf(" %8s: %s\n", "Network", data.WifiName)
-		str += fmt.Sprintf(" %8s: %s\n", "Crypto", data.WifiEncryption)
-		str += "\n"
+func (widget *Widget) firewallSection(data *SecurityData) string {
+	str := widget.sectionHeader(data, sectionFirewall, "Firewall")
+	if err := data.Errors[sectionFirewall]; err != nil {
+		return str + sectionError(err) + "\n"
 	}
 
-	str += fmt.Sprintf(" [%s]Firewall[white]\n", widget.settings.Colors.Subheading)
 	str += fmt.Sprintf(" %8s: %4s\n", "Status", data.FirewallEnabled)
 	str += fmt.Sprintf(" %8s: %4s\n", "Stealth", data.FirewallStealth)
-	str += "\n"
+	return str + "\n"
+}
 
-	str += fmt.Sprintf(" [%s]Users[white]\n", widget.settings.Colors.Subheading)
-	str += fmt.Sprintf("  %s", strings.Join(data.LoggedInUsers, "\n  "))
-	str += "\n\n"
+func (widget *Widget) encryptionSection(data *SecurityData) string {
+	str := widget.sectionHeader(data, sectionEncryption, "Encryption")
 
-	str += fmt.Sprintf(" [%s]DNS[white]\n", widget.settings.Colors.Subheading)
-	// If no DNS servers are found, display a single line of 'n/a'
-	if len(data.Dns) == 0 {
+	switch {
+	case data.Errors[sectionEncryption] != nil:
+		str += sectionError(data.Errors[sectionEncryption])
+	case len(data.Encryption) == 0:
 		str += fmt.Sprintf(" %6s\n", "n/a")
+	default:
+		for _, volume := range data.Encryption {
+			str += fmt.Sprintf(" %8s: %s\n", tview.Escape(volume.Volume), encryptionLabel(volume))
+		}
+	}
+
+	return str + "\n"
+}
+
+// encryptionLabel colors the encryption state of a volume, in red when the boot volume
+// isn't encrypted
+func encryptionLabel(volume DiskEncryption) string {
+	switch {
+	case volume.State == encryptionEnabled:
+		return "[green]" + volume.State + "[white]"
+	case volume.State == encryptionDisabled && volume.Boot:
+		return "[red]" + volume.State + "[white]"
+	case volume.State == encryptionUnknown:
+		return "[yellow]" + volume.State + "[white]"
+	default:
+		return volume.State
+	}
+}
+
+// listeningSection shows the unexpected listeners, in yellow
+func (widget *Widget) listeningSection(data *SecurityData) string {
+	str := widget.sectionHeader(data, sectionListening, "Listening")
+	if err := data.Errors[sectionListening]; err != nil {
+		return str + sectionError(err) + "\n"
+	}
+
+	shown, more := shownListeners(data.Listening, widget.settings.allowedPorts, widget.settings.hideLocalhost, widget.settings.maxListening)
+	if len(shown) == 0 {
+		return str + fmt.Sprintf(" %6s\n", "none") + "\n"
+	}
+
+	for _, listener := range shown {
+		process := cmp.Or(listener.Process, "?")
+		str += fmt.Sprintf(" [yellow]%5d %-15s %s[white]\n", listener.Port, tview.Escape(process), tview.Escape(listener.Address))
+	}
+	if more > 0 {
+		str += fmt.Sprintf("  [gray]… and %d more[white]\n", more)
+	}
+
+	return str + "\n"
+}
+
+// loginsSection shows how many logins failed in the last day, in red over the threshold, and
+// the most recent ones
+func (widget *Widget) loginsSection(data *SecurityData) string {
+	str := widget.sectionHeader(data, sectionLogins, "Logins")
+	if err := data.Errors[sectionLogins]; err != nil {
+		return str + sectionError(err) + "\n"
+	}
+
+	logins := data.FailedLogins
+	if logins.NeedsPrivileges {
+		return str + fmt.Sprintf(" %8s: %s\n", "Failed", "[yellow]needs privileges[white]") + "\n"
+	}
+
+	count := fmt.Sprintf("%d in 24h", len(logins.Attempts))
+	if len(logins.Attempts) > widget.settings.failedLoginsThreshold {
+		count = "[red]" + count + "[white]"
+	}
+	str += fmt.Sprintf(" %8s: %s\n", "Failed", count)
+
+	for idx, attempt := range logins.Attempts {
+		if idx >= widget.settings.maxFailedLogins {
+			break
+		}
+		str += fmt.Sprintf("  %s %s from %s\n",
+			attempt.At.In(nowFunc().Location()).Format("15:04"), tview.Escape(attempt.User), tview.Escape(attempt.From))
+	}
+
+	return str + "\n"
+}
+
+// sshSection shows the settings of the SSH server that let passwords in, in red, and the
+// keys loaded in the agent
+func (widget *Widget) sshSection(data *SecurityData) string {
+	str := widget.sectionHeader(data, sectionSSH, "SSH")
+	if err := data.Errors[sectionSSH]; err != nil {
+		return str + sectionError(err) + "\n"
+	}
+
+	audit := data.SSH
+	if audit.SSHDInstalled {
+		str += fmt.Sprintf(" %8s: %s\n", "Password", riskyLabel(audit.PasswordAuthentication, audit.PasswordAuthentication == "yes"))
+		str += fmt.Sprintf(" %8s: %s\n", "Root", riskyLabel(audit.PermitRootLogin, audit.PermitRootLogin == "yes"))
+		str += fmt.Sprintf(" %8s: %s\n", "Port", tview.Escape(strings.Join(audit.Ports, ", ")))
+		if len(audit.Includes) > 0 {
+			str += fmt.Sprintf("  [gray]not evaluated: %s[white]\n", tview.Escape(strings.Join(audit.Includes, ", ")))
+		}
 	} else {
+		str += fmt.Sprintf(" %8s: %s\n", "sshd", "not installed")
+	}
+
+	if len(audit.AgentKeys) == 0 {
+		str += fmt.Sprintf(" %8s: %s\n", "Agent", audit.AgentStatus)
+	}
+	for idx, key := range audit.AgentKeys {
+		if idx == 0 {
+			str += fmt.Sprintf(" %8s: %s\n", "Agent", tview.Escape(key))
+		} else {
+			str += fmt.Sprintf(" %8s  %s\n", "", tview.Escape(key))
+		}
+	}
+
+	return str + "\n"
+}
+
+// riskyLabel shows a setting in red when it's risky
+func riskyLabel(value string, risky bool) string {
+	if risky {
+		return "[red]" + tview.Escape(value) + "[white]"
+	}
+	return tview.Escape(value)
+}
+
+// usersSection shows who is logged in, with their sessions where they're listed: remote
+// ones in yellow, and the ones idle for longer than the threshold in gray
+func (widget *Widget) usersSection(data *SecurityData) string {
+	str := widget.sectionHeader(data, sectionUsers, "Users")
+	if err := data.Errors[sectionUsers]; err != nil {
+		return str + sectionError(err) + "\n"
+	}
+
+	if data.Sessions != nil {
+		str += widget.sessionLines(data)
+	} else {
+		for _, user := range data.LoggedInUsers {
+			str += "  " + userLabel(user, data.AdminUsers) + "\n"
+		}
+	}
+
+	if data.AccountCount > 0 {
+		str += fmt.Sprintf("  [gray]%d of %d accounts logged in[white]\n", len(data.LoggedInUsers), data.AccountCount)
+	}
+	if data.Sudo == sudoPasswordless {
+		str += "  [yellow]passwordless sudo enabled[white]\n"
+	}
+
+	return str + "\n"
+}
+
+// userLabel renders the name of a user, marking admins
+func userLabel(user string, admins []string) string {
+	if slices.Contains(admins, user) {
+		return tview.Escape(user) + " [yellow](admin)[white]"
+	}
+	return tview.Escape(user)
+}
+
+// sessionLines renders the sessions under their user, with the number of sessions of the
+// users that have several
+func (widget *Widget) sessionLines(data *SecurityData) string {
+	ttyWidth, fromWidth := 0, 0
+	for _, session := range data.Sessions {
+		ttyWidth = max(ttyWidth, len(session.TTY))
+		fromWidth = max(fromWidth, len(cmp.Or(session.From, "local")))
+	}
+
+	now := nowFunc()
+	str := ""
+
+	for _, group := range groupSessions(data.Sessions) {
+		count := ""
+		if len(group.sessions) > 1 {
+			count = fmt.Sprintf(" ×%d", len(group.sessions))
+		}
+		str += "  " + userLabel(group.user, data.AdminUsers) + count + "\n"
+
+		for _, session := range group.sessions {
+			line := strings.TrimRight(fmt.Sprintf("%-*s %-*s %-6s %s",
+				ttyWidth, session.TTY, fromWidth, cmp.Or(session.From, "local"),
+				loginLabel(session.LoginAt, now), idleLabel(session.Idle)), " ")
+
+			switch {
+			case session.Idle > widget.settings.idleThreshold:
+				line = "[gray]" + tview.Escape(line) + "[white]"
+			case session.From != "":
+				line = "[yellow]" + tview.Escape(line) + "[white]"
+			default:
+				line = tview.Escape(line)
+			}
+			str += "    " + line + "\n"
+		}
+	}
+
+	return str
+}
+
+// loginLabel renders when a session started: its time for sessions from today, its date
+// for older ones
+func loginLabel(loginAt, now time.Time) string {
+	loginAt = loginAt.In(now.Location())
+
+	if loginAt.YearDay() == now.YearDay() && loginAt.Year() == now.Year() {
+		return loginAt.Format("15:04")
+	}
+	return loginAt.Format("Jan 2")
+}
+
+// idleLabel renders how long a session has been idle, if known
+func idleLabel(idle time.Duration) string {
+	switch {
+	case idle == idleUnknown:
+		return ""
+	case idle < time.Minute:
+		return "active"
+	case idle >= idleOld:
+		return "idle >1d"
+	default:
+		return "idle " + utils.CompactDuration(idle)
+	}
+}
+
+func (widget *Widget) certificatesSection(data *SecurityData) string {
+	if len(data.Certificates) == 0 {
+		return ""
+	}
+
+	width := 0
+	for _, cert := range data.Certificates {
+		width = max(width, len(cert.Target))
+	}
+
+	str := widget.sectionHeader(data, sectionCertificates, "Certificates")
+	now := nowFunc()
+
+	for _, cert := range data.Certificates {
+		str += fmt.Sprintf("  %-*s: %s\n", width, tview.Escape(cert.Target), widget.certificateLabel(cert, now))
+	}
+
+	return str + "\n"
+}
+
+// certificateLabel shows how many days are left until a certificate expires, colored by
+// how soon that is
+func (widget *Widget) certificateLabel(cert Certificate, now time.Time) string {
+	if cert.Err != nil {
+		return fmt.Sprintf("[red]%s[white]", tview.Escape(cert.Err.Error()))
+	}
+
+	days := cert.daysLeft(now)
+	label := fmt.Sprintf("%d days", days)
+	if days == 1 {
+		label = "1 day"
+	}
+
+	switch {
+	case days < 0:
+		return "[red]expired[white]"
+	case days < widget.settings.critDays:
+		return "[red]" + label + "[white]"
+	case days < widget.settings.warnDays:
+		return "[yellow]" + label + "[white]"
+	default:
+		return label
+	}
+}
+
+func (widget *Widget) dnsSection(data *SecurityData) string {
+	str := widget.sectionHeader(data, sectionDNS, "DNS")
+
+	switch {
+	case data.Errors[sectionDNS] != nil:
+		str += sectionError(data.Errors[sectionDNS])
+	case len(data.Dns) == 0:
+		// If no DNS servers are found, display a single line of 'n/a'
+		str += fmt.Sprintf(" %6s\n", "n/a")
+	default:
 		for _, ip := range data.Dns {
-			str += fmt.Sprintf(" %12s\n", ip)
+			if len(widget.settings.expectedDNS) > 0 && !slices.Contains(widget.settings.expectedDNS, ip) {
+				str += fmt.Sprintf(" [red]%12s[white]\n", tview.Escape(ip))
+			} else {
+				str += fmt.Sprintf(" %12s\n", tview.Escape(ip))
+			}
 		}
 	}
-	str += "\n"
 
-	return widget.CommonSettings().Title, str, false
+	if data.DNSHijack.Checked {
+		str += hijackLabel(data.DNSHijack)
+	}
+
+	return str + "\n"
+}
+
+// hijackLabel shows the result of the DNS hijack check
+func hijackLabel(hijack DNSHijack) string {
+	switch {
+	case hijack.Hijacked():
+		return fmt.Sprintf("  [red]hijacked: made-up answers (%s)[white]\n", tview.Escape(strings.Join(hijack.Answers, ", ")))
+	case hijack.Err != nil:
+		return fmt.Sprintf("  [yellow]hijack check failed (%s)[white]\n", tview.Escape(hijack.Err.Error()))
+	default:
+		return "  [green]not hijacked[white]\n"
+	}
+}
+
+// sectionHeader renders the name of a section, marked with a "*" when its data changed in
+// the last fetch
+func (widget *Widget) sectionHeader(data *SecurityData, section, name string) string {
+	marker := ""
+	if data.Changed[section] {
+		marker = " [yellow]*[white]"
+	}
+
+	return fmt.Sprintf(" [%s]%s[white]%s\n", widget.settings.Colors.Subheading, name, marker)
+}
+
+// sectionError renders why the probes of a section failed, in place of its values. The
+// error can hold what a command printed, colors included
+func sectionError(err error) string {
+	return fmt.Sprintf("  [red]n/a[white] (%s)\n", utils.ANSIToTview(err.Error()))
 }
@@ -0,0 +1,177 @@
+package security
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/olebedev/config"
+	"github.com/rivo/tview"
+	"gotest.tools/assert"
+)
+
+func newTestSettings(t *testing.T, yaml string) *Settings {
+	t.Helper()
+
+	ymlConfig, err := config.ParseYaml(yaml)
+	assert.NilError(t, err)
+
+	globalConfig, err := config.ParseYaml("global: {}")
+	assert.NilError(t, err)
+
+	return NewSettingsFromYAML("security", ymlConfig, globalConfig)
+}
+
+func newTestWidget(t *testing.T, yaml string, fetch func(prev *SecurityData, settings *Settings, commands CommandRunner, force bool) *SecurityData) (*Widget, chan bool) {
+	t.Helper()
+
+	redrawChan := make(chan bool, 4)
+	widget := NewWidget(tview.NewApplication(), redrawChan, newTestSettings(t, yaml))
+	widget.fetch = fetch
+
+	return widget, redrawChan
+}
+
+func Test_Refresh_LoadingThenLoaded(t *testing.T) {
+	release := make(chan struct{})
+	widget, redrawChan := newTestWidget(t, "enabled: true", func(*SecurityData, *Settings, CommandRunner, bool) *SecurityData {
+		<-release
+
+		data := NewSecurityData()
+		data.FirewallEnabled = "[green]Enabled[white]"
+		data.FirewallStealth = "[red]Disabled[white]"
+		data.LoggedInUsers = []string{"alice", "bob"}
+		data.Dns = []string{"1.1.1.1"}
+		return data
+	})
+
+	widget.Refresh()
+	<-redrawChan
+
+	_, content, _ := widget.content()
+	assert.Equal(t, " Loading…", content)
+
+	// A refresh while the fetch is still running doesn't start another one
+	widget.Refresh()
+	<-redrawChan
+
+	close(release)
+	<-redrawChan
+
+	_, content, _ = widget.content()
+	assert.Assert(t, strings.Contains(content, "Status: [green]Enabled[white]"), content)
+	assert.Assert(t, strings.Contains(content, "  alice\n  bob\n"), content)
+	assert.Assert(t, strings.Contains(content, "1.1.1.1"), content)
+	assert.Assert(t, !strings.Contains(content, "WiFi"), content)
+}
+
+func Test_content_SectionError(t *testing.T) {
+	widget, _ := newTestWidget(t, "enabled: true", nil)
+
+	widget.data = NewSecurityData()
+	widget.data.LoggedInUsers = []string{"alice"}
+	widget.data.Errors[sectionFirewall] = errors.New("ufw not installed")
+	widget.data.Errors[sectionWifi] = errors.New("nmcli: [exit status 1]")
+	widget.data.Errors[sectionSSH] = errors.New("sshd: \x1b[1;31mno hostkeys available\x1b[0m\x1b[K")
+
+	_, content, _ := widget.content()
+
+	assert.Assert(t, strings.Contains(content, "Firewall[white]\n  [red]n/a[white] (ufw not installed)\n"), content)
+	assert.Assert(t, strings.Contains(content, "WiFi[white]\n  [red]n/a[white] (nmcli: [exit status 1[])\n"), content)
+	assert.Assert(t, strings.Contains(content, "SSH[white]\n  [red]n/a[white] (sshd: [maroon::b]no hostkeys available[-:-:-])\n"), content)
+	assert.Assert(t, !strings.Contains(content, "Stealth"), content)
+
+	// The other sections still render
+	assert.Assert(t, strings.Contains(content, "  alice\n"), content)
+	assert.Assert(t, strings.Contains(content, "n/a\n"), content)
+}
+
+func Test_content_Sections(t *testing.T) {
+	fetched := []string{}
+	widget, redrawChan := newTestWidget(t, "{enabled: true, sections: [dns, firewall, vpn, dns, vpn]}", func(_ *SecurityData, settings *Settings, _ CommandRunner, _ bool) *SecurityData {
+		fetched = settings.sections
+
+		data := NewSecurityData()
+		data.FirewallEnabled = "[green]Enabled[white]"
+		data.Dns = []string{"9.9.9.9"}
+		return data
+	})
+
+	widget.Refresh()
+	<-redrawChan
+	<-redrawChan
+
+	assert.DeepEqual(t, []string{sectionDNS, sectionFirewall}, fetched)
+
+	_, content, _ := widget.content()
+	lines := strings.Split(content, "\n")
+
+	assert.Equal(t, " [yellow]unknown sections ignored: vpn[white]", lines[0])
+	assert.Equal(t, 1, strings.Count(content, "vpn"))
+	assert.Assert(t, strings.Index(content, "DNS") < strings.Index(content, "Firewall"), content)
+	assert.Assert(t, !strings.Contains(content, "Users"), content)
+	assert.Assert(t, !strings.Contains(content, "WiFi"), content)
+}
+
+func Test_content_ChangedMarker(t *testing.T) {
+	widget, _ := newTestWidget(t, "{enabled: true, sections: [firewall, dns]}", nil)
+
+	widget.data = NewSecurityData()
+	widget.data.Changed[sectionFirewall] = true
+
+	_, content, _ := widget.content()
+
+	assert.Assert(t, strings.Contains(content, " [red]Firewall[white] [yellow]*[white]\n"), content)
+	assert.Assert(t, strings.Contains(content, " [red]DNS[white]\n"), content)
+}
+
+func Test_forceRefresh(t *testing.T) {
+	forced := make(chan bool, 2)
+	widget, redrawChan := newTestWidget(t, "enabled: true", func(prev *SecurityData, _ *Settings, _ CommandRunner, force bool) *SecurityData {
+		forced <- force
+		return NewSecurityData()
+	})
+
+	widget.forceRefresh()
+	<-redrawChan
+	<-redrawChan
+	assert.Equal(t, true, <-forced)
+
+	// Only the next fetch is forced
+	widget.Refresh()
+	<-redrawChan
+	<-redrawChan
+	assert.Equal(t, false, <-forced)
+}
+
+func Test_NewWidget_Scrolling(t *testing.T) {
+	widget, _ := newTestWidget(t, "enabled: true", nil)
+
+	assert.Assert(t, widget.CommonSettings().Focusable)
+	assert.Assert(t, strings.Contains(widget.HelpText(), "Scroll down a page"))
+}
+
+func Test_NewWidget_CommandTimeout(t *testing.T) {
+	fast, _ := newTestWidget(t, "enabled: true\ncommandTimeout: 1s", nil)
+	slow, _ := newTestWidget(t, "enabled: true\ncommandTimeout: 30s", nil)
+
+	// Each widget keeps the timeout it was configured with
+	assert.Equal(t, newExecRunner(time.Second), fast.commands)
+	assert.Equal(t, newExecRunner(30*time.Second), slow.commands)
+}
+
+func Test_Refresh_Commands(t *testing.T) {
+	used := make(chan CommandRunner, 1)
+	widget, redrawChan := newTestWidget(t, "enabled: true", func(_ *SecurityData, _ *Settings, commands CommandRunner, _ bool) *SecurityData {
+		used <- commands
+		return NewSecurityData()
+	})
+	widget.commands = newExecRunner(time.Minute)
+
+	widget.Refresh()
+	<-redrawChan
+	<-redrawChan
+
+	assert.Equal(t, widget.commands, <-used)
+}
@@ -1,8 +1,9 @@
 package security
 
 import (
-	"os/exec"
+	"errors"
 	"runtime"
+	"slices"
 	"strings"
 
 	"github.com/wtfutil/wtf/utils"
@@ -12,67 +13,192 @@ import (
 const osxWifiCmd = "/System/Library/PrivateFrameworks/Apple80211.framework/Versions/Current/Resources/airport"
 const osxWifiArg = "-I"
 
+const (
+	cryptoStrong  = "strong"
+	cryptoWeak    = "weak"
+	cryptoUnknown = "unknown"
+)
+
 /* -------------------- Exported Functions -------------------- */
 
-func WifiEncryption() string {
+func WifiEncryption(commands CommandRunner) (string, error) {
 	switch runtime.GOOS {
 	case "linux":
-		return wifiEncryptionLinux()
+		return wifiEncryptionLinux(commands)
 	case "darwin":
-		return wifiEncryptionMacOS()
+		return wifiEncryptionMacOS(commands)
 	case "windows":
-		return wifiEncryptionWindows()
+		return wifiEncryptionWindows(commands)
 	default:
-		return ""
+		return "", nil
 	}
 }
 
-func WifiName() string {
+func WifiName(commands CommandRunner) (string, error) {
 	switch runtime.GOOS {
 	case "linux":
-		return wifiNameLinux()
+		return wifiNameLinux(commands)
 	case "darwin":
-		return wifiNameMacOS()
+		return wifiNameMacOS(commands)
 	case "windows":
-		return wifiNameWindows()
+		return wifiNameWindows(commands)
 	default:
-		return ""
+		return "", nil
 	}
 }
 
 /* -------------------- Unexported Functions -------------------- */
 
-func wifiEncryptionLinux() string {
-	cmd := exec.Command("nmcli", "-t", "-f", "in-use,security", "dev", "wifi")
-	out := utils.ExecuteCommand(cmd)
+func wifiEncryptionLinux(commands CommandRunner) (string, error) {
+	network, err := activeNetworkNmcli(commands)
+	if err == nil {
+		return network.security, nil
+	}
+
+	name, err := wifiNameWithoutNmcli(commands, err)
+	if err != nil || name == "" {
+		return "", err
+	}
+
+	// iw doesn't tell the encryption without scanning, which needs root
+	return "N/A", nil
+}
+
+func wifiEncryptionMacOS(commands CommandRunner) (string, error) {
+	info, err := wifiInfo(commands)
+	if err != nil {
+		return "", err
+	}
+
+	name := utils.FindMatch(`s*auth: (.+)s*`, info)
+	return matchStr(name), nil
+}
+
+func wifiInfo(commands CommandRunner) (string, error) {
+	return commands.Run(osxWifiCmd, osxWifiArg)
+}
+
+func wifiNameLinux(commands CommandRunner) (string, error) {
+	network, err := activeNetworkNmcli(commands)
+	if err == nil {
+		return network.ssid, nil
+	}
+
+	return wifiNameWithoutNmcli(commands, err)
+}
+
+// wifiNameWithoutNmcli asks the wireless interfaces directly when nmcli failed with nmcliErr,
+// which is returned if iw isn't installed either
+func wifiNameWithoutNmcli(commands CommandRunner, nmcliErr error) (string, error) {
+	name, err := wifiNameIw(commands)
+	switch {
+	case err == nil:
+		return name, nil
+	case errors.Is(err, errNotInstalled) && errors.Is(nmcliErr, errNotInstalled):
+		// Neither tool is installed, which leaves no Wi-Fi to report
+		return "", nil
+	case errors.Is(err, errNotInstalled):
+		return "", nmcliErr
+	default:
+		return "", err
+	}
+}
+
+// wifiNetwork is a wireless network, as listed by nmcli or netsh
+type wifiNetwork struct {
+	ssid     string
+	security string
+}
+
+// activeNetworkNmcli returns the network NetworkManager is connected to, or an empty one
+func activeNetworkNmcli(commands CommandRunner) (wifiNetwork, error) {
+	out, err := commands.Run("nmcli", "-t", "-f", "active,ssid,security", "dev", "wifi")
+	if err != nil {
+		return wifiNetwork{}, err
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := splitTerse(line)
+		if len(fields) == 3 && fields[0] == "yes" {
+			return wifiNetwork{ssid: fields[1], security: fields[2]}, nil
+		}
+	}
 
-	name := utils.FindMatch(`\*:(.+)`, out)
+	return wifiNetwork{}, nil
+}
+
+// splitTerse splits a line of nmcli's terse output into its fields. Colons that are part of
+// a value are escaped with a backslash
+func splitTerse(line string) []string {
+	fields := []string{}
+	field := strings.Builder{}
 
-	if len(name) > 0 {
-		return name[0][1]
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line):
+			i++
+			field.WriteByte(line[i])
+		case line[i] == ':':
+			fields = append(fields, field.String())
+			field.Reset()
+		default:
+			field.WriteByte(line[i])
+		}
 	}
 
-	return ""
+	return append(fields, field.String())
 }
 
-func wifiEncryptionMacOS() string {
-	name := utils.FindMatch(`s*auth: (.+)s*`, wifiInfo())
-	return matchStr(name)
+// wifiNameIw returns the SSID of the first connected wireless interface that "iw dev" lists
+func wifiNameIw(commands CommandRunner) (string, error) {
+	out, err := commands.Run("iw", "dev")
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		if ssid, ok := strings.CutPrefix(strings.TrimSpace(line), "ssid "); ok {
+			return ssid, nil
+		}
+	}
+
+	return "", nil
 }
 
-func wifiInfo() string {
-	cmd := exec.Command(osxWifiCmd, osxWifiArg)
-	return utils.ExecuteCommand(cmd)
+func wifiNameMacOS(commands CommandRunner) (string, error) {
+	info, err := wifiInfo(commands)
+	if err != nil {
+		return "", err
+	}
+
+	name := utils.FindMatch(`s*SSID: (.+)s*`, info)
+	return matchStr(name), nil
 }
 
-func wifiNameLinux() string {
-	cmd, _ := exec.Command("iwgetid", "-r").Output()
-	return string(cmd)
+// cryptoStrength classifies the encryption of a network, as named by nmcli ("WPA1 WPA2"),
+// airport ("wpa2-psk") or netsh ("WPA3-Personal"). Networks that offer several kinds are
+// as strong as the best one
+func cryptoStrength(crypto string) string {
+	crypto = strings.ToUpper(strings.TrimSpace(crypto))
+
+	switch {
+	case crypto == "N/A":
+		return cryptoUnknown
+	case crypto == "" || crypto == "--" || crypto == "NONE" || crypto == "OPEN":
+		return cryptoWeak
+	case strings.Contains(crypto, "WPA2") || strings.Contains(crypto, "WPA3") || strings.Contains(crypto, "SAE"):
+		return cryptoStrong
+	case strings.Contains(crypto, "WEP") || strings.Contains(crypto, "WPA"):
+		return cryptoWeak
+	default:
+		return cryptoUnknown
+	}
 }
 
-func wifiNameMacOS() string {
-	name := utils.FindMatch(`s*SSID: (.+)s*`, wifiInfo())
-	return matchStr(name)
+// isTrustedNetwork returns whether the SSID is one of the trusted ones, as they are spelled.
+// Every network is trusted when there are none
+func isTrustedNetwork(ssid string, trusted []string) bool {
+	return len(trusted) == 0 || slices.Contains(trusted, ssid)
 }
 
 func matchStr(data [][]string) string {
@@ -83,33 +209,58 @@ func matchStr(data [][]string) string {
 	return data[1][1]
 }
 
-// Windows
-func wifiEncryptionWindows() string {
-	return parseWlanNetsh("Authentication")
+func wifiEncryptionWindows(commands CommandRunner) (string, error) {
+	network, err := activeNetworkNetsh(commands)
+	return network.security, err
 }
 
-func wifiNameWindows() string {
-	return parseWlanNetsh("SSID")
+func wifiNameWindows(commands CommandRunner) (string, error) {
+	network, err := activeNetworkNetsh(commands)
+	return network.ssid, err
 }
 
-func parseWlanNetsh(target string) string {
-	cmd := exec.Command("netsh.exe", "wlan", "show", "interfaces")
-	out, err := cmd.Output()
+// activeNetworkNetsh returns the network the first connected wireless interface is connected
+// to, or an empty one
+func activeNetworkNetsh(commands CommandRunner) (wifiNetwork, error) {
+	out, err := commands.Run("netsh", "wlan", "show", "interfaces")
 	if err != nil {
-		return ""
+		return wifiNetwork{}, err
 	}
-	splits := strings.Split(string(out), "\n")
-	var words []string
-	for _, line := range splits {
-		token := strings.Split(line, ":")
-		for _, word := range token {
-			words = append(words, strings.TrimSpace(word))
+
+	return parseWlanInterfaces(out), nil
+}
+
+// parseWlanInterfaces finds the first connected interface "netsh wlan show interfaces" lists.
+// Every interface starts with its "Name" line
+func parseWlanInterfaces(out string) wifiNetwork {
+	network := wifiNetwork{}
+	connected := false
+
+	for _, line := range strings.Split(out, "\n") {
+		// Values can have colons too (ex: "Physical address : aa:bb:cc:dd:ee:ff")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
 		}
-	}
-	for i, token := range words {
-		if token == target {
-			return words[i+1]
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch key {
+		case "Name":
+			if connected {
+				return network
+			}
+			network = wifiNetwork{}
+		case "State":
+			connected = value == "connected"
+		case "SSID":
+			network.ssid = value
+		case "Authentication":
+			network.security = value
 		}
 	}
-	return "N/A"
+
+	if !connected {
+		return wifiNetwork{}
+	}
+	return network
 }
@@ -0,0 +1,253 @@
+package security
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const iwDevOutput = `phy#0
+	Interface wlp2s0
+		ifindex 3
+		wdev 0x1
+		addr 3c:58:c2:aa:bb:cc
+		ssid Coffee Shop
+		type managed
+		channel 36 (5180 MHz), width: 80 MHz, center1: 5210 MHz
+`
+
+func Test_wifiLinux(t *testing.T) {
+	tests := []struct {
+		name       string
+		outputs    map[string]cannedOutput
+		ssid       string
+		encryption string
+		err        string
+	}{
+		{
+			name: "nmcli",
+			outputs: map[string]cannedOutput{
+				"nmcli -t -f active,ssid,security dev wifi": {out: "no:Neighbours:WPA2\nyes:Home\\:5G:WPA2 WPA3\nno::\n"},
+			},
+			ssid:       "Home:5G",
+			encryption: "WPA2 WPA3",
+		},
+		{
+			name: "nmcli not connected",
+			outputs: map[string]cannedOutput{
+				"nmcli -t -f active,ssid,security dev wifi": {out: "no:Neighbours:WPA2\n"},
+			},
+		},
+		{
+			name: "iw without nmcli",
+			outputs: map[string]cannedOutput{
+				"iw dev": {out: iwDevOutput},
+			},
+			ssid:       "Coffee Shop",
+			encryption: "N/A",
+		},
+		{
+			name: "iw when NetworkManager isn't running",
+			outputs: map[string]cannedOutput{
+				"nmcli -t -f active,ssid,security dev wifi": {err: errExitStatus},
+				"iw dev": {out: iwDevOutput},
+			},
+			ssid:       "Coffee Shop",
+			encryption: "N/A",
+		},
+		{
+			name: "nmcli failing without iw",
+			outputs: map[string]cannedOutput{
+				"nmcli -t -f active,ssid,security dev wifi": {err: errExitStatus},
+			},
+			err: "nmcli: exit status 1",
+		},
+		{
+			name:    "nothing installed",
+			outputs: map[string]cannedOutput{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands := fakeRunner(tt.outputs)
+
+			ssid, err := wifiNameLinux(commands)
+			encryption, encryptionErr := wifiEncryptionLinux(commands)
+
+			if tt.err != "" {
+				assert.Error(t, err, tt.err)
+				assert.Error(t, encryptionErr, tt.err)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.NilError(t, encryptionErr)
+			assert.Equal(t, tt.ssid, ssid)
+			assert.Equal(t, tt.encryption, encryption)
+		})
+	}
+}
+
+func Test_splitTerse(t *testing.T) {
+	assert.DeepEqual(t, []string{"yes", "a:b\\c", ""}, splitTerse(`yes:a\:b\\c:`))
+}
+
+func Test_cryptoStrength(t *testing.T) {
+	tests := []struct {
+		crypto   string
+		expected string
+	}{
+		{crypto: "", expected: cryptoWeak},
+		{crypto: "--", expected: cryptoWeak},
+		{crypto: "none", expected: cryptoWeak},
+		{crypto: "Open", expected: cryptoWeak},
+		{crypto: "WEP", expected: cryptoWeak},
+		{crypto: "wep", expected: cryptoWeak},
+		{crypto: "WPA1", expected: cryptoWeak},
+		{crypto: "wpa-psk", expected: cryptoWeak},
+		{crypto: "WPA-Personal", expected: cryptoWeak},
+		{crypto: "WPA2", expected: cryptoStrong},
+		{crypto: "WPA1 WPA2", expected: cryptoStrong},
+		{crypto: "wpa2-psk", expected: cryptoStrong},
+		{crypto: "WPA2-Enterprise", expected: cryptoStrong},
+		{crypto: "WPA2 WPA3", expected: cryptoStrong},
+		{crypto: "wpa3-sae", expected: cryptoStrong},
+		{crypto: "WPA3-Personal", expected: cryptoStrong},
+		{crypto: "N/A", expected: cryptoUnknown},
+		{crypto: "802.1X", expected: cryptoUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.crypto, func(t *testing.T) {
+			assert.Equal(t, tt.expected, cryptoStrength(tt.crypto))
+		})
+	}
+}
+
+func Test_cryptoLabel(t *testing.T) {
+	assert.Equal(t, "[green]WPA2 WPA3[white]", cryptoLabel("WPA2 WPA3"))
+	assert.Equal(t, "[red]WEP (weak)[white]", cryptoLabel("WEP"))
+	assert.Equal(t, "[red]Open (weak)[white]", cryptoLabel(""))
+	assert.Equal(t, "N/A", cryptoLabel("N/A"))
+}
+
+func Test_isTrustedNetwork(t *testing.T) {
+	trusted := []string{"Home Network", "Café ☕", "🏠 5G"}
+
+	tests := []struct {
+		ssid     string
+		expected bool
+	}{
+		{ssid: "Home Network", expected: true},
+		{ssid: "home network", expected: false},
+		{ssid: "Home Network ", expected: false},
+		{ssid: "Home", expected: false},
+		{ssid: "Café ☕", expected: true},
+		{ssid: "Cafe ☕", expected: false},
+		{ssid: "🏠 5G", expected: true},
+		{ssid: "🏠 5g", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ssid, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isTrustedNetwork(tt.ssid, trusted))
+		})
+	}
+
+	assert.Assert(t, isTrustedNetwork("Anything", nil))
+}
+
+func Test_wifiSection_UntrustedNetwork(t *testing.T) {
+	widget, _ := newTestWidget(t, `{enabled: true, trustedNetworks: ["Home Network"]}`, nil)
+
+	data := NewSecurityData()
+	data.WifiName = "Free Airport WiFi"
+	data.WifiEncryption = ""
+
+	assert.Equal(t, " [red]WiFi[white]\n"+
+		"  Network: [yellow]Free Airport WiFi[white]\n"+
+		"   Crypto: [red]Open (weak)[white]\n\n", widget.wifiSection(data))
+
+	data.WifiName = "Home Network"
+	data.WifiEncryption = "WPA2"
+
+	assert.Equal(t, " [red]WiFi[white]\n"+
+		"  Network: Home Network\n"+
+		"   Crypto: [green]WPA2[white]\n\n", widget.wifiSection(data))
+}
+
+const netshInterfacesOutput = `
+There are 2 interfaces on the system:
+
+    Name                   : Wi-Fi 2
+    Description            : USB Wireless LAN Card
+    GUID                   : 1b2c3d4e-0000-0000-0000-000000000001
+    Physical address       : 00:11:22:33:44:55
+    State                  : disconnected
+    Radio status           : Hardware On
+                             Software On
+
+    Name                   : Wi-Fi
+    Description            : Intel(R) Wi-Fi 6 AX201 160MHz
+    GUID                   : 1b2c3d4e-0000-0000-0000-000000000002
+    Physical address       : 3c:58:c2:aa:bb:cc
+    State                  : connected
+    SSID                   : Home: 5G
+    BSSID                  : 10:20:30:40:50:60
+    Network type           : Infrastructure
+    Radio type             : 802.11ax
+    Authentication         : WPA2-Personal
+    Cipher                 : CCMP
+    Connection mode        : Profile
+    Profile                : Home: 5G
+
+    Hosted network status  : Not available
+`
+
+func Test_wifiWindows(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     cannedOutput
+		ssid       string
+		encryption string
+		err        string
+	}{
+		{
+			name:       "connected",
+			output:     cannedOutput{out: netshInterfacesOutput},
+			ssid:       "Home: 5G",
+			encryption: "WPA2-Personal",
+		},
+		{
+			name:   "not connected",
+			output: cannedOutput{out: strings.ReplaceAll(netshInterfacesOutput, ": connected", ": disconnected")},
+		},
+		{
+			name:   "WLAN service stopped",
+			output: cannedOutput{err: errExitStatus},
+			err:    "netsh: exit status 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands := fakeRunner(map[string]cannedOutput{"netsh wlan show interfaces": tt.output})
+
+			ssid, err := wifiNameWindows(commands)
+			encryption, encryptionErr := wifiEncryptionWindows(commands)
+
+			if tt.err != "" {
+				assert.Error(t, err, tt.err)
+				assert.Error(t, encryptionErr, tt.err)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.NilError(t, encryptionErr)
+			assert.Equal(t, tt.ssid, ssid)
+			assert.Equal(t, tt.encryption, encryption)
+		})
+	}
+}
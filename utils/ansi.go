@@ -0,0 +1,193 @@
+package utils
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// ansiSequencePattern matches the escape sequences terminals read: control sequences
+// ("\x1b[31m", "\x1b[2J"), operating system commands ("\x1b]0;title\x07") and the short ones
+// ("\x1b7", "\x1b(B")
+var ansiSequencePattern = regexp.MustCompile(`\x1b(?:\[([0-9;:?<=>]*)[ -/]*([@-~])|\][^\x07\x1b]*(?:\x07|\x1b\\)?|[ -/]*[0-~]|$)`)
+
+// ansiColorNames are the 16 standard colors, by number, named the way tview names them
+var ansiColorNames = []string{
+	"black", "maroon", "green", "olive", "navy", "purple", "teal", "silver",
+	"gray", "red", "lime", "yellow", "blue", "fuchsia", "aqua", "white",
+}
+
+// ansiAttributes are the tview text attributes the SGR parameters turn on, and the ones that
+// turn them off
+var ansiAttributes = map[int]string{1: "b", 2: "d", 3: "i", 4: "u", 5: "l", 7: "r", 9: "s"}
+var ansiAttributeResets = map[int]string{22: "bd", 23: "i", 24: "u", 25: "l", 27: "r", 29: "s"}
+
+// ANSIToTview converts the colors and text attributes in text that was written for a
+// terminal into tview color tags, so that the output of commands can be shown as is. Other
+// escape sequences, like cursor movements or clearing the screen, are removed, and the rest
+// of the text is escaped so that tview doesn't read brackets in it as tags. Styles that are
+// still on at the end are reset
+//
+// Example:
+//
+//	x := ANSIToTview("\x1b[31merror\x1b[0m: [file]")
+//	> "[maroon]error[-:-:-]: [file[]"
+func ANSIToTview(text string) string {
+	out := strings.Builder{}
+	state := ansiState{}
+
+	for {
+		loc := ansiSequencePattern.FindStringSubmatchIndex(text)
+		if loc == nil {
+			out.WriteString(tview.Escape(text))
+			break
+		}
+
+		out.WriteString(tview.Escape(text[:loc[0]]))
+
+		// Only "Select Graphic Rendition" sequences ("\x1b[...m") are kept
+		if loc[4] >= 0 && text[loc[4]:loc[5]] == "m" {
+			out.WriteString(state.apply(text[loc[2]:loc[3]]))
+		}
+
+		text = text[loc[1]:]
+	}
+
+	if state.styled {
+		out.WriteString("[-:-:-]")
+	}
+
+	return out.String()
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// ansiState is the style the SGR sequences so far left the text in
+type ansiState struct {
+	attributes string
+	styled     bool
+}
+
+// apply changes the style by the parameters of an SGR sequence ("1;31"), and returns the tags
+// that do the same, or ""
+func (state *ansiState) apply(params string) string {
+	fields := strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' })
+	if len(fields) == 0 {
+		fields = []string{"0"}
+	}
+
+	foreground, background, attributes := "", "", state.attributes
+	reset := false
+
+	for i := 0; i < len(fields); i++ {
+		code, err := strconv.Atoi(fields[i])
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case code == 0:
+			reset = true
+			foreground, background, attributes = "", "", ""
+		case ansiAttributes[code] != "":
+			if !strings.Contains(attributes, ansiAttributes[code]) {
+				attributes += ansiAttributes[code]
+			}
+		case ansiAttributeResets[code] != "":
+			attributes = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(ansiAttributeResets[code], r) {
+					return -1
+				}
+				return r
+			}, attributes)
+		case code >= 30 && code <= 37:
+			foreground = ansiColorNames[code-30]
+		case code >= 90 && code <= 97:
+			foreground = ansiColorNames[code-90+8]
+		case code == 39:
+			foreground = "-"
+		case code >= 40 && code <= 47:
+			background = ansiColorNames[code-40]
+		case code >= 100 && code <= 107:
+			background = ansiColorNames[code-100+8]
+		case code == 49:
+			background = "-"
+		case code == 38 || code == 48:
+			color, used := extendedColor(fields[i+1:])
+			i += used
+			if code == 38 && color != "" {
+				foreground = color
+			} else if color != "" {
+				background = color
+			}
+		}
+	}
+
+	// A reset can be followed by the new style, in the same sequence ("0;1;31")
+	tags := ""
+	if reset {
+		tags = "[-:-:-]"
+		state.attributes, state.styled = "", false
+	}
+
+	attributesTag := ""
+	if attributes != state.attributes {
+		attributesTag = cmp.Or(attributes, "-")
+	}
+	state.attributes = attributes
+
+	if tag := strings.TrimRight(foreground+":"+background+":"+attributesTag, ":"); tag != "" {
+		tags += "[" + tag + "]"
+		state.styled = true
+	}
+
+	return tags
+}
+
+// extendedColor reads the color of a "38" or "48" parameter from the parameters after it:
+// "5;n" for the 256 colors, or "2;r;g;b". Returns how many parameters it read
+func extendedColor(fields []string) (string, int) {
+	numbers := []int{}
+	for _, field := range fields {
+		number, err := strconv.Atoi(field)
+		if err != nil || number < 0 || number > 255 {
+			break
+		}
+		numbers = append(numbers, number)
+	}
+
+	switch {
+	case len(numbers) >= 2 && numbers[0] == 5:
+		return ansi256Color(numbers[1]), 2
+	case len(numbers) >= 4 && numbers[0] == 2:
+		return fmt.Sprintf("#%02x%02x%02x", numbers[1], numbers[2], numbers[3]), 4
+	default:
+		return "", 0
+	}
+}
+
+// ansi256Color names one of the 256 colors: the 16 standard ones, then a 6x6x6 cube, then
+// 24 grays, with the levels xterm uses
+func ansi256Color(number int) string {
+	switch {
+	case number < 16:
+		return ansiColorNames[number]
+	case number < 232:
+		level := func(n int) int {
+			if n == 0 {
+				return 0
+			}
+			return 55 + 40*n
+		}
+
+		number -= 16
+		return fmt.Sprintf("#%02x%02x%02x", level(number/36), level(number/6%6), level(number%6))
+	default:
+		gray := 8 + 10*(number-232)
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+	}
+}
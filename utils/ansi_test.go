@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lsOutput is "ls --color=always" listing a directory, a file and an executable
+const lsOutput = "\x1b[0m\x1b[01;34mdir\x1b[0m  file.txt  \x1b[01;32mscript.sh\x1b[0m\n"
+
+// grepOutput is "grep --color=always -n" matching a line of a file
+const grepOutput = "\x1b[35m\x1b[Kmain.go\x1b[m\x1b[K\x1b[36m\x1b[K:\x1b[m\x1b[K\x1b[32m\x1b[K12\x1b[m\x1b[K\x1b[36m\x1b[K:\x1b[m\x1b[Kfunc \x1b[01;31m\x1b[Kmain\x1b[m\x1b[K() {\n"
+
+func Test_ANSIToTview(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "plain text",
+			text:     "no colors here",
+			expected: "no colors here",
+		},
+		{
+			name:     "ls",
+			text:     lsOutput,
+			expected: "[-:-:-][navy::b]dir[-:-:-]  file.txt  [green::b]script.sh[-:-:-]\n",
+		},
+		{
+			name:     "grep",
+			text:     grepOutput,
+			expected: "[purple]main.go[-:-:-][teal]:[-:-:-][green]12[-:-:-][teal]:[-:-:-]func [maroon::b]main[-:-:-]() {\n",
+		},
+		{
+			name:     "bright colors and backgrounds",
+			text:     "\x1b[91;44mred on navy\x1b[39;49m default",
+			expected: "[red:navy]red on navy[-:-] default[-:-:-]",
+		},
+		{
+			name:     "nested attributes",
+			text:     "\x1b[1mbold \x1b[4mand underlined\x1b[24m just bold\x1b[22m plain",
+			expected: "[::b]bold [::bu]and underlined[::b] just bold[::-] plain[-:-:-]",
+		},
+		{
+			name:     "reset followed by a style",
+			text:     "\x1b[1mdone\x1b[0;33m warning",
+			expected: "[::b]done[-:-:-][olive] warning[-:-:-]",
+		},
+		{
+			name:     "256 colors",
+			text:     "\x1b[38;5;208morange\x1b[0m \x1b[38;5;244mgray\x1b[0m \x1b[48;5;9mred\x1b[0m",
+			expected: "[#ff8700]orange[-:-:-] [#808080]gray[-:-:-] [:red]red[-:-:-]",
+		},
+		{
+			name:     "true colors",
+			text:     "\x1b[38;2;10;20;30mdeep blue",
+			expected: "[#0a141e]deep blue[-:-:-]",
+		},
+		{
+			name:     "malformed extended color",
+			text:     "\x1b[38;7mreversed",
+			expected: "[::r]reversed[-:-:-]",
+		},
+		{
+			name:     "cursor movement and clearing",
+			text:     "\x1b[2J\x1b[Hhello\x1b[1A\x1b[K world\x1b7\x1b8",
+			expected: "hello world",
+		},
+		{
+			name:     "tput",
+			text:     "\x1b[1m\x1b[31mfailed\x1b(B\x1b[m",
+			expected: "[::b][maroon]failed[-:-:-]",
+		},
+		{
+			name:     "window title",
+			text:     "\x1b]0;user@host: ~\x07prompt",
+			expected: "prompt",
+		},
+		{
+			name:     "brackets in the text",
+			text:     "[error] in \x1b[31m[main]\x1b[0m",
+			expected: "[error[] in [maroon][main[][-:-:-]",
+		},
+		{
+			name:     "escape at the end",
+			text:     "cut short\x1b",
+			expected: "cut short",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ANSIToTview(tt.text))
+		})
+	}
+}
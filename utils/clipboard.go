@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// The values of the clipboardMode setting
+const (
+	// ClipboardAuto uses a clipboard utility, unless the session is remote or none is
+	// installed, in which case it asks the terminal to copy with OSC 52
+	ClipboardAuto = "auto"
+	// ClipboardNative only uses the clipboard utilities
+	ClipboardNative = "native"
+	// ClipboardOSC52 only asks the terminal to copy, with an OSC 52 escape sequence
+	ClipboardOSC52 = "osc52"
+	// ClipboardOff turns copying off
+	ClipboardOff = "off"
+)
+
+// osc52MaxEncodedBytes is the longest base64 payload sent in an OSC 52 sequence. Terminals
+// drop longer sequences, most of them silently
+const osc52MaxEncodedBytes = 100_000
+
+var (
+	// ErrNoClipboard is returned when none of the known clipboard utilities are installed
+	ErrNoClipboard = errors.New("no clipboard utility found")
+
+	// ErrClipboardOff is returned when the clipboardMode setting turns copying off
+	ErrClipboardOff = errors.New("copying is turned off by clipboardMode")
+)
+
+// ClipboardMode is how Clipboard copies, set from the clipboardMode setting
+var ClipboardMode = ClipboardAuto
+
+// lookPath is replaceable in tests
+var lookPath = exec.LookPath
+
+// openTerminal opens the terminal the OSC 52 sequences are written to. It's replaceable
+// in tests
+var openTerminal = func() (io.WriteCloser, error) {
+	return os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+}
+
+// Clipboard copies text for widgets, so that tests can replace it with a FakeClipboard
+type Clipboard interface {
+	Write(text string) error
+}
+
+// systemClipboard copies with a clipboard utility or the terminal, as ClipboardMode says
+type systemClipboard struct{}
+
+// NewClipboard returns the Clipboard that copies to the user's clipboard
+func NewClipboard() Clipboard {
+	return systemClipboard{}
+}
+
+// Write copies text to the clipboard
+func (systemClipboard) Write(text string) error {
+	switch clipboardMethod(ClipboardMode, isRemoteSession(), hasClipboardUtility()) {
+	case ClipboardOff:
+		return ErrClipboardOff
+	case ClipboardOSC52:
+		return copyWithOSC52(text)
+	default:
+		return CopyToClipboard(text)
+	}
+}
+
+// FakeClipboard is a Clipboard for tests, that records what is copied instead
+type FakeClipboard struct {
+	mu     sync.Mutex
+	copies []string
+
+	// Err is returned by Write, which doesn't record the copy, when it's set
+	Err error
+}
+
+// Write records text, or returns Err
+func (clipboard *FakeClipboard) Write(text string) error {
+	clipboard.mu.Lock()
+	defer clipboard.mu.Unlock()
+
+	if clipboard.Err != nil {
+		return clipboard.Err
+	}
+
+	clipboard.copies = append(clipboard.copies, text)
+	return nil
+}
+
+// Copies returns everything that was copied, oldest first
+func (clipboard *FakeClipboard) Copies() []string {
+	clipboard.mu.Lock()
+	defer clipboard.mu.Unlock()
+
+	return append([]string{}, clipboard.copies...)
+}
+
+// Last returns the last text that was copied, or ""
+func (clipboard *FakeClipboard) Last() string {
+	copies := clipboard.Copies()
+	if len(copies) == 0 {
+		return ""
+	}
+
+	return copies[len(copies)-1]
+}
+
+// clipboardCommands returns, for the current operating system, the utilities that can
+// write stdin to the clipboard, in order of preference
+func clipboardCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	default:
+		return [][]string{
+			{"wl-copy"},
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+		}
+	}
+}
+
+// CopyToClipboard writes text to the system clipboard using the first available
+// clipboard utility
+//
+// Example:
+//
+//	err := CopyToClipboard("hello")
+func CopyToClipboard(text string) error {
+	for _, command := range clipboardCommands() {
+		path, err := lookPath(command[0])
+		if err != nil {
+			continue
+		}
+
+		cmd := exec.Command(path, command[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed: %w", command[0], err)
+		}
+
+		return nil
+	}
+
+	return ErrNoClipboard
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// clipboardMethod decides how to copy, for the clipboardMode setting: with a clipboard
+// utility (ClipboardNative), through the terminal (ClipboardOSC52), or not at all. Remote
+// sessions copy through the terminal in auto mode, as a utility would copy to the clipboard
+// of the remote machine. Unknown modes are auto
+func clipboardMethod(mode string, remote, hasUtility bool) string {
+	switch mode {
+	case ClipboardNative, ClipboardOSC52, ClipboardOff:
+		return mode
+	}
+
+	if remote || !hasUtility {
+		return ClipboardOSC52
+	}
+
+	return ClipboardNative
+}
+
+// copyWithOSC52 asks the terminal to copy text
+func copyWithOSC52(text string) error {
+	sequence, err := osc52Sequence(text, os.Getenv("TMUX") != "")
+	if err != nil {
+		return err
+	}
+
+	terminal, err := openTerminal()
+	if err != nil {
+		return fmt.Errorf("could not open the terminal: %w", err)
+	}
+	defer func() { _ = terminal.Close() }()
+
+	_, err = io.WriteString(terminal, sequence)
+	return err
+}
+
+// hasClipboardUtility returns true if one of the clipboard utilities is installed
+func hasClipboardUtility() bool {
+	for _, command := range clipboardCommands() {
+		if _, err := lookPath(command[0]); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isRemoteSession returns true when WTF runs over SSH
+func isRemoteSession() bool {
+	return os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != ""
+}
+
+// osc52Sequence returns the escape sequence that asks the terminal to copy text to the
+// clipboard. Inside tmux, the sequence is wrapped so that tmux passes it on to the terminal
+//
+// Example:
+//
+//	x, _ := osc52Sequence("hello", false)
+//	> "\x1b]52;c;aGVsbG8=\x07"
+func osc52Sequence(text string, tmux bool) (string, error) {
+	encoded := base64.StdEncoding.EncodeToString([]byte(text))
+	if len(encoded) > osc52MaxEncodedBytes {
+		return "", fmt.Errorf("text is too long to copy through the terminal: %d bytes", len(text))
+	}
+
+	sequence := "\x1b]52;c;" + encoded + "\x07"
+	if tmux {
+		sequence = "\x1bPtmux;\x1b" + sequence + "\x1b\\"
+	}
+
+	return sequence, nil
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CopyToClipboard_NoUtility(t *testing.T) {
+	originalLookPath := lookPath
+	defer func() { lookPath = originalLookPath }()
+
+	lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+
+	err := CopyToClipboard("hello")
+
+	assert.True(t, errors.Is(err, ErrNoClipboard))
+}
+
+func Test_clipboardMethod(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		remote     bool
+		hasUtility bool
+		expected   string
+	}{
+		{name: "auto, local", mode: ClipboardAuto, hasUtility: true, expected: ClipboardNative},
+		{name: "auto, no utility", mode: ClipboardAuto, expected: ClipboardOSC52},
+		{name: "auto, remote", mode: ClipboardAuto, remote: true, hasUtility: true, expected: ClipboardOSC52},
+		{name: "unknown", mode: "clipboard", hasUtility: true, expected: ClipboardNative},
+		{name: "empty", mode: "", expected: ClipboardOSC52},
+		{name: "native", mode: ClipboardNative, remote: true, expected: ClipboardNative},
+		{name: "osc52", mode: ClipboardOSC52, hasUtility: true, expected: ClipboardOSC52},
+		{name: "off", mode: ClipboardOff, hasUtility: true, expected: ClipboardOff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, clipboardMethod(tt.mode, tt.remote, tt.hasUtility))
+		})
+	}
+}
+
+func Test_osc52Sequence(t *testing.T) {
+	sequence, err := osc52Sequence("hello", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "\x1b]52;c;aGVsbG8=\x07", sequence)
+
+	sequence, err = osc52Sequence("AB-123\tDone ✓", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "\x1b]52;c;QUItMTIzCURvbmUg4pyT\x07", sequence)
+
+	sequence, err = osc52Sequence("hello", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]52;c;aGVsbG8=\x07\x1b\\", sequence)
+}
+
+func Test_osc52Sequence_LengthCap(t *testing.T) {
+	// Every 3 bytes take 4 in base64
+	longest := strings.Repeat("a", osc52MaxEncodedBytes/4*3)
+
+	sequence, err := osc52Sequence(longest, false)
+	assert.NoError(t, err)
+	assert.Len(t, sequence, len("\x1b]52;c;")+osc52MaxEncodedBytes+len("\x07"))
+
+	_, err = osc52Sequence(longest+"a", false)
+	assert.EqualError(t, err, "text is too long to copy through the terminal: 75001 bytes")
+}
+
+type closingBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (buffer *closingBuffer) Close() error {
+	buffer.closed = true
+	return nil
+}
+
+func Test_Clipboard_Write(t *testing.T) {
+	originalMode, originalLookPath, originalOpenTerminal := ClipboardMode, lookPath, openTerminal
+	defer func() { ClipboardMode, lookPath, openTerminal = originalMode, originalLookPath, originalOpenTerminal }()
+
+	t.Setenv("TMUX", "")
+	lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+
+	terminal := &closingBuffer{}
+	openTerminal = func() (io.WriteCloser, error) { return terminal, nil }
+
+	ClipboardMode = ClipboardAuto
+	assert.NoError(t, NewClipboard().Write("hello"))
+	assert.Equal(t, "\x1b]52;c;aGVsbG8=\x07", terminal.String())
+	assert.True(t, terminal.closed)
+
+	ClipboardMode = ClipboardNative
+	assert.ErrorIs(t, NewClipboard().Write("hello"), ErrNoClipboard)
+
+	ClipboardMode = ClipboardOff
+	assert.ErrorIs(t, NewClipboard().Write("hello"), ErrClipboardOff)
+}
+
+func Test_FakeClipboard(t *testing.T) {
+	clipboard := &FakeClipboard{}
+	assert.Equal(t, "", clipboard.Last())
+
+	assert.NoError(t, clipboard.Write("AB-1"))
+	assert.NoError(t, clipboard.Write("AB-2"))
+	assert.Equal(t, []string{"AB-1", "AB-2"}, clipboard.Copies())
+	assert.Equal(t, "AB-2", clipboard.Last())
+
+	clipboard.Err = ErrNoClipboard
+	assert.ErrorIs(t, clipboard.Write("AB-3"), ErrNoClipboard)
+	assert.Equal(t, "AB-2", clipboard.Last())
+}
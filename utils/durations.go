@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	day   = 24 * time.Hour
+	month = 30 * day
+	year  = 365 * day
+)
+
+// durationUnit is one of the units durations are written in, from the largest down
+type durationUnit struct {
+	length  time.Duration
+	compact string
+	name    string
+}
+
+// durationDaysPattern matches the days and weeks ParseDuration reads, which
+// time.ParseDuration doesn't: "2d", "1.5w"
+var durationDaysPattern = regexp.MustCompile(`^([0-9]*\.?[0-9]+)([dw])`)
+
+var durationUnits = []durationUnit{
+	{length: year, compact: "y", name: "year"},
+	{length: month, compact: "mo", name: "month"},
+	{length: day, compact: "d", name: "day"},
+	{length: time.Hour, compact: "h", name: "hour"},
+	{length: time.Minute, compact: "m", name: "minute"},
+	{length: time.Second, compact: "s", name: "second"},
+}
+
+// HumanDuration writes a duration out in its two largest units, truncated rather than
+// rounded. The second unit is left out when it is zero. A month is 30 days, a year 365
+//
+// Example:
+//
+//	x := HumanDuration(134 * time.Minute)
+//	> "2 hours 14 minutes"
+func HumanDuration(d time.Duration) string {
+	return formatDuration(d, false)
+}
+
+// CompactDuration is HumanDuration with one-letter units, for columns and titles
+//
+// Example:
+//
+//	x := CompactDuration(134 * time.Minute)
+//	> "2h14m"
+func CompactDuration(d time.Duration) string {
+	return formatDuration(d, true)
+}
+
+// RelativeTime says how long ago t was, or how long until it is, in the units of
+// HumanDuration ("3 hours ago", "in 5 minutes"). Anything within a minute is "just now"
+func RelativeTime(t time.Time) string {
+	return RelativeAge(time.Since(t), false)
+}
+
+// CompactRelativeTime is RelativeTime in the units of CompactDuration ("3h", "in 5m", "now")
+func CompactRelativeTime(t time.Time) string {
+	return RelativeAge(time.Since(t), true)
+}
+
+// RelativeAge is RelativeTime, or CompactRelativeTime, for callers that measure the age
+// themselves. Negative ages are in the future
+func RelativeAge(age time.Duration, compact bool) string {
+	switch {
+	case age > -time.Minute && age < time.Minute && compact:
+		return "now"
+	case age > -time.Minute && age < time.Minute:
+		return "just now"
+	case age < 0:
+		return "in " + formatDuration(-age, compact)
+	case compact:
+		return formatDuration(age, compact)
+	default:
+		return formatDuration(age, compact) + " ago"
+	}
+}
+
+// ParseDuration reads a duration such as 90m or 1h30m, like time.ParseDuration, that can also
+// be in days and weeks, such as 2d or 1w3d, for thresholds that are days long. A day is 24
+// hours. A number without a unit is a number of seconds, like refreshInterval
+//
+// Example:
+//
+//	x, _ := ParseDuration("1d12h")
+//	> 36h0m0s
+func ParseDuration(text string) (time.Duration, error) {
+	text = strings.TrimSpace(text)
+
+	if seconds, err := strconv.Atoi(text); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	total := time.Duration(0)
+	rest := text
+
+	for {
+		match := durationDaysPattern.FindStringSubmatch(rest)
+		if match == nil {
+			break
+		}
+
+		count, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", text)
+		}
+
+		unit := day
+		if match[2] == "w" {
+			unit = 7 * day
+		}
+
+		total += time.Duration(count * float64(unit))
+		rest = rest[len(match[0]):]
+	}
+
+	if rest == "" && text != "" {
+		return total, nil
+	}
+
+	d, err := time.ParseDuration(rest)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", text)
+	}
+
+	return total + d, nil
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+func formatDuration(d time.Duration, compact bool) string {
+	d = d.Abs()
+
+	for i, unit := range durationUnits {
+		if d < unit.length && unit.length != time.Second {
+			continue
+		}
+
+		parts := []string{formatUnit(d/unit.length, unit, compact)}
+		if i+1 < len(durationUnits) {
+			next := durationUnits[i+1]
+			if count := d % unit.length / next.length; count > 0 {
+				parts = append(parts, formatUnit(count, next, compact))
+			}
+		}
+
+		if compact {
+			return strings.Join(parts, "")
+		}
+		return strings.Join(parts, " ")
+	}
+
+	return ""
+}
+
+// formatUnit writes count of the unit ("3h", "3 hours")
+func formatUnit(count time.Duration, unit durationUnit, compact bool) string {
+	switch {
+	case compact:
+		return fmt.Sprintf("%d%s", count, unit.compact)
+	case count == 1:
+		return "1 " + unit.name
+	default:
+		return fmt.Sprintf("%d %ss", count, unit.name)
+	}
+}
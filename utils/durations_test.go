@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HumanDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		long     string
+		compact  string
+	}{
+		{duration: 0, long: "0 seconds", compact: "0s"},
+		{duration: time.Second, long: "1 second", compact: "1s"},
+		{duration: 59 * time.Second, long: "59 seconds", compact: "59s"},
+		{duration: 60 * time.Second, long: "1 minute", compact: "1m"},
+		{duration: 61 * time.Second, long: "1 minute 1 second", compact: "1m1s"},
+		{duration: 59*time.Minute + 59*time.Second, long: "59 minutes 59 seconds", compact: "59m59s"},
+		{duration: time.Hour, long: "1 hour", compact: "1h"},
+		{duration: 2*time.Hour + 14*time.Minute + 59*time.Second, long: "2 hours 14 minutes", compact: "2h14m"},
+		{duration: 23*time.Hour + 59*time.Minute, long: "23 hours 59 minutes", compact: "23h59m"},
+		{duration: 24 * time.Hour, long: "1 day", compact: "1d"},
+		{duration: 24*time.Hour + 5*time.Minute, long: "1 day", compact: "1d"},
+		{duration: 29*day + 23*time.Hour, long: "29 days 23 hours", compact: "29d23h"},
+		{duration: 30 * day, long: "1 month", compact: "1mo"},
+		{duration: 45 * day, long: "1 month 15 days", compact: "1mo15d"},
+		{duration: 364 * day, long: "12 months 4 days", compact: "12mo4d"},
+		{duration: 365 * day, long: "1 year", compact: "1y"},
+		{duration: 400 * day, long: "1 year 1 month", compact: "1y1mo"},
+		{duration: 3*365*day + 10*day, long: "3 years", compact: "3y"},
+		{duration: -90 * time.Minute, long: "1 hour 30 minutes", compact: "1h30m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.duration.String(), func(t *testing.T) {
+			assert.Equal(t, tt.long, HumanDuration(tt.duration))
+			assert.Equal(t, tt.compact, CompactDuration(tt.duration))
+		})
+	}
+}
+
+func Test_ParseDuration(t *testing.T) {
+	tests := []struct {
+		text        string
+		expected    time.Duration
+		expectedErr bool
+	}{
+		{text: "90", expected: 90 * time.Second},
+		{text: "0", expected: 0},
+		{text: "90m", expected: 90 * time.Minute},
+		{text: "1h30m", expected: 90 * time.Minute},
+		{text: "1.5h", expected: 90 * time.Minute},
+		{text: "2d", expected: 2 * day},
+		{text: "0d", expected: 0},
+		{text: "1.5d", expected: 36 * time.Hour},
+		{text: "1d12h", expected: 36 * time.Hour},
+		{text: "1w", expected: 7 * day},
+		{text: "1w3d", expected: 10 * day},
+		{text: "2d30m15s", expected: 2*day + 30*time.Minute + 15*time.Second},
+		{text: " 3d ", expected: 3 * day},
+		{text: "", expectedErr: true},
+		{text: "d", expectedErr: true},
+		{text: "2x", expectedErr: true},
+		{text: "3h2d", expectedErr: true},
+		{text: "soon", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			actual, err := ParseDuration(tt.text)
+
+			if tt.expectedErr {
+				assert.EqualError(t, err, fmt.Sprintf("invalid duration %q", strings.TrimSpace(tt.text)))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func Test_RelativeAge(t *testing.T) {
+	tests := []struct {
+		age     time.Duration
+		long    string
+		compact string
+	}{
+		{age: 0, long: "just now", compact: "now"},
+		{age: 59 * time.Second, long: "just now", compact: "now"},
+		{age: -59 * time.Second, long: "just now", compact: "now"},
+		{age: 60 * time.Second, long: "1 minute ago", compact: "1m"},
+		{age: 3*time.Hour + 20*time.Second, long: "3 hours ago", compact: "3h"},
+		{age: 23*time.Hour + 59*time.Minute, long: "23 hours 59 minutes ago", compact: "23h59m"},
+		{age: 24 * time.Hour, long: "1 day ago", compact: "1d"},
+		{age: 29 * day, long: "29 days ago", compact: "29d"},
+		{age: 30 * day, long: "1 month ago", compact: "1mo"},
+		{age: 2*365*day + 60*day, long: "2 years 2 months ago", compact: "2y2mo"},
+		{age: -time.Minute, long: "in 1 minute", compact: "in 1m"},
+		{age: -(5*time.Minute + 30*time.Second), long: "in 5 minutes 30 seconds", compact: "in 5m30s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.age.String(), func(t *testing.T) {
+			assert.Equal(t, tt.long, RelativeAge(tt.age, false))
+			assert.Equal(t, tt.compact, RelativeAge(tt.age, true))
+		})
+	}
+}
+
+func Test_RelativeTime(t *testing.T) {
+	assert.Equal(t, "just now", RelativeTime(time.Now()))
+	assert.Equal(t, "now", CompactRelativeTime(time.Now()))
+
+	assert.Equal(t, "2 hours ago", RelativeTime(time.Now().Add(-2*time.Hour-time.Second)))
+	assert.Equal(t, "in 1d", CompactRelativeTime(time.Now().Add(24*time.Hour+time.Minute)))
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+)
+
+// EscapeTview escapes text that comes from outside, such as log messages, issue summaries
+// or feed titles, so that tview shows it verbatim instead of reading the color tags
+// ("[red]"), region tags ("["1"]", and the `[""]` that ends a region) or attribute tags
+// ("[::b]") in it. Brackets that can't be read as a tag ("[]", "[red", "a]") are left alone,
+// as tview shows them as they are
+//
+// Example:
+//
+//	x := EscapeTview("[ERROR] disk full")
+//	> "[ERROR[] disk full"
+func EscapeTview(text string) string {
+	return tview.Escape(text)
+}
+
+// SafeSprintf is fmt.Sprintf for text that tview renders: the tags in the format are kept,
+// and the arguments are escaped with EscapeTview after they are formatted, so that data
+// can't add tags of its own. Padding ("%-10s") is applied before escaping, so columns stay
+// aligned on screen. Numbers and booleans are passed as they are, so they can still be used
+// as widths ("%-*s")
+//
+// Example:
+//
+//	x := SafeSprintf("[red]%s[white]", "[ERROR]")
+//	> "[red][ERROR[][white]"
+func SafeSprintf(format string, args ...interface{}) string {
+	escaped := make([]interface{}, len(args))
+
+	for i, arg := range args {
+		switch arg.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr,
+			float32, float64, complex64, complex128, bool, nil:
+			escaped[i] = arg
+		default:
+			escaped[i] = escapedArg{arg: arg}
+		}
+	}
+
+	return fmt.Sprintf(format, escaped...)
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// escapedArg formats its argument with the verb and flags it is given, then escapes the result
+type escapedArg struct {
+	arg interface{}
+}
+
+func (e escapedArg) Format(state fmt.State, verb rune) {
+	_, _ = fmt.Fprint(state, EscapeTview(fmt.Sprintf(fmt.FormatString(state, verb), e.arg)))
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+)
+
+// shown returns the text tview shows for a string with tags
+func shown(text string) string {
+	view := tview.NewTextView().SetDynamicColors(true).SetRegions(true)
+	view.SetText(text)
+	return view.GetText(true)
+}
+
+func Test_EscapeTview(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "no brackets", text: "disk full", expected: "disk full"},
+		{name: "color tag", text: "[red]alert", expected: "[red[]alert"},
+		{name: "attribute tag", text: "[::b]bold", expected: "[::b[]bold"},
+		{name: "empty brackets", text: "array[]", expected: "array[]"},
+		{name: "region", text: `["x"]selected`, expected: `["x"[]selected`},
+		{name: "end of region", text: `before[""]after`, expected: `before[""[]after`},
+		{name: "unmatched brackets", text: "red] and [blue", expected: "red] and [blue"},
+		{name: "trailing bracket", text: "[red and ]blue[", expected: "[red and []blue["},
+		{name: "already escaped", text: "[red[]", expected: "[red[[]"},
+		{name: "nested", text: "[[red]]", expected: "[[red[]]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			escaped := EscapeTview(tt.text)
+
+			assert.Equal(t, tt.expected, escaped)
+			assert.Equal(t, tt.text, shown("<" + escaped + "[white]>")[1:len(tt.text)+1])
+		})
+	}
+}
+
+func Test_SafeSprintf(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		args     []interface{}
+		expected string
+	}{
+		{
+			name:     "tags in the format are kept",
+			format:   "[green]%s[white]",
+			args:     []interface{}{"[red]summary"},
+			expected: "[green][red[]summary[white]",
+		},
+		{
+			name:     "padding is applied before escaping",
+			format:   "[yellow]%-8s|",
+			args:     []interface{}{"[ab]"},
+			expected: "[yellow][ab[]    |",
+		},
+		{
+			name:     "widths and numbers",
+			format:   "%-*s %d %.1f %t",
+			args:     []interface{}{6, "[x]", 42, 1.5, true},
+			expected: "[x[]    42 1.5 true",
+		},
+		{
+			name:     "region tags in the data",
+			format:   `["1"]%s[""]`,
+			args:     []interface{}{`close[""] early`},
+			expected: `["1"]close[""[] early[""]`,
+		},
+		{
+			name:     "errors and slices",
+			format:   "%v %v",
+			args:     []interface{}{errors.New("[red]boom"), []string{"red"}},
+			expected: "[red[]boom [red[]",
+		},
+		{
+			name:     "unmatched brackets",
+			format:   "%s[white]",
+			args:     []interface{}{"ends with ["},
+			expected: "ends with [[white]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SafeSprintf(tt.format, tt.args...))
+		})
+	}
+}
@@ -2,10 +2,7 @@ package utils
 
 import (
 	"reflect"
-	"regexp"
 	"strconv"
-	"unicode"
-	"unicode/utf8"
 
 	"github.com/wtfutil/wtf/cfg"
 )
@@ -39,12 +36,6 @@ func HelpFromInterface(item interface{}) string {
 	return result
 }
 
-// StripColorTags removes tcell color tags from a given string
-func StripColorTags(input string) string {
-	openColorRegex := regexp.MustCompile(`\[.*?\]`)
-	return openColorRegex.ReplaceAllString(input, "")
-}
-
 /* -------------------- Unexported Functions -------------------- */
 
 func helpFromValue(field reflect.StructField) string {
@@ -62,7 +53,7 @@ func helpFromValue(field reflect.StructField) string {
 
 	values := field.Tag.Get("values")
 	if help != "" {
-		result += "\n\n " + lowercaseTitle(field.Name)
+		result += "\n\n " + cfg.SettingKey(field)
 		result += "\n " + help
 
 		if values != "" {
@@ -72,11 +63,3 @@ func helpFromValue(field reflect.StructField) string {
 
 	return result
 }
-
-func lowercaseTitle(title string) string {
-	if title == "" {
-		return ""
-	}
-	r, n := utf8.DecodeRuneInString(title)
-	return string(unicode.ToLower(r)) + title[n:]
-}
@@ -5,7 +5,8 @@ var OpenFileUtil = "open"
 var OpenUrlUtil = []string{}
 
 // Init initializes global settings in the wtf package
-func Init(openFileUtil string, openUrlUtil []string) {
+func Init(openFileUtil string, openUrlUtil []string, clipboardMode string) {
 	OpenFileUtil = openFileUtil
 	OpenUrlUtil = openUrlUtil
+	ClipboardMode = clipboardMode
 }
@@ -7,8 +7,10 @@ import (
 )
 
 func Test_Init(t *testing.T) {
-	Init("cats", []string{"dogs"})
+	Init("cats", []string{"dogs"}, ClipboardOSC52)
+	defer func() { ClipboardMode = ClipboardAuto }()
 
 	assert.Equal(t, OpenFileUtil, "cats")
 	assert.Equal(t, OpenUrlUtil, []string{"dogs"})
+	assert.Equal(t, ClipboardOSC52, ClipboardMode)
 }
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// jsonSnippetRadius is how many bytes on either side of a decode error are quoted in it
+const jsonSnippetRadius = 20
+
+// ParseJSONLimited is ParseJSON for responses that can be of any size, such as API
+// responses: it reads at most maxBytes from text, and fails with a "response exceeded N
+// bytes" error instead of reading more. Decode errors say at which byte the JSON is invalid,
+// and quote the JSON around it. A maxBytes of 0 or less doesn't limit the size
+//
+// Example:
+//
+//	err := ParseJSONLimited(&issue, resp.Body, 10<<20)
+//	> invalid JSON at byte 16, near `{"key": "AB-1",}`: invalid character '}' looking for beginning of object key string
+func ParseJSONLimited(obj interface{}, text io.Reader, maxBytes int64) error {
+	if maxBytes > 0 {
+		text = io.LimitReader(text, maxBytes+1)
+	}
+
+	data, err := io.ReadAll(text)
+	if err != nil {
+		return err
+	}
+
+	if maxBytes > 0 && int64(len(data)) > maxBytes {
+		return fmt.Errorf("response exceeded %d bytes", maxBytes)
+	}
+
+	err = json.NewDecoder(bytes.NewReader(data)).Decode(obj)
+	if err != nil {
+		return jsonDecodeError(data, err)
+	}
+
+	return nil
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// jsonDecodeError adds where decoding data failed to err
+func jsonDecodeError(data []byte, err error) error {
+	var offset int64
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		// The JSON is truncated, or empty
+		offset = int64(len(data))
+	default:
+		return err
+	}
+
+	return fmt.Errorf("invalid JSON at byte %d, near `%s`: %w", offset, jsonSnippet(data, offset), err)
+}
+
+// jsonSnippet returns the JSON around offset, on a single line
+func jsonSnippet(data []byte, offset int64) string {
+	end := min(offset+jsonSnippetRadius, int64(len(data)))
+	start := min(max(offset-jsonSnippetRadius, 0), end)
+
+	snippet := bytes.ToValidUTF8(data[start:end], []byte("?"))
+	return string(bytes.Join(bytes.Fields(snippet), []byte(" ")))
+}
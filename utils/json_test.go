@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type jsonTestIssue struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+func Test_ParseJSONLimited(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		maxBytes    int64
+		expected    jsonTestIssue
+		expectedErr string
+	}{
+		{
+			name:     "valid",
+			text:     `{"key": "AB-1", "count": 2}`,
+			maxBytes: 100,
+			expected: jsonTestIssue{Key: "AB-1", Count: 2},
+		},
+		{
+			name:     "exactly at the limit",
+			text:     `{"key": "AB-1"}`,
+			maxBytes: 15,
+			expected: jsonTestIssue{Key: "AB-1"},
+		},
+		{
+			name:        "oversized",
+			text:        `{"key": "AB-1"}`,
+			maxBytes:    14,
+			expectedErr: "response exceeded 14 bytes",
+		},
+		{
+			name:     "no limit",
+			text:     `{"key": "` + strings.Repeat("a", 1000) + `"}`,
+			expected: jsonTestIssue{Key: strings.Repeat("a", 1000)},
+		},
+		{
+			name:        "truncated",
+			text:        `{"key": "AB-1", "cou`,
+			maxBytes:    100,
+			expectedErr: "invalid JSON at byte 20, near `{\"key\": \"AB-1\", \"cou`: unexpected EOF",
+		},
+		{
+			name:        "empty",
+			maxBytes:    100,
+			expectedErr: "invalid JSON at byte 0, near ``: EOF",
+		},
+		{
+			name:        "syntax error",
+			text:        `{"key": "AB-1",}`,
+			maxBytes:    100,
+			expectedErr: "invalid JSON at byte 16, near `{\"key\": \"AB-1\",}`: invalid character '}' looking for beginning of object key string",
+		},
+		{
+			name:        "wrong type",
+			text:        `{"key": "AB-1", "count": "two"}`,
+			maxBytes:    100,
+			expectedErr: "invalid JSON at byte 30, near `B-1\", \"count\": \"two\"}`: json: cannot unmarshal string into Go struct field jsonTestIssue.count of type int",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := jsonTestIssue{}
+			err := ParseJSONLimited(&actual, strings.NewReader(tt.text), tt.maxBytes)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func Test_ParseJSONLimited_Offset(t *testing.T) {
+	// The error is deep in a long response, so only the JSON around it is quoted
+	text := `{"issues": [` + strings.Repeat(`{"key": "AB-1"}, `, 50) + `{"key": AB-2}]}`
+
+	err := ParseJSONLimited(&struct{}{}, strings.NewReader(text), 0)
+
+	offset := strings.Index(text, "AB-2") + 1
+	assert.ErrorContains(t, err, "invalid JSON at byte "+strconv.Itoa(offset)+",")
+	assert.ErrorContains(t, err, "near `: \"AB-1\"}, {\"key\": AB-2}]}`")
+
+	// The decode error itself is kept
+	var syntaxErr *json.SyntaxError
+	assert.True(t, errors.As(err, &syntaxErr))
+	assert.Equal(t, int64(offset), syntaxErr.Offset)
+}
+
+func Test_jsonSnippet(t *testing.T) {
+	data := []byte("{\n  \"key\":\n\t\"AB-1\"\n}")
+
+	assert.Equal(t, `{ "key": "AB-1" }`, jsonSnippet(data, 5))
+	assert.Equal(t, "", jsonSnippet(data, 100))
+	assert.Equal(t, "", jsonSnippet(nil, 0))
+}
@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy defines when and how often DoWithRetry retries a request
+type RetryPolicy struct {
+	// MaxAttempts is how many times the request is sent at most, including the first time
+	MaxAttempts int
+	// BaseDelay is how long to wait before the first retry. It doubles for every retry after it
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between two attempts, including the one asked for by Retry-After
+	MaxDelay time.Duration
+	// RetryableStatuses are the response status codes that are retried
+	RetryableStatuses []int
+	// HonorRetryAfter waits as long as the Retry-After header of a response asks, instead of
+	// the backoff, when it has one
+	HonorRetryAfter bool
+}
+
+// DefaultRetryPolicy returns the policy most API widgets use: three attempts, half a second
+// apart and then a second, for rate limiting and server errors
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		RetryableStatuses: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		HonorRetryAfter: true,
+	}
+}
+
+// DoWithRetry sends req with client, and sends it again, as the policy says, when it fails or
+// gets a response with a retryable status. The body of requests that have one is re-created
+// with req.GetBody for every attempt, so requests made with http.NewRequest from a buffer,
+// a bytes.Reader or a strings.Reader can be retried. Requests whose body can't be re-created
+// are only sent once. Waiting between attempts stops when the request's context is done.
+// The last response is returned as it is, even when its status is retryable, so that the
+// caller can report it
+//
+// Example:
+//
+//	req, _ := http.NewRequest("POST", url, bytes.NewBuffer(data))
+//	resp, err := DoWithRetry(http.DefaultClient, req, DefaultRetryPolicy())
+func DoWithRetry(client *http.Client, req *http.Request, policy RetryPolicy) (*http.Response, error) {
+	attempts := max(policy.MaxAttempts, 1)
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptReq, err := retryRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(attemptReq)
+		if attempt >= attempts || !policy.retryable(req, resp, err) {
+			return resp, err
+		}
+
+		delay := policy.delay(attempt, resp)
+
+		if resp != nil {
+			// Read the body so that the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// retryRequest returns the request to send for the given attempt: req itself the first
+// time, and a copy of it with a new body after that
+func retryRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 || req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	attemptReq := req.Clone(req.Context())
+	attemptReq.Body = body
+
+	return attemptReq, nil
+}
+
+// retryable returns true if the attempt of req that returned resp and err should be retried
+func (policy RetryPolicy) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// Requests that were cancelled, or ran out of time, aren't retried
+		return req.Context().Err() == nil
+	}
+
+	return slices.Contains(policy.RetryableStatuses, resp.StatusCode)
+}
+
+// delay returns how long to wait after the given attempt
+func (policy RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	// Past 30 doublings, the backoff is longer than any sensible cap anyway
+	delay := policy.BaseDelay << min(attempt-1, 30)
+
+	if policy.HonorRetryAfter && resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			delay = retryAfter
+		}
+	}
+
+	if policy.MaxDelay > 0 {
+		delay = min(delay, policy.MaxDelay)
+	}
+
+	return max(delay, 0)
+}
+
+// parseRetryAfter reads a Retry-After header, which is either a number of seconds or a date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testRetryPolicy is the default policy, with delays short enough for tests
+func testRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 10 * time.Millisecond
+	return policy
+}
+
+// scriptedServer fails the first failures requests with status, and then answers "ok". It
+// records the bodies of the requests it receives
+type scriptedServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	bodies   []string
+	failures int
+	status   int
+	header   http.Header
+}
+
+func newScriptedServer(t *testing.T, failures, status int) *scriptedServer {
+	server := &scriptedServer{failures: failures, status: status, header: http.Header{}}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		server.mu.Lock()
+		server.bodies = append(server.bodies, string(body))
+		failing := len(server.bodies) <= server.failures
+		server.mu.Unlock()
+
+		if failing {
+			for key, values := range server.header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(server.status)
+			_, _ = w.Write([]byte("failed"))
+			return
+		}
+
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func (server *scriptedServer) requests() []string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return append([]string{}, server.bodies...)
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	return string(body)
+}
+
+func Test_DoWithRetry(t *testing.T) {
+	tests := []struct {
+		name             string
+		failures         int
+		status           int
+		expectedStatus   int
+		expectedRequests int
+	}{
+		{name: "first attempt", failures: 0, status: 500, expectedStatus: 200, expectedRequests: 1},
+		{name: "rate limited", failures: 1, status: 429, expectedStatus: 200, expectedRequests: 2},
+		{name: "server errors", failures: 2, status: 503, expectedStatus: 200, expectedRequests: 3},
+		{name: "out of attempts", failures: 3, status: 502, expectedStatus: 502, expectedRequests: 3},
+		{name: "not retryable", failures: 1, status: 404, expectedStatus: 404, expectedRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newScriptedServer(t, tt.failures, tt.status)
+
+			req, err := http.NewRequest("GET", server.URL, http.NoBody)
+			assert.NoError(t, err)
+
+			resp, err := DoWithRetry(server.Client(), req, testRetryPolicy())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			_ = readBody(t, resp)
+			assert.Len(t, server.requests(), tt.expectedRequests)
+		})
+	}
+}
+
+func Test_DoWithRetry_Post(t *testing.T) {
+	server := newScriptedServer(t, 2, 500)
+	payload := `{"queryStrings": ["assignee = \"jdoe\""]}`
+
+	req, err := http.NewRequest("POST", server.URL, bytes.NewBufferString(payload))
+	assert.NoError(t, err)
+
+	resp, err := DoWithRetry(server.Client(), req, testRetryPolicy())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", readBody(t, resp))
+	assert.Equal(t, []string{payload, payload, payload}, server.requests())
+}
+
+func Test_DoWithRetry_BodyWithoutGetBody(t *testing.T) {
+	server := newScriptedServer(t, 1, 500)
+
+	req, err := http.NewRequest("POST", server.URL, io.NopCloser(bytes.NewBufferString("data")))
+	assert.NoError(t, err)
+	assert.Nil(t, req.GetBody)
+
+	resp, err := DoWithRetry(server.Client(), req, testRetryPolicy())
+
+	// The body can't be sent again, so the failure is returned
+	assert.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+	_ = readBody(t, resp)
+	assert.Equal(t, []string{"data"}, server.requests())
+}
+
+func Test_DoWithRetry_RetryAfter(t *testing.T) {
+	server := newScriptedServer(t, 1, 429)
+	server.header.Set("Retry-After", "1")
+
+	policy := testRetryPolicy()
+	policy.MaxDelay = time.Second
+
+	req, err := http.NewRequest("GET", server.URL, http.NoBody)
+	assert.NoError(t, err)
+
+	start := time.Now()
+	resp, err := DoWithRetry(server.Client(), req, policy)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", readBody(t, resp))
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func Test_DoWithRetry_CancelledDuringBackoff(t *testing.T) {
+	server := newScriptedServer(t, 3, 503)
+
+	policy := testRetryPolicy()
+	policy.BaseDelay = time.Minute
+	policy.MaxDelay = time.Minute
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL, http.NoBody)
+	assert.NoError(t, err)
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	resp, err := DoWithRetry(server.Client(), req, policy)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, resp)
+	assert.Less(t, time.Since(start), time.Minute)
+	assert.Len(t, server.requests(), 1)
+}
+
+func Test_RetryPolicy_delay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second, HonorRetryAfter: true}
+
+	assert.Equal(t, time.Second, policy.delay(1, nil))
+	assert.Equal(t, 2*time.Second, policy.delay(2, nil))
+	assert.Equal(t, 4*time.Second, policy.delay(3, nil))
+	assert.Equal(t, 5*time.Second, policy.delay(4, nil))
+	assert.Equal(t, 5*time.Second, policy.delay(100, nil))
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3"}}}
+	assert.Equal(t, 3*time.Second, policy.delay(1, resp))
+
+	resp.Header.Set("Retry-After", "3600")
+	assert.Equal(t, 5*time.Second, policy.delay(1, resp))
+
+	policy.HonorRetryAfter = false
+	assert.Equal(t, time.Second, policy.delay(1, resp))
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 5, 14, 2, 11, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "empty", value: ""},
+		{name: "seconds", value: "120", expected: 2 * time.Minute, ok: true},
+		{name: "date", value: "Tue, 05 Mar 2024 14:02:41 GMT", expected: 30 * time.Second, ok: true},
+		{name: "past date", value: "Tue, 05 Mar 2024 14:00:00 GMT", expected: 0, ok: true},
+		{name: "negative", value: "-5"},
+		{name: "invalid", value: "soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := parseRetryAfter(tt.value, now)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
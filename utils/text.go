@@ -3,7 +3,9 @@ package utils
 import (
 	"fmt"
 	"math"
+	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"golang.org/x/text/message"
 
@@ -79,6 +81,26 @@ func HighlightableHelper(view *tview.TextView, input string, idx, offset int) st
 	return fmtStr
 }
 
+// HighlightableBlockHelper is HighlightableHelper for text that spans several lines. Each
+// line is cut to maxWidth cells, ending with ellipsis, so that long lines don't push the
+// layout past the right edge of the view, and padded to the width of the view. A maxWidth
+// of 0 or less doesn't cut anything
+func HighlightableBlockHelper(view *tview.TextView, input string, idx, maxWidth int, ellipsis string) string {
+	_, _, w, _ := view.GetInnerRect()
+
+	lines := strings.Split(input, "\n")
+	for i, line := range lines {
+		line = TruncateTagged(line, maxWidth, ellipsis)
+		lines[i] = line + RowPadding(DisplayWidth(line), w)
+	}
+
+	fmtStr := fmt.Sprintf(`["%d"][""]`, idx)
+	fmtStr += strings.Join(lines, "\n")
+	fmtStr += `[""]` + "\n"
+
+	return fmtStr
+}
+
 // RowPadding returns a padding for a row to make it the full width of the containing widget.
 // Useful for ensuring row highlighting spans the full width (I suspect tcell has a better
 // way to do this, but I haven't yet found it)
@@ -115,6 +137,59 @@ func Truncate(src string, maxLen int, withEllipse bool) string {
 	return src
 }
 
+var (
+	// taggedColorPattern and taggedRegionPattern match the color and region tags tview reads
+	taggedColorPattern  = regexp.MustCompile(`^\[([a-zA-Z]+|#[0-9a-zA-Z]{6}|\-)?(:([a-zA-Z]+|#[0-9a-zA-Z]{6}|\-)?(:([lbidrus]+|\-)?)?)?\]`)
+	taggedRegionPattern = regexp.MustCompile(`^\["([a-zA-Z0-9_,;: \-\.]*)"\]`)
+	// taggedEscapePattern matches text that tview.Escape escaped, which shows one bracket less
+	taggedEscapePattern = regexp.MustCompile(`^\[([a-zA-Z0-9_,;: \-\."#]+)\[(\[*)\]`)
+)
+
+// TruncateTagged cuts text that has tview color and region tags to maxWidth cells on screen,
+// ending it with ellipsis when it is cut. The tags take no room, and those after the cut are
+// kept, so that regions are still closed and colors still reset. Runes are never split.
+// A maxWidth of 0 or less doesn't cut anything
+//
+// Example:
+//
+//	x := TruncateTagged("[red]catalog[white]", 4, "…")
+//	> "[red]cat…[white]"
+func TruncateTagged(text string, maxWidth int, ellipsis string) string {
+	if maxWidth <= 0 || DisplayWidth(text) <= maxWidth {
+		return text
+	}
+
+	room := max(maxWidth-DisplayWidth(ellipsis), 0)
+	out := strings.Builder{}
+	cut := false
+
+	for i := 0; i < len(text); {
+		chunk, shown, tag := scanTagged(text[i:])
+		i += len(chunk)
+
+		if tag {
+			out.WriteString(chunk)
+			continue
+		}
+
+		width := DisplayWidth(tview.Escape(shown))
+
+		if cut {
+			continue
+		}
+		if width > room {
+			out.WriteString(ellipsis)
+			cut = true
+			continue
+		}
+
+		out.WriteString(chunk)
+		room -= width
+	}
+
+	return out.String()
+}
+
 // PrettyNumber formats number as string with 1000 delimiters and, if necessary, rounds it to 2 decimals
 func PrettyNumber(prtr *message.Printer, number float64) string {
 	if number == math.Trunc(number) {
@@ -123,3 +198,67 @@ func PrettyNumber(prtr *message.Printer, number float64) string {
 
 	return prtr.Sprintf("%.2f", number)
 }
+
+// StripColorTags removes the color, attribute and region tags tview reads from text
+// ("[red]", "[::b]", `["0"]`, `[""]`), leaving the text as it's shown. Escaped brackets are
+// unescaped, as tview shows them: "[red[]" becomes "[red]". Brackets that aren't tags
+// ("[]", "[red", "a]") are left as they are
+//
+// Example:
+//
+//	x := StripColorTags(`["0"][red]ERROR[white] [disk[] full[""]`)
+//	> "ERROR [disk] full"
+func StripColorTags(text string) string {
+	out := strings.Builder{}
+
+	for i := 0; i < len(text); {
+		chunk, shown, tag := scanTagged(text[i:])
+		i += len(chunk)
+
+		if !tag {
+			out.WriteString(shown)
+		}
+	}
+
+	return out.String()
+}
+
+// DisplayWidth returns how many terminal cells text with tview tags takes up on screen:
+// the tags take no room, escaped brackets take one cell, and wide runes take two. Text
+// that spans several lines is as wide as its widest line. Text from outside that isn't
+// meant to have tags is escaped first: DisplayWidth(EscapeTview(text))
+//
+// Example:
+//
+//	x := DisplayWidth("[red]日本[white] [b[]")
+//	> 8
+func DisplayWidth(text string) int {
+	width := 0
+
+	for _, line := range strings.Split(StripColorTags(text), "\n") {
+		width = max(width, tview.TaggedStringWidth(tview.Escape(line)))
+	}
+
+	return width
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// scanTagged splits the start of text into its first chunk: a tag, an escaped bracket or
+// a rune. tag is true for color and region tags, which aren't shown, and shown is what the
+// chunk looks like on screen otherwise
+func scanTagged(text string) (chunk string, shown string, tag bool) {
+	// "[]" matches the color pattern, but tview shows it as it is
+	if text[0] == '[' && !strings.HasPrefix(text, "[]") {
+		if chunk = taggedColorPattern.FindString(text) + taggedRegionPattern.FindString(text); chunk != "" {
+			return chunk, "", true
+		}
+
+		if match := taggedEscapePattern.FindStringSubmatch(text); match != nil {
+			return match[0], "[" + match[1] + match[2] + "]", false
+		}
+	}
+
+	_, size := utf8.DecodeRuneInString(text)
+	return text[:size], text[:size], false
+}
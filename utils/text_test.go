@@ -96,6 +96,49 @@ func Test_HighlightableHelper(t *testing.T) {
 	assert.Equal(t, "[\"0\"][\"\"]cats          [\"\"]\n", actual)
 }
 
+func Test_HighlightableBlockHelper(t *testing.T) {
+	view := tview.NewTextView()
+	view.SetRect(0, 0, 8, 4)
+
+	actual := HighlightableBlockHelper(view, "[red]cats and dogs\nmice[white]", 2, 6, "…")
+	assert.Equal(t, "[\"2\"][\"\"][red]cats …  \nmice[white]    [\"\"]\n", actual)
+
+	actual = HighlightableBlockHelper(view, "cats and dogs", 0, 0, "…")
+	assert.Equal(t, "[\"0\"][\"\"]cats and dogs[\"\"]\n", actual)
+}
+
+func Test_TruncateTagged(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxWidth int
+		ellipsis string
+		expected string
+	}{
+		{name: "fits", text: "cat", maxWidth: 3, ellipsis: "…", expected: "cat"},
+		{name: "no limit", text: "catalog", maxWidth: 0, ellipsis: "…", expected: "catalog"},
+		{name: "cut", text: "catalog", maxWidth: 4, ellipsis: "…", expected: "cat…"},
+		{name: "longer ellipsis", text: "catalog", maxWidth: 5, ellipsis: "...", expected: "ca..."},
+		{name: "no ellipsis", text: "catalog", maxWidth: 3, ellipsis: "", expected: "cat"},
+		{name: "narrower than the ellipsis", text: "catalog", maxWidth: 2, ellipsis: "...", expected: "..."},
+		{name: "color tags take no room", text: "[red]cat[white]alog", maxWidth: 4, ellipsis: "…", expected: "[red]cat[white]…"},
+		{name: "tags after the cut are kept", text: "[red]catalog[white]", maxWidth: 4, ellipsis: "…", expected: "[red]cat…[white]"},
+		{name: "tags with attributes", text: "[red:black:b]catalog[-:-:-]", maxWidth: 4, ellipsis: "…", expected: "[red:black:b]cat…[-:-:-]"},
+		{name: "regions stay balanced", text: `["1"]cat["2"]alog[""]`, maxWidth: 4, ellipsis: "…", expected: `["1"]cat["2"]…[""]`},
+		{name: "escaped brackets aren't split", text: "cat[dog[]", maxWidth: 6, ellipsis: "…", expected: "cat…"},
+		{name: "escaped brackets that fit", text: "[dog[] and cat", maxWidth: 8, ellipsis: "…", expected: "[dog[] a…"},
+		{name: "multibyte runes", text: "héllo wörld", maxWidth: 6, ellipsis: "…", expected: "héllo…"},
+		{name: "wide runes", text: "日本語のテキスト", maxWidth: 7, ellipsis: "…", expected: "日本語…"},
+		{name: "wide rune that doesn't fit", text: "ab日本", maxWidth: 4, ellipsis: "…", expected: "ab…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, TruncateTagged(tt.text, tt.maxWidth, tt.ellipsis))
+		})
+	}
+}
+
 func Test_RowPadding(t *testing.T) {
 	assert.Equal(t, "", RowPadding(0, 0))
 	assert.Equal(t, "", RowPadding(5, 2))
@@ -135,3 +178,94 @@ func Test_PrettyNumber(t *testing.T) {
 	assert.Equal(t, "0", PrettyNumber(locPrinter, 0))
 	assert.Equal(t, "0.10", PrettyNumber(locPrinter, 0.1))
 }
+
+func Test_StripColorTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+		// unfinished is set for text that ends in what could still become a tag, which a
+		// TextView holds back until more text is written
+		unfinished bool
+	}{
+		{name: "empty", text: "", expected: ""},
+		{name: "no tags", text: "disk full", expected: "disk full"},
+		{name: "color tag", text: "[red]alert[white]", expected: "alert"},
+		{name: "hex color", text: "[#ff8700]orange", expected: "orange"},
+		{name: "background", text: "[black:green]selected", expected: "selected"},
+		{name: "attributes", text: "[::b]bold[::-]", expected: "bold"},
+		{name: "full style", text: "[red:blue:bu]styled[-:-:-]", expected: "styled"},
+		{name: "reset", text: "[-]plain", expected: "plain"},
+		{name: "region", text: `["0"]row[""]`, expected: "row"},
+		{name: "named region", text: `["item-1"]row[""]`, expected: "row"},
+		{name: "region with spaces", text: `["a b"]row[""]`, expected: "row"},
+		{name: "adjacent tags", text: `["3"][""][red][::b]x`, expected: "x"},
+		{name: "nested brackets", text: "[[red]]", expected: "[]"},
+		{name: "escaped tag", text: "[red[]", expected: "[red]"},
+		{name: "doubly escaped tag", text: "[red[[]", expected: "[red[]"},
+		{name: "escaped region", text: `["0"[]`, expected: `["0"]`},
+		{name: "escaped between tags", text: "[yellow][ERROR[][white] disk", expected: "[ERROR] disk"},
+		{name: "empty brackets", text: "array[] x", expected: "array[] x"},
+		{name: "double open", text: "[[", expected: "[[", unfinished: true},
+		{name: "double close", text: "]]", expected: "]]"},
+		{name: "unclosed", text: "[red", expected: "[red", unfinished: true},
+		{name: "unclosed region", text: `["0"`, expected: `["0"`, unfinished: true},
+		{name: "lone bracket", text: "a ] b [ c", expected: "a ] b [ c"},
+		{name: "not a tag", text: "[not a tag!]", expected: "[not a tag!]"},
+		{name: "wide runes", text: "[green]日本語[white]", expected: "日本語"},
+		{name: "multiline", text: "[red]one\n[\"1\"]two[\"\"]", expected: "one\ntwo"},
+		{name: "many brackets", text: "[[[[[[[[[[]]]]]]]]]]", expected: "[[[[[[[[[[]]]]]]]]]]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := StripColorTags(tt.text)
+
+			assert.Equal(t, tt.expected, actual)
+			if !tt.unfinished {
+				assert.Equal(t, shown(tt.text), actual, "tview shows it differently")
+			}
+		})
+	}
+}
+
+func Test_StripColorTags_Escaped(t *testing.T) {
+	// Escaping and then stripping gives back the text, whatever brackets it has
+	texts := []string{
+		"[red]alert",
+		`["0"]row[""]`,
+		"[ERROR] [WARN[] [[x]] []",
+		"a[b]c[d[e]f]",
+		"\xff[red]\xfe",
+	}
+
+	for _, text := range texts {
+		assert.Equal(t, text, StripColorTags(tview.Escape(text)))
+	}
+}
+
+func Test_DisplayWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected int
+	}{
+		{name: "empty", text: "", expected: 0},
+		{name: "plain", text: "disk full", expected: 9},
+		{name: "color tags", text: "[red]alert[white]", expected: 5},
+		{name: "region tags", text: `["12"]row[""]`, expected: 3},
+		{name: "escaped tag", text: "[ERROR[] disk", expected: 12},
+		{name: "wide runes", text: "[green]日本[white]", expected: 4},
+		{name: "combining", text: "é", expected: 1},
+		{name: "emoji", text: "✓ 🚀", expected: 4},
+		{name: "multiline", text: "[red]one\nthree[white]\ntwo", expected: 5},
+		{name: "trailing newline", text: "four\n", expected: 4},
+		{name: "only tags", text: `[red]["0"][""][::b]`, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DisplayWidth(tt.text))
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package view
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/wtfutil/wtf/cfg"
+)
+
+// AsyncState is where an AsyncTextWidget is in fetching its data
+type AsyncState int
+
+const (
+	// AsyncIdle is the state before the first fetch
+	AsyncIdle AsyncState = iota
+	// AsyncLoading is the state while a fetch runs
+	AsyncLoading
+	// AsyncLoaded is the state once a fetch has succeeded
+	AsyncLoaded
+	// AsyncFailed is the state once a fetch has failed
+	AsyncFailed
+)
+
+const spinnerInterval = 100 * time.Millisecond
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// AsyncTextWidget is a TextWidget whose data is fetched in the background. It keeps track
+// of whether the data is loading, loaded or failed to load, animates a spinner while it
+// loads, and makes sure only one fetch runs at a time:
+//
+//	func (widget *Widget) Refresh() {
+//		if widget.StartLoading("Loading...") {
+//			go widget.fetch()
+//		}
+//		widget.Redraw(widget.AsyncContent)
+//	}
+//
+//	func (widget *Widget) fetch() {
+//		data, err := client.Get()
+//		if err != nil {
+//			widget.SetError(err)
+//			return
+//		}
+//		widget.SetContent(func() (string, string, bool) { return title, render(data), false })
+//	}
+type AsyncTextWidget struct {
+	TextWidget
+
+	// DisplayFunction renders the widget when its state changes and when the spinner ticks.
+	// It defaults to AsyncContent, and can be replaced by widgets that render the loading
+	// and error states themselves
+	DisplayFunction func() (string, string, bool)
+
+	mu          *sync.Mutex
+	state       AsyncState
+	message     string
+	err         error
+	content     func() (string, string, bool)
+	loadingAt   time.Time
+	stopSpinner chan struct{}
+}
+
+// NewAsyncTextWidget creates and returns an instance of AsyncTextWidget
+func NewAsyncTextWidget(tviewApp *tview.Application, redrawChan chan bool, pages *tview.Pages, commonSettings *cfg.Common) AsyncTextWidget {
+	return AsyncTextWidget{
+		TextWidget: NewTextWidget(tviewApp, redrawChan, pages, commonSettings),
+
+		mu:    &sync.Mutex{},
+		state: AsyncIdle,
+	}
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+// StartLoading moves the widget into the loading state, with a message to show next to the
+// spinner, and starts the spinner. It returns false, and changes nothing, when a fetch is
+// already running, so that callers only start a fetch when it returns true. It doesn't
+// redraw, so that it can be called while rendering
+func (widget *AsyncTextWidget) StartLoading(message string) bool {
+	widget.mu.Lock()
+	defer widget.mu.Unlock()
+
+	if widget.state == AsyncLoading {
+		return false
+	}
+
+	widget.state = AsyncLoading
+	widget.message = message
+	widget.err = nil
+	widget.loadingAt = time.Now()
+	widget.stopSpinner = make(chan struct{})
+
+	go widget.spin(widget.stopSpinner)
+
+	return true
+}
+
+// SetContent moves the widget into the loaded state, stops the spinner and redraws.
+// content renders the data that was fetched
+func (widget *AsyncTextWidget) SetContent(content func() (string, string, bool)) {
+	widget.finish(AsyncLoaded, nil, content)
+}
+
+// SetError moves the widget into the failed state, stops the spinner and redraws. The
+// content of the previous successful fetch, if any, is kept
+func (widget *AsyncTextWidget) SetError(err error) {
+	widget.finish(AsyncFailed, err, nil)
+}
+
+// EnableRefreshFooter lets the showRefreshFooter setting add the refresh footer, which
+// says when the last fetch finished
+func (widget *AsyncTextWidget) EnableRefreshFooter() {
+	widget.TextWidget.EnableRefreshFooter()
+	widget.refreshFooter.marked = true
+}
+
+// State returns where the widget is in fetching its data
+func (widget *AsyncTextWidget) State() AsyncState {
+	widget.mu.Lock()
+	defer widget.mu.Unlock()
+
+	return widget.state
+}
+
+// Loading returns true while a fetch runs
+func (widget *AsyncTextWidget) Loading() bool {
+	return widget.State() == AsyncLoading
+}
+
+// Err returns why the last fetch failed, or nil
+func (widget *AsyncTextWidget) Err() error {
+	widget.mu.Lock()
+	defer widget.mu.Unlock()
+
+	return widget.err
+}
+
+// LoadingMessage returns the message passed to StartLoading
+func (widget *AsyncTextWidget) LoadingMessage() string {
+	widget.mu.Lock()
+	defer widget.mu.Unlock()
+
+	return widget.message
+}
+
+// Spinner returns the frame of the spinner for how long the widget has been loading
+func (widget *AsyncTextWidget) Spinner() string {
+	widget.mu.Lock()
+	defer widget.mu.Unlock()
+
+	return SpinnerFrame(time.Since(widget.loadingAt))
+}
+
+// AsyncContent renders the widget in its current state: the spinner and the loading message,
+// the error, or the content
+func (widget *AsyncTextWidget) AsyncContent() (string, string, bool) {
+	title := widget.CommonSettings().Title
+
+	widget.mu.Lock()
+	state, message, err, content := widget.state, widget.message, widget.err, widget.content
+	widget.mu.Unlock()
+
+	switch {
+	case state == AsyncLoading:
+		return title, fmt.Sprintf("[yellow]%s[white] %s", widget.Spinner(), message), false
+	case state == AsyncFailed:
+		return title, fmt.Sprintf("[red]Error: %v[white]", err), true
+	case state == AsyncLoaded && content != nil:
+		return content()
+	default:
+		return title, "", false
+	}
+}
+
+// SpinnerFrame returns the frame of the loading spinner after it has been spinning for
+// elapsed
+func SpinnerFrame(elapsed time.Duration) string {
+	return spinnerFrames[int(max(elapsed, 0)/spinnerInterval)%len(spinnerFrames)]
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// display redraws the widget with its display function
+func (widget *AsyncTextWidget) display() {
+	if widget.DisplayFunction != nil {
+		widget.Redraw(widget.DisplayFunction)
+		return
+	}
+
+	widget.Redraw(widget.AsyncContent)
+}
+
+// finish ends the running fetch in the given state, and redraws
+func (widget *AsyncTextWidget) finish(state AsyncState, err error, content func() (string, string, bool)) {
+	widget.mu.Lock()
+
+	widget.state = state
+	widget.err = err
+	if content != nil {
+		widget.content = content
+	}
+
+	if widget.stopSpinner != nil {
+		close(widget.stopSpinner)
+		widget.stopSpinner = nil
+	}
+
+	widget.mu.Unlock()
+
+	widget.MarkRefreshed()
+	widget.display()
+}
+
+// spin redraws the widget on every frame of the spinner, until stop is closed
+func (widget *AsyncTextWidget) spin(stop chan struct{}) {
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			widget.display()
+		}
+	}
+}
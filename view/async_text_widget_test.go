@@ -0,0 +1,150 @@
+package view
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"github.com/wtfutil/wtf/cfg"
+)
+
+func testAsyncTextWidget() *AsyncTextWidget {
+	widget := NewAsyncTextWidget(
+		tview.NewApplication(),
+		make(chan bool, 1),
+		tview.NewPages(),
+		&cfg.Common{
+			Module: cfg.Module{
+				Name: "test widget",
+			},
+			Title: "Async",
+		},
+	)
+	return &widget
+}
+
+// countRedraws counts the redraws the widget asks for until stop is closed
+func countRedraws(widget *AsyncTextWidget, stop chan struct{}) chan int {
+	count := make(chan int)
+
+	go func() {
+		n := 0
+		for {
+			select {
+			case <-widget.RedrawChan:
+				n++
+			case <-stop:
+				count <- n
+				return
+			}
+		}
+	}()
+
+	return count
+}
+
+func Test_AsyncTextWidget_States(t *testing.T) {
+	widget := testAsyncTextWidget()
+	stop := make(chan struct{})
+	count := countRedraws(widget, stop)
+
+	assert.Equal(t, AsyncIdle, widget.State())
+	_, content, _ := widget.AsyncContent()
+	assert.Equal(t, "", content)
+
+	assert.True(t, widget.StartLoading("Fetching"))
+	assert.Equal(t, AsyncLoading, widget.State())
+	assert.True(t, widget.Loading())
+	_, content, _ = widget.AsyncContent()
+	assert.Contains(t, content, "Fetching")
+	assert.Contains(t, content, spinnerFrames[0])
+
+	widget.SetError(errors.New("boom"))
+	assert.Equal(t, AsyncFailed, widget.State())
+	assert.EqualError(t, widget.Err(), "boom")
+	_, content, _ = widget.AsyncContent()
+	assert.Equal(t, "[red]Error: boom[white]", content)
+
+	// Loading again clears the error
+	assert.True(t, widget.StartLoading("Fetching"))
+	assert.NoError(t, widget.Err())
+
+	widget.SetContent(func() (string, string, bool) { return "Async", "data", false })
+	assert.Equal(t, AsyncLoaded, widget.State())
+	assert.False(t, widget.Loading())
+	title, content, _ := widget.AsyncContent()
+	assert.Equal(t, "Async", title)
+	assert.Equal(t, "data", content)
+
+	close(stop)
+	assert.GreaterOrEqual(t, <-count, 2)
+}
+
+func Test_AsyncTextWidget_StartLoading_Overlapping(t *testing.T) {
+	widget := testAsyncTextWidget()
+	stop := make(chan struct{})
+	count := countRedraws(widget, stop)
+	defer func() { close(stop); <-count }()
+
+	assert.True(t, widget.StartLoading("first"))
+	assert.False(t, widget.StartLoading("second"))
+	assert.Equal(t, "first", widget.LoadingMessage())
+
+	widget.SetContent(func() (string, string, bool) { return "", "", false })
+	assert.True(t, widget.StartLoading("third"))
+	widget.SetError(errors.New("boom"))
+}
+
+func Test_AsyncTextWidget_SpinnerStops(t *testing.T) {
+	tests := []struct {
+		name   string
+		finish func(widget *AsyncTextWidget)
+	}{
+		{
+			name: "content",
+			finish: func(widget *AsyncTextWidget) {
+				widget.SetContent(func() (string, string, bool) { return "", "", false })
+			},
+		},
+		{
+			name:   "error",
+			finish: func(widget *AsyncTextWidget) { widget.SetError(errors.New("boom")) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			widget := testAsyncTextWidget()
+
+			widget.StartLoading("Fetching")
+
+			// The spinner redraws on every frame while loading
+			for i := 0; i < 3; i++ {
+				select {
+				case <-widget.RedrawChan:
+				case <-time.After(time.Second):
+					t.Fatal("the spinner didn't redraw")
+				}
+			}
+
+			stop := make(chan struct{})
+			count := countRedraws(widget, stop)
+			tt.finish(widget)
+
+			// Once finished, only the redraw of the new state, and a frame that was already
+			// being drawn, are left
+			time.Sleep(5 * spinnerInterval)
+			close(stop)
+			assert.LessOrEqual(t, <-count, 2)
+		})
+	}
+}
+
+func Test_SpinnerFrame(t *testing.T) {
+	assert.Equal(t, "⠋", SpinnerFrame(0))
+	assert.Equal(t, "⠙", SpinnerFrame(spinnerInterval))
+	assert.Equal(t, "⠋", SpinnerFrame(time.Duration(len(spinnerFrames))*spinnerInterval))
+	assert.Equal(t, "⠋", SpinnerFrame(-time.Second))
+}
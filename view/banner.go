@@ -0,0 +1,192 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// BannerLevel is how serious the message in a widget's banner is
+type BannerLevel int
+
+const (
+	// BannerInfo is for messages that need no action
+	BannerInfo BannerLevel = iota
+	// BannerWarning is for problems the widget can still show its data with, like a partial fetch
+	BannerWarning
+	// BannerError is for problems that keep the widget from showing its data
+	BannerError
+)
+
+// errorHistorySize is how many warnings and errors LastErrors remembers
+const errorHistorySize = 5
+
+// BannerEntry is a message that was shown in a widget's banner
+type BannerEntry struct {
+	At      time.Time
+	Level   BannerLevel
+	Message string
+}
+
+// banner is the message pinned at the top of a widget, and the warnings and errors it showed
+// before
+type banner struct {
+	mu      sync.Mutex
+	current BannerEntry
+	// history holds the last warnings and errors, oldest first
+	history []BannerEntry
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+// SetBanner pins a single line with message above the widget's content, colored for level,
+// until it's replaced or cleared. An empty message clears it. Warnings and errors are also
+// kept for LastErrors, unless they're the message already shown. It doesn't redraw, so
+// modules set it while refreshing, before rendering
+func (widget *TextWidget) SetBanner(level BannerLevel, message string) {
+	if widget.banner == nil {
+		return
+	}
+
+	message = strings.Join(strings.Fields(message), " ")
+
+	widget.banner.mu.Lock()
+	defer widget.banner.mu.Unlock()
+
+	entry := BannerEntry{At: time.Now(), Level: level, Message: message}
+
+	repeated := entry.Level == widget.banner.current.Level && entry.Message == widget.banner.current.Message
+	if level >= BannerWarning && message != "" && !repeated {
+		widget.banner.history = append(widget.banner.history, entry)
+		if len(widget.banner.history) > errorHistorySize {
+			widget.banner.history = widget.banner.history[len(widget.banner.history)-errorHistorySize:]
+		}
+	}
+
+	widget.banner.current = entry
+}
+
+// ClearBanner removes the banner from the top of the widget
+func (widget *TextWidget) ClearBanner() {
+	widget.SetBanner(BannerInfo, "")
+}
+
+// LastErrors returns the last warnings and errors the widget showed in its banner, newest
+// first
+func (widget *TextWidget) LastErrors() []BannerEntry {
+	if widget.banner == nil {
+		return []BannerEntry{}
+	}
+
+	widget.banner.mu.Lock()
+	defer widget.banner.mu.Unlock()
+
+	entries := make([]BannerEntry, 0, len(widget.banner.history))
+	for i := len(widget.banner.history) - 1; i >= 0; i-- {
+		entries = append(entries, widget.banner.history[i])
+	}
+
+	return entries
+}
+
+// ShowLastErrors displays the last warnings and errors of the widget in a modal dialog
+func (widget *TextWidget) ShowLastErrors() {
+	if widget.pages == nil {
+		return
+	}
+
+	closeFunc := func() {
+		widget.pages.RemovePage("errors")
+		widget.tviewApp.SetFocus(widget.view)
+	}
+
+	modal := NewBillboardModal(widget.lastErrorsText(), closeFunc)
+
+	widget.pages.AddPage("errors", modal, false, true)
+	widget.tviewApp.SetFocus(modal)
+
+	// Tell the app to force redraw the screen
+	widget.RedrawChan <- true
+}
+
+// RenderBanner writes a banner line for message, cut to fit in width when it's wider. A
+// width of 0 or less doesn't cut it
+//
+// Example:
+//
+//	x := RenderBanner(BannerWarning, "Could not fetch 2 issues", 0)
+//	> "[yellow]⚠ Could not fetch 2 issues[white]"
+func RenderBanner(level BannerLevel, message string, width int) string {
+	if width > 0 {
+		message = truncateToWidth(message, max(width-2, len(textTableTruncateMarker)))
+	}
+
+	return fmt.Sprintf("[%s]%s %s[white]", level.color(), level.icon(), tview.Escape(message))
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+func (level BannerLevel) color() string {
+	switch level {
+	case BannerError:
+		return "red"
+	case BannerWarning:
+		return "yellow"
+	default:
+		return "lightblue"
+	}
+}
+
+func (level BannerLevel) icon() string {
+	switch level {
+	case BannerError:
+		return "✖"
+	case BannerWarning:
+		return "⚠"
+	default:
+		return "ℹ"
+	}
+}
+
+// bannerLine returns the banner, on a line of its own, or "" when there's none
+func (widget *TextWidget) bannerLine() string {
+	if widget.banner == nil {
+		return ""
+	}
+
+	widget.banner.mu.Lock()
+	current := widget.banner.current
+	widget.banner.mu.Unlock()
+
+	if current.Message == "" {
+		return ""
+	}
+
+	_, _, width, _ := widget.View.GetInnerRect()
+	return RenderBanner(current.Level, current.Message, width) + "\n"
+}
+
+// lastErrorsText renders the last warnings and errors for the modal dialog
+func (widget *TextWidget) lastErrorsText() string {
+	str := fmt.Sprintf(" [green::b]Last errors in %s[white]\n\n", tview.Escape(widget.CommonSettings().Title))
+
+	entries := widget.LastErrors()
+	if len(entries) == 0 {
+		return str + "  No errors\n"
+	}
+
+	for _, entry := range entries {
+		str += fmt.Sprintf(
+			"  %s  [%s]%s[white] %s\n",
+			entry.At.Format("15:04:05"),
+			entry.Level.color(),
+			entry.Level.icon(),
+			tview.Escape(entry.Message),
+		)
+	}
+
+	return str
+}
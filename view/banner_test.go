@@ -0,0 +1,144 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"github.com/wtfutil/wtf/cfg"
+)
+
+func testBannerWidget(settings *cfg.Common) TextWidget {
+	return NewTextWidget(tview.NewApplication(), make(chan bool, 1), tview.NewPages(), settings)
+}
+
+func Test_RenderBanner(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    BannerLevel
+		message  string
+		width    int
+		expected string
+	}{
+		{
+			name:     "info",
+			level:    BannerInfo,
+			message:  "Showing cached issues",
+			expected: "[lightblue]ℹ Showing cached issues[white]",
+		},
+		{
+			name:     "warning",
+			level:    BannerWarning,
+			message:  "Could not fetch 2 of 20 issues",
+			expected: "[yellow]⚠ Could not fetch 2 of 20 issues[white]",
+		},
+		{
+			name:     "error",
+			level:    BannerError,
+			message:  "JIRA search failed",
+			expected: "[red]✖ JIRA search failed[white]",
+		},
+		{
+			name:     "fits",
+			level:    BannerError,
+			message:  "timeout",
+			width:    9,
+			expected: "[red]✖ timeout[white]",
+		},
+		{
+			name:     "truncated",
+			level:    BannerError,
+			message:  "connection refused by the server",
+			width:    20,
+			expected: "[red]✖ connection refu...[white]",
+		},
+		{
+			name:     "escaped",
+			level:    BannerWarning,
+			message:  "[ERROR] disk full",
+			expected: "[yellow]⚠ [ERROR[] disk full[white]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RenderBanner(tt.level, tt.message, tt.width))
+		})
+	}
+}
+
+func Test_SetBanner_Order(t *testing.T) {
+	widget := testBannerWidget(&cfg.Common{ShowRefreshFooter: true})
+	widget.EnableRefreshFooter()
+	widget.View.SetRect(0, 0, 80, 10)
+
+	widget.SetBanner(BannerWarning, "Could not fetch\n2 issues")
+	widget.Redraw(func() (string, string, bool) { return "", "first\nsecond\n", false })
+
+	lines := strings.Split(widget.View.GetText(true), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "⚠ Could not fetch 2 issues", lines[0])
+	assert.Equal(t, "first", lines[1])
+	assert.Equal(t, "second", lines[2])
+	assert.Contains(t, lines[3], "↻ ")
+	<-widget.RedrawChan
+
+	widget.ClearBanner()
+	widget.Redraw(func() (string, string, bool) { return "", "first\nsecond\n", false })
+
+	assert.True(t, strings.HasPrefix(widget.View.GetText(true), "first\n"))
+}
+
+func Test_SetBanner_Truncated(t *testing.T) {
+	widget := testBannerWidget(&cfg.Common{})
+	widget.View.SetRect(0, 0, 20, 5)
+
+	widget.SetBanner(BannerError, strings.Repeat("x", 40))
+	widget.Redraw(func() (string, string, bool) { return "", "content", false })
+
+	lines := strings.Split(widget.View.GetText(true), "\n")
+	assert.Equal(t, "✖ "+strings.Repeat("x", 15)+"...", lines[0])
+	assert.Equal(t, "content", lines[1])
+}
+
+func Test_LastErrors(t *testing.T) {
+	widget := testBannerWidget(&cfg.Common{})
+	assert.Empty(t, widget.LastErrors())
+
+	widget.SetBanner(BannerInfo, "informational")
+	assert.Empty(t, widget.LastErrors())
+
+	for i := 1; i <= 7; i++ {
+		widget.SetBanner(BannerError, fmt.Sprintf("error %d", i))
+	}
+
+	// The same message shown on every refresh is only kept once
+	widget.SetBanner(BannerError, "error 7")
+
+	messages := []string{}
+	for _, entry := range widget.LastErrors() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{"error 7", "error 6", "error 5", "error 4", "error 3"}, messages)
+
+	// Once cleared, it's new again
+	widget.ClearBanner()
+	widget.SetBanner(BannerWarning, "error 7")
+
+	entries := widget.LastErrors()
+	assert.Len(t, entries, errorHistorySize)
+	assert.Equal(t, BannerWarning, entries[0].Level)
+	assert.Equal(t, "error 7", entries[0].Message)
+	assert.Equal(t, "error 4", entries[4].Message)
+}
+
+func Test_lastErrorsText(t *testing.T) {
+	widget := testBannerWidget(&cfg.Common{Title: "Jira"})
+	assert.Equal(t, " [green::b]Last errors in Jira[white]\n\n  No errors\n", widget.lastErrorsText())
+
+	widget.SetBanner(BannerError, "[boom]")
+	text := widget.lastErrorsText()
+	assert.Contains(t, text, "[red]✖[white] [boom[]\n")
+}
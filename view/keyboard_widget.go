@@ -10,6 +10,7 @@ import (
 	"golang.org/x/text/language"
 )
 
+const errorsKeyChar = "E"
 const helpKeyChar = "/"
 const refreshKeyChar = "r"
 
@@ -84,6 +85,14 @@ func (widget *KeyboardWidget) InitializeHelpTextKeyboardControl(helpFunc func())
 	}
 }
 
+// InitializeErrorsKeyboardControl assigns the function that displays the last errors of
+// the widget to the common errors key value
+func (widget *KeyboardWidget) InitializeErrorsKeyboardControl(errorsFunc func()) {
+	if errorsFunc != nil {
+		widget.SetKeyboardChar(errorsKeyChar, errorsFunc, "Show/hide the last errors")
+	}
+}
+
 // InitializeRefreshKeyboardControl assigns the module's explicit refresh function to
 // the commom refresh key value
 func (widget *KeyboardWidget) InitializeRefreshKeyboardControl(refreshFunc func()) {
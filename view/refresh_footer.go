@@ -0,0 +1,91 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// refreshFooter is when a widget that shows the refresh footer last refreshed
+type refreshFooter struct {
+	mu          sync.Mutex
+	refreshedAt time.Time
+	// marked is set once the widget marks its refreshes itself, after which redraws
+	// no longer count as refreshes
+	marked bool
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+// EnableRefreshFooter lets the showRefreshFooter setting add a line under the content that
+// says when the widget last refreshed and when it refreshes next. Modules that render a
+// footer of their own leave it off. Every redraw counts as a refresh until the widget
+// calls MarkRefreshed
+func (widget *TextWidget) EnableRefreshFooter() {
+	widget.refreshFooter = &refreshFooter{}
+}
+
+// MarkRefreshed records that the widget refreshed its data now, for widgets that also
+// redraw for other reasons, like moving the selection
+func (widget *TextWidget) MarkRefreshed() {
+	if widget.refreshFooter == nil {
+		return
+	}
+
+	widget.refreshFooter.mu.Lock()
+	defer widget.refreshFooter.mu.Unlock()
+
+	widget.refreshFooter.refreshedAt = time.Now()
+	widget.refreshFooter.marked = true
+}
+
+// RefreshFooter writes when a widget last refreshed and when it refreshes next, right
+// aligned in width, or just the former when it doesn't refresh on its own
+//
+// Example:
+//
+//	x := RefreshFooter(time.Date(2024, 3, 5, 14, 2, 11, 0, time.Local), time.Minute, 0)
+//	> "[::d]↻ 14:02:11 · next 14:03[::-]"
+func RefreshFooter(refreshedAt time.Time, interval time.Duration, width int) string {
+	text := "↻ " + refreshedAt.Format("15:04:05")
+	if interval > 0 {
+		next := refreshedAt.Add(interval)
+
+		layout := "15:04"
+		if interval < time.Minute {
+			layout = "15:04:05"
+		}
+
+		text += " · next " + next.Format(layout)
+	}
+
+	padding := max(width-tview.TaggedStringWidth(text), 0)
+	return fmt.Sprintf("%s[::d]%s[::-]", strings.Repeat(" ", padding), text)
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// refreshFooterLine returns the refresh footer, on a line of its own, when the widget has
+// opted in and the setting is on, or ""
+func (widget *TextWidget) refreshFooterLine() string {
+	if widget.refreshFooter == nil || !widget.commonSettings.ShowRefreshFooter {
+		return ""
+	}
+
+	widget.refreshFooter.mu.Lock()
+	if !widget.refreshFooter.marked {
+		widget.refreshFooter.refreshedAt = time.Now()
+	}
+	refreshedAt := widget.refreshFooter.refreshedAt
+	widget.refreshFooter.mu.Unlock()
+
+	if refreshedAt.IsZero() {
+		return ""
+	}
+
+	_, _, width, _ := widget.View.GetInnerRect()
+	return "\n" + RefreshFooter(refreshedAt, widget.commonSettings.RefreshInterval, width)
+}
@@ -0,0 +1,127 @@
+package view
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"github.com/wtfutil/wtf/cfg"
+)
+
+func Test_RefreshFooter(t *testing.T) {
+	refreshedAt := time.Date(2024, 3, 5, 14, 2, 11, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		width    int
+		expected string
+	}{
+		{
+			name:     "minutes",
+			interval: time.Minute,
+			expected: "[::d]↻ 14:02:11 · next 14:03[::-]",
+		},
+		{
+			name:     "seconds",
+			interval: 30 * time.Second,
+			expected: "[::d]↻ 14:02:11 · next 14:02:41[::-]",
+		},
+		{
+			name:     "no refresh",
+			expected: "[::d]↻ 14:02:11[::-]",
+		},
+		{
+			name:     "right aligned",
+			interval: time.Hour,
+			width:    30,
+			expected: "       [::d]↻ 14:02:11 · next 15:02[::-]",
+		},
+		{
+			name:     "narrower than the footer",
+			interval: time.Hour,
+			width:    10,
+			expected: "[::d]↻ 14:02:11 · next 15:02[::-]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RefreshFooter(refreshedAt, tt.interval, tt.width))
+		})
+	}
+}
+
+func Test_RefreshFooter_OptIn(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		setting  bool
+		expected bool
+	}{
+		{name: "module and setting", enabled: true, setting: true, expected: true},
+		{name: "module only", enabled: true, setting: false, expected: false},
+		{name: "setting only", enabled: false, setting: true, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			widget := NewTextWidget(
+				tview.NewApplication(),
+				make(chan bool, 1),
+				tview.NewPages(),
+				&cfg.Common{RefreshInterval: time.Minute, ShowRefreshFooter: tt.setting},
+			)
+			if tt.enabled {
+				widget.EnableRefreshFooter()
+			}
+
+			widget.Redraw(func() (string, string, bool) { return "", "content\n", false })
+
+			assert.Equal(t, tt.expected, widget.View.GetText(true) != "content")
+			if tt.expected {
+				assert.Contains(t, widget.View.GetText(true), "content\n")
+				assert.Contains(t, widget.View.GetText(true), "↻ ")
+			}
+		})
+	}
+}
+
+func Test_MarkRefreshed(t *testing.T) {
+	widget := NewTextWidget(
+		tview.NewApplication(),
+		make(chan bool, 1),
+		tview.NewPages(),
+		&cfg.Common{ShowRefreshFooter: true},
+	)
+	widget.EnableRefreshFooter()
+
+	// Until the widget marks its refreshes, every redraw counts as one
+	assert.NotEqual(t, "", widget.refreshFooterLine())
+
+	widget.refreshFooter.refreshedAt = time.Date(2024, 3, 5, 14, 2, 11, 0, time.Local)
+	widget.refreshFooter.marked = true
+	assert.Contains(t, widget.refreshFooterLine(), "↻ 14:02:11")
+
+	widget.MarkRefreshed()
+	assert.NotContains(t, widget.refreshFooterLine(), "↻ 14:02:11")
+}
+
+func Test_AsyncTextWidget_RefreshFooter(t *testing.T) {
+	widget := testAsyncTextWidget()
+	widget.commonSettings.ShowRefreshFooter = true
+	widget.EnableRefreshFooter()
+
+	// Nothing has been fetched yet
+	assert.Equal(t, "", widget.refreshFooterLine())
+
+	stop := make(chan struct{})
+	count := countRedraws(widget, stop)
+	defer func() { close(stop); <-count }()
+
+	widget.StartLoading("Fetching")
+	widget.SetContent(func() (string, string, bool) { return "", "data", false })
+
+	assert.Contains(t, widget.refreshFooterLine(), "↻ ")
+}
@@ -0,0 +1,137 @@
+package view
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/wtfutil/wtf/wtf"
+)
+
+// textScroll is how far the content of a widget that scrolls is scrolled
+type textScroll struct {
+	mu sync.Mutex
+	// offset is the first line shown
+	offset int
+	// height is how many lines of content fit, as of the last draw
+	height int
+	// persist keeps the offset when the content is redrawn
+	persist bool
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+// EnableScrolling lets the arrow keys, PageUp and PageDown scroll the content of a widget
+// that is taller than it when it's focused, and shows how many lines are below the bottom
+// edge. Redraws go back to the top unless persist is set
+func (widget *TextWidget) EnableScrolling(persist bool) {
+	widget.scroll = &textScroll{persist: persist}
+
+	widget.View.SetScrollable(true)
+	widget.View.SetDrawFunc(widget.drawScroll)
+
+	widget.SetKeyboardKey(tcell.KeyUp, func() { widget.scrollBy(-1) }, "Scroll up")
+	widget.SetKeyboardKey(tcell.KeyDown, func() { widget.scrollBy(1) }, "Scroll down")
+	widget.SetKeyboardKey(tcell.KeyPgUp, func() { widget.scrollBy(-widget.scrollPage()) }, "Scroll up a page")
+	widget.SetKeyboardKey(tcell.KeyPgDn, func() { widget.scrollBy(widget.scrollPage()) }, "Scroll down a page")
+}
+
+// ScrollIndicator says how many lines of content are below the bottom edge, or returns ""
+// when there are none
+//
+// Example:
+//
+//	x := ScrollIndicator(12)
+//	> "▼ 12 more lines"
+func ScrollIndicator(hidden int) string {
+	switch {
+	case hidden <= 0:
+		return ""
+	case hidden == 1:
+		return "▼ 1 more line"
+	default:
+		return fmt.Sprintf("▼ %d more lines", hidden)
+	}
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// clampScroll keeps an offset between the top and the last offset that still fills height
+func clampScroll(offset, lines, height int) int {
+	return max(min(offset, lines-height), 0)
+}
+
+// hiddenLines returns how many lines are below the bottom edge
+func hiddenLines(offset, lines, height int) int {
+	return max(lines-offset-height, 0)
+}
+
+// scrollBy moves the content by delta lines, down when it's positive
+func (widget *TextWidget) scrollBy(delta int) {
+	if widget.scroll == nil {
+		return
+	}
+
+	widget.scroll.mu.Lock()
+	defer widget.scroll.mu.Unlock()
+
+	widget.scroll.offset = clampScroll(widget.scroll.offset+delta, widget.View.GetOriginalLineCount(), widget.scroll.height)
+	widget.View.ScrollTo(widget.scroll.offset, 0)
+}
+
+// scrollPage returns how many lines PageUp and PageDown move by: all but one of the lines
+// shown, so that one line stays in view
+func (widget *TextWidget) scrollPage() int {
+	widget.scroll.mu.Lock()
+	defer widget.scroll.mu.Unlock()
+
+	return max(widget.scroll.height-1, 1)
+}
+
+// restoreScroll scrolls redrawn content back to the top, or to where it was when the
+// offset persists
+func (widget *TextWidget) restoreScroll() {
+	if widget.scroll == nil {
+		return
+	}
+
+	widget.scroll.mu.Lock()
+	defer widget.scroll.mu.Unlock()
+
+	if !widget.scroll.persist {
+		widget.scroll.offset = 0
+	}
+	widget.View.ScrollTo(widget.scroll.offset, 0)
+}
+
+// drawScroll draws the scroll indicator on the bottom border or, without a border, on the
+// last line, which the content then leaves free. It returns where the content goes
+func (widget *TextWidget) drawScroll(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+	innerX, innerY, innerWidth, innerHeight := x, y, width, height
+	if widget.bordered {
+		innerX, innerY, innerWidth, innerHeight = x+1, y+1, width-2, height-2
+	}
+
+	widget.scroll.mu.Lock()
+	defer widget.scroll.mu.Unlock()
+
+	lines := widget.View.GetOriginalLineCount()
+	indicatorY := y + height - 1
+
+	if !widget.bordered && lines > innerHeight {
+		innerHeight--
+		indicatorY = innerY + innerHeight
+	}
+
+	widget.scroll.height = max(innerHeight, 0)
+	widget.scroll.offset = clampScroll(widget.scroll.offset, lines, widget.scroll.height)
+	widget.View.ScrollTo(widget.scroll.offset, 0)
+
+	indicator := ScrollIndicator(hiddenLines(widget.scroll.offset, lines, widget.scroll.height))
+	if indicator != "" {
+		tview.Print(screen, " "+indicator+" ", innerX, indicatorY, innerWidth, tview.AlignRight, wtf.ColorFor(widget.BorderColor()))
+	}
+
+	return innerX, innerY, innerWidth, innerHeight
+}
@@ -0,0 +1,185 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wtfutil/wtf/cfg"
+)
+
+func Test_ScrollIndicator(t *testing.T) {
+	assert.Equal(t, "", ScrollIndicator(0))
+	assert.Equal(t, "", ScrollIndicator(-3))
+	assert.Equal(t, "▼ 1 more line", ScrollIndicator(1))
+	assert.Equal(t, "▼ 12 more lines", ScrollIndicator(12))
+}
+
+func Test_scrollMath(t *testing.T) {
+	tests := []struct {
+		name    string
+		offset  int
+		lines   int
+		height  int
+		clamped int
+		hidden  int
+	}{
+		{name: "top", offset: 0, lines: 30, height: 8, clamped: 0, hidden: 22},
+		{name: "middle", offset: 10, lines: 30, height: 8, clamped: 10, hidden: 12},
+		{name: "bottom", offset: 22, lines: 30, height: 8, clamped: 22, hidden: 0},
+		{name: "past the bottom", offset: 40, lines: 30, height: 8, clamped: 22, hidden: 0},
+		{name: "above the top", offset: -2, lines: 30, height: 8, clamped: 0, hidden: 22},
+		{name: "fits", offset: 3, lines: 5, height: 8, clamped: 0, hidden: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clamped := clampScroll(tt.offset, tt.lines, tt.height)
+			assert.Equal(t, tt.clamped, clamped)
+			assert.Equal(t, tt.hidden, hiddenLines(clamped, tt.lines, tt.height))
+		})
+	}
+}
+
+// newScrollTestWidget returns a widget showing lines numbered lines, drawn 20 by 10 on screen
+func newScrollTestWidget(t *testing.T, bordered, persist bool, lines int) (*TextWidget, tcell.SimulationScreen) {
+	t.Helper()
+
+	widget := NewTextWidget(tview.NewApplication(), make(chan bool, 16), tview.NewPages(), &cfg.Common{Bordered: bordered})
+	widget.EnableScrolling(persist)
+	widget.Redraw(func() (string, string, bool) { return "", numberedLines(lines), false })
+
+	screen := tcell.NewSimulationScreen("UTF-8")
+	require.NoError(t, screen.Init())
+	t.Cleanup(screen.Fini)
+	screen.SetSize(20, 10)
+
+	widget.View.SetRect(0, 0, 20, 10)
+	widget.View.Draw(screen)
+
+	return &widget, screen
+}
+
+func numberedLines(count int) string {
+	lines := make([]string, count)
+	for idx := range lines {
+		lines[idx] = fmt.Sprintf("line %d", idx+1)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func screenRow(screen tcell.SimulationScreen, y int) string {
+	width, _ := screen.Size()
+
+	row := ""
+	for x := 0; x < width; x++ {
+		char, _, _, _ := screen.GetContent(x, y)
+		row += string(char)
+	}
+	return strings.TrimSpace(row)
+}
+
+func pressKey(widget *TextWidget, screen tcell.SimulationScreen, key tcell.Key) {
+	widget.InputCapture(tcell.NewEventKey(key, 0, tcell.ModNone))
+	widget.View.Draw(screen)
+}
+
+func Test_EnableScrolling_Keys(t *testing.T) {
+	widget, screen := newScrollTestWidget(t, true, false, 30)
+
+	// 8 lines fit inside the border
+	assert.Equal(t, "│line 1", screenRow(screen, 1)[:len("│line 1")])
+	assert.Contains(t, screenRow(screen, 9), "▼ 22 more lines")
+
+	pressKey(widget, screen, tcell.KeyDown)
+	row, _ := widget.View.GetScrollOffset()
+	assert.Equal(t, 1, row)
+	assert.Contains(t, screenRow(screen, 1), "line 2")
+	assert.Contains(t, screenRow(screen, 9), "▼ 21 more lines")
+
+	// A page keeps one line in view
+	pressKey(widget, screen, tcell.KeyPgDn)
+	row, _ = widget.View.GetScrollOffset()
+	assert.Equal(t, 8, row)
+
+	// Scrolling stops at the bottom, where there's no indicator
+	for range 5 {
+		pressKey(widget, screen, tcell.KeyPgDn)
+	}
+	row, _ = widget.View.GetScrollOffset()
+	assert.Equal(t, 22, row)
+	assert.Contains(t, screenRow(screen, 8), "line 30")
+	assert.NotContains(t, screenRow(screen, 9), "more line")
+
+	pressKey(widget, screen, tcell.KeyPgUp)
+	row, _ = widget.View.GetScrollOffset()
+	assert.Equal(t, 15, row)
+
+	for range 5 {
+		pressKey(widget, screen, tcell.KeyUp)
+	}
+	pressKey(widget, screen, tcell.KeyPgUp)
+	pressKey(widget, screen, tcell.KeyPgUp)
+	row, _ = widget.View.GetScrollOffset()
+	assert.Equal(t, 0, row)
+
+	assert.Contains(t, widget.HelpText(), "Scroll down a page")
+}
+
+func Test_EnableScrolling_Redraw(t *testing.T) {
+	tests := []struct {
+		name     string
+		persist  bool
+		expected int
+	}{
+		{name: "resets", persist: false, expected: 0},
+		{name: "persists", persist: true, expected: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			widget, screen := newScrollTestWidget(t, true, tt.persist, 30)
+
+			for range 5 {
+				pressKey(widget, screen, tcell.KeyDown)
+			}
+
+			widget.Redraw(func() (string, string, bool) { return "", numberedLines(30), false })
+			widget.View.Draw(screen)
+
+			row, _ := widget.View.GetScrollOffset()
+			assert.Equal(t, tt.expected, row)
+		})
+	}
+
+	// Persisted offsets past the end of shorter content stop at its bottom
+	widget, screen := newScrollTestWidget(t, true, true, 30)
+	pressKey(widget, screen, tcell.KeyPgDn)
+	pressKey(widget, screen, tcell.KeyPgDn)
+
+	widget.Redraw(func() (string, string, bool) { return "", numberedLines(12), false })
+	widget.View.Draw(screen)
+
+	row, _ := widget.View.GetScrollOffset()
+	assert.Equal(t, 4, row)
+	assert.Contains(t, screenRow(screen, 8), "line 12")
+}
+
+func Test_EnableScrolling_Borderless(t *testing.T) {
+	widget, screen := newScrollTestWidget(t, false, false, 30)
+
+	// The last line holds the indicator, under 9 lines of content
+	assert.Equal(t, "line 9", screenRow(screen, 8))
+	assert.Equal(t, "▼ 21 more lines", screenRow(screen, 9))
+
+	pressKey(widget, screen, tcell.KeyPgDn)
+	assert.Equal(t, "line 9", screenRow(screen, 0))
+
+	// Content that fits keeps every line
+	widget, screen = newScrollTestWidget(t, false, false, 10)
+	assert.Equal(t, "line 10", screenRow(screen, 9))
+}
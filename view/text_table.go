@@ -0,0 +1,459 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wtfutil/wtf/utils"
+)
+
+const (
+	textTableHeaderColor    = "lightblue"
+	textTableMinColumnWidth = 8
+	textTableMaxColumnWidth = 30
+	textTableSampleRows     = 15
+	textTableSeparator      = " ¦"
+	textTableTruncateMarker = "..."
+)
+
+/*
+	A TextTable lays rows of text out in aligned columns, for TextWidgets:
+
+	   Level    ¦Message                        ¦Computer
+	   ---------------------------------------------------
+	 1 Error    ¦disk is full on /var           ¦web-01
+	 2 Warning  ¦certificate expires in 3 days  ¦web-02
+
+	Columns are as wide as their widest cell, within limits, and shrink to fit the maximum
+	width. Widths are measured in terminal cells, so wide and combining characters line up.
+	Cells are escaped, so brackets in them are shown as is.
+*/
+
+// TextTable builds a table of text. The Set functions return the table so that calls can
+// be chained
+type TextTable struct {
+	headers []string
+	rows    [][]string
+
+	maxWidth       int
+	maxRows        int
+	minColumnWidth int
+	maxColumnWidth int
+	columnColors   []string
+	columnWidths   []int
+	rightAligned   []bool
+
+	headerSuffix   string
+	rowNumberWidth int
+	selectedColumn int
+	selectedRow    int
+	selectedColor  string
+	wrapColumn     int
+
+	formatCell func(row, col int, cell, text string) string
+}
+
+// NewTextTable creates and returns an empty TextTable
+func NewTextTable() *TextTable {
+	return &TextTable{
+		minColumnWidth: textTableMinColumnWidth,
+		maxColumnWidth: textTableMaxColumnWidth,
+		selectedColumn: -1,
+		selectedRow:    -1,
+		wrapColumn:     -1,
+	}
+}
+
+/* -------------------- Exported Functions -------------------- */
+
+// SetHeaders sets the names of the columns
+func (table *TextTable) SetHeaders(headers ...string) *TextTable {
+	table.headers = headers
+	return table
+}
+
+// AddRow appends a row. Cells past the last column are left out
+func (table *TextTable) AddRow(cells ...string) *TextTable {
+	table.rows = append(table.rows, cells)
+	return table
+}
+
+// SetMaxWidth sets the width the columns shrink to fit, separators included. 0, the
+// default, doesn't limit the width
+func (table *TextTable) SetMaxWidth(width int) *TextTable {
+	table.maxWidth = width
+	return table
+}
+
+// SetMaxRows sets how many rows are shown at most. The rest are counted in a line under
+// the table. 0, the default, shows every row
+func (table *TextTable) SetMaxRows(rows int) *TextTable {
+	table.maxRows = rows
+	return table
+}
+
+// SetColumnColors sets the color of the cells of each column, by position. An empty color
+// leaves the column in the default color
+func (table *TextTable) SetColumnColors(colors ...string) *TextTable {
+	table.columnColors = colors
+	return table
+}
+
+// SetRightAligned right-aligns the columns, by position, for which aligned is true, such as
+// columns of numbers. Their headers are right-aligned too. The wrapped column never is
+func (table *TextTable) SetRightAligned(aligned ...bool) *TextTable {
+	table.rightAligned = aligned
+	return table
+}
+
+// SetColumnWidthLimits sets how narrow and how wide the columns are sized, 8 and 30 by
+// default. The maximum width can still make them narrower than the minimum
+func (table *TextTable) SetColumnWidthLimits(minWidth, maxWidth int) *TextTable {
+	table.minColumnWidth = minWidth
+	table.maxColumnWidth = maxWidth
+	return table
+}
+
+// SetColumnWidths fixes the width of the columns, instead of sizing them to their cells.
+// This keeps the columns from moving when the rows change
+func (table *TextTable) SetColumnWidths(widths ...int) *TextTable {
+	table.columnWidths = widths
+	return table
+}
+
+// SetHeaderSuffix sets text written at the end of the header row, after the last column
+func (table *TextTable) SetHeaderSuffix(suffix string) *TextTable {
+	table.headerSuffix = suffix
+	return table
+}
+
+// SetRowNumberWidth shows the number of each row in front of it, in a column of this width,
+// the space after the number included. 0, the default, leaves the numbers out
+func (table *TextTable) SetRowNumberWidth(width int) *TextTable {
+	table.rowNumberWidth = width
+	return table
+}
+
+// SetSelectedColumn underlines the header of a column, or none when idx is -1
+func (table *TextTable) SetSelectedColumn(idx int) *TextTable {
+	table.selectedColumn = idx
+	return table
+}
+
+// SetSelectedRow highlights a row in the given color, or none when idx is -1
+func (table *TextTable) SetSelectedRow(idx int, color string) *TextTable {
+	table.selectedRow = idx
+	table.selectedColor = color
+	return table
+}
+
+// SetWrapColumn wraps the cells of a column onto as many lines as they need, instead of
+// truncating them, or none when idx is -1. When it is the last column it wraps at the
+// maximum width rather than at its own width
+func (table *TextTable) SetWrapColumn(idx int) *TextTable {
+	table.wrapColumn = idx
+	return table
+}
+
+// SetCellFormatter sets a function that decorates the text of each cell, once it is padded
+// and escaped, for example to color it by its value. cell is the cell as it was added, and
+// the function is called once for each line of a wrapped cell
+func (table *TextTable) SetCellFormatter(formatCell func(row, col int, cell, text string) string) *TextTable {
+	table.formatCell = formatCell
+	return table
+}
+
+// Render returns the table: the headers, a separator and the rows. A table without
+// headers renders as ""
+func (table *TextTable) Render() string {
+	if len(table.headers) == 0 {
+		return ""
+	}
+
+	return table.RenderHeaders() + table.RenderSeparator() + table.RenderRows()
+}
+
+// RenderHeaders returns the header row
+func (table *TextTable) RenderHeaders() string {
+	widths := table.ColumnWidths()
+
+	sb := strings.Builder{}
+	sb.WriteString(strings.Repeat(" ", table.rowNumberWidth))
+
+	for i, header := range table.headers {
+		if i > 0 {
+			sb.WriteString(textTableSeparator)
+		}
+
+		text := table.pad(i, truncateToWidth(header, widths[i]), widths[i])
+		if i == table.selectedColumn {
+			_, _ = fmt.Fprintf(&sb, "[%s::u]%s[white::-]", textTableHeaderColor, text)
+			continue
+		}
+		_, _ = fmt.Fprintf(&sb, "[%s]%s[white]", textTableHeaderColor, text)
+	}
+
+	sb.WriteString(table.headerSuffix)
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RenderSeparator returns the line between the headers and the rows
+func (table *TextTable) RenderSeparator() string {
+	sb := strings.Builder{}
+	sb.WriteString(strings.Repeat(" ", table.rowNumberWidth))
+
+	for i, width := range table.ColumnWidths() {
+		if i > 0 {
+			sb.WriteString("---")
+		}
+		sb.WriteString(strings.Repeat("-", width))
+	}
+
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RenderRows returns the rows, and how many more there are when they are limited
+func (table *TextTable) RenderRows() string {
+	widths := table.ColumnWidths()
+
+	rowCount := len(table.rows)
+	if table.maxRows > 0 {
+		rowCount = min(rowCount, table.maxRows)
+	}
+
+	sb := strings.Builder{}
+
+	for rowIdx, row := range table.rows[:rowCount] {
+		if rowIdx == table.selectedRow {
+			_, _ = fmt.Fprintf(&sb, "[%s]", table.selectedColor)
+		}
+
+		if table.rowNumberWidth > 0 {
+			_, _ = fmt.Fprintf(&sb, "%*d ", table.rowNumberWidth-1, rowIdx+1)
+		}
+
+		// Continuation lines of the wrapped column, written after the row itself
+		continuation := []string{}
+
+		for colIdx, cell := range row {
+			if colIdx >= len(widths) {
+				break
+			}
+
+			if colIdx > 0 {
+				sb.WriteString(textTableSeparator)
+			}
+
+			text := strings.TrimSpace(cell)
+			if colIdx == table.wrapColumn {
+				lines := wrapText(text, table.wrapWidth(widths))
+				text, continuation = lines[0], lines[1:]
+			} else {
+				text = truncateToWidth(text, widths[colIdx])
+			}
+
+			sb.WriteString(table.decorate(rowIdx, colIdx, cell, table.pad(colIdx, text, widths[colIdx])))
+		}
+
+		indent := strings.Repeat(" ", table.rowNumberWidth+columnStart(table.wrapColumn, widths))
+		for _, line := range continuation {
+			sb.WriteString("\n" + indent + table.decorate(rowIdx, table.wrapColumn, row[table.wrapColumn], utils.EscapeTview(line)))
+		}
+
+		if rowIdx == table.selectedRow {
+			sb.WriteString("[-:-:-]")
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(table.rows) > rowCount {
+		_, _ = fmt.Fprintf(&sb, "\n[gray]... (%d more rows truncated for display)[white]\n", len(table.rows)-rowCount)
+	}
+
+	return sb.String()
+}
+
+// ColumnWidths returns the width of each column. Unless they are fixed, columns are as wide
+// as the widest of their header and first rows, within the column width limits, and are
+// scaled down together when they don't fit in the maximum width
+func (table *TextTable) ColumnWidths() []int {
+	if table.columnWidths != nil {
+		widths := make([]int, len(table.headers))
+		copy(widths, table.columnWidths)
+		return widths
+	}
+
+	widths := make([]int, len(table.headers))
+	for i, header := range table.headers {
+		widths[i] = displayWidth(header)
+	}
+
+	for _, row := range table.rows[:min(len(table.rows), textTableSampleRows)] {
+		for colIdx, cell := range row {
+			if colIdx >= len(widths) {
+				break
+			}
+			widths[colIdx] = max(widths[colIdx], displayWidth(strings.TrimSpace(cell)))
+		}
+	}
+
+	totalWidth := 0
+	for i := range widths {
+		widths[i] = min(max(widths[i], table.minColumnWidth), table.maxColumnWidth)
+		totalWidth += widths[i]
+	}
+
+	separatorSpace := (len(widths) - 1) * len([]rune(textTableSeparator))
+	if table.maxWidth <= 0 || totalWidth+separatorSpace <= table.maxWidth {
+		return widths
+	}
+
+	scaleFactor := float64(table.maxWidth-separatorSpace) / float64(totalWidth)
+	for i := range widths {
+		widths[i] = max(int(float64(widths[i])*scaleFactor), table.minColumnWidth)
+	}
+
+	return widths
+}
+
+/* -------------------- Unexported Functions -------------------- */
+
+// decorate colors a cell by its column, then runs it through the cell formatter
+func (table *TextTable) decorate(row, col int, cell, text string) string {
+	if col < len(table.columnColors) && table.columnColors[col] != "" {
+		text = fmt.Sprintf("[%s]%s[white]", table.columnColors[col], text)
+	}
+
+	if table.formatCell != nil {
+		text = table.formatCell(row, col, cell, text)
+	}
+
+	return text
+}
+
+// pad pads text to the width of its column, on the left when the column is right-aligned,
+// and escapes it
+func (table *TextTable) pad(col int, text string, width int) string {
+	if col < len(table.rightAligned) && table.rightAligned[col] && col != table.wrapColumn {
+		return utils.EscapeTview(strings.Repeat(" ", max(width-displayWidth(text), 0)) + text)
+	}
+
+	return padEscaped(text, width)
+}
+
+// wrapWidth returns the width the wrapped column wraps at. When it is the last column it
+// takes up all the remaining room
+func (table *TextTable) wrapWidth(widths []int) int {
+	width := widths[table.wrapColumn]
+	if table.wrapColumn != len(widths)-1 || table.maxWidth <= 0 {
+		return width
+	}
+
+	return max(width, table.maxWidth-columnStart(table.wrapColumn, widths)-table.rowNumberWidth)
+}
+
+// columnStart returns the offset of a column from the start of the row
+func columnStart(colIdx int, widths []int) int {
+	start := 0
+	for i := 0; i < colIdx; i++ {
+		start += widths[i] + len([]rune(textTableSeparator))
+	}
+
+	return start
+}
+
+// displayWidth returns how many terminal cells text takes up when it is displayed as is
+func displayWidth(text string) int {
+	return utils.DisplayWidth(utils.EscapeTview(text))
+}
+
+// padEscaped pads text to width and then escapes it, so that bracketed text such as
+// "[ERROR]" is displayed verbatim without the escaping affecting the alignment
+func padEscaped(text string, width int) string {
+	return utils.EscapeTview(text + strings.Repeat(" ", max(width-displayWidth(text), 0)))
+}
+
+// truncateToWidth cuts text that is wider than width, ending it with a marker
+func truncateToWidth(text string, width int) string {
+	if displayWidth(text) <= width {
+		return text
+	}
+
+	room := width - len(textTableTruncateMarker)
+	sb := strings.Builder{}
+	used := 0
+
+	for _, r := range text {
+		runeWidth := displayWidth(string(r))
+		if used+runeWidth > room {
+			break
+		}
+
+		sb.WriteRune(r)
+		used += runeWidth
+	}
+
+	return sb.String() + textTableTruncateMarker
+}
+
+// wrapText breaks text into lines no wider than width, breaking on whitespace where
+// possible and splitting words that are wider than a whole line
+func wrapText(text string, width int) []string {
+	width = max(width, 1)
+
+	lines := []string{}
+	for _, paragraph := range strings.Split(text, "\n") {
+		line, lineWidth := "", 0
+
+		for _, word := range strings.Fields(paragraph) {
+			wordWidth := displayWidth(word)
+
+			if lineWidth > 0 && lineWidth+1+wordWidth > width {
+				lines = append(lines, line)
+				line, lineWidth = "", 0
+			}
+
+			if lineWidth > 0 {
+				line += " "
+				lineWidth++
+			}
+
+			for lineWidth+wordWidth > width {
+				head, tail := cutAtWidth(word, width-lineWidth)
+				if tail == "" {
+					break
+				}
+
+				lines = append(lines, line+head)
+				line, lineWidth = "", 0
+				word, wordWidth = tail, displayWidth(tail)
+			}
+
+			line += word
+			lineWidth += wordWidth
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// cutAtWidth splits text after as many runes as fit in width, and at least one, so that
+// text that is wider than a line still gets split
+func cutAtWidth(text string, width int) (string, string) {
+	used := 0
+	for i, r := range text {
+		runeWidth := displayWidth(string(r))
+		if i > 0 && used+runeWidth > width {
+			return text[:i], text[i:]
+		}
+		used += runeWidth
+	}
+
+	return text, ""
+}
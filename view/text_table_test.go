@@ -0,0 +1,203 @@
+package view
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TextTable_Empty(t *testing.T) {
+	assert.Equal(t, "", NewTextTable().Render())
+	assert.Equal(t, []int{}, NewTextTable().SetMaxWidth(10).ColumnWidths())
+
+	table := NewTextTable().SetHeaders("Name", "Value")
+	assert.Equal(t, "[lightblue]Name    [white] ¦[lightblue]Value   [white]\n"+
+		"-------------------\n", table.Render())
+}
+
+func Test_TextTable_OneColumn(t *testing.T) {
+	table := NewTextTable().
+		SetHeaders("Host").
+		AddRow("web-01").
+		AddRow("a-host-name-that-is-longer-than-thirty-cells")
+
+	assert.Equal(t, []int{30}, table.ColumnWidths())
+	assert.Equal(t, "[lightblue]Host                          [white]\n"+
+		"------------------------------\n"+
+		"web-01                        \n"+
+		"a-host-name-that-is-longer-...\n", table.Render())
+}
+
+func Test_TextTable_ColumnWidths(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  []string
+		rows     [][]string
+		maxWidth int
+		expected []int
+	}{
+		{
+			name:     "sized to cells",
+			headers:  []string{"Col1", "Col2"},
+			rows:     [][]string{{"ShortData", "VeryLongDataValue"}, {"X", "Y"}},
+			expected: []int{9, 17},
+		},
+		{
+			name:     "within limits",
+			headers:  []string{"A", "VeryVeryVeryLongColumnNameThatExceedsMaxWidth"},
+			expected: []int{8, 30},
+		},
+		{
+			name:     "fits the max width",
+			headers:  []string{"Col1", "Col2"},
+			rows:     [][]string{{"ShortData", "VeryLongDataValue"}},
+			maxWidth: 28,
+			expected: []int{9, 17},
+		},
+		{
+			name:     "scaled to the max width",
+			headers:  []string{"LongHeader1-abcdefghij", "LongHeader2-abcdefghij", "LongHeader3"},
+			maxWidth: 40,
+			expected: []int{14, 14, 8},
+		},
+		{
+			name:     "no narrower than the minimum",
+			headers:  []string{"LongHeader1", "LongHeader2", "LongHeader3"},
+			maxWidth: 20,
+			expected: []int{8, 8, 8},
+		},
+		{
+			name:     "wide characters",
+			headers:  []string{"Name"},
+			rows:     [][]string{{"東京都のサーバー"}},
+			expected: []int{16},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := NewTextTable().SetHeaders(tt.headers...).SetMaxWidth(tt.maxWidth)
+			for _, row := range tt.rows {
+				table.AddRow(row...)
+			}
+
+			assert.Equal(t, tt.expected, table.ColumnWidths())
+		})
+	}
+}
+
+func Test_TextTable_WideCharacters(t *testing.T) {
+	table := NewTextTable().
+		SetHeaders("City", "Code").
+		SetColumnWidths(8, 8).
+		AddRow("東京", "TYO").
+		AddRow("大阪府大阪市", "OSA")
+
+	assert.Equal(t, "東京     ¦TYO     \n"+
+		"大阪...  ¦OSA     \n", table.RenderRows())
+}
+
+func Test_TextTable_MaxRows(t *testing.T) {
+	table := NewTextTable().SetHeaders("N").SetMaxRows(2)
+	for _, n := range []string{"1", "2", "3", "4"} {
+		table.AddRow(n)
+	}
+
+	assert.Equal(t, "1       \n2       \n\n[gray]... (2 more rows truncated for display)[white]\n", table.RenderRows())
+}
+
+func Test_TextTable_ColumnColors(t *testing.T) {
+	table := NewTextTable().
+		SetHeaders("Level", "Message").
+		SetColumnColors("red").
+		SetCellFormatter(func(row, col int, cell, text string) string {
+			if col == 1 && cell == "[boom]" {
+				return "[::b]" + text + "[::-]"
+			}
+			return text
+		}).
+		AddRow("Error", "[boom]")
+
+	assert.Equal(t, "[red]Error   [white] ¦[::b][boom[]  [::-]\n", table.RenderRows())
+}
+
+func Test_TextTable_Selection(t *testing.T) {
+	table := NewTextTable().
+		SetHeaders("A", "B").
+		SetHeaderSuffix(" +1").
+		SetRowNumberWidth(2).
+		SetSelectedColumn(1).
+		SetSelectedRow(0, "black:white").
+		AddRow("x", "y")
+
+	assert.Equal(t, "  [lightblue]A       [white] ¦[lightblue::u]B       [white::-] +1\n", table.RenderHeaders())
+	assert.Equal(t, "[black:white]1 x        ¦y       [-:-:-]\n", table.RenderRows())
+}
+
+func Test_TextTable_RightAligned(t *testing.T) {
+	table := NewTextTable().
+		SetHeaders("Computer", "Count", "Latency").
+		SetColumnWidths(8, 8, 6).
+		SetRightAligned(false, true, true).
+		AddRow("web-01", "1,024", "3").
+		AddRow("[web-02]", "-7", "12345678").
+		AddRow("web-03", "", "0.5")
+
+	assert.Equal(t, "[lightblue]Computer[white] ¦[lightblue]   Count[white] ¦[lightblue]Lat...[white]\n", table.RenderHeaders())
+
+	lines := strings.Split(strings.TrimRight(table.RenderRows(), "\n"), "\n")
+	assert.Equal(t, []string{
+		"web-01   ¦   1,024 ¦     3",
+		"[web-02[] ¦      -7 ¦123...",
+		"web-03   ¦         ¦   0.5",
+	}, lines)
+
+	// The wrapped column stays left-aligned
+	table = NewTextTable().
+		SetHeaders("N").
+		SetColumnWidths(8).
+		SetRightAligned(true).
+		SetWrapColumn(0).
+		AddRow("42")
+	assert.Equal(t, "42      \n", table.RenderRows())
+}
+
+func Test_TextTable_WrapColumn(t *testing.T) {
+	table := NewTextTable().
+		SetHeaders("Level", "Message").
+		SetMaxWidth(24).
+		SetColumnWidths(8, 8).
+		SetWrapColumn(1).
+		AddRow("Error", "disk [red] is full on /var")
+
+	lines := strings.Split(strings.TrimRight(table.RenderRows(), "\n"), "\n")
+	assert.Equal(t, []string{
+		"Error    ¦disk [red[] is",
+		"          full on /var",
+	}, lines)
+}
+
+func Test_wrapText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		width    int
+		expected []string
+	}{
+		{name: "fits", text: "short", width: 10, expected: []string{"short"}},
+		{name: "breaks on spaces", text: "the quick brown fox", width: 10, expected: []string{"the quick", "brown fox"}},
+		{name: "splits long words", text: "abcdefghijkl", width: 5, expected: []string{"abcde", "fghij", "kl"}},
+		{name: "keeps newlines", text: "one\ntwo", width: 10, expected: []string{"one", "two"}},
+		{name: "multibyte runes", text: "ééééé ééé", width: 5, expected: []string{"ééééé", "ééé"}},
+		{name: "wide characters", text: "東京都大阪府", width: 5, expected: []string{"東京", "都大", "阪府"}},
+		{name: "wider than the line", text: "東京", width: 1, expected: []string{"東", "京"}},
+		{name: "empty", text: "", width: 5, expected: []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, wrapText(tt.text, tt.width))
+		})
+	}
+}
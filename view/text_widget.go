@@ -14,6 +14,10 @@ type TextWidget struct {
 	*KeyboardWidget
 
 	View *tview.TextView
+
+	banner        *banner
+	refreshFooter *refreshFooter
+	scroll        *textScroll
 }
 
 // NewTextWidget creates and returns an instance of TextWidget
@@ -21,6 +25,8 @@ func NewTextWidget(tviewApp *tview.Application, redrawChan chan bool, pages *tvi
 	widget := TextWidget{
 		Base:           NewBase(tviewApp, redrawChan, pages, commonSettings),
 		KeyboardWidget: NewKeyboardWidget(commonSettings),
+
+		banner: &banner{},
 	}
 
 	widget.View = widget.createView(widget.bordered)
@@ -46,7 +52,8 @@ func (widget *TextWidget) Redraw(data func() (string, string, bool)) {
 	widget.View.Clear()
 	widget.View.SetWrap(wrap)
 	widget.View.SetTitle(widget.ContextualTitle(title))
-	widget.View.SetText(strings.TrimRight(content, "\n"))
+	widget.View.SetText(widget.bannerLine() + strings.TrimRight(content, "\n") + widget.refreshFooterLine())
+	widget.restoreScroll()
 
 	widget.RedrawChan <- true
 }